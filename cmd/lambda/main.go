@@ -0,0 +1,48 @@
+// Command lambda serves the API from AWS Lambda behind API Gateway or a
+// function URL. Build it as the "bootstrap" binary of a provided.al2023
+// function. Migrations and background workers are left to the standalone
+// server, since a function only runs while it handles requests.
+package main
+
+import (
+	"log"
+	"postman-api/internal/database"
+	"postman-api/internal/lambda"
+	"postman-api/pkg/postmanapi"
+	"time"
+)
+
+// A function instance handles one request at a time, so a small pool kept
+// across invocations is enough and bounds connections as instances scale out
+const (
+	maxOpenConns    = 2
+	connMaxIdleTime = 5 * time.Minute
+)
+
+func main() {
+	cfg, err := postmanapi.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect once per cold start; warm invocations reuse the pool
+	db, err := database.Open(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
+	db.SetConnMaxIdleTime(connMaxIdleTime)
+
+	app, err := postmanapi.NewApp(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer app.Close()
+
+	if err := lambda.Start(app.Handler()); err != nil {
+		log.Fatalf("Lambda runtime stopped: %v", err)
+	}
+}
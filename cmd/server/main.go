@@ -2,58 +2,98 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"postman-api/internal/api"
-	"postman-api/internal/config"
 	"postman-api/internal/database"
-	"postman-api/internal/interfaces"
-	"postman-api/internal/repository"
-	"postman-api/internal/service"
+	"postman-api/internal/version"
+	"postman-api/pkg/postmanapi"
 	"syscall"
 	"time"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// abortGracePeriod is how long interrupted requests and jobs get to record
+// their state once the shutdown timeout has expired
+const abortGracePeriod = 5 * time.Second
+
 func main() {
-	cfg, err := config.Load()
+	cfg, err := postmanapi.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	flag.StringVar(&cfg.Server.SeedDir, "seed", cfg.Server.SeedDir, "load example collections, specs and environments from this directory at startup")
+	flag.Parse()
+
 	// Initialize database connection
-	db, err := database.NewConnection(&cfg.Database)
+	db, err := database.Open(&cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
-	// Initialize repositories
-	var collectionRepo interfaces.CollectionRepository = repository.NewCollectionRepository(db.DB)
-	var requestRepo interfaces.RequestRepository = repository.NewRequestRepository(db.DB)
-	var openAPIRepo interfaces.OpenAPIRepository = repository.NewOpenAPIRepository(db.DB)
+	app, err := postmanapi.NewApp(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer app.Close()
+
+	if err := app.Migrate(context.Background()); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	if cfg.Server.SeedDir != "" {
+		report, err := app.Seed(context.Background(), cfg.Server.SeedDir)
+		if err != nil {
+			log.Fatalf("Failed to seed database: %v", err)
+		}
+		log.Printf("Seeded fixtures from %s: %d loaded, %d already present, %d failed",
+			cfg.Server.SeedDir, report.Loaded, report.Skipped, report.Failed)
+	}
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+		app.RunWorkers(workerCtx)
+	}()
+
+	listener, err := listen(&cfg.Server)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
 
-	// Initialize services
-	var collectionService interfaces.CollectionService = service.NewCollectionService(collectionRepo, requestRepo)
-	var requestService interfaces.RequestService = service.NewRequestService(requestRepo, collectionRepo)
-	var openAPIService interfaces.OpenAPIService = service.NewOpenAPIService(openAPIRepo)
+	// Requests still running when the shutdown timeout expires, such as
+	// collection runs, are cancelled through their base context
+	requestCtx, abortRequests := context.WithCancel(context.Background())
+	defer abortRequests()
 
-	// Initialize router
-	router := api.NewRouter(collectionService, requestService, openAPIService)
 	server := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
-		Handler:      router.Setup(),
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Server.IdleTimeout,
+		Handler:           app.Handler(),
+		BaseContext:       func(net.Listener) context.Context { return requestCtx },
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		HTTP2:             &http.HTTP2Config{MaxConcurrentStreams: cfg.Server.H2MaxConcurrentStreams},
+	}
+
+	if cfg.Server.H2C {
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
 	}
 
 	go func() {
-		log.Printf("Server starting on port %s", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Server %s (commit %s) listening on %s %s", version.Version, version.Commit, listener.Addr().Network(), listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
@@ -62,13 +102,41 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Stop taking new requests and jobs, and give the ones in flight until
+	// the shutdown timeout to finish
 	log.Println("Shutting down server...")
+	stopWorkers()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	drained := true
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		// Cancelled requests save their state, collection runs as aborted,
+		// before the remaining connections are closed
+		log.Printf("Requests still running after %s, cancelling them", cfg.Server.ShutdownTimeout)
+		drained = false
+		abortRequests()
+		time.Sleep(abortGracePeriod)
+		server.Close()
+	}
+
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		// Interrupted jobs go back to the queue for the next instance
+		log.Printf("Jobs still running after %s, returning them to the queue", cfg.Server.ShutdownTimeout)
+		drained = false
+		app.AbortJobs()
+		select {
+		case <-workersDone:
+		case <-time.After(abortGracePeriod):
+		}
+	}
+
+	if !drained {
+		log.Println("Server exited after interrupting in-flight work")
+		return
 	}
 
 	log.Println("Server exited properly")
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler handles HTTP requests for request body attachments
+type AttachmentHandler struct {
+	attachmentService interfaces.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService interfaces.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// Upload stores the uploaded file and returns the reference to put in a form-data or file body
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		SendBadRequest(c, "Invalid file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), header.Filename, header.Header.Get("Content-Type"), file)
+	if err != nil {
+		SendServiceError(c, err, "Failed to store attachment")
+		return
+	}
+
+	SendCreated(c, attachment)
+}
+
+// Get retrieves attachment metadata by ID
+func (h *AttachmentHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get attachment")
+		return
+	}
+
+	SendSuccess(c, attachment)
+}
+
+// Download streams the content of an attachment
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get attachment")
+		return
+	}
+	defer content.Close()
+
+	extraHeaders := map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", attachment.Filename),
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, extraHeaders)
+}
+
+// Delete removes an attachment
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), id); err != nil {
+		SendServiceError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Attachment deleted successfully"})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler handles HTTP requests for API ownership
+type CatalogHandler struct {
+	catalogService interfaces.CatalogService
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogService interfaces.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// endpointOwnership is the body of an inventory entry ownership update
+type endpointOwnership struct {
+	Key string `json:"key" binding:"required"`
+	models.Ownership
+}
+
+// GetOwnershipFilter extracts the team, tier and lifecycle filters from the request
+func GetOwnershipFilter(c *gin.Context) models.OwnershipFilter {
+	return models.OwnershipFilter{
+		Team:      c.Query("team"),
+		Tier:      c.Query("tier"),
+		Lifecycle: c.Query("lifecycle"),
+	}
+}
+
+// SetCollectionOwnership records the team, tier, lifecycle and contact of a collection
+func (h *CatalogHandler) SetCollectionOwnership(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var ownership models.Ownership
+	if err := c.ShouldBindJSON(&ownership); err != nil {
+		SendBadRequest(c, "Invalid ownership body: "+err.Error())
+		return
+	}
+
+	if err := h.catalogService.SetCollectionOwnership(c.Request.Context(), id, ownership); err != nil {
+		SendServiceError(c, err, "Failed to update collection ownership")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Collection ownership updated successfully"})
+}
+
+// SetSpecOwnership records the team, tier, lifecycle and contact of an OpenAPI spec
+func (h *CatalogHandler) SetSpecOwnership(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var ownership models.Ownership
+	if err := c.ShouldBindJSON(&ownership); err != nil {
+		SendBadRequest(c, "Invalid ownership body: "+err.Error())
+		return
+	}
+
+	if err := h.catalogService.SetSpecOwnership(c.Request.Context(), id, ownership); err != nil {
+		SendServiceError(c, err, "Failed to update OpenAPI specification ownership")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "OpenAPI specification ownership updated successfully"})
+}
+
+// SetEndpointOwnership records the ownership of an inventory entry, identified by its key
+func (h *CatalogHandler) SetEndpointOwnership(c *gin.Context) {
+	var body endpointOwnership
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid ownership body: "+err.Error())
+		return
+	}
+
+	if err := h.catalogService.SetEndpointOwnership(c.Request.Context(), body.Key, body.Ownership); err != nil {
+		SendServiceError(c, err, "Failed to update endpoint ownership")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Endpoint ownership updated successfully"})
+}
+
+// List returns owned collections, specs and endpoints, filtered by type, team, tier and lifecycle
+func (h *CatalogHandler) List(c *gin.Context) {
+	filter := models.CatalogFilter{
+		Type:            c.Query("type"),
+		OwnershipFilter: GetOwnershipFilter(c),
+	}
+
+	switch filter.Type {
+	case "", models.CatalogTypeCollection, models.CatalogTypeSpec, models.CatalogTypeEndpoint:
+	default:
+		SendBadRequest(c, "Invalid type, expected collection, spec or endpoint")
+		return
+	}
+
+	entries, err := h.catalogService.ListCatalog(c.Request.Context(), filter)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list catalog")
+		return
+	}
+
+	SendSuccess(c, entries)
+}
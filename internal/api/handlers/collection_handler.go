@@ -2,8 +2,6 @@ package handlers
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
 	"strconv"
@@ -15,13 +13,25 @@ import (
 type CollectionHandler struct {
 	collectionService interfaces.CollectionService
 	openAPIService    interfaces.OpenAPIService
+	signingService    interfaces.SigningService
+	bulkImportService interfaces.BulkImportService
+	converters        interfaces.ConverterRegistry
 }
 
 // NewCollectionHandler creates a new collection handler
-func NewCollectionHandler(collectionService interfaces.CollectionService, openAPIService interfaces.OpenAPIService) *CollectionHandler {
+func NewCollectionHandler(
+	collectionService interfaces.CollectionService,
+	openAPIService interfaces.OpenAPIService,
+	signingService interfaces.SigningService,
+	bulkImportService interfaces.BulkImportService,
+	converters interfaces.ConverterRegistry,
+) *CollectionHandler {
 	return &CollectionHandler{
 		collectionService: collectionService,
 		openAPIService:    openAPIService,
+		signingService:    signingService,
+		bulkImportService: bulkImportService,
+		converters:        converters,
 	}
 }
 
@@ -34,11 +44,11 @@ func (h *CollectionHandler) Create(c *gin.Context) {
 	}
 
 	if err := h.collectionService.CreateCollection(c.Request.Context(), &collection); err != nil {
-		SendInternalError(c, "Failed to create collection: "+err.Error())
+		SendServiceError(c, err, "Failed to create collection")
 		return
 	}
 
-	SendCreated(c, collection)
+	SendCreated(c, withCollectionLinks(c, &collection))
 }
 
 // Get retrieves a collection by ID
@@ -51,11 +61,11 @@ func (h *CollectionHandler) Get(c *gin.Context) {
 
 	collection, err := h.collectionService.GetCollection(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "Collection not found")
+		SendServiceError(c, err, "Failed to get collection")
 		return
 	}
 
-	SendSuccess(c, collection)
+	SendSuccess(c, withCollectionLinks(c, collection))
 }
 
 // GetWithRequests retrieves a collection with all its requests
@@ -68,23 +78,51 @@ func (h *CollectionHandler) GetWithRequests(c *gin.Context) {
 
 	collection, err := h.collectionService.GetCollectionWithRequests(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "Collection not found")
+		SendServiceError(c, err, "Failed to get collection")
 		return
 	}
 
-	SendSuccess(c, collection)
+	SendSuccess(c, withCollectionLinks(c, collection))
 }
 
-// List returns all collections with pagination
+// List returns collections with pagination; archived collections are only
+// included when include_archived=true. With ids=1,5,9 it returns the
+// collections with those IDs in that order, archived or not.
 func (h *CollectionHandler) List(c *gin.Context) {
+	if raw, ok := c.GetQuery("ids"); ok {
+		ids, err := parseIDList(raw)
+		if err != nil {
+			SendBadRequest(c, "Invalid ids format")
+			return
+		}
+
+		collections, err := h.collectionService.GetCollections(c.Request.Context(), ids)
+		if err != nil {
+			SendServiceError(c, err, "Failed to get collections")
+			return
+		}
+
+		for _, collection := range collections {
+			withCollectionLinks(c, collection)
+		}
+
+		SendSuccess(c, collections)
+		return
+	}
+
 	page, pageSize := GetPaginationParams(c)
+	includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
 
-	collections, total, err := h.collectionService.ListCollections(c.Request.Context(), page, pageSize)
+	collections, total, err := h.collectionService.ListCollections(c.Request.Context(), page, pageSize, GetCountMode(c), includeArchived)
 	if err != nil {
-		SendInternalError(c, "Failed to list collections: "+err.Error())
+		SendServiceError(c, err, "Failed to list collections")
 		return
 	}
 
+	for _, collection := range collections {
+		withCollectionLinks(c, collection)
+	}
+
 	SendPaginated(c, collections, page, pageSize, total)
 }
 
@@ -105,11 +143,11 @@ func (h *CollectionHandler) Update(c *gin.Context) {
 	collection.ID = id
 
 	if err := h.collectionService.UpdateCollection(c.Request.Context(), &collection); err != nil {
-		SendInternalError(c, "Failed to update collection: "+err.Error())
+		SendServiceError(c, err, "Failed to update collection")
 		return
 	}
 
-	SendSuccess(c, collection)
+	SendSuccess(c, withCollectionLinks(c, &collection))
 }
 
 // Delete removes a collection and all its requests
@@ -121,38 +159,380 @@ func (h *CollectionHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.collectionService.DeleteCollection(c.Request.Context(), id); err != nil {
-		SendInternalError(c, "Failed to delete collection: "+err.Error())
+		SendServiceError(c, err, "Failed to delete collection")
 		return
 	}
 
 	SendSuccess(c, map[string]string{"message": "Collection deleted successfully"})
 }
 
-// Import imports a Postman collection from JSON
+// Archive makes a collection read-only and hides it from default listings
+func (h *CollectionHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// Unarchive makes an archived collection editable and listed again
+func (h *CollectionHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *CollectionHandler) setArchived(c *gin.Context, archived bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	collection, err := h.collectionService.SetCollectionArchived(c.Request.Context(), id, archived)
+	if err != nil {
+		SendServiceError(c, err, "Failed to archive collection")
+		return
+	}
+
+	SendSuccess(c, withCollectionLinks(c, collection))
+}
+
+// BulkArchive archives every collection listed in the body
+func (h *CollectionHandler) BulkArchive(c *gin.Context) {
+	h.bulkSetArchived(c, true)
+}
+
+// BulkUnarchive unarchives every collection listed in the body
+func (h *CollectionHandler) BulkUnarchive(c *gin.Context) {
+	h.bulkSetArchived(c, false)
+}
+
+func (h *CollectionHandler) bulkSetArchived(c *gin.Context, archived bool) {
+	var body struct {
+		IDs []int64 `json:"ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid request body, ids are required")
+		return
+	}
+
+	changed, err := h.collectionService.ArchiveCollections(c.Request.Context(), body.IDs, archived)
+	if err != nil {
+		SendServiceError(c, err, "Failed to archive collections")
+		return
+	}
+
+	SendSuccess(c, map[string]int{"collections_changed": changed})
+}
+
+// ListItems returns the requests of a collection as addressable Postman items
+func (h *CollectionHandler) ListItems(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	items, err := h.collectionService.ListCollectionItems(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get collection")
+		return
+	}
+
+	SendSuccess(c, items)
+}
+
+// AddItem adds a Postman request or folder item to a collection
+func (h *CollectionHandler) AddItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var entry models.CollectionItem
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ids, err := h.collectionService.AddCollectionItem(c.Request.Context(), id, &entry)
+	if err != nil {
+		SendServiceError(c, err, "Failed to add item")
+		return
+	}
+
+	SendCreated(c, map[string][]int64{"request_ids": ids})
+}
+
+// UpdateItem replaces a single item of a collection
+func (h *CollectionHandler) UpdateItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid item ID format")
+		return
+	}
+
+	var entry models.CollectionItem
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.collectionService.UpdateCollectionItem(c.Request.Context(), id, itemID, &entry); err != nil {
+		SendServiceError(c, err, "Failed to update item")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Item updated successfully"})
+}
+
+// RemoveItem removes a single item from a collection
+func (h *CollectionHandler) RemoveItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid item ID format")
+		return
+	}
+
+	if err := h.collectionService.RemoveCollectionItem(c.Request.Context(), id, itemID); err != nil {
+		SendServiceError(c, err, "Failed to remove item")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Item removed successfully"})
+}
+
+// RenameFolder renames a folder and every subfolder path beneath it
+func (h *CollectionHandler) RenameFolder(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var body struct {
+		From string `json:"from" binding:"required"`
+		To   string `json:"to" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid request body, from and to are required")
+		return
+	}
+
+	moved, err := h.collectionService.RenameFolder(c.Request.Context(), id, body.From, body.To)
+	if err != nil {
+		SendServiceError(c, err, "Failed to rename folder")
+		return
+	}
+
+	SendSuccess(c, map[string]int{"requests_moved": moved})
+}
+
+// ListRevisions returns the revisions of a collection with pagination, newest first
+func (h *CollectionHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	page, pageSize := GetPaginationParams(c)
+
+	revisions, total, err := h.collectionService.ListRevisions(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list revisions")
+		return
+	}
+
+	for _, revision := range revisions {
+		withRevisionLinks(c, revision)
+	}
+
+	SendPaginated(c, revisions, page, pageSize, models.Total{Count: total})
+}
+
+// GetRevision retrieves a collection revision with its snapshot
+func (h *CollectionHandler) GetRevision(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid revision ID format")
+		return
+	}
+
+	revision, err := h.collectionService.GetRevision(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get revision")
+		return
+	}
+
+	SendSuccess(c, withRevisionLinks(c, revision))
+}
+
+// Rollback restores a collection to one of its revisions
+func (h *CollectionHandler) Rollback(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	rev, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid revision ID format")
+		return
+	}
+
+	collection, err := h.collectionService.RollbackCollection(c.Request.Context(), id, rev)
+	if err != nil {
+		SendServiceError(c, err, "Failed to roll back collection")
+		return
+	}
+
+	SendSuccess(c, withCollectionLinks(c, collection))
+}
+
+// Import imports a collection in the given format, or the one detected from
+// the upload, optionally stripping credentials when strip_secrets=true;
+// progress is streamed as server-sent events when the client accepts
+// text/event-stream. Several files, or a zip archive of collections, are
+// imported one by one and answered with the outcome of each. Bundles of
+// archived collections go back into the archive unless unarchive=true.
 func (h *CollectionHandler) Import(c *gin.Context) {
-	file, _, err := c.Request.FormFile("file")
+	files, err := readUploads(c, "file")
 	if err != nil {
 		SendBadRequest(c, "Invalid file: "+err.Error())
 		return
 	}
-	defer file.Close()
+	data := files[0].Data
+
+	stripSecrets, _ := strconv.ParseBool(c.Query("strip_secrets"))
+	unarchive, _ := strconv.ParseBool(c.Query("unarchive"))
+	opts := models.ImportOptions{
+		StripSecrets: stripSecrets,
+		Provenance:   uploadProvenance(c, files[0].Filename),
+		Unarchive:    unarchive,
+	}
 
-	data, err := io.ReadAll(file)
+	format := c.Query("format")
+	if len(files) > 1 || (format == "" && h.bulkImportService.IsArchive(data)) {
+		h.importMany(c, files, opts)
+		return
+	}
+
+	var converter interfaces.Converter
+	if format == "" {
+		converter, err = h.converters.Detect(data)
+	} else {
+		converter, err = h.converters.Converter(format)
+	}
+	if err != nil {
+		SendServiceError(c, err, "Failed to import collection")
+		return
+	}
+	if !converter.Format().Import {
+		SendBadRequest(c, fmt.Sprintf("Collections cannot be imported from format %q", converter.Format().Name))
+		return
+	}
+
+	run := func(opts models.ImportOptions) (*models.ImportResult, error) {
+		return converter.Import(c.Request.Context(), data, opts)
+	}
+
+	if WantsEventStream(c) {
+		StreamImport(c, opts, "Failed to import collection", run)
+		return
+	}
+
+	result, err := run(opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to import collection")
+		return
+	}
+
+	SendImported(c, result)
+}
+
+// importMany imports several collections and reports the outcome of each;
+// the files that failed do not stop the others
+func (h *CollectionHandler) importMany(c *gin.Context, files []models.ImportFile, opts models.ImportOptions) {
+	report, err := h.bulkImportService.ImportCollections(c.Request.Context(), files, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to import collections")
+		return
+	}
+
+	for _, result := range report.Results {
+		if result.Err != nil {
+			_, result.Code, result.Error, _ = describeError(c, result.Err, "Failed to import collection")
+		}
+	}
+
+	SendSuccess(c, report)
+}
+
+// ImportWorkspace imports a Postman workspace dump, a data export archive or
+// its older single JSON file, and reports the collection, environment and
+// global created from each of its entries
+func (h *CollectionHandler) ImportWorkspace(c *gin.Context) {
+	files, err := readUploads(c, "file")
+	if err != nil {
+		SendBadRequest(c, "Invalid file: "+err.Error())
+		return
+	}
+
+	stripSecrets, _ := strconv.ParseBool(c.Query("strip_secrets"))
+	opts := models.ImportOptions{
+		StripSecrets: stripSecrets,
+		Provenance:   uploadProvenance(c, files[0].Filename),
+	}
+
+	report, err := h.bulkImportService.ImportWorkspace(c.Request.Context(), files[0], opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to import workspace")
+		return
+	}
+
+	for _, results := range [][]*models.BulkImportResult{report.Collections, report.Environments, report.Globals} {
+		for _, result := range results {
+			if result.Err != nil {
+				_, result.Code, result.Error, _ = describeError(c, result.Err, "Failed to import workspace entry")
+			}
+		}
+	}
+
+	SendSuccess(c, report)
+}
+
+// Validate runs the import validation pipeline on an uploaded document
+// without writing anything to the database
+func (h *CollectionHandler) Validate(c *gin.Context) {
+	data, err := ReadUploadedDocument(c)
 	if err != nil {
-		SendInternalError(c, "Failed to read file: "+err.Error())
+		SendBadRequest(c, "Invalid file: "+err.Error())
 		return
 	}
 
-	collectionID, err := h.collectionService.ImportPostmanCollection(c.Request.Context(), data)
+	result, err := h.collectionService.ValidatePostmanCollection(c.Request.Context(), data)
 	if err != nil {
-		SendBadRequest(c, "Failed to import collection: "+err.Error())
+		SendServiceError(c, err, "Failed to validate collection")
 		return
 	}
 
-	SendCreated(c, map[string]int64{"id": collectionID})
+	SendValidationResult(c, result)
 }
 
-// Export exports a collection to Postman format
+// Export exports a collection in the given format, Postman JSON by default;
+// format=bundle returns a zip that also carries the collection's attachments
 func (h *CollectionHandler) Export(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -160,19 +540,34 @@ func (h *CollectionHandler) Export(c *gin.Context) {
 		return
 	}
 
+	converter, err := h.converters.Converter(c.DefaultQuery("format", models.FormatPostman))
+	if err != nil {
+		SendServiceError(c, err, "Failed to export collection")
+		return
+	}
+
+	format := converter.Format()
+	if !format.Export {
+		SendBadRequest(c, fmt.Sprintf("Collections cannot be exported to format %q", format.Name))
+		return
+	}
+
 	collection, err := h.collectionService.GetCollection(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "Collection not found")
+		SendServiceError(c, err, "Failed to get collection")
 		return
 	}
 
-	data, err := h.collectionService.ExportPostmanCollection(c.Request.Context(), id)
+	data, err := converter.Export(c.Request.Context(), id)
 	if err != nil {
-		SendInternalError(c, "Failed to export collection: "+err.Error())
+		SendServiceError(c, err, "Failed to export collection")
 		return
 	}
 
-	filename := fmt.Sprintf("%s.postman_collection.json", collection.Name)
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Data(http.StatusOK, "application/json", data)
+	SendExport(c, h.signingService, models.ExportManifest{
+		Kind:        format.ExportKind,
+		ID:          id,
+		Filename:    collection.Name + format.Extension,
+		ContentType: format.ContentType,
+	}, data)
 }
@@ -1,18 +1,30 @@
 package handlers
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"postman-api/internal/config"
+	"postman-api/internal/models"
+	"postman-api/internal/resilience"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
 
 // Response is a common API response structure
 type Response struct {
-	Success bool   `json:"success"`
-	Data    any    `json:"data,omitempty"`
-	Error   string `json:"error,omitempty"`
-	Meta    *Meta  `json:"meta,omitempty"`
+	Success bool             `json:"success"`
+	Data    any              `json:"data,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Code    models.ErrorCode `json:"code,omitempty"`
+	// Fields lists the per-field failures of a validation error
+	Fields []models.ValidationIssue `json:"fields,omitempty"`
+	Meta   *Meta                    `json:"meta,omitempty"`
 }
 
 // Meta contains metadata for paginated responses
@@ -21,6 +33,35 @@ type Meta struct {
 	PageSize  int `json:"pageSize"`
 	TotalRows int `json:"totalRows"`
 	TotalPage int `json:"totalPage"`
+	// TotalEstimated is set when TotalRows is a planner estimate rather than an exact count
+	TotalEstimated bool `json:"totalEstimated,omitempty"`
+	// Next and Prev link to the neighbouring pages, when there are any
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// paginationKey stores the page size limits in the request context
+const paginationKey = "pagination"
+
+type paginationLimits struct {
+	defaultSize int
+	maxSize     int
+}
+
+// Pagination sets the default and maximum page sizes of list endpoints
+func Pagination(defaultSize, maxSize int) gin.HandlerFunc {
+	if maxSize < 1 {
+		maxSize = config.DefaultMaxPageSize
+	}
+	if defaultSize < 1 || defaultSize > maxSize {
+		defaultSize = min(config.DefaultPageSize, maxSize)
+	}
+
+	limits := paginationLimits{defaultSize: defaultSize, maxSize: maxSize}
+	return func(c *gin.Context) {
+		c.Set(paginationKey, limits)
+		c.Next()
+	}
 }
 
 // SuccessResponse creates a success response with data
@@ -31,18 +72,19 @@ func SuccessResponse(data any) Response {
 	}
 }
 
-// ErrorResponse creates an error response with message
-func ErrorResponse(message string) Response {
+// ErrorResponse creates an error response with code and message
+func ErrorResponse(code models.ErrorCode, message string) Response {
 	return Response{
 		Success: false,
 		Error:   message,
+		Code:    code,
 	}
 }
 
 // PaginatedResponse creates a paginated response
-func PaginatedResponse(data any, page, pageSize, total int) Response {
-	totalPage := total / pageSize
-	if total%pageSize > 0 {
+func PaginatedResponse(data any, page, pageSize int, total models.Total) Response {
+	totalPage := total.Count / pageSize
+	if total.Count%pageSize > 0 {
 		totalPage++
 	}
 
@@ -50,32 +92,62 @@ func PaginatedResponse(data any, page, pageSize, total int) Response {
 		Success: true,
 		Data:    data,
 		Meta: &Meta{
-			Page:      page,
-			PageSize:  pageSize,
-			TotalRows: total,
-			TotalPage: totalPage,
+			Page:           page,
+			PageSize:       pageSize,
+			TotalRows:      total.Count,
+			TotalPage:      totalPage,
+			TotalEstimated: total.Estimated,
 		},
 	}
 }
 
-// GetPaginationParams extracts pagination parameters from the request
+// GetPaginationParams extracts pagination parameters from the request; a
+// page_size of 0 asks for the largest page allowed
 func GetPaginationParams(c *gin.Context) (page int, pageSize int) {
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
+	limits := paginationLimits{defaultSize: config.DefaultPageSize, maxSize: config.DefaultMaxPageSize}
+	if value, ok := c.Get(paginationKey); ok {
+		limits = value.(paginationLimits)
+	}
 
-	page, err := strconv.Atoi(pageStr)
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
 	}
 
-	pageSize, err = strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 || pageSize > 100 {
-		pageSize = 10
+	pageSize, err = strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(limits.defaultSize)))
+	switch {
+	case err != nil || pageSize < 0:
+		pageSize = limits.defaultSize
+	case pageSize == 0 || pageSize > limits.maxSize:
+		pageSize = limits.maxSize
 	}
 
 	return page, pageSize
 }
 
+// GetCountMode extracts the count mode (exact, estimate or auto) from the request
+func GetCountMode(c *gin.Context) models.CountMode {
+	switch mode := models.CountMode(c.Query("count")); mode {
+	case models.CountExact, models.CountEstimate:
+		return mode
+	default:
+		return models.CountAuto
+	}
+}
+
+// parseIDList parses a comma separated list of IDs, such as "1,5,9"
+func parseIDList(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // SendJSON is a helper function to send JSON responses
 func SendJSON(c *gin.Context, statusCode int, response Response) {
 	c.JSON(statusCode, response)
@@ -91,9 +163,97 @@ func SendCreated(c *gin.Context, data any) {
 	SendJSON(c, http.StatusCreated, SuccessResponse(data))
 }
 
+// SendAccepted sends an accepted response for work queued as a job
+func SendAccepted(c *gin.Context, job *models.Job) {
+	c.Header("Location", apiLink(c, "/jobs/%d", job.ID))
+	SendJSON(c, http.StatusAccepted, SuccessResponse(withJobLinks(c, job)))
+}
+
+// SendImported sends the result of an import: created for a new document,
+// success when an identical one was already imported
+func SendImported(c *gin.Context, result *models.ImportResult) {
+	if result.Existing {
+		SendSuccess(c, result)
+		return
+	}
+	SendCreated(c, result)
+}
+
+// statusCodes maps error codes to HTTP statuses
+var statusCodes = map[models.ErrorCode]int{
+	models.ErrCodeNotFound:     http.StatusNotFound,
+	models.ErrCodeValidation:   http.StatusBadRequest,
+	models.ErrCodeConflict:     http.StatusConflict,
+	models.ErrCodeTooLarge:     http.StatusRequestEntityTooLarge,
+	models.ErrCodeUnauthorized: http.StatusUnauthorized,
+	models.ErrCodeUpstream:     http.StatusBadGateway,
+	models.ErrCodeUnavailable:  http.StatusServiceUnavailable,
+	models.ErrCodeInternal:     http.StatusInternalServerError,
+}
+
+// codeForStatus returns the error code reported with an HTTP status
+func codeForStatus(statusCode int) models.ErrorCode {
+	for code, status := range statusCodes {
+		if status == statusCode {
+			return code
+		}
+	}
+	if statusCode >= 400 && statusCode < 500 {
+		return models.ErrCodeValidation
+	}
+	return models.ErrCodeInternal
+}
+
 // SendError sends an error response
 func SendError(c *gin.Context, statusCode int, message string) {
-	SendJSON(c, statusCode, ErrorResponse(message))
+	sendError(c, statusCode, codeForStatus(statusCode), message, nil)
+}
+
+// sendError sends an error in the response envelope, or as problem details
+// when the client asks for them
+func sendError(c *gin.Context, statusCode int, code models.ErrorCode, message string, fields []models.ValidationIssue) {
+	if WantsProblem(c) {
+		problem := NewProblem(c, statusCode, code, message)
+		problem.Errors = fields
+		SendProblem(c, problem)
+		return
+	}
+
+	response := ErrorResponse(code, message)
+	response.Fields = fields
+	SendJSON(c, statusCode, response)
+}
+
+// SendServiceError sends the status and code of a domain error with its
+// message; other errors are logged and reported as message without detail
+func SendServiceError(c *gin.Context, err error, message string) {
+	statusCode, code, message, fields := describeError(c, err, message)
+	sendError(c, statusCode, code, message, fields)
+}
+
+// describeError returns the status, code, client-facing message and field
+// failures reported for a service error
+func describeError(c *gin.Context, err error, message string) (int, models.ErrorCode, string, []models.ValidationIssue) {
+	if appErr, ok := models.AsError(err); ok && appErr.Code != models.ErrCodeInternal {
+		message = capitalize(appErr.Message)
+		if len(appErr.Fields) > 0 {
+			message = "Validation failed"
+		}
+		return statusCodes[appErr.Code], appErr.Code, message, appErr.Fields
+	}
+
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return http.StatusServiceUnavailable, models.ErrCodeUnavailable, "Database is unavailable, try again later", nil
+	}
+
+	log.Printf("%s %s: %s: %v", c.Request.Method, c.FullPath(), message, err)
+	return http.StatusInternalServerError, models.ErrCodeInternal, message, nil
+}
+
+// capitalize upper-cases the first letter of a message
+func capitalize(message string) string {
+	r, size := utf8.DecodeRuneInString(message)
+	return string(unicode.ToUpper(r)) + message[size:]
 }
 
 // SendBadRequest sends a bad request error
@@ -111,7 +271,94 @@ func SendInternalError(c *gin.Context, message string) {
 	SendError(c, http.StatusInternalServerError, message)
 }
 
-// SendPaginated sends a paginated response
-func SendPaginated(c *gin.Context, data any, page, pageSize, total int) {
-	SendJSON(c, http.StatusOK, PaginatedResponse(data, page, pageSize, total))
+// SendPaginated sends a paginated response with links to the neighbouring pages
+func SendPaginated(c *gin.Context, data any, page, pageSize int, total models.Total) {
+	response := PaginatedResponse(data, page, pageSize, total)
+	if page < response.Meta.TotalPage {
+		response.Meta.Next = pageLink(c, page+1, pageSize)
+	}
+	if page > 1 {
+		response.Meta.Prev = pageLink(c, min(page-1, max(response.Meta.TotalPage, 1)), pageSize)
+	}
+
+	SendJSON(c, http.StatusOK, response)
+}
+
+// pageLink returns the link to another page of the current request, composed
+// like apiLink from the link base
+func pageLink(c *gin.Context, page, pageSize int) string {
+	query := c.Request.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(pageSize))
+
+	return link(c, routePath(c)+"?"+query.Encode())
+}
+
+// ReadUploadedDocument reads a document from the "file" form field or, for
+// JSON requests, from the raw request body
+func ReadUploadedDocument(c *gin.Context) ([]byte, error) {
+	if c.ContentType() == "application/json" {
+		return io.ReadAll(c.Request.Body)
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// readUploads reads every file uploaded under field of a multipart form
+func readUploads(c *gin.Context, field string) ([]models.ImportFile, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := form.File[field]
+	if len(headers) == 0 {
+		return nil, http.ErrMissingFile
+	}
+
+	files := make([]models.ImportFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, models.ImportFile{Filename: header.Filename, Data: data})
+	}
+
+	return files, nil
+}
+
+// SendValidationResult sends a validation report, using 422 when the document is invalid
+func SendValidationResult(c *gin.Context, result *models.ValidationResult) {
+	if result.Valid {
+		SendSuccess(c, result)
+		return
+	}
+
+	if WantsProblem(c) {
+		problem := NewProblem(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, "Document is invalid")
+		problem.Errors = result.Errors
+		SendProblem(c, problem)
+		return
+	}
+
+	SendJSON(c, http.StatusUnprocessableEntity, Response{
+		Success: false,
+		Data:    result,
+		Error:   "Document is invalid",
+		Code:    models.ErrCodeValidation,
+	})
 }
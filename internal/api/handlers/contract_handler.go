@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContractTestHandler handles HTTP requests for generated contract tests
+type ContractTestHandler struct {
+	contractTestService interfaces.ContractTestService
+}
+
+// NewContractTestHandler creates a new contract test handler
+func NewContractTestHandler(contractTestService interfaces.ContractTestService) *ContractTestHandler {
+	return &ContractTestHandler{
+		contractTestService: contractTestService,
+	}
+}
+
+// Generate stores a contract test collection for a spec, one request per operation
+func (h *ContractTestHandler) Generate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	collection, err := h.contractTestService.GenerateContractTests(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate contract tests")
+		return
+	}
+
+	SendCreated(c, withCollectionLinks(c, collection))
+}
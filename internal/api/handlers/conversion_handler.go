@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConversionHandler handles HTTP requests for converting specs into collections
+type ConversionHandler struct {
+	conversionService interfaces.ConversionService
+}
+
+// NewConversionHandler creates a new conversion handler
+func NewConversionHandler(conversionService interfaces.ConversionService) *ConversionHandler {
+	return &ConversionHandler{
+		conversionService: conversionService,
+	}
+}
+
+// ConvertToCollection stores a collection generated from a spec, a folder per tag and a request per operation
+func (h *ConversionHandler) ConvertToCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	collection, err := h.conversionService.ConvertToCollection(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to convert spec to collection")
+		return
+	}
+
+	SendCreated(c, withCollectionLinks(c, collection))
+}
+
+// SyncFromSpec regenerates a collection from the current version of the spec
+// it was converted from, keeping what users added to unchanged operations
+func (h *ConversionHandler) SyncFromSpec(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	report, err := h.conversionService.SyncFromSpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to sync collection from spec")
+		return
+	}
+
+	SendSuccess(c, report)
+}
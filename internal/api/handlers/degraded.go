@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"postman-api/internal/resilience"
+
+	"github.com/gin-gonic/gin"
+)
+
+// degradedWarning is sent when a response was built from replica reads
+const degradedWarning = `110 - "Response may be stale: served from a read replica while the primary database is unavailable"`
+
+// DegradedWarning adds a Warning header to responses whose reads fell back to the replica
+func DegradedWarning() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := resilience.WithDegradedMarker(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &degradedWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Next()
+	}
+}
+
+// degradedWriter sets the Warning header just before the headers are flushed
+type degradedWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+func (w *degradedWriter) warn() {
+	if !w.Written() && resilience.IsDegraded(w.ctx) {
+		w.Header().Set("Warning", degradedWarning)
+	}
+}
+
+func (w *degradedWriter) WriteHeaderNow() {
+	w.warn()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *degradedWriter) Write(data []byte) (int, error) {
+	w.warn()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *degradedWriter) WriteString(s string) (int, error) {
+	w.warn()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to lift the
+// write deadline of a stream
+func (w *degradedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
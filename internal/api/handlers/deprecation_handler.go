@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationHandler handles HTTP requests for the deprecation report
+type DeprecationHandler struct {
+	deprecationService interfaces.DeprecationService
+}
+
+// NewDeprecationHandler creates a new deprecation handler
+func NewDeprecationHandler(deprecationService interfaces.DeprecationService) *DeprecationHandler {
+	return &DeprecationHandler{
+		deprecationService: deprecationService,
+	}
+}
+
+// List reports deprecated endpoints across specs and collections, filtered by
+// source, spec_id, collection_id and sunset_before
+func (h *DeprecationHandler) List(c *gin.Context) {
+	filter := models.DeprecationFilter{Source: c.Query("source")}
+
+	switch filter.Source {
+	case "", models.DeprecationSourceSpec, models.DeprecationSourceRequest:
+	default:
+		SendBadRequest(c, "Invalid source, expected spec or request")
+		return
+	}
+
+	if raw := c.Query("spec_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			SendBadRequest(c, "Invalid spec_id")
+			return
+		}
+		filter.SpecID = id
+	}
+
+	if raw := c.Query("collection_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			SendBadRequest(c, "Invalid collection_id")
+			return
+		}
+		filter.CollectionID = id
+	}
+
+	if raw := c.Query("sunset_before"); raw != "" {
+		date, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			SendBadRequest(c, "Invalid sunset_before date, expected YYYY-MM-DD")
+			return
+		}
+		filter.SunsetBefore = &date
+	}
+
+	endpoints, err := h.deprecationService.ListDeprecated(c.Request.Context(), filter)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list deprecated endpoints")
+		return
+	}
+
+	SendSuccess(c, endpoints)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DigestHandler handles HTTP requests for digest subscriptions
+type DigestHandler struct {
+	digestService interfaces.DigestService
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digestService interfaces.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// Create registers a new digest subscription, enabled unless stated otherwise
+func (h *DigestHandler) Create(c *gin.Context) {
+	subscription := models.DigestSubscription{Enabled: true}
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.digestService.CreateDigestSubscription(c.Request.Context(), &subscription); err != nil {
+		SendServiceError(c, err, "Failed to create digest subscription")
+		return
+	}
+
+	SendCreated(c, subscription)
+}
+
+// Get retrieves a digest subscription by ID
+func (h *DigestHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	subscription, err := h.digestService.GetDigestSubscription(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get digest subscription")
+		return
+	}
+
+	SendSuccess(c, subscription)
+}
+
+// List returns all digest subscriptions with pagination
+func (h *DigestHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	subscriptions, total, err := h.digestService.ListDigestSubscriptions(c.Request.Context(), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list digest subscriptions")
+		return
+	}
+
+	SendPaginated(c, subscriptions, page, pageSize, models.Total{Count: total})
+}
+
+// Update updates an existing digest subscription
+func (h *DigestHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	subscription := models.DigestSubscription{Enabled: true}
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	subscription.ID = id
+
+	if err := h.digestService.UpdateDigestSubscription(c.Request.Context(), &subscription); err != nil {
+		SendServiceError(c, err, "Failed to update digest subscription")
+		return
+	}
+
+	SendSuccess(c, subscription)
+}
+
+// Delete removes a digest subscription
+func (h *DigestHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	if err := h.digestService.DeleteDigestSubscription(c.Request.Context(), id); err != nil {
+		SendServiceError(c, err, "Failed to delete digest subscription")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Digest subscription deleted successfully"})
+}
+
+// Preview returns the digest a subscription would be sent now, without sending it
+func (h *DigestHandler) Preview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	digest, err := h.digestService.PreviewDigest(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to build digest")
+		return
+	}
+
+	SendSuccess(c, digest)
+}
+
+// Send sends a subscription its digest immediately instead of waiting for the scheduler
+func (h *DigestHandler) Send(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	digest, err := h.digestService.SendDigest(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to send digest")
+		return
+	}
+
+	SendSuccess(c, digest)
+}
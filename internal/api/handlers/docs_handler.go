@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"postman-api/internal/codegen"
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// docsContentTypes maps documentation formats to their content types
+var docsContentTypes = map[string]string{
+	codegen.DocsMarkdown: "text/markdown; charset=utf-8",
+	codegen.DocsHTML:     "text/html; charset=utf-8",
+}
+
+// DocsHandler handles HTTP requests for collection documentation
+type DocsHandler struct {
+	docsService interfaces.DocsService
+}
+
+// NewDocsHandler creates a new docs handler
+func NewDocsHandler(docsService interfaces.DocsService) *DocsHandler {
+	return &DocsHandler{
+		docsService: docsService,
+	}
+}
+
+// CollectionDocs renders the documentation of a collection as Markdown, or
+// as HTML with format=html, resolving variables with environment_id when given
+func (h *DocsHandler) CollectionDocs(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	format := c.DefaultQuery("format", codegen.DocsMarkdown)
+	if !codegen.ValidDocsFormat(format) {
+		SendBadRequest(c, "Invalid format, expected markdown or html")
+		return
+	}
+
+	var environmentID int64
+	if raw := c.Query("environment_id"); raw != "" {
+		if environmentID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			SendBadRequest(c, "Invalid environment_id format")
+			return
+		}
+	}
+
+	data, err := h.docsService.CollectionDocs(c.Request.Context(), id, environmentID, format)
+	if err != nil {
+		SendServiceError(c, err, "Failed to render collection documentation")
+		return
+	}
+
+	c.Data(http.StatusOK, docsContentTypes[format], data)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvironmentHandler handles HTTP requests for environments
+type EnvironmentHandler struct {
+	environmentService interfaces.EnvironmentService
+}
+
+// NewEnvironmentHandler creates a new environment handler
+func NewEnvironmentHandler(environmentService interfaces.EnvironmentService) *EnvironmentHandler {
+	return &EnvironmentHandler{
+		environmentService: environmentService,
+	}
+}
+
+// Create stores a new environment
+func (h *EnvironmentHandler) Create(c *gin.Context) {
+	var env models.Environment
+	if err := c.ShouldBindJSON(&env); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.environmentService.CreateEnvironment(c.Request.Context(), &env); err != nil {
+		SendServiceError(c, err, "Failed to create environment")
+		return
+	}
+
+	SendCreated(c, env)
+}
+
+// Get retrieves an environment by ID; secret values are masked
+func (h *EnvironmentHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	env, err := h.environmentService.GetEnvironment(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get environment")
+		return
+	}
+
+	SendSuccess(c, env)
+}
+
+// List returns all environments with pagination
+func (h *EnvironmentHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	envs, total, err := h.environmentService.ListEnvironments(c.Request.Context(), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list environments")
+		return
+	}
+
+	SendPaginated(c, envs, page, pageSize, models.Total{Count: total})
+}
+
+// Update replaces the name and variables of an environment
+func (h *EnvironmentHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var env models.Environment
+	if err := c.ShouldBindJSON(&env); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	env.ID = id
+
+	if err := h.environmentService.UpdateEnvironment(c.Request.Context(), &env); err != nil {
+		SendServiceError(c, err, "Failed to update environment")
+		return
+	}
+
+	SendSuccess(c, env)
+}
+
+// Delete removes an environment
+func (h *EnvironmentHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	if err := h.environmentService.DeleteEnvironment(c.Request.Context(), id); err != nil {
+		SendServiceError(c, err, "Failed to delete environment")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Environment deleted successfully"})
+}
+
+// Import imports a Postman environment from an uploaded file or JSON body
+func (h *EnvironmentHandler) Import(c *gin.Context) {
+	data, err := ReadUploadedDocument(c)
+	if err != nil {
+		SendBadRequest(c, "Invalid file: "+err.Error())
+		return
+	}
+
+	stripSecrets, _ := strconv.ParseBool(c.Query("strip_secrets"))
+	opts := models.ImportOptions{StripSecrets: stripSecrets}
+
+	envID, err := h.environmentService.ImportPostmanEnvironment(c.Request.Context(), data, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to import environment")
+		return
+	}
+
+	SendCreated(c, map[string]int64{"id": envID})
+}
+
+// Export exports an environment to Postman format; secret values are only
+// included with include_secrets=true
+func (h *EnvironmentHandler) Export(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	env, err := h.environmentService.GetEnvironment(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get environment")
+		return
+	}
+
+	includeSecrets, _ := strconv.ParseBool(c.Query("include_secrets"))
+
+	data, err := h.environmentService.ExportPostmanEnvironment(c.Request.Context(), id, includeSecrets)
+	if err != nil {
+		SendServiceError(c, err, "Failed to export environment")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.postman_environment.json", env.Name)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/json", data)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExampleHandler handles HTTP requests for the saved examples of requests
+type ExampleHandler struct {
+	exampleService interfaces.ExampleService
+}
+
+// NewExampleHandler creates a new example handler
+func NewExampleHandler(exampleService interfaces.ExampleService) *ExampleHandler {
+	return &ExampleHandler{
+		exampleService: exampleService,
+	}
+}
+
+// List returns the examples of a request
+func (h *ExampleHandler) List(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	examples, err := h.exampleService.ListExamples(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list examples")
+		return
+	}
+
+	for _, example := range examples {
+		withExampleLinks(c, example)
+	}
+
+	SendSuccess(c, examples)
+}
+
+// Get returns a single example of a request
+func (h *ExampleHandler) Get(c *gin.Context) {
+	id, exampleID, ok := exampleParams(c)
+	if !ok {
+		return
+	}
+
+	example, err := h.exampleService.GetExample(c.Request.Context(), id, exampleID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get example")
+		return
+	}
+
+	SendSuccess(c, withExampleLinks(c, example))
+}
+
+// Create adds an example to a request
+func (h *ExampleHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var example models.Example
+	if err := c.ShouldBindJSON(&example); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.exampleService.CreateExample(c.Request.Context(), id, &example); err != nil {
+		SendServiceError(c, err, "Failed to create example")
+		return
+	}
+
+	SendCreated(c, withExampleLinks(c, &example))
+}
+
+// Update replaces the content of an example
+func (h *ExampleHandler) Update(c *gin.Context) {
+	id, exampleID, ok := exampleParams(c)
+	if !ok {
+		return
+	}
+
+	var example models.Example
+	if err := c.ShouldBindJSON(&example); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.exampleService.UpdateExample(c.Request.Context(), id, exampleID, &example); err != nil {
+		SendServiceError(c, err, "Failed to update example")
+		return
+	}
+
+	SendSuccess(c, withExampleLinks(c, &example))
+}
+
+// Delete removes an example from a request
+func (h *ExampleHandler) Delete(c *gin.Context) {
+	id, exampleID, ok := exampleParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.exampleService.DeleteExample(c.Request.Context(), id, exampleID); err != nil {
+		SendServiceError(c, err, "Failed to delete example")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Example deleted successfully"})
+}
+
+// Body streams the body of an example with the content type it was saved with
+func (h *ExampleHandler) Body(c *gin.Context) {
+	id, exampleID, ok := exampleParams(c)
+	if !ok {
+		return
+	}
+
+	example, body, err := h.exampleService.OpenExampleBody(c.Request.Context(), id, exampleID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to open example")
+		return
+	}
+	defer body.Close()
+
+	sendSavedBody(c, example.Header, example.BodyTruncated, body)
+}
+
+// exampleParams parses the request and example IDs of an example route,
+// responding with an error when either is invalid
+func exampleParams(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return 0, 0, false
+	}
+
+	exampleID, err := strconv.ParseInt(c.Param("exampleId"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid example ID format")
+		return 0, 0, false
+	}
+
+	return id, exampleID, true
+}
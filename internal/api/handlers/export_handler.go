@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler handles HTTP requests for verifying signed exports
+type ExportHandler struct {
+	signingService interfaces.SigningService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(signingService interfaces.SigningService) *ExportHandler {
+	return &ExportHandler{
+		signingService: signingService,
+	}
+}
+
+// Verify checks a signed export produced by this catalog
+func (h *ExportHandler) Verify(c *gin.Context) {
+	var export models.SignedExport
+	if err := c.ShouldBindJSON(&export); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	verification, err := h.signingService.VerifyExport(c.Request.Context(), &export)
+	if err != nil {
+		SendServiceError(c, err, "Failed to verify export")
+		return
+	}
+
+	SendSuccess(c, verification)
+}
+
+// SigningKey describes the key exports are signed with, including the public
+// key for Ed25519 so consumers can verify exports themselves
+func (h *ExportHandler) SigningKey(c *gin.Context) {
+	key, err := h.signingService.SigningKey(c.Request.Context())
+	if err != nil {
+		SendServiceError(c, err, "Failed to get signing key")
+		return
+	}
+
+	SendSuccess(c, key)
+}
+
+// SendExport sends an exported document as a download; with signed=true it
+// is wrapped with a signed manifest described by manifest
+func SendExport(c *gin.Context, signingService interfaces.SigningService, manifest models.ExportManifest, data []byte) {
+	if signed, _ := strconv.ParseBool(c.Query("signed")); !signed {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", manifest.Filename))
+		c.Data(http.StatusOK, manifest.ContentType, data)
+		return
+	}
+
+	export, err := signingService.SignExport(c.Request.Context(), manifest, data)
+	if err != nil {
+		SendServiceError(c, err, "Failed to sign export")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.signed.json", manifest.Filename))
+	c.JSON(http.StatusOK, export)
+}
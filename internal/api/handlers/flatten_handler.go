@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlattenHandler handles HTTP requests for flattened collections
+type FlattenHandler struct {
+	flattenService interfaces.FlattenService
+}
+
+// NewFlattenHandler creates a new flatten handler
+func NewFlattenHandler(flattenService interfaces.FlattenService) *FlattenHandler {
+	return &FlattenHandler{
+		flattenService: flattenService,
+	}
+}
+
+// FlattenCollection returns the requests of a collection fully resolved,
+// using the variables of environment_id when given
+func (h *FlattenHandler) FlattenCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var environmentID int64
+	if raw := c.Query("environment_id"); raw != "" {
+		if environmentID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			SendBadRequest(c, "Invalid environment_id format")
+			return
+		}
+	}
+
+	requests, err := h.flattenService.FlattenCollection(c.Request.Context(), id, environmentID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to flatten collection")
+		return
+	}
+
+	SendSuccess(c, requests)
+}
+
+// ResolveRequest previews a request with its variables substituted, using
+// the variables of environment_id when given
+func (h *FlattenHandler) ResolveRequest(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var environmentID int64
+	if raw := c.Query("environment_id"); raw != "" {
+		if environmentID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			SendBadRequest(c, "Invalid environment_id format")
+			return
+		}
+	}
+
+	resolved, err := h.flattenService.ResolveRequest(c.Request.Context(), id, environmentID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to resolve request")
+		return
+	}
+
+	SendSuccess(c, resolved)
+}
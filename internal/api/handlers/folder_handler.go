@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FolderHandler handles HTTP requests for the folders of a collection
+type FolderHandler struct {
+	folderService interfaces.FolderService
+}
+
+// NewFolderHandler creates a new folder handler
+func NewFolderHandler(folderService interfaces.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+// List returns the folders of a collection, each followed by its subfolders
+func (h *FolderHandler) List(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	folders, err := h.folderService.ListFolders(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list folders")
+		return
+	}
+
+	for _, folder := range folders {
+		withFolderLinks(c, folder)
+	}
+
+	SendSuccess(c, folders)
+}
+
+// Get returns a single folder of a collection
+func (h *FolderHandler) Get(c *gin.Context) {
+	id, folderID, ok := folderParams(c)
+	if !ok {
+		return
+	}
+
+	folder, err := h.folderService.GetFolder(c.Request.Context(), id, folderID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get folder")
+		return
+	}
+
+	SendSuccess(c, withFolderLinks(c, folder))
+}
+
+// Create adds a folder to a collection
+func (h *FolderHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var input models.FolderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	folder, err := h.folderService.CreateFolder(c.Request.Context(), id, &input)
+	if err != nil {
+		SendServiceError(c, err, "Failed to create folder")
+		return
+	}
+
+	SendCreated(c, withFolderLinks(c, folder))
+}
+
+// Update renames, moves or repositions a folder
+func (h *FolderHandler) Update(c *gin.Context) {
+	id, folderID, ok := folderParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.FolderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	folder, err := h.folderService.UpdateFolder(c.Request.Context(), id, folderID, &input)
+	if err != nil {
+		SendServiceError(c, err, "Failed to update folder")
+		return
+	}
+
+	SendSuccess(c, withFolderLinks(c, folder))
+}
+
+// Delete removes a folder with its subfolders and requests
+func (h *FolderHandler) Delete(c *gin.Context) {
+	id, folderID, ok := folderParams(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.folderService.DeleteFolder(c.Request.Context(), id, folderID)
+	if err != nil {
+		SendServiceError(c, err, "Failed to delete folder")
+		return
+	}
+
+	SendSuccess(c, map[string]int{"requests_deleted": deleted})
+}
+
+// folderParams parses the collection and folder IDs of a folder route,
+// responding with an error when either is invalid
+func folderParams(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return 0, 0, false
+	}
+
+	folderID, err := strconv.ParseInt(c.Param("folderId"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid folder ID format")
+		return 0, 0, false
+	}
+
+	return id, folderID, true
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FormatHandler handles HTTP requests describing collection formats
+type FormatHandler struct {
+	converters interfaces.ConverterRegistry
+}
+
+// NewFormatHandler creates a new format handler
+func NewFormatHandler(converters interfaces.ConverterRegistry) *FormatHandler {
+	return &FormatHandler{
+		converters: converters,
+	}
+}
+
+// List returns the formats collections can be imported from and exported to
+func (h *FormatHandler) List(c *gin.Context) {
+	SendSuccess(c, h.converters.Formats())
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GlobalVariableHandler handles HTTP requests for global variables
+type GlobalVariableHandler struct {
+	globalService interfaces.GlobalVariableService
+}
+
+// NewGlobalVariableHandler creates a new global variable handler
+func NewGlobalVariableHandler(globalService interfaces.GlobalVariableService) *GlobalVariableHandler {
+	return &GlobalVariableHandler{
+		globalService: globalService,
+	}
+}
+
+// List returns the managed global variables with pagination
+func (h *GlobalVariableHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	variables, total, err := h.globalService.ListGlobals(c.Request.Context(), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list global variables")
+		return
+	}
+
+	SendPaginated(c, variables, page, pageSize, models.Total{Count: total})
+}
+
+// Get retrieves a managed global variable by key
+func (h *GlobalVariableHandler) Get(c *gin.Context) {
+	variable, err := h.globalService.GetGlobal(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		SendServiceError(c, err, "Failed to get global variable")
+		return
+	}
+
+	SendSuccess(c, variable)
+}
+
+// Set creates or replaces the value and description of a global variable
+func (h *GlobalVariableHandler) Set(c *gin.Context) {
+	var variable models.GlobalVariable
+	if err := c.ShouldBindJSON(&variable); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	variable.Key = c.Param("key")
+
+	if err := h.globalService.SetGlobal(c.Request.Context(), &variable); err != nil {
+		SendServiceError(c, err, "Failed to set global variable")
+		return
+	}
+
+	SendSuccess(c, variable)
+}
+
+// Delete removes a managed global variable
+func (h *GlobalVariableHandler) Delete(c *gin.Context) {
+	if err := h.globalService.DeleteGlobal(c.Request.Context(), c.Param("key")); err != nil {
+		SendServiceError(c, err, "Failed to delete global variable")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Global variable deleted successfully"})
+}
+
+// History returns the changes to a global variable with pagination, newest first
+func (h *GlobalVariableHandler) History(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	changes, total, err := h.globalService.ListGlobalChanges(c.Request.Context(), c.Param("key"), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list global variable history")
+		return
+	}
+
+	SendPaginated(c, changes, page, pageSize, models.Total{Count: total})
+}
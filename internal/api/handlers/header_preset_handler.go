@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderPresetHandler handles HTTP requests for header presets
+type HeaderPresetHandler struct {
+	presetService interfaces.HeaderPresetService
+}
+
+// NewHeaderPresetHandler creates a new header preset handler
+func NewHeaderPresetHandler(presetService interfaces.HeaderPresetService) *HeaderPresetHandler {
+	return &HeaderPresetHandler{
+		presetService: presetService,
+	}
+}
+
+// Create stores a new header preset
+func (h *HeaderPresetHandler) Create(c *gin.Context) {
+	var preset models.HeaderPreset
+	if err := c.ShouldBindJSON(&preset); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.presetService.CreatePreset(c.Request.Context(), &preset); err != nil {
+		SendServiceError(c, err, "Failed to create header preset")
+		return
+	}
+
+	SendCreated(c, preset)
+}
+
+// Get retrieves a header preset by ID
+func (h *HeaderPresetHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	preset, err := h.presetService.GetPreset(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get header preset")
+		return
+	}
+
+	SendSuccess(c, preset)
+}
+
+// List returns all header presets with pagination
+func (h *HeaderPresetHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	presets, total, err := h.presetService.ListPresets(c.Request.Context(), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list header presets")
+		return
+	}
+
+	SendPaginated(c, presets, page, pageSize, models.Total{Count: total})
+}
+
+// Update replaces the name, description and headers of a header preset
+func (h *HeaderPresetHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var preset models.HeaderPreset
+	if err := c.ShouldBindJSON(&preset); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	preset.ID = id
+
+	if err := h.presetService.UpdatePreset(c.Request.Context(), &preset); err != nil {
+		SendServiceError(c, err, "Failed to update header preset")
+		return
+	}
+
+	SendSuccess(c, preset)
+}
+
+// Delete removes a header preset no request is attached to
+func (h *HeaderPresetHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	if err := h.presetService.DeletePreset(c.Request.Context(), id); err != nil {
+		SendServiceError(c, err, "Failed to delete header preset")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Header preset deleted successfully"})
+}
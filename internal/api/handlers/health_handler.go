@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthService interfaces.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService interfaces.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Ready answers 200 when the database is reachable and the circuit is closed, 503 otherwise
+func (h *HealthHandler) Ready(c *gin.Context) {
+	readiness := h.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if readiness.Status != models.ReadinessOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, readiness)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxHookBodySize = 10 << 20
+
+// HookHandler handles inbound webhooks from CI pipelines
+type HookHandler struct {
+	importHookService interfaces.ImportHookService
+	jobService        interfaces.JobService
+	importSecret      string
+}
+
+// NewHookHandler creates a new hook handler; an empty secret disables the hooks
+func NewHookHandler(importHookService interfaces.ImportHookService, jobService interfaces.JobService, importSecret string) *HookHandler {
+	return &HookHandler{
+		importHookService: importHookService,
+		jobService:        jobService,
+		importSecret:      importSecret,
+	}
+}
+
+// Import triggers an import of an inline document or artifact URL.
+// Callers authenticate with either an X-Hook-Secret header holding the shared
+// secret or an X-Hook-Signature header of the form sha256=<hex HMAC of the body>.
+// With async=true the import is queued as a job and retried on failure; the
+// job stores auth encrypted, so queuing it requires SECRETS_KEY.
+func (h *HookHandler) Import(c *gin.Context) {
+	if h.importSecret == "" {
+		SendNotFound(c, "Import hook is not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHookBodySize))
+	if err != nil {
+		SendBadRequest(c, "Failed to read body: "+err.Error())
+		return
+	}
+
+	if !h.authorized(c.Request, body) {
+		SendError(c, http.StatusUnauthorized, "Invalid hook secret or signature")
+		return
+	}
+
+	var payload models.ImportHookPayload
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(&payload); err != nil || payload.Type == "" {
+		SendBadRequest(c, "Invalid hook payload, type is required")
+		return
+	}
+
+	if async, _ := strconv.ParseBool(c.Query("async")); async {
+		data, err := h.importHookService.SealPayload(&payload)
+		if err != nil {
+			SendServiceError(c, err, "Failed to queue import")
+			return
+		}
+
+		job, err := h.jobService.Enqueue(c.Request.Context(), models.JobImportHook, data)
+		if err != nil {
+			SendServiceError(c, err, "Failed to queue import")
+			return
+		}
+
+		SendAccepted(c, job)
+		return
+	}
+
+	result, err := h.importHookService.HandleImport(c.Request.Context(), &payload)
+	if err != nil {
+		SendServiceError(c, err, "Failed to import document")
+		return
+	}
+
+	SendCreated(c, result)
+}
+
+func (h *HookHandler) authorized(r *http.Request, body []byte) bool {
+	if secret := r.Header.Get("X-Hook-Secret"); secret != "" {
+		return subtle.ConstantTimeCompare([]byte(secret), []byte(h.importSecret)) == 1
+	}
+
+	signature, ok := strings.CutPrefix(r.Header.Get("X-Hook-Signature"), "sha256=")
+	if !ok {
+		return false
+	}
+
+	provided, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.importSecret))
+	mac.Write(body)
+	return hmac.Equal(provided, mac.Sum(nil))
+}
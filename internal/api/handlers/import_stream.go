@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"postman-api/internal/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventStreamContentType is the media type of server-sent events
+const EventStreamContentType = "text/event-stream"
+
+// Import stream events sent after the progress stages
+const (
+	importEventDone  = "done"
+	importEventError = "error"
+)
+
+// WantsEventStream reports whether the client asked for server-sent events in Accept
+func WantsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), EventStreamContentType)
+}
+
+// StreamImport runs an import and streams its progress as server-sent events
+// named after the progress stages, ending with a "done" event carrying the
+// result or an "error" event carrying the error envelope
+func StreamImport(c *gin.Context, opts models.ImportOptions, message string, run func(models.ImportOptions) (*models.ImportResult, error)) {
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	opts.Progress = func(p models.ImportProgress) {
+		c.SSEvent(p.Stage, p)
+		c.Writer.Flush()
+	}
+
+	result, err := run(opts)
+	if err != nil {
+		_, code, message, fields := describeError(c, err, message)
+		response := ErrorResponse(code, message)
+		response.Fields = fields
+		c.SSEvent(importEventError, response)
+		return
+	}
+
+	c.SSEvent(importEventDone, result)
+}
+
+// uploadProvenance describes an uploaded document by its file name and the
+// optional source_url, git_commit, postman_workspace_id and imported_by form
+// fields; imported_by falls back to the X-Forwarded-User header set by an
+// authenticating proxy
+func uploadProvenance(c *gin.Context, filename string) *models.Provenance {
+	importedBy := c.PostForm("imported_by")
+	if importedBy == "" {
+		importedBy = c.GetHeader("X-Forwarded-User")
+	}
+
+	return &models.Provenance{
+		Source:             models.ProvenanceUpload,
+		Filename:           filename,
+		URL:                c.PostForm("source_url"),
+		GitCommit:          c.PostForm("git_commit"),
+		PostmanWorkspaceID: c.PostForm("postman_workspace_id"),
+		ImportedBy:         importedBy,
+	}
+}
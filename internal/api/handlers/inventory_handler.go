@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryHandler handles HTTP requests for the API inventory
+type InventoryHandler struct {
+	inventoryService interfaces.InventoryService
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(inventoryService interfaces.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// List returns every distinct endpoint across collections and specs, with
+// links to where it was seen, optionally filtered by host, method and ownership
+func (h *InventoryHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+	filter := models.InventoryFilter{
+		Host:            c.Query("host"),
+		Method:          c.Query("method"),
+		OwnershipFilter: GetOwnershipFilter(c),
+	}
+
+	entries, err := h.inventoryService.ListInventory(c.Request.Context(), filter)
+	if err != nil {
+		SendServiceError(c, err, "Failed to build inventory")
+		return
+	}
+
+	total := len(entries)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	SendPaginated(c, entries[start:end], page, pageSize, models.Total{Count: total})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler handles HTTP requests for background jobs
+type JobHandler struct {
+	jobService interfaces.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService interfaces.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// List returns jobs with pagination, filtered by the status and type query parameters
+func (h *JobHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+	filter := models.JobFilter{
+		Status: c.Query("status"),
+		Type:   c.Query("type"),
+	}
+
+	jobs, total, err := h.jobService.ListJobs(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list jobs")
+		return
+	}
+
+	for _, job := range jobs {
+		withJobLinks(c, job)
+	}
+
+	SendPaginated(c, jobs, page, pageSize, models.Total{Count: total})
+}
+
+// Get retrieves a job with its status, last error and result
+func (h *JobHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get job")
+		return
+	}
+
+	SendSuccess(c, withJobLinks(c, job))
+}
+
+// Retry queues a dead or cancelled job again
+func (h *JobHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	job, err := h.jobService.RetryJob(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to retry job")
+		return
+	}
+
+	SendSuccess(c, withJobLinks(c, job))
+}
+
+// Cancel stops a pending job from running
+func (h *JobHandler) Cancel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	job, err := h.jobService.CancelJob(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to cancel job")
+		return
+	}
+
+	SendSuccess(c, withJobLinks(c, job))
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"fmt"
+	"postman-api/internal/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIPrefix is the path prefix of the versioned API
+const APIPrefix = "/api/v1"
+
+// linkBaseKey is the context key of the link base set by LinkBase
+const linkBaseKey = "link_base"
+
+// linkBase is what links in responses are prefixed with
+type linkBase struct {
+	externalURL string
+	basePath    string
+}
+
+// LinkBase makes links in responses absolute to externalURL, when set, and
+// relative to the basePath routes are served under
+func LinkBase(externalURL, basePath string) gin.HandlerFunc {
+	base := linkBase{externalURL: externalURL, basePath: basePath}
+	return func(c *gin.Context) {
+		c.Set(linkBaseKey, base)
+		c.Next()
+	}
+}
+
+// link returns the link to a path below the base path of the request's router
+func link(c *gin.Context, path string) string {
+	var base linkBase
+	if value, ok := c.Get(linkBaseKey); ok {
+		base = value.(linkBase)
+	}
+
+	return base.externalURL + base.basePath + path
+}
+
+// routePath returns the path of the request below the base path it is served under
+func routePath(c *gin.Context) string {
+	if value, ok := c.Get(linkBaseKey); ok {
+		return strings.TrimPrefix(c.Request.URL.EscapedPath(), value.(linkBase).basePath)
+	}
+
+	return c.Request.URL.EscapedPath()
+}
+
+// apiLink returns the link to a path of the versioned API
+func apiLink(c *gin.Context, format string, args ...any) string {
+	return link(c, APIPrefix+fmt.Sprintf(format, args...))
+}
+
+// withCollectionLinks adds links to the operations on a collection and its requests
+func withCollectionLinks(c *gin.Context, collection *models.Collection) *models.Collection {
+	base := apiLink(c, "/postman/%d", collection.ID)
+	collection.Links = models.Links{
+		"self":      base,
+		"requests":  base + "/requests",
+		"items":     base + "/items",
+		"export":    base + "/export",
+		"lint":      base + "/lint",
+		"scan":      base + "/scan",
+		"flatten":   base + "/flatten",
+		"docs":      base + "/docs",
+		"run":       base + "/run",
+		"runs":      base + "/runs",
+		"folders":   base + "/folders",
+		"revisions": base + "/revisions",
+	}
+	if collection.Archived {
+		collection.Links["unarchive"] = base + "/unarchive"
+	} else {
+		collection.Links["archive"] = base + "/archive"
+	}
+
+	for _, request := range collection.Requests {
+		withRequestLinks(c, request)
+	}
+
+	return collection
+}
+
+// withRequestLinks adds links to the operations on a request and its collection
+func withRequestLinks(c *gin.Context, request *models.Request) *models.Request {
+	base := apiLink(c, "/requests/%d", request.ID)
+	request.Links = models.Links{
+		"self":       base,
+		"collection": apiLink(c, "/postman/%d", request.CollectionID),
+		"clone":      base + "/clone",
+		"execute":    base + "/execute",
+		"resolve":    base + "/resolve",
+		"examples":   base + "/examples",
+	}
+
+	return request
+}
+
+// withExampleLinks adds links to an example, its body and its request
+func withExampleLinks(c *gin.Context, example *models.Example) *models.Example {
+	request := apiLink(c, "/requests/%d", example.RequestID)
+	self := fmt.Sprintf("%s/examples/%d", request, example.ID)
+	example.Links = models.Links{
+		"self":    self,
+		"body":    self + "/body",
+		"request": request,
+	}
+
+	return example
+}
+
+// withFolderLinks adds links to a folder, its collection and its parent
+func withFolderLinks(c *gin.Context, folder *models.Folder) *models.Folder {
+	collection := apiLink(c, "/postman/%d", folder.CollectionID)
+	folder.Links = models.Links{
+		"self":       fmt.Sprintf("%s/folders/%d", collection, folder.ID),
+		"collection": collection,
+	}
+	if folder.ParentID != 0 {
+		folder.Links["parent"] = fmt.Sprintf("%s/folders/%d", collection, folder.ParentID)
+	}
+
+	return folder
+}
+
+// withSpecLinks adds links to the operations on an OpenAPI specification
+func withSpecLinks(c *gin.Context, spec *models.OpenAPISpec) *models.OpenAPISpec {
+	base := apiLink(c, "/openapi/%d", spec.ID)
+	spec.Links = models.Links{
+		"self":       base,
+		"export":     base + "/export",
+		"typescript": base + "/codegen/typescript",
+		"go":         base + "/codegen/go",
+		"server":     base + "/codegen/server",
+		"proto":      base + "/codegen/proto",
+		"terraform":  base + "/codegen/terraform",
+		"contract":   base + "/contract-tests",
+		"convert":    base + "/convert-to-collection",
+		"gateway":    base + "/gateway",
+		"kubernetes": base + "/kubernetes",
+		"revisions":  base + "/revisions",
+		"diff":       base + "/diff",
+		"review":     base + "/review",
+		"labels":     base + "/labels",
+	}
+
+	return spec
+}
+
+// withJobLinks adds links to the operations on a job
+func withJobLinks(c *gin.Context, job *models.Job) *models.Job {
+	base := apiLink(c, "/jobs/%d", job.ID)
+	job.Links = models.Links{"self": base}
+	switch job.Status {
+	case models.JobPending:
+		job.Links["cancel"] = base
+	case models.JobDead, models.JobCancelled:
+		job.Links["retry"] = base + "/retry"
+	}
+
+	return job
+}
+
+// withRevisionLinks sets the links of a collection revision
+func withRevisionLinks(c *gin.Context, revision *models.CollectionRevision) *models.CollectionRevision {
+	collection := apiLink(c, "/postman/%d", revision.CollectionID)
+	revision.Links = models.Links{
+		"self":       apiLink(c, "/revisions/%d", revision.ID),
+		"collection": collection,
+		"rollback":   fmt.Sprintf("%s/revisions/%d/rollback", collection, revision.ID),
+	}
+
+	return revision
+}
+
+// withLoadTestLinks adds links to a load test, the collection or request it
+// sent and the job it was queued as
+func withLoadTestLinks(c *gin.Context, test *models.LoadTest) *models.LoadTest {
+	test.Links = models.Links{
+		"self":       apiLink(c, "/load-tests/%d", test.ID),
+		"collection": apiLink(c, "/postman/%d", test.CollectionID),
+	}
+	if test.RequestID != 0 {
+		test.Links["request"] = apiLink(c, "/requests/%d", test.RequestID)
+	}
+	if test.Job != nil {
+		test.Links["job"] = withJobLinks(c, test.Job).Links["self"]
+	}
+
+	return test
+}
+
+// withRunLinks adds links to a run, the collection it ran and the job it was
+// queued as
+func withRunLinks(c *gin.Context, run *models.Run) *models.Run {
+	run.Links = models.Links{
+		"self":       apiLink(c, "/runs/%d", run.ID),
+		"collection": apiLink(c, "/postman/%d", run.CollectionID),
+		"metrics":    apiLink(c, "/runs/%d/metrics", run.ID),
+	}
+	if run.Job != nil {
+		run.Links["job"] = withJobLinks(c, run.Job).Links["self"]
+	}
+
+	return run
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LintHandler handles HTTP requests for collection lint passes
+type LintHandler struct {
+	lintService interfaces.LintService
+}
+
+// NewLintHandler creates a new lint handler
+func NewLintHandler(lintService interfaces.LintService) *LintHandler {
+	return &LintHandler{
+		lintService: lintService,
+	}
+}
+
+// LintCollection returns the convention findings for a collection
+func (h *LintHandler) LintCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	opts := models.LintOptions{Naming: strings.TrimSpace(c.Query("naming"))}
+	if opts.Naming != "" && !models.ValidNamingStyle(opts.Naming) {
+		SendBadRequest(c, "Invalid naming style: "+opts.Naming)
+		return
+	}
+
+	for _, rule := range strings.Split(c.Query("rules"), ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		if !slices.Contains(models.LintRules, rule) {
+			SendBadRequest(c, "Unknown lint rule: "+rule)
+			return
+		}
+		opts.Rules = append(opts.Rules, rule)
+	}
+
+	report, err := h.lintService.LintCollection(c.Request.Context(), id, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to lint collection")
+		return
+	}
+
+	SendSuccess(c, report)
+}
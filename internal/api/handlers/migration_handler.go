@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MigrationHandler handles HTTP requests starting data migrations
+type MigrationHandler struct {
+	jobService interfaces.JobService
+}
+
+// NewMigrationHandler creates a new migration handler
+func NewMigrationHandler(jobService interfaces.JobService) *MigrationHandler {
+	return &MigrationHandler{
+		jobService: jobService,
+	}
+}
+
+// MigrateItems queues the migration of collections stored only in their
+// legacy items blob onto folder and request rows; its progress is reported
+// on the job
+func (h *MigrationHandler) MigrateItems(c *gin.Context) {
+	job, err := h.jobService.Enqueue(c.Request.Context(), models.JobMigrateItems, nil)
+	if err != nil {
+		SendServiceError(c, err, "Failed to queue items migration")
+		return
+	}
+
+	SendAccepted(c, job)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"postman-api/internal/codegen"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
 	"strconv"
@@ -14,12 +16,14 @@ import (
 // OpenAPIHandler handles HTTP requests for OpenAPI specifications
 type OpenAPIHandler struct {
 	openAPIService interfaces.OpenAPIService
+	signingService interfaces.SigningService
 }
 
 // NewOpenAPIHandler creates a new OpenAPI handler
-func NewOpenAPIHandler(openAPIService interfaces.OpenAPIService) *OpenAPIHandler {
+func NewOpenAPIHandler(openAPIService interfaces.OpenAPIService, signingService interfaces.SigningService) *OpenAPIHandler {
 	return &OpenAPIHandler{
 		openAPIService: openAPIService,
+		signingService: signingService,
 	}
 }
 
@@ -32,11 +36,11 @@ func (h *OpenAPIHandler) Create(c *gin.Context) {
 	}
 
 	if err := h.openAPIService.CreateOpenAPISpec(c.Request.Context(), &spec); err != nil {
-		SendInternalError(c, "Failed to create OpenAPI specification: "+err.Error())
+		SendServiceError(c, err, "Failed to create OpenAPI specification")
 		return
 	}
 
-	SendCreated(c, spec)
+	SendCreated(c, withSpecLinks(c, &spec))
 }
 
 // Get retrieves an OpenAPI specification by ID
@@ -49,26 +53,69 @@ func (h *OpenAPIHandler) Get(c *gin.Context) {
 
 	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "OpenAPI specification not found")
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
 		return
 	}
 
-	SendSuccess(c, spec)
+	SendSuccess(c, withSpecLinks(c, spec))
 }
 
-// List returns all OpenAPI specifications with pagination
+// List returns all OpenAPI specifications with pagination, or with ids=1,5,9
+// the specifications with those IDs in that order
 func (h *OpenAPIHandler) List(c *gin.Context) {
+	if raw, ok := c.GetQuery("ids"); ok {
+		ids, err := parseIDList(raw)
+		if err != nil {
+			SendBadRequest(c, "Invalid ids format")
+			return
+		}
+
+		specs, err := h.openAPIService.GetOpenAPISpecs(c.Request.Context(), ids)
+		if err != nil {
+			SendServiceError(c, err, "Failed to get OpenAPI specifications")
+			return
+		}
+
+		for _, spec := range specs {
+			withSpecLinks(c, spec)
+		}
+
+		SendSuccess(c, specs)
+		return
+	}
+
 	page, pageSize := GetPaginationParams(c)
 
-	specs, total, err := h.openAPIService.ListOpenAPISpecs(c.Request.Context(), page, pageSize)
+	specs, total, err := h.openAPIService.ListOpenAPISpecs(c.Request.Context(), page, pageSize, GetCountMode(c))
 	if err != nil {
-		SendInternalError(c, "Failed to list OpenAPI specifications: "+err.Error())
+		SendServiceError(c, err, "Failed to list OpenAPI specifications")
 		return
 	}
 
+	for _, spec := range specs {
+		withSpecLinks(c, spec)
+	}
+
 	SendPaginated(c, specs, page, pageSize, total)
 }
 
+// Search returns the specifications whose title or description contains q, with pagination
+func (h *OpenAPIHandler) Search(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	specs, total, err := h.openAPIService.SearchOpenAPISpecs(c.Request.Context(), c.Query("q"), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to search OpenAPI specifications")
+		return
+	}
+
+	for _, spec := range specs {
+		withSpecLinks(c, spec)
+	}
+
+	SendPaginated(c, specs, page, pageSize, models.Total{Count: total})
+}
+
 // Update updates an existing OpenAPI specification
 func (h *OpenAPIHandler) Update(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -86,11 +133,11 @@ func (h *OpenAPIHandler) Update(c *gin.Context) {
 	spec.ID = id
 
 	if err := h.openAPIService.UpdateOpenAPISpec(c.Request.Context(), &spec); err != nil {
-		SendInternalError(c, "Failed to update OpenAPI specification: "+err.Error())
+		SendServiceError(c, err, "Failed to update OpenAPI specification")
 		return
 	}
 
-	SendSuccess(c, spec)
+	SendSuccess(c, withSpecLinks(c, &spec))
 }
 
 // Delete removes an OpenAPI specification
@@ -102,16 +149,96 @@ func (h *OpenAPIHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.openAPIService.DeleteOpenAPISpec(c.Request.Context(), id); err != nil {
-		SendInternalError(c, "Failed to delete OpenAPI specification: "+err.Error())
+		SendServiceError(c, err, "Failed to delete OpenAPI specification")
 		return
 	}
 
 	SendSuccess(c, map[string]string{"message": "OpenAPI specification deleted successfully"})
 }
 
-// Import imports an OpenAPI specification from JSON
+// ListRevisions returns the earlier contents of a spec with pagination, newest first
+func (h *OpenAPIHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	page, pageSize := GetPaginationParams(c)
+
+	revisions, total, err := h.openAPIService.ListSpecRevisions(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list OpenAPI specification revisions")
+		return
+	}
+
+	SendPaginated(c, revisions, page, pageSize, models.Total{Count: total})
+}
+
+// Diff compares two revisions of a spec; from defaults to the latest
+// revision and to, when omitted or "current", is the current content
+func (h *OpenAPIHandler) Diff(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var from, to int
+	if raw := c.Query("from"); raw != "" {
+		if from, err = strconv.Atoi(raw); err != nil || from < 1 {
+			SendBadRequest(c, "Invalid from, expected a revision number")
+			return
+		}
+	}
+	if raw := c.Query("to"); raw != "" && raw != "current" {
+		if to, err = strconv.Atoi(raw); err != nil || to < 1 {
+			SendBadRequest(c, "Invalid to, expected a revision number or current")
+			return
+		}
+	}
+
+	diff, err := h.openAPIService.DiffOpenAPISpec(c.Request.Context(), id, from, to)
+	if err != nil {
+		SendServiceError(c, err, "Failed to diff OpenAPI specification")
+		return
+	}
+
+	SendSuccess(c, diff)
+}
+
+// Compare classifies the changes to a spec since another spec or one of its
+// revisions as breaking or not; without a body it compares against the
+// latest revision
+func (h *OpenAPIHandler) Compare(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var body struct {
+		AgainstSpecID   int64 `json:"against_spec_id" binding:"min=0"`
+		AgainstRevision int   `json:"against_revision" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	comparison, err := h.openAPIService.CompareOpenAPISpec(c.Request.Context(), id, body.AgainstSpecID, body.AgainstRevision)
+	if err != nil {
+		SendServiceError(c, err, "Failed to compare OpenAPI specification")
+		return
+	}
+
+	SendSuccess(c, comparison)
+}
+
+// Import imports an OpenAPI specification from JSON; progress is streamed as
+// server-sent events when the client accepts text/event-stream
 func (h *OpenAPIHandler) Import(c *gin.Context) {
-	file, _, err := c.Request.FormFile("file")
+	file, header, err := c.Request.FormFile("file")
 	if err != nil {
 		SendBadRequest(c, "Invalid file: "+err.Error())
 		return
@@ -124,13 +251,42 @@ func (h *OpenAPIHandler) Import(c *gin.Context) {
 		return
 	}
 
-	specID, err := h.openAPIService.ImportOpenAPISpec(c.Request.Context(), data)
+	opts := models.ImportOptions{Provenance: uploadProvenance(c, header.Filename)}
+
+	run := func(opts models.ImportOptions) (*models.ImportResult, error) {
+		return h.openAPIService.ImportOpenAPISpec(c.Request.Context(), data, opts)
+	}
+
+	if WantsEventStream(c) {
+		StreamImport(c, opts, "Failed to import OpenAPI specification", run)
+		return
+	}
+
+	result, err := run(opts)
 	if err != nil {
-		SendBadRequest(c, "Failed to import OpenAPI specification: "+err.Error())
+		SendServiceError(c, err, "Failed to import OpenAPI specification")
 		return
 	}
 
-	SendCreated(c, map[string]int64{"id": specID})
+	SendImported(c, result)
+}
+
+// Validate runs the import validation pipeline on an uploaded document
+// without writing anything to the database
+func (h *OpenAPIHandler) Validate(c *gin.Context) {
+	data, err := ReadUploadedDocument(c)
+	if err != nil {
+		SendBadRequest(c, "Invalid file: "+err.Error())
+		return
+	}
+
+	result, err := h.openAPIService.ValidateOpenAPISpec(c.Request.Context(), data)
+	if err != nil {
+		SendServiceError(c, err, "Failed to validate OpenAPI specification")
+		return
+	}
+
+	SendValidationResult(c, result)
 }
 
 // Export exports an OpenAPI specification to JSON
@@ -143,17 +299,254 @@ func (h *OpenAPIHandler) Export(c *gin.Context) {
 
 	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "OpenAPI specification not found")
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
 		return
 	}
 
 	data, err := h.openAPIService.ExportOpenAPISpec(c.Request.Context(), id)
 	if err != nil {
-		SendInternalError(c, "Failed to export OpenAPI specification: "+err.Error())
+		SendServiceError(c, err, "Failed to export OpenAPI specification")
+		return
+	}
+
+	SendExport(c, h.signingService, models.ExportManifest{
+		Kind:        models.ExportKindOpenAPISpec,
+		ID:          id,
+		Filename:    fmt.Sprintf("%s.openapi.json", spec.Title),
+		ContentType: "application/json",
+	}, data)
+}
+
+// CodegenTypeScript downloads TypeScript type definitions generated from a stored spec
+func (h *OpenAPIHandler) CodegenTypeScript(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.GenerateTypeScript(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate TypeScript types")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.d.ts", spec.Title)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/typescript", data)
+}
+
+// CodegenGo downloads a zip of Go types and a typed client generated from a stored spec
+func (h *OpenAPIHandler) CodegenGo(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	pkg := c.DefaultQuery("package", "client")
+	if !codegen.ValidPackageName(pkg) {
+		SendBadRequest(c, "Invalid package name")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
 		return
 	}
 
-	filename := fmt.Sprintf("%s.openapi.json", spec.Title)
+	data, err := h.openAPIService.GenerateGoClient(c.Request.Context(), id, pkg)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate Go client")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.zip", spec.Title, pkg)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// CodegenServer downloads a zip of Gin or Echo handler stubs and routes generated from a stored spec
+func (h *OpenAPIHandler) CodegenServer(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	pkg := c.DefaultQuery("package", "server")
+	if !codegen.ValidPackageName(pkg) {
+		SendBadRequest(c, "Invalid package name")
+		return
+	}
+
+	framework := c.DefaultQuery("framework", codegen.FrameworkGin)
+	if !codegen.ValidFramework(framework) {
+		SendBadRequest(c, "Unsupported framework: use gin or echo")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.GenerateGoServer(c.Request.Context(), id, pkg, framework)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate server stubs")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.zip", spec.Title, pkg)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// CodegenProto downloads a .proto definition converted from a stored spec
+func (h *OpenAPIHandler) CodegenProto(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	pkg := c.DefaultQuery("package", "api.v1")
+	if !codegen.ValidProtoPackage(pkg) {
+		SendBadRequest(c, "Invalid proto package name")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.GenerateProto(c.Request.Context(), id, pkg)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate proto definition")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.proto", spec.Title)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// CodegenTerraform downloads Terraform resources for a stored spec:
+// provider=aws or kong
+func (h *OpenAPIHandler) CodegenTerraform(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	provider := c.DefaultQuery("provider", codegen.TerraformAWS)
+	if !codegen.ValidTerraformProvider(provider) {
+		SendBadRequest(c, "Unsupported provider: use aws or kong")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.GenerateTerraform(c.Request.Context(), id, provider)
+	if err != nil {
+		SendServiceError(c, err, "Failed to generate terraform")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.tf", spec.Title, provider)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// ExportGateway downloads API gateway configuration for a stored spec:
+// target=kong, aws or google
+func (h *OpenAPIHandler) ExportGateway(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	target := c.Query("target")
+	if !codegen.ValidGateway(target) {
+		SendBadRequest(c, "Unsupported gateway: use kong, aws or google")
+		return
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.ExportGateway(c.Request.Context(), id, target)
+	if err != nil {
+		SendServiceError(c, err, "Failed to export gateway configuration")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.json", spec.Title, target)
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 	c.Data(http.StatusOK, "application/json", data)
 }
+
+// ExportKubernetes downloads Kubernetes manifests for a stored spec:
+// kind=httproute or ingress, routing to service:port in namespace
+func (h *OpenAPIHandler) ExportKubernetes(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	opts := models.ManifestOptions{
+		Kind:      c.DefaultQuery("kind", codegen.ManifestHTTPRoute),
+		Namespace: c.Query("namespace"),
+		Service:   c.Query("service"),
+		Gateway:   c.Query("gateway"),
+	}
+	if !codegen.ValidManifestKind(opts.Kind) {
+		SendBadRequest(c, "Unsupported manifest kind: use httproute or ingress")
+		return
+	}
+
+	if raw := c.Query("port"); raw != "" {
+		port, err := strconv.Atoi(raw)
+		if err != nil || port < 1 || port > 65535 {
+			SendBadRequest(c, "Invalid port")
+			return
+		}
+		opts.Port = port
+	}
+
+	spec, err := h.openAPIService.GetOpenAPISpec(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get OpenAPI specification")
+		return
+	}
+
+	data, err := h.openAPIService.ExportKubernetes(c.Request.Context(), id, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to export Kubernetes manifests")
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s.yaml", spec.Title, opts.Kind)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/yaml", data)
+}
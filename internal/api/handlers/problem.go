@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"postman-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix prefixes the error code to form a problem type URI
+const problemTypePrefix = "urn:postman-api:problem:"
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type     string                   `json:"type"`
+	Title    string                   `json:"title"`
+	Status   int                      `json:"status"`
+	Detail   string                   `json:"detail,omitempty"`
+	Instance string                   `json:"instance,omitempty"`
+	Code     models.ErrorCode         `json:"code,omitempty"`
+	Errors   []models.ValidationIssue `json:"errors,omitempty"`
+}
+
+// WantsProblem reports whether the client prefers problem details to the
+// response envelope, by listing application/problem+json in Accept
+func WantsProblem(c *gin.Context) bool {
+	if c.GetHeader("Accept") == "" {
+		return false
+	}
+	return c.NegotiateFormat(gin.MIMEJSON, ProblemContentType) == ProblemContentType
+}
+
+// NewProblem creates problem details for an error response to the current request
+func NewProblem(c *gin.Context, statusCode int, code models.ErrorCode, detail string) Problem {
+	return Problem{
+		Type:     problemTypePrefix + string(code),
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	}
+}
+
+// SendProblem sends problem details as application/problem+json
+func SendProblem(c *gin.Context, problem Problem) {
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
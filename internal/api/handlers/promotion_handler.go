@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionHandler handles HTTP requests for promoting OpenAPI specifications
+// through environment-of-record labels
+type PromotionHandler struct {
+	promotionService interfaces.SpecPromotionService
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(promotionService interfaces.SpecPromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// ListLabels returns the content of record of a spec at every label
+func (h *PromotionHandler) ListLabels(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	promotions, err := h.promotionService.ListSpecLabels(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list spec labels")
+		return
+	}
+
+	SendSuccess(c, promotions)
+}
+
+// GetLabel returns the content of record of a spec at one label
+func (h *PromotionHandler) GetLabel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	promotion, err := h.promotionService.GetSpecLabel(c.Request.Context(), id, c.Param("label"))
+	if err != nil {
+		SendServiceError(c, err, "Failed to get spec label")
+		return
+	}
+
+	SendSuccess(c, promotion)
+}
+
+// Promote copies a spec to a label from the label before it, or from the
+// approved spec itself for the first label, queueing the label's exports
+func (h *PromotionHandler) Promote(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var body struct {
+		PromotedBy string `json:"promoted_by" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	promotion, err := h.promotionService.PromoteSpec(c.Request.Context(), id, c.Param("label"), body.PromotedBy)
+	if err != nil {
+		SendServiceError(c, err, "Failed to promote spec")
+		return
+	}
+
+	for _, job := range promotion.Jobs {
+		withJobLinks(c, job)
+	}
+
+	SendCreated(c, promotion)
+}
+
+// ListPromotions returns the promotions of a spec with pagination
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	page, pageSize := GetPaginationParams(c)
+
+	promotions, total, err := h.promotionService.ListSpecPromotions(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list spec promotions")
+		return
+	}
+
+	SendPaginated(c, promotions, page, pageSize, models.Total{Count: total})
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"io"
+	"net/http"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,11 +32,11 @@ func (h *RequestHandler) Create(c *gin.Context) {
 	}
 
 	if err := h.requestService.CreateRequest(c.Request.Context(), &request); err != nil {
-		SendInternalError(c, "Failed to create request: "+err.Error())
+		SendServiceError(c, err, "Failed to create request")
 		return
 	}
 
-	SendCreated(c, request)
+	SendCreated(c, withRequestLinks(c, &request))
 }
 
 // Get retrieves a request by ID
@@ -46,23 +49,91 @@ func (h *RequestHandler) Get(c *gin.Context) {
 
 	request, err := h.requestService.GetRequest(c.Request.Context(), id)
 	if err != nil {
-		SendNotFound(c, "Request not found")
+		SendServiceError(c, err, "Failed to get request")
 		return
 	}
 
-	SendSuccess(c, request)
+	SendSuccess(c, withRequestLinks(c, request))
 }
 
-// List returns all requests with pagination
+// ResponseBody streams the body of a saved response; X-Body-Truncated marks
+// bodies cut by the truncation policy
+func (h *RequestHandler) ResponseBody(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		SendBadRequest(c, "Invalid response index")
+		return
+	}
+
+	resp, body, err := h.requestService.OpenResponseBody(c.Request.Context(), id, index)
+	if err != nil {
+		SendServiceError(c, err, "Failed to open saved response")
+		return
+	}
+	defer body.Close()
+
+	sendSavedBody(c, resp.Header, resp.BodyTruncated, body)
+}
+
+// sendSavedBody streams the body of a saved response with the content type
+// it was saved with
+func sendSavedBody(c *gin.Context, headers []models.KeyValuePair, truncated bool, body io.Reader) {
+	contentType := "text/plain; charset=utf-8"
+	for _, header := range headers {
+		if http.CanonicalHeaderKey(header.Key) == "Content-Type" && header.Value != "" {
+			contentType = header.Value
+		}
+	}
+
+	extraHeaders := map[string]string{}
+	if truncated {
+		extraHeaders["X-Body-Truncated"] = "true"
+	}
+	c.DataFromReader(http.StatusOK, -1, contentType, body, extraHeaders)
+}
+
+// List returns all requests with pagination, or with ids=1,5,9 the requests
+// with those IDs in that order
 func (h *RequestHandler) List(c *gin.Context) {
+	if raw, ok := c.GetQuery("ids"); ok {
+		ids, err := parseIDList(raw)
+		if err != nil {
+			SendBadRequest(c, "Invalid ids format")
+			return
+		}
+
+		requests, err := h.requestService.GetRequests(c.Request.Context(), ids)
+		if err != nil {
+			SendServiceError(c, err, "Failed to get requests")
+			return
+		}
+
+		for _, request := range requests {
+			withRequestLinks(c, request)
+		}
+
+		SendSuccess(c, requests)
+		return
+	}
+
 	page, pageSize := GetPaginationParams(c)
 
-	requests, total, err := h.requestService.ListRequests(c.Request.Context(), page, pageSize)
+	requests, total, err := h.requestService.ListRequests(c.Request.Context(), page, pageSize, GetCountMode(c))
 	if err != nil {
-		SendInternalError(c, "Failed to list requests: "+err.Error())
+		SendServiceError(c, err, "Failed to list requests")
 		return
 	}
 
+	for _, request := range requests {
+		withRequestLinks(c, request)
+	}
+
 	SendPaginated(c, requests, page, pageSize, total)
 }
 
@@ -76,12 +147,16 @@ func (h *RequestHandler) ListByCollection(c *gin.Context) {
 
 	page, pageSize := GetPaginationParams(c)
 
-	requests, total, err := h.requestService.ListRequestsByCollection(c.Request.Context(), collectionID, page, pageSize)
+	requests, total, err := h.requestService.ListRequestsByCollection(c.Request.Context(), collectionID, page, pageSize, GetCountMode(c))
 	if err != nil {
-		SendInternalError(c, "Failed to list requests: "+err.Error())
+		SendServiceError(c, err, "Failed to list requests")
 		return
 	}
 
+	for _, request := range requests {
+		withRequestLinks(c, request)
+	}
+
 	SendPaginated(c, requests, page, pageSize, total)
 }
 
@@ -102,7 +177,7 @@ func (h *RequestHandler) ListByCollection(c *gin.Context) {
 // 	request.ID = id
 
 // 	if err := h.requestService.UpdateRequest(c.Request.Context(), &request); err != nil {
-// 		SendInternalError(c, "Failed to update request: "+err.Error())
+// 		SendServiceError(c, err, "Failed to update request")
 // 		return
 // 	}
 
@@ -124,7 +199,7 @@ func (h *RequestHandler) UpdatePayload(c *gin.Context) {
 	}
 
 	if err := h.requestService.UpdateRequestPayload(c.Request.Context(), id, body); err != nil {
-		SendInternalError(c, "Failed to update request payload: "+err.Error())
+		SendServiceError(c, err, "Failed to update request payload")
 		return
 	}
 
@@ -146,13 +221,35 @@ func (h *RequestHandler) UpdateHeaders(c *gin.Context) {
 	}
 
 	if err := h.requestService.UpdateRequestHeaders(c.Request.Context(), id, headers); err != nil {
-		SendInternalError(c, "Failed to update request headers: "+err.Error())
+		SendServiceError(c, err, "Failed to update request headers")
 		return
 	}
 
 	SendSuccess(c, map[string]string{"message": "Request headers updated successfully"})
 }
 
+// UpdateHeaderPresets replaces the header presets attached to a request
+func (h *RequestHandler) UpdateHeaderPresets(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var presetIDs []int64
+	if err := c.ShouldBindJSON(&presetIDs); err != nil {
+		SendBadRequest(c, "Invalid header presets body: "+err.Error())
+		return
+	}
+
+	if err := h.requestService.UpdateRequestHeaderPresets(c.Request.Context(), id, presetIDs); err != nil {
+		SendServiceError(c, err, "Failed to update request header presets")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Request header presets updated successfully"})
+}
+
 // UpdateParams updates only the query parameters of a request
 func (h *RequestHandler) UpdateParams(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -168,13 +265,89 @@ func (h *RequestHandler) UpdateParams(c *gin.Context) {
 	}
 
 	if err := h.requestService.UpdateRequestParams(c.Request.Context(), id, params); err != nil {
-		SendInternalError(c, "Failed to update request params: "+err.Error())
+		SendServiceError(c, err, "Failed to update request params")
 		return
 	}
 
 	SendSuccess(c, map[string]string{"message": "Request parameters updated successfully"})
 }
 
+// UpdateAssertions replaces the declarative response assertions of a request
+func (h *RequestHandler) UpdateAssertions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var assertions []models.Assertion
+	if err := c.ShouldBindJSON(&assertions); err != nil {
+		SendBadRequest(c, "Invalid assertions body: "+err.Error())
+		return
+	}
+
+	if err := h.requestService.UpdateRequestAssertions(c.Request.Context(), id, assertions); err != nil {
+		SendServiceError(c, err, "Failed to update request assertions")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Request assertions updated successfully"})
+}
+
+// UpdateDeprecation flags a request as deprecated, with an optional YYYY-MM-DD sunset date
+func (h *RequestHandler) UpdateDeprecation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var update models.DeprecationUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		SendBadRequest(c, "Invalid deprecation body: "+err.Error())
+		return
+	}
+
+	var sunset *time.Time
+	if update.Sunset != "" {
+		date, err := time.Parse(time.DateOnly, update.Sunset)
+		if err != nil {
+			SendBadRequest(c, "Invalid sunset date, expected YYYY-MM-DD")
+			return
+		}
+		sunset = &date
+	}
+
+	if err := h.requestService.UpdateRequestDeprecation(c.Request.Context(), id, update.Deprecated, sunset); err != nil {
+		SendServiceError(c, err, "Failed to update request deprecation")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Request deprecation updated successfully"})
+}
+
+// UpdateRunSettings replaces the overrides of how the runner sends a request
+func (h *RequestHandler) UpdateRunSettings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var settings models.RunSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		SendBadRequest(c, "Invalid run settings body: "+err.Error())
+		return
+	}
+
+	if err := h.requestService.UpdateRequestRunSettings(c.Request.Context(), id, settings); err != nil {
+		SendServiceError(c, err, "Failed to update request run settings")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Request run settings updated successfully"})
+}
+
 // Delete removes a request
 func (h *RequestHandler) Delete(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -184,14 +357,15 @@ func (h *RequestHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.requestService.DeleteRequest(c.Request.Context(), id); err != nil {
-		SendInternalError(c, "Failed to delete request: "+err.Error())
+		SendServiceError(c, err, "Failed to delete request")
 		return
 	}
 
 	SendSuccess(c, map[string]string{"message": "Request deleted successfully"})
 }
 
-// Clone creates a copy of an existing request
+// Clone creates a copy of an existing request, optionally into
+// target_collection_id and the folder at folder_path
 func (h *RequestHandler) Clone(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -200,7 +374,9 @@ func (h *RequestHandler) Clone(c *gin.Context) {
 	}
 
 	var body struct {
-		Name string `json:"name" binding:"required"`
+		Name               string  `json:"name" binding:"required"`
+		TargetCollectionID int64   `json:"target_collection_id"`
+		FolderPath         *string `json:"folder_path"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -208,11 +384,39 @@ func (h *RequestHandler) Clone(c *gin.Context) {
 		return
 	}
 
-	newID, err := h.requestService.CloneRequest(c.Request.Context(), id, body.Name)
+	newID, err := h.requestService.CloneRequest(c.Request.Context(), id, body.Name, body.TargetCollectionID, body.FolderPath)
 	if err != nil {
-		SendInternalError(c, "Failed to clone request: "+err.Error())
+		SendServiceError(c, err, "Failed to clone request")
 		return
 	}
 
 	SendCreated(c, map[string]int64{"id": newID})
 }
+
+// Move moves a request to target_collection_id, optionally into the folder
+// at folder_path
+func (h *RequestHandler) Move(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var body struct {
+		TargetCollectionID int64   `json:"target_collection_id" binding:"required"`
+		FolderPath         *string `json:"folder_path"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid request body, target_collection_id is required")
+		return
+	}
+
+	request, err := h.requestService.MoveRequest(c.Request.Context(), id, body.TargetCollectionID, body.FolderPath)
+	if err != nil {
+		SendServiceError(c, err, "Failed to move request")
+		return
+	}
+
+	SendSuccess(c, withRequestLinks(c, request))
+}
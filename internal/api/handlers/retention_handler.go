@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetentionHandler handles HTTP requests for retention reports and runs
+type RetentionHandler struct {
+	retentionService interfaces.RetentionService
+	jobService       interfaces.JobService
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService interfaces.RetentionService, jobService interfaces.JobService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+		jobService:       jobService,
+	}
+}
+
+// Report returns a dry run of the retention policy
+func (h *RetentionHandler) Report(c *gin.Context) {
+	report, err := h.retentionService.Report(c.Request.Context())
+	if err != nil {
+		SendServiceError(c, err, "Failed to build retention report")
+		return
+	}
+
+	SendSuccess(c, report)
+}
+
+// Enforce purges the items past their retention now instead of waiting for
+// the schedule; with async=true the run is queued as a job
+func (h *RetentionHandler) Enforce(c *gin.Context) {
+	if async, _ := strconv.ParseBool(c.Query("async")); async {
+		job, err := h.jobService.Enqueue(c.Request.Context(), models.JobRetentionEnforce, nil)
+		if err != nil {
+			SendServiceError(c, err, "Failed to queue retention run")
+			return
+		}
+
+		SendAccepted(c, job)
+		return
+	}
+
+	report, err := h.retentionService.Enforce(c.Request.Context())
+	if err != nil {
+		SendServiceError(c, err, "Failed to enforce retention policy")
+		return
+	}
+
+	SendSuccess(c, report)
+}
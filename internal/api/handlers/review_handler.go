@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler handles HTTP requests for the review of OpenAPI specifications
+type ReviewHandler struct {
+	reviewService interfaces.SpecReviewService
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService interfaces.SpecReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// Get returns the review status of a spec with its decisions
+func (h *ReviewHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	review, err := h.reviewService.GetSpecReview(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get spec review")
+		return
+	}
+
+	SendSuccess(c, review)
+}
+
+// Decide records a decision on a spec: submit, approve, request_changes or publish
+func (h *ReviewHandler) Decide(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var body struct {
+		Decision string `json:"decision" binding:"required"`
+		Reviewer string `json:"reviewer" binding:"required"`
+		Comment  string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	review, err := h.reviewService.DecideSpecReview(c.Request.Context(), &models.SpecReviewDecision{
+		SpecID:   id,
+		Decision: body.Decision,
+		Reviewer: body.Reviewer,
+		Comment:  body.Comment,
+	})
+	if err != nil {
+		SendServiceError(c, err, "Failed to record review decision")
+		return
+	}
+
+	SendSuccess(c, review)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadTestStreamInterval is how often a streamed load test sends its stats
+const loadTestStreamInterval = time.Second
+
+// Load test stream events: its stats while it runs, then the finished load
+// test or the error envelope
+const (
+	loadTestEventStats = "stats"
+	loadTestEventDone  = "done"
+	loadTestEventError = "error"
+)
+
+// RunnerHandler handles HTTP requests for sending stored requests and running collections
+type RunnerHandler struct {
+	runnerService interfaces.RunnerService
+}
+
+// NewRunnerHandler creates a new runner handler
+func NewRunnerHandler(runnerService interfaces.RunnerService) *RunnerHandler {
+	return &RunnerHandler{
+		runnerService: runnerService,
+	}
+}
+
+// Execute sends a request, resolved with the variables of environment_id
+// when given and bounded by timeout_ms unless the request has a timeout of
+// its own, and returns the response with its assertion results
+func (h *RunnerHandler) Execute(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var opts models.ExecuteOptions
+	if raw := c.Query("environment_id"); raw != "" {
+		if opts.EnvironmentID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			SendBadRequest(c, "Invalid environment_id format")
+			return
+		}
+	}
+	if raw := c.Query("header_presets"); raw != "" {
+		if opts.HeaderPresets, err = parseIDList(raw); err != nil {
+			SendBadRequest(c, "Invalid header_presets format")
+			return
+		}
+	}
+	if raw := c.Query("timeout_ms"); raw != "" {
+		if opts.TimeoutMs, err = strconv.Atoi(raw); err != nil {
+			SendBadRequest(c, "Invalid timeout_ms format")
+			return
+		}
+	}
+
+	result, err := h.runnerService.ExecuteRequest(c.Request.Context(), id, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to execute request")
+		return
+	}
+
+	SendSuccess(c, result)
+}
+
+// RunCollection queues a run of every request of a collection in folder
+// order and answers with the queued run; its report fills in as it runs
+func (h *RunnerHandler) RunCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	// The body is optional; an empty one runs with the defaults
+	var opts models.RunOptions
+	if err := c.ShouldBindJSON(&opts); err != nil && !errors.Is(err, io.EOF) {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	run, err := h.runnerService.RunCollection(c.Request.Context(), id, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to run collection")
+		return
+	}
+
+	c.Header("Location", apiLink(c, "/runs/%d", run.ID))
+	SendJSON(c, http.StatusAccepted, SuccessResponse(withRunLinks(c, run)))
+}
+
+// GetRun retrieves a run report by ID
+func (h *RunnerHandler) GetRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	run, err := h.runnerService.GetRun(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get run")
+		return
+	}
+
+	SendSuccess(c, withRunLinks(c, run))
+}
+
+// GetRunMetrics returns the chart data of a finished run: the latency of
+// each request over the run and the status codes it got
+func (h *RunnerHandler) GetRunMetrics(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	metrics, err := h.runnerService.GetRunMetrics(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get run metrics")
+		return
+	}
+
+	SendSuccess(c, metrics)
+}
+
+// LoadTestCollection queues a load test sending the requests of a
+// collection in turn at rps requests a second for duration_ms, and answers
+// with the queued load test
+func (h *RunnerHandler) LoadTestCollection(c *gin.Context) {
+	h.loadTest(c, h.runnerService.LoadTestCollection)
+}
+
+// LoadTestRequest queues a load test sending a single request at rps
+// requests a second for duration_ms, and answers with the queued load test
+func (h *RunnerHandler) LoadTestRequest(c *gin.Context) {
+	h.loadTest(c, h.runnerService.LoadTestRequest)
+}
+
+// loadTest queues a load test of the collection or request of the path with
+// queue
+func (h *RunnerHandler) loadTest(c *gin.Context, queue func(ctx context.Context, id int64, opts models.LoadTestOptions) (*models.LoadTest, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var opts models.LoadTestOptions
+	if err := c.ShouldBindJSON(&opts); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	test, err := queue(c.Request.Context(), id, opts)
+	if err != nil {
+		SendServiceError(c, err, "Failed to start load test")
+		return
+	}
+
+	c.Header("Location", apiLink(c, "/load-tests/%d", test.ID))
+	SendJSON(c, http.StatusAccepted, SuccessResponse(withLoadTestLinks(c, test)))
+}
+
+// GetLoadTest retrieves a load test with its stats so far. Clients
+// accepting text/event-stream get it as a "stats" event every second while
+// it runs, then a "done" event once it finishes.
+func (h *RunnerHandler) GetLoadTest(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	test, err := h.runnerService.GetLoadTest(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get load test")
+		return
+	}
+
+	if !WantsEventStream(c) {
+		SendSuccess(c, withLoadTestLinks(c, test))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// The stream lasts as long as the load test, past the write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	ticker := time.NewTicker(loadTestStreamInterval)
+	defer ticker.Stop()
+	for test.Status == models.LoadTestQueued || test.Status == models.LoadTestRunning {
+		c.SSEvent(loadTestEventStats, withLoadTestLinks(c, test))
+		c.Writer.Flush()
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		if test, err = h.runnerService.GetLoadTest(c.Request.Context(), id); err != nil {
+			_, code, message, fields := describeError(c, err, "Failed to get load test")
+			response := ErrorResponse(code, message)
+			response.Fields = fields
+			c.SSEvent(loadTestEventError, response)
+			return
+		}
+	}
+
+	c.SSEvent(loadTestEventDone, withLoadTestLinks(c, test))
+}
+
+// GetSnapshot returns the response snapshot of a request, the one snapshot
+// runs diff its responses against
+func (h *RunnerHandler) GetSnapshot(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	snapshot, err := h.runnerService.GetSnapshot(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get response snapshot")
+		return
+	}
+
+	SendSuccess(c, snapshot)
+}
+
+// ListRuns returns the runs of a collection with pagination, newest first
+func (h *RunnerHandler) ListRuns(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	page, pageSize := GetPaginationParams(c)
+
+	runs, total, err := h.runnerService.ListRuns(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list runs")
+		return
+	}
+
+	for _, run := range runs {
+		withRunLinks(c, run)
+	}
+
+	SendPaginated(c, runs, page, pageSize, models.Total{Count: total})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScannerHandler handles HTTP requests for secret and PII scans
+type ScannerHandler struct {
+	scannerService interfaces.ScannerService
+}
+
+// NewScannerHandler creates a new scanner handler
+func NewScannerHandler(scannerService interfaces.ScannerService) *ScannerHandler {
+	return &ScannerHandler{
+		scannerService: scannerService,
+	}
+}
+
+// ScanCollection returns a findings report for a collection
+func (h *ScannerHandler) ScanCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	report, err := h.scannerService.ScanCollection(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to scan collection")
+		return
+	}
+
+	SendSuccess(c, report)
+}
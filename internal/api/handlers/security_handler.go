@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHandler handles HTTP requests exchanging targets and findings with security scanners
+type SecurityHandler struct {
+	securityService interfaces.SecurityService
+}
+
+// NewSecurityHandler creates a new security handler
+func NewSecurityHandler(securityService interfaces.SecurityService) *SecurityHandler {
+	return &SecurityHandler{
+		securityService: securityService,
+	}
+}
+
+// ExportTargets downloads a collection as scanner targets, a ZAP automation
+// plan for format=zap or a Burp REST API scan for format=burp
+func (h *SecurityHandler) ExportTargets(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var environmentID int64
+	if raw := c.Query("environment_id"); raw != "" {
+		if environmentID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			SendBadRequest(c, "Invalid environment_id format")
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", models.SecurityTargetZAP)
+	data, err := h.securityService.ExportTargets(c.Request.Context(), id, environmentID, format)
+	if err != nil {
+		SendServiceError(c, err, "Failed to export security targets")
+		return
+	}
+
+	filename := fmt.Sprintf("collection-%d.%s.json", id, format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// IngestFindings stores findings a scanner reports back for a collection
+func (h *SecurityHandler) IngestFindings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	var payload models.SecurityFindingsPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.securityService.IngestFindings(c.Request.Context(), id, &payload)
+	if err != nil {
+		SendServiceError(c, err, "Failed to ingest security findings")
+		return
+	}
+
+	SendCreated(c, result)
+}
+
+// ListFindings returns the scanner findings of a collection with pagination
+func (h *SecurityHandler) ListFindings(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	page, pageSize := GetPaginationParams(c)
+
+	findings, total, err := h.securityService.ListFindings(c.Request.Context(), id, page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list security findings")
+		return
+	}
+
+	SendPaginated(c, findings, page, pageSize, models.Total{Count: total})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpecSourceHandler handles HTTP requests for polled spec sources
+type SpecSourceHandler struct {
+	specSourceService interfaces.SpecSourceService
+}
+
+// NewSpecSourceHandler creates a new spec source handler
+func NewSpecSourceHandler(specSourceService interfaces.SpecSourceService) *SpecSourceHandler {
+	return &SpecSourceHandler{
+		specSourceService: specSourceService,
+	}
+}
+
+// Create registers a new spec source, enabled unless stated otherwise;
+// credentials are accepted but never returned
+func (h *SpecSourceHandler) Create(c *gin.Context) {
+	input := models.SpecSourceInput{SpecSource: models.SpecSource{Enabled: true}}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	source := input.SpecSource
+	source.Auth = input.Auth
+
+	if err := h.specSourceService.CreateSpecSource(c.Request.Context(), &source); err != nil {
+		SendServiceError(c, err, "Failed to create spec source")
+		return
+	}
+
+	SendCreated(c, source)
+}
+
+// Get retrieves a spec source by ID
+func (h *SpecSourceHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	source, err := h.specSourceService.GetSpecSource(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to get spec source")
+		return
+	}
+
+	SendSuccess(c, source)
+}
+
+// List returns all spec sources with pagination
+func (h *SpecSourceHandler) List(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	sources, total, err := h.specSourceService.ListSpecSources(c.Request.Context(), page, pageSize)
+	if err != nil {
+		SendServiceError(c, err, "Failed to list spec sources")
+		return
+	}
+
+	SendPaginated(c, sources, page, pageSize, models.Total{Count: total})
+}
+
+// Update updates an existing spec source; omitting auth keeps the stored credentials
+func (h *SpecSourceHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	input := models.SpecSourceInput{SpecSource: models.SpecSource{Enabled: true}}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		SendBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	source := input.SpecSource
+	source.Auth = input.Auth
+
+	source.ID = id
+
+	if err := h.specSourceService.UpdateSpecSource(c.Request.Context(), &source); err != nil {
+		SendServiceError(c, err, "Failed to update spec source")
+		return
+	}
+
+	SendSuccess(c, source)
+}
+
+// Delete removes a spec source
+func (h *SpecSourceHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	if err := h.specSourceService.DeleteSpecSource(c.Request.Context(), id); err != nil {
+		SendServiceError(c, err, "Failed to delete spec source")
+		return
+	}
+
+	SendSuccess(c, map[string]string{"message": "Spec source deleted successfully"})
+}
+
+// Refresh checks a spec source immediately instead of waiting for the poller
+func (h *SpecSourceHandler) Refresh(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SendBadRequest(c, "Invalid ID format")
+		return
+	}
+
+	result, err := h.specSourceService.RefreshSpecSource(c.Request.Context(), id)
+	if err != nil {
+		SendServiceError(c, err, "Failed to refresh spec source")
+		return
+	}
+
+	SendSuccess(c, result)
+}
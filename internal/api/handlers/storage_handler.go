@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"postman-api/internal/interfaces"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultStorageReportCollections is how many collections a storage report lists by default
+const defaultStorageReportCollections = 50
+
+// StorageHandler handles HTTP requests for storage usage reports
+type StorageHandler struct {
+	storageService interfaces.StorageService
+}
+
+// NewStorageHandler creates a new storage handler
+func NewStorageHandler(storageService interfaces.StorageService) *StorageHandler {
+	return &StorageHandler{
+		storageService: storageService,
+	}
+}
+
+// Report returns the storage footprint by table, team and collection,
+// listing the largest limit collections
+func (h *StorageHandler) Report(c *gin.Context) {
+	limit := defaultStorageReportCollections
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil {
+			SendBadRequest(c, "Invalid limit format")
+			return
+		}
+	}
+
+	report, err := h.storageService.StorageReport(c.Request.Context(), limit)
+	if err != nil {
+		SendServiceError(c, err, "Failed to build storage report")
+		return
+	}
+
+	SendSuccess(c, report)
+}
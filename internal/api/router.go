@@ -1,8 +1,11 @@
 package api
 
 import (
+	"net/http"
 	"postman-api/internal/api/handlers"
+	"postman-api/internal/config"
 	"postman-api/internal/interfaces"
+	"postman-api/internal/version"
 
 	"time"
 
@@ -11,22 +14,112 @@ import (
 )
 
 type Router struct {
-	engine            *gin.Engine
-	collectionHandler *handlers.CollectionHandler
-	requestHandler    *handlers.RequestHandler
-	openAPIHandler    *handlers.OpenAPIHandler
+	engine             *gin.Engine
+	config             *config.Config
+	collectionHandler  *handlers.CollectionHandler
+	requestHandler     *handlers.RequestHandler
+	openAPIHandler     *handlers.OpenAPIHandler
+	scannerHandler     *handlers.ScannerHandler
+	specSourceHandler  *handlers.SpecSourceHandler
+	hookHandler        *handlers.HookHandler
+	healthHandler      *handlers.HealthHandler
+	attachmentHandler  *handlers.AttachmentHandler
+	environmentHandler *handlers.EnvironmentHandler
+	deprecationHandler *handlers.DeprecationHandler
+	inventoryHandler   *handlers.InventoryHandler
+	catalogHandler     *handlers.CatalogHandler
+	lintHandler        *handlers.LintHandler
+	retentionHandler   *handlers.RetentionHandler
+	flattenHandler     *handlers.FlattenHandler
+	securityHandler    *handlers.SecurityHandler
+	contractHandler    *handlers.ContractTestHandler
+	conversionHandler  *handlers.ConversionHandler
+	jobHandler         *handlers.JobHandler
+	runnerHandler      *handlers.RunnerHandler
+	storageHandler     *handlers.StorageHandler
+	exportHandler      *handlers.ExportHandler
+	folderHandler      *handlers.FolderHandler
+	exampleHandler     *handlers.ExampleHandler
+	presetHandler      *handlers.HeaderPresetHandler
+	globalHandler      *handlers.GlobalVariableHandler
+	migrationHandler   *handlers.MigrationHandler
+	formatHandler      *handlers.FormatHandler
+	docsHandler        *handlers.DocsHandler
+	reviewHandler      *handlers.ReviewHandler
+	digestHandler      *handlers.DigestHandler
+	promotionHandler   *handlers.PromotionHandler
 }
 
 func NewRouter(
+	cfg *config.Config,
 	collectionService interfaces.CollectionService,
 	requestService interfaces.RequestService,
 	openAPIService interfaces.OpenAPIService,
+	scannerService interfaces.ScannerService,
+	specSourceService interfaces.SpecSourceService,
+	importHookService interfaces.ImportHookService,
+	healthService interfaces.HealthService,
+	attachmentService interfaces.AttachmentService,
+	environmentService interfaces.EnvironmentService,
+	deprecationService interfaces.DeprecationService,
+	inventoryService interfaces.InventoryService,
+	catalogService interfaces.CatalogService,
+	lintService interfaces.LintService,
+	retentionService interfaces.RetentionService,
+	flattenService interfaces.FlattenService,
+	securityService interfaces.SecurityService,
+	contractTestService interfaces.ContractTestService,
+	conversionService interfaces.ConversionService,
+	jobService interfaces.JobService,
+	runnerService interfaces.RunnerService,
+	storageService interfaces.StorageService,
+	signingService interfaces.SigningService,
+	folderService interfaces.FolderService,
+	bulkImportService interfaces.BulkImportService,
+	exampleService interfaces.ExampleService,
+	headerPresetService interfaces.HeaderPresetService,
+	globalVariableService interfaces.GlobalVariableService,
+	converters interfaces.ConverterRegistry,
+	docsService interfaces.DocsService,
+	specReviewService interfaces.SpecReviewService,
+	digestService interfaces.DigestService,
+	specPromotionService interfaces.SpecPromotionService,
 ) *Router {
 	return &Router{
-		engine:            gin.Default(),
-		collectionHandler: handlers.NewCollectionHandler(collectionService, openAPIService),
-		requestHandler:    handlers.NewRequestHandler(requestService),
-		openAPIHandler:    handlers.NewOpenAPIHandler(openAPIService),
+		engine:             gin.Default(),
+		config:             cfg,
+		collectionHandler:  handlers.NewCollectionHandler(collectionService, openAPIService, signingService, bulkImportService, converters),
+		requestHandler:     handlers.NewRequestHandler(requestService),
+		openAPIHandler:     handlers.NewOpenAPIHandler(openAPIService, signingService),
+		scannerHandler:     handlers.NewScannerHandler(scannerService),
+		specSourceHandler:  handlers.NewSpecSourceHandler(specSourceService),
+		hookHandler:        handlers.NewHookHandler(importHookService, jobService, cfg.Hooks.ImportSecret),
+		healthHandler:      handlers.NewHealthHandler(healthService),
+		attachmentHandler:  handlers.NewAttachmentHandler(attachmentService),
+		environmentHandler: handlers.NewEnvironmentHandler(environmentService),
+		deprecationHandler: handlers.NewDeprecationHandler(deprecationService),
+		inventoryHandler:   handlers.NewInventoryHandler(inventoryService),
+		catalogHandler:     handlers.NewCatalogHandler(catalogService),
+		lintHandler:        handlers.NewLintHandler(lintService),
+		retentionHandler:   handlers.NewRetentionHandler(retentionService, jobService),
+		flattenHandler:     handlers.NewFlattenHandler(flattenService),
+		securityHandler:    handlers.NewSecurityHandler(securityService),
+		contractHandler:    handlers.NewContractTestHandler(contractTestService),
+		conversionHandler:  handlers.NewConversionHandler(conversionService),
+		jobHandler:         handlers.NewJobHandler(jobService),
+		runnerHandler:      handlers.NewRunnerHandler(runnerService),
+		storageHandler:     handlers.NewStorageHandler(storageService),
+		exportHandler:      handlers.NewExportHandler(signingService),
+		folderHandler:      handlers.NewFolderHandler(folderService),
+		exampleHandler:     handlers.NewExampleHandler(exampleService),
+		presetHandler:      handlers.NewHeaderPresetHandler(headerPresetService),
+		globalHandler:      handlers.NewGlobalVariableHandler(globalVariableService),
+		migrationHandler:   handlers.NewMigrationHandler(jobService),
+		formatHandler:      handlers.NewFormatHandler(converters),
+		docsHandler:        handlers.NewDocsHandler(docsService),
+		reviewHandler:      handlers.NewReviewHandler(specReviewService),
+		digestHandler:      handlers.NewDigestHandler(digestService),
+		promotionHandler:   handlers.NewPromotionHandler(specPromotionService),
 	}
 }
 
@@ -40,12 +133,38 @@ func (r *Router) Setup() *gin.Engine {
 		MaxAge:           12 * time.Hour,
 	}))
 
+	r.engine.Use(func(c *gin.Context) {
+		c.Header("Server", version.ServerHeader())
+		c.Next()
+	})
+
+	r.engine.Use(handlers.DegradedWarning())
+	r.engine.Use(handlers.Pagination(r.config.Server.DefaultPageSize, r.config.Server.MaxPageSize))
+
+	// Unknown routes answer with the same error body as the API
+	r.engine.NoRoute(func(c *gin.Context) {
+		handlers.SendNotFound(c, "Route not found")
+	})
+
+	// Every route is served under the configured base path, and links in
+	// responses point there
+	root := r.engine.Group(r.config.Server.BasePath)
+	root.Use(handlers.LinkBase(r.config.Server.ExternalURL, r.config.Server.BasePath))
+
 	// Health check endpoint
-	r.engine.GET("/health", func(c *gin.Context) {
+	root.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	api := r.engine.Group("/api/v1")
+	// Readiness endpoint: database reachability and circuit breaker state
+	root.GET("/readyz", r.healthHandler.Ready)
+
+	// Build info endpoint
+	root.GET("/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, version.Get(r.config.Server.Features))
+	})
+
+	api := root.Group(handlers.APIPrefix)
 	{
 		// Collection endpoints
 		collections := api.Group("/postman")
@@ -56,7 +175,37 @@ func (r *Router) Setup() *gin.Engine {
 			collections.PUT("/:id", r.collectionHandler.Update)
 			collections.DELETE("/:id", r.collectionHandler.Delete)
 			collections.POST("/import", r.collectionHandler.Import)
+			collections.POST("/import/workspace", r.collectionHandler.ImportWorkspace)
+			collections.POST("/validate", r.collectionHandler.Validate)
+			collections.POST("/archive", r.collectionHandler.BulkArchive)
+			collections.POST("/unarchive", r.collectionHandler.BulkUnarchive)
+			collections.POST("/:id/archive", r.collectionHandler.Archive)
+			collections.POST("/:id/unarchive", r.collectionHandler.Unarchive)
 			collections.GET("/:id/export", r.collectionHandler.Export)
+			collections.GET("/:id/scan", r.scannerHandler.ScanCollection)
+			collections.GET("/:id/lint", r.lintHandler.LintCollection)
+			collections.GET("/:id/flatten", r.flattenHandler.FlattenCollection)
+			collections.GET("/:id/docs", r.docsHandler.CollectionDocs)
+			collections.GET("/:id/security/targets", r.securityHandler.ExportTargets)
+			collections.GET("/:id/security/findings", r.securityHandler.ListFindings)
+			collections.POST("/:id/security/findings", r.securityHandler.IngestFindings)
+			collections.GET("/:id/items", r.collectionHandler.ListItems)
+			collections.POST("/:id/items", r.collectionHandler.AddItem)
+			collections.PUT("/:id/items/:itemId", r.collectionHandler.UpdateItem)
+			collections.DELETE("/:id/items/:itemId", r.collectionHandler.RemoveItem)
+			collections.GET("/:id/folders", r.folderHandler.List)
+			collections.POST("/:id/folders", r.folderHandler.Create)
+			collections.GET("/:id/folders/:folderId", r.folderHandler.Get)
+			collections.PUT("/:id/folders/:folderId", r.folderHandler.Update)
+			collections.DELETE("/:id/folders/:folderId", r.folderHandler.Delete)
+			collections.PUT("/:id/folders/rename", r.collectionHandler.RenameFolder)
+			collections.PUT("/:id/ownership", r.catalogHandler.SetCollectionOwnership)
+			collections.POST("/:id/run", r.runnerHandler.RunCollection)
+			collections.POST("/:id/sync-from-spec", r.conversionHandler.SyncFromSpec)
+			collections.GET("/:id/runs", r.runnerHandler.ListRuns)
+			collections.POST("/:id/load-test", r.runnerHandler.LoadTestCollection)
+			collections.GET("/:id/revisions", r.collectionHandler.ListRevisions)
+			collections.POST("/:id/revisions/:rev/rollback", r.collectionHandler.Rollback)
 		}
 
 		// Request endpoints
@@ -65,11 +214,27 @@ func (r *Router) Setup() *gin.Engine {
 			requests.POST("", r.requestHandler.Create)
 			requests.GET("", r.requestHandler.List)
 			requests.GET("/:id", r.requestHandler.Get)
+			requests.GET("/:id/responses/:index/body", r.requestHandler.ResponseBody)
+			requests.GET("/:id/examples", r.exampleHandler.List)
+			requests.POST("/:id/examples", r.exampleHandler.Create)
+			requests.GET("/:id/examples/:exampleId", r.exampleHandler.Get)
+			requests.PUT("/:id/examples/:exampleId", r.exampleHandler.Update)
+			requests.DELETE("/:id/examples/:exampleId", r.exampleHandler.Delete)
+			requests.GET("/:id/examples/:exampleId/body", r.exampleHandler.Body)
 			requests.DELETE("/:id", r.requestHandler.Delete)
 			requests.PUT("/:id/payload", r.requestHandler.UpdatePayload)
 			requests.PUT("/:id/headers", r.requestHandler.UpdateHeaders)
+			requests.PUT("/:id/header-presets", r.requestHandler.UpdateHeaderPresets)
 			requests.PUT("/:id/params", r.requestHandler.UpdateParams)
+			requests.PUT("/:id/assertions", r.requestHandler.UpdateAssertions)
+			requests.PUT("/:id/deprecation", r.requestHandler.UpdateDeprecation)
+			requests.PUT("/:id/run-settings", r.requestHandler.UpdateRunSettings)
 			requests.POST("/:id/clone", r.requestHandler.Clone)
+			requests.POST("/:id/move", r.requestHandler.Move)
+			requests.POST("/:id/execute", r.runnerHandler.Execute)
+			requests.GET("/:id/snapshot", r.runnerHandler.GetSnapshot)
+			requests.POST("/:id/load-test", r.runnerHandler.LoadTestRequest)
+			requests.POST("/:id/resolve", r.flattenHandler.ResolveRequest)
 		}
 
 		api.GET("/postman/:id/requests", r.requestHandler.ListByCollection)
@@ -78,11 +243,148 @@ func (r *Router) Setup() *gin.Engine {
 		openapi := api.Group("/openapi")
 		{
 			openapi.GET("", r.openAPIHandler.List)
+			openapi.GET("/search", r.openAPIHandler.Search)
 			openapi.GET("/:id", r.openAPIHandler.Get)
 			openapi.PUT("/:id", r.openAPIHandler.Update)
 			openapi.DELETE("/:id", r.openAPIHandler.Delete)
+			openapi.GET("/:id/revisions", r.openAPIHandler.ListRevisions)
+			openapi.GET("/:id/diff", r.openAPIHandler.Diff)
+			openapi.POST("/:id/compare", r.openAPIHandler.Compare)
+			openapi.GET("/:id/review", r.reviewHandler.Get)
+			openapi.POST("/:id/review", r.reviewHandler.Decide)
+			openapi.GET("/:id/labels", r.promotionHandler.ListLabels)
+			openapi.GET("/:id/labels/:label", r.promotionHandler.GetLabel)
+			openapi.POST("/:id/labels/:label/promote", r.promotionHandler.Promote)
+			openapi.GET("/:id/promotions", r.promotionHandler.ListPromotions)
 			openapi.POST("/import", r.openAPIHandler.Import)
+			openapi.POST("/validate", r.openAPIHandler.Validate)
 			openapi.GET("/:id/export", r.openAPIHandler.Export)
+			openapi.GET("/:id/codegen/typescript", r.openAPIHandler.CodegenTypeScript)
+			openapi.GET("/:id/codegen/go", r.openAPIHandler.CodegenGo)
+			openapi.GET("/:id/codegen/server", r.openAPIHandler.CodegenServer)
+			openapi.GET("/:id/codegen/proto", r.openAPIHandler.CodegenProto)
+			openapi.GET("/:id/codegen/terraform", r.openAPIHandler.CodegenTerraform)
+			openapi.POST("/:id/contract-tests", r.contractHandler.Generate)
+			openapi.POST("/:id/convert-to-collection", r.conversionHandler.ConvertToCollection)
+			openapi.GET("/:id/gateway", r.openAPIHandler.ExportGateway)
+			openapi.GET("/:id/kubernetes", r.openAPIHandler.ExportKubernetes)
+			openapi.PUT("/:id/ownership", r.catalogHandler.SetSpecOwnership)
+		}
+
+		// Polled OpenAPI spec source endpoints
+		specSources := api.Group("/spec-sources")
+		{
+			specSources.POST("", r.specSourceHandler.Create)
+			specSources.GET("", r.specSourceHandler.List)
+			specSources.GET("/:id", r.specSourceHandler.Get)
+			specSources.PUT("/:id", r.specSourceHandler.Update)
+			specSources.DELETE("/:id", r.specSourceHandler.Delete)
+			specSources.POST("/:id/refresh", r.specSourceHandler.Refresh)
+		}
+
+		// Digest subscriptions, sent the changes to collections and specs
+		digests := api.Group("/digests")
+		{
+			digests.POST("", r.digestHandler.Create)
+			digests.GET("", r.digestHandler.List)
+			digests.GET("/:id", r.digestHandler.Get)
+			digests.PUT("/:id", r.digestHandler.Update)
+			digests.DELETE("/:id", r.digestHandler.Delete)
+			digests.GET("/:id/preview", r.digestHandler.Preview)
+			digests.POST("/:id/send", r.digestHandler.Send)
+		}
+
+		// Attachment endpoints for form-data and file request bodies
+		attachments := api.Group("/attachments")
+		{
+			attachments.POST("", r.attachmentHandler.Upload)
+			attachments.GET("/:id", r.attachmentHandler.Get)
+			attachments.GET("/:id/content", r.attachmentHandler.Download)
+			attachments.DELETE("/:id", r.attachmentHandler.Delete)
+		}
+
+		// Deprecated operations and requests across the catalog
+		api.GET("/deprecations", r.deprecationHandler.List)
+
+		// Deduplicated inventory of every endpoint across collections and specs
+		api.GET("/inventory", r.inventoryHandler.List)
+		api.PUT("/inventory/ownership", r.catalogHandler.SetEndpointOwnership)
+
+		// Owned collections, specs and endpoints
+		api.GET("/catalog", r.catalogHandler.List)
+
+		// Retention policy dry run and on-demand enforcement
+		api.GET("/retention/report", r.retentionHandler.Report)
+		api.POST("/retention/enforce", r.retentionHandler.Enforce)
+
+		// Verification of signed collection and spec exports
+		api.POST("/exports/verify", r.exportHandler.Verify)
+		api.GET("/exports/signing-key", r.exportHandler.SigningKey)
+
+		// Formats collections are imported from and exported to
+		api.GET("/formats", r.formatHandler.List)
+
+		// Storage footprint by table, team and collection
+		api.GET("/admin/storage", r.storageHandler.Report)
+
+		// Backfill of collections stored before folders and requests had rows
+		api.POST("/admin/migrations/items", r.migrationHandler.MigrateItems)
+
+		// Collection run reports
+		api.GET("/runs/:id", r.runnerHandler.GetRun)
+		api.GET("/runs/:id/metrics", r.runnerHandler.GetRunMetrics)
+
+		// Load tests, streamed as server-sent events while they run
+		api.GET("/load-tests/:id", r.runnerHandler.GetLoadTest)
+
+		// Collection revision snapshots
+		api.GET("/revisions/:rev", r.collectionHandler.GetRevision)
+
+		// Background job queue status and management
+		jobs := api.Group("/jobs")
+		{
+			jobs.GET("", r.jobHandler.List)
+			jobs.GET("/:id", r.jobHandler.Get)
+			jobs.POST("/:id/retry", r.jobHandler.Retry)
+			jobs.DELETE("/:id", r.jobHandler.Cancel)
+		}
+
+		// Environment endpoints
+		environments := api.Group("/environments")
+		{
+			environments.POST("", r.environmentHandler.Create)
+			environments.GET("", r.environmentHandler.List)
+			environments.GET("/:id", r.environmentHandler.Get)
+			environments.PUT("/:id", r.environmentHandler.Update)
+			environments.DELETE("/:id", r.environmentHandler.Delete)
+			environments.POST("/import", r.environmentHandler.Import)
+			environments.GET("/:id/export", r.environmentHandler.Export)
+		}
+
+		// Header preset endpoints
+		presets := api.Group("/header-presets")
+		{
+			presets.POST("", r.presetHandler.Create)
+			presets.GET("", r.presetHandler.List)
+			presets.GET("/:id", r.presetHandler.Get)
+			presets.PUT("/:id", r.presetHandler.Update)
+			presets.DELETE("/:id", r.presetHandler.Delete)
+		}
+
+		// Global variable endpoints
+		globals := api.Group("/globals")
+		{
+			globals.GET("", r.globalHandler.List)
+			globals.GET("/:key", r.globalHandler.Get)
+			globals.PUT("/:key", r.globalHandler.Set)
+			globals.DELETE("/:key", r.globalHandler.Delete)
+			globals.GET("/:key/history", r.globalHandler.History)
+		}
+
+		// Inbound webhook endpoints
+		hooks := api.Group("/hooks")
+		{
+			hooks.POST("/import", r.hookHandler.Import)
 		}
 	}
 
@@ -0,0 +1,172 @@
+package assertions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"postman-api/internal/models"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Response is the observed outcome of a request that assertions are checked against
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Latency    time.Duration
+}
+
+// Validate checks that every assertion is well formed
+func Validate(list []models.Assertion) error {
+	for i, a := range list {
+		if err := validate(a); err != nil {
+			return fmt.Errorf("assertion %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validate(a models.Assertion) error {
+	switch a.Type {
+	case models.AssertionStatusEquals:
+		if _, ok := asInt(a.Equals); !ok {
+			return errors.New("status_equals requires a numeric 'equals'")
+		}
+	case models.AssertionHeaderPresent:
+		if a.Header == "" {
+			return errors.New("header_present requires 'header'")
+		}
+	case models.AssertionJSONPath:
+		if a.Path == "" {
+			return errors.New("jsonpath requires 'path'")
+		}
+		if a.Matches != "" {
+			if _, err := regexp.Compile(a.Matches); err != nil {
+				return fmt.Errorf("invalid 'matches' pattern: %w", err)
+			}
+		}
+	case models.AssertionLatencyUnder:
+		if a.MaxMs <= 0 {
+			return errors.New("latency_under requires a positive 'max_ms'")
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+
+	return nil
+}
+
+// Evaluate checks every assertion against resp
+func Evaluate(list []models.Assertion, resp *Response) []models.AssertionResult {
+	results := make([]models.AssertionResult, 0, len(list))
+
+	var body any
+	var bodyErr error
+	if len(resp.Body) > 0 {
+		bodyErr = json.Unmarshal(resp.Body, &body)
+	} else {
+		bodyErr = errors.New("empty body")
+	}
+
+	for _, a := range list {
+		result := models.AssertionResult{Assertion: a}
+
+		switch a.Type {
+		case models.AssertionStatusEquals:
+			expected, _ := asInt(a.Equals)
+			result.Passed = resp.StatusCode == expected
+			result.Message = fmt.Sprintf("expected status %d, got %d", expected, resp.StatusCode)
+		case models.AssertionHeaderPresent:
+			result.Passed = resp.Header.Get(a.Header) != ""
+			result.Message = fmt.Sprintf("expected header %q to be present", a.Header)
+		case models.AssertionJSONPath:
+			result.Passed, result.Message = evaluateJSONPath(a, body, bodyErr)
+		case models.AssertionLatencyUnder:
+			latency := resp.Latency.Milliseconds()
+			result.Passed = latency < a.MaxMs
+			result.Message = fmt.Sprintf("expected latency under %dms, got %dms", a.MaxMs, latency)
+		default:
+			result.Message = fmt.Sprintf("unknown assertion type %q", a.Type)
+		}
+
+		if result.Passed {
+			result.Message = ""
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Passed reports whether every result passed
+func Passed(results []models.AssertionResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func evaluateJSONPath(a models.Assertion, body any, bodyErr error) (bool, string) {
+	if bodyErr != nil {
+		return false, fmt.Sprintf("response body is not JSON: %v", bodyErr)
+	}
+
+	value, err := lookup(body, a.Path)
+	if err != nil {
+		return false, fmt.Sprintf("%s: %v", a.Path, err)
+	}
+
+	if a.Matches != "" {
+		re, err := regexp.Compile(a.Matches)
+		if err != nil {
+			return false, fmt.Sprintf("invalid pattern: %v", err)
+		}
+		str := fmt.Sprintf("%v", value)
+		return re.MatchString(str), fmt.Sprintf("expected %s to match %q, got %q", a.Path, a.Matches, str)
+	}
+
+	if a.Equals != nil {
+		expected := normalize(a.Equals)
+		return reflect.DeepEqual(value, expected), fmt.Sprintf("expected %s to equal %v, got %v", a.Path, expected, value)
+	}
+
+	return true, ""
+}
+
+// normalize round-trips v through JSON so it compares equal to decoded body values
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+
+	return out
+}
+
+func asInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), n == float64(int(n))
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+
+	return 0, false
+}
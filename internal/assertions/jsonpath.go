@@ -0,0 +1,75 @@
+package assertions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookup resolves a simple JSONPath expression against a decoded JSON document.
+// Supported syntax: $, .key, ['key'] and [index], e.g. $.data.items[0]['id']
+func lookup(doc any, path string) (any, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	current := doc
+	rest := path[1:]
+	for rest != "" {
+		var segment string
+		var index = -1
+
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				segment, rest = rest[1:], ""
+			} else {
+				segment, rest = rest[1:end+1], rest[end+1:]
+			}
+			if segment == "" {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated bracket in path %q", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segment = inner[1 : len(inner)-1]
+			} else {
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+				}
+				index = i
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", rest[0], path)
+		}
+
+		if index >= 0 {
+			arr, ok := current.([]any)
+			if !ok || index >= len(arr) {
+				return nil, fmt.Errorf("index %d not found", index)
+			}
+			current = arr[index]
+			continue
+		}
+
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", segment)
+		}
+		value, ok := obj[segment]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", segment)
+		}
+		current = value
+	}
+
+	return current, nil
+}
@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CollectionItem is a Postman request for one operation of a document
+type CollectionItem struct {
+	Name   string
+	Method string
+	// Folder is the operation's first tag, empty for untagged operations
+	Folder      string
+	Description string
+	// Path uses {{name}} variables for path parameters
+	Path string
+	// PathParams holds sample values of the path parameters
+	PathParams map[string]string
+	// Query and Headers list every query and header parameter; optional
+	// ones are meant to be added disabled
+	Query   []ItemParam
+	Headers []ItemParam
+	// Body is a sample JSON request body, nil when the operation takes none
+	Body any
+}
+
+// ItemParam is a query or header parameter with a sample value
+type ItemParam struct {
+	Name        string
+	Value       string
+	Description string
+	Required    bool
+}
+
+// CollectionItems derives a request for every operation of the document, with
+// sample parameters and bodies generated from the operation's schemas
+func CollectionItems(doc map[string]any) []CollectionItem {
+	var items []CollectionItem
+	for _, op := range collectOperations(doc) {
+		item := CollectionItem{
+			Name:        op.summary,
+			Method:      op.method,
+			Description: op.description,
+			Path:        pathParamPattern.ReplaceAllString(op.path, "{{$1}}"),
+			PathParams:  map[string]string{},
+		}
+		if item.Name == "" {
+			item.Name = op.name
+		}
+		if len(op.tags) > 0 {
+			item.Folder = op.tags[0]
+		}
+
+		for _, param := range op.params {
+			value := ""
+			if sample := sampleValue(doc, param.schema, nil); sample != nil {
+				value = fmt.Sprint(sample)
+			}
+			switch param.in {
+			case "path":
+				item.PathParams[param.name] = value
+			case "query":
+				item.Query = append(item.Query, ItemParam{param.name, value, param.description, param.required})
+			case "header":
+				item.Headers = append(item.Headers, ItemParam{param.name, value, param.description, param.required})
+			}
+		}
+
+		if op.body != nil {
+			item.Body = sampleValue(doc, op.body, nil)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// ServerTemplate returns the first server URL of the document with its
+// variables written as {{name}} references, together with their defaults;
+// Swagger 2 documents have no server variables
+func ServerTemplate(doc map[string]any) (string, map[string]string) {
+	variables := map[string]string{}
+
+	servers, ok := doc["servers"].([]any)
+	if !ok || len(servers) == 0 {
+		return ServerURL(doc), variables
+	}
+
+	server, _ := servers[0].(map[string]any)
+	serverURL, _ := server["url"].(string)
+	declared, _ := server["variables"].(map[string]any)
+	for name, v := range declared {
+		variable, _ := v.(map[string]any)
+		def, _ := variable["default"].(string)
+		variables[name] = def
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", "{{"+name+"}}")
+	}
+
+	return strings.TrimSuffix(serverURL, "/"), variables
+}
@@ -0,0 +1,236 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ContractTest is a request exercising one operation of a document together
+// with the response it is expected to produce
+type ContractTest struct {
+	Name   string
+	Method string
+	// Path uses {{name}} variables for path parameters
+	Path    string
+	Summary string
+	// PathParams, Query and Headers hold sample values of the operation's
+	// path parameters and required query and header parameters
+	PathParams map[string]string
+	Query      map[string]string
+	Headers    map[string]string
+	// Body is a sample JSON request body, nil when the operation takes none
+	Body any
+	// Status is the first 2xx response code, zero when none is declared
+	Status int
+	// JSON is set when the success response has a JSON schema
+	JSON bool
+	// Required lists the required top-level properties of an object response
+	Required []string
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// ContractTests derives a contract test for every operation of the document,
+// with sample inputs generated from the operation's schemas
+func ContractTests(doc map[string]any) []ContractTest {
+	var tests []ContractTest
+	for _, op := range collectOperations(doc) {
+		test := ContractTest{
+			Name:       op.name,
+			Method:     op.method,
+			Path:       pathParamPattern.ReplaceAllString(op.path, "{{$1}}"),
+			Summary:    op.summary,
+			PathParams: map[string]string{},
+			Query:      map[string]string{},
+			Headers:    map[string]string{},
+		}
+
+		for _, param := range op.params {
+			if !param.required {
+				continue
+			}
+			value := ""
+			if sample := sampleValue(doc, param.schema, nil); sample != nil {
+				value = fmt.Sprint(sample)
+			}
+			switch param.in {
+			case "path":
+				test.PathParams[param.name] = value
+			case "query":
+				test.Query[param.name] = value
+			case "header":
+				test.Headers[param.name] = value
+			}
+		}
+
+		if op.body != nil {
+			test.Body = sampleValue(doc, op.body, nil)
+		}
+
+		test.Status, _ = strconv.Atoi(op.status)
+
+		if op.response != nil {
+			test.JSON = true
+			if schema, ok := resolveSchema(doc, op.response); ok {
+				test.Required = sortedKeys(boolKeys(requiredSet(schema)))
+			}
+		}
+
+		tests = append(tests, test)
+	}
+
+	return tests
+}
+
+// ServerURL returns the first server URL of an OpenAPI 3 document, with
+// variables at their defaults, or the Swagger 2 scheme, host and basePath
+func ServerURL(doc map[string]any) string {
+	if servers, ok := doc["servers"].([]any); ok && len(servers) > 0 {
+		server, _ := servers[0].(map[string]any)
+		serverURL, _ := server["url"].(string)
+		variables, _ := server["variables"].(map[string]any)
+		for name, v := range variables {
+			variable, _ := v.(map[string]any)
+			if def, ok := variable["default"].(string); ok {
+				serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", def)
+			}
+		}
+		return strings.TrimSuffix(serverURL, "/")
+	}
+
+	host, _ := doc["host"].(string)
+	if host == "" {
+		return ""
+	}
+
+	scheme := "https"
+	if schemes, ok := doc["schemes"].([]any); ok && len(schemes) > 0 {
+		if s, ok := schemes[0].(string); ok {
+			scheme = s
+		}
+	}
+	basePath, _ := doc["basePath"].(string)
+
+	return scheme + "://" + host + strings.TrimSuffix(basePath, "/")
+}
+
+// JSONPathKey returns the JSONPath of a top-level property
+func JSONPathKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return "$." + name
+	}
+	return "$['" + strings.ReplaceAll(name, "'", "\\'") + "']"
+}
+
+// resolveSchema follows a schema's local $ref and reports whether it describes an object
+func resolveSchema(doc map[string]any, schema map[string]any) (map[string]any, bool) {
+	resolved, ok := resolveRef(doc, schema).(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	typ, _ := schemaType(resolved)
+	_, hasProperties := resolved["properties"]
+	return resolved, typ == "object" || (typ == "" && hasProperties)
+}
+
+// sampleValue builds a value conforming to schema, preferring its example,
+// default or first enum value; refs already being expanded are left out so
+// recursive schemas terminate
+func sampleValue(doc map[string]any, schema map[string]any, expanding map[string]bool) any {
+	if ref, ok := schema["$ref"].(string); ok {
+		if expanding[ref] {
+			return nil
+		}
+		nested := map[string]bool{ref: true}
+		for r := range expanding {
+			nested[r] = true
+		}
+		expanding = nested
+	}
+
+	schema, _ = resolveRef(doc, schema).(map[string]any)
+	if schema == nil {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{}
+		for _, part := range all {
+			sub, _ := part.(map[string]any)
+			if obj, ok := sampleValue(doc, sub, expanding).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]any); ok && len(options) > 0 {
+			sub, _ := options[0].(map[string]any)
+			return sampleValue(doc, sub, expanding)
+		}
+	}
+
+	typ, _ := schemaType(schema)
+	switch typ {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "email":
+			return "user@example.com"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	case "integer", "number":
+		if min, ok := schema["minimum"].(float64); ok {
+			return min
+		}
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if item := sampleValue(doc, items, expanding); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		if typ == "object" {
+			return map[string]any{}
+		}
+		return nil
+	}
+
+	obj := map[string]any{}
+	for _, name := range sortedKeys(properties) {
+		prop, _ := properties[name].(map[string]any)
+		if value := sampleValue(doc, prop, expanding); value != nil {
+			obj[name] = value
+		}
+	}
+
+	return obj
+}
@@ -0,0 +1,255 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Documentation formats supported by Documentation
+const (
+	DocsMarkdown = "markdown"
+	DocsHTML     = "html"
+)
+
+// ValidDocsFormat reports whether Documentation can render format
+func ValidDocsFormat(format string) bool {
+	return format == DocsMarkdown || format == DocsHTML
+}
+
+// Docs is the documentation of a collection
+type Docs struct {
+	Name        string
+	Description string
+	// Variables are the collection variables requests refer to as {{name}}
+	Variables []DocsVariable
+	Requests  []DocsRequest
+}
+
+// DocsVariable is a collection variable with its documented value
+type DocsVariable struct {
+	Name  string
+	Value string
+}
+
+// DocsRequest documents one request of a collection
+type DocsRequest struct {
+	Name string
+	// Folder is the "a/b" path of the request's folder, empty at the top level
+	Folder      string
+	Description string
+	Request     SnippetRequest
+	Deprecated  bool
+	// Sunset is the date the request is due to be retired, empty when unset
+	Sunset   string
+	Examples []DocsExample
+}
+
+// DocsExample is a saved example response of a request
+type DocsExample struct {
+	Name   string
+	Code   int
+	Status string
+	Body   string
+	// OmittedBytes is the size of a body kept out of the documentation
+	OmittedBytes int64
+}
+
+// docsSnippet is a code snippet ready to render
+type docsSnippet struct {
+	Language string
+	Label    string
+	Code     string
+}
+
+// Documentation renders docs as Markdown or as a standalone HTML page, with a
+// snippet sending each request in every language of SnippetLanguages
+func Documentation(docs Docs, format string) ([]byte, error) {
+	switch format {
+	case DocsMarkdown:
+		return markdownDocs(docs), nil
+	case DocsHTML:
+		return htmlDocs(docs)
+	default:
+		return nil, fmt.Errorf("unsupported documentation format %q", format)
+	}
+}
+
+func snippets(req SnippetRequest) []docsSnippet {
+	out := make([]docsSnippet, len(SnippetLanguages))
+	for i, language := range SnippetLanguages {
+		out[i] = docsSnippet{Language: language, Label: snippetLabels[language], Code: Snippet(req, language)}
+	}
+	return out
+}
+
+func markdownDocs(docs Docs) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", docs.Name)
+	if docs.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(docs.Description))
+	}
+
+	if len(docs.Variables) > 0 {
+		b.WriteString("\n## Variables\n\n| Name | Value |\n| --- | --- |\n")
+		for _, v := range docs.Variables {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", v.Name, markdownCell(v.Value))
+		}
+	}
+
+	folder := ""
+	for i, req := range docs.Requests {
+		if i == 0 || req.Folder != folder {
+			folder = req.Folder
+			if folder != "" {
+				fmt.Fprintf(&b, "\n## %s\n", folder)
+			}
+		}
+
+		fmt.Fprintf(&b, "\n### %s %s\n", req.Request.Method, req.Name)
+		if req.Deprecated {
+			b.WriteString("\n> **Deprecated**")
+			if req.Sunset != "" {
+				fmt.Fprintf(&b, ", sunset on %s", req.Sunset)
+			}
+			b.WriteString("\n")
+		}
+		if req.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(req.Description))
+		}
+
+		fmt.Fprintf(&b, "\n```\n%s %s\n```\n", req.Request.Method, req.Request.URL)
+		if len(req.Request.Headers) > 0 {
+			b.WriteString("\n| Header | Value |\n| --- | --- |\n")
+			for _, name := range headerNames(req.Request.Headers) {
+				fmt.Fprintf(&b, "| `%s` | %s |\n", name, markdownCell(req.Request.Headers[name]))
+			}
+		}
+		if req.Request.Body != "" {
+			fmt.Fprintf(&b, "\n**Body**\n\n%s", markdownFence(req.Request.Body, ""))
+		}
+
+		for _, snippet := range snippets(req.Request) {
+			fmt.Fprintf(&b, "\n#### %s\n\n%s", snippet.Label, markdownFence(snippet.Code, fenceLanguage(snippet.Language)))
+		}
+
+		for _, example := range req.Examples {
+			fmt.Fprintf(&b, "\n#### Example: %s\n\n", example.Name)
+			if example.Code != 0 {
+				fmt.Fprintf(&b, "Status: `%d %s`\n", example.Code, example.Status)
+			}
+			switch {
+			case example.OmittedBytes > 0:
+				fmt.Fprintf(&b, "\n_Body of %d bytes not shown._\n", example.OmittedBytes)
+			case example.Body != "":
+				fmt.Fprintf(&b, "\n%s", markdownFence(example.Body, ""))
+			}
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// fenceLanguage returns the info string of the code fence for a snippet language
+func fenceLanguage(language string) string {
+	if language == SnippetCurl {
+		return "shell"
+	}
+	return language
+}
+
+// markdownFence wraps code in a fence longer than any backtick run within it
+func markdownFence(code, language string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	return fence + language + "\n" + code + fence + "\n"
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #212121; }
+pre { background: #f5f5f5; padding: .75rem; overflow-x: auto; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ddd; padding: .25rem .5rem; text-align: left; }
+.description { white-space: pre-wrap; }
+.method { font-weight: bold; color: #ff6c37; }
+.deprecated { color: #b00020; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{with .Description}}<div class="description">{{.}}</div>{{end}}
+{{with .Variables}}<h2>Variables</h2>
+<table><tr><th>Name</th><th>Value</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{range .Requests}}{{if .FolderStart}}<h2>{{.Folder}}</h2>{{end}}
+<section>
+<h3><span class="method">{{.Request.Method}}</span> {{.Name}}</h3>
+{{if .Deprecated}}<p class="deprecated">Deprecated{{with .Sunset}}, sunset on {{.}}{{end}}</p>{{end}}
+{{with .Description}}<div class="description">{{.}}</div>{{end}}
+<pre>{{.Request.Method}} {{.Request.URL}}</pre>
+{{with .Headers}}<table><tr><th>Header</th><th>Value</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{with .Request.Body}}<h4>Body</h4><pre>{{.}}</pre>{{end}}
+{{range .Snippets}}<h4>{{.Label}}</h4><pre><code>{{.Code}}</code></pre>
+{{end}}{{range .Examples}}<h4>Example: {{.Name}}</h4>
+{{if .Code}}<p>Status: <code>{{.Code}} {{.Status}}</code></p>{{end}}
+{{if .OmittedBytes}}<p><em>Body of {{.OmittedBytes}} bytes not shown.</em></p>{{else if .Body}}<pre>{{.Body}}</pre>{{end}}
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+// htmlRequest is a request with what the HTML template derives from it
+type htmlRequest struct {
+	DocsRequest
+	FolderStart bool
+	Headers     []DocsVariable
+	Snippets    []docsSnippet
+}
+
+func htmlDocs(docs Docs) ([]byte, error) {
+	requests := make([]htmlRequest, len(docs.Requests))
+	for i, req := range docs.Requests {
+		headers := make([]DocsVariable, 0, len(req.Request.Headers))
+		for _, name := range headerNames(req.Request.Headers) {
+			headers = append(headers, DocsVariable{Name: name, Value: req.Request.Headers[name]})
+		}
+
+		requests[i] = htmlRequest{
+			DocsRequest: req,
+			FolderStart: req.Folder != "" && (i == 0 || docs.Requests[i-1].Folder != req.Folder),
+			Headers:     headers,
+			Snippets:    snippets(req.Request),
+		}
+	}
+
+	var buf bytes.Buffer
+	err := docsTemplate.Execute(&buf, struct {
+		Docs
+		Requests []htmlRequest
+	}{docs, requests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render documentation: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
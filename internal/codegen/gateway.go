@@ -0,0 +1,169 @@
+package codegen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// API gateways supported by Gateway
+const (
+	GatewayKong   = "kong"
+	GatewayAWS    = "aws"
+	GatewayGoogle = "google"
+)
+
+var kongNamePattern = regexp.MustCompile(`[^a-zA-Z0-9._~-]+`)
+
+// ValidGateway reports whether Gateway can export configuration for target
+func ValidGateway(target string) bool {
+	return target == GatewayKong || target == GatewayAWS || target == GatewayGoogle
+}
+
+// Gateway renders provisioning configuration for an API gateway that proxies
+// every operation of the document to its first server: a Kong declarative
+// config, or the document extended for AWS API Gateway or Google API Gateway
+func Gateway(doc map[string]any, target string) ([]byte, error) {
+	if !ValidGateway(target) {
+		return nil, fmt.Errorf("unsupported gateway %q", target)
+	}
+
+	upstream := ServerURL(doc)
+	if !strings.Contains(upstream, "://") {
+		return nil, errors.New("document declares no absolute server URL to proxy to")
+	}
+
+	switch target {
+	case GatewayKong:
+		return json.MarshalIndent(kongConfig(doc, upstream), "", "  ")
+	case GatewayAWS:
+		return extendDocument(doc, func(out map[string]any) { awsIntegrations(out, upstream) })
+	default:
+		return extendDocument(doc, func(out map[string]any) { googleBackend(out, upstream) })
+	}
+}
+
+// kongConfig builds a declarative config with one service for the upstream
+// and a route per operation
+func kongConfig(doc map[string]any, upstream string) map[string]any {
+	name := kongServiceName(doc)
+
+	var routes []map[string]any
+	for _, op := range collectOperations(doc) {
+		routes = append(routes, map[string]any{
+			"name":       name + "." + op.name,
+			"methods":    []string{op.method},
+			"paths":      []string{kongPath(op.path)},
+			"strip_path": false,
+		})
+	}
+
+	return map[string]any{
+		"_format_version": "3.0",
+		"services": []map[string]any{{
+			"name":   name,
+			"url":    upstream,
+			"routes": routes,
+		}},
+	}
+}
+
+// kongServiceName derives a Kong entity name from the document's title
+func kongServiceName(doc map[string]any) string {
+	info, _ := doc["info"].(map[string]any)
+	title, _ := info["title"].(string)
+
+	name := strings.Trim(kongNamePattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if name == "" {
+		return "api"
+	}
+	return name
+}
+
+// kongPath returns the route path of an operation; Kong matches templated
+// paths with a regex route
+func kongPath(path string) string {
+	if !pathParamPattern.MatchString(path) {
+		return path
+	}
+	return "~" + pathRegex(path) + "$"
+}
+
+// awsIntegrations adds an HTTP proxy integration to every operation, passing
+// path parameters through to the upstream
+func awsIntegrations(doc map[string]any, upstream string) {
+	eachOperation(doc, func(path, method string, op map[string]any) {
+		integration := map[string]any{
+			"type":                "http_proxy",
+			"httpMethod":          strings.ToUpper(method),
+			"uri":                 upstream + path,
+			"passthroughBehavior": "when_no_match",
+		}
+
+		params := map[string]any{}
+		for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+			params["integration.request.path."+match[1]] = "method.request.path." + match[1]
+		}
+		if len(params) > 0 {
+			integration["requestParameters"] = params
+		}
+
+		op["x-amazon-apigateway-integration"] = integration
+	})
+}
+
+// googleBackend routes the whole API to the upstream and names every
+// operation, which Google API Gateway requires
+func googleBackend(doc map[string]any, upstream string) {
+	doc["x-google-backend"] = map[string]any{
+		"address":          upstream,
+		"path_translation": "APPEND_PATH_TO_ADDRESS",
+	}
+
+	seen := map[string]int{}
+	eachOperation(doc, func(path, method string, op map[string]any) {
+		name, _ := op["operationId"].(string)
+		if name == "" {
+			name = typeName(method + " " + path)
+		}
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, seen[name])
+		}
+		op["operationId"] = name
+	})
+}
+
+// extendDocument applies fn to a deep copy of the document and renders the result
+func extendDocument(doc map[string]any, fn func(map[string]any)) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy document: %w", err)
+	}
+
+	fn(out)
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// eachOperation calls fn for every operation of the document in path and method order
+func eachOperation(doc map[string]any, fn func(path, method string, op map[string]any)) {
+	paths, _ := doc["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, method := range operationMethods {
+			if op, ok := item[method].(map[string]any); ok {
+				fn(path, method, op)
+			}
+		}
+	}
+}
@@ -0,0 +1,531 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+var goPackageName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidPackageName reports whether name can be used as a generated Go package name
+func ValidPackageName(name string) bool {
+	return goPackageName.MatchString(name) && !token.IsKeyword(name)
+}
+
+// Go renders Go types for the document's component schemas and a basic typed
+// client for its operations, returning the formatted files by name
+func Go(doc map[string]any, pkg string) (map[string][]byte, error) {
+	if !ValidPackageName(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	g := &goGenerator{doc: doc, pkg: pkg, imports: map[string]bool{}}
+	types := g.file(generatedHeader, g.types(), g.imports)
+
+	g.imports = map[string]bool{}
+	client := g.file(generatedHeader, g.client(), g.imports)
+
+	return formatFiles(map[string]string{
+		"types.go":  types,
+		"client.go": client,
+	})
+}
+
+// formatFiles gofmts generated sources; a failure means the generator emitted invalid Go
+func formatFiles(sources map[string]string) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(sources))
+	for name, src := range sources {
+		formatted, err := format.Source([]byte(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to format generated %s: %w", name, err)
+		}
+		files[name] = formatted
+	}
+
+	return files, nil
+}
+
+type goGenerator struct {
+	doc     map[string]any
+	pkg     string
+	imports map[string]bool
+	// extra holds named types hoisted out of inline object schemas
+	extra strings.Builder
+}
+
+const (
+	generatedHeader = "// Code generated from the OpenAPI specification; DO NOT EDIT."
+	scaffoldHeader  = "// Scaffolded from the OpenAPI specification; edit freely."
+)
+
+func (g *goGenerator) file(header, body string, imports map[string]bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\n", header, g.pkg)
+
+	if len(imports) > 0 {
+		// Standard library imports first, then third-party ones
+		var std, external []string
+		for _, path := range sortedKeys(boolKeys(imports)) {
+			if strings.Contains(strings.Split(path, "/")[0], ".") {
+				external = append(external, path)
+			} else {
+				std = append(std, path)
+			}
+		}
+
+		b.WriteString("import (\n")
+		for _, path := range std {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		if len(std) > 0 && len(external) > 0 {
+			b.WriteString("\n")
+		}
+		for _, path := range external {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	b.WriteString(body)
+	return b.String()
+}
+
+func boolKeys(m map[string]bool) map[string]any {
+	keys := make(map[string]any, len(m))
+	for k := range m {
+		keys[k] = nil
+	}
+	return keys
+}
+
+func (g *goGenerator) types() string {
+	var b strings.Builder
+
+	schemas := componentSchemas(g.doc)
+	for _, name := range sortedKeys(schemas) {
+		schema, ok := schemas[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		g.writeNamedType(&b, typeName(name), schema)
+	}
+
+	b.WriteString(g.extra.String())
+	g.extra.Reset()
+
+	return b.String()
+}
+
+// writeNamedType declares a named Go type for a schema
+func (g *goGenerator) writeNamedType(b *strings.Builder, name string, schema map[string]any) {
+	writeGoDoc(b, "", name, schema)
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		if typ, _ := schemaType(schema); typ == "string" {
+			fmt.Fprintf(b, "type %s string\n\n", name)
+			b.WriteString("const (\n")
+			for _, v := range enum {
+				if s, ok := v.(string); ok {
+					fmt.Fprintf(b, "\t%s%s %s = %q\n", name, typeName(s), name, s)
+				}
+			}
+			b.WriteString(")\n\n")
+			return
+		}
+	}
+
+	if _, hasProps := schema["properties"]; hasProps {
+		fmt.Fprintf(b, "type %s %s\n\n", name, g.structType(name, schema))
+		return
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		fmt.Fprintf(b, "type %s %s\n\n", name, g.allOfType(name, all))
+		return
+	}
+
+	fmt.Fprintf(b, "type %s = %s\n\n", name, g.goType(name, schema, true))
+}
+
+func (g *goGenerator) structType(owner string, schema map[string]any) string {
+	properties, _ := schema["properties"].(map[string]any)
+	required := requiredSet(schema)
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, prop := range sortedKeys(properties) {
+		propSchema, ok := properties[prop].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		field := typeName(prop)
+		writeGoDoc(&b, "\t", field, propSchema)
+
+		typ := g.goType(owner+field, propSchema, required[prop])
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// allOfType embeds referenced schemas and flattens inline object parts
+func (g *goGenerator) allOfType(owner string, parts []any) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for i, part := range parts {
+		schema, ok := part.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if ref, ok := schema["$ref"].(string); ok {
+			fmt.Fprintf(&b, "\t%s\n", typeName(refName(ref)))
+			continue
+		}
+
+		if _, ok := schema["properties"]; ok {
+			inline := g.structType(owner, schema)
+			b.WriteString(strings.TrimSuffix(strings.TrimPrefix(inline, "struct {\n"), "}"))
+			continue
+		}
+
+		fmt.Fprintf(&b, "\tPart%d %s `json:\"-\"`\n", i+1, g.goType(owner, schema, true))
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// goType maps a schema to a Go type; optional values become pointers so
+// that absent and zero values stay distinguishable
+func (g *goGenerator) goType(owner string, schema map[string]any, required bool) string {
+	typ, pointable := g.baseGoType(owner, schema)
+	if pointable && (!required || isNullable(schema)) {
+		return "*" + typ
+	}
+	return typ
+}
+
+func (g *goGenerator) baseGoType(owner string, schema map[string]any) (string, bool) {
+	if ref, ok := schema["$ref"].(string); ok {
+		return typeName(refName(ref)), true
+	}
+
+	typ, _ := schemaType(schema)
+	format, _ := schema["format"].(string)
+
+	switch typ {
+	case "string":
+		switch format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", true
+		case "binary":
+			return "[]byte", false
+		}
+		return "string", true
+	case "integer":
+		if format == "int32" {
+			return "int32", true
+		}
+		return "int64", true
+	case "number":
+		if format == "float" {
+			return "float32", true
+		}
+		return "float64", true
+	case "boolean":
+		return "bool", true
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return "[]any", false
+		}
+		return "[]" + g.goType(owner+"Item", items, true), false
+	}
+
+	if _, ok := schema["properties"]; ok {
+		g.writeNamedType(&g.extra, owner, schema)
+		return owner, true
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		g.extra.WriteString(fmt.Sprintf("type %s %s\n\n", owner, g.allOfType(owner, all)))
+		return owner, true
+	}
+
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		return "map[string]" + g.goType(owner+"Value", additional, true), false
+	}
+
+	if typ == "object" {
+		return "map[string]any", false
+	}
+
+	return "any", false
+}
+
+func writeGoDoc(b *strings.Builder, indent, name string, schema map[string]any) {
+	description, _ := schema["description"].(string)
+	if strings.TrimSpace(description) == "" {
+		return
+	}
+
+	for i, line := range commentLines(description) {
+		if i == 0 {
+			line = name + ": " + line
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// goOperation is an operation with its parameters and payloads mapped to Go types
+type goOperation struct {
+	name     string
+	method   string
+	path     string
+	summary  string
+	params   []goParam
+	body     string
+	response string
+}
+
+type goParam struct {
+	name     string
+	field    string
+	in       string
+	typ      string
+	required bool
+}
+
+func (g *goGenerator) operations() []goOperation {
+	var ops []goOperation
+	for _, raw := range collectOperations(g.doc) {
+		op := goOperation{name: raw.name, method: raw.method, path: raw.path, summary: raw.summary}
+
+		for _, p := range raw.params {
+			op.params = append(op.params, goParam{
+				name:     p.name,
+				field:    typeName(p.name),
+				in:       p.in,
+				typ:      g.goType(raw.name+typeName(p.name), p.schema, p.required),
+				required: p.required,
+			})
+		}
+
+		if raw.body != nil {
+			op.body = strings.TrimPrefix(g.goType(raw.name+"Request", raw.body, true), "*")
+		}
+		if raw.response != nil {
+			op.response = strings.TrimPrefix(g.goType(raw.name+"Response", raw.response, true), "*")
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops
+}
+
+func (g *goGenerator) client() string {
+	g.imports["bytes"] = true
+	g.imports["context"] = true
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+	g.imports["io"] = true
+	g.imports["net/http"] = true
+	g.imports["net/url"] = true
+	g.imports["strings"] = true
+
+	var b strings.Builder
+	b.WriteString(goClientPreamble)
+
+	for _, op := range g.operations() {
+		g.writeOperation(&b, op)
+	}
+
+	b.WriteString(g.extra.String())
+	g.extra.Reset()
+
+	return b.String()
+}
+
+func (g *goGenerator) writeOperation(b *strings.Builder, op goOperation) {
+	hasParams := len(op.params) > 0
+	if hasParams {
+		fmt.Fprintf(b, "// %sParams holds the parameters of %s\n", op.name, op.name)
+		fmt.Fprintf(b, "type %sParams struct {\n", op.name)
+		for _, p := range op.params {
+			fmt.Fprintf(b, "\t%s %s\n", p.field, p.typ)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	writeOperationDoc(b, op)
+
+	args := []string{"ctx context.Context"}
+	if hasParams {
+		args = append(args, fmt.Sprintf("params %sParams", op.name))
+	}
+	if op.body != "" {
+		args = append(args, "body "+op.body)
+	}
+
+	result := "error"
+	if op.response != "" {
+		result = fmt.Sprintf("(%s, error)", pointerTo(op.response))
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", op.name, strings.Join(args, ", "), result)
+
+	fail := "return err"
+	if op.response != "" {
+		fail = "return nil, err"
+	}
+
+	fmt.Fprintf(b, "\tpath := %q\n", op.path)
+	b.WriteString("\tquery := url.Values{}\n")
+	b.WriteString("\theader := http.Header{}\n")
+	for _, p := range op.params {
+		value := "params." + p.field
+		if strings.HasPrefix(p.typ, "*") {
+			fmt.Fprintf(b, "\tif %s != nil {\n", value)
+			value = "*" + value
+		} else if strings.HasPrefix(p.typ, "[]") {
+			fmt.Fprintf(b, "\tfor _, v := range %s {\n", value)
+			value = "v"
+		} else {
+			b.WriteString("\t{\n")
+		}
+
+		switch p.in {
+		case "path":
+			fmt.Fprintf(b, "\t\tpath = strings.ReplaceAll(path, %q, url.PathEscape(fmt.Sprint(%s)))\n", "{"+p.name+"}", value)
+		case "query":
+			fmt.Fprintf(b, "\t\tquery.Add(%q, fmt.Sprint(%s))\n", p.name, value)
+		case "header":
+			fmt.Fprintf(b, "\t\theader.Add(%q, fmt.Sprint(%s))\n", p.name, value)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	body := "nil"
+	if op.body != "" {
+		body = "body"
+	}
+
+	if op.response != "" {
+		fmt.Fprintf(b, "\tvar out %s\n", op.response)
+		fmt.Fprintf(b, "\tif err := c.do(ctx, %q, path, query, header, %s, &out); err != nil {\n\t\t%s\n\t}\n", op.method, body, fail)
+		b.WriteString("\treturn &out, nil\n}\n\n")
+		return
+	}
+
+	fmt.Fprintf(b, "\treturn c.do(ctx, %q, path, query, header, %s, nil)\n}\n\n", op.method, body)
+}
+
+func writeOperationDoc(b *strings.Builder, op goOperation) {
+	summary := op.summary
+	if summary == "" {
+		summary = op.method + " " + op.path
+	}
+	fmt.Fprintf(b, "// %s: %s\n", op.name, strings.TrimSpace(strings.SplitN(summary, "\n", 2)[0]))
+}
+
+func pointerTo(typ string) string {
+	if strings.HasPrefix(typ, "*") {
+		return typ
+	}
+	return "*" + typ
+}
+
+const goClientPreamble = `// Client calls the API over HTTP
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// RequestEditor, when set, can modify every request before it is sent,
+	// e.g. to add authentication headers
+	RequestEditor func(req *http.Request) error
+}
+
+// NewClient creates a client for the API served at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// APIError is returned for responses outside the 2xx range
+type APIError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if c.RequestEditor != nil {
+		if err := c.RequestEditor(req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{StatusCode: resp.StatusCode, Body: data}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+`
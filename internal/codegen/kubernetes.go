@@ -0,0 +1,267 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubernetes resources supported by Kubernetes
+const (
+	ManifestHTTPRoute = "httproute"
+	ManifestIngress   = "ingress"
+)
+
+// maxHTTPRouteRules is the Gateway API limit on rules per HTTPRoute
+const maxHTTPRouteRules = 16
+
+var resourceNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// KubernetesOptions names the resources the generated manifests refer to
+type KubernetesOptions struct {
+	Kind      string
+	Namespace string
+	// Service and Port are the backend every route points at
+	Service string
+	Port    int
+	// Gateway is the parent Gateway of HTTPRoutes
+	Gateway string
+}
+
+// ValidManifestKind reports whether Kubernetes can emit manifests of kind
+func ValidManifestKind(kind string) bool {
+	return kind == ManifestHTTPRoute || kind == ManifestIngress
+}
+
+// Kubernetes renders YAML manifests routing the document's paths, under the
+// hosts and base path of its servers, to a backend service: Gateway API
+// HTTPRoutes or an Ingress skeleton
+func Kubernetes(doc map[string]any, opts KubernetesOptions) ([]byte, error) {
+	if !ValidManifestKind(opts.Kind) {
+		return nil, fmt.Errorf("unsupported manifest kind %q", opts.Kind)
+	}
+
+	name := resourceName(doc)
+	if opts.Service == "" {
+		opts.Service = name
+	}
+	if opts.Port == 0 {
+		opts.Port = 80
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+
+	hosts, basePath := serverHosts(doc)
+
+	var resources []map[string]any
+	switch opts.Kind {
+	case ManifestHTTPRoute:
+		if opts.Gateway == "" {
+			opts.Gateway = "gateway"
+		}
+		resources = httpRoutes(doc, name, hosts, basePath, opts)
+	case ManifestIngress:
+		resources = []map[string]any{ingress(doc, name, hosts, basePath, opts)}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, resource := range resources {
+		if err := enc.Encode(resource); err != nil {
+			return nil, fmt.Errorf("failed to render manifest: %w", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to render manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// httpRoutes builds a rule per path matching its methods, split across as
+// many HTTPRoutes as the per-route rule limit requires
+func httpRoutes(doc map[string]any, name string, hosts []string, basePath string, opts KubernetesOptions) []map[string]any {
+	methods := map[string][]string{}
+	for _, op := range collectOperations(doc) {
+		methods[op.path] = append(methods[op.path], op.method)
+	}
+
+	paths := make([]string, 0, len(methods))
+	for path := range methods {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var rules []map[string]any
+	for _, path := range paths {
+		pathMatch := map[string]any{"type": "Exact", "value": basePath + path}
+		if pathParamPattern.MatchString(path) {
+			pathMatch = map[string]any{"type": "RegularExpression", "value": pathRegex(basePath + path)}
+		}
+
+		var matches []map[string]any
+		for _, method := range methods[path] {
+			matches = append(matches, map[string]any{"path": pathMatch, "method": method})
+		}
+
+		rules = append(rules, map[string]any{
+			"matches": matches,
+			"backendRefs": []map[string]any{{
+				"name": opts.Service,
+				"port": opts.Port,
+			}},
+		})
+	}
+
+	var routes []map[string]any
+	for start := 0; start < len(rules); start += maxHTTPRouteRules {
+		end := min(start+maxHTTPRouteRules, len(rules))
+
+		routeName := name
+		if start > 0 {
+			routeName = fmt.Sprintf("%s-%d", name, start/maxHTTPRouteRules+1)
+		}
+
+		spec := map[string]any{
+			"parentRefs": []map[string]any{{"name": opts.Gateway}},
+			"rules":      rules[start:end],
+		}
+		if len(hosts) > 0 {
+			spec["hostnames"] = hosts
+		}
+
+		routes = append(routes, map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata":   map[string]any{"name": routeName, "namespace": opts.Namespace},
+			"spec":       spec,
+		})
+	}
+
+	return routes
+}
+
+// ingress builds an Ingress with an Exact path per literal path and a Prefix
+// path up to the first parameter of templated ones
+func ingress(doc map[string]any, name string, hosts []string, basePath string, opts KubernetesOptions) map[string]any {
+	seen := map[string]bool{}
+	var paths []map[string]any
+	for _, op := range collectOperations(doc) {
+		path, pathType := basePath+op.path, "Exact"
+		if loc := pathParamPattern.FindStringIndex(path); loc != nil {
+			path, pathType = strings.TrimSuffix(path[:loc[0]], "/"), "Prefix"
+			if path == "" {
+				path = "/"
+			}
+		}
+
+		key := pathType + " " + path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		paths = append(paths, map[string]any{
+			"path":     path,
+			"pathType": pathType,
+			"backend": map[string]any{
+				"service": map[string]any{
+					"name": opts.Service,
+					"port": map[string]any{"number": opts.Port},
+				},
+			},
+		})
+	}
+
+	http := map[string]any{"paths": paths}
+	var rules []map[string]any
+	if len(hosts) == 0 {
+		rules = append(rules, map[string]any{"http": http})
+	}
+	for _, host := range hosts {
+		rules = append(rules, map[string]any{"host": host, "http": http})
+	}
+
+	return map[string]any{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "Ingress",
+		"metadata":   map[string]any{"name": name, "namespace": opts.Namespace},
+		"spec":       map[string]any{"rules": rules},
+	}
+}
+
+// serverHosts returns the distinct hosts of the document's servers, without
+// ports, and the base path of the first one
+func serverHosts(doc map[string]any) ([]string, string) {
+	var urls []string
+	if servers, ok := doc["servers"].([]any); ok {
+		for _, raw := range servers {
+			server, _ := raw.(map[string]any)
+			serverURL, _ := server["url"].(string)
+			variables, _ := server["variables"].(map[string]any)
+			for name, v := range variables {
+				variable, _ := v.(map[string]any)
+				if def, ok := variable["default"].(string); ok {
+					serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", def)
+				}
+			}
+			urls = append(urls, serverURL)
+		}
+	} else if serverURL := ServerURL(doc); serverURL != "" {
+		urls = append(urls, serverURL)
+	}
+
+	var hosts []string
+	var basePath string
+	seen := map[string]bool{}
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if i == 0 {
+			basePath = strings.TrimSuffix(u.Path, "/")
+		}
+		if host := u.Hostname(); host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts, basePath
+}
+
+// pathRegex matches a templated path with each parameter standing for one segment
+func pathRegex(path string) string {
+	var pattern strings.Builder
+	for i, literal := range pathParamPattern.Split(path, -1) {
+		if i > 0 {
+			pattern.WriteString("[^/]+")
+		}
+		pattern.WriteString(regexp.QuoteMeta(literal))
+	}
+	return pattern.String()
+}
+
+// resourceName derives a DNS label from the document's title
+func resourceName(doc map[string]any) string {
+	info, _ := doc["info"].(map[string]any)
+	title, _ := info["title"].(string)
+
+	name := strings.Trim(resourceNamePattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(name) > 53 {
+		name = strings.TrimRight(name[:53], "-")
+	}
+	if name == "" {
+		return "api"
+	}
+
+	return name
+}
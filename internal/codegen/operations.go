@@ -0,0 +1,235 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// operation is a single path+method pair of a document with local $refs of
+// its parameters, request body and response resolved
+type operation struct {
+	name    string
+	method  string
+	path    string
+	summary string
+	// description and tags are kept as declared for documentation output
+	description string
+	tags        []string
+	status      string
+	params      []operationParam
+	body        map[string]any
+	response    map[string]any
+}
+
+type operationParam struct {
+	name        string
+	in          string
+	description string
+	schema      map[string]any
+	required    bool
+}
+
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// collectOperations lists the document's operations in path and method order,
+// naming each after its operationId or, lacking one, its method and path
+func collectOperations(doc map[string]any) []operation {
+	paths, _ := doc["paths"].(map[string]any)
+
+	var ops []operation
+	seen := map[string]int{}
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		shared, _ := item["parameters"].([]any)
+		for _, method := range operationMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			name, _ := op["operationId"].(string)
+			if name == "" {
+				name = method + " " + path
+			}
+			name = typeName(name)
+			if seen[name]++; seen[name] > 1 {
+				name = fmt.Sprintf("%s%d", name, seen[name])
+			}
+
+			own, _ := op["parameters"].([]any)
+			summary, _ := op["summary"].(string)
+			description, _ := op["description"].(string)
+
+			var tags []string
+			raw, _ := op["tags"].([]any)
+			for _, t := range raw {
+				if tag, ok := t.(string); ok && tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+
+			ops = append(ops, operation{
+				name:        name,
+				method:      strings.ToUpper(method),
+				path:        path,
+				summary:     summary,
+				description: description,
+				tags:        tags,
+				status:      successStatus(op),
+				params:      operationParams(doc, append(append([]any{}, shared...), own...)),
+				body:        requestBodySchema(doc, op),
+				response:    responseSchema(doc, op),
+			})
+		}
+	}
+
+	return ops
+}
+
+func operationParams(doc map[string]any, raw []any) []operationParam {
+	var params []operationParam
+	index := map[string]int{}
+	for _, p := range raw {
+		param, ok := resolveRef(doc, p).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if name == "" || (in != "path" && in != "query" && in != "header") {
+			continue
+		}
+
+		required, _ := param["required"].(bool)
+		required = required || in == "path"
+
+		schema, ok := param["schema"].(map[string]any)
+		if !ok {
+			// Swagger 2 puts the type on the parameter itself
+			schema = map[string]any{"type": "string"}
+			if t, ok := param["type"].(string); ok {
+				schema["type"] = t
+			}
+		}
+
+		description, _ := param["description"].(string)
+
+		op := operationParam{name: name, in: in, description: description, schema: schema, required: required}
+
+		// Operation parameters override path-level ones with the same name and location
+		key := in + ":" + name
+		if i, ok := index[key]; ok {
+			params[i] = op
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, op)
+	}
+
+	return params
+}
+
+func requestBodySchema(doc map[string]any, op map[string]any) map[string]any {
+	if body, ok := resolveRef(doc, op["requestBody"]).(map[string]any); ok {
+		if schema := jsonSchema(body); schema != nil {
+			return schema
+		}
+	}
+
+	// Swagger 2 declares the body as an "in: body" parameter
+	params, _ := op["parameters"].([]any)
+	for _, p := range params {
+		param, ok := resolveRef(doc, p).(map[string]any)
+		if !ok || param["in"] != "body" {
+			continue
+		}
+		if schema, ok := param["schema"].(map[string]any); ok {
+			return schema
+		}
+	}
+
+	return nil
+}
+
+// successStatus returns the first 2xx response code of an operation
+func successStatus(op map[string]any) string {
+	responses, _ := op["responses"].(map[string]any)
+	for _, code := range sortedKeys(responses) {
+		if strings.HasPrefix(code, "2") {
+			return code
+		}
+	}
+
+	return ""
+}
+
+// responseSchema returns the JSON schema of the first 2xx response
+func responseSchema(doc map[string]any, op map[string]any) map[string]any {
+	responses, _ := op["responses"].(map[string]any)
+	for _, code := range sortedKeys(responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+
+		response, ok := resolveRef(doc, responses[code]).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if schema := jsonSchema(response); schema != nil {
+			return schema
+		}
+		if schema, ok := response["schema"].(map[string]any); ok {
+			return schema
+		}
+	}
+
+	return nil
+}
+
+// jsonSchema returns the JSON media type schema of a request body or response
+func jsonSchema(obj map[string]any) map[string]any {
+	content, _ := obj["content"].(map[string]any)
+	for _, mediaType := range sortedKeys(content) {
+		if !strings.Contains(mediaType, "json") {
+			continue
+		}
+		if media, ok := content[mediaType].(map[string]any); ok {
+			if schema, ok := media["schema"].(map[string]any); ok {
+				return schema
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveRef follows a local $ref of a parameter, request body or response
+func resolveRef(doc map[string]any, v any) any {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	ref, ok := obj["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return v
+	}
+
+	var current any = doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = node[part]
+	}
+
+	return current
+}
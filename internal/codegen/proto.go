@@ -0,0 +1,377 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var protoPackageName = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// ValidProtoPackage reports whether name can be used as a proto package name
+func ValidProtoPackage(name string) bool {
+	return protoPackageName.MatchString(name)
+}
+
+// Proto renders a proto3 file with one message per component schema and a
+// service whose RPCs carry google.api.http annotations for each operation
+func Proto(doc map[string]any, pkg string) ([]byte, error) {
+	if !ValidProtoPackage(pkg) {
+		return nil, fmt.Errorf("invalid proto package %q", pkg)
+	}
+
+	g := &protoGenerator{doc: doc, imports: map[string]bool{}}
+
+	var messages strings.Builder
+	schemas := componentSchemas(doc)
+	for _, name := range sortedKeys(schemas) {
+		if schema, ok := schemas[name].(map[string]any); ok {
+			g.writeMessage(&messages, "", typeName(name), schema)
+		}
+	}
+
+	service := g.service()
+
+	title := "API"
+	if info, ok := doc["info"].(map[string]any); ok {
+		if t, ok := info["title"].(string); ok && t != "" {
+			title = t
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated from the OpenAPI specification %q; do not edit.\n", title)
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\npackage %s;\n\n", pkg)
+	for _, path := range sortedKeys(boolKeys(g.imports)) {
+		fmt.Fprintf(&b, "import %q;\n", path)
+	}
+	if len(g.imports) > 0 {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "service %sService {\n%s}\n", typeName(title), service)
+	b.WriteString(messages.String())
+	b.WriteString(g.extra.String())
+
+	return []byte(b.String()), nil
+}
+
+type protoGenerator struct {
+	doc     map[string]any
+	imports map[string]bool
+	// extra holds request and response messages synthesized for operations
+	extra strings.Builder
+}
+
+func (g *protoGenerator) writeMessage(b *strings.Builder, indent, name string, schema map[string]any) {
+	b.WriteString("\n")
+	writeProtoDoc(b, indent, schema)
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		if typ, _ := schemaType(schema); typ == "string" {
+			g.writeEnum(b, indent, name, enum)
+			return
+		}
+	}
+
+	fmt.Fprintf(b, "%smessage %s {\n", indent, name)
+
+	inner := indent + "  "
+	var nested strings.Builder
+	number := 1
+	for _, field := range g.fields(schema) {
+		writeProtoDoc(b, inner, field.schema)
+		typ := g.fieldType(&nested, inner, field)
+		fmt.Fprintf(b, "%s%s %s = %d;\n", inner, typ, snakeCase(field.name), number)
+		number++
+	}
+
+	b.WriteString(nested.String())
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+func (g *protoGenerator) writeEnum(b *strings.Builder, indent, name string, values []any) {
+	prefix := strings.ToUpper(snakeCase(name))
+
+	fmt.Fprintf(b, "%senum %s {\n", indent, name)
+	fmt.Fprintf(b, "%s  %s_UNSPECIFIED = 0;\n", indent, prefix)
+	number := 1
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			fmt.Fprintf(b, "%s  %s_%s = %d;\n", indent, prefix, strings.ToUpper(snakeCase(s)), number)
+			number++
+		}
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+type protoField struct {
+	name     string
+	schema   map[string]any
+	required bool
+}
+
+// fields flattens properties, including those of allOf parts, in a stable order
+func (g *protoGenerator) fields(schema map[string]any) []protoField {
+	var fields []protoField
+	seen := map[string]bool{}
+
+	var collect func(schema map[string]any)
+	collect = func(schema map[string]any) {
+		if ref, ok := schema["$ref"].(string); ok {
+			if target, ok := componentSchemas(g.doc)[refName(ref)].(map[string]any); ok {
+				collect(target)
+			}
+			return
+		}
+
+		if all, ok := schema["allOf"].([]any); ok {
+			for _, part := range all {
+				if partSchema, ok := part.(map[string]any); ok {
+					collect(partSchema)
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		required := requiredSet(schema)
+		for _, name := range sortedKeys(properties) {
+			prop, ok := properties[name].(map[string]any)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			fields = append(fields, protoField{name: name, schema: prop, required: required[name]})
+		}
+	}
+
+	collect(schema)
+	return fields
+}
+
+// fieldType maps a property schema to a proto field type, declaring nested
+// messages and enums for inline object and enum schemas
+func (g *protoGenerator) fieldType(nested *strings.Builder, indent string, f protoField) string {
+	field, schema := f.name, f.schema
+	typ, _ := schemaType(schema)
+	if typ == "array" {
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			g.imports["google/protobuf/struct.proto"] = true
+			return "repeated google.protobuf.Value"
+		}
+		if itemType, _ := schemaType(items); itemType == "array" {
+			// proto has no nested repeated fields
+			g.imports["google/protobuf/struct.proto"] = true
+			return "repeated google.protobuf.ListValue"
+		}
+		return "repeated " + g.scalarType(nested, indent, field, items)
+	}
+
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		if _, hasProps := schema["properties"]; !hasProps {
+			valueType := g.scalarType(nested, indent, field+"_value", additional)
+			if strings.HasPrefix(valueType, "repeated ") {
+				g.imports["google/protobuf/struct.proto"] = true
+				valueType = "google.protobuf.ListValue"
+			}
+			return "map<string, " + valueType + ">"
+		}
+	}
+
+	if !hasPresence(schema) && (!f.required || isNullable(schema)) {
+		return "optional " + g.scalarType(nested, indent, field, schema)
+	}
+
+	return g.scalarType(nested, indent, field, schema)
+}
+
+// hasPresence reports whether a schema maps to a message or enum type, whose
+// absence is already observable without marking the field optional
+func hasPresence(schema map[string]any) bool {
+	typ, _ := schemaType(schema)
+	switch typ {
+	case "string":
+		format, _ := schema["format"].(string)
+		enum, _ := schema["enum"].([]any)
+		return format == "date-time" || len(enum) > 0
+	case "integer", "number", "boolean":
+		return false
+	}
+	return true
+}
+
+func (g *protoGenerator) scalarType(nested *strings.Builder, indent, field string, schema map[string]any) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return typeName(refName(ref))
+	}
+
+	typ, _ := schemaType(schema)
+	format, _ := schema["format"].(string)
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 && typ == "string" {
+		name := typeName(field)
+		nested.WriteString("\n")
+		g.writeEnum(nested, indent, name, enum)
+		return name
+	}
+
+	switch typ {
+	case "string":
+		switch format {
+		case "date-time":
+			g.imports["google/protobuf/timestamp.proto"] = true
+			return "google.protobuf.Timestamp"
+		case "binary", "byte":
+			return "bytes"
+		}
+		return "string"
+	case "integer":
+		if format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if format == "float" {
+			return "float"
+		}
+		return "double"
+	case "boolean":
+		return "bool"
+	case "array":
+		g.imports["google/protobuf/struct.proto"] = true
+		return "google.protobuf.ListValue"
+	}
+
+	if _, ok := schema["properties"]; ok {
+		name := typeName(field)
+		g.writeMessage(nested, indent, name, schema)
+		return name
+	}
+
+	if _, ok := schema["allOf"]; ok {
+		name := typeName(field)
+		g.writeMessage(nested, indent, name, schema)
+		return name
+	}
+
+	g.imports["google/protobuf/struct.proto"] = true
+	if typ == "object" {
+		return "google.protobuf.Struct"
+	}
+	return "google.protobuf.Value"
+}
+
+func (g *protoGenerator) service() string {
+	var b strings.Builder
+	for _, op := range collectOperations(g.doc) {
+		request := g.requestMessage(op)
+		response := g.responseMessage(op)
+
+		writeProtoDoc(&b, "  ", map[string]any{"description": op.summary})
+		fmt.Fprintf(&b, "  rpc %s(%s) returns (%s) {\n", op.name, request, response)
+		g.imports["google/api/annotations.proto"] = true
+
+		path := pathParamPattern.ReplaceAllStringFunc(op.path, func(param string) string {
+			return "{" + snakeCase(strings.Trim(param, "{}")) + "}"
+		})
+		fmt.Fprintf(&b, "    option (google.api.http) = {\n      %s: %q\n", strings.ToLower(op.method), path)
+		if op.body != nil {
+			b.WriteString("      body: \"body\"\n")
+		}
+		b.WriteString("    };\n  }\n")
+	}
+
+	return b.String()
+}
+
+func (g *protoGenerator) requestMessage(op operation) string {
+	if len(op.params) == 0 && op.body == nil {
+		g.imports["google/protobuf/empty.proto"] = true
+		return "google.protobuf.Empty"
+	}
+
+	name := op.name + "Request"
+	schema := map[string]any{"type": "object", "properties": map[string]any{}}
+	properties := schema["properties"].(map[string]any)
+	var required []any
+	for _, p := range op.params {
+		properties[p.name] = p.schema
+		if p.required {
+			required = append(required, p.name)
+		}
+	}
+	if op.body != nil {
+		properties["body"] = op.body
+		required = append(required, "body")
+	}
+	schema["required"] = required
+
+	g.writeMessage(&g.extra, "", name, schema)
+	return name
+}
+
+func (g *protoGenerator) responseMessage(op operation) string {
+	if op.response == nil {
+		g.imports["google/protobuf/empty.proto"] = true
+		return "google.protobuf.Empty"
+	}
+
+	if ref, ok := op.response["$ref"].(string); ok {
+		return typeName(refName(ref))
+	}
+
+	name := op.name + "Response"
+	if _, ok := op.response["properties"]; ok {
+		g.writeMessage(&g.extra, "", name, op.response)
+		return name
+	}
+
+	// Non-object responses are wrapped, since RPCs must return messages
+	g.writeMessage(&g.extra, "", name, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"data": op.response},
+		"required":   []any{"data"},
+	})
+	return name
+}
+
+func writeProtoDoc(b *strings.Builder, indent string, schema map[string]any) {
+	description, _ := schema["description"].(string)
+	if strings.TrimSpace(description) == "" {
+		return
+	}
+
+	for _, line := range commentLines(description) {
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// snakeCase converts camelCase, kebab-case and spaced names to snake_case
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "f_" + s
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+// Package codegen renders client-side types and clients from stored OpenAPI documents.
+package codegen
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// componentSchemas returns the named schemas of an OpenAPI 3 or Swagger 2 document
+func componentSchemas(doc map[string]any) map[string]any {
+	if components, ok := doc["components"].(map[string]any); ok {
+		if schemas, ok := components["schemas"].(map[string]any); ok {
+			return schemas
+		}
+	}
+
+	if definitions, ok := doc["definitions"].(map[string]any); ok {
+		return definitions
+	}
+
+	return map[string]any{}
+}
+
+// refName returns the schema name a local $ref points at
+func refName(ref string) string {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+}
+
+// typeName turns an arbitrary schema or operation name into a PascalCase identifier
+func typeName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	ident := b.String()
+	if ident == "" {
+		return "Type"
+	}
+	if unicode.IsDigit(rune(ident[0])) {
+		return "T" + ident
+	}
+
+	return ident
+}
+
+// schemaType returns the schema's type keyword, accepting the 3.1 array form
+func schemaType(schema map[string]any) (string, bool) {
+	switch t := schema["type"].(type) {
+	case string:
+		return t, false
+	case []any:
+		var typ string
+		nullable := false
+		for _, v := range t {
+			if s, _ := v.(string); s == "null" {
+				nullable = true
+			} else if typ == "" {
+				typ = s
+			}
+		}
+		return typ, nullable
+	}
+
+	return "", false
+}
+
+func isNullable(schema map[string]any) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	_, nullable := schemaType(schema)
+	return nullable
+}
+
+func requiredSet(schema map[string]any) map[string]bool {
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	return required
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// commentLines splits a description into trimmed lines for doc comments
+func commentLines(description string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+
+	return lines
+}
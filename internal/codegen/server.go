@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Server frameworks supported by GoServer
+const (
+	FrameworkGin  = "gin"
+	FrameworkEcho = "echo"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// ValidFramework reports whether GoServer can emit stubs for framework
+func ValidFramework(framework string) bool {
+	return framework == FrameworkGin || framework == FrameworkEcho
+}
+
+// GoServer renders Go types plus handler stubs and route registration for
+// every operation of the document, using the given web framework
+func GoServer(doc map[string]any, pkg, framework string) (map[string][]byte, error) {
+	if !ValidPackageName(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	if !ValidFramework(framework) {
+		return nil, fmt.Errorf("unsupported framework %q", framework)
+	}
+
+	g := &goGenerator{doc: doc, pkg: pkg, imports: map[string]bool{}}
+	types := g.file(generatedHeader, g.types(), g.imports)
+
+	g.imports = map[string]bool{"net/http": true}
+	ops := g.operations()
+
+	var handlers, routes strings.Builder
+	routeImports := map[string]bool{}
+	switch framework {
+	case FrameworkGin:
+		routeImports["github.com/gin-gonic/gin"] = true
+		writeGinServer(&handlers, &routes, ops)
+	case FrameworkEcho:
+		routeImports["github.com/labstack/echo/v4"] = true
+		writeEchoServer(&handlers, &routes, ops)
+	}
+
+	for path := range routeImports {
+		g.imports[path] = true
+	}
+	handlers.WriteString(g.extra.String())
+	g.extra.Reset()
+
+	return formatFiles(map[string]string{
+		"types.go":    types,
+		"handlers.go": g.file(scaffoldHeader, handlers.String(), g.imports),
+		"routes.go":   g.file(generatedHeader, routes.String(), routeImports),
+	})
+}
+
+// routePath converts an OpenAPI path template into the :param form Gin and Echo use
+func routePath(path string) string {
+	return pathParamPattern.ReplaceAllString(path, ":$1")
+}
+
+func writeGinServer(handlers, routes *strings.Builder, ops []goOperation) {
+	handlers.WriteString("// Handlers implements the API; replace each stub with the real logic\ntype Handlers struct{}\n\n")
+
+	for _, op := range ops {
+		writeOperationDoc(handlers, op)
+		fmt.Fprintf(handlers, "func (h *Handlers) %s(c *gin.Context) {\n", op.name)
+		if op.body != "" {
+			fmt.Fprintf(handlers, "\tvar body %s\n", op.body)
+			handlers.WriteString("\tif err := c.ShouldBindJSON(&body); err != nil {\n")
+			handlers.WriteString("\t\tc.JSON(http.StatusBadRequest, gin.H{\"error\": err.Error()})\n\t\treturn\n\t}\n\n")
+		}
+		handlers.WriteString("\tc.JSON(http.StatusNotImplemented, gin.H{\"error\": \"not implemented\"})\n}\n\n")
+	}
+
+	routes.WriteString("// RegisterRoutes mounts every operation of the API on r\n")
+	routes.WriteString("func RegisterRoutes(r gin.IRouter, h *Handlers) {\n")
+	for _, op := range ops {
+		fmt.Fprintf(routes, "\tr.Handle(%q, %q, h.%s)\n", op.method, routePath(op.path), op.name)
+	}
+	routes.WriteString("}\n")
+}
+
+func writeEchoServer(handlers, routes *strings.Builder, ops []goOperation) {
+	handlers.WriteString("// Handlers implements the API; replace each stub with the real logic\ntype Handlers struct{}\n\n")
+
+	for _, op := range ops {
+		writeOperationDoc(handlers, op)
+		fmt.Fprintf(handlers, "func (h *Handlers) %s(c echo.Context) error {\n", op.name)
+		if op.body != "" {
+			fmt.Fprintf(handlers, "\tvar body %s\n", op.body)
+			handlers.WriteString("\tif err := c.Bind(&body); err != nil {\n")
+			handlers.WriteString("\t\treturn echo.NewHTTPError(http.StatusBadRequest, err.Error())\n\t}\n\n")
+		}
+		handlers.WriteString("\treturn echo.NewHTTPError(http.StatusNotImplemented, \"not implemented\")\n}\n\n")
+	}
+
+	routes.WriteString("// RegisterRoutes mounts every operation of the API on g\n")
+	routes.WriteString("func RegisterRoutes(g *echo.Group, h *Handlers) {\n")
+	for _, op := range ops {
+		fmt.Fprintf(routes, "\tg.Add(%q, %q, h.%s)\n", op.method, routePath(op.path), op.name)
+	}
+	routes.WriteString("}\n")
+}
@@ -0,0 +1,163 @@
+package codegen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Languages supported by Snippet
+const (
+	SnippetCurl       = "curl"
+	SnippetJavaScript = "javascript"
+	SnippetPython     = "python"
+	SnippetGo         = "go"
+)
+
+// SnippetLanguages lists the snippet languages in the order documentation shows them
+var SnippetLanguages = []string{SnippetCurl, SnippetJavaScript, SnippetPython, SnippetGo}
+
+// snippetLabels are the headings documentation gives each language
+var snippetLabels = map[string]string{
+	SnippetCurl:       "cURL",
+	SnippetJavaScript: "JavaScript (fetch)",
+	SnippetPython:     "Python (requests)",
+	SnippetGo:         "Go (net/http)",
+}
+
+// SnippetRequest is a resolved HTTP request to write a snippet for
+type SnippetRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// Snippet writes code sending req in language, or an empty string for an
+// unsupported language
+func Snippet(req SnippetRequest, language string) string {
+	switch language {
+	case SnippetCurl:
+		return curlSnippet(req)
+	case SnippetJavaScript:
+		return javaScriptSnippet(req)
+	case SnippetPython:
+		return pythonSnippet(req)
+	case SnippetGo:
+		return goSnippet(req)
+	default:
+		return ""
+	}
+}
+
+func curlSnippet(req SnippetRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -X %s %s", req.Method, shellQuote(req.URL))
+	for _, name := range headerNames(req.Headers) {
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote(name+": "+req.Headers[name]))
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, " \\\n  --data-raw %s", shellQuote(req.Body))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func javaScriptSnippet(req SnippetRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n", strconv.Quote(req.URL))
+	fmt.Fprintf(&b, "  method: %s,\n", strconv.Quote(req.Method))
+	if len(req.Headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, name := range headerNames(req.Headers) {
+			fmt.Fprintf(&b, "    %s: %s,\n", strconv.Quote(name), strconv.Quote(req.Headers[name]))
+		}
+		b.WriteString("  },\n")
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, "  body: %s,\n", strconv.Quote(req.Body))
+	}
+	b.WriteString("});\n")
+	b.WriteString("console.log(response.status, await response.text());\n")
+	return b.String()
+}
+
+func pythonSnippet(req SnippetRequest) string {
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+	if len(req.Headers) > 0 {
+		b.WriteString("headers = {\n")
+		for _, name := range headerNames(req.Headers) {
+			fmt.Fprintf(&b, "    %s: %s,\n", strconv.Quote(name), strconv.Quote(req.Headers[name]))
+		}
+		b.WriteString("}\n")
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, "data = %s\n", strconv.Quote(req.Body))
+	}
+	if len(req.Headers) > 0 || req.Body != "" {
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "response = requests.request(%s, %s", strconv.Quote(req.Method), strconv.Quote(req.URL))
+	if len(req.Headers) > 0 {
+		b.WriteString(", headers=headers")
+	}
+	if req.Body != "" {
+		b.WriteString(", data=data")
+	}
+	b.WriteString(")\n")
+	b.WriteString("print(response.status_code, response.text)\n")
+	return b.String()
+}
+
+func goSnippet(req SnippetRequest) string {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if req.Body != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if req.Body != "" {
+		body = fmt.Sprintf("strings.NewReader(%s)", goStringLiteral(req.Body))
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(req.Method), strconv.Quote(req.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, name := range headerNames(req.Headers) {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", strconv.Quote(name), strconv.Quote(req.Headers[name]))
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, _ := io.ReadAll(resp.Body)\n")
+	b.WriteString("\tfmt.Println(resp.Status, string(data))\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// headerNames returns the names of headers in a stable order
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// goStringLiteral quotes s as a raw string literal when it can be one,
+// keeping multi-line bodies readable
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
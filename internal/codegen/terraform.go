@@ -0,0 +1,214 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Terraform providers supported by Terraform
+const (
+	TerraformAWS  = "aws"
+	TerraformKong = "kong"
+)
+
+// hclAttr is a single attribute of a Terraform block
+type hclAttr struct {
+	key   string
+	value string
+}
+
+// ValidTerraformProvider reports whether Terraform can emit resources for provider
+func ValidTerraformProvider(provider string) bool {
+	return provider == TerraformAWS || provider == TerraformKong
+}
+
+// Terraform renders resources that provision the document's operations,
+// proxied to its first server: an AWS API Gateway REST API or Kong services
+// and routes
+func Terraform(doc map[string]any, provider string) ([]byte, error) {
+	if !ValidTerraformProvider(provider) {
+		return nil, fmt.Errorf("unsupported terraform provider %q", provider)
+	}
+
+	upstream := ServerURL(doc)
+	if !strings.Contains(upstream, "://") {
+		return nil, errors.New("document declares no absolute server URL to proxy to")
+	}
+
+	var b strings.Builder
+	b.WriteString("# Code generated from the OpenAPI specification; DO NOT EDIT.\n")
+
+	switch provider {
+	case TerraformAWS:
+		writeAWSTerraform(&b, doc, upstream)
+	case TerraformKong:
+		if err := writeKongTerraform(&b, doc, upstream); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// writeAWSTerraform declares a REST API with a resource per path segment, a
+// method and HTTP proxy integration per operation, and a deployment that is
+// replaced whenever an integration changes
+func writeAWSTerraform(b *strings.Builder, doc map[string]any, upstream string) {
+	info, _ := doc["info"].(map[string]any)
+	title, _ := info["title"].(string)
+	if title == "" {
+		title = "api"
+	}
+
+	apiID := "aws_api_gateway_rest_api.api.id"
+	attrs := []hclAttr{{"name", hclString(title)}}
+	if description, _ := info["description"].(string); description != "" {
+		attrs = append(attrs, hclAttr{"description", hclString(description)})
+	}
+	writeHCLBlock(b, `resource "aws_api_gateway_rest_api" "api"`, attrs)
+
+	// resources maps a path prefix to the reference of its resource id
+	resources := map[string]string{"": "aws_api_gateway_rest_api.api.root_resource_id"}
+	resourceFor := func(path string) string {
+		prefix := ""
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			if segment == "" {
+				continue
+			}
+			parent := resources[prefix]
+			prefix += "/" + segment
+			if _, ok := resources[prefix]; ok {
+				continue
+			}
+
+			name := snakeCase(prefix)
+			writeHCLBlock(b, fmt.Sprintf("resource \"aws_api_gateway_resource\" %q", name), []hclAttr{
+				{"rest_api_id", apiID},
+				{"parent_id", parent},
+				{"path_part", hclString(segment)},
+			})
+			resources[prefix] = "aws_api_gateway_resource." + name + ".id"
+		}
+		return resources[prefix]
+	}
+
+	var integrations []string
+	for _, op := range collectOperations(doc) {
+		resourceID := resourceFor(op.path)
+		name := snakeCase(op.name)
+
+		var methodParams, integrationParams []hclAttr
+		for _, match := range pathParamPattern.FindAllStringSubmatch(op.path, -1) {
+			methodParams = append(methodParams, hclAttr{hclString("method.request.path." + match[1]), "true"})
+			integrationParams = append(integrationParams, hclAttr{
+				hclString("integration.request.path." + match[1]),
+				hclString("method.request.path." + match[1]),
+			})
+		}
+
+		writeHCLBlock(b, fmt.Sprintf("resource \"aws_api_gateway_method\" %q", name), []hclAttr{
+			{"rest_api_id", apiID},
+			{"resource_id", resourceID},
+			{"http_method", hclString(op.method)},
+			{"authorization", hclString("NONE")},
+		}, methodParams...)
+
+		writeHCLBlock(b, fmt.Sprintf("resource \"aws_api_gateway_integration\" %q", name), []hclAttr{
+			{"rest_api_id", apiID},
+			{"resource_id", resourceID},
+			{"http_method", "aws_api_gateway_method." + name + ".http_method"},
+			{"type", hclString("HTTP_PROXY")},
+			{"integration_http_method", hclString(op.method)},
+			{"uri", hclString(upstream + op.path)},
+		}, integrationParams...)
+
+		integrations = append(integrations, "aws_api_gateway_integration."+name+".id")
+	}
+
+	fmt.Fprintf(b, "\nresource \"aws_api_gateway_deployment\" \"api\" {\n")
+	fmt.Fprintf(b, "  rest_api_id = %s\n\n", apiID)
+	fmt.Fprintf(b, "  triggers = {\n    redeployment = sha1(jsonencode([\n")
+	for _, ref := range integrations {
+		fmt.Fprintf(b, "      %s,\n", ref)
+	}
+	fmt.Fprintf(b, "    ]))\n  }\n\n")
+	fmt.Fprintf(b, "  lifecycle {\n    create_before_destroy = true\n  }\n}\n")
+}
+
+// writeKongTerraform declares a Kong service for the upstream and a route per operation
+func writeKongTerraform(b *strings.Builder, doc map[string]any, upstream string) error {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return fmt.Errorf("invalid server URL %q: %w", upstream, err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	serviceName := kongServiceName(doc)
+	serviceRef := snakeCase(serviceName)
+
+	attrs := []hclAttr{
+		{"name", hclString(serviceName)},
+		{"protocol", hclString(u.Scheme)},
+		{"host", hclString(u.Hostname())},
+		{"port", port},
+	}
+	if u.Path != "" {
+		attrs = append(attrs, hclAttr{"path", hclString(u.Path)})
+	}
+	writeHCLBlock(b, fmt.Sprintf("resource \"kong_service\" %q", serviceRef), attrs)
+
+	for _, op := range collectOperations(doc) {
+		writeHCLBlock(b, fmt.Sprintf("resource \"kong_route\" %q", snakeCase(op.name)), []hclAttr{
+			{"name", hclString(serviceName + "." + op.name)},
+			{"protocols", `["http", "https"]`},
+			{"methods", "[" + hclString(op.method) + "]"},
+			{"paths", "[" + hclString(kongPath(op.path)) + "]"},
+			{"strip_path", "false"},
+			{"service_id", "kong_service." + serviceRef + ".id"},
+		})
+	}
+
+	return nil
+}
+
+// writeHCLBlock writes a block with its attributes aligned the way terraform
+// fmt does, followed by a request_parameters map when params are given
+func writeHCLBlock(b *strings.Builder, header string, attrs []hclAttr, params ...hclAttr) {
+	fmt.Fprintf(b, "\n%s {\n", header)
+	writeHCLAttrs(b, "  ", attrs)
+
+	if len(params) > 0 {
+		b.WriteString("\n  request_parameters = {\n")
+		writeHCLAttrs(b, "    ", params)
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+}
+
+func writeHCLAttrs(b *strings.Builder, indent string, attrs []hclAttr) {
+	width := 0
+	for _, attr := range attrs {
+		width = max(width, len(attr.key))
+	}
+	for _, attr := range attrs {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, attr.key, attr.value)
+	}
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences
+func hclString(s string) string {
+	quoted := strconv.Quote(s)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
@@ -0,0 +1,205 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript renders a .d.ts declaration file with one interface or type alias
+// per component schema of the document
+func TypeScript(doc map[string]any) []byte {
+	var b strings.Builder
+
+	title := "API"
+	if info, ok := doc["info"].(map[string]any); ok {
+		if t, ok := info["title"].(string); ok && t != "" {
+			title = t
+		}
+	}
+	fmt.Fprintf(&b, "// Type definitions for %s\n// Generated from the OpenAPI specification; do not edit.\n", title)
+
+	schemas := componentSchemas(doc)
+	for _, name := range sortedKeys(schemas) {
+		schema, ok := schemas[name].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		b.WriteString("\n")
+		writeTSDoc(&b, "", schema)
+
+		if _, hasProps := schema["properties"]; hasProps && !isComposite(schema) {
+			fmt.Fprintf(&b, "export interface %s ", typeName(name))
+			writeTSObject(&b, "", schema)
+			b.WriteString("\n")
+			continue
+		}
+
+		fmt.Fprintf(&b, "export type %s = %s;\n", typeName(name), tsType(schema, ""))
+	}
+
+	return []byte(b.String())
+}
+
+func isComposite(schema map[string]any) bool {
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if _, ok := schema[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func tsType(schema map[string]any, indent string) string {
+	t := tsBaseType(schema, indent)
+	if isNullable(schema) && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func tsBaseType(schema map[string]any, indent string) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return typeName(refName(ref))
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		literals := make([]string, 0, len(enum))
+		for _, v := range enum {
+			if v == nil {
+				continue
+			}
+			literal, _ := json.Marshal(v)
+			literals = append(literals, string(literal))
+		}
+		if len(literals) > 0 {
+			return strings.Join(literals, " | ")
+		}
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		return tsJoin(all, " & ", indent)
+	}
+	if one, ok := schema["oneOf"].([]any); ok {
+		return tsJoin(one, " | ", indent)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		return tsJoin(anyOf, " | ", indent)
+	}
+
+	typ, _ := schemaType(schema)
+	switch typ {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return "unknown[]"
+		}
+		item := tsType(items, indent)
+		if strings.ContainsAny(item, "|&") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object", "":
+		if _, ok := schema["properties"]; ok {
+			var b strings.Builder
+			writeTSObject(&b, indent, schema)
+			return b.String()
+		}
+		if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+			return "Record<string, " + tsType(additional, indent) + ">"
+		}
+		if typ == "object" {
+			return "Record<string, unknown>"
+		}
+	}
+
+	return "unknown"
+}
+
+func tsJoin(schemas []any, sep, indent string) string {
+	parts := make([]string, 0, len(schemas))
+	for _, s := range schemas {
+		if schema, ok := s.(map[string]any); ok {
+			part := tsType(schema, indent)
+			if strings.Contains(part, " ") && !strings.HasPrefix(part, "{") {
+				part = "(" + part + ")"
+			}
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(parts, sep)
+}
+
+func writeTSObject(b *strings.Builder, indent string, schema map[string]any) {
+	properties, _ := schema["properties"].(map[string]any)
+	required := requiredSet(schema)
+	inner := indent + "  "
+
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(properties) {
+		prop, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		writeTSDoc(b, inner, prop)
+
+		key := name
+		if !tsIdentifier.MatchString(name) {
+			quoted, _ := json.Marshal(name)
+			key = string(quoted)
+		}
+		if !required[name] {
+			key += "?"
+		}
+
+		fmt.Fprintf(b, "%s%s%s: %s;\n", inner, readonlyPrefix(prop), key, tsType(prop, inner))
+	}
+
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		fmt.Fprintf(b, "%s[key: string]: %s;\n", inner, tsType(additional, inner))
+	}
+
+	b.WriteString(indent + "}")
+}
+
+func readonlyPrefix(schema map[string]any) string {
+	if readOnly, _ := schema["readOnly"].(bool); readOnly {
+		return "readonly "
+	}
+	return ""
+}
+
+func writeTSDoc(b *strings.Builder, indent string, schema map[string]any) {
+	description, _ := schema["description"].(string)
+	if strings.TrimSpace(description) == "" {
+		return
+	}
+
+	lines := commentLines(strings.ReplaceAll(description, "*/", "*\\/"))
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
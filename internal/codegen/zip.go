@@ -0,0 +1,35 @@
+package codegen
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Zip packs generated files into a zip archive under dir
+func Zip(dir string, files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := archive.Create(dir + "/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return nil, fmt.Errorf("failed to write %s to archive: %w", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -1,77 +1,687 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
+	"postman-api/internal/codegen"
+	"postman-api/internal/events"
+	"postman-api/internal/models"
+	"postman-api/internal/secrets"
+	"postman-api/internal/signing"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Hooks         HooksConfig
+	Storage       StorageConfig
+	Secrets       SecretsConfig
+	Import        ImportConfig
+	Retention     RetentionConfig
+	Outbound      OutboundConfig
+	Events        EventsConfig
+	Jobs          JobsConfig
+	Variables     VariablesConfig
+	Exports       ExportsConfig
+	CatalogMirror CatalogMirrorConfig
+	Review        ReviewConfig
+	Digests       DigestsConfig
+	Promotion     PromotionConfig
+	Runner        RunnerConfig
 }
 
 type ServerConfig struct {
-	Port         string
+	Port string
+	// Socket, when set, serves on this unix socket path instead of Port
+	Socket       string
+	SocketMode   os.FileMode
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// ReadHeaderTimeout bounds reading request headers; zero falls back to ReadTimeout
+	ReadHeaderTimeout time.Duration
+	// MaxHeaderBytes caps request header size; zero uses the net/http default of 1MB
+	MaxHeaderBytes int
+	// H2C serves HTTP/2 without TLS alongside HTTP/1.1, for multiplexing clients
+	H2C bool
+	// H2MaxConcurrentStreams limits streams per HTTP/2 connection; zero uses the default
+	H2MaxConcurrentStreams int
+
+	// DefaultPageSize and MaxPageSize bound the page_size of list endpoints
+	DefaultPageSize int
+	MaxPageSize     int
+
+	Features []string
+	// SeedDir, when set, is loaded with example fixtures at startup
+	SeedDir string
+	// ShutdownTimeout bounds how long in-flight requests and jobs may run
+	// after a shutdown signal before they are interrupted
+	ShutdownTimeout time.Duration
+
+	// BasePath, when set, serves every route under this path prefix, e.g.
+	// "/postman-api", for proxies that forward the prefix
+	BasePath string
+	// ExternalURL, when set, is the scheme, host and any path a proxy strips
+	// that clients reach the server at; links in responses are made absolute to it
+	ExternalURL string
+}
+
+type HooksConfig struct {
+	// ImportSecret enables POST /api/v1/hooks/import when set
+	ImportSecret string
+}
+
+type SecretsConfig struct {
+	// Key encrypts secret environment variables at rest; secrets are rejected while it is unset
+	Key []byte
+}
+
+type ImportConfig struct {
+	// Deduplicate returns the existing document when a byte-identical one is imported again
+	Deduplicate bool
+}
+
+// OutboundConfig is the one proxy of the whole process: there are no
+// workspaces to hold proxy settings of their own, so every collection run,
+// spec source poll, import hook fetch and digest goes through the same one
+type OutboundConfig struct {
+	// ProxyURL routes outbound traffic through an HTTP(S) or SOCKS5 proxy;
+	// when unset the HTTP_PROXY family of environment variables applies
+	ProxyURL string
+	// NoProxy lists hosts, domains and CIDRs reached without the proxy
+	NoProxy []string
+}
+
+type EventsConfig struct {
+	// Broker publishes entity-change events through the outbox to "nats" or
+	// "kafka"; when unset events are only logged. Kafka is reached only
+	// through a Kafka REST proxy, whose base URL BrokerURL then is: there is
+	// no native Kafka client.
+	Broker    string
+	BrokerURL string
+	// NATS holds the token, nkey, credentials and TLS files of the NATS
+	// connection
+	NATS events.NATSOptions
+	// TopicPrefix names topics, and NATS subjects, as prefix.entity_type
+	TopicPrefix string
+	// RelayInterval is how often pending outbox events are delivered
+	RelayInterval time.Duration
+}
+
+type JobsConfig struct {
+	// Workers is the number of jobs run concurrently; zero disables the workers
+	Workers      int
+	PollInterval time.Duration
+	// MaxAttempts is how many times a failing job runs before it is left dead
+	MaxAttempts int
+}
+
+type RunnerConfig struct {
+	// RequestTimeout bounds each request sent by the runner that neither it
+	// nor its run gives a timeout for
+	RequestTimeout time.Duration
+}
+
+type VariablesConfig struct {
+	// Globals are variables available to every collection, overridden by
+	// global variables managed through the API and by collection and
+	// environment variables of the same name
+	Globals map[string]string
+}
+
+type ExportsConfig struct {
+	// SigningKey enables signed exports; it is the HMAC secret or the seed
+	// of the Ed25519 private key, depending on SigningAlgorithm
+	SigningKey       []byte
+	SigningAlgorithm string
+	// Exporter identifies this catalog in the manifest of signed exports
+	Exporter string
+}
+
+// CatalogMirrorConfig exports every collection as a JSON file below Dir, for
+// review and version control. The mirror is write-only: collections are
+// always stored in and read from the database, and edits to the files are
+// overwritten.
+type CatalogMirrorConfig struct {
+	// Dir enables the mirror when set
+	Dir string
+	// FlushInterval is how often changed collections are written to their files
+	FlushInterval time.Duration
+}
+
+type DigestsConfig struct {
+	// SMTPAddr is the host:port of the mail server email digests are sent
+	// through; email subscriptions are rejected while it is unset
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	// From is the sender address of digest emails
+	From string
+}
+
+type PromotionConfig struct {
+	// Labels are the environments of record specs are promoted through, in
+	// order; a spec enters the first once approved and moves one label on
+	// per promotion
+	Labels []string
+	// Exports lists the downstream exports run when a spec is promoted to a
+	// label, as gateway:<kong|aws|google> or docs:<markdown|html>
+	Exports map[string][]string
+	// Dir receives the exports, as <label>/<spec id>/<file>
+	Dir string
+}
+
+type ReviewConfig struct {
+	// RequiredApprovals is how many reviewers must approve a spec; when set,
+	// gateway exports of a spec wait until it is approved
+	RequiredApprovals int
+}
+
+type RetentionConfig struct {
+	// ArchivedCollections purges collections archived for longer than this; zero keeps them
+	ArchivedCollections time.Duration
+	// SupersededSpecs purges spec source imports replaced by a newer import and older than this; zero keeps them
+	SupersededSpecs time.Duration
+	// Interval is how often the retention policy is enforced
+	Interval time.Duration
+}
+
+type StorageConfig struct {
+	// Dir holds attachment content, addressed by hash
+	Dir string
+	// MaxAttachmentBytes caps the size of a single attachment
+	MaxAttachmentBytes int64
+	// ResponseOffloadBytes moves saved response bodies above this size out of the database
+	ResponseOffloadBytes int64
+	// ResponseMaxBytes truncates saved response bodies; zero keeps them whole
+	ResponseMaxBytes int64
+	// ResponseRetain keeps the last N saved responses per request; zero keeps all
+	ResponseRetain int
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-	DSN      string
+	// Driver is "postgres", or "sqlite" to keep everything in the single
+	// file at SQLitePath, for local use and CI without a PostgreSQL server.
+	// SQLite serves one replica at a time and has no read replica.
+	Driver     string
+	SQLitePath string
+	Host       string
+	Port       int
+	User       string
+	Password   string
+	DBName     string
+	SSLMode    string
+	DSN        string
+	// ReplicaDSN optionally points at a read replica used while the primary is unavailable
+	ReplicaDSN string
+	// QueryTimeout bounds each query; zero or negative disables the bound
+	QueryTimeout time.Duration
+	// RetryAttempts bounds how often idempotent reads are tried on transient errors
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	// BreakerThreshold consecutive transient failures open the circuit for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
 }
 
+// Database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Defaults used when the environment leaves a setting unset
+const (
+	DefaultPort            = "8080"
+	DefaultReadTimeout     = 10 * time.Second
+	DefaultWriteTimeout    = 10 * time.Second
+	DefaultIdleTimeout     = 120 * time.Second
+	DefaultShutdownTimeout = 30 * time.Second
+
+	DefaultPageSize    = 10
+	DefaultMaxPageSize = 100
+
+	DefaultDBDriver     = DriverPostgres
+	DefaultDBSQLitePath = "data/postman-api.db"
+	DefaultDBHost       = "localhost"
+	DefaultDBPort       = 5432
+	DefaultSSLMode      = "prefer"
+
+	DefaultQueryTimeout     = 10 * time.Second
+	DefaultRetryAttempts    = 3
+	DefaultRetryBaseDelay   = 50 * time.Millisecond
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+
+	DefaultStorageDir         = "data/blobs"
+	DefaultMaxAttachmentBytes = 10 << 20
+
+	DefaultResponseOffloadBytes = 64 << 10
+
+	DefaultRetentionInterval = time.Hour
+
+	DefaultEventsTopicPrefix   = "postman-api"
+	DefaultEventsRelayInterval = 5 * time.Second
+
+	DefaultExportSigningAlgorithm = signing.AlgorithmHMACSHA256
+	DefaultExporter               = "postman-api"
+
+	DefaultJobWorkers      = 4
+	DefaultJobPollInterval = time.Second
+	DefaultJobMaxAttempts  = 5
+
+	DefaultCatalogMirrorFlushInterval = 2 * time.Second
+
+	DefaultPromotionDir = "data/promotions"
+
+	DefaultRunnerRequestTimeout = 30 * time.Second
+)
+
+// DefaultPromotionLabels are the labels specs are promoted through unless
+// PROMOTION_LABELS says otherwise
+var DefaultPromotionLabels = []string{"dev", "staging", "prod"}
+
+// Default returns a configuration with the database resilience defaults set,
+// for programs that build their configuration without the environment
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            DefaultPort,
+			ReadTimeout:     DefaultReadTimeout,
+			WriteTimeout:    DefaultWriteTimeout,
+			IdleTimeout:     DefaultIdleTimeout,
+			DefaultPageSize: DefaultPageSize,
+			MaxPageSize:     DefaultMaxPageSize,
+			ShutdownTimeout: DefaultShutdownTimeout,
+		},
+		Database: DatabaseConfig{
+			Host:    DefaultDBHost,
+			Port:    DefaultDBPort,
+			SSLMode: DefaultSSLMode,
+
+			QueryTimeout:     DefaultQueryTimeout,
+			RetryAttempts:    DefaultRetryAttempts,
+			RetryBaseDelay:   DefaultRetryBaseDelay,
+			BreakerThreshold: DefaultBreakerThreshold,
+			BreakerCooldown:  DefaultBreakerCooldown,
+		},
+		Storage: StorageConfig{
+			Dir:                DefaultStorageDir,
+			MaxAttachmentBytes: DefaultMaxAttachmentBytes,
+
+			ResponseOffloadBytes: DefaultResponseOffloadBytes,
+		},
+		Retention: RetentionConfig{
+			Interval: DefaultRetentionInterval,
+		},
+		Events: EventsConfig{
+			TopicPrefix:   DefaultEventsTopicPrefix,
+			RelayInterval: DefaultEventsRelayInterval,
+		},
+		Jobs: JobsConfig{
+			Workers:      DefaultJobWorkers,
+			PollInterval: DefaultJobPollInterval,
+			MaxAttempts:  DefaultJobMaxAttempts,
+		},
+		Exports: ExportsConfig{
+			SigningAlgorithm: DefaultExportSigningAlgorithm,
+			Exporter:         DefaultExporter,
+		},
+		CatalogMirror: CatalogMirrorConfig{
+			FlushInterval: DefaultCatalogMirrorFlushInterval,
+		},
+		Promotion: PromotionConfig{
+			Labels: DefaultPromotionLabels,
+			Dir:    DefaultPromotionDir,
+		},
+		Runner: RunnerConfig{
+			RequestTimeout: DefaultRunnerRequestTimeout,
+		},
+	}
+}
+
+// Load reads the configuration from the environment, an optional .env file
+// and the optional YAML or JSON file named by CONFIG_FILE, in that order of
+// precedence, reporting every missing or invalid setting at once
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("no .env found")
 	}
 
-	dbPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
-	if err != nil {
-		return nil, fmt.Errorf("could not find DB_PORT %w", err)
+	l := &loader{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file = file
 	}
 
-	dbConfig := DatabaseConfig{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     dbPort,
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		DBName:   os.Getenv("DB_NAME"),
-		SSLMode:  os.Getenv("DB_SSL_MODE"),
+	dbConfig := l.database()
+
+	var secretsKey []byte
+	if raw := l.get("SECRETS_KEY"); raw != "" {
+		key, err := secrets.ParseKey(raw)
+		if err != nil {
+			l.errorf("invalid SECRETS_KEY: %v", err)
+		}
+		secretsKey = key
 	}
 
-	dbConfig.DSN = fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password,
-		dbConfig.DBName, dbConfig.SSLMode,
-	)
+	proxyURL := l.get("OUTBOUND_PROXY")
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			l.invalid("OUTBOUND_PROXY", proxyURL, "must be an http, https or socks5 URL")
+		}
+	}
+
+	eventsBroker := l.get("EVENTS_BROKER")
+	if eventsBroker != "" {
+		if !events.ValidBroker(eventsBroker) {
+			l.invalid("EVENTS_BROKER", eventsBroker, "must be nats or kafka")
+		}
+		if l.get("EVENTS_BROKER_URL") == "" {
+			l.errorf("EVENTS_BROKER_URL is required when EVENTS_BROKER is set")
+		}
+	}
+	if (l.get("EVENTS_NATS_CERT_FILE") == "") != (l.get("EVENTS_NATS_KEY_FILE") == "") {
+		l.errorf("EVENTS_NATS_CERT_FILE and EVENTS_NATS_KEY_FILE must be set together")
+	}
+
+	signingAlgorithm := l.getDefault("EXPORT_SIGNING_ALGORITHM", DefaultExportSigningAlgorithm)
+	var signingKey []byte
+	if raw := l.get("EXPORT_SIGNING_KEY"); raw != "" {
+		key, err := signing.ParseKey(raw)
+		if err == nil {
+			_, err = signing.NewSigner(signingAlgorithm, key)
+		}
+		if err != nil {
+			l.errorf("invalid EXPORT_SIGNING_KEY: %v", err)
+		}
+		signingKey = key
+	}
+
+	basePath := strings.TrimSuffix(l.get("BASE_PATH"), "/")
+	if basePath != "" && (!strings.HasPrefix(basePath, "/") || strings.ContainsAny(basePath, "?#:*") || strings.Contains(basePath, "//")) {
+		l.invalid("BASE_PATH", l.get("BASE_PATH"), "must be a path such as /postman-api")
+	}
+
+	externalURL := strings.TrimSuffix(l.get("EXTERNAL_URL"), "/")
+	if externalURL != "" {
+		u, err := url.Parse(externalURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") || u.RawQuery != "" || u.Fragment != "" {
+			l.invalid("EXTERNAL_URL", externalURL, "must be an http or https URL without a query")
+		}
+	}
+
+	smtpAddr := l.get("SMTP_ADDR")
+	if smtpAddr != "" {
+		if _, _, err := net.SplitHostPort(smtpAddr); err != nil {
+			l.invalid("SMTP_ADDR", smtpAddr, "must be host:port")
+		}
+		if _, err := mail.ParseAddress(l.get("DIGEST_FROM")); err != nil {
+			l.errorf("DIGEST_FROM must be an email address when SMTP_ADDR is set")
+		}
+	}
+
+	promotion := l.promotion()
+
+	var globals map[string]string
+	if raw := l.get("GLOBAL_VARIABLES"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &globals); err != nil {
+			l.errorf("invalid GLOBAL_VARIABLES: must be a JSON object of strings: %v", err)
+		}
+	}
 
 	config := &Config{
 		Server: ServerConfig{
-			Port:         os.Getenv("SERVER_PORT"),
-			ReadTimeout:  parseDuration(os.Getenv("READ_TIMEOUT")),
-			WriteTimeout: parseDuration(os.Getenv("WRITE_TIMEOUT")),
+			Port:         l.getDefault("SERVER_PORT", DefaultPort),
+			Socket:       l.get("SERVER_SOCKET"),
+			SocketMode:   l.fileMode("SERVER_SOCKET_MODE", 0o660),
+			ReadTimeout:  l.duration("READ_TIMEOUT", DefaultReadTimeout),
+			WriteTimeout: l.duration("WRITE_TIMEOUT", DefaultWriteTimeout),
+			IdleTimeout:  l.duration("IDLE_TIMEOUT", DefaultIdleTimeout),
+
+			ReadHeaderTimeout:      l.duration("READ_HEADER_TIMEOUT", 0),
+			MaxHeaderBytes:         l.integer("MAX_HEADER_BYTES", 0),
+			H2C:                    l.boolean("SERVER_H2C"),
+			H2MaxConcurrentStreams: l.integer("H2_MAX_CONCURRENT_STREAMS", 0),
+
+			DefaultPageSize: l.integer("DEFAULT_PAGE_SIZE", DefaultPageSize),
+			MaxPageSize:     l.integer("MAX_PAGE_SIZE", DefaultMaxPageSize),
+
+			Features: l.list("FEATURE_FLAGS"),
+			SeedDir:  l.get("SEED_DIR"),
+
+			ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", DefaultShutdownTimeout),
+
+			BasePath:    basePath,
+			ExternalURL: externalURL,
 		},
 		Database: dbConfig,
+		Hooks: HooksConfig{
+			ImportSecret: l.get("IMPORT_HOOK_SECRET"),
+		},
+		Storage: StorageConfig{
+			Dir:                l.getDefault("STORAGE_DIR", DefaultStorageDir),
+			MaxAttachmentBytes: int64(l.integer("ATTACHMENT_MAX_BYTES", DefaultMaxAttachmentBytes)),
+
+			ResponseOffloadBytes: int64(l.integer("RESPONSE_OFFLOAD_BYTES", DefaultResponseOffloadBytes)),
+			ResponseMaxBytes:     int64(l.integer("RESPONSE_MAX_BYTES", 0)),
+			ResponseRetain:       l.integer("RESPONSE_RETAIN", 0),
+		},
+		Secrets: SecretsConfig{
+			Key: secretsKey,
+		},
+		Import: ImportConfig{
+			Deduplicate: l.boolean("IMPORT_DEDUPLICATE"),
+		},
+		Retention: RetentionConfig{
+			ArchivedCollections: l.duration("RETENTION_ARCHIVED_COLLECTIONS", 0),
+			SupersededSpecs:     l.duration("RETENTION_SUPERSEDED_SPECS", 0),
+			Interval:            l.duration("RETENTION_INTERVAL", DefaultRetentionInterval),
+		},
+		Outbound: OutboundConfig{
+			ProxyURL: proxyURL,
+			NoProxy:  l.list("OUTBOUND_NO_PROXY"),
+		},
+		Events: EventsConfig{
+			Broker:    eventsBroker,
+			BrokerURL: l.get("EVENTS_BROKER_URL"),
+			NATS: events.NATSOptions{
+				Token:           l.get("EVENTS_NATS_TOKEN"),
+				NKeySeedFile:    l.get("EVENTS_NATS_NKEY_SEED_FILE"),
+				CredentialsFile: l.get("EVENTS_NATS_CREDS_FILE"),
+				CAFile:          l.get("EVENTS_NATS_CA_FILE"),
+				CertFile:        l.get("EVENTS_NATS_CERT_FILE"),
+				KeyFile:         l.get("EVENTS_NATS_KEY_FILE"),
+			},
+			TopicPrefix:   l.getDefault("EVENTS_TOPIC_PREFIX", DefaultEventsTopicPrefix),
+			RelayInterval: l.duration("EVENTS_RELAY_INTERVAL", DefaultEventsRelayInterval),
+		},
+		Jobs: JobsConfig{
+			Workers:      l.integer("JOB_WORKERS", DefaultJobWorkers),
+			PollInterval: l.duration("JOB_POLL_INTERVAL", DefaultJobPollInterval),
+			MaxAttempts:  l.integer("JOB_MAX_ATTEMPTS", DefaultJobMaxAttempts),
+		},
+		Variables: VariablesConfig{
+			Globals: globals,
+		},
+		Exports: ExportsConfig{
+			SigningKey:       signingKey,
+			SigningAlgorithm: signingAlgorithm,
+			Exporter:         l.getDefault("EXPORT_EXPORTER", DefaultExporter),
+		},
+		CatalogMirror: CatalogMirrorConfig{
+			Dir:           l.get("CATALOG_MIRROR_DIR"),
+			FlushInterval: l.duration("CATALOG_MIRROR_FLUSH_INTERVAL", DefaultCatalogMirrorFlushInterval),
+		},
+		Review: ReviewConfig{
+			RequiredApprovals: l.integer("REVIEW_REQUIRED_APPROVALS", 0),
+		},
+		Digests: DigestsConfig{
+			SMTPAddr:     smtpAddr,
+			SMTPUsername: l.get("SMTP_USERNAME"),
+			SMTPPassword: l.get("SMTP_PASSWORD"),
+			From:         l.get("DIGEST_FROM"),
+		},
+		Promotion: promotion,
+		Runner: RunnerConfig{
+			RequestTimeout: l.duration("RUNNER_REQUEST_TIMEOUT", DefaultRunnerRequestTimeout),
+		},
+	}
+
+	if config.Server.MaxPageSize < config.Server.DefaultPageSize {
+		l.errorf("MAX_PAGE_SIZE %d must not be below DEFAULT_PAGE_SIZE %d", config.Server.MaxPageSize, config.Server.DefaultPageSize)
+	}
+
+	if config.Runner.RequestTimeout <= 0 {
+		l.errorf("RUNNER_REQUEST_TIMEOUT must be positive")
+	}
+
+	if err := l.err(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
 }
 
-func parseDuration(s string) time.Duration {
-	duration, err := time.ParseDuration(s)
-	if err != nil {
-		return 10 * time.Second
+// database reads the database settings from DATABASE_URL when it is set, and
+// otherwise from the DB_* settings, of which DB_USER and DB_NAME are required
+func (l *loader) database() DatabaseConfig {
+	dbConfig := DatabaseConfig{
+		Driver:  l.getDefault("DB_DRIVER", DefaultDBDriver),
+		Host:    l.getDefault("DB_HOST", DefaultDBHost),
+		Port:    l.integer("DB_PORT", DefaultDBPort),
+		SSLMode: l.getDefault("DB_SSL_MODE", DefaultSSLMode),
+
+		ReplicaDSN: l.get("DB_REPLICA_DSN"),
+
+		QueryTimeout: l.duration("DB_QUERY_TIMEOUT", DefaultQueryTimeout),
+
+		RetryAttempts:    l.integer("DB_RETRY_ATTEMPTS", DefaultRetryAttempts),
+		RetryBaseDelay:   l.duration("DB_RETRY_BASE_DELAY", DefaultRetryBaseDelay),
+		BreakerThreshold: l.integer("DB_BREAKER_THRESHOLD", DefaultBreakerThreshold),
+		BreakerCooldown:  l.duration("DB_BREAKER_COOLDOWN", DefaultBreakerCooldown),
+	}
+
+	switch dbConfig.Driver {
+	case DriverPostgres:
+	case DriverSQLite:
+		if dbConfig.ReplicaDSN != "" {
+			l.errorf("DB_REPLICA_DSN is not supported with DB_DRIVER=sqlite")
+		}
+		dbConfig.SQLitePath = l.getDefault("DB_SQLITE_PATH", DefaultDBSQLitePath)
+		dbConfig.DSN = sqliteDSN(dbConfig.SQLitePath)
+		return dbConfig
+	default:
+		l.invalid("DB_DRIVER", dbConfig.Driver, "must be postgres or sqlite")
+		return dbConfig
 	}
-	return duration
+
+	if raw := l.get("DATABASE_URL"); raw != "" {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			// The URL may carry a password, so it is left out of the message
+			l.errorf("invalid DATABASE_URL: must be a postgres:// URL")
+			return dbConfig
+		}
+
+		dbConfig.DSN = raw
+		dbConfig.Host = u.Hostname()
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			dbConfig.Port = port
+		}
+		dbConfig.User = u.User.Username()
+		dbConfig.Password, _ = u.User.Password()
+		dbConfig.DBName = strings.TrimPrefix(u.Path, "/")
+		if sslMode := u.Query().Get("sslmode"); sslMode != "" {
+			dbConfig.SSLMode = sslMode
+		}
+		return dbConfig
+	}
+
+	dbConfig.User = l.require("DB_USER")
+	dbConfig.Password = l.get("DB_PASSWORD")
+	dbConfig.DBName = l.require("DB_NAME")
+
+	dbConfig.DSN = fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(dbConfig.Host), dbConfig.Port, dsnValue(dbConfig.User), dsnValue(dbConfig.Password),
+		dsnValue(dbConfig.DBName), dsnValue(dbConfig.SSLMode),
+	)
+	return dbConfig
+}
+
+// promotion reads the promotion labels and the exports run per label
+func (l *loader) promotion() PromotionConfig {
+	promotion := PromotionConfig{
+		Labels: l.list("PROMOTION_LABELS"),
+		Dir:    l.getDefault("PROMOTION_DIR", DefaultPromotionDir),
+	}
+	if len(promotion.Labels) == 0 {
+		promotion.Labels = DefaultPromotionLabels
+	}
+	for i, label := range promotion.Labels {
+		if slices.Contains(promotion.Labels[:i], label) {
+			l.errorf("invalid PROMOTION_LABELS: %s is listed twice", label)
+		}
+	}
+
+	raw := l.get("PROMOTION_EXPORTS")
+	if raw == "" {
+		return promotion
+	}
+	if err := json.Unmarshal([]byte(raw), &promotion.Exports); err != nil {
+		l.errorf("invalid PROMOTION_EXPORTS: must be a JSON object of label to list of exports: %v", err)
+		return promotion
+	}
+	for label, exports := range promotion.Exports {
+		if !slices.Contains(promotion.Labels, label) {
+			l.errorf("invalid PROMOTION_EXPORTS: %s is not a promotion label", label)
+		}
+		for _, export := range exports {
+			kind, arg, _ := strings.Cut(export, ":")
+			valid := (kind == models.PromotionExportGateway && codegen.ValidGateway(arg)) ||
+				(kind == models.PromotionExportDocs && codegen.ValidDocsFormat(arg))
+			if !valid {
+				l.errorf("invalid PROMOTION_EXPORTS: unsupported export %q for %s, use gateway:<kong|aws|google> or docs:<markdown|html>", export, label)
+			}
+		}
+	}
+
+	return promotion
+}
+
+// dsnValue quotes a keyword/value connection string value when it is empty
+// or holds spaces, quotes or backslashes
+func dsnValue(s string) string {
+	if s != "" && !strings.ContainsAny(s, ` '\`) {
+		return s
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// sqliteDSN returns the connection string of the SQLite database at path,
+// with foreign keys enforced, writers waiting on each other rather than
+// failing, and transactions taking the write lock when they begin so that
+// two of them cannot deadlock upgrading to it
+func sqliteDSN(path string) string {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(10000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_txlock", "immediate")
+	return "file:" + path + "?" + query.Encode()
 }
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loader reads settings from the environment, falling back to the config
+// file, and collects every problem it finds so that they are reported at once
+type loader struct {
+	file     map[string]string
+	problems []string
+}
+
+// readConfigFile reads a YAML or JSON file mapping setting names, spelled as
+// in the environment, to values. Lists are joined with commas and objects
+// encoded as JSON, the way the environment spells them.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// JSON documents are YAML documents as well
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[strings.ToUpper(key)] = strings.Join(items, ",")
+		case map[string]any:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %s: %w", path, key, err)
+			}
+			values[strings.ToUpper(key)] = string(encoded)
+		default:
+			values[strings.ToUpper(key)] = fmt.Sprint(v)
+		}
+	}
+
+	return values, nil
+}
+
+func (l *loader) get(key string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return l.file[key]
+}
+
+func (l *loader) getDefault(key, fallback string) string {
+	if value := l.get(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// require returns a setting, reporting it when it is unset
+func (l *loader) require(key string) string {
+	value := l.get(key)
+	if value == "" {
+		l.problems = append(l.problems, key+" is required")
+	}
+	return value
+}
+
+func (l *loader) errorf(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) invalid(key, value, expected string) {
+	l.errorf("invalid %s %q: %s", key, value, expected)
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	raw := l.get(key)
+	if raw == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		l.invalid(key, raw, "must be a duration such as 30s")
+		return fallback
+	}
+	return duration
+}
+
+// integer reads a non-negative integer
+func (l *loader) integer(key string, fallback int) int {
+	raw := l.get(key)
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		l.invalid(key, raw, "must be a non-negative integer")
+		return fallback
+	}
+	return n
+}
+
+func (l *loader) boolean(key string) bool {
+	raw := l.get(key)
+	if raw == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.invalid(key, raw, "must be true or false")
+	}
+	return b
+}
+
+func (l *loader) fileMode(key string, fallback os.FileMode) os.FileMode {
+	raw := l.get(key)
+	if raw == "" {
+		return fallback
+	}
+	mode, err := strconv.ParseUint(raw, 8, 32)
+	if err != nil {
+		l.invalid(key, raw, "must be an octal file mode such as 0660")
+		return fallback
+	}
+	return os.FileMode(mode)
+}
+
+func (l *loader) list(key string) []string {
+	var items []string
+	for _, item := range strings.Split(l.get(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// err reports every problem found, or nil when there are none
+func (l *loader) err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration: %s", strings.Join(l.problems, "; "))
+}
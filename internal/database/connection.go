@@ -4,11 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 	"postman-api/internal/config"
 	"time"
 
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
+	_ "modernc.org/sqlite"
 )
 
 type Database struct {
@@ -16,21 +23,72 @@ type Database struct {
 }
 
 func NewConnection(cfg *config.DatabaseConfig) (*Database, error) {
-	sqldb, err := sql.Open("pgx", cfg.DSN)
+	sqldb, err := Open(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection %w", err)
+		return nil, err
+	}
+
+	return New(sqldb, cfg), nil
+}
+
+// NewReplicaConnection connects to the configured read replica
+func NewReplicaConnection(cfg *config.DatabaseConfig) (*Database, error) {
+	sqldb, err := open("pgx", cfg.ReplicaDSN)
+	if err != nil {
+		return nil, err
 	}
 
-	db := bun.NewDB(sqldb, pgdialect.New())
+	return New(sqldb, cfg), nil
+}
+
+// Open opens and pings the configured PostgreSQL connection pool, or the
+// SQLite database, creating its file and directory when missing
+func Open(cfg *config.DatabaseConfig) (*sql.DB, error) {
+	if cfg.Driver == config.DriverSQLite {
+		if err := os.MkdirAll(filepath.Dir(cfg.SQLitePath), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory %w", err)
+		}
+		return open("sqlite", cfg.DSN)
+	}
+
+	return open("pgx", cfg.DSN)
+}
+
+func open(driverName, dsn string) (*sql.DB, error) {
+	sqldb, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection %w", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := db.DB.PingContext(ctx); err != nil {
+	if err := sqldb.PingContext(ctx); err != nil {
+		sqldb.Close()
 		return nil, fmt.Errorf("failed to ping database %w", err)
 	}
 
-	return &Database{DB: db}, nil
+	return sqldb, nil
+}
+
+// New wraps an open connection pool, applying the configured query timeout
+func New(sqldb *sql.DB, cfg *config.DatabaseConfig) *Database {
+	var sqlDialect schema.Dialect = pgdialect.New()
+	if cfg.Driver == config.DriverSQLite {
+		sqlDialect = sqlitedialect.New()
+	}
+
+	db := bun.NewDB(sqldb, sqlDialect)
+	if cfg.QueryTimeout > 0 {
+		db.AddQueryHook(&queryTimeoutHook{timeout: cfg.QueryTimeout})
+	}
+
+	return &Database{DB: db}
+}
+
+// SQLite reports whether the database is SQLite rather than PostgreSQL
+func (d *Database) SQLite() bool {
+	return d.Dialect().Name() == dialect.SQLite
 }
 
 func (d *Database) Close() error {
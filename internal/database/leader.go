@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// RunAsLeader runs fn while this process holds the PostgreSQL advisory lock
+// named name, so that among replicas sharing the database only one runs it at
+// a time. Other replicas retry every interval and take over when the leader
+// stops or its connection is lost, in which case fn's context is cancelled.
+// It returns when ctx is done, or when fn returns on its own. A SQLite
+// database is served by a single process, which always leads.
+func (d *Database) RunAsLeader(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context)) {
+	if d.SQLite() {
+		fn(ctx)
+		return
+	}
+
+	for {
+		if done := d.lead(ctx, name, interval, fn); done {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// lead runs fn if the lock is free and reports whether RunAsLeader is done
+func (d *Database) lead(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context)) bool {
+	// Advisory locks belong to a session, so the lock is taken and held on a
+	// connection of its own rather than one shared through the pool
+	conn, err := d.DB.DB.Conn(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to connect for %s leader election: %v", name, err)
+		}
+		return ctx.Err() != nil
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&acquired); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to try %s leader lock: %v", name, err)
+		}
+		return ctx.Err() != nil
+	}
+	if !acquired {
+		return false
+	}
+
+	log.Printf("Acquired %s leadership", name)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go watchLeaderConn(leaderCtx, cancel, conn, name, interval)
+
+	fn(leaderCtx)
+	lost := leaderCtx.Err() != nil && ctx.Err() == nil
+	cancel()
+
+	unlockCtx, unlockCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer unlockCancel()
+	if _, err := conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil && !lost {
+		log.Printf("Failed to release %s leader lock: %v", name, err)
+	}
+
+	if lost {
+		log.Printf("Lost %s leadership", name)
+		return false
+	}
+
+	return true
+}
+
+// watchLeaderConn cancels the leader's context once the connection holding
+// the lock stops answering, since the lock is gone with it
+func watchLeaderConn(ctx context.Context, cancel context.CancelFunc, conn *sql.Conn, name string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.PingContext(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Leader connection for %s failed: %v", name, err)
+				cancel()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"postman-api/internal/database/migrations"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+// Migrate applies any pending schema migrations
+func (d *Database) Migrate(ctx context.Context) error {
+	ctx = WithoutQueryTimeout(ctx)
+	set := migrations.Migrations
+	if d.SQLite() {
+		set = migrations.SQLiteMigrations
+	}
+	migrator := migrate.NewMigrator(d.DB, set)
+
+	if err := migrator.Init(ctx); err != nil {
+		return fmt.Errorf("failed to initialize migrations %w", err)
+	}
+
+	if err := migrator.Lock(ctx); err != nil {
+		return fmt.Errorf("failed to lock migrations %w", err)
+	}
+	defer migrator.Unlock(ctx)
+
+	group, err := migrator.Migrate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations %w", err)
+	}
+
+	if !group.IsZero() {
+		log.Printf("Applied migrations: %s", group)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS openapi_specs;
+
+--bun:split
+
+DROP TABLE IF EXISTS requests;
+
+--bun:split
+
+DROP TABLE IF EXISTS collections;
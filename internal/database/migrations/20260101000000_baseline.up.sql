@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    description VARCHAR,
+    schema VARCHAR,
+    variables JSONB,
+    auth JSONB,
+    events JSONB,
+    items JSONB,
+    postman_id VARCHAR,
+    exporter_id VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS requests (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES collections (id),
+    name VARCHAR NOT NULL,
+    description VARCHAR,
+    folder_path VARCHAR,
+    url JSONB,
+    method VARCHAR NOT NULL,
+    headers JSONB,
+    params JSONB,
+    body JSONB,
+    auth JSONB,
+    events JSONB,
+    responses JSONB,
+    postman_id VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS requests_collection_id_idx ON requests (collection_id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS openapi_specs (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR NOT NULL,
+    description VARCHAR,
+    version VARCHAR NOT NULL,
+    content JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1 @@
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS assertions JSONB;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS spec_sources;
+
+--bun:split
+
+ALTER TABLE openapi_specs DROP COLUMN IF EXISTS metadata;
+
+--bun:split
+
+ALTER TABLE collections DROP COLUMN IF EXISTS metadata;
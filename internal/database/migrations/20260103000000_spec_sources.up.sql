@@ -0,0 +1,22 @@
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+--bun:split
+
+ALTER TABLE openapi_specs ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS spec_sources (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    url VARCHAR NOT NULL,
+    poll_interval_seconds INTEGER NOT NULL,
+    auth JSONB,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_hash VARCHAR,
+    last_spec_id BIGINT,
+    last_checked_at TIMESTAMPTZ,
+    last_error VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS environments (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    variables JSONB NOT NULL DEFAULT '[]',
+    postman_id VARCHAR,
+    metadata JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
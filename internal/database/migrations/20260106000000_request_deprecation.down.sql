@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_requests_deprecated;
+
+--bun:split
+
+ALTER TABLE requests DROP COLUMN IF EXISTS sunset;
+
+--bun:split
+
+ALTER TABLE requests DROP COLUMN IF EXISTS deprecated;
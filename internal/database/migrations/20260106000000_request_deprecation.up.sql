@@ -0,0 +1,9 @@
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS deprecated BOOLEAN NOT NULL DEFAULT FALSE;
+
+--bun:split
+
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS sunset DATE;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_deprecated ON requests(id) WHERE deprecated;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_openapi_specs_ownership_team;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_collections_ownership_team;
+
+--bun:split
+
+DROP TABLE IF EXISTS inventory_ownership;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS inventory_ownership (
+    key VARCHAR PRIMARY KEY,
+    ownership JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_collections_ownership_team ON collections ((metadata -> 'ownership' ->> 'team'));
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_specs_ownership_team ON openapi_specs ((metadata -> 'ownership' ->> 'team'));
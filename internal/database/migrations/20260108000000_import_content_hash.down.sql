@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_openapi_specs_content_hash;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_collections_content_hash;
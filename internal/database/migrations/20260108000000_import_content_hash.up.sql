@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_collections_content_hash ON collections ((metadata ->> 'content_hash'));
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_specs_content_hash ON openapi_specs ((metadata ->> 'content_hash'));
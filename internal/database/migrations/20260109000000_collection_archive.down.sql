@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_collections_active;
+
+--bun:split
+
+ALTER TABLE collections DROP COLUMN IF EXISTS archived_at;
+
+--bun:split
+
+ALTER TABLE collections DROP COLUMN IF EXISTS archived;
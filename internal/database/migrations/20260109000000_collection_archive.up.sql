@@ -0,0 +1,9 @@
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+--bun:split
+
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_collections_active ON collections(created_at DESC) WHERE NOT archived;
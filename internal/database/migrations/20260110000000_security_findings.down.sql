@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_security_findings_collection_id;
+
+--bun:split
+
+DROP TABLE IF EXISTS security_findings;
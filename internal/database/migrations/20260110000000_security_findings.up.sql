@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS security_findings (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    request_id BIGINT REFERENCES requests (id) ON DELETE SET NULL,
+    tool VARCHAR NOT NULL,
+    rule VARCHAR NOT NULL,
+    severity VARCHAR,
+    method VARCHAR,
+    url VARCHAR NOT NULL,
+    description TEXT,
+    evidence TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_security_findings_collection_id ON security_findings(collection_id);
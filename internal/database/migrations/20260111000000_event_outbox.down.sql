@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+
+--bun:split
+
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR NOT NULL,
+    entity_type VARCHAR NOT NULL,
+    entity_id BIGINT NOT NULL,
+    payload JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    published_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_jobs_status;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_jobs_pending;
+
+--bun:split
+
+DROP TABLE IF EXISTS jobs;
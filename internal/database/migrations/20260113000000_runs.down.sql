@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_runs_collection_id;
+
+--bun:split
+
+DROP TABLE IF EXISTS runs;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS runs (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    environment_id BIGINT,
+    status VARCHAR NOT NULL,
+    delay_ms INTEGER NOT NULL DEFAULT 0,
+    stop_on_failure BOOLEAN NOT NULL DEFAULT FALSE,
+    total INTEGER NOT NULL DEFAULT 0,
+    passed INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    results JSONB,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    finished_at TIMESTAMPTZ
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_runs_collection_id ON runs(collection_id, id DESC);
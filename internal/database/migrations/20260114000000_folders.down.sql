@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_requests_folder_id;
+
+--bun:split
+
+ALTER TABLE requests DROP COLUMN IF EXISTS folder_id;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_folders_collection_id;
+
+--bun:split
+
+DROP TABLE IF EXISTS folders;
@@ -0,0 +1,72 @@
+CREATE TABLE IF NOT EXISTS folders (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    parent_id BIGINT REFERENCES folders (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    postman_id VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_folders_collection_id ON folders(collection_id, parent_id, position);
+
+--bun:split
+
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS folder_id BIGINT REFERENCES folders (id) ON DELETE CASCADE;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_folder_id ON requests(folder_id);
+
+--bun:split
+
+-- Build the folder rows of existing requests from their folder paths, one
+-- level of nesting at a time, ordered by the first request in each folder
+ALTER TABLE folders ADD COLUMN backfill_path TEXT;
+
+--bun:split
+
+DO $$
+DECLARE
+    depth INTEGER := 1;
+    inserted INTEGER;
+BEGIN
+    LOOP
+        INSERT INTO folders (collection_id, parent_id, name, position, backfill_path)
+        SELECT p.collection_id, parent.id, p.name,
+            ROW_NUMBER() OVER (PARTITION BY p.collection_id, p.parent_path ORDER BY p.first_request) - 1,
+            p.path
+        FROM (
+            SELECT collection_id,
+                array_to_string(segments[1:depth], '/') AS path,
+                array_to_string(segments[1:depth - 1], '/') AS parent_path,
+                segments[depth] AS name,
+                MIN(id) AS first_request
+            FROM (SELECT id, collection_id, string_to_array(folder_path, '/') AS segments
+                FROM requests WHERE folder_path <> '') r
+            WHERE array_length(segments, 1) >= depth
+            GROUP BY 1, 2, 3, 4
+        ) p
+        LEFT JOIN folders parent
+            ON parent.collection_id = p.collection_id AND parent.backfill_path = p.parent_path
+        ORDER BY p.first_request;
+
+        GET DIAGNOSTICS inserted = ROW_COUNT;
+        EXIT WHEN inserted = 0;
+        depth := depth + 1;
+    END LOOP;
+END $$;
+
+--bun:split
+
+UPDATE requests r SET folder_id = f.id
+FROM folders f
+WHERE f.collection_id = r.collection_id AND f.backfill_path = r.folder_path;
+
+--bun:split
+
+ALTER TABLE folders DROP COLUMN backfill_path;
@@ -0,0 +1,28 @@
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS responses JSONB;
+
+--bun:split
+
+UPDATE requests r SET responses = e.responses
+FROM (
+    SELECT request_id, jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+        'name', name,
+        'status', status,
+        'code', code,
+        'header', headers,
+        'body', body,
+        'originalRequest', original_request,
+        'cookie', cookies,
+        '_postman_previewlanguage', preview_language,
+        'id', postman_id,
+        '_body_ref', body_ref,
+        '_body_size', body_size,
+        '_body_truncated', CASE WHEN body_truncated THEN TRUE END
+    )) ORDER BY position, id) AS responses
+    FROM request_examples
+    GROUP BY request_id
+) e
+WHERE e.request_id = r.id;
+
+--bun:split
+
+DROP TABLE IF EXISTS request_examples;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS request_examples (
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT NOT NULL REFERENCES requests (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    name VARCHAR NOT NULL DEFAULT '',
+    status VARCHAR,
+    code INTEGER,
+    headers JSONB,
+    body TEXT,
+    original_request JSONB,
+    cookies JSONB,
+    preview_language VARCHAR,
+    postman_id VARCHAR,
+    body_ref VARCHAR,
+    body_size BIGINT,
+    body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_request_examples_request_id ON request_examples(request_id, position);
+
+--bun:split
+
+-- Move the saved responses of every request into rows of their own
+INSERT INTO request_examples (request_id, position, name, status, code, headers, body,
+    original_request, cookies, preview_language, postman_id, body_ref, body_size, body_truncated,
+    created_at, updated_at)
+SELECT r.id, e.ordinality - 1, COALESCE(e.value ->> 'name', ''), e.value ->> 'status',
+    (e.value ->> 'code')::integer, e.value -> 'header', e.value ->> 'body',
+    e.value -> 'originalRequest', e.value -> 'cookie', e.value ->> '_postman_previewlanguage',
+    e.value ->> 'id', e.value ->> '_body_ref', (e.value ->> '_body_size')::bigint,
+    COALESCE((e.value ->> '_body_truncated')::boolean, FALSE),
+    r.updated_at, r.updated_at
+FROM requests r
+CROSS JOIN LATERAL jsonb_array_elements(r.responses) WITH ORDINALITY AS e(value, ordinality)
+WHERE jsonb_typeof(r.responses) = 'array';
+
+--bun:split
+
+ALTER TABLE requests DROP COLUMN IF EXISTS responses;
@@ -0,0 +1,9 @@
+ALTER TABLE requests DROP COLUMN IF EXISTS header_presets;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_header_presets_name;
+
+--bun:split
+
+DROP TABLE IF EXISTS header_presets;
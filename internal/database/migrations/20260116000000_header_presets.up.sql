@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS header_presets (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    description TEXT,
+    headers JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_header_presets_name ON header_presets(name);
+
+--bun:split
+
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS header_presets JSONB;
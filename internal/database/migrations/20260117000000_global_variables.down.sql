@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_global_variable_changes_key;
+
+--bun:split
+
+DROP TABLE IF EXISTS global_variable_changes;
+
+--bun:split
+
+DROP INDEX IF EXISTS idx_global_variables_key;
+
+--bun:split
+
+DROP TABLE IF EXISTS global_variables;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS global_variables (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_global_variables_key ON global_variables(key);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS global_variable_changes (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_global_variable_changes_key ON global_variable_changes(key, id);
@@ -0,0 +1,8 @@
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS request_count INTEGER NOT NULL DEFAULT 0;
+
+--bun:split
+
+UPDATE collections c
+SET request_count = r.count
+FROM (SELECT collection_id, COUNT(*) AS count FROM requests GROUP BY collection_id) r
+WHERE r.collection_id = c.id;
@@ -0,0 +1,12 @@
+-- Revisions outlive their collection so that a deleted one can be restored
+CREATE TABLE IF NOT EXISTS collection_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL,
+    revision INTEGER NOT NULL,
+    action VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    snapshot JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    UNIQUE (collection_id, revision)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS openapi_spec_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR NOT NULL,
+    version VARCHAR NOT NULL,
+    content JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    UNIQUE (spec_id, revision)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS openapi_spec_review_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    decision VARCHAR NOT NULL,
+    reviewer VARCHAR NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    from_status VARCHAR NOT NULL,
+    to_status VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_spec_review_decisions_spec_id ON openapi_spec_review_decisions (spec_id, id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    frequency VARCHAR NOT NULL,
+    channel VARCHAR NOT NULL,
+    target VARCHAR NOT NULL,
+    collection_ids BIGINT[] NOT NULL DEFAULT '{}',
+    spec_ids BIGINT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS openapi_spec_promotions;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS openapi_spec_promotions (
+    id BIGSERIAL PRIMARY KEY,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    label VARCHAR NOT NULL,
+    from_label VARCHAR,
+    title VARCHAR NOT NULL,
+    version VARCHAR NOT NULL,
+    content JSONB,
+    promoted_by VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_spec_promotions_spec_label ON openapi_spec_promotions (spec_id, label, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_requests_folder_position;
+
+--bun:split
+
+ALTER TABLE requests DROP COLUMN IF EXISTS position;
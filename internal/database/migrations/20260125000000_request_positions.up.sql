@@ -0,0 +1,27 @@
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+--bun:split
+
+-- Requests share the positions of their folder with its subfolders. Existing
+-- requests keep coming first, in the order they were added, and the
+-- subfolders move after them.
+UPDATE requests r SET position = p.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY collection_id, folder_id ORDER BY id) - 1 AS position
+    FROM requests
+) p
+WHERE p.id = r.id;
+
+--bun:split
+
+UPDATE folders f SET position = f.position + c.requests
+FROM (
+    SELECT collection_id, folder_id, COUNT(*) AS requests
+    FROM requests
+    GROUP BY 1, 2
+) c
+WHERE c.collection_id = f.collection_id AND c.folder_id IS NOT DISTINCT FROM f.parent_id;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_folder_position ON requests(collection_id, folder_id, position);
@@ -0,0 +1 @@
+ALTER TABLE runs DROP COLUMN IF EXISTS error;
@@ -0,0 +1 @@
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS run_settings JSONB;
@@ -0,0 +1 @@
+ALTER TABLE environments ADD COLUMN IF NOT EXISTS host_overrides JSONB;
@@ -0,0 +1 @@
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS metrics JSONB;
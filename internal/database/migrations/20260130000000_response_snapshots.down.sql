@@ -0,0 +1 @@
+DROP TABLE IF EXISTS response_snapshots;
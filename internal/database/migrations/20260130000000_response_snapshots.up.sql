@@ -0,0 +1,12 @@
+-- The response each request got when its snapshot was recorded, diffed
+-- against by later snapshot runs
+CREATE TABLE IF NOT EXISTS response_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    request_id BIGINT NOT NULL UNIQUE REFERENCES requests (id) ON DELETE CASCADE,
+    run_id BIGINT,
+    status_code INTEGER NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    body_base64 BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+);
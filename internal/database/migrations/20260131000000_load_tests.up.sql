@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS load_tests (
+    id BIGSERIAL PRIMARY KEY,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    request_id BIGINT REFERENCES requests (id) ON DELETE CASCADE,
+    status VARCHAR NOT NULL,
+    rps INTEGER NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    stats JSONB,
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp,
+    finished_at TIMESTAMPTZ
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS load_tests_collection_id_idx ON load_tests (collection_id);
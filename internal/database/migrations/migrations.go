@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+//go:embed *.sql
+var sqlMigrations embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// Migrations holds every schema migration shipped with the server
+var Migrations = migrate.NewMigrations()
+
+// SQLiteMigrations holds the same schema for SQLite databases; every
+// migration added to Migrations needs a counterpart here
+var SQLiteMigrations = migrate.NewMigrations()
+
+func init() {
+	if err := Migrations.Discover(sqlMigrations); err != nil {
+		panic(err)
+	}
+
+	sqlite, err := fs.Sub(sqliteMigrations, "sqlite")
+	if err != nil {
+		panic(err)
+	}
+	if err := SQLiteMigrations.Discover(sqlite); err != nil {
+		panic(err)
+	}
+}
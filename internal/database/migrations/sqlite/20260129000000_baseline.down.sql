@@ -0,0 +1,81 @@
+DROP TABLE IF EXISTS digest_subscriptions;
+
+--bun:split
+
+DROP TABLE IF EXISTS collection_revisions;
+
+--bun:split
+
+DROP TABLE IF EXISTS global_variable_changes;
+
+--bun:split
+
+DROP TABLE IF EXISTS global_variables;
+
+--bun:split
+
+DROP TABLE IF EXISTS header_presets;
+
+--bun:split
+
+DROP TABLE IF EXISTS runs;
+
+--bun:split
+
+DROP TABLE IF EXISTS jobs;
+
+--bun:split
+
+DROP TABLE IF EXISTS event_outbox;
+
+--bun:split
+
+DROP TABLE IF EXISTS security_findings;
+
+--bun:split
+
+DROP TABLE IF EXISTS inventory_ownership;
+
+--bun:split
+
+DROP TABLE IF EXISTS environments;
+
+--bun:split
+
+DROP TABLE IF EXISTS attachments;
+
+--bun:split
+
+DROP TABLE IF EXISTS spec_sources;
+
+--bun:split
+
+DROP TABLE IF EXISTS openapi_spec_promotions;
+
+--bun:split
+
+DROP TABLE IF EXISTS openapi_spec_review_decisions;
+
+--bun:split
+
+DROP TABLE IF EXISTS openapi_spec_revisions;
+
+--bun:split
+
+DROP TABLE IF EXISTS openapi_specs;
+
+--bun:split
+
+DROP TABLE IF EXISTS request_examples;
+
+--bun:split
+
+DROP TABLE IF EXISTS requests;
+
+--bun:split
+
+DROP TABLE IF EXISTS folders;
+
+--bun:split
+
+DROP TABLE IF EXISTS collections;
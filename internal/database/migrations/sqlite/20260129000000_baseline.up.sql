@@ -0,0 +1,406 @@
+-- The schema of the PostgreSQL migrations up to 20260129000000_run_metrics,
+-- in one step since SQLite databases start out empty. Later migrations get
+-- a SQLite counterpart of the same name here.
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    description VARCHAR,
+    schema VARCHAR,
+    variables TEXT,
+    auth TEXT,
+    events TEXT,
+    items TEXT,
+    postman_id VARCHAR,
+    exporter_id VARCHAR,
+    metadata TEXT,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    archived_at TIMESTAMP,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_collections_ownership_team ON collections ((metadata -> 'ownership' ->> 'team'));
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_collections_content_hash ON collections ((metadata ->> 'content_hash'));
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_collections_active ON collections(created_at DESC) WHERE NOT archived;
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS folders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    parent_id BIGINT REFERENCES folders (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    postman_id VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_folders_collection_id ON folders(collection_id, parent_id, position);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL REFERENCES collections (id),
+    folder_id BIGINT REFERENCES folders (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    description VARCHAR,
+    folder_path VARCHAR,
+    position INTEGER NOT NULL DEFAULT 0,
+    url TEXT,
+    method VARCHAR NOT NULL,
+    headers TEXT,
+    header_presets TEXT,
+    params TEXT,
+    body TEXT,
+    auth TEXT,
+    events TEXT,
+    assertions TEXT,
+    run_settings TEXT,
+    deprecated BOOLEAN NOT NULL DEFAULT FALSE,
+    sunset DATE,
+    postman_id VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS requests_collection_id_idx ON requests (collection_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_deprecated ON requests(id) WHERE deprecated;
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_folder_id ON requests(folder_id);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_requests_folder_position ON requests(collection_id, folder_id, position);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS request_examples (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id BIGINT NOT NULL REFERENCES requests (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    name VARCHAR NOT NULL DEFAULT '',
+    status VARCHAR,
+    code INTEGER,
+    headers TEXT,
+    body TEXT,
+    original_request TEXT,
+    cookies TEXT,
+    preview_language VARCHAR,
+    postman_id VARCHAR,
+    body_ref VARCHAR,
+    body_size BIGINT,
+    body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_request_examples_request_id ON request_examples(request_id, position);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS openapi_specs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR NOT NULL,
+    description VARCHAR,
+    version VARCHAR NOT NULL,
+    content TEXT,
+    metadata TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_specs_ownership_team ON openapi_specs ((metadata -> 'ownership' ->> 'team'));
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_specs_content_hash ON openapi_specs ((metadata ->> 'content_hash'));
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS openapi_spec_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR NOT NULL,
+    version VARCHAR NOT NULL,
+    content TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (spec_id, revision)
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS openapi_spec_review_decisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    decision VARCHAR NOT NULL,
+    reviewer VARCHAR NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    from_status VARCHAR NOT NULL,
+    to_status VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_spec_review_decisions_spec_id ON openapi_spec_review_decisions (spec_id, id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS openapi_spec_promotions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    spec_id BIGINT NOT NULL REFERENCES openapi_specs (id) ON DELETE CASCADE,
+    label VARCHAR NOT NULL,
+    from_label VARCHAR,
+    title VARCHAR NOT NULL,
+    version VARCHAR NOT NULL,
+    content TEXT,
+    promoted_by VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_openapi_spec_promotions_spec_label ON openapi_spec_promotions (spec_id, label, id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS spec_sources (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    url VARCHAR NOT NULL,
+    poll_interval_seconds INTEGER NOT NULL,
+    auth TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_hash VARCHAR,
+    last_spec_id BIGINT,
+    last_checked_at TIMESTAMP,
+    last_error VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hash VARCHAR(64) NOT NULL UNIQUE,
+    filename VARCHAR NOT NULL,
+    content_type VARCHAR NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS environments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    variables TEXT NOT NULL DEFAULT '[]',
+    host_overrides TEXT,
+    postman_id VARCHAR,
+    metadata TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS inventory_ownership (
+    key VARCHAR PRIMARY KEY,
+    ownership TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS security_findings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    request_id BIGINT REFERENCES requests (id) ON DELETE SET NULL,
+    tool VARCHAR NOT NULL,
+    rule VARCHAR NOT NULL,
+    severity VARCHAR,
+    method VARCHAR,
+    url VARCHAR NOT NULL,
+    description TEXT,
+    evidence TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_security_findings_collection_id ON security_findings(collection_id);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR NOT NULL,
+    entity_type VARCHAR NOT NULL,
+    entity_id BIGINT NOT NULL,
+    payload TEXT,
+    occurred_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    published_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR NOT NULL,
+    payload TEXT,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1,
+    run_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    last_error TEXT,
+    result TEXT,
+    progress TEXT,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pending ON jobs(run_at, id) WHERE status = 'pending';
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, created_at DESC);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    environment_id BIGINT,
+    status VARCHAR NOT NULL,
+    delay_ms INTEGER NOT NULL DEFAULT 0,
+    stop_on_failure BOOLEAN NOT NULL DEFAULT FALSE,
+    total INTEGER NOT NULL DEFAULT 0,
+    passed INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    results TEXT,
+    metrics TEXT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    finished_at TIMESTAMP
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_runs_collection_id ON runs(collection_id, id DESC);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS header_presets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    description TEXT,
+    headers TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_header_presets_name ON header_presets(name);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS global_variables (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key VARCHAR NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_global_variables_key ON global_variables(key);
+
+--bun:split
+
+CREATE TABLE IF NOT EXISTS global_variable_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS idx_global_variable_changes_key ON global_variable_changes(key, id);
+
+--bun:split
+
+-- Revisions outlive their collection so that a deleted one can be restored
+CREATE TABLE IF NOT EXISTS collection_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL,
+    revision INTEGER NOT NULL,
+    action VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    request_count INTEGER NOT NULL DEFAULT 0,
+    snapshot TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (collection_id, revision)
+);
+
+--bun:split
+
+-- Arrays are kept as JSON arrays
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR NOT NULL,
+    frequency VARCHAR NOT NULL,
+    channel VARCHAR NOT NULL,
+    target VARCHAR NOT NULL,
+    collection_ids TEXT NOT NULL DEFAULT '[]',
+    spec_ids TEXT NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
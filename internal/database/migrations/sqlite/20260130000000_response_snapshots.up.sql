@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS response_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    request_id BIGINT NOT NULL UNIQUE REFERENCES requests (id) ON DELETE CASCADE,
+    run_id BIGINT,
+    status_code INTEGER NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    body_base64 BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS load_tests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    request_id BIGINT REFERENCES requests (id) ON DELETE CASCADE,
+    status VARCHAR NOT NULL,
+    rps INTEGER NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    stats TEXT,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    finished_at TIMESTAMP
+);
+
+--bun:split
+
+CREATE INDEX IF NOT EXISTS load_tests_collection_id_idx ON load_tests (collection_id);
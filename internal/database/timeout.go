@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type cancelStashKey struct{}
+
+type noTimeoutKey struct{}
+
+// WithoutQueryTimeout marks ctx so queries run with it are not bounded by the
+// per-query timeout, e.g. for migrations and long-running maintenance jobs
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTimeoutKey{}, true)
+}
+
+// queryTimeoutHook bounds every query by a timeout derived from the caller's
+// context; it never extends a deadline the context already carries
+type queryTimeoutHook struct {
+	timeout time.Duration
+}
+
+func (h *queryTimeoutHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	// A transaction lives as long as its BEGIN context, so it must not be bounded here
+	if event.Query == "BEGIN" || ctx.Value(noTimeoutKey{}) != nil {
+		return ctx
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	if event.Stash == nil {
+		event.Stash = make(map[any]any)
+	}
+	event.Stash[cancelStashKey{}] = cancel
+
+	return ctx
+}
+
+func (h *queryTimeoutHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if cancel, ok := event.Stash[cancelStashKey{}].(context.CancelFunc); ok {
+		cancel()
+	}
+}
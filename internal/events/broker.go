@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// Supported event buses
+const (
+	BrokerNATS  = "nats"
+	BrokerKafka = "kafka"
+)
+
+// Broker sends serialized events to a topic of an event bus
+type Broker interface {
+	Send(ctx context.Context, topic, key string, data []byte) error
+	Close() error
+}
+
+// ValidBroker reports whether NewBroker supports kind
+func ValidBroker(kind string) bool {
+	return kind == BrokerNATS || kind == BrokerKafka
+}
+
+// NewBroker creates a broker of the given kind: a NATS server URL
+// (nats:// or tls://[user:pass@]host:port) connected with natsOptions, or
+// the base URL of a Kafka REST proxy. Kafka is only reached through a REST
+// proxy such as Confluent's; brokers speaking the native protocol are not.
+func NewBroker(kind, url string, natsOptions NATSOptions) (Broker, error) {
+	switch kind {
+	case BrokerNATS:
+		return NewNATSBroker(url, natsOptions)
+	case BrokerKafka:
+		return NewKafkaRESTBroker(url)
+	default:
+		return nil, fmt.Errorf("unsupported event broker %q", kind)
+	}
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Event types
+const (
+	SpecSourceChanged = "spec_source.changed"
+	RunCompleted      = "run.completed"
+
+	CollectionCreated    = "collection.created"
+	CollectionUpdated    = "collection.updated"
+	CollectionDeleted    = "collection.deleted"
+	CollectionArchived   = "collection.archived"
+	CollectionUnarchived = "collection.unarchived"
+	RequestCreated       = "request.created"
+	RequestUpdated       = "request.updated"
+	RequestDeleted       = "request.deleted"
+	OpenAPISpecCreated   = "openapi_spec.created"
+	OpenAPISpecUpdated   = "openapi_spec.updated"
+	OpenAPISpecDeleted   = "openapi_spec.deleted"
+)
+
+// Event describes a change to a stored entity; ID is set once the event is
+// stored in the outbox and lets consumers discard redeliveries
+type Event struct {
+	ID         int64          `json:"id,omitempty"`
+	Type       string         `json:"type"`
+	EntityType string         `json:"entity_type"`
+	EntityID   int64          `json:"entity_id"`
+	Payload    map[string]any `json:"payload,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+// Publisher delivers events to interested consumers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the standard logger
+type LogPublisher struct{}
+
+// NewLogPublisher creates a publisher that logs every event
+func NewLogPublisher() Publisher {
+	return &LogPublisher{}
+}
+
+// Publish logs the event as JSON
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("event: %s", data)
+	return nil
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// natsDialTimeout bounds connecting to the NATS server
+	natsDialTimeout = 10 * time.Second
+	// natsPublishTimeout bounds waiting for the server to take a message
+	// when the caller sets no deadline
+	natsPublishTimeout = 10 * time.Second
+	// natsReconnectWait is the pause between attempts to reconnect
+	natsReconnectWait = 2 * time.Second
+)
+
+// NATSOptions are the credentials and TLS files of a NATS connection, on top
+// of the user, password or token the server URL may carry
+type NATSOptions struct {
+	// Token authenticates with a token
+	Token string
+	// NKeySeedFile authenticates with the nkey whose seed it holds
+	NKeySeedFile string
+	// CredentialsFile holds a user JWT and its nkey seed, as written by nsc
+	CredentialsFile string
+	// CAFile verifies the server certificate; CertFile and KeyFile
+	// authenticate the client. Any of them turns on TLS, as does a tls://
+	// server URL.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// NATSBroker publishes events as NATS messages, with the topic as subject.
+// The client reconnects in the background whenever the connection drops;
+// publishes fail rather than buffer while it is down, so the outbox keeps
+// the events until the server has them.
+type NATSBroker struct {
+	conn *nats.Conn
+}
+
+// NewNATSBroker connects to a nats:// or tls:// server URL. The broker is
+// created while the server cannot be reached and connects once it comes up.
+func NewNATSBroker(rawURL string, options NATSOptions) (Broker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q", rawURL)
+	}
+
+	opts := []nats.Option{
+		nats.Name("postman-api"),
+		nats.Timeout(natsDialTimeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(natsReconnectWait),
+		nats.ReconnectBufSize(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("Disconnected from NATS: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Printf("Reconnected to NATS at %s", conn.ConnectedUrlRedacted())
+		}),
+		// Errors such as publish permission violations arrive asynchronously
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			log.Printf("NATS error: %v", err)
+		}),
+	}
+
+	if options.Token != "" {
+		opts = append(opts, nats.Token(options.Token))
+	}
+	if options.NKeySeedFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(options.NKeySeedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NATS nkey seed: %w", err)
+		}
+		opts = append(opts, opt)
+	}
+	if options.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(options.CredentialsFile))
+	}
+	if options.CAFile != "" {
+		opts = append(opts, nats.RootCAs(options.CAFile))
+	}
+	if options.CertFile != "" {
+		opts = append(opts, nats.ClientCert(options.CertFile, options.KeyFile))
+	}
+
+	conn, err := nats.Connect(rawURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &NATSBroker{conn: conn}, nil
+}
+
+// Send publishes data on subject topic and waits for the server to take it;
+// key is not used by NATS
+func (b *NATSBroker) Send(ctx context.Context, topic, key string, data []byte) error {
+	if err := b.conn.Publish(topic, data); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, natsPublishTimeout)
+		defer cancel()
+	}
+
+	if err := b.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server
+func (b *NATSBroker) Close() error {
+	b.conn.Close()
+	return nil
+}
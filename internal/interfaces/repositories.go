@@ -3,39 +3,267 @@ package interfaces
 import (
 	"context"
 	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
 )
 
+// Transactor runs work in a database transaction; repositories take part in
+// it through their WithTx method
+type Transactor interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error
+}
+
 // CollectionRepository defines operations for collection persistence
 type CollectionRepository interface {
 	Create(ctx context.Context, collection *models.Collection) error
 	GetByID(ctx context.Context, id int64) (*models.Collection, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.Collection, error)
 	GetWithRequests(ctx context.Context, id int64) (*models.Collection, error)
-	List(ctx context.Context, offset, limit int) ([]*models.Collection, error)
+	List(ctx context.Context, offset, limit int, includeArchived bool) ([]*models.Collection, error)
+	ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.Collection, error)
 	Update(ctx context.Context, collection *models.Collection) error
 	Delete(ctx context.Context, id int64) error
-	Count(ctx context.Context) (int, error)
+	SetArchived(ctx context.Context, ids []int64, archived bool) (int, error)
+	ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Collection, error)
+	Count(ctx context.Context, includeArchived bool) (int, error)
+	EstimateCount(ctx context.Context) (int, error)
+	ExistsByMetadata(ctx context.Context, key, value string) (bool, error)
+	FindByMetadata(ctx context.Context, key, value string) (*models.Collection, error)
+	IDAvailable(ctx context.Context, id int64) (bool, error)
+	ListWithLegacyItems(ctx context.Context, afterID int64, limit int) ([]int64, error)
+	CountWithLegacyItems(ctx context.Context, afterID int64) (int, error)
+	LockLegacyItems(ctx context.Context, id int64) ([]models.PostmanItem, error)
+	ClearItems(ctx context.Context, id int64) error
+	WithTx(tx bun.Tx) CollectionRepository
 }
 
 // RequestRepository defines operations for request persistence
 type RequestRepository interface {
 	Create(ctx context.Context, request *models.Request) error
 	GetByID(ctx context.Context, id int64) (*models.Request, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.Request, error)
 	List(ctx context.Context, offset, limit int) ([]*models.Request, error)
 	ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Request, error)
+	ListDeprecated(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Request, error)
 	Update(ctx context.Context, request *models.Request) error
 	Delete(ctx context.Context, id int64) error
 	DeleteByCollectionID(ctx context.Context, collectionID int64) error
+	DeleteByFolderIDs(ctx context.Context, collectionID int64, folderIDs []int64) ([]int64, error)
+	SetFolderPath(ctx context.Context, folderID int64, path string) (int, error)
+	NextPositions(ctx context.Context, collectionID int64) (map[int64]int, error)
 	Count(ctx context.Context) (int, error)
 	CountByCollectionID(ctx context.Context, collectionID int64) (int, error)
+	EstimateCount(ctx context.Context) (int, error)
+	EstimateCountByCollectionID(ctx context.Context, collectionID int64) (int, error)
+	WithTx(tx bun.Tx) RequestRepository
+}
+
+// FolderRepository defines operations for folder persistence
+type FolderRepository interface {
+	Create(ctx context.Context, folder *models.Folder) error
+	GetByID(ctx context.Context, id int64) (*models.Folder, error)
+	ListByCollectionID(ctx context.Context, collectionID int64) ([]*models.Folder, error)
+	Update(ctx context.Context, folder *models.Folder) error
+	Delete(ctx context.Context, id int64) error
+	WithTx(tx bun.Tx) FolderRepository
+}
+
+// ExampleRepository defines operations for the persistence of request examples
+type ExampleRepository interface {
+	Create(ctx context.Context, example *models.Example) error
+	GetByID(ctx context.Context, id int64) (*models.Example, error)
+	ListByRequestID(ctx context.Context, requestID int64) ([]*models.Example, error)
+	Update(ctx context.Context, example *models.Example) error
+	Delete(ctx context.Context, id int64) error
+	ReplaceForRequest(ctx context.Context, requestID int64, responses []models.PostmanResponse) error
+	WithTx(tx bun.Tx) ExampleRepository
 }
 
 // OpenAPIRepository defines operations for OpenAPI spec persistence
 type OpenAPIRepository interface {
 	Create(ctx context.Context, spec *models.OpenAPISpec) error
 	GetByID(ctx context.Context, id int64) (*models.OpenAPISpec, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.OpenAPISpec, error)
 	GetByTitle(ctx context.Context, title string) (*models.OpenAPISpec, error)
 	List(ctx context.Context, offset, limit int) ([]*models.OpenAPISpec, error)
+	ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.OpenAPISpec, error)
 	Update(ctx context.Context, spec *models.OpenAPISpec) error
 	Delete(ctx context.Context, id int64) error
 	Count(ctx context.Context) (int, error)
+	EstimateCount(ctx context.Context) (int, error)
+	Search(ctx context.Context, query string, offset, limit int) ([]*models.OpenAPISpec, error)
+	CountSearch(ctx context.Context, query string) (int, error)
+	ExistsByMetadata(ctx context.Context, key, value string) (bool, error)
+	FindByMetadata(ctx context.Context, key, value string) (*models.OpenAPISpec, error)
+	ListSupersededBefore(ctx context.Context, before time.Time, limit int) ([]*models.OpenAPISpec, error)
+	WithTx(tx bun.Tx) OpenAPIRepository
+}
+
+// SecurityFindingRepository defines operations for security finding persistence
+type SecurityFindingRepository interface {
+	CreateBatch(ctx context.Context, findings []*models.SecurityFinding) error
+	ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.SecurityFinding, error)
+	CountByCollectionID(ctx context.Context, collectionID int64) (int, error)
+}
+
+// RunRepository defines operations for collection run persistence
+type RunRepository interface {
+	Create(ctx context.Context, run *models.Run) error
+	GetByID(ctx context.Context, id int64) (*models.Run, error)
+	Update(ctx context.Context, run *models.Run) error
+	ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Run, error)
+	CountByCollectionID(ctx context.Context, collectionID int64) (int, error)
+}
+
+// LoadTestRepository defines operations for load test persistence
+type LoadTestRepository interface {
+	Create(ctx context.Context, test *models.LoadTest) error
+	GetByID(ctx context.Context, id int64) (*models.LoadTest, error)
+	Update(ctx context.Context, test *models.LoadTest) error
+}
+
+// ResponseSnapshotRepository defines operations for the response snapshots of requests
+type ResponseSnapshotRepository interface {
+	GetByRequestID(ctx context.Context, requestID int64) (*models.ResponseSnapshot, error)
+	Save(ctx context.Context, snapshot *models.ResponseSnapshot) error
+}
+
+// CollectionRevisionRepository defines operations for collection revision persistence
+type CollectionRevisionRepository interface {
+	Create(ctx context.Context, revision *models.CollectionRevision) error
+	GetByID(ctx context.Context, id int64) (*models.CollectionRevision, error)
+	ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.CollectionRevision, error)
+	CountByCollectionID(ctx context.Context, collectionID int64) (int, error)
+	ListSince(ctx context.Context, collectionID int64, since, until time.Time) ([]*models.CollectionRevision, error)
+	WithTx(tx bun.Tx) CollectionRevisionRepository
+}
+
+// SpecRevisionRepository defines operations for reading OpenAPI spec revisions
+type SpecRevisionRepository interface {
+	GetByRevision(ctx context.Context, specID int64, revision int) (*models.OpenAPISpecRevision, error)
+	ListBySpecID(ctx context.Context, specID int64, offset, limit int) ([]*models.OpenAPISpecRevision, error)
+	CountBySpecID(ctx context.Context, specID int64) (int, error)
+	ListSince(ctx context.Context, specID int64, since, until time.Time) ([]*models.OpenAPISpecRevision, error)
+}
+
+// SpecReviewRepository defines operations for the review decisions of OpenAPI specs
+type SpecReviewRepository interface {
+	WithTx(tx bun.Tx) SpecReviewRepository
+	LockSpec(ctx context.Context, specID int64) error
+	Status(ctx context.Context, specID int64) (string, error)
+	Approvers(ctx context.Context, specID int64) ([]string, error)
+	Create(ctx context.Context, decision *models.SpecReviewDecision) error
+	ListBySpecID(ctx context.Context, specID int64) ([]*models.SpecReviewDecision, error)
+}
+
+// SpecPromotionRepository defines operations for the promotions of OpenAPI specs to labels
+type SpecPromotionRepository interface {
+	WithTx(tx bun.Tx) SpecPromotionRepository
+	Create(ctx context.Context, promotion *models.SpecPromotion) error
+	GetByID(ctx context.Context, id int64) (*models.SpecPromotion, error)
+	Current(ctx context.Context, specID int64, label string) (*models.SpecPromotion, error)
+	ListCurrent(ctx context.Context, specID int64) ([]*models.SpecPromotion, error)
+	ListBySpecID(ctx context.Context, specID int64, offset, limit int) ([]*models.SpecPromotion, error)
+	CountBySpecID(ctx context.Context, specID int64) (int, error)
+}
+
+// StorageRepository defines queries over the storage footprint of the database
+type StorageRepository interface {
+	TableUsage(ctx context.Context) ([]models.TableStorage, error)
+	TeamUsage(ctx context.Context) ([]models.TeamStorage, error)
+	CollectionUsage(ctx context.Context, limit int) ([]models.CollectionStorage, error)
+}
+
+// OutboxRepository defines operations for the event outbox
+type OutboxRepository interface {
+	Create(ctx context.Context, event *models.OutboxEvent) error
+	ListPending(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+	WithTx(tx bun.Tx) OutboxRepository
+}
+
+// JobRepository defines operations for the persistent job queue
+type JobRepository interface {
+	Create(ctx context.Context, job *models.Job) error
+	GetByID(ctx context.Context, id int64) (*models.Job, error)
+	List(ctx context.Context, filter models.JobFilter, offset, limit int) ([]*models.Job, error)
+	Count(ctx context.Context, filter models.JobFilter) (int, error)
+	Claim(ctx context.Context, now time.Time) (*models.Job, error)
+	Update(ctx context.Context, job *models.Job) error
+	SetProgress(ctx context.Context, id int64, progress models.JSONMap) error
+	RequeueStale(ctx context.Context, startedBefore time.Time) (int, error)
+}
+
+// SpecSourceRepository defines operations for spec source persistence
+type SpecSourceRepository interface {
+	Create(ctx context.Context, source *models.SpecSource) error
+	GetByID(ctx context.Context, id int64) (*models.SpecSource, error)
+	List(ctx context.Context, offset, limit int) ([]*models.SpecSource, error)
+	ListDue(ctx context.Context, now time.Time) ([]*models.SpecSource, error)
+	Update(ctx context.Context, source *models.SpecSource) error
+	Delete(ctx context.Context, id int64) error
+	Count(ctx context.Context) (int, error)
+}
+
+// DigestRepository defines operations for digest subscription persistence
+type DigestRepository interface {
+	Create(ctx context.Context, subscription *models.DigestSubscription) error
+	GetByID(ctx context.Context, id int64) (*models.DigestSubscription, error)
+	List(ctx context.Context, offset, limit int) ([]*models.DigestSubscription, error)
+	ListDue(ctx context.Context, now time.Time) ([]*models.DigestSubscription, error)
+	Update(ctx context.Context, subscription *models.DigestSubscription) error
+	Delete(ctx context.Context, id int64) error
+	Count(ctx context.Context) (int, error)
+}
+
+// AttachmentRepository defines operations for attachment persistence
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetByID(ctx context.Context, id int64) (*models.Attachment, error)
+	GetByHash(ctx context.Context, hash string) (*models.Attachment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// EnvironmentRepository defines operations for environment persistence
+type EnvironmentRepository interface {
+	Create(ctx context.Context, env *models.Environment) error
+	GetByID(ctx context.Context, id int64) (*models.Environment, error)
+	List(ctx context.Context, offset, limit int) ([]*models.Environment, error)
+	Update(ctx context.Context, env *models.Environment) error
+	Delete(ctx context.Context, id int64) error
+	Count(ctx context.Context) (int, error)
+	ExistsByMetadata(ctx context.Context, key, value string) (bool, error)
+}
+
+// GlobalVariableRepository defines operations for global variable persistence
+type GlobalVariableRepository interface {
+	GetByKey(ctx context.Context, key string) (*models.GlobalVariable, error)
+	List(ctx context.Context, offset, limit int) ([]*models.GlobalVariable, error)
+	ListAll(ctx context.Context) ([]*models.GlobalVariable, error)
+	Count(ctx context.Context) (int, error)
+	Set(ctx context.Context, variable *models.GlobalVariable) (*models.GlobalVariableChange, error)
+	Delete(ctx context.Context, key string) error
+	ListChanges(ctx context.Context, key string, offset, limit int) ([]*models.GlobalVariableChange, error)
+	CountChanges(ctx context.Context, key string) (int, error)
+}
+
+// HeaderPresetRepository defines operations for header preset persistence
+type HeaderPresetRepository interface {
+	Create(ctx context.Context, preset *models.HeaderPreset) error
+	GetByID(ctx context.Context, id int64) (*models.HeaderPreset, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]*models.HeaderPreset, error)
+	List(ctx context.Context, offset, limit int) ([]*models.HeaderPreset, error)
+	Update(ctx context.Context, preset *models.HeaderPreset) error
+	Delete(ctx context.Context, id int64) error
+	Count(ctx context.Context) (int, error)
+	CountRequests(ctx context.Context, id int64) (int, error)
+}
+
+// InventoryOwnershipRepository defines operations for inventory entry ownership persistence
+type InventoryOwnershipRepository interface {
+	Upsert(ctx context.Context, ownership *models.InventoryOwnership) error
+	List(ctx context.Context) ([]*models.InventoryOwnership, error)
+	Delete(ctx context.Context, key string) error
 }
@@ -2,42 +2,334 @@ package interfaces
 
 import (
 	"context"
+	"io"
+	"postman-api/internal/events"
 	"postman-api/internal/models"
+	"time"
 )
 
 // CollectionService defines operations for managing collections
 type CollectionService interface {
 	CreateCollection(ctx context.Context, collection *models.Collection) error
 	GetCollection(ctx context.Context, id int64) (*models.Collection, error)
+	GetCollections(ctx context.Context, ids []int64) ([]*models.Collection, error)
 	GetCollectionWithRequests(ctx context.Context, id int64) (*models.Collection, error)
-	ListCollections(ctx context.Context, page, pageSize int) ([]*models.Collection, int, error)
+	ListCollections(ctx context.Context, page, pageSize int, countMode models.CountMode, includeArchived bool) ([]*models.Collection, models.Total, error)
 	UpdateCollection(ctx context.Context, collection *models.Collection) error
 	DeleteCollection(ctx context.Context, id int64) error
-	ImportPostmanCollection(ctx context.Context, data []byte) (int64, error)
+	SetCollectionArchived(ctx context.Context, id int64, archived bool) (*models.Collection, error)
+	ArchiveCollections(ctx context.Context, ids []int64, archived bool) (int, error)
+	ImportPostmanCollection(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error)
+	MigrateLegacyItems(ctx context.Context, migration *models.ItemsMigration, report func(*models.ItemsMigration) error) error
 	ExportPostmanCollection(ctx context.Context, id int64) ([]byte, error)
+	ValidatePostmanCollection(ctx context.Context, data []byte) (*models.ValidationResult, error)
+	ListCollectionItems(ctx context.Context, collectionID int64) ([]*models.CollectionItem, error)
+	AddCollectionItem(ctx context.Context, collectionID int64, entry *models.CollectionItem) ([]int64, error)
+	UpdateCollectionItem(ctx context.Context, collectionID, requestID int64, entry *models.CollectionItem) error
+	RemoveCollectionItem(ctx context.Context, collectionID, requestID int64) error
+	RenameFolder(ctx context.Context, collectionID int64, from, to string) (int, error)
+	ListRevisions(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.CollectionRevision, int, error)
+	GetRevision(ctx context.Context, id int64) (*models.CollectionRevision, error)
+	RollbackCollection(ctx context.Context, collectionID, revisionID int64) (*models.Collection, error)
+}
+
+// FolderService defines operations for managing the folders of a collection
+type FolderService interface {
+	ListFolders(ctx context.Context, collectionID int64) ([]*models.Folder, error)
+	GetFolder(ctx context.Context, collectionID, id int64) (*models.Folder, error)
+	CreateFolder(ctx context.Context, collectionID int64, input *models.FolderInput) (*models.Folder, error)
+	UpdateFolder(ctx context.Context, collectionID, id int64, input *models.FolderInput) (*models.Folder, error)
+	DeleteFolder(ctx context.Context, collectionID, id int64) (int, error)
 }
 
 // RequestService defines operations for managing API requests
 type RequestService interface {
 	CreateRequest(ctx context.Context, request *models.Request) error
 	GetRequest(ctx context.Context, id int64) (*models.Request, error)
-	ListRequests(ctx context.Context, page, pageSize int) ([]*models.Request, int, error)
-	ListRequestsByCollection(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.Request, int, error)
+	GetRequests(ctx context.Context, ids []int64) ([]*models.Request, error)
+	OpenResponseBody(ctx context.Context, id int64, index int) (*models.PostmanResponse, io.ReadCloser, error)
+	ListRequests(ctx context.Context, page, pageSize int, countMode models.CountMode) ([]*models.Request, models.Total, error)
+	ListRequestsByCollection(ctx context.Context, collectionID int64, page, pageSize int, countMode models.CountMode) ([]*models.Request, models.Total, error)
 	DeleteRequest(ctx context.Context, id int64) error
 	UpdateRequestPayload(ctx context.Context, id int64, body models.JSONMap) error
 	UpdateRequestHeaders(ctx context.Context, id int64, headers map[string]string) error
+	UpdateRequestHeaderPresets(ctx context.Context, id int64, presetIDs []int64) error
 	UpdateRequestParams(ctx context.Context, id int64, params models.JSONMap) error
-	CloneRequest(ctx context.Context, id int64, newName string) (int64, error)
+	UpdateRequestAssertions(ctx context.Context, id int64, assertions []models.Assertion) error
+	UpdateRequestDeprecation(ctx context.Context, id int64, deprecated bool, sunset *time.Time) error
+	UpdateRequestRunSettings(ctx context.Context, id int64, settings models.RunSettings) error
+	CloneRequest(ctx context.Context, id int64, newName string, targetCollectionID int64, folderPath *string) (int64, error)
+	MoveRequest(ctx context.Context, id int64, targetCollectionID int64, folderPath *string) (*models.Request, error)
+}
+
+// ExampleService defines operations for managing the saved examples of requests
+type ExampleService interface {
+	ListExamples(ctx context.Context, requestID int64) ([]*models.Example, error)
+	GetExample(ctx context.Context, requestID, id int64) (*models.Example, error)
+	CreateExample(ctx context.Context, requestID int64, example *models.Example) error
+	UpdateExample(ctx context.Context, requestID, id int64, example *models.Example) error
+	DeleteExample(ctx context.Context, requestID, id int64) error
+	OpenExampleBody(ctx context.Context, requestID, id int64) (*models.Example, io.ReadCloser, error)
+}
+
+// GlobalVariableService defines operations for managing global variables
+type GlobalVariableService interface {
+	ListGlobals(ctx context.Context, page, pageSize int) ([]*models.GlobalVariable, int, error)
+	GetGlobal(ctx context.Context, key string) (*models.GlobalVariable, error)
+	SetGlobal(ctx context.Context, variable *models.GlobalVariable) error
+	DeleteGlobal(ctx context.Context, key string) error
+	ListGlobalChanges(ctx context.Context, key string, page, pageSize int) ([]*models.GlobalVariableChange, int, error)
+	ResolveGlobals(ctx context.Context) (map[string]string, error)
+}
+
+// HeaderPresetService defines operations for managing header presets
+type HeaderPresetService interface {
+	CreatePreset(ctx context.Context, preset *models.HeaderPreset) error
+	GetPreset(ctx context.Context, id int64) (*models.HeaderPreset, error)
+	ListPresets(ctx context.Context, page, pageSize int) ([]*models.HeaderPreset, int, error)
+	UpdatePreset(ctx context.Context, preset *models.HeaderPreset) error
+	DeletePreset(ctx context.Context, id int64) error
 }
 
 // OpenAPIService defines operations for managing OpenAPI specifications
 type OpenAPIService interface {
 	CreateOpenAPISpec(ctx context.Context, spec *models.OpenAPISpec) error
 	GetOpenAPISpec(ctx context.Context, id int64) (*models.OpenAPISpec, error)
+	GetOpenAPISpecs(ctx context.Context, ids []int64) ([]*models.OpenAPISpec, error)
 	GetOpenAPISpecByTitle(ctx context.Context, title string) (*models.OpenAPISpec, error)
-	ListOpenAPISpecs(ctx context.Context, page, pageSize int) ([]*models.OpenAPISpec, int, error)
+	ListOpenAPISpecs(ctx context.Context, page, pageSize int, countMode models.CountMode) ([]*models.OpenAPISpec, models.Total, error)
+	SearchOpenAPISpecs(ctx context.Context, query string, page, pageSize int) ([]*models.OpenAPISpec, int, error)
 	UpdateOpenAPISpec(ctx context.Context, spec *models.OpenAPISpec) error
 	DeleteOpenAPISpec(ctx context.Context, id int64) error
-	ImportOpenAPISpec(ctx context.Context, data []byte) (int64, error)
+	ListSpecRevisions(ctx context.Context, id int64, page, pageSize int) ([]*models.OpenAPISpecRevision, int, error)
+	DiffOpenAPISpec(ctx context.Context, id int64, from, to int) (*models.SpecDiff, error)
+	CompareOpenAPISpec(ctx context.Context, id, againstSpecID int64, againstRevision int) (*models.SpecComparison, error)
+	ImportOpenAPISpec(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error)
 	ExportOpenAPISpec(ctx context.Context, id int64) ([]byte, error)
+	ValidateOpenAPISpec(ctx context.Context, data []byte) (*models.ValidationResult, error)
+	GenerateTypeScript(ctx context.Context, id int64) ([]byte, error)
+	GenerateGoClient(ctx context.Context, id int64, pkg string) ([]byte, error)
+	GenerateGoServer(ctx context.Context, id int64, pkg, framework string) ([]byte, error)
+	GenerateProto(ctx context.Context, id int64, pkg string) ([]byte, error)
+	ExportGateway(ctx context.Context, id int64, target string) ([]byte, error)
+	ExportKubernetes(ctx context.Context, id int64, opts models.ManifestOptions) ([]byte, error)
+	GenerateTerraform(ctx context.Context, id int64, provider string) ([]byte, error)
+}
+
+// SpecReviewService defines operations for the review and approval of OpenAPI specs
+type SpecReviewService interface {
+	GetSpecReview(ctx context.Context, specID int64) (*models.SpecReview, error)
+	DecideSpecReview(ctx context.Context, decision *models.SpecReviewDecision) (*models.SpecReview, error)
+	RequireApproved(ctx context.Context, specID int64) error
+}
+
+// SpecPromotionService defines operations for promoting OpenAPI specs through environment-of-record labels
+type SpecPromotionService interface {
+	ListSpecLabels(ctx context.Context, specID int64) ([]*models.SpecPromotion, error)
+	GetSpecLabel(ctx context.Context, specID int64, label string) (*models.SpecPromotion, error)
+	ListSpecPromotions(ctx context.Context, specID int64, page, pageSize int) ([]*models.SpecPromotion, int, error)
+	PromoteSpec(ctx context.Context, specID int64, label, promotedBy string) (*models.SpecPromotion, error)
+	RunPromotionExport(ctx context.Context, promotionID int64, export string) (string, error)
+}
+
+// ScannerService defines operations for detecting secrets and PII in stored data
+type ScannerService interface {
+	ScanCollection(ctx context.Context, collectionID int64) (*models.ScanReport, error)
+}
+
+// ContractTestService defines operations for generating contract tests from specifications
+type ContractTestService interface {
+	GenerateContractTests(ctx context.Context, specID int64) (*models.Collection, error)
+}
+
+// ConversionService defines operations for turning specifications into collections
+type ConversionService interface {
+	ConvertToCollection(ctx context.Context, specID int64) (*models.Collection, error)
+	SyncFromSpec(ctx context.Context, collectionID int64) (*models.SpecSyncReport, error)
+}
+
+// RunnerService defines operations for sending stored requests and running collections
+type RunnerService interface {
+	ExecuteRequest(ctx context.Context, id int64, opts models.ExecuteOptions) (*models.ExecutionResult, error)
+	RunCollection(ctx context.Context, collectionID int64, opts models.RunOptions) (*models.Run, error)
+	ExecuteRun(ctx context.Context, runID int64, opts models.RunOptions) (*models.Run, error)
+	GetRun(ctx context.Context, id int64) (*models.Run, error)
+	GetRunMetrics(ctx context.Context, id int64) (*models.RunMetrics, error)
+	GetSnapshot(ctx context.Context, requestID int64) (*models.ResponseSnapshot, error)
+	ListRuns(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.Run, int, error)
+	LoadTestCollection(ctx context.Context, collectionID int64, opts models.LoadTestOptions) (*models.LoadTest, error)
+	LoadTestRequest(ctx context.Context, requestID int64, opts models.LoadTestOptions) (*models.LoadTest, error)
+	ExecuteLoadTest(ctx context.Context, testID int64, opts models.LoadTestOptions) (*models.LoadTest, error)
+	GetLoadTest(ctx context.Context, id int64) (*models.LoadTest, error)
+}
+
+// FlattenService defines operations for resolving collections into plain requests
+type FlattenService interface {
+	FlattenCollection(ctx context.Context, collectionID, environmentID int64) ([]*models.FlatRequest, error)
+	ResolveRequest(ctx context.Context, requestID, environmentID int64) (*models.ResolvedRequest, error)
+}
+
+// DocsService defines operations for rendering collection documentation
+type DocsService interface {
+	CollectionDocs(ctx context.Context, collectionID, environmentID int64, format string) ([]byte, error)
+}
+
+// SecurityService defines operations for exchanging targets and findings with security scanners
+type SecurityService interface {
+	ExportTargets(ctx context.Context, collectionID, environmentID int64, format string) ([]byte, error)
+	IngestFindings(ctx context.Context, collectionID int64, payload *models.SecurityFindingsPayload) (*models.SecurityIngestResult, error)
+	ListFindings(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.SecurityFinding, int, error)
+}
+
+// LintService defines operations for checking collections against conventions
+type LintService interface {
+	LintCollection(ctx context.Context, collectionID int64, opts models.LintOptions) (*models.LintReport, error)
+}
+
+// SpecSourceService defines operations for managing polled spec sources
+type SpecSourceService interface {
+	CreateSpecSource(ctx context.Context, source *models.SpecSource) error
+	GetSpecSource(ctx context.Context, id int64) (*models.SpecSource, error)
+	ListSpecSources(ctx context.Context, page, pageSize int) ([]*models.SpecSource, int, error)
+	UpdateSpecSource(ctx context.Context, source *models.SpecSource) error
+	DeleteSpecSource(ctx context.Context, id int64) error
+	RefreshSpecSource(ctx context.Context, id int64) (*models.SpecSourceRefresh, error)
+	PollDueSources(ctx context.Context) error
+	RunPoller(ctx context.Context, interval time.Duration)
+}
+
+// DigestService defines operations for digest subscriptions and sending their digests
+type DigestService interface {
+	CreateDigestSubscription(ctx context.Context, subscription *models.DigestSubscription) error
+	GetDigestSubscription(ctx context.Context, id int64) (*models.DigestSubscription, error)
+	ListDigestSubscriptions(ctx context.Context, page, pageSize int) ([]*models.DigestSubscription, int, error)
+	UpdateDigestSubscription(ctx context.Context, subscription *models.DigestSubscription) error
+	DeleteDigestSubscription(ctx context.Context, id int64) error
+	PreviewDigest(ctx context.Context, id int64) (*models.Digest, error)
+	SendDigest(ctx context.Context, id int64) (*models.Digest, error)
+	SendDueDigests(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// ImportHookService defines operations for webhook-triggered imports
+type ImportHookService interface {
+	HandleImport(ctx context.Context, payload *models.ImportHookPayload) (*models.ImportHookResult, error)
+	SealPayload(payload *models.ImportHookPayload) (models.JSONMap, error)
+	HandleQueuedImport(ctx context.Context, payload models.JSONMap) (*models.ImportHookResult, error)
+}
+
+// SeedService defines operations for loading development fixtures
+type SeedService interface {
+	Seed(ctx context.Context, dir string) (*models.SeedReport, error)
+}
+
+// HealthService defines readiness checks for the service's dependencies
+type HealthService interface {
+	Readiness(ctx context.Context) *models.Readiness
+}
+
+// AttachmentService defines operations for files referenced by request bodies
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, filename, contentType string, r io.Reader) (*models.Attachment, error)
+	GetAttachment(ctx context.Context, id int64) (*models.Attachment, error)
+	OpenAttachment(ctx context.Context, id int64) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+	ExportCollectionBundle(ctx context.Context, collectionID int64) ([]byte, error)
+	ImportCollectionBundle(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error)
+}
+
+// BulkImportService defines operations for importing many collections at once
+type BulkImportService interface {
+	IsArchive(data []byte) bool
+	ImportCollections(ctx context.Context, files []models.ImportFile, opts models.ImportOptions) (*models.BulkImportReport, error)
+	ImportWorkspace(ctx context.Context, file models.ImportFile, opts models.ImportOptions) (*models.WorkspaceImportReport, error)
+}
+
+// Converter imports collections from and exports them to one file format;
+// converters of formats that only go one way refuse the other direction
+type Converter interface {
+	Format() models.Format
+	// Detect reports whether data looks like a document in the format
+	Detect(data []byte) bool
+	Import(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error)
+	Export(ctx context.Context, collectionID int64) ([]byte, error)
+}
+
+// ConverterRegistry holds the converters of the formats collections can be
+// imported from and exported to
+type ConverterRegistry interface {
+	Register(converter Converter)
+	Converter(name string) (Converter, error)
+	Detect(data []byte) (Converter, error)
+	Formats() []models.Format
+}
+
+// EnvironmentService defines operations for managing environments; secret
+// values are only returned in plain text by ResolveEnvironment
+type EnvironmentService interface {
+	CreateEnvironment(ctx context.Context, env *models.Environment) error
+	GetEnvironment(ctx context.Context, id int64) (*models.Environment, error)
+	ListEnvironments(ctx context.Context, page, pageSize int) ([]*models.Environment, int, error)
+	UpdateEnvironment(ctx context.Context, env *models.Environment) error
+	DeleteEnvironment(ctx context.Context, id int64) error
+	ResolveEnvironment(ctx context.Context, id int64) (map[string]string, error)
+	ImportPostmanEnvironment(ctx context.Context, data []byte, opts models.ImportOptions) (int64, error)
+	ExportPostmanEnvironment(ctx context.Context, id int64, includeSecrets bool) ([]byte, error)
+}
+
+// DeprecationService defines operations for tracking deprecated endpoints
+type DeprecationService interface {
+	ListDeprecated(ctx context.Context, filter models.DeprecationFilter) ([]*models.DeprecatedEndpoint, error)
+}
+
+// InventoryService defines operations for the catalog-wide API inventory
+type InventoryService interface {
+	ListInventory(ctx context.Context, filter models.InventoryFilter) ([]*models.InventoryEntry, error)
+}
+
+// CatalogService defines operations for recording and listing API ownership
+type CatalogService interface {
+	SetCollectionOwnership(ctx context.Context, id int64, ownership models.Ownership) error
+	SetSpecOwnership(ctx context.Context, id int64, ownership models.Ownership) error
+	SetEndpointOwnership(ctx context.Context, key string, ownership models.Ownership) error
+	ListCatalog(ctx context.Context, filter models.CatalogFilter) ([]*models.CatalogEntry, error)
+}
+
+// StorageService defines operations for reporting storage usage
+type StorageService interface {
+	StorageReport(ctx context.Context, limit int) (*models.StorageReport, error)
+}
+
+// SigningService defines operations for signing exports and verifying them
+type SigningService interface {
+	SignExport(ctx context.Context, manifest models.ExportManifest, data []byte) (*models.SignedExport, error)
+	VerifyExport(ctx context.Context, export *models.SignedExport) (*models.ExportVerification, error)
+	SigningKey(ctx context.Context) (*models.SigningKey, error)
+}
+
+// RetentionService defines operations for purging items past their retention
+type RetentionService interface {
+	Report(ctx context.Context) (*models.RetentionReport, error)
+	Enforce(ctx context.Context) (*models.RetentionReport, error)
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// JobHandler runs a job of one type, returning a result stored with the job
+type JobHandler func(ctx context.Context, payload models.JSONMap) (models.JSONMap, error)
+
+// JobService defines operations for the background job queue and its workers
+type JobService interface {
+	Register(jobType string, handler JobHandler)
+	Enqueue(ctx context.Context, jobType string, payload models.JSONMap) (*models.Job, error)
+	GetJob(ctx context.Context, id int64) (*models.Job, error)
+	ListJobs(ctx context.Context, filter models.JobFilter, page, pageSize int) ([]*models.Job, int, error)
+	RetryJob(ctx context.Context, id int64) (*models.Job, error)
+	CancelJob(ctx context.Context, id int64) (*models.Job, error)
+	RunWorkers(ctx, jobCtx context.Context, workers int, pollInterval time.Duration)
+}
+
+// EventRelayService defines operations for publishing events through the outbox
+type EventRelayService interface {
+	events.Publisher
+	RunRelay(ctx context.Context, interval time.Duration)
 }
@@ -0,0 +1,182 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+// proxyRequest covers API Gateway HTTP API (payload 2.0) and REST API
+// (payload 1.0) proxy events, as well as Lambda function URL events
+type proxyRequest struct {
+	Version string `json:"version"`
+
+	// Payload 2.0
+	RawPath        string   `json:"rawPath"`
+	RawQueryString string   `json:"rawQueryString"`
+	Cookies        []string `json:"cookies"`
+
+	// Payload 1.0
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+	RequestContext  struct {
+		HTTP struct {
+			Method   string `json:"method"`
+			SourceIP string `json:"sourceIp"`
+		} `json:"http"`
+		Identity struct {
+			SourceIP string `json:"sourceIp"`
+		} `json:"identity"`
+	} `json:"requestContext"`
+}
+
+// proxyResponse is understood by both API Gateway payload versions
+type proxyResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+// Invoke serves a single API Gateway proxy event with handler and returns
+// the proxy response payload
+func Invoke(ctx context.Context, handler http.Handler, payload []byte) ([]byte, error) {
+	var event proxyRequest
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy event: %w", err)
+	}
+
+	req, err := event.httpRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return json.Marshal(newProxyResponse(recorder, event.Version == "2.0"))
+}
+
+func (e *proxyRequest) httpRequest(ctx context.Context) (*http.Request, error) {
+	v2 := e.Version == "2.0"
+
+	method, path, remoteIP := e.HTTPMethod, e.Path, e.RequestContext.Identity.SourceIP
+	query := url.Values{}
+	if v2 {
+		method, path, remoteIP = e.RequestContext.HTTP.Method, e.RawPath, e.RequestContext.HTTP.SourceIP
+		parsed, err := url.ParseQuery(e.RawQueryString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query string: %w", err)
+		}
+		query = parsed
+	} else if len(e.MultiValueQueryStringParameters) > 0 {
+		for key, values := range e.MultiValueQueryStringParameters {
+			query[key] = values
+		}
+	} else {
+		for key, value := range e.QueryStringParameters {
+			query.Set(key, value)
+		}
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	body := []byte(e.Body)
+	if e.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode request body: %w", err)
+		}
+		body = decoded
+	}
+
+	target := &url.URL{Path: path, RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	for key, values := range e.MultiValueHeaders {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	for key, value := range e.Headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+	if len(e.Cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(e.Cookies, "; "))
+	}
+
+	req.Host = req.Header.Get("Host")
+	req.RemoteAddr = remoteIP + ":0"
+	req.ContentLength = int64(len(body))
+
+	return req, nil
+}
+
+func newProxyResponse(recorder *httptest.ResponseRecorder, v2 bool) *proxyResponse {
+	result := recorder.Result()
+	body := recorder.Body.Bytes()
+
+	resp := &proxyResponse{StatusCode: result.StatusCode}
+	if isTextContent(result.Header.Get("Content-Type")) {
+		resp.Body = string(body)
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(body)
+		resp.IsBase64Encoded = true
+	}
+
+	if v2 {
+		resp.Headers = make(map[string]string, len(result.Header))
+		for key, values := range result.Header {
+			if key == "Set-Cookie" {
+				resp.Cookies = values
+				continue
+			}
+			resp.Headers[key] = strings.Join(values, ",")
+		}
+		return resp
+	}
+
+	resp.MultiValueHeaders = result.Header
+	return resp
+}
+
+func isTextContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "text/") {
+		return true
+	}
+
+	for _, kind := range []string{"json", "xml", "javascript", "typescript", "yaml"} {
+		if strings.Contains(contentType, kind) {
+			return true
+		}
+	}
+
+	return false
+}
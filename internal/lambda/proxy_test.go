@@ -0,0 +1,282 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// seenRequest is what the handler under test received
+type seenRequest struct {
+	Method     string
+	Path       string
+	Query      string
+	Host       string
+	RemoteAddr string
+	Headers    map[string][]string
+	Body       string
+	Length     int64
+}
+
+func TestInvokeRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  seenRequest
+	}{
+		{
+			name: "HTTP API payload 2.0",
+			event: `{
+				"version": "2.0",
+				"rawPath": "/api/v1/postman/1",
+				"rawQueryString": "page=2&tag=a&tag=b",
+				"cookies": ["session=abc", "theme=dark"],
+				"headers": {"host": "api.example.com", "content-type": "application/json", "x-request-id": "r-1"},
+				"body": "{\"name\":\"Pets\"}",
+				"requestContext": {"http": {"method": "PUT", "sourceIp": "203.0.113.7"}}
+			}`,
+			want: seenRequest{
+				Method:     "PUT",
+				Path:       "/api/v1/postman/1",
+				Query:      "page=2&tag=a&tag=b",
+				Host:       "api.example.com",
+				RemoteAddr: "203.0.113.7:0",
+				Headers: map[string][]string{
+					"Content-Type": {"application/json"},
+					"Cookie":       {"session=abc; theme=dark"},
+					"Host":         {"api.example.com"},
+					"X-Request-Id": {"r-1"},
+				},
+				Body:   `{"name":"Pets"}`,
+				Length: 15,
+			},
+		},
+		{
+			name: "REST API payload 1.0 with multi-value parameters",
+			event: `{
+				"httpMethod": "GET",
+				"path": "/api/v1/openapi",
+				"queryStringParameters": {"tag": "b"},
+				"multiValueQueryStringParameters": {"tag": ["a", "b"]},
+				"headers": {"Accept": "application/json", "X-Forwarded-For": "198.51.100.1"},
+				"multiValueHeaders": {"Accept": ["application/json", "text/plain"]},
+				"requestContext": {"identity": {"sourceIp": "198.51.100.1"}}
+			}`,
+			want: seenRequest{
+				Method:     "GET",
+				Path:       "/api/v1/openapi",
+				Query:      "tag=a&tag=b",
+				RemoteAddr: "198.51.100.1:0",
+				Headers: map[string][]string{
+					"Accept":          {"application/json", "text/plain"},
+					"X-Forwarded-For": {"198.51.100.1"},
+				},
+			},
+		},
+		{
+			name: "REST API payload 1.0 with single-value parameters",
+			event: `{
+				"httpMethod": "DELETE",
+				"path": "/api/v1/jobs/3",
+				"queryStringParameters": {"force": "true"}
+			}`,
+			want: seenRequest{
+				Method:     "DELETE",
+				Path:       "/api/v1/jobs/3",
+				Query:      "force=true",
+				RemoteAddr: ":0",
+				Headers:    map[string][]string{},
+			},
+		},
+		{
+			name: "base64 body",
+			event: `{
+				"version": "2.0",
+				"rawPath": "/api/v1/attachments",
+				"body": "` + base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G'}) + `",
+				"isBase64Encoded": true,
+				"requestContext": {"http": {"method": "POST"}}
+			}`,
+			want: seenRequest{
+				Method:     "POST",
+				Path:       "/api/v1/attachments",
+				RemoteAddr: ":0",
+				Headers:    map[string][]string{},
+				Body:       "\x89PNG",
+				Length:     4,
+			},
+		},
+		{
+			name:  "missing path",
+			event: `{"version": "2.0", "requestContext": {"http": {"method": "GET"}}}`,
+			want: seenRequest{
+				Method:     "GET",
+				Path:       "/",
+				RemoteAddr: ":0",
+				Headers:    map[string][]string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen seenRequest
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				seen = seenRequest{
+					Method:     r.Method,
+					Path:       r.URL.Path,
+					Query:      r.URL.RawQuery,
+					Host:       r.Host,
+					RemoteAddr: r.RemoteAddr,
+					Headers:    r.Header,
+					Body:       string(body),
+					Length:     r.ContentLength,
+				}
+			})
+
+			if _, err := Invoke(context.Background(), handler, []byte(tt.event)); err != nil {
+				t.Fatalf("Invoke() = %v", err)
+			}
+
+			if !reflect.DeepEqual(seen, tt.want) {
+				t.Errorf("handler got\n%+v\nwant\n%+v", seen, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvokeResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		contentType string
+		body        []byte
+		want        proxyResponse
+	}{
+		{
+			name:        "payload 2.0 moves cookies out of the headers",
+			version:     "2.0",
+			contentType: "application/json; charset=utf-8",
+			body:        []byte(`{"success":true}`),
+			want: proxyResponse{
+				StatusCode: http.StatusCreated,
+				Headers: map[string]string{
+					"Content-Type": "application/json; charset=utf-8",
+					"Vary":         "Accept,Origin",
+				},
+				Cookies: []string{"a=1", "b=2"},
+				Body:    `{"success":true}`,
+			},
+		},
+		{
+			name:        "payload 1.0 keeps multi-value headers",
+			contentType: "text/plain",
+			body:        []byte("ok"),
+			want: proxyResponse{
+				StatusCode: http.StatusCreated,
+				MultiValueHeaders: map[string][]string{
+					"Content-Type": {"text/plain"},
+					"Set-Cookie":   {"a=1", "b=2"},
+					"Vary":         {"Accept", "Origin"},
+				},
+				Body: "ok",
+			},
+		},
+		{
+			name:        "binary bodies are base64 encoded",
+			version:     "2.0",
+			contentType: "application/zip",
+			body:        []byte{'P', 'K', 0x03, 0x04},
+			want: proxyResponse{
+				StatusCode: http.StatusCreated,
+				Headers: map[string]string{
+					"Content-Type": "application/zip",
+					"Vary":         "Accept,Origin",
+				},
+				Cookies:         []string{"a=1", "b=2"},
+				Body:            base64.StdEncoding.EncodeToString([]byte{'P', 'K', 0x03, 0x04}),
+				IsBase64Encoded: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Add("Set-Cookie", "a=1")
+				w.Header().Add("Set-Cookie", "b=2")
+				w.Header().Add("Vary", "Accept")
+				w.Header().Add("Vary", "Origin")
+				w.WriteHeader(http.StatusCreated)
+				w.Write(tt.body)
+			})
+
+			event := `{"version":"` + tt.version + `","httpMethod":"GET","path":"/","requestContext":{"http":{"method":"GET"}}}`
+			payload, err := Invoke(context.Background(), handler, []byte(event))
+			if err != nil {
+				t.Fatalf("Invoke() = %v", err)
+			}
+
+			var got proxyResponse
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("invalid proxy response %s: %v", payload, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Invoke() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvokeInvalidEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+	}{
+		{"not JSON", `{"version":`},
+		{"invalid base64 body", `{"version":"2.0","body":"%%%","isBase64Encoded":true,"requestContext":{"http":{"method":"POST"}}}`},
+		{"invalid query string", `{"version":"2.0","rawQueryString":"a=%zz","requestContext":{"http":{"method":"GET"}}}`},
+		{"invalid method", `{"httpMethod":"GET /","path":"/"}`},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler called for an invalid event")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Invoke(context.Background(), handler, []byte(tt.event)); err == nil {
+				t.Error("Invoke() = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestIsTextContent(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"", true},
+		{"text/html; charset=utf-8", true},
+		{"application/json", true},
+		{"application/problem+json", true},
+		{"application/xml", true},
+		{"application/x-yaml", true},
+		{"application/javascript", true},
+		{"application/octet-stream", false},
+		{"image/png", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTextContent(tt.contentType); got != tt.want {
+			t.Errorf("isTextContent(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
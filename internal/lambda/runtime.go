@@ -0,0 +1,98 @@
+// Package lambda serves an http.Handler from AWS Lambda through the custom
+// runtime API, translating API Gateway proxy events into HTTP requests.
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const runtimeAPIVersion = "2018-06-01"
+
+// Start polls the Lambda runtime API for invocations and serves each with
+// handler. It only returns if the runtime API becomes unreachable.
+func Start(handler http.Handler) error {
+	api := os.Getenv("AWS_LAMBDA_RUNTIME_API")
+	if api == "" {
+		return errors.New("AWS_LAMBDA_RUNTIME_API is not set: not running inside Lambda")
+	}
+
+	runtime := &runtimeClient{
+		base: fmt.Sprintf("http://%s/%s/runtime", api, runtimeAPIVersion),
+		// Waiting for the next invocation is a long poll, so no client timeout
+		client: &http.Client{},
+	}
+
+	for {
+		if err := runtime.serveNext(handler); err != nil {
+			return err
+		}
+	}
+}
+
+type runtimeClient struct {
+	base   string
+	client *http.Client
+}
+
+// serveNext handles one invocation; handler failures are reported to the
+// runtime API, only runtime API failures are returned
+func (r *runtimeClient) serveNext(handler http.Handler) error {
+	resp, err := r.client.Get(r.base + "/invocation/next")
+	if err != nil {
+		return fmt.Errorf("failed to fetch next invocation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read invocation: %w", err)
+	}
+
+	requestID := resp.Header.Get("Lambda-Runtime-Aws-Request-Id")
+	if traceID := resp.Header.Get("Lambda-Runtime-Trace-Id"); traceID != "" {
+		os.Setenv("_X_AMZN_TRACE_ID", traceID)
+	}
+
+	ctx := context.Background()
+	if ms, err := strconv.ParseInt(resp.Header.Get("Lambda-Runtime-Deadline-Ms"), 10, 64); err == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, time.UnixMilli(ms))
+		defer cancel()
+	}
+
+	out, err := Invoke(ctx, handler, payload)
+	if err != nil {
+		body, _ := json.Marshal(map[string]string{
+			"errorMessage": err.Error(),
+			"errorType":    "InvocationError",
+		})
+		return r.post("/invocation/"+requestID+"/error", body)
+	}
+
+	return r.post("/invocation/"+requestID+"/response", out)
+}
+
+func (r *runtimeClient) post(path string, body []byte) error {
+	resp, err := r.client.Post(r.base+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to runtime API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A rejected result, e.g. an oversized response, fails only this invocation
+	if resp.StatusCode >= 300 {
+		log.Printf("runtime API rejected %s with status %d", path, resp.StatusCode)
+	}
+
+	return nil
+}
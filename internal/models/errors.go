@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCode is a machine-readable error code returned in API error responses
+type ErrorCode string
+
+// Error codes
+const (
+	ErrCodeNotFound     ErrorCode = "not_found"
+	ErrCodeValidation   ErrorCode = "validation_failed"
+	ErrCodeConflict     ErrorCode = "conflict"
+	ErrCodeTooLarge     ErrorCode = "payload_too_large"
+	ErrCodeUnauthorized ErrorCode = "unauthorized"
+	ErrCodeUpstream     ErrorCode = "upstream_failed"
+	ErrCodeUnavailable  ErrorCode = "service_unavailable"
+	ErrCodeInternal     ErrorCode = "internal_error"
+)
+
+// Error is a domain error with a code and a message that is safe to show to
+// API clients; the wrapped cause is kept for logging only
+type Error struct {
+	Code    ErrorCode
+	Message string
+	// Fields lists per-field failures of a validation error
+	Fields []ValidationIssue
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates a domain error with code and message
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// NewNotFoundError reports that a resource does not exist
+func NewNotFoundError(resource string, err error) *Error {
+	return &Error{Code: ErrCodeNotFound, Message: resource + " not found", Err: err}
+}
+
+// NewValidationError reports invalid input
+func NewValidationError(format string, args ...any) *Error {
+	return &Error{Code: ErrCodeValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewConflictError reports a write that conflicts with existing data
+func NewConflictError(message string, err error) *Error {
+	return &Error{Code: ErrCodeConflict, Message: message, Err: err}
+}
+
+// AsError returns the domain error in err's chain, if any
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// ErrorCodeOf returns the code of the domain error in err's chain, or
+// ErrCodeInternal for untyped errors
+func ErrorCodeOf(err error) ErrorCode {
+	if e, ok := AsError(err); ok {
+		return e.Code
+	}
+	return ErrCodeInternal
+}
+
+// FieldErrors collects per-field validation failures; Path names the field
+type FieldErrors []ValidationIssue
+
+// Add records a failure of field
+func (f *FieldErrors) Add(field, format string, args ...any) {
+	*f = append(*f, ValidationIssue{Path: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns a validation error listing the failures, or nil when there are none
+func (f FieldErrors) Err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(f))
+	for i, issue := range f {
+		messages[i] = issue.Path + ": " + issue.Message
+	}
+
+	return &Error{Code: ErrCodeValidation, Message: strings.Join(messages, "; "), Fields: f}
+}
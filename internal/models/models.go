@@ -11,18 +11,25 @@ import (
 type Collection struct {
 	bun.BaseModel `bun:"table:collections,alias:c"`
 
-	ID          int64     `bun:"id,pk,autoincrement" json:"id"`
-	Name        string    `bun:"name,notnull" json:"name"`
-	Description string    `bun:"description" json:"description"`
-	Schema      string    `bun:"schema" json:"schema"`
-	Variables   JSONMap   `bun:"variables,type:jsonb" json:"variables"`
-	Auth        JSONMap   `bun:"auth,type:jsonb" json:"auth,omitempty"`
-	Events      JSONMap   `bun:"events,type:jsonb" json:"events,omitempty"`
-	Items       JSONMap   `bun:"items,type:jsonb" json:"items,omitempty"`
-	PostmanID   string    `bun:"postman_id" json:"_postman_id,omitempty"`
-	ExporterID  string    `bun:"exporter_id" json:"_exporter_id,omitempty"`
-	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
-	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	ID          int64      `bun:"id,pk,autoincrement" json:"id"`
+	Name        string     `bun:"name,notnull" json:"name"`
+	Description string     `bun:"description" json:"description"`
+	Schema      string     `bun:"schema" json:"schema"`
+	Variables   JSONMap    `bun:"variables,type:jsonb" json:"variables"`
+	Auth        JSONMap    `bun:"auth,type:jsonb" json:"auth,omitempty"`
+	Events      JSONMap    `bun:"events,type:jsonb" json:"events,omitempty"`
+	Items       JSONMap    `bun:"items,type:jsonb" json:"items,omitempty"`
+	PostmanID   string     `bun:"postman_id" json:"_postman_id,omitempty"`
+	ExporterID  string     `bun:"exporter_id" json:"_exporter_id,omitempty"`
+	Metadata    JSONMap    `bun:"metadata,type:jsonb" json:"metadata,omitempty"`
+	Archived    bool       `bun:"archived,notnull,default:false" json:"archived"`
+	ArchivedAt  *time.Time `bun:"archived_at" json:"archived_at,omitempty"`
+	// RequestCount is kept up to date by the request repository as requests
+	// are created, deleted and moved between collections
+	RequestCount int       `bun:"request_count,notnull,default:0" json:"request_count"`
+	CreatedAt    time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt    time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	Links        Links     `bun:"-" json:"links,omitempty"`
 
 	Requests []*Request `bun:"rel:has-many,join:id=collection_id" json:"requests,omitempty"`
 }
@@ -31,26 +38,135 @@ type Collection struct {
 type Request struct {
 	bun.BaseModel `bun:"table:requests,alias:r"`
 
-	ID           int64             `bun:"id,pk,autoincrement" json:"id"`
-	CollectionID int64             `bun:"collection_id,notnull" json:"collection_id"`
-	Name         string            `bun:"name,notnull" json:"name"`
-	Description  string            `bun:"description" json:"description"`
-	FolderPath   string            `bun:"folder_path" json:"folder_path,omitempty"`
-	URL          JSONMap           `bun:"url,type:jsonb" json:"url"`
-	Method       string            `bun:"method,notnull" json:"method"`
-	Headers      map[string]string `bun:"headers,type:jsonb" json:"headers,omitempty"`
-	Params       JSONMap           `bun:"params,type:jsonb" json:"params,omitempty"`
-	Body         JSONMap           `bun:"body,type:jsonb" json:"body,omitempty"`
-	Auth         JSONMap           `bun:"auth,type:jsonb" json:"auth,omitempty"`
-	Events       JSONMap           `bun:"events,type:jsonb" json:"events,omitempty"`
-	Responses    JSONMap           `bun:"responses,type:jsonb" json:"responses,omitempty"`
-	PostmanID    string            `bun:"postman_id" json:"_postman_id,omitempty"`
-	CreatedAt    time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
-	UpdatedAt    time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	ID           int64  `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID int64  `bun:"collection_id,notnull" json:"collection_id"`
+	Name         string `bun:"name,notnull" json:"name"`
+	Description  string `bun:"description" json:"description"`
+	FolderID     int64  `bun:"folder_id,nullzero" json:"folder_id,omitempty"`
+	FolderPath   string `bun:"folder_path" json:"folder_path,omitempty"`
+	// Position orders the request among the requests and subfolders of its folder
+	Position int               `bun:"position,notnull" json:"position"`
+	URL      JSONMap           `bun:"url,type:jsonb" json:"url"`
+	Method   string            `bun:"method,notnull" json:"method"`
+	Headers  map[string]string `bun:"headers,type:jsonb" json:"headers,omitempty"`
+	// HeaderPresets are the IDs of the presets whose headers the request
+	// sends, in order, beneath its own headers
+	HeaderPresets []int64           `bun:"header_presets,type:jsonb" json:"header_presets,omitempty"`
+	Params        JSONMap           `bun:"params,type:jsonb" json:"params,omitempty"`
+	Body          JSONMap           `bun:"body,type:jsonb" json:"body,omitempty"`
+	Auth          JSONMap           `bun:"auth,type:jsonb" json:"auth,omitempty"`
+	Events        []PostmanEvent    `bun:"events,type:jsonb" json:"events,omitempty"`
+	Responses     []PostmanResponse `bun:"-" json:"responses,omitempty"`
+	Assertions    []Assertion       `bun:"assertions,type:jsonb" json:"assertions,omitempty"`
+	RunSettings   *RunSettings      `bun:"run_settings,type:jsonb" json:"run_settings,omitempty"`
+	Deprecated    bool              `bun:"deprecated,notnull" json:"deprecated,omitempty"`
+	Sunset        *time.Time        `bun:"sunset,type:date" json:"sunset,omitempty"`
+	PostmanID     string            `bun:"postman_id" json:"_postman_id,omitempty"`
+	CreatedAt     time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	Links         Links             `bun:"-" json:"links,omitempty"`
 
 	Collection *Collection `bun:"rel:belongs-to,join:collection_id=id" json:"collection,omitempty"`
 }
 
+// Folder groups requests and other folders within a collection; ParentID is
+// zero for top-level folders
+type Folder struct {
+	bun.BaseModel `bun:"table:folders,alias:f"`
+
+	ID           int64     `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID int64     `bun:"collection_id,notnull" json:"collection_id"`
+	ParentID     int64     `bun:"parent_id,nullzero" json:"parent_id,omitempty"`
+	Name         string    `bun:"name,notnull" json:"name"`
+	Description  string    `bun:"description" json:"description,omitempty"`
+	Position     int       `bun:"position,notnull" json:"position"`
+	PostmanID    string    `bun:"postman_id" json:"_postman_id,omitempty"`
+	CreatedAt    time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt    time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	// Path is the "a/b/c" path of the folder within its collection
+	Path  string `bun:"-" json:"path"`
+	Links Links  `bun:"-" json:"links,omitempty"`
+}
+
+// FolderInput creates or changes a folder; on update, nil fields are left
+// as they are and a ParentID of zero moves the folder to the top level
+type FolderInput struct {
+	Name        *string `json:"name"`
+	ParentID    *int64  `json:"parent_id"`
+	Description *string `json:"description"`
+	Position    *int    `json:"position"`
+}
+
+// Example is a saved example response of a request, stored in its own row.
+// The Responses of a request are its examples in Postman form.
+type Example struct {
+	bun.BaseModel `bun:"table:request_examples,alias:ex"`
+
+	ID              int64             `bun:"id,pk,autoincrement" json:"id"`
+	RequestID       int64             `bun:"request_id,notnull" json:"request_id"`
+	Position        int               `bun:"position,notnull" json:"position"`
+	Name            string            `bun:"name,notnull" json:"name"`
+	Status          string            `bun:"status" json:"status,omitempty"`
+	Code            int               `bun:"code" json:"code,omitempty"`
+	Header          []KeyValuePair    `bun:"headers,type:jsonb" json:"header,omitempty"`
+	Body            string            `bun:"body" json:"body,omitempty"`
+	OriginalRequest json.RawMessage   `bun:"original_request,type:jsonb" json:"originalRequest,omitempty"`
+	Cookie          []json.RawMessage `bun:"cookies,type:jsonb" json:"cookie,omitempty"`
+	PreviewLanguage string            `bun:"preview_language" json:"_postman_previewlanguage,omitempty"`
+	PostmanID       string            `bun:"postman_id" json:"_postman_id,omitempty"`
+	BodyRef         string            `bun:"body_ref" json:"_body_ref,omitempty"`
+	BodySize        int64             `bun:"body_size" json:"_body_size,omitempty"`
+	BodyTruncated   bool              `bun:"body_truncated,notnull" json:"_body_truncated,omitempty"`
+	CreatedAt       time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt       time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	Links           Links             `bun:"-" json:"links,omitempty"`
+}
+
+// NewExample stores a saved response of a request as an example at position
+func NewExample(requestID int64, position int, resp PostmanResponse) *Example {
+	return &Example{
+		RequestID:       requestID,
+		Position:        position,
+		Name:            resp.Name,
+		Status:          resp.Status,
+		Code:            resp.Code,
+		Header:          resp.Header,
+		Body:            resp.Body,
+		OriginalRequest: resp.OriginalReq,
+		Cookie:          resp.Cookie,
+		PreviewLanguage: resp.PreviewType,
+		PostmanID:       resp.PostmanID,
+		BodyRef:         resp.BodyRef,
+		BodySize:        resp.BodySize,
+		BodyTruncated:   resp.BodyTruncated,
+	}
+}
+
+// Response returns the example as a saved response in Postman form
+func (e *Example) Response() PostmanResponse {
+	return PostmanResponse{
+		Name:          e.Name,
+		OriginalReq:   e.OriginalRequest,
+		Status:        e.Status,
+		Code:          e.Code,
+		Header:        e.Header,
+		Body:          e.Body,
+		Cookie:        e.Cookie,
+		PreviewType:   e.PreviewLanguage,
+		PostmanID:     e.PostmanID,
+		BodyRef:       e.BodyRef,
+		BodySize:      e.BodySize,
+		BodyTruncated: e.BodyTruncated,
+	}
+}
+
+// CollectionItem is a request of a collection expressed as a Postman item
+type CollectionItem struct {
+	RequestID  int64       `json:"request_id,omitempty"`
+	FolderPath string      `json:"folder_path"`
+	Item       PostmanItem `json:"item"`
+}
+
 // OpenAPISpec represents an OpenAPI specification
 type OpenAPISpec struct {
 	bun.BaseModel `bun:"table:openapi_specs,alias:o"`
@@ -60,8 +176,1038 @@ type OpenAPISpec struct {
 	Description string    `bun:"description" json:"description"`
 	Version     string    `bun:"version,notnull" json:"version"`
 	Content     JSONMap   `bun:"content,type:jsonb" json:"content"`
+	Metadata    JSONMap   `bun:"metadata,type:jsonb" json:"metadata,omitempty"`
 	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
 	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	Links       Links     `bun:"-" json:"links,omitempty"`
+}
+
+// OpenAPISpecRevision is an earlier content of a spec, kept when an update
+// replaced it
+type OpenAPISpecRevision struct {
+	bun.BaseModel `bun:"table:openapi_spec_revisions,alias:osr"`
+
+	ID     int64 `bun:"id,pk,autoincrement" json:"id"`
+	SpecID int64 `bun:"spec_id,notnull" json:"spec_id"`
+	// Revision numbers the revisions of a spec from 1
+	Revision  int       `bun:"revision,notnull" json:"revision"`
+	Title     string    `bun:"title,notnull" json:"title"`
+	Version   string    `bun:"version,notnull" json:"version"`
+	Content   JSONMap   `bun:"content,type:jsonb" json:"content,omitempty"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// SpecDiff compares two contents of a spec by the paths, operations and
+// schemas they declare
+type SpecDiff struct {
+	SpecID int64 `json:"spec_id"`
+	// From and To are revision numbers; a To of zero is the current content
+	From        int         `json:"from"`
+	To          int         `json:"to"`
+	FromVersion string      `json:"from_version"`
+	ToVersion   string      `json:"to_version"`
+	Paths       DiffEntries `json:"paths"`
+	// Operations are named "METHOD /path"
+	Operations DiffEntries `json:"operations"`
+	Schemas    DiffEntries `json:"schemas"`
+}
+
+// SpecComparison classifies the changes from an earlier version of a spec,
+// or another spec, to the spec
+type SpecComparison struct {
+	SpecID int64 `json:"spec_id"`
+	// AgainstSpecID or AgainstRevision identifies the version compared against
+	AgainstSpecID   int64        `json:"against_spec_id,omitempty"`
+	AgainstRevision int          `json:"against_revision,omitempty"`
+	Breaking        bool         `json:"breaking"`
+	BreakingCount   int          `json:"breaking_count"`
+	Changes         []SpecChange `json:"changes"`
+}
+
+// SpecChange is one difference between two versions of a spec; breaking
+// changes can fail clients written against the earlier version
+type SpecChange struct {
+	Type     string `json:"type"`
+	Breaking bool   `json:"breaking"`
+	// Operation is the "METHOD /path" of the operation changed, if any
+	Operation string `json:"operation,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Spec change types
+const (
+	ChangePathRemoved            = "path_removed"
+	ChangePathAdded              = "path_added"
+	ChangeOperationRemoved       = "operation_removed"
+	ChangeOperationAdded         = "operation_added"
+	ChangeRequiredParameterAdded = "required_parameter_added"
+	ChangeParameterAdded         = "parameter_added"
+	ChangeParameterRequired      = "parameter_required"
+	ChangeParameterRemoved       = "parameter_removed"
+	ChangeRequestBodyRequired    = "request_body_required"
+	ChangeTypeChanged            = "type_changed"
+	ChangeEnumNarrowed           = "enum_narrowed"
+	ChangeEnumWidened            = "enum_widened"
+	ChangeRequiredFieldAdded     = "required_field_added"
+	ChangePropertyAdded          = "property_added"
+	ChangePropertyRemoved        = "property_removed"
+)
+
+// DiffEntries lists the names added, removed and changed between two contents
+type DiffEntries struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// Review statuses of a spec; a spec without review decisions is a draft
+const (
+	ReviewDraft     = "draft"
+	ReviewInReview  = "in_review"
+	ReviewApproved  = "approved"
+	ReviewPublished = "published"
+)
+
+// Review decisions; DecisionContentChanged is recorded by the server when an
+// update changes the content of a spec under review
+const (
+	DecisionSubmit         = "submit"
+	DecisionApprove        = "approve"
+	DecisionRequestChanges = "request_changes"
+	DecisionPublish        = "publish"
+	DecisionContentChanged = "content_changed"
+)
+
+// SpecReviewDecision is one decision in the review of a spec; the decisions
+// of a spec are the audit trail of its reviews
+type SpecReviewDecision struct {
+	bun.BaseModel `bun:"table:openapi_spec_review_decisions,alias:osrd"`
+
+	ID         int64     `bun:"id,pk,autoincrement" json:"id"`
+	SpecID     int64     `bun:"spec_id,notnull" json:"spec_id"`
+	Decision   string    `bun:"decision,notnull" json:"decision"`
+	Reviewer   string    `bun:"reviewer,notnull" json:"reviewer,omitempty"`
+	Comment    string    `bun:"comment,notnull" json:"comment,omitempty"`
+	FromStatus string    `bun:"from_status,notnull" json:"from_status"`
+	ToStatus   string    `bun:"to_status,notnull" json:"to_status"`
+	CreatedAt  time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// SpecReview is the review state of a spec with its decisions, oldest first
+type SpecReview struct {
+	SpecID int64  `json:"spec_id"`
+	Status string `json:"status"`
+	// RequiredApprovals is how many reviewers must approve a submitted spec;
+	// gateway exports only wait for approval when it is set
+	RequiredApprovals int `json:"required_approvals"`
+	// Approvers are the reviewers who approved since the spec was last submitted
+	Approvers []string              `json:"approvers"`
+	Decisions []*SpecReviewDecision `json:"decisions"`
+}
+
+// SpecPromotion copies the content of a spec to an environment-of-record
+// label such as staging; the latest promotion to a label is the content of
+// record there
+type SpecPromotion struct {
+	bun.BaseModel `bun:"table:openapi_spec_promotions,alias:osp"`
+
+	ID     int64  `bun:"id,pk,autoincrement" json:"id"`
+	SpecID int64  `bun:"spec_id,notnull" json:"spec_id"`
+	Label  string `bun:"label,notnull" json:"label"`
+	// FromLabel is the label the content was copied from, empty when it was
+	// copied from the spec itself
+	FromLabel  string    `bun:"from_label,nullzero" json:"from_label,omitempty"`
+	Title      string    `bun:"title,notnull" json:"title"`
+	Version    string    `bun:"version,notnull" json:"version"`
+	Content    JSONMap   `bun:"content,type:jsonb" json:"content,omitempty"`
+	PromotedBy string    `bun:"promoted_by,notnull" json:"promoted_by"`
+	CreatedAt  time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	// Jobs are the downstream exports the promotion queued
+	Jobs []*Job `bun:"-" json:"jobs,omitempty"`
+}
+
+// Downstream exports run when a spec is promoted, written as kind:argument
+// such as gateway:kong or docs:html
+const (
+	PromotionExportGateway = "gateway"
+	PromotionExportDocs    = "docs"
+)
+
+// Links maps relation names to the API paths of related operations
+type Links map[string]string
+
+// SpecSource is a remote OpenAPI document polled for changes. Auth holds the
+// upstream credentials with their secret values encrypted and never leaves
+// the service; responses describe it through AuthStatus.
+type SpecSource struct {
+	bun.BaseModel `bun:"table:spec_sources,alias:ss"`
+
+	ID                  int64             `bun:"id,pk,autoincrement" json:"id"`
+	Name                string            `bun:"name,notnull" json:"name"`
+	URL                 string            `bun:"url,notnull" json:"url"`
+	PollIntervalSeconds int               `bun:"poll_interval_seconds,notnull" json:"poll_interval_seconds"`
+	Auth                JSONMap           `bun:"auth,type:jsonb" json:"-"`
+	AuthStatus          *CredentialStatus `bun:"-" json:"auth,omitempty"`
+	Enabled             bool              `bun:"enabled,notnull" json:"enabled"`
+	LastHash            string            `bun:"last_hash" json:"last_hash,omitempty"`
+	LastSpecID          int64             `bun:"last_spec_id,nullzero" json:"last_spec_id,omitempty"`
+	LastCheckedAt       *time.Time        `bun:"last_checked_at" json:"last_checked_at,omitempty"`
+	LastError           string            `bun:"last_error" json:"last_error,omitempty"`
+	CreatedAt           time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt           time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// CredentialStatus describes stored credentials without revealing them
+type CredentialStatus struct {
+	Type       string `json:"type"`
+	Configured bool   `json:"configured"`
+}
+
+// SpecSourceInput is a spec source as sent by clients, carrying its upstream
+// credentials in plaintext. On update, omitting auth keeps the stored
+// credentials and an empty auth object removes them.
+type SpecSourceInput struct {
+	SpecSource
+	Auth JSONMap `json:"auth"`
+}
+
+// ErrCredentialsDisabled is returned when spec source or hook credentials are stored without an encryption key
+var ErrCredentialsDisabled = NewError(ErrCodeValidation, "stored credentials require SECRETS_KEY to be configured")
+
+// ErrAttachmentTooLarge is returned when a file exceeds the configured attachment size limit
+var ErrAttachmentTooLarge = NewError(ErrCodeTooLarge, "attachment too large")
+
+// AttachmentRefPrefix marks a Postman file src that points at a stored attachment
+const AttachmentRefPrefix = "attachment:"
+
+// Attachment is an uploaded file referenced by form-data and file request bodies.
+// Content is stored once per SHA-256 hash.
+type Attachment struct {
+	bun.BaseModel `bun:"table:attachments,alias:a"`
+
+	ID          int64     `bun:"id,pk,autoincrement" json:"id"`
+	Hash        string    `bun:"hash,notnull,unique" json:"hash"`
+	Filename    string    `bun:"filename,notnull" json:"filename"`
+	ContentType string    `bun:"content_type,notnull" json:"content_type"`
+	Size        int64     `bun:"size,notnull" json:"size"`
+	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+
+	// Ref is the src value that references the attachment from a request body
+	Ref string `bun:"-" json:"ref"`
+}
+
+// Environment variable types
+const (
+	VariableTypeDefault = "default"
+	VariableTypeSecret  = "secret"
+)
+
+// SecretMask replaces secret values in responses; sending it back on update
+// keeps the stored value
+const SecretMask = "********"
+
+// ErrSecretsDisabled is returned when a secret variable is stored without an encryption key
+var ErrSecretsDisabled = NewError(ErrCodeValidation, "secret variables require SECRETS_KEY to be configured")
+
+// Environment is a named set of variables that requests are resolved against
+type Environment struct {
+	bun.BaseModel `bun:"table:environments,alias:e"`
+
+	ID        int64                 `bun:"id,pk,autoincrement" json:"id"`
+	Name      string                `bun:"name,notnull" json:"name"`
+	Variables []EnvironmentVariable `bun:"variables,type:jsonb,notnull" json:"variables"`
+	PostmanID string                `bun:"postman_id" json:"_postman_id,omitempty"`
+	Metadata  JSONMap               `bun:"metadata,type:jsonb" json:"metadata,omitempty"`
+	// HostOverrides maps host names to the IP addresses the runner connects
+	// to for them, like a hosts file
+	HostOverrides map[string]string `bun:"host_overrides,type:jsonb" json:"host_overrides,omitempty"`
+	CreatedAt     time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// HeaderPreset is a named set of headers shared by requests, attached to
+// them or applied when they are run
+type HeaderPreset struct {
+	bun.BaseModel `bun:"table:header_presets,alias:hp"`
+
+	ID          int64             `bun:"id,pk,autoincrement" json:"id"`
+	Name        string            `bun:"name,notnull" json:"name"`
+	Description string            `bun:"description" json:"description,omitempty"`
+	Headers     map[string]string `bun:"headers,type:jsonb,notnull" json:"headers"`
+	CreatedAt   time.Time         `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt   time.Time         `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// GlobalVariable is a variable shared by every collection, overriding the
+// configured globals of the same key
+type GlobalVariable struct {
+	bun.BaseModel `bun:"table:global_variables,alias:gv"`
+
+	ID          int64     `bun:"id,pk,autoincrement" json:"-"`
+	Key         string    `bun:"key,notnull" json:"key"`
+	Value       string    `bun:"value,notnull" json:"value"`
+	Description string    `bun:"description" json:"description,omitempty"`
+	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt   time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Global variable change actions
+const (
+	GlobalVariableCreated = "created"
+	GlobalVariableUpdated = "updated"
+	GlobalVariableDeleted = "deleted"
+)
+
+// GlobalVariableChange records a change to a global variable; OldValue is
+// nil for creations and NewValue for deletions
+type GlobalVariableChange struct {
+	bun.BaseModel `bun:"table:global_variable_changes,alias:gvc"`
+
+	ID        int64     `bun:"id,pk,autoincrement" json:"id"`
+	Key       string    `bun:"key,notnull" json:"key"`
+	Action    string    `bun:"action,notnull" json:"action"`
+	OldValue  *string   `bun:"old_value" json:"old_value,omitempty"`
+	NewValue  *string   `bun:"new_value" json:"new_value,omitempty"`
+	ChangedAt time.Time `bun:"changed_at,notnull,default:current_timestamp" json:"changed_at"`
+}
+
+// EnvironmentVariable is a variable of an environment. Secret values are
+// encrypted at rest and masked in responses.
+type EnvironmentVariable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// PostmanEnvironment is a *.postman_environment.json document
+type PostmanEnvironment struct {
+	ID            string                    `json:"id,omitempty"`
+	Name          string                    `json:"name"`
+	Values        []PostmanEnvironmentValue `json:"values"`
+	Scope         string                    `json:"_postman_variable_scope,omitempty"`
+	ExportedAt    string                    `json:"_postman_exported_at,omitempty"`
+	ExportedUsing string                    `json:"_postman_exported_using,omitempty"`
+}
+
+// PostmanEnvironmentValue is a variable of a Postman environment
+type PostmanEnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   any    `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// IsSecret reports whether the variable holds a secret value
+func (v EnvironmentVariable) IsSecret() bool {
+	return v.Type == VariableTypeSecret
+}
+
+// SpecSourceRefresh reports the outcome of checking a spec source
+type SpecSourceRefresh struct {
+	SourceID int64  `json:"source_id"`
+	Changed  bool   `json:"changed"`
+	Hash     string `json:"hash"`
+	SpecID   int64  `json:"spec_id,omitempty"`
+}
+
+// Digest frequencies
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Digest delivery channels
+const (
+	DigestWebhook = "webhook"
+	DigestEmail   = "email"
+)
+
+// DigestSubscription sends a periodic digest of the changes to selected
+// collections and specs to a webhook or an email address
+type DigestSubscription struct {
+	bun.BaseModel `bun:"table:digest_subscriptions,alias:dsub"`
+
+	ID        int64  `bun:"id,pk,autoincrement" json:"id"`
+	Name      string `bun:"name,notnull" json:"name"`
+	Frequency string `bun:"frequency,notnull" json:"frequency"`
+	Channel   string `bun:"channel,notnull" json:"channel"`
+	// Target is the webhook URL or the email address digests are sent to
+	Target        string  `bun:"target,notnull" json:"target"`
+	CollectionIDs []int64 `bun:"collection_ids,array" json:"collection_ids"`
+	SpecIDs       []int64 `bun:"spec_ids,array" json:"spec_ids"`
+	Enabled       bool    `bun:"enabled,notnull" json:"enabled"`
+	// LastSentAt ends the period covered by the last digest, which the next
+	// one starts from
+	LastSentAt *time.Time `bun:"last_sent_at" json:"last_sent_at,omitempty"`
+	LastError  string     `bun:"last_error" json:"last_error,omitempty"`
+	CreatedAt  time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt  time.Time  `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Digest summarizes what changed in the collections and specs of a
+// subscription between Since and Until
+type Digest struct {
+	SubscriptionID int64              `json:"subscription_id"`
+	Name           string             `json:"name"`
+	Since          time.Time          `json:"since"`
+	Until          time.Time          `json:"until"`
+	Collections    []DigestCollection `json:"collections"`
+	Specs          []DigestSpec       `json:"specs"`
+}
+
+// Empty reports whether nothing changed in the period of a digest
+func (d *Digest) Empty() bool {
+	return len(d.Collections) == 0 && len(d.Specs) == 0
+}
+
+// DigestCollection summarizes the revisions of a collection in a digest period
+type DigestCollection struct {
+	CollectionID int64  `json:"collection_id"`
+	Name         string `json:"name"`
+	// Revisions counts the updates, deletions and rollbacks in the period
+	Revisions int  `json:"revisions"`
+	Deleted   bool `json:"deleted"`
+}
+
+// DigestSpec summarizes how a spec changed in a digest period
+type DigestSpec struct {
+	SpecID    int64  `json:"spec_id"`
+	Title     string `json:"title"`
+	Revisions int    `json:"revisions"`
+	// Diff compares the content at the start of the period to the current one
+	Diff          *SpecDiff `json:"diff"`
+	BreakingCount int       `json:"breaking_count"`
+}
+
+// CountMode selects how list endpoints compute their total row count
+type CountMode string
+
+// Count modes
+const (
+	CountAuto     CountMode = "auto"
+	CountExact    CountMode = "exact"
+	CountEstimate CountMode = "estimate"
+)
+
+// Total is the row count reported alongside a page of results
+type Total struct {
+	Count     int
+	Estimated bool
+}
+
+// ValidationResult reports the outcome of validating an uploaded document
+type ValidationResult struct {
+	Valid    bool              `json:"valid"`
+	Errors   []ValidationIssue `json:"errors"`
+	Warnings []ValidationIssue `json:"warnings"`
+	Summary  map[string]int    `json:"summary"`
+}
+
+// ValidationIssue is a problem found at a location within a document
+type ValidationIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// AddError records an issue that prevents import
+func (r *ValidationResult) AddError(path, message string) {
+	r.Errors = append(r.Errors, ValidationIssue{Path: path, Message: message})
+}
+
+// AddWarning records an issue that does not prevent import
+func (r *ValidationResult) AddWarning(path, message string) {
+	r.Warnings = append(r.Warnings, ValidationIssue{Path: path, Message: message})
+}
+
+// Import document types
+const (
+	DocumentTypeOpenAPI     = "openapi"
+	DocumentTypePostman     = "postman"
+	DocumentTypeEnvironment = "environment"
+)
+
+// ImportHookPayload is the body accepted by the CI import webhook
+type ImportHookPayload struct {
+	Type         string          `json:"type" binding:"required"`
+	URL          string          `json:"url,omitempty"`
+	Auth         JSONMap         `json:"auth,omitempty"`
+	Document     json.RawMessage `json:"document,omitempty"`
+	StripSecrets bool            `json:"strip_secrets,omitempty"`
+	// Provenance describes the document's origin, such as the commit it was built from
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// ImportHookResult reports what a webhook-triggered import created
+type ImportHookResult struct {
+	Type     string `json:"type"`
+	ID       int64  `json:"id"`
+	Existing bool   `json:"existing,omitempty"`
+}
+
+// Assertion types
+const (
+	AssertionStatusEquals  = "status_equals"
+	AssertionHeaderPresent = "header_present"
+	AssertionJSONPath      = "jsonpath"
+	AssertionLatencyUnder  = "latency_under"
+)
+
+// Assertion is a declarative check evaluated against a request's response
+type Assertion struct {
+	Type    string `json:"type"`
+	Equals  any    `json:"equals,omitempty"`
+	Header  string `json:"header,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Matches string `json:"matches,omitempty"`
+	MaxMs   int64  `json:"max_ms,omitempty"`
+}
+
+// RunSettings overrides how the runner sends a request; unset fields fall
+// back to the options of the run or execution, then to the server defaults
+type RunSettings struct {
+	// TimeoutMs bounds the request from sending it to reading its response
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Retry resends the request in runs when it fails
+	Retry *RetryPolicy `json:"retry,omitempty"`
+}
+
+// RetryPolicy resends a request that got no response, or one of the RetryOn
+// statuses, up to MaxAttempts sends in all. BackoffMs is waited before the
+// first retry and doubles for each further one.
+type RetryPolicy struct {
+	MaxAttempts int   `json:"max_attempts"`
+	BackoffMs   int   `json:"backoff_ms,omitempty"`
+	RetryOn     []int `json:"retry_on,omitempty"`
+}
+
+// DeprecationUpdate flags a request as deprecated, optionally with a sunset date
+type DeprecationUpdate struct {
+	Deprecated bool   `json:"deprecated"`
+	Sunset     string `json:"sunset,omitempty"`
+}
+
+// Deprecation sources
+const (
+	DeprecationSourceSpec    = "spec"
+	DeprecationSourceRequest = "request"
+)
+
+// DeprecationFilter narrows the deprecation report
+type DeprecationFilter struct {
+	Source       string
+	CollectionID int64
+	SpecID       int64
+	// SunsetBefore keeps entries whose sunset date falls before it
+	SunsetBefore *time.Time
+}
+
+// DeprecatedEndpoint is a deprecated spec operation or request
+type DeprecatedEndpoint struct {
+	Source       string     `json:"source"`
+	Method       string     `json:"method"`
+	Path         string     `json:"path"`
+	Name         string     `json:"name,omitempty"`
+	SpecID       int64      `json:"spec_id,omitempty"`
+	SpecTitle    string     `json:"spec_title,omitempty"`
+	OperationID  string     `json:"operation_id,omitempty"`
+	CollectionID int64      `json:"collection_id,omitempty"`
+	RequestID    int64      `json:"request_id,omitempty"`
+	Sunset       *time.Time `json:"sunset,omitempty"`
+}
+
+// Inventory source types
+const (
+	InventorySourceSpec    = "spec"
+	InventorySourceRequest = "request"
+)
+
+// InventoryFilter narrows the API inventory
+type InventoryFilter struct {
+	Host   string
+	Method string
+	OwnershipFilter
+}
+
+// OwnershipMetadataKey is the metadata key holding the ownership of a collection or spec
+const OwnershipMetadataKey = "ownership"
+
+// Ownership describes who owns an API and where it is in its lifecycle
+type Ownership struct {
+	Team      string `json:"team,omitempty"`
+	Tier      string `json:"tier,omitempty"`
+	Lifecycle string `json:"lifecycle,omitempty"`
+	Contact   string `json:"contact,omitempty"`
+}
+
+// IsZero reports whether no ownership field is set
+func (o Ownership) IsZero() bool {
+	return o == Ownership{}
+}
+
+// OwnershipFilter selects records by ownership; empty fields match anything
+type OwnershipFilter struct {
+	Team      string
+	Tier      string
+	Lifecycle string
+}
+
+// IsZero reports whether the filter matches everything
+func (f OwnershipFilter) IsZero() bool {
+	return f == OwnershipFilter{}
+}
+
+// Matches reports whether o satisfies the filter
+func (f OwnershipFilter) Matches(o *Ownership) bool {
+	if f.IsZero() {
+		return true
+	}
+	if o == nil {
+		return false
+	}
+
+	return (f.Team == "" || f.Team == o.Team) &&
+		(f.Tier == "" || f.Tier == o.Tier) &&
+		(f.Lifecycle == "" || f.Lifecycle == o.Lifecycle)
+}
+
+// InventoryOwnership records the ownership of an inventory entry by its key
+type InventoryOwnership struct {
+	bun.BaseModel `bun:"table:inventory_ownership,alias:io"`
+
+	Key       string    `bun:"key,pk" json:"key"`
+	Ownership Ownership `bun:"ownership,type:jsonb,notnull" json:"ownership"`
+	UpdatedAt time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Catalog entry types
+const (
+	CatalogTypeCollection = "collection"
+	CatalogTypeSpec       = "spec"
+	CatalogTypeEndpoint   = "endpoint"
+)
+
+// CatalogFilter narrows the catalog listing
+type CatalogFilter struct {
+	Type string
+	OwnershipFilter
+}
+
+// CatalogEntry is an owned collection, spec or inventory endpoint
+type CatalogEntry struct {
+	Type      string    `json:"type"`
+	ID        int64     `json:"id,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	Name      string    `json:"name"`
+	Ownership Ownership `json:"ownership"`
+}
+
+// InventoryEntry is a distinct endpoint seen across stored specs and collections
+type InventoryEntry struct {
+	// Key identifies the entry by method, host and path shape
+	Key     string            `json:"key"`
+	Method  string            `json:"method"`
+	Host    string            `json:"host"`
+	Path    string            `json:"path"`
+	Sources []InventorySource `json:"sources"`
+	// Ownership is set once recorded for the entry's key
+	Ownership *Ownership `json:"ownership,omitempty"`
+}
+
+// InventorySource links an inventory entry to the spec operation or request it was seen in
+type InventorySource struct {
+	Type         string `json:"type"`
+	SpecID       int64  `json:"spec_id,omitempty"`
+	SpecTitle    string `json:"spec_title,omitempty"`
+	OperationID  string `json:"operation_id,omitempty"`
+	CollectionID int64  `json:"collection_id,omitempty"`
+	RequestID    int64  `json:"request_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// InventoryKey builds the key of an inventory entry
+func InventoryKey(method, host, pathShape string) string {
+	return method + " " + host + pathShape
+}
+
+// AssertionResult is the outcome of evaluating a single assertion
+type AssertionResult struct {
+	Assertion Assertion `json:"assertion"`
+	Passed    bool      `json:"passed"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// ManifestOptions selects the Kubernetes resource kind exported for a spec and
+// the namespace, backend service and parent gateway it refers to
+type ManifestOptions struct {
+	Kind      string
+	Namespace string
+	Service   string
+	Port      int
+	Gateway   string
+}
+
+// ImportOptions controls how an uploaded document is imported
+type ImportOptions struct {
+	// StripSecrets replaces credentials with placeholder variables before storage
+	StripSecrets bool
+	// Metadata is stored on the imported collection or spec
+	Metadata JSONMap
+	// Provenance, when set, is recorded in the metadata of the imported collection or spec
+	Provenance *Provenance
+	// Progress, when set, is called as the import advances
+	Progress func(ImportProgress)
+	// ArchivedAt, when set, imports the collection archived at that time
+	ArchivedAt *time.Time
+	// PreferredID is kept as the ID of the imported collection when it is
+	// free and was handed out by the database before, so that it cannot
+	// collide with a later collection
+	PreferredID int64
+	// Unarchive imports the collection of an archived bundle as a live
+	// collection rather than back into the archive
+	Unarchive bool
+}
+
+// Report passes p to the progress callback, if any
+func (o ImportOptions) Report(p ImportProgress) {
+	if o.Progress != nil {
+		o.Progress(p)
+	}
+}
+
+// Import progress stages
+const (
+	ImportStageParsed  = "parsed"
+	ImportStageWarning = "warning"
+	ImportStageRequest = "request"
+)
+
+// ImportProgress reports a step of a running import: the parsed document's
+// summary, a validation warning, or a created request out of Total
+type ImportProgress struct {
+	Stage   string           `json:"stage"`
+	Summary map[string]int   `json:"summary,omitempty"`
+	Warning *ValidationIssue `json:"warning,omitempty"`
+	Name    string           `json:"name,omitempty"`
+	Created int              `json:"created,omitempty"`
+	Total   int              `json:"total,omitempty"`
+}
+
+// ContentHashMetadataKey records the hash of an imported document in its metadata
+const ContentHashMetadataKey = "content_hash"
+
+// SourceSpecMetadataKey records the ID of the spec a generated collection was derived from
+const SourceSpecMetadataKey = "source_spec_id"
+
+// SpecOperationsMetadataKey records the operations, as "METHOD /path", a
+// collection was last generated or synced from, so that a sync can tell
+// operations removed from the spec from requests users added
+const SpecOperationsMetadataKey = "spec_operations"
+
+// SpecSyncReport describes how syncing a collection from its spec changed it:
+// requests added for new operations, requests of operations gone from the
+// spec deleted, and how many requests were regenerated in place
+type SpecSyncReport struct {
+	CollectionID int64           `json:"collection_id"`
+	SpecID       int64           `json:"spec_id"`
+	Added        []SpecOperation `json:"added"`
+	Removed      []SpecOperation `json:"removed"`
+	Kept         int             `json:"kept"`
+}
+
+// SpecOperation is an operation of a spec and the request generated for it
+type SpecOperation struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Name      string `json:"name"`
+	RequestID int64  `json:"request_id"`
+}
+
+// ErrSigningDisabled is returned when a signed export is requested without a signing key
+var ErrSigningDisabled = NewError(ErrCodeValidation, "signed exports require EXPORT_SIGNING_KEY to be configured")
+
+// Signed export kinds
+const (
+	ExportKindCollection       = "postman_collection"
+	ExportKindCollectionBundle = "postman_collection_bundle"
+	ExportKindOpenAPISpec      = "openapi_spec"
+)
+
+// Collection formats registered by default
+const (
+	FormatPostman = "postman"
+	FormatBundle  = "bundle"
+)
+
+// Format describes a file format collections are imported from or exported to
+type Format struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Extension is appended to the collection name to name exported files
+	Extension   string `json:"extension"`
+	ContentType string `json:"content_type"`
+	Import      bool   `json:"import"`
+	Export      bool   `json:"export"`
+	// ExportKind is the kind recorded in the manifest of signed exports
+	ExportKind string `json:"-"`
+}
+
+// ExportManifest describes a signed export; the signature covers its JSON
+// encoding, and through SHA256 the document
+type ExportManifest struct {
+	Kind        string    `json:"kind"`
+	ID          int64     `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SHA256      string    `json:"sha256"`
+	Size        int64     `json:"size"`
+	Exporter    string    `json:"exporter"`
+	Version     string    `json:"version"`
+	ExportedAt  time.Time `json:"exported_at"`
+	Algorithm   string    `json:"algorithm"`
+	KeyID       string    `json:"key_id"`
+}
+
+// SignedExport wraps an exported document, base64 encoded so that it is
+// carried byte for byte, with its manifest and signature
+type SignedExport struct {
+	Manifest  ExportManifest `json:"manifest"`
+	Document  string         `json:"document"`
+	Signature string         `json:"signature"`
+}
+
+// ExportVerification is the outcome of checking a signed export
+type ExportVerification struct {
+	Valid    bool            `json:"valid"`
+	Reason   string          `json:"reason,omitempty"`
+	Manifest *ExportManifest `json:"manifest,omitempty"`
+}
+
+// SigningKey describes the key signed exports are signed with; PublicKey is
+// only set for Ed25519, whose signatures anyone can verify
+type SigningKey struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+// ProvenanceMetadataKey is the metadata key holding the provenance of a collection or spec
+const ProvenanceMetadataKey = "provenance"
+
+// Provenance sources
+const (
+	ProvenanceUpload     = "upload"
+	ProvenanceWebhook    = "webhook"
+	ProvenanceSpecSource = "spec_source"
+	ProvenanceSeed       = "seed"
+	ProvenanceGenerated  = "generated"
+)
+
+// Provenance records where an imported collection or spec came from, so it
+// can be traced back to its source
+type Provenance struct {
+	Source             string `json:"source"`
+	Filename           string `json:"filename,omitempty"`
+	URL                string `json:"url,omitempty"`
+	GitCommit          string `json:"git_commit,omitempty"`
+	PostmanWorkspaceID string `json:"postman_workspace_id,omitempty"`
+	ImportedBy         string `json:"imported_by,omitempty"`
+	// SpecID is the spec a generated collection was derived from
+	SpecID int64 `json:"spec_id,omitempty"`
+	// OriginalHash is the SHA-256 of the document as received, before
+	// secrets were stripped or a bundle unpacked
+	OriginalHash string    `json:"original_hash,omitempty"`
+	ImportedAt   time.Time `json:"imported_at"`
+}
+
+// ImportResult identifies an imported collection or spec; Existing is set when
+// an identical document had already been imported and no new row was created
+type ImportResult struct {
+	ID       int64 `json:"id"`
+	Existing bool  `json:"existing,omitempty"`
+	Archived bool  `json:"archived,omitempty"`
+}
+
+// BundleArchival is carried by the bundle of an archived collection so that
+// the collection can be imported back into the archive, under its ID if free
+type BundleArchival struct {
+	CollectionID int64     `json:"collection_id"`
+	ArchivedAt   time.Time `json:"archived_at"`
+}
+
+// ItemsMigration reports the progress of moving collections whose folders
+// and requests are only stored in their legacy items blob onto rows. It is
+// recorded as the run goes, so an interrupted run resumes after AfterID.
+type ItemsMigration struct {
+	// AfterID is the last collection handled
+	AfterID int64 `json:"after_id"`
+	// Total is the number of legacy collections, handled or not
+	Total    int `json:"total"`
+	Migrated int `json:"migrated"`
+	Requests int `json:"requests"`
+	// Skipped are the collections left alone because they already have
+	// folder or request rows
+	Skipped []int64 `json:"skipped,omitempty"`
+}
+
+// ImportFile is one uploaded document of a bulk import
+type ImportFile struct {
+	Filename string
+	Data     []byte
+}
+
+// BulkImportResult is the outcome of importing one file of a bulk import:
+// the imported collection, or the error that stopped it
+type BulkImportResult struct {
+	Filename string `json:"filename"`
+	// Name and PostmanID identify the entry within a workspace dump
+	Name      string `json:"name,omitempty"`
+	PostmanID string `json:"postman_id,omitempty"`
+	ID        int64  `json:"id,omitempty"`
+	Existing  bool   `json:"existing,omitempty"`
+	// Skipped says why an entry of a workspace dump was left out
+	Skipped string    `json:"skipped,omitempty"`
+	Code    ErrorCode `json:"code,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Err     error     `json:"-"`
+}
+
+// BulkImportReport lists the outcome of every file of a bulk import
+type BulkImportReport struct {
+	Imported int                 `json:"imported"`
+	Failed   int                 `json:"failed"`
+	Results  []*BulkImportResult `json:"results"`
+}
+
+// WorkspaceImportReport maps the collections, environments and globals of
+// a Postman workspace dump to what the import created from them
+type WorkspaceImportReport struct {
+	Imported     int                 `json:"imported"`
+	Failed       int                 `json:"failed"`
+	Skipped      int                 `json:"skipped"`
+	Collections  []*BulkImportResult `json:"collections"`
+	Environments []*BulkImportResult `json:"environments"`
+	Globals      []*BulkImportResult `json:"globals"`
+}
+
+// Scan finding categories
+const (
+	FindingCategorySecret = "secret"
+	FindingCategoryPII    = "pii"
+)
+
+// SeedReport summarizes a fixture directory load
+type SeedReport struct {
+	Loaded  int `json:"loaded"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// Readiness statuses
+const (
+	ReadinessOK          = "ok"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+// Readiness reports whether the service can currently serve traffic
+type Readiness struct {
+	Status   string `json:"status"`
+	Database string `json:"database"`
+	Circuit  string `json:"circuit"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ScanReport lists likely secrets and PII found in a collection
+type ScanReport struct {
+	CollectionID    int64          `json:"collection_id"`
+	ScannedRequests int            `json:"scanned_requests"`
+	Findings        []ScanFinding  `json:"findings"`
+	Summary         map[string]int `json:"summary"`
+}
+
+// ScanFinding is a single match of a scan rule
+type ScanFinding struct {
+	RequestID   int64  `json:"request_id,omitempty"`
+	RequestName string `json:"request_name,omitempty"`
+	Location    string `json:"location"`
+	Rule        string `json:"rule"`
+	Category    string `json:"category"`
+	Match       string `json:"match"`
+}
+
+// Lint finding severities
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityInfo    = "info"
+)
+
+// Collection lint rules
+const (
+	LintRuleEmptyName          = "empty_name"
+	LintRuleDefaultName        = "default_name"
+	LintRuleNamingStyle        = "naming_style"
+	LintRuleDuplicateName      = "duplicate_name"
+	LintRuleMissingDescription = "missing_description"
+	LintRuleHardcodedURL       = "hardcoded_url"
+	LintRuleMissingTests       = "missing_tests"
+)
+
+// LintRules lists every collection lint rule
+var LintRules = []string{
+	LintRuleEmptyName,
+	LintRuleDefaultName,
+	LintRuleNamingStyle,
+	LintRuleDuplicateName,
+	LintRuleMissingDescription,
+	LintRuleHardcodedURL,
+	LintRuleMissingTests,
+}
+
+// Naming styles a collection's folder and request names can be checked against
+const (
+	NamingStyleTitle    = "title"
+	NamingStyleSentence = "sentence"
+	NamingStyleKebab    = "kebab"
+	NamingStyleSnake    = "snake"
+	NamingStyleCamel    = "camel"
+)
+
+// ValidNamingStyle reports whether style is a supported naming style
+func ValidNamingStyle(style string) bool {
+	switch style {
+	case NamingStyleTitle, NamingStyleSentence, NamingStyleKebab, NamingStyleSnake, NamingStyleCamel:
+		return true
+	}
+	return false
+}
+
+// LintOptions configures a collection lint pass
+type LintOptions struct {
+	// Rules limits the pass to these rules; empty runs every rule
+	Rules []string
+	// Naming, when set, is the style folder and request names must follow
+	Naming string
+}
+
+// LintReport lists convention issues found in a collection
+type LintReport struct {
+	CollectionID   int64          `json:"collection_id"`
+	LintedRequests int            `json:"linted_requests"`
+	Findings       []LintFinding  `json:"findings"`
+	Summary        map[string]int `json:"summary"`
+}
+
+// LintFinding is a single violation of a lint rule
+type LintFinding struct {
+	RequestID   int64  `json:"request_id,omitempty"`
+	RequestName string `json:"request_name,omitempty"`
+	Folder      string `json:"folder,omitempty"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
 }
 
 // JSONMap is a helper type for JSON columns
@@ -154,6 +1300,7 @@ type KeyValuePair struct {
 	Value       string `json:"value"`
 	Description any    `json:"description,omitempty"`
 	Type        string `json:"type,omitempty"`
+	Src         any    `json:"src,omitempty"`
 	Disabled    bool   `json:"disabled,omitempty"`
 	Name        string `json:"name,omitempty"`
 }
@@ -169,6 +1316,525 @@ type PostmanResponse struct {
 	Cookie      []json.RawMessage `json:"cookie,omitempty"`
 	PreviewType string            `json:"_postman_previewlanguage,omitempty"`
 	PostmanID   string            `json:"id,omitempty"`
+
+	// BodyRef is the hash of a body offloaded to blob storage; Body is empty while it is set
+	BodyRef string `json:"_body_ref,omitempty"`
+	// BodySize is the original size of an offloaded or truncated body
+	BodySize      int64 `json:"_body_size,omitempty"`
+	BodyTruncated bool  `json:"_body_truncated,omitempty"`
+}
+
+// ResponseBodyPolicy controls how saved response bodies are stored
+type ResponseBodyPolicy struct {
+	// OffloadBytes moves bodies larger than this to blob storage; zero keeps every body inline
+	OffloadBytes int64
+	// MaxBytes truncates bodies larger than this; zero keeps bodies whole
+	MaxBytes int64
+	// Retain keeps only the last Retain saved responses of a request; zero keeps all
+	Retain int
+}
+
+// FlatRequest is a request with its variables, inherited auth and body
+// resolved, for tools that do not understand Postman collections
+type FlatRequest struct {
+	RequestID  int64             `json:"request_id"`
+	Name       string            `json:"name"`
+	FolderPath string            `json:"folder_path,omitempty"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	// Unresolved lists variables without a value, left in place as {{name}}
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// VariableReference is a variable substituted into a request, with the
+// scope its value came from: global, collection or environment
+type VariableReference struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Scope string `json:"scope"`
+}
+
+// ResolvedRequest previews a request with its variables substituted, listing
+// the variables used and where each value came from
+type ResolvedRequest struct {
+	FlatRequest
+	Params    JSONMap             `json:"params,omitempty"`
+	Variables []VariableReference `json:"variables"`
+}
+
+// ExecuteOptions selects the environment and header presets a request is sent with
+type ExecuteOptions struct {
+	EnvironmentID int64
+	// HeaderPresets are applied on top of the request's own presets
+	HeaderPresets []int64
+	// TimeoutMs bounds the request unless it has a timeout of its own
+	TimeoutMs int
+}
+
+// ExecutionResult is the response to a request sent by the runner
+type ExecutionResult struct {
+	RequestID  int64               `json:"request_id"`
+	Method     string              `json:"method"`
+	URL        string              `json:"url"`
+	StatusCode int                 `json:"status_code"`
+	Status     string              `json:"status"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+	// BodyBase64 is set when Body holds a base64 encoding of binary content
+	BodyBase64 bool `json:"body_base64,omitempty"`
+	// Truncated is set when the body exceeded the runner's limit
+	Truncated  bool              `json:"truncated,omitempty"`
+	Size       int64             `json:"size"`
+	LatencyMs  int64             `json:"latency_ms"`
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+	Passed     bool              `json:"passed"`
+	Unresolved []string          `json:"unresolved,omitempty"`
+}
+
+// RunOptions controls a collection run
+type RunOptions struct {
+	EnvironmentID int64 `json:"environment_id,omitempty"`
+	// DelayMs is waited before every request but the first
+	DelayMs int `json:"delay_ms,omitempty"`
+	// StopOnFailure skips the remaining requests after the first failed one
+	StopOnFailure bool `json:"stop_on_failure,omitempty"`
+	// HeaderPresets are applied to every request on top of its own presets
+	HeaderPresets []int64 `json:"header_presets,omitempty"`
+	// TimeoutMs bounds each request without a timeout of its own
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Retry resends failed requests without a retry policy of their own
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// MaxRPSPerHost caps the requests per second sent to each host; a host
+	// answering with Retry-After is not sent another request before then
+	MaxRPSPerHost int `json:"max_rps_per_host,omitempty"`
+	// DisableCookies stops cookies set by responses from being sent with the
+	// later requests of the run
+	DisableCookies bool `json:"disable_cookies,omitempty"`
+	// HostOverrides maps host names to the IP addresses connected to for
+	// them, over those of the environment
+	HostOverrides map[string]string `json:"host_overrides,omitempty"`
+	// Snapshot diffs each response against the snapshot recorded for its
+	// request, failing the requests whose responses drifted
+	Snapshot *SnapshotOptions `json:"snapshot,omitempty"`
+}
+
+// SnapshotOptions control a snapshot run. Requests without a snapshot, or
+// all of them with Update, get their response recorded as their snapshot
+// when they pass. Ignore lists the volatile fields left out of the diff:
+// JSON paths such as $.items[*].id, or bare names such as updated_at
+// matching a field at any depth.
+type SnapshotOptions struct {
+	Update bool     `json:"update,omitempty"`
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// ResponseSnapshot is the response a request got in the run that recorded
+// it; snapshot runs diff later responses against it
+type ResponseSnapshot struct {
+	bun.BaseModel `bun:"table:response_snapshots,alias:rs"`
+
+	ID         int64  `bun:"id,pk,autoincrement" json:"id"`
+	RequestID  int64  `bun:"request_id,notnull" json:"request_id"`
+	RunID      int64  `bun:"run_id,nullzero" json:"run_id,omitempty"`
+	StatusCode int    `bun:"status_code,notnull" json:"status_code"`
+	Body       string `bun:"body,notnull" json:"body"`
+	// BodyBase64 is set when Body holds a base64 encoding of binary content
+	BodyBase64 bool      `bun:"body_base64,notnull" json:"body_base64,omitempty"`
+	CreatedAt  time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt  time.Time `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Outcomes of a request of a snapshot run
+const (
+	SnapshotRecorded = "recorded"
+	SnapshotMatched  = "matched"
+	SnapshotDrifted  = "drifted"
+)
+
+// CollectionRevision is a snapshot of a collection with its folders and
+// requests, taken just before the collection was changed or deleted
+type CollectionRevision struct {
+	bun.BaseModel `bun:"table:collection_revisions,alias:cr"`
+
+	ID           int64 `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID int64 `bun:"collection_id,notnull" json:"collection_id"`
+	// Revision numbers the revisions of a collection from 1
+	Revision     int                 `bun:"revision,notnull" json:"revision"`
+	Action       string              `bun:"action,notnull" json:"action"`
+	Name         string              `bun:"name,notnull" json:"name"`
+	RequestCount int                 `bun:"request_count,notnull" json:"request_count"`
+	Snapshot     *CollectionSnapshot `bun:"snapshot,type:jsonb" json:"snapshot,omitempty"`
+	CreatedAt    time.Time           `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	Links        Links               `bun:"-" json:"links,omitempty"`
+}
+
+// CollectionSnapshot is the content of a collection as a revision keeps it
+type CollectionSnapshot struct {
+	Collection *Collection `json:"collection"`
+	Folders    []*Folder   `json:"folders"`
+	Requests   []*Request  `json:"requests"`
+}
+
+// Collection revision actions, naming the change a revision was taken before
+const (
+	RevisionUpdated    = "updated"
+	RevisionDeleted    = "deleted"
+	RevisionRolledBack = "rolled_back"
+)
+
+// Run is a recorded execution of every request of a collection in folder
+// order; runs are queued as jobs and Error is set when one could not complete
+type Run struct {
+	bun.BaseModel `bun:"table:runs,alias:ru"`
+
+	ID            int64       `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID  int64       `bun:"collection_id,notnull" json:"collection_id"`
+	EnvironmentID int64       `bun:"environment_id,nullzero" json:"environment_id,omitempty"`
+	Status        string      `bun:"status,notnull" json:"status"`
+	DelayMs       int         `bun:"delay_ms,notnull" json:"delay_ms"`
+	StopOnFailure bool        `bun:"stop_on_failure,notnull" json:"stop_on_failure"`
+	Total         int         `bun:"total,notnull" json:"total"`
+	Passed        int         `bun:"passed,notnull" json:"passed"`
+	Failed        int         `bun:"failed,notnull" json:"failed"`
+	Skipped       int         `bun:"skipped,notnull" json:"skipped"`
+	Results       []RunResult `bun:"results,type:jsonb" json:"results"`
+	DurationMs    int64       `bun:"duration_ms,notnull" json:"duration_ms"`
+	StartedAt     time.Time   `bun:"started_at,notnull" json:"started_at"`
+	FinishedAt    *time.Time  `bun:"finished_at" json:"finished_at,omitempty"`
+	Error         string      `bun:"error" json:"error,omitempty"`
+	// Metrics are aggregated from the results when the run finishes
+	Metrics *RunMetrics `bun:"metrics,type:jsonb" json:"-"`
+	// Job is the job the run was queued as, when it was just queued
+	Job   *Job  `bun:"-" json:"job,omitempty"`
+	Links Links `bun:"-" json:"links,omitempty"`
+}
+
+// Run statuses
+const (
+	RunQueued  = "queued"
+	RunRunning = "running"
+	RunPassed  = "passed"
+	RunFailed  = "failed"
+	RunAborted = "aborted"
+)
+
+// RunResult is the outcome of one request of a run
+type RunResult struct {
+	RequestID  int64             `json:"request_id"`
+	Name       string            `json:"name"`
+	FolderPath string            `json:"folder_path,omitempty"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	StatusCode int               `json:"status_code,omitempty"`
+	LatencyMs  int64             `json:"latency_ms"`
+	SentAt     *time.Time        `json:"sent_at,omitempty"`
+	Passed     bool              `json:"passed"`
+	Skipped    bool              `json:"skipped,omitempty"`
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	// Attempts is how many times the request was sent; Retries are the
+	// outcomes of the sends before the last, in order
+	Attempts int          `json:"attempts,omitempty"`
+	Retries  []RunAttempt `json:"retries,omitempty"`
+	// Snapshot is the outcome of the request in a snapshot run; Drift
+	// describes how its response differs from the snapshot when it drifted
+	Snapshot string   `json:"snapshot,omitempty"`
+	Drift    []string `json:"drift,omitempty"`
+}
+
+// RunAttempt is the outcome of a send of a request that was retried
+type RunAttempt struct {
+	StatusCode int       `json:"status_code,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+	SentAt     time.Time `json:"sent_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// RunMetrics is the data of a run for charts: the latency of every send of
+// each request over the run, and a heatmap of the statuses each got
+type RunMetrics struct {
+	RunID      int64            `json:"run_id"`
+	DurationMs int64            `json:"duration_ms"`
+	Requests   []RequestMetrics `json:"requests"`
+}
+
+// RequestMetrics are the sends of a request in a run; StatusCodes counts
+// them by status code, with sends that got no response under 0
+type RequestMetrics struct {
+	RequestID   int64          `json:"request_id"`
+	Name        string         `json:"name"`
+	Latency     []LatencyPoint `json:"latency"`
+	StatusCodes map[int]int    `json:"status_codes"`
+}
+
+// LatencyPoint is a send OffsetMs into a run that took LatencyMs
+type LatencyPoint struct {
+	OffsetMs   int64 `json:"offset_ms"`
+	LatencyMs  int64 `json:"latency_ms"`
+	StatusCode int   `json:"status_code,omitempty"`
+}
+
+// LoadTestOptions control a load test: RPS requests a second are sent for
+// DurationMs, taking the requests of the test in turn
+type LoadTestOptions struct {
+	EnvironmentID int64 `json:"environment_id,omitempty"`
+	// HeaderPresets are applied to every request on top of its own presets
+	HeaderPresets []int64 `json:"header_presets,omitempty"`
+	// TimeoutMs bounds each request without a timeout of its own
+	TimeoutMs  int `json:"timeout_ms,omitempty"`
+	RPS        int `json:"rps"`
+	DurationMs int `json:"duration_ms"`
+	// HostOverrides maps host names to the IP addresses connected to for
+	// them, over those of the environment
+	HostOverrides map[string]string `json:"host_overrides,omitempty"`
+}
+
+// LoadTest is a sustained load sent to a collection, or to one of its
+// requests when RequestID is set; load tests are queued as jobs and their
+// Stats are updated as they run
+type LoadTest struct {
+	bun.BaseModel `bun:"table:load_tests,alias:lt"`
+
+	ID           int64          `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID int64          `bun:"collection_id,notnull" json:"collection_id"`
+	RequestID    int64          `bun:"request_id,nullzero" json:"request_id,omitempty"`
+	Status       string         `bun:"status,notnull" json:"status"`
+	RPS          int            `bun:"rps,notnull" json:"rps"`
+	DurationMs   int64          `bun:"duration_ms,notnull" json:"duration_ms"`
+	Stats        *LoadTestStats `bun:"stats,type:jsonb" json:"stats,omitempty"`
+	Error        string         `bun:"error" json:"error,omitempty"`
+	StartedAt    time.Time      `bun:"started_at,notnull" json:"started_at"`
+	FinishedAt   *time.Time     `bun:"finished_at" json:"finished_at,omitempty"`
+	// Job is the job the load test was queued as, when it was just queued
+	Job   *Job  `bun:"-" json:"job,omitempty"`
+	Links Links `bun:"-" json:"links,omitempty"`
+}
+
+// Load test statuses
+const (
+	LoadTestQueued    = "queued"
+	LoadTestRunning   = "running"
+	LoadTestCompleted = "completed"
+	LoadTestFailed    = "failed"
+	LoadTestAborted   = "aborted"
+)
+
+// LoadTestStats are the sends of a load test so far. A send is an error
+// when it gets no response or its assertions fail; Throughput is the sends
+// completed per second and the latencies are those of the responses.
+// Dropped counts the sends skipped while too many were in flight.
+type LoadTestStats struct {
+	ElapsedMs   int64              `json:"elapsed_ms"`
+	Sent        int                `json:"sent"`
+	Dropped     int                `json:"dropped"`
+	Completed   int                `json:"completed"`
+	Errors      int                `json:"errors"`
+	ErrorRate   float64            `json:"error_rate"`
+	Throughput  float64            `json:"throughput"`
+	Latency     LatencyPercentiles `json:"latency"`
+	StatusCodes map[int]int        `json:"status_codes"`
+}
+
+// LatencyPercentiles summarize the latencies of responses, in milliseconds
+type LatencyPercentiles struct {
+	P50  int64 `json:"p50"`
+	P95  int64 `json:"p95"`
+	P99  int64 `json:"p99"`
+	Max  int64 `json:"max"`
+	Mean int64 `json:"mean"`
+}
+
+// Security scanner target formats
+const (
+	SecurityTargetZAP  = "zap"
+	SecurityTargetBurp = "burp"
+)
+
+// SecurityFinding is a finding reported by an external security scanner,
+// linked to the request it was found on when one matches
+type SecurityFinding struct {
+	bun.BaseModel `bun:"table:security_findings,alias:sf"`
+
+	ID           int64     `bun:"id,pk,autoincrement" json:"id"`
+	CollectionID int64     `bun:"collection_id,notnull" json:"collection_id"`
+	RequestID    int64     `bun:"request_id,nullzero" json:"request_id,omitempty"`
+	Tool         string    `bun:"tool,notnull" json:"tool"`
+	Rule         string    `bun:"rule,notnull" json:"rule"`
+	Severity     string    `bun:"severity" json:"severity,omitempty"`
+	Method       string    `bun:"method" json:"method,omitempty"`
+	URL          string    `bun:"url,notnull" json:"url"`
+	Description  string    `bun:"description" json:"description,omitempty"`
+	Evidence     string    `bun:"evidence" json:"evidence,omitempty"`
+	CreatedAt    time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// SecurityFindingsPayload is a batch of findings posted back by a scanner,
+// either as a list of findings or as a native ZAP JSON report
+type SecurityFindingsPayload struct {
+	Tool     string            `json:"tool" binding:"required"`
+	Findings []SecurityFinding `json:"findings,omitempty"`
+	Report   json.RawMessage   `json:"report,omitempty"`
+}
+
+// SecurityIngestResult summarizes an ingested batch of findings
+type SecurityIngestResult struct {
+	Ingested int `json:"ingested"`
+	Linked   int `json:"linked"`
+}
+
+// OutboxEvent is an entity-change event stored with the change and relayed to
+// the configured event bus; PublishedAt is set once the broker accepted it
+type OutboxEvent struct {
+	bun.BaseModel `bun:"table:event_outbox,alias:eo"`
+
+	ID          int64      `bun:"id,pk,autoincrement" json:"id"`
+	Type        string     `bun:"type,notnull" json:"type"`
+	EntityType  string     `bun:"entity_type,notnull" json:"entity_type"`
+	EntityID    int64      `bun:"entity_id,notnull" json:"entity_id"`
+	Payload     JSONMap    `bun:"payload,type:jsonb" json:"payload,omitempty"`
+	OccurredAt  time.Time  `bun:"occurred_at,notnull,default:current_timestamp" json:"occurred_at"`
+	PublishedAt *time.Time `bun:"published_at" json:"published_at,omitempty"`
+	Attempts    int        `bun:"attempts,notnull" json:"attempts"`
+	LastError   string     `bun:"last_error" json:"last_error,omitempty"`
+}
+
+// Job is a unit of background work in the persistent queue. Failed jobs are
+// retried with backoff until MaxAttempts, then left dead for inspection.
+type Job struct {
+	bun.BaseModel `bun:"table:jobs,alias:j"`
+
+	ID          int64      `bun:"id,pk,autoincrement" json:"id"`
+	Type        string     `bun:"type,notnull" json:"type"`
+	Payload     JSONMap    `bun:"payload,type:jsonb" json:"payload,omitempty"`
+	Status      string     `bun:"status,notnull" json:"status"`
+	Attempts    int        `bun:"attempts,notnull" json:"attempts"`
+	MaxAttempts int        `bun:"max_attempts,notnull" json:"max_attempts"`
+	RunAt       time.Time  `bun:"run_at,notnull" json:"run_at"`
+	LastError   string     `bun:"last_error" json:"last_error,omitempty"`
+	Result      JSONMap    `bun:"result,type:jsonb" json:"result,omitempty"`
+	Progress    JSONMap    `bun:"progress,type:jsonb" json:"progress,omitempty"`
+	StartedAt   *time.Time `bun:"started_at" json:"started_at,omitempty"`
+	FinishedAt  *time.Time `bun:"finished_at" json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt   time.Time  `bun:"updated_at,notnull,default:current_timestamp" json:"updated_at"`
+	Links       Links      `bun:"-" json:"links,omitempty"`
+}
+
+// Job statuses
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead"
+	JobCancelled = "cancelled"
+)
+
+// Job types
+const (
+	JobImportHook       = "import.hook"
+	JobRetentionEnforce = "retention.enforce"
+	JobMigrateItems     = "collections.migrate_items"
+	JobPromotionExport  = "specs.promotion_export"
+	JobRunCollection    = "collections.run"
+	JobLoadTest         = "collections.load_test"
+)
+
+// JobFilter narrows a job listing; empty fields match every job
+type JobFilter struct {
+	Status string
+	Type   string
+}
+
+// OutboundProxy routes collection runs and documents fetched from URLs
+// through a proxy; hosts matching NoProxy, in the NO_PROXY format, are
+// reached directly. It is set once for the process.
+type OutboundProxy struct {
+	URL     string
+	NoProxy []string
+}
+
+// SMTPServer is the mail server email digests are sent through; email
+// digests are unavailable while Addr is unset
+type SMTPServer struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// StorageReport breaks down the database footprint by table, owning team and
+// collection, to find the collections worth cleaning up
+type StorageReport struct {
+	TotalBytes  int64               `json:"total_bytes"`
+	Tables      []TableStorage      `json:"tables"`
+	Teams       []TeamStorage       `json:"teams"`
+	Collections []CollectionStorage `json:"collections"`
+}
+
+// TableStorage is the size of a table including its indexes and TOAST data;
+// Rows is the planner's estimate, or the count on SQLite
+type TableStorage struct {
+	Table      string `bun:"table_name" json:"table"`
+	Rows       int64  `bun:"rows" json:"rows"`
+	TotalBytes int64  `bun:"total_bytes" json:"total_bytes"`
+}
+
+// TeamStorage sums the collections owned by a team; collections without an
+// owner are grouped under an empty team
+type TeamStorage struct {
+	Team        string `bun:"team" json:"team"`
+	Collections int    `bun:"collections" json:"collections"`
+	Requests    int    `bun:"requests" json:"requests"`
+	Runs        int    `bun:"runs" json:"runs"`
+	TotalBytes  int64  `bun:"total_bytes" json:"total_bytes"`
+}
+
+// CollectionStorage is the stored size of a collection, its requests and its
+// runs, with the saved responses of the requests broken out; sizes are those
+// of the stored, possibly compressed, values
+type CollectionStorage struct {
+	CollectionID    int64  `bun:"collection_id" json:"collection_id"`
+	Name            string `bun:"name" json:"name"`
+	Team            string `bun:"team" json:"team,omitempty"`
+	Archived        bool   `bun:"archived" json:"archived"`
+	Requests        int    `bun:"requests" json:"requests"`
+	Runs            int    `bun:"runs" json:"runs"`
+	CollectionBytes int64  `bun:"collection_bytes" json:"collection_bytes"`
+	RequestBytes    int64  `bun:"request_bytes" json:"request_bytes"`
+	ResponseBytes   int64  `bun:"response_bytes" json:"response_bytes"`
+	RunBytes        int64  `bun:"run_bytes" json:"run_bytes"`
+	TotalBytes      int64  `bun:"total_bytes" json:"total_bytes"`
+}
+
+// RetentionPolicy controls which items are purged; a zero age keeps them forever
+type RetentionPolicy struct {
+	// ArchivedCollections purges collections archived for longer than this
+	ArchivedCollections time.Duration
+	// SupersededSpecs purges specs imported by a spec source longer ago than
+	// this once a newer import of the source replaced them
+	SupersededSpecs time.Duration
+}
+
+// Enabled reports whether the policy purges anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.ArchivedCollections > 0 || p.SupersededSpecs > 0
+}
+
+// RetentionReport lists the items a retention run purged, or would purge on a dry run
+type RetentionReport struct {
+	DryRun      bool            `json:"dry_run"`
+	Collections []RetentionItem `json:"collections"`
+	Specs       []RetentionItem `json:"specs"`
+}
+
+// RetentionItem is an item selected by a retention policy; Since is when it
+// was archived or imported
+type RetentionItem struct {
+	ID    int64     `json:"id"`
+	Name  string    `json:"name"`
+	Since time.Time `json:"since"`
 }
 
 // PostmanEvent represents event scripts in Postman
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AttachmentRepository handles database operations for attachments
+type AttachmentRepository struct {
+	db *bun.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *bun.DB) interfaces.AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create records an attachment; content already recorded under the same
+// hash is left as is and the existing row is loaded into attachment
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	attachment.CreatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(attachment).
+		On("CONFLICT (hash) DO NOTHING").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "attachment", "failed to create attachment")
+	}
+
+	existing, err := r.GetByHash(ctx, attachment.Hash)
+	if err != nil {
+		return err
+	}
+	*attachment = *existing
+
+	return nil
+}
+
+// GetByID retrieves an attachment by its ID
+func (r *AttachmentRepository) GetByID(ctx context.Context, id int64) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := r.db.NewSelect().
+		Model(attachment).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "attachment", "failed to get attachment by ID")
+	}
+
+	return attachment, nil
+}
+
+// GetByHash retrieves an attachment by the SHA-256 hash of its content
+func (r *AttachmentRepository) GetByHash(ctx context.Context, hash string) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := r.db.NewSelect().
+		Model(attachment).
+		Where("hash = ?", hash).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "attachment", "failed to get attachment by hash")
+	}
+
+	return attachment, nil
+}
+
+// Delete removes an attachment from the database
+func (r *AttachmentRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.Attachment)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "attachment", "failed to delete attachment")
+	}
+
+	return nil
+}
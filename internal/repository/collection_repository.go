@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
 	"time"
@@ -12,17 +12,23 @@ import (
 
 // CollectionRepository handles database operations for collections
 type CollectionRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 func NewCollectionRepository(db *bun.DB) interfaces.CollectionRepository {
 	return &CollectionRepository{db: db}
 }
 
+// WithTx returns a repository running its queries in tx
+func (r *CollectionRepository) WithTx(tx bun.Tx) interfaces.CollectionRepository {
+	return &CollectionRepository{db: tx}
+}
+
 // Create adds a new collection to the database
 func (r *CollectionRepository) Create(ctx context.Context, collection *models.Collection) error {
 	collection.CreatedAt = time.Now()
 	collection.UpdatedAt = time.Now()
+	collection.RequestCount = 0
 
 	_, err := r.db.NewInsert().
 		Model(collection).
@@ -30,7 +36,7 @@ func (r *CollectionRepository) Create(ctx context.Context, collection *models.Co
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to create collection: %w", err)
+		return dbError(err, "collection", "failed to create collection")
 	}
 
 	return nil
@@ -45,24 +51,39 @@ func (r *CollectionRepository) GetByID(ctx context.Context, id int64) (*models.C
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get collection by ID: %w", err)
+		return nil, dbError(err, "collection", "failed to get collection by ID")
 	}
 
 	return collection, nil
 }
 
-// List returns all collections with pagination
-func (r *CollectionRepository) List(ctx context.Context, offset, limit int) ([]*models.Collection, error) {
+// GetByIDs retrieves the collections with the given IDs in one query; IDs
+// without a collection are skipped
+func (r *CollectionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.Collection, error) {
 	var collections []*models.Collection
 	err := r.db.NewSelect().
 		Model(&collections).
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to get collections by ID")
+	}
+
+	return collections, nil
+}
+
+// List returns collections with pagination, leaving out archived ones unless includeArchived is set
+func (r *CollectionRepository) List(ctx context.Context, offset, limit int, includeArchived bool) ([]*models.Collection, error) {
+	var collections []*models.Collection
+	err := whereArchived(r.db.NewSelect().Model(&collections), includeArchived).
 		OrderExpr("created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list collections: %w", err)
+		return nil, dbError(err, "collection", "failed to list collections")
 	}
 
 	return collections, nil
@@ -72,13 +93,15 @@ func (r *CollectionRepository) List(ctx context.Context, offset, limit int) ([]*
 func (r *CollectionRepository) Update(ctx context.Context, collection *models.Collection) error {
 	collection.UpdatedAt = time.Now()
 
+	// The request count is only ever changed along with the requests
 	_, err := r.db.NewUpdate().
 		Model(collection).
+		ExcludeColumn("request_count").
 		WherePK().
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to update collection: %w", err)
+		return dbError(err, "collection", "failed to update collection")
 	}
 
 	return nil
@@ -92,12 +115,60 @@ func (r *CollectionRepository) Delete(ctx context.Context, id int64) error {
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete collection: %w", err)
+		return dbError(err, "collection", "failed to delete collection")
 	}
 
 	return nil
 }
 
+// SetArchived archives or unarchives the given collections and returns how many
+// changed state; collections already in the requested state keep their archived_at
+func (r *CollectionRepository) SetArchived(ctx context.Context, ids []int64, archived bool) (int, error) {
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	result, err := r.db.NewUpdate().
+		Model((*models.Collection)(nil)).
+		Set("archived = ?", archived).
+		Set("archived_at = ?", archivedAt).
+		Set("updated_at = ?", time.Now()).
+		Where("id IN (?)", bun.In(ids)).
+		Where("archived <> ?", archived).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "collection", "failed to archive collections")
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return 0, dbError(err, "collection", "failed to archive collections")
+	}
+
+	return int(changed), nil
+}
+
+// ListArchivedBefore returns the collections archived before the given time, oldest first
+func (r *CollectionRepository) ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Collection, error) {
+	var collections []*models.Collection
+	err := r.db.NewSelect().
+		Model(&collections).
+		Where("archived").
+		Where("archived_at < ?", before).
+		OrderExpr("archived_at ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to list archived collections")
+	}
+
+	return collections, nil
+}
+
 // GetWithRequests retrieves a collection with all its requests
 func (r *CollectionRepository) GetWithRequests(ctx context.Context, id int64) (*models.Collection, error) {
 	collection := &models.Collection{}
@@ -108,21 +179,190 @@ func (r *CollectionRepository) GetWithRequests(ctx context.Context, id int64) (*
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get collection with requests: %w", err)
+		return nil, dbError(err, "collection", "failed to get collection with requests")
+	}
+
+	if err := loadExamples(ctx, r.db, collection.Requests...); err != nil {
+		return nil, err
 	}
 
 	return collection, nil
 }
 
-// Count returns the total number of collections
-func (r *CollectionRepository) Count(ctx context.Context) (int, error) {
+// EstimateCount returns the planner's estimate of the number of collections
+func (r *CollectionRepository) EstimateCount(ctx context.Context) (int, error) {
+	return estimateTableRows(ctx, r.db, "collections")
+}
+
+// Count returns the number of collections, leaving out archived ones unless includeArchived is set
+func (r *CollectionRepository) Count(ctx context.Context, includeArchived bool) (int, error) {
+	count, err := whereArchived(r.db.NewSelect().Model((*models.Collection)(nil)), includeArchived).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "collection", "failed to count collections")
+	}
+
+	return count, nil
+}
+
+// ExistsByMetadata reports whether any collection has metadata[key] equal to value
+func (r *CollectionRepository) ExistsByMetadata(ctx context.Context, key, value string) (bool, error) {
+	exists, err := r.db.NewSelect().
+		Model((*models.Collection)(nil)).
+		Where("metadata ->> ? = ?", key, value).
+		Exists(ctx)
+
+	if err != nil {
+		return false, dbError(err, "collection", "failed to look up collection by metadata")
+	}
+
+	return exists, nil
+}
+
+// IDAvailable reports whether a collection can be created with id: no
+// collection has it and the ID sequence has already handed it out, so that
+// the sequence never yields it again
+func (r *CollectionRepository) IDAvailable(ctx context.Context, id int64) (bool, error) {
+	lastValue := "pg_sequence_last_value(pg_get_serial_sequence('collections', 'id')::regclass)"
+	if isSQLite(r.db) {
+		lastValue = "(SELECT seq FROM sqlite_sequence WHERE name = 'collections')"
+	}
+
+	var available bool
+	err := r.db.NewRaw(
+		"SELECT ? <= COALESCE("+lastValue+", 0) "+
+			"AND NOT EXISTS (SELECT 1 FROM collections WHERE id = ?)",
+		id, id,
+	).Scan(ctx, &available)
+
+	if err != nil {
+		return false, dbError(err, "collection", "failed to check collection ID")
+	}
+
+	return available, nil
+}
+
+// legacyItems matches collections whose folders and requests are stored in
+// the items blob, as an array of Postman items
+func legacyItems(q *bun.SelectQuery) *bun.SelectQuery {
+	if isSQLite(q.DB()) {
+		return q.Where("json_type(items) = 'array' AND json_array_length(items) > 0")
+	}
+	return q.Where("jsonb_typeof(items) = 'array' AND jsonb_array_length(items) > 0")
+}
+
+// ListWithLegacyItems returns the IDs of collections with legacy items after afterID, in order
+func (r *CollectionRepository) ListWithLegacyItems(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.NewSelect().
+		Model((*models.Collection)(nil)).
+		Column("id").
+		Where("id > ?", afterID).
+		Apply(legacyItems).
+		OrderExpr("id ASC").
+		Limit(limit).
+		Scan(ctx, &ids)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to list collections with legacy items")
+	}
+
+	return ids, nil
+}
+
+// CountWithLegacyItems returns the number of collections with legacy items after afterID
+func (r *CollectionRepository) CountWithLegacyItems(ctx context.Context, afterID int64) (int, error) {
 	count, err := r.db.NewSelect().
 		Model((*models.Collection)(nil)).
+		Where("id > ?", afterID).
+		Apply(legacyItems).
 		Count(ctx)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to count collections: %w", err)
+		return 0, dbError(err, "collection", "failed to count collections with legacy items")
 	}
 
 	return count, nil
 }
+
+// LockLegacyItems locks a collection with legacy items until the end of the
+// transaction and returns its items; it is not found once they are cleared
+func (r *CollectionRepository) LockLegacyItems(ctx context.Context, id int64) ([]models.PostmanItem, error) {
+	var data []byte
+	err := r.db.NewSelect().
+		Model((*models.Collection)(nil)).
+		Column("items").
+		Where("id = ?", id).
+		Apply(legacyItems).
+		Apply(lockRows("UPDATE")).
+		Scan(ctx, &data)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to lock legacy items")
+	}
+
+	var items []models.PostmanItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, models.NewValidationError("invalid legacy items of collection %d: %v", id, err)
+	}
+
+	return items, nil
+}
+
+// ClearItems removes the items blob of a collection
+func (r *CollectionRepository) ClearItems(ctx context.Context, id int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.Collection)(nil)).
+		Set("items = NULL").
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "collection", "failed to clear collection items")
+	}
+
+	return nil
+}
+
+// FindByMetadata returns the oldest collection with metadata[key] equal to value
+func (r *CollectionRepository) FindByMetadata(ctx context.Context, key, value string) (*models.Collection, error) {
+	collection := &models.Collection{}
+	err := r.db.NewSelect().
+		Model(collection).
+		Where("metadata ->> ? = ?", key, value).
+		OrderExpr("id ASC").
+		Limit(1).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to find collection by metadata")
+	}
+
+	return collection, nil
+}
+
+// ListByOwnership returns collections whose recorded ownership matches filter
+func (r *CollectionRepository) ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.Collection, error) {
+	var collections []*models.Collection
+	err := whereOwnership(r.db.NewSelect().Model(&collections), filter).
+		OrderExpr("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to list collections by ownership")
+	}
+
+	return collections, nil
+}
+
+// whereArchived leaves archived collections out of q unless includeArchived is set
+func whereArchived(q *bun.SelectQuery, includeArchived bool) *bun.SelectQuery {
+	if includeArchived {
+		return q
+	}
+	return q.Where("archived = false")
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// isSQLite reports whether db is a SQLite database, whose queries differ
+// from PostgreSQL's where they lock rows or look inside JSON
+func isSQLite(db bun.IDB) bool {
+	return db.Dialect().Name() == dialect.SQLite
+}
+
+// lockRows locks the rows a query selects until the end of the transaction
+// with the given locking clause. SQLite has no row locks and needs none:
+// its transactions begin immediate, holding the only write lock there is.
+func lockRows(lock string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if isSQLite(q.DB()) {
+			return q
+		}
+		return q.For(lock)
+	}
+}
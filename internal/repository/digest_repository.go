@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DigestRepository handles database operations for digest subscriptions
+type DigestRepository struct {
+	db *bun.DB
+}
+
+// NewDigestRepository creates a new digest subscription repository
+func NewDigestRepository(db *bun.DB) interfaces.DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// Create adds a new digest subscription to the database
+func (r *DigestRepository) Create(ctx context.Context, subscription *models.DigestSubscription) error {
+	subscription.CreatedAt = time.Now()
+	subscription.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(subscription).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "digest subscription", "failed to create digest subscription")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a digest subscription by its ID
+func (r *DigestRepository) GetByID(ctx context.Context, id int64) (*models.DigestSubscription, error) {
+	subscription := &models.DigestSubscription{}
+	err := r.db.NewSelect().
+		Model(subscription).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "digest subscription", "failed to get digest subscription by ID")
+	}
+
+	return subscription, nil
+}
+
+// List returns all digest subscriptions with pagination
+func (r *DigestRepository) List(ctx context.Context, offset, limit int) ([]*models.DigestSubscription, error) {
+	var subscriptions []*models.DigestSubscription
+	err := r.db.NewSelect().
+		Model(&subscriptions).
+		OrderExpr("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "digest subscription", "failed to list digest subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+// ListDue returns enabled subscriptions whose period has elapsed since their
+// last digest, or since they were created
+func (r *DigestRepository) ListDue(ctx context.Context, now time.Time) ([]*models.DigestSubscription, error) {
+	due := "COALESCE(last_sent_at, created_at) + CASE frequency WHEN ? THEN INTERVAL '1 day' ELSE INTERVAL '7 days' END <= ?"
+	if isSQLite(r.db) {
+		due = "unixepoch(COALESCE(last_sent_at, created_at)) + CASE frequency WHEN ? THEN 86400 ELSE 604800 END <= unixepoch(?)"
+	}
+
+	var subscriptions []*models.DigestSubscription
+	err := r.db.NewSelect().
+		Model(&subscriptions).
+		Where("enabled = TRUE").
+		Where(due, models.DigestDaily, now).
+		OrderExpr("COALESCE(last_sent_at, created_at) ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "digest subscription", "failed to list due digest subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+// Update modifies an existing digest subscription
+func (r *DigestRepository) Update(ctx context.Context, subscription *models.DigestSubscription) error {
+	subscription.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(subscription).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "digest subscription", "failed to update digest subscription")
+	}
+
+	return nil
+}
+
+// Delete removes a digest subscription from the database
+func (r *DigestRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.DigestSubscription)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "digest subscription", "failed to delete digest subscription")
+	}
+
+	return nil
+}
+
+// Count returns the total number of digest subscriptions
+func (r *DigestRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.DigestSubscription)(nil)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "digest subscription", "failed to count digest subscriptions")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// EnvironmentRepository handles database operations for environments
+type EnvironmentRepository struct {
+	db *bun.DB
+}
+
+// NewEnvironmentRepository creates a new environment repository
+func NewEnvironmentRepository(db *bun.DB) interfaces.EnvironmentRepository {
+	return &EnvironmentRepository{db: db}
+}
+
+// Create adds a new environment to the database
+func (r *EnvironmentRepository) Create(ctx context.Context, env *models.Environment) error {
+	env.CreatedAt = time.Now()
+	env.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(env).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "environment", "failed to create environment")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an environment by its ID
+func (r *EnvironmentRepository) GetByID(ctx context.Context, id int64) (*models.Environment, error) {
+	env := &models.Environment{}
+	err := r.db.NewSelect().
+		Model(env).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "environment", "failed to get environment by ID")
+	}
+
+	return env, nil
+}
+
+// List returns all environments with pagination
+func (r *EnvironmentRepository) List(ctx context.Context, offset, limit int) ([]*models.Environment, error) {
+	var envs []*models.Environment
+	err := r.db.NewSelect().
+		Model(&envs).
+		OrderExpr("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "environment", "failed to list environments")
+	}
+
+	return envs, nil
+}
+
+// Update modifies an existing environment
+func (r *EnvironmentRepository) Update(ctx context.Context, env *models.Environment) error {
+	env.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(env).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "environment", "failed to update environment")
+	}
+
+	return nil
+}
+
+// Delete removes an environment from the database
+func (r *EnvironmentRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.Environment)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "environment", "failed to delete environment")
+	}
+
+	return nil
+}
+
+// Count returns the total number of environments
+func (r *EnvironmentRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.Environment)(nil)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "environment", "failed to count environments")
+	}
+
+	return count, nil
+}
+
+// ExistsByMetadata reports whether any environment has metadata[key] equal to value
+func (r *EnvironmentRepository) ExistsByMetadata(ctx context.Context, key, value string) (bool, error) {
+	exists, err := r.db.NewSelect().
+		Model((*models.Environment)(nil)).
+		Where("metadata ->> ? = ?", key, value).
+		Exists(ctx)
+
+	if err != nil {
+		return false, dbError(err, "environment", "failed to look up environment by metadata")
+	}
+
+	return exists, nil
+}
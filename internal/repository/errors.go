@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"postman-api/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// PostgreSQL error codes translated into domain errors, along with their
+// SQLite counterparts
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// dbError wraps a database error with message, translating missing rows into
+// a not-found error for resource and constraint violations into conflicts
+func dbError(err error, resource, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.NewNotFoundError(resource, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return models.NewConflictError(resource+" already exists", err)
+		case pgForeignKeyViolation:
+			return models.NewConflictError(resource+" references a missing or in-use record", err)
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return models.NewConflictError(resource+" already exists", err)
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return models.NewConflictError(resource+" references a missing or in-use record", err)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", message, err)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// estimateTableRows returns the planner's row estimate for a whole table from pg_class,
+// avoiding a full COUNT(*) scan. SQLite keeps no estimate, so its rows are counted.
+func estimateTableRows(ctx context.Context, db bun.IDB, table string) (int, error) {
+	if isSQLite(db) {
+		count, err := db.NewSelect().TableExpr("?", bun.Ident(table)).Count(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count rows of %s: %w", table, err)
+		}
+		return count, nil
+	}
+
+	var estimate float64
+	err := db.NewRaw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", table).
+		Scan(ctx, &estimate)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate rows of %s: %w", table, err)
+	}
+
+	// reltuples is -1 for tables that have never been vacuumed or analyzed
+	if estimate < 0 {
+		return 0, fmt.Errorf("no row estimate available for %s", table)
+	}
+
+	return int(estimate), nil
+}
+
+// estimateQueryRows returns the planner's row estimate for a filtered query via
+// EXPLAIN, or on SQLite the rows it returns
+func estimateQueryRows(ctx context.Context, db bun.IDB, query *bun.SelectQuery) (int, error) {
+	if isSQLite(db) {
+		count, err := query.Count(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count query rows: %w", err)
+		}
+		return count, nil
+	}
+
+	var plan []byte
+	err := db.NewRaw("EXPLAIN (FORMAT JSON) ?", query).Scan(ctx, &plan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to explain query: %w", err)
+	}
+
+	var explained []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+
+	if len(explained) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+
+	return int(explained[0].Plan.PlanRows), nil
+}
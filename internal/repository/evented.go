@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/events"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// emitter records entity-change events in the outbox within the transaction
+// of the write they describe, so an event is stored exactly when its change
+// commits. A failed insert is returned and rolls the write back.
+type emitter struct {
+	// db starts a transaction for writes made outside one; it is nil once
+	// the emitter is bound to a transaction
+	db     *bun.DB
+	outbox interfaces.OutboxRepository
+}
+
+func (e emitter) withTx(tx bun.Tx) emitter {
+	return emitter{outbox: e.outbox.WithTx(tx)}
+}
+
+func (e emitter) emit(ctx context.Context, eventType, entityType string, id int64, payload map[string]any) error {
+	err := e.outbox.Create(ctx, &models.OutboxEvent{
+		Type:       eventType,
+		EntityType: entityType,
+		EntityID:   id,
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// inTx runs fn with repo bound to a transaction, starting one unless repo
+// already runs in one
+func inTx[R any](ctx context.Context, e emitter, repo R, withTx func(tx bun.Tx) R, fn func(ctx context.Context, repo R) error) error {
+	if e.db == nil {
+		return fn(ctx, repo)
+	}
+
+	return e.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, withTx(tx))
+	})
+}
+
+// EventedCollectionRepository records an event for every change to a collection
+type EventedCollectionRepository struct {
+	interfaces.CollectionRepository
+	emitter
+}
+
+// NewEventedCollectionRepository wraps repo to record collection changes in
+// outbox; repo must run its queries against db
+func NewEventedCollectionRepository(repo interfaces.CollectionRepository, db *bun.DB, outbox interfaces.OutboxRepository) interfaces.CollectionRepository {
+	return &EventedCollectionRepository{CollectionRepository: repo, emitter: emitter{db: db, outbox: outbox}}
+}
+
+func (r *EventedCollectionRepository) WithTx(tx bun.Tx) interfaces.CollectionRepository {
+	return r.withTx(tx)
+}
+
+func (r *EventedCollectionRepository) withTx(tx bun.Tx) *EventedCollectionRepository {
+	return &EventedCollectionRepository{CollectionRepository: r.CollectionRepository.WithTx(tx), emitter: r.emitter.withTx(tx)}
+}
+
+func (r *EventedCollectionRepository) Create(ctx context.Context, collection *models.Collection) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedCollectionRepository) error {
+		if err := r.CollectionRepository.Create(ctx, collection); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.CollectionCreated, "collection", collection.ID, map[string]any{"name": collection.Name})
+	})
+}
+
+func (r *EventedCollectionRepository) Update(ctx context.Context, collection *models.Collection) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedCollectionRepository) error {
+		if err := r.CollectionRepository.Update(ctx, collection); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.CollectionUpdated, "collection", collection.ID, map[string]any{"name": collection.Name})
+	})
+}
+
+func (r *EventedCollectionRepository) Delete(ctx context.Context, id int64) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedCollectionRepository) error {
+		if err := r.CollectionRepository.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.CollectionDeleted, "collection", id, nil)
+	})
+}
+
+func (r *EventedCollectionRepository) SetArchived(ctx context.Context, ids []int64, archived bool) (int, error) {
+	eventType := events.CollectionArchived
+	if !archived {
+		eventType = events.CollectionUnarchived
+	}
+
+	var changed int
+	err := inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedCollectionRepository) error {
+		var err error
+		changed, err = r.CollectionRepository.SetArchived(ctx, ids, archived)
+		if err != nil || changed == 0 {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := r.emit(ctx, eventType, "collection", id, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// EventedRequestRepository records an event for every change to a single request
+type EventedRequestRepository struct {
+	interfaces.RequestRepository
+	emitter
+}
+
+// NewEventedRequestRepository wraps repo to record request changes in
+// outbox; repo must run its queries against db
+func NewEventedRequestRepository(repo interfaces.RequestRepository, db *bun.DB, outbox interfaces.OutboxRepository) interfaces.RequestRepository {
+	return &EventedRequestRepository{RequestRepository: repo, emitter: emitter{db: db, outbox: outbox}}
+}
+
+func (r *EventedRequestRepository) WithTx(tx bun.Tx) interfaces.RequestRepository {
+	return r.withTx(tx)
+}
+
+func (r *EventedRequestRepository) withTx(tx bun.Tx) *EventedRequestRepository {
+	return &EventedRequestRepository{RequestRepository: r.RequestRepository.WithTx(tx), emitter: r.emitter.withTx(tx)}
+}
+
+func (r *EventedRequestRepository) Create(ctx context.Context, request *models.Request) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedRequestRepository) error {
+		if err := r.RequestRepository.Create(ctx, request); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.RequestCreated, "request", request.ID, map[string]any{"collection_id": request.CollectionID})
+	})
+}
+
+func (r *EventedRequestRepository) Update(ctx context.Context, request *models.Request) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedRequestRepository) error {
+		if err := r.RequestRepository.Update(ctx, request); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.RequestUpdated, "request", request.ID, map[string]any{"collection_id": request.CollectionID})
+	})
+}
+
+func (r *EventedRequestRepository) Delete(ctx context.Context, id int64) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedRequestRepository) error {
+		if err := r.RequestRepository.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.RequestDeleted, "request", id, nil)
+	})
+}
+
+func (r *EventedRequestRepository) DeleteByFolderIDs(ctx context.Context, collectionID int64, folderIDs []int64) ([]int64, error) {
+	var deleted []int64
+	err := inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedRequestRepository) error {
+		var err error
+		if deleted, err = r.RequestRepository.DeleteByFolderIDs(ctx, collectionID, folderIDs); err != nil {
+			return err
+		}
+
+		for _, id := range deleted {
+			if err := r.emit(ctx, events.RequestDeleted, "request", id, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// EventedOpenAPIRepository records an event for every change to a spec
+type EventedOpenAPIRepository struct {
+	interfaces.OpenAPIRepository
+	emitter
+}
+
+// NewEventedOpenAPIRepository wraps repo to record spec changes in outbox;
+// repo must run its queries against db
+func NewEventedOpenAPIRepository(repo interfaces.OpenAPIRepository, db *bun.DB, outbox interfaces.OutboxRepository) interfaces.OpenAPIRepository {
+	return &EventedOpenAPIRepository{OpenAPIRepository: repo, emitter: emitter{db: db, outbox: outbox}}
+}
+
+func (r *EventedOpenAPIRepository) WithTx(tx bun.Tx) interfaces.OpenAPIRepository {
+	return r.withTx(tx)
+}
+
+func (r *EventedOpenAPIRepository) withTx(tx bun.Tx) *EventedOpenAPIRepository {
+	return &EventedOpenAPIRepository{OpenAPIRepository: r.OpenAPIRepository.WithTx(tx), emitter: r.emitter.withTx(tx)}
+}
+
+func (r *EventedOpenAPIRepository) Create(ctx context.Context, spec *models.OpenAPISpec) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedOpenAPIRepository) error {
+		if err := r.OpenAPIRepository.Create(ctx, spec); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.OpenAPISpecCreated, "openapi_spec", spec.ID, map[string]any{"title": spec.Title, "version": spec.Version})
+	})
+}
+
+func (r *EventedOpenAPIRepository) Update(ctx context.Context, spec *models.OpenAPISpec) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedOpenAPIRepository) error {
+		if err := r.OpenAPIRepository.Update(ctx, spec); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.OpenAPISpecUpdated, "openapi_spec", spec.ID, map[string]any{"title": spec.Title, "version": spec.Version})
+	})
+}
+
+func (r *EventedOpenAPIRepository) Delete(ctx context.Context, id int64) error {
+	return inTx(ctx, r.emitter, r, r.withTx, func(ctx context.Context, r *EventedOpenAPIRepository) error {
+		if err := r.OpenAPIRepository.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.emit(ctx, events.OpenAPISpecDeleted, "openapi_spec", id, nil)
+	})
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ExampleRepository handles database operations for the saved examples of requests
+type ExampleRepository struct {
+	db bun.IDB
+}
+
+// NewExampleRepository creates a new example repository
+func NewExampleRepository(db *bun.DB) interfaces.ExampleRepository {
+	return &ExampleRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *ExampleRepository) WithTx(tx bun.Tx) interfaces.ExampleRepository {
+	return &ExampleRepository{db: tx}
+}
+
+// Create adds a new example to the database
+func (r *ExampleRepository) Create(ctx context.Context, example *models.Example) error {
+	example.CreatedAt = time.Now()
+	example.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(example).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "example", "failed to create example")
+	}
+
+	return nil
+}
+
+// GetByID retrieves an example by its ID
+func (r *ExampleRepository) GetByID(ctx context.Context, id int64) (*models.Example, error) {
+	example := &models.Example{}
+	err := r.db.NewSelect().
+		Model(example).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "example", "failed to get example by ID")
+	}
+
+	return example, nil
+}
+
+// ListByRequestID returns the examples of a request in position order
+func (r *ExampleRepository) ListByRequestID(ctx context.Context, requestID int64) ([]*models.Example, error) {
+	var examples []*models.Example
+	err := r.db.NewSelect().
+		Model(&examples).
+		Where("request_id = ?", requestID).
+		OrderExpr("position ASC, id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "example", "failed to list examples")
+	}
+
+	return examples, nil
+}
+
+// Update modifies an existing example
+func (r *ExampleRepository) Update(ctx context.Context, example *models.Example) error {
+	example.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(example).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "example", "failed to update example")
+	}
+
+	return nil
+}
+
+// Delete removes an example from the database
+func (r *ExampleRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.Example)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "example", "failed to delete example")
+	}
+
+	return nil
+}
+
+// ReplaceForRequest swaps the examples of a request for responses, in one transaction
+func (r *ExampleRepository) ReplaceForRequest(ctx context.Context, requestID int64, responses []models.PostmanResponse) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.Example)(nil)).
+			Where("request_id = ?", requestID).
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "example", "failed to delete examples")
+		}
+
+		return insertExamples(ctx, tx, requestID, responses)
+	})
+}
+
+// insertExamples stores responses as the examples of a request, in order
+func insertExamples(ctx context.Context, db bun.IDB, requestID int64, responses []models.PostmanResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	examples := make([]*models.Example, len(responses))
+	for i, resp := range responses {
+		examples[i] = models.NewExample(requestID, i, resp)
+		examples[i].CreatedAt = now
+		examples[i].UpdatedAt = now
+	}
+
+	_, err := db.NewInsert().
+		Model(&examples).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "example", "failed to create examples")
+	}
+
+	return nil
+}
+
+// loadExamples fills in the saved responses of requests from their examples
+func loadExamples(ctx context.Context, db bun.IDB, requests ...*models.Request) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Request, len(requests))
+	ids := make([]int64, len(requests))
+	for i, request := range requests {
+		byID[request.ID] = request
+		ids[i] = request.ID
+	}
+
+	var examples []*models.Example
+	err := db.NewSelect().
+		Model(&examples).
+		Where("request_id IN (?)", bun.In(ids)).
+		OrderExpr("request_id ASC, position ASC, id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return dbError(err, "example", "failed to load examples")
+	}
+
+	for _, example := range examples {
+		request := byID[example.RequestID]
+		request.Responses = append(request.Responses, example.Response())
+	}
+
+	return nil
+}
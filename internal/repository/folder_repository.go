@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FolderRepository handles database operations for folders
+type FolderRepository struct {
+	db bun.IDB
+}
+
+// NewFolderRepository creates a new folder repository
+func NewFolderRepository(db *bun.DB) interfaces.FolderRepository {
+	return &FolderRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *FolderRepository) WithTx(tx bun.Tx) interfaces.FolderRepository {
+	return &FolderRepository{db: tx}
+}
+
+// Create adds a new folder to the database
+func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) error {
+	folder.CreatedAt = time.Now()
+	folder.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(folder).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "folder", "failed to create folder")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a folder by its ID
+func (r *FolderRepository) GetByID(ctx context.Context, id int64) (*models.Folder, error) {
+	folder := &models.Folder{}
+	err := r.db.NewSelect().
+		Model(folder).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "folder", "failed to get folder by ID")
+	}
+
+	return folder, nil
+}
+
+// ListByCollectionID returns every folder of a collection in position order
+func (r *FolderRepository) ListByCollectionID(ctx context.Context, collectionID int64) ([]*models.Folder, error) {
+	var folders []*models.Folder
+	err := r.db.NewSelect().
+		Model(&folders).
+		Where("collection_id = ?", collectionID).
+		OrderExpr("position ASC, id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "folder", "failed to list folders")
+	}
+
+	return folders, nil
+}
+
+// Update modifies an existing folder
+func (r *FolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	folder.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(folder).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "folder", "failed to update folder")
+	}
+
+	return nil
+}
+
+// Delete removes a folder; its subfolders and their requests go with it
+func (r *FolderRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.Folder)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "folder", "failed to delete folder")
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// GlobalVariableRepository handles database operations for global variables
+// and their history
+type GlobalVariableRepository struct {
+	db *bun.DB
+}
+
+// NewGlobalVariableRepository creates a new global variable repository
+func NewGlobalVariableRepository(db *bun.DB) interfaces.GlobalVariableRepository {
+	return &GlobalVariableRepository{db: db}
+}
+
+// GetByKey retrieves a global variable by its key
+func (r *GlobalVariableRepository) GetByKey(ctx context.Context, key string) (*models.GlobalVariable, error) {
+	variable := &models.GlobalVariable{}
+	err := r.db.NewSelect().
+		Model(variable).
+		Where("key = ?", key).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "global variable", "failed to get global variable")
+	}
+
+	return variable, nil
+}
+
+// List returns global variables with pagination, by key
+func (r *GlobalVariableRepository) List(ctx context.Context, offset, limit int) ([]*models.GlobalVariable, error) {
+	var variables []*models.GlobalVariable
+	err := r.db.NewSelect().
+		Model(&variables).
+		OrderExpr("key ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "global variable", "failed to list global variables")
+	}
+
+	return variables, nil
+}
+
+// ListAll returns every global variable
+func (r *GlobalVariableRepository) ListAll(ctx context.Context) ([]*models.GlobalVariable, error) {
+	var variables []*models.GlobalVariable
+	err := r.db.NewSelect().
+		Model(&variables).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "global variable", "failed to list global variables")
+	}
+
+	return variables, nil
+}
+
+// Count returns the total number of global variables
+func (r *GlobalVariableRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.GlobalVariable)(nil)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "global variable", "failed to count global variables")
+	}
+
+	return count, nil
+}
+
+// Set creates or replaces a global variable and records the change; it
+// returns nil without recording anything when neither value nor description changed
+func (r *GlobalVariableRepository) Set(ctx context.Context, variable *models.GlobalVariable) (*models.GlobalVariableChange, error) {
+	var change *models.GlobalVariableChange
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		existing := &models.GlobalVariable{}
+		err := tx.NewSelect().
+			Model(existing).
+			Where("key = ?", variable.Key).
+			Apply(lockRows("UPDATE")).
+			Scan(ctx)
+
+		now := time.Now()
+		switch {
+		case err == nil:
+			if existing.Value == variable.Value && existing.Description == variable.Description {
+				*variable = *existing
+				return nil
+			}
+
+			variable.ID = existing.ID
+			variable.CreatedAt = existing.CreatedAt
+			variable.UpdatedAt = now
+			if _, err := tx.NewUpdate().Model(variable).WherePK().Exec(ctx); err != nil {
+				return dbError(err, "global variable", "failed to update global variable")
+			}
+			change = &models.GlobalVariableChange{Action: models.GlobalVariableUpdated, OldValue: &existing.Value}
+		case errors.Is(err, sql.ErrNoRows):
+			variable.CreatedAt = now
+			variable.UpdatedAt = now
+			if _, err := tx.NewInsert().Model(variable).Returning("id").Exec(ctx); err != nil {
+				return dbError(err, "global variable", "failed to create global variable")
+			}
+			change = &models.GlobalVariableChange{Action: models.GlobalVariableCreated}
+		default:
+			return dbError(err, "global variable", "failed to get global variable")
+		}
+
+		change.Key = variable.Key
+		change.NewValue = &variable.Value
+		change.ChangedAt = now
+		if _, err := tx.NewInsert().Model(change).Returning("id").Exec(ctx); err != nil {
+			return dbError(err, "global variable change", "failed to record global variable change")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// Delete removes a global variable and records the change
+func (r *GlobalVariableRepository) Delete(ctx context.Context, key string) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		variable := &models.GlobalVariable{}
+		err := tx.NewDelete().
+			Model(variable).
+			Where("key = ?", key).
+			Returning("*").
+			Scan(ctx)
+
+		if err != nil {
+			return dbError(err, "global variable", "failed to delete global variable")
+		}
+
+		change := &models.GlobalVariableChange{
+			Key:       key,
+			Action:    models.GlobalVariableDeleted,
+			OldValue:  &variable.Value,
+			ChangedAt: time.Now(),
+		}
+		if _, err := tx.NewInsert().Model(change).Returning("id").Exec(ctx); err != nil {
+			return dbError(err, "global variable change", "failed to record global variable change")
+		}
+
+		return nil
+	})
+}
+
+// ListChanges returns the recorded changes to a global variable with
+// pagination, newest first
+func (r *GlobalVariableRepository) ListChanges(ctx context.Context, key string, offset, limit int) ([]*models.GlobalVariableChange, error) {
+	var changes []*models.GlobalVariableChange
+	err := r.db.NewSelect().
+		Model(&changes).
+		Where("key = ?", key).
+		OrderExpr("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "global variable change", "failed to list global variable changes")
+	}
+
+	return changes, nil
+}
+
+// CountChanges returns the number of recorded changes to a global variable
+func (r *GlobalVariableRepository) CountChanges(ctx context.Context, key string) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.GlobalVariableChange)(nil)).
+		Where("key = ?", key).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "global variable change", "failed to count global variable changes")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// HeaderPresetRepository handles database operations for header presets
+type HeaderPresetRepository struct {
+	db *bun.DB
+}
+
+// NewHeaderPresetRepository creates a new header preset repository
+func NewHeaderPresetRepository(db *bun.DB) interfaces.HeaderPresetRepository {
+	return &HeaderPresetRepository{db: db}
+}
+
+// Create adds a new header preset to the database
+func (r *HeaderPresetRepository) Create(ctx context.Context, preset *models.HeaderPreset) error {
+	preset.CreatedAt = time.Now()
+	preset.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(preset).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "header preset", "failed to create header preset")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a header preset by its ID
+func (r *HeaderPresetRepository) GetByID(ctx context.Context, id int64) (*models.HeaderPreset, error) {
+	preset := &models.HeaderPreset{}
+	err := r.db.NewSelect().
+		Model(preset).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "header preset", "failed to get header preset by ID")
+	}
+
+	return preset, nil
+}
+
+// ListByIDs returns the header presets with the given IDs; missing IDs are skipped
+func (r *HeaderPresetRepository) ListByIDs(ctx context.Context, ids []int64) ([]*models.HeaderPreset, error) {
+	var presets []*models.HeaderPreset
+	if len(ids) == 0 {
+		return presets, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&presets).
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "header preset", "failed to list header presets by ID")
+	}
+
+	return presets, nil
+}
+
+// List returns all header presets with pagination, by name
+func (r *HeaderPresetRepository) List(ctx context.Context, offset, limit int) ([]*models.HeaderPreset, error) {
+	var presets []*models.HeaderPreset
+	err := r.db.NewSelect().
+		Model(&presets).
+		OrderExpr("name ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "header preset", "failed to list header presets")
+	}
+
+	return presets, nil
+}
+
+// Update modifies an existing header preset
+func (r *HeaderPresetRepository) Update(ctx context.Context, preset *models.HeaderPreset) error {
+	preset.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(preset).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "header preset", "failed to update header preset")
+	}
+
+	return nil
+}
+
+// Delete removes a header preset from the database
+func (r *HeaderPresetRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.HeaderPreset)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "header preset", "failed to delete header preset")
+	}
+
+	return nil
+}
+
+// Count returns the total number of header presets
+func (r *HeaderPresetRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.HeaderPreset)(nil)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "header preset", "failed to count header presets")
+	}
+
+	return count, nil
+}
+
+// CountRequests returns the number of requests a header preset is attached to
+func (r *HeaderPresetRepository) CountRequests(ctx context.Context, id int64) (int, error) {
+	ids, err := json.Marshal([]int64{id})
+	if err != nil {
+		return 0, err
+	}
+
+	q := r.db.NewSelect().Model((*models.Request)(nil))
+	if isSQLite(r.db) {
+		q = q.Where("EXISTS (SELECT 1 FROM json_each(header_presets) WHERE value = ?)", id)
+	} else {
+		q = q.Where("header_presets @> ?::jsonb", string(ids))
+	}
+
+	count, err := q.Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "request", "failed to count requests using header preset")
+	}
+
+	return count, nil
+}
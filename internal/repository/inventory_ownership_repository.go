@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// InventoryOwnershipRepository handles database operations for inventory entry ownership
+type InventoryOwnershipRepository struct {
+	db *bun.DB
+}
+
+// NewInventoryOwnershipRepository creates a new inventory ownership repository
+func NewInventoryOwnershipRepository(db *bun.DB) interfaces.InventoryOwnershipRepository {
+	return &InventoryOwnershipRepository{db: db}
+}
+
+// Upsert records the ownership of an inventory entry, replacing any previous one
+func (r *InventoryOwnershipRepository) Upsert(ctx context.Context, ownership *models.InventoryOwnership) error {
+	ownership.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(ownership).
+		On("CONFLICT (key) DO UPDATE").
+		Set("ownership = EXCLUDED.ownership").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "inventory ownership", "failed to save inventory ownership")
+	}
+
+	return nil
+}
+
+// List returns the recorded ownership of every inventory entry
+func (r *InventoryOwnershipRepository) List(ctx context.Context) ([]*models.InventoryOwnership, error) {
+	var ownerships []*models.InventoryOwnership
+	err := r.db.NewSelect().
+		Model(&ownerships).
+		OrderExpr("key ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "inventory ownership", "failed to list inventory ownership")
+	}
+
+	return ownerships, nil
+}
+
+// Delete removes the ownership recorded for an inventory entry
+func (r *InventoryOwnershipRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.db.NewDelete().
+		Model((*models.InventoryOwnership)(nil)).
+		Where("key = ?", key).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "inventory ownership", "failed to delete inventory ownership")
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// JobRepository handles database operations for the job queue
+type JobRepository struct {
+	db *bun.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *bun.DB) interfaces.JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Create adds a job to the queue
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = time.Now()
+	if job.RunAt.IsZero() {
+		job.RunAt = job.CreatedAt
+	}
+
+	_, err := r.db.NewInsert().
+		Model(job).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "job", "failed to create job")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(ctx context.Context, id int64) (*models.Job, error) {
+	job := &models.Job{}
+	err := r.db.NewSelect().
+		Model(job).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "job", "failed to get job by ID")
+	}
+
+	return job, nil
+}
+
+// List returns the jobs matching filter, newest first
+func (r *JobRepository) List(ctx context.Context, filter models.JobFilter, offset, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	err := r.filtered(r.db.NewSelect().Model(&jobs), filter).
+		OrderExpr("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "job", "failed to list jobs")
+	}
+
+	return jobs, nil
+}
+
+// Count returns the number of jobs matching filter
+func (r *JobRepository) Count(ctx context.Context, filter models.JobFilter) (int, error) {
+	count, err := r.filtered(r.db.NewSelect().Model((*models.Job)(nil)), filter).Count(ctx)
+	if err != nil {
+		return 0, dbError(err, "job", "failed to count jobs")
+	}
+
+	return count, nil
+}
+
+func (r *JobRepository) filtered(q *bun.SelectQuery, filter models.JobFilter) *bun.SelectQuery {
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	return q
+}
+
+// Claim marks the next due pending job running and returns it, or nil when
+// none is due; rows locked by other workers are skipped, so each job is
+// handed to a single worker. SQLite runs one write at a time, which does
+// the same.
+func (r *JobRepository) Claim(ctx context.Context, now time.Time) (*models.Job, error) {
+	next := r.db.NewSelect().
+		Model((*models.Job)(nil)).
+		Column("id").
+		Where("status = ?", models.JobPending).
+		Where("run_at <= ?", now).
+		OrderExpr("run_at ASC, id ASC").
+		Limit(1).
+		Apply(lockRows("UPDATE SKIP LOCKED"))
+
+	job := &models.Job{}
+	res, err := r.db.NewUpdate().
+		Model(job).
+		Set("status = ?", models.JobRunning).
+		Set("attempts = attempts + 1").
+		Set("started_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = (?)", next).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "job", "failed to claim job")
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, nil
+	}
+
+	return job, nil
+}
+
+// Update modifies an existing job
+func (r *JobRepository) Update(ctx context.Context, job *models.Job) error {
+	job.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(job).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "job", "failed to update job")
+	}
+
+	return nil
+}
+
+// SetProgress records how far a running job got
+func (r *JobRepository) SetProgress(ctx context.Context, id int64, progress models.JSONMap) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.Job)(nil)).
+		Set("progress = ?", progress).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "job", "failed to record job progress")
+	}
+
+	return nil
+}
+
+// RequeueStale returns jobs left running since before startedBefore, by a
+// worker that stopped without finishing them, to the queue
+func (r *JobRepository) RequeueStale(ctx context.Context, startedBefore time.Time) (int, error) {
+	res, err := r.db.NewUpdate().
+		Model((*models.Job)(nil)).
+		Set("status = ?", models.JobPending).
+		Set("run_at = ?", time.Now()).
+		Set("updated_at = ?", time.Now()).
+		Where("status = ?", models.JobRunning).
+		Where("started_at < ?", startedBefore).
+		Exec(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "job", "failed to requeue stale jobs")
+	}
+
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
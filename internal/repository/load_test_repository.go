@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// LoadTestRepository handles database operations for load tests
+type LoadTestRepository struct {
+	db *bun.DB
+}
+
+// NewLoadTestRepository creates a new load test repository
+func NewLoadTestRepository(db *bun.DB) interfaces.LoadTestRepository {
+	return &LoadTestRepository{db: db}
+}
+
+// Create adds a new load test to the database
+func (r *LoadTestRepository) Create(ctx context.Context, test *models.LoadTest) error {
+	if test.StartedAt.IsZero() {
+		test.StartedAt = time.Now()
+	}
+
+	_, err := r.db.NewInsert().
+		Model(test).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "load test", "failed to create load test")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a load test by its ID
+func (r *LoadTestRepository) GetByID(ctx context.Context, id int64) (*models.LoadTest, error) {
+	test := &models.LoadTest{}
+	err := r.db.NewSelect().
+		Model(test).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "load test", "failed to get load test by ID")
+	}
+
+	return test, nil
+}
+
+// Update modifies an existing load test
+func (r *LoadTestRepository) Update(ctx context.Context, test *models.LoadTest) error {
+	_, err := r.db.NewUpdate().
+		Model(test).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "load test", "failed to update load test")
+	}
+
+	return nil
+}
@@ -0,0 +1,496 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// mirrorBatchSize bounds the requests read at a time when writing a collection file
+const mirrorBatchSize = 500
+
+// collectionFile is the content of a collection's file: the collection row
+// with its folders and requests, ordered by ID so that files diff cleanly
+type collectionFile struct {
+	Collection *models.Collection `json:"collection"`
+	Folders    []*models.Folder   `json:"folders"`
+	Requests   []*models.Request  `json:"requests"`
+}
+
+// CatalogMirror exports every collection as a JSON file below a directory,
+// for teams who review their catalog as files or keep it under version
+// control. It is a one-way, write-only copy: the database stays the system
+// of record, files are never read back, and edits to them are overwritten.
+// The mirrored repositories mark the collections they change, and Run writes
+// marked collections out after their transactions commit.
+type CatalogMirror struct {
+	dir            string
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+	folderRepo     interfaces.FolderRepository
+
+	mu          sync.Mutex
+	collections map[int64]bool
+	requests    map[int64]bool
+}
+
+// NewCatalogMirror creates a mirror of the catalog kept in dir, reading
+// collections through the given repositories
+func NewCatalogMirror(
+	dir string,
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	folderRepo interfaces.FolderRepository,
+) *CatalogMirror {
+	return &CatalogMirror{
+		dir:            dir,
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+		folderRepo:     folderRepo,
+		collections:    map[int64]bool{},
+		requests:       map[int64]bool{},
+	}
+}
+
+// markCollections queues collections to be written out once the transaction
+// of ctx commits
+func (f *CatalogMirror) markCollections(ctx context.Context, ids ...int64) {
+	deferUntilCommit(ctx, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, id := range ids {
+			f.collections[id] = true
+		}
+	})
+}
+
+// markRequest queues the collection of a request to be written out once the
+// transaction of ctx commits
+func (f *CatalogMirror) markRequest(ctx context.Context, id int64) {
+	deferUntilCommit(ctx, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.requests[id] = true
+	})
+}
+
+// Run writes every collection out, then the collections changed since every
+// interval, until ctx is cancelled
+func (f *CatalogMirror) Run(ctx context.Context, interval time.Duration) {
+	if err := f.SyncAll(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("Failed to write catalog mirror: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Changes made before shutdown still reach the files
+			f.Flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			f.Flush(ctx)
+		}
+	}
+}
+
+// SyncAll writes out every collection, archived ones included, and removes
+// the files of collections deleted since
+func (f *CatalogMirror) SyncAll(ctx context.Context) error {
+	written := map[string]bool{}
+	for offset := 0; ; offset += mirrorBatchSize {
+		collections, err := f.collectionRepo.List(ctx, offset, mirrorBatchSize, true)
+		if err != nil {
+			return err
+		}
+
+		for _, collection := range collections {
+			if err := f.write(ctx, collection); err != nil {
+				return err
+			}
+			written[filepath.Base(f.path(collection.ID))] = true
+		}
+
+		if len(collections) < mirrorBatchSize {
+			break
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(f.dir, "collections"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read mirror directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || written[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.dir, "collections", entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove mirror file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Flush writes out the collections changed since the last flush; those that
+// fail are tried again on the next one
+func (f *CatalogMirror) Flush(ctx context.Context) {
+	f.mu.Lock()
+	collections, requests := f.collections, f.requests
+	f.collections, f.requests = map[int64]bool{}, map[int64]bool{}
+	f.mu.Unlock()
+
+	for id := range requests {
+		request, err := f.requestRepo.GetByID(ctx, id)
+		if err != nil {
+			if !missing(err) {
+				log.Printf("Failed to find collection of request %d for catalog mirror: %v", id, err)
+				f.markRequest(ctx, id)
+			}
+			continue
+		}
+		collections[request.CollectionID] = true
+	}
+
+	for id := range collections {
+		if err := f.sync(ctx, id); err != nil {
+			log.Printf("Failed to write mirror file of collection %d: %v", id, err)
+			f.markCollections(ctx, id)
+		}
+	}
+}
+
+// sync writes out a collection, or removes its file once it is deleted
+func (f *CatalogMirror) sync(ctx context.Context, id int64) error {
+	collection, err := f.collectionRepo.GetByID(ctx, id)
+	if missing(err) {
+		if err := os.Remove(f.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove mirror file: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return f.write(ctx, collection)
+}
+
+// write replaces the file of a collection with its current content
+func (f *CatalogMirror) write(ctx context.Context, collection *models.Collection) error {
+	file := collectionFile{Collection: collection, Folders: []*models.Folder{}, Requests: []*models.Request{}}
+
+	folders, err := f.folderRepo.ListByCollectionID(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+	file.Folders = append(file.Folders, folders...)
+
+	for offset := 0; ; offset += mirrorBatchSize {
+		requests, err := f.requestRepo.ListByCollectionID(ctx, collection.ID, offset, mirrorBatchSize)
+		if err != nil {
+			return err
+		}
+		file.Requests = append(file.Requests, requests...)
+		if len(requests) < mirrorBatchSize {
+			break
+		}
+	}
+
+	slices.SortFunc(file.Folders, func(a, b *models.Folder) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(file.Requests, func(a, b *models.Request) int { return cmp.Compare(a.ID, b.ID) })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mirror file: %w", err)
+	}
+
+	return writeFileAtomic(f.path(collection.ID), append(data, '\n'))
+}
+
+// missing reports whether err is the not-found error of a missing row
+func missing(err error) bool {
+	return err != nil && models.ErrorCodeOf(err) == models.ErrCodeNotFound
+}
+
+func (f *CatalogMirror) path(id int64) string {
+	return filepath.Join(f.dir, "collections", strconv.FormatInt(id, 10)+".json")
+}
+
+// writeFileAtomic replaces the file at path with data, so that readers never
+// see it half written
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create mirror file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write mirror file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write mirror file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store mirror file: %w", err)
+	}
+
+	return nil
+}
+
+// MirroredCollectionRepository marks the collections it changes for CatalogMirror
+type MirroredCollectionRepository struct {
+	interfaces.CollectionRepository
+	mirror *CatalogMirror
+}
+
+// NewMirroredCollectionRepository wraps repo to keep the mirror of collections up to date
+func NewMirroredCollectionRepository(repo interfaces.CollectionRepository, mirror *CatalogMirror) interfaces.CollectionRepository {
+	return &MirroredCollectionRepository{CollectionRepository: repo, mirror: mirror}
+}
+
+func (r *MirroredCollectionRepository) WithTx(tx bun.Tx) interfaces.CollectionRepository {
+	return &MirroredCollectionRepository{CollectionRepository: r.CollectionRepository.WithTx(tx), mirror: r.mirror}
+}
+
+func (r *MirroredCollectionRepository) Create(ctx context.Context, collection *models.Collection) error {
+	if err := r.CollectionRepository.Create(ctx, collection); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, collection.ID)
+	return nil
+}
+
+func (r *MirroredCollectionRepository) Update(ctx context.Context, collection *models.Collection) error {
+	if err := r.CollectionRepository.Update(ctx, collection); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, collection.ID)
+	return nil
+}
+
+func (r *MirroredCollectionRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.CollectionRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, id)
+	return nil
+}
+
+func (r *MirroredCollectionRepository) SetArchived(ctx context.Context, ids []int64, archived bool) (int, error) {
+	changed, err := r.CollectionRepository.SetArchived(ctx, ids, archived)
+	if err != nil || changed == 0 {
+		return changed, err
+	}
+	r.mirror.markCollections(ctx, ids...)
+	return changed, nil
+}
+
+func (r *MirroredCollectionRepository) ClearItems(ctx context.Context, id int64) error {
+	if err := r.CollectionRepository.ClearItems(ctx, id); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, id)
+	return nil
+}
+
+// MirroredRequestRepository marks the collections of the requests it changes for CatalogMirror
+type MirroredRequestRepository struct {
+	interfaces.RequestRepository
+	mirror *CatalogMirror
+}
+
+// NewMirroredRequestRepository wraps repo to keep the mirror of collections up to date
+func NewMirroredRequestRepository(repo interfaces.RequestRepository, mirror *CatalogMirror) interfaces.RequestRepository {
+	return &MirroredRequestRepository{RequestRepository: repo, mirror: mirror}
+}
+
+func (r *MirroredRequestRepository) WithTx(tx bun.Tx) interfaces.RequestRepository {
+	return &MirroredRequestRepository{RequestRepository: r.RequestRepository.WithTx(tx), mirror: r.mirror}
+}
+
+func (r *MirroredRequestRepository) Create(ctx context.Context, request *models.Request) error {
+	if err := r.RequestRepository.Create(ctx, request); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, request.CollectionID)
+	return nil
+}
+
+// Update also marks the collection a request was moved out of
+func (r *MirroredRequestRepository) Update(ctx context.Context, request *models.Request) error {
+	previous, err := r.RequestRepository.GetByID(ctx, request.ID)
+	if err != nil && !missing(err) {
+		return err
+	}
+
+	if err := r.RequestRepository.Update(ctx, request); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, request.CollectionID)
+	if previous != nil && previous.CollectionID != request.CollectionID {
+		r.mirror.markCollections(ctx, previous.CollectionID)
+	}
+	return nil
+}
+
+func (r *MirroredRequestRepository) Delete(ctx context.Context, id int64) error {
+	request, err := r.RequestRepository.GetByID(ctx, id)
+	if missing(err) {
+		return r.RequestRepository.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.RequestRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, request.CollectionID)
+	return nil
+}
+
+func (r *MirroredRequestRepository) DeleteByCollectionID(ctx context.Context, collectionID int64) error {
+	if err := r.RequestRepository.DeleteByCollectionID(ctx, collectionID); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, collectionID)
+	return nil
+}
+
+func (r *MirroredRequestRepository) DeleteByFolderIDs(ctx context.Context, collectionID int64, folderIDs []int64) ([]int64, error) {
+	deleted, err := r.RequestRepository.DeleteByFolderIDs(ctx, collectionID, folderIDs)
+	if err != nil {
+		return nil, err
+	}
+	r.mirror.markCollections(ctx, collectionID)
+	return deleted, nil
+}
+
+// MirroredFolderRepository marks the collections of the folders it changes for
+// CatalogMirror; requests moved along with a folder are written out with it
+type MirroredFolderRepository struct {
+	interfaces.FolderRepository
+	mirror *CatalogMirror
+}
+
+// NewMirroredFolderRepository wraps repo to keep the mirror of collections up to date
+func NewMirroredFolderRepository(repo interfaces.FolderRepository, mirror *CatalogMirror) interfaces.FolderRepository {
+	return &MirroredFolderRepository{FolderRepository: repo, mirror: mirror}
+}
+
+func (r *MirroredFolderRepository) WithTx(tx bun.Tx) interfaces.FolderRepository {
+	return &MirroredFolderRepository{FolderRepository: r.FolderRepository.WithTx(tx), mirror: r.mirror}
+}
+
+func (r *MirroredFolderRepository) Create(ctx context.Context, folder *models.Folder) error {
+	if err := r.FolderRepository.Create(ctx, folder); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, folder.CollectionID)
+	return nil
+}
+
+func (r *MirroredFolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	if err := r.FolderRepository.Update(ctx, folder); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, folder.CollectionID)
+	return nil
+}
+
+func (r *MirroredFolderRepository) Delete(ctx context.Context, id int64) error {
+	folder, err := r.FolderRepository.GetByID(ctx, id)
+	if missing(err) {
+		return r.FolderRepository.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.FolderRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.mirror.markCollections(ctx, folder.CollectionID)
+	return nil
+}
+
+// MirroredExampleRepository marks the collections of the requests whose examples
+// it changes for CatalogMirror
+type MirroredExampleRepository struct {
+	interfaces.ExampleRepository
+	mirror *CatalogMirror
+}
+
+// NewMirroredExampleRepository wraps repo to keep the mirror of collections up to date
+func NewMirroredExampleRepository(repo interfaces.ExampleRepository, mirror *CatalogMirror) interfaces.ExampleRepository {
+	return &MirroredExampleRepository{ExampleRepository: repo, mirror: mirror}
+}
+
+func (r *MirroredExampleRepository) WithTx(tx bun.Tx) interfaces.ExampleRepository {
+	return &MirroredExampleRepository{ExampleRepository: r.ExampleRepository.WithTx(tx), mirror: r.mirror}
+}
+
+func (r *MirroredExampleRepository) Create(ctx context.Context, example *models.Example) error {
+	if err := r.ExampleRepository.Create(ctx, example); err != nil {
+		return err
+	}
+	r.mirror.markRequest(ctx, example.RequestID)
+	return nil
+}
+
+func (r *MirroredExampleRepository) Update(ctx context.Context, example *models.Example) error {
+	if err := r.ExampleRepository.Update(ctx, example); err != nil {
+		return err
+	}
+	r.mirror.markRequest(ctx, example.RequestID)
+	return nil
+}
+
+func (r *MirroredExampleRepository) Delete(ctx context.Context, id int64) error {
+	example, err := r.ExampleRepository.GetByID(ctx, id)
+	if missing(err) {
+		return r.ExampleRepository.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.ExampleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.mirror.markRequest(ctx, example.RequestID)
+	return nil
+}
+
+func (r *MirroredExampleRepository) ReplaceForRequest(ctx context.Context, requestID int64, responses []models.PostmanResponse) error {
+	if err := r.ExampleRepository.ReplaceForRequest(ctx, requestID, responses); err != nil {
+		return err
+	}
+	r.mirror.markRequest(ctx, requestID)
+	return nil
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -12,13 +14,18 @@ import (
 
 // OpenAPIRepository handles database operations for OpenAPI specifications
 type OpenAPIRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 func NewOpenAPIRepository(db *bun.DB) interfaces.OpenAPIRepository {
 	return &OpenAPIRepository{db: db}
 }
 
+// WithTx returns a repository running its queries in tx
+func (r *OpenAPIRepository) WithTx(tx bun.Tx) interfaces.OpenAPIRepository {
+	return &OpenAPIRepository{db: tx}
+}
+
 // Create adds a new OpenAPI specification to the database
 func (r *OpenAPIRepository) Create(ctx context.Context, spec *models.OpenAPISpec) error {
 	spec.CreatedAt = time.Now()
@@ -30,7 +37,7 @@ func (r *OpenAPIRepository) Create(ctx context.Context, spec *models.OpenAPISpec
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to create OpenAPI spec: %w", err)
+		return dbError(err, "OpenAPI specification", "failed to create OpenAPI spec")
 	}
 
 	return nil
@@ -45,12 +52,28 @@ func (r *OpenAPIRepository) GetByID(ctx context.Context, id int64) (*models.Open
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get OpenAPI spec by ID: %w", err)
+		return nil, dbError(err, "OpenAPI specification", "failed to get OpenAPI spec by ID")
 	}
 
 	return spec, nil
 }
 
+// GetByIDs retrieves the OpenAPI specifications with the given IDs in one
+// query; IDs without a specification are skipped
+func (r *OpenAPIRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.OpenAPISpec, error) {
+	var specs []*models.OpenAPISpec
+	err := r.db.NewSelect().
+		Model(&specs).
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification", "failed to get OpenAPI specs by ID")
+	}
+
+	return specs, nil
+}
+
 // GetByTitle retrieves an OpenAPI specification by its title
 func (r *OpenAPIRepository) GetByTitle(ctx context.Context, title string) (*models.OpenAPISpec, error) {
 	spec := &models.OpenAPISpec{}
@@ -60,7 +83,7 @@ func (r *OpenAPIRepository) GetByTitle(ctx context.Context, title string) (*mode
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get OpenAPI spec by title: %w", err)
+		return nil, dbError(err, "OpenAPI specification", "failed to get OpenAPI spec by title")
 	}
 
 	return spec, nil
@@ -77,26 +100,73 @@ func (r *OpenAPIRepository) List(ctx context.Context, offset, limit int) ([]*mod
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list OpenAPI specs: %w", err)
+		return nil, dbError(err, "OpenAPI specification", "failed to list OpenAPI specs")
 	}
 
 	return specs, nil
 }
 
-// Update modifies an existing OpenAPI specification
+// Update modifies an existing OpenAPI specification; when its content
+// changes, the content it replaces is kept as a revision
 func (r *OpenAPIRepository) Update(ctx context.Context, spec *models.OpenAPISpec) error {
 	spec.UpdatedAt = time.Now()
 
-	_, err := r.db.NewUpdate().
-		Model(spec).
-		WherePK().
-		Exec(ctx)
+	var content any
+	if spec.Content != nil {
+		data, err := json.Marshal(spec.Content)
+		if err != nil {
+			return fmt.Errorf("failed to encode OpenAPI spec content: %w", err)
+		}
+		content = string(data)
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to update OpenAPI spec: %w", err)
+	// SQLite keeps the content as the same JSON text and locks nothing
+	changed, lock := "?::jsonb", "FOR UPDATE OF s"
+	if isSQLite(r.db) {
+		changed, lock = "?", ""
 	}
 
-	return nil
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewRaw(`
+			INSERT INTO openapi_spec_revisions (spec_id, revision, title, version, content, created_at)
+			SELECT s.id,
+				COALESCE((SELECT MAX(revision) FROM openapi_spec_revisions WHERE spec_id = s.id), 0) + 1,
+				s.title, s.version, s.content, ?
+			FROM openapi_specs s
+			WHERE s.id = ? AND s.content IS DISTINCT FROM `+changed+`
+			`+lock, spec.UpdatedAt, spec.ID, content).
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "OpenAPI specification revision", "failed to keep OpenAPI spec revision")
+		}
+
+		// New content sends a spec under review back to draft
+		if kept, _ := res.RowsAffected(); kept > 0 {
+			_, err = tx.NewRaw(`
+				INSERT INTO openapi_spec_review_decisions (spec_id, decision, from_status, to_status, created_at)
+				SELECT d.spec_id, ?, d.to_status, ?, ?
+				FROM (SELECT spec_id, to_status FROM openapi_spec_review_decisions WHERE spec_id = ? ORDER BY id DESC LIMIT 1) d
+				WHERE d.to_status <> ?`,
+				models.DecisionContentChanged, models.ReviewDraft, spec.UpdatedAt, spec.ID, models.ReviewDraft).
+				Exec(ctx)
+
+			if err != nil {
+				return dbError(err, "spec review decision", "failed to reset spec review")
+			}
+		}
+
+		_, err = tx.NewUpdate().
+			Model(spec).
+			WherePK().
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "OpenAPI specification", "failed to update OpenAPI spec")
+		}
+
+		return nil
+	})
 }
 
 // Delete removes an OpenAPI specification from the database
@@ -107,12 +177,17 @@ func (r *OpenAPIRepository) Delete(ctx context.Context, id int64) error {
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete OpenAPI spec: %w", err)
+		return dbError(err, "OpenAPI specification", "failed to delete OpenAPI spec")
 	}
 
 	return nil
 }
 
+// EstimateCount returns the planner's estimate of the number of OpenAPI specifications
+func (r *OpenAPIRepository) EstimateCount(ctx context.Context) (int, error) {
+	return estimateTableRows(ctx, r.db, "openapi_specs")
+}
+
 // Count returns the total number of OpenAPI specifications
 func (r *OpenAPIRepository) Count(ctx context.Context) (int, error) {
 	count, err := r.db.NewSelect().
@@ -120,7 +195,7 @@ func (r *OpenAPIRepository) Count(ctx context.Context) (int, error) {
 		Count(ctx)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to count OpenAPI specs: %w", err)
+		return 0, dbError(err, "OpenAPI specification", "failed to count OpenAPI specs")
 	}
 
 	return count, nil
@@ -131,14 +206,107 @@ func (r *OpenAPIRepository) Search(ctx context.Context, query string, offset, li
 	var specs []*models.OpenAPISpec
 	err := r.db.NewSelect().
 		Model(&specs).
-		Where("title ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").
+		Apply(matchSpecs(query)).
+		OrderExpr("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification", "failed to search OpenAPI specs")
+	}
+
+	return specs, nil
+}
+
+// CountSearch returns the number of OpenAPI specifications Search matches
+func (r *OpenAPIRepository) CountSearch(ctx context.Context, query string) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.OpenAPISpec)(nil)).
+		Apply(matchSpecs(query)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "OpenAPI specification", "failed to count OpenAPI spec search results")
+	}
+
+	return count, nil
+}
+
+// matchSpecs filters specs whose title or description contains query,
+// ignoring case; LIKE wildcards in query match literally. SQLite's LIKE
+// ignores the case of ASCII letters only and needs its escape spelled out.
+func matchSpecs(query string) func(*bun.SelectQuery) *bun.SelectQuery {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		if isSQLite(q.DB()) {
+			return q.Where(`title LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\'`, pattern, pattern)
+		}
+		return q.Where("title ILIKE ? OR description ILIKE ?", pattern, pattern)
+	}
+}
+
+// ExistsByMetadata reports whether any OpenAPI spec has metadata[key] equal to value
+func (r *OpenAPIRepository) ExistsByMetadata(ctx context.Context, key, value string) (bool, error) {
+	exists, err := r.db.NewSelect().
+		Model((*models.OpenAPISpec)(nil)).
+		Where("metadata ->> ? = ?", key, value).
+		Exists(ctx)
+
+	if err != nil {
+		return false, dbError(err, "OpenAPI specification", "failed to look up OpenAPI spec by metadata")
+	}
+
+	return exists, nil
+}
+
+// FindByMetadata returns the oldest OpenAPI spec with metadata[key] equal to value
+func (r *OpenAPIRepository) FindByMetadata(ctx context.Context, key, value string) (*models.OpenAPISpec, error) {
+	spec := &models.OpenAPISpec{}
+	err := r.db.NewSelect().
+		Model(spec).
+		Where("metadata ->> ? = ?", key, value).
+		OrderExpr("id ASC").
+		Limit(1).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification", "failed to find OpenAPI spec by metadata")
+	}
+
+	return spec, nil
+}
+
+// ListSupersededBefore returns specs imported by a spec source before the given
+// time that are no longer the source's latest import, oldest first
+func (r *OpenAPIRepository) ListSupersededBefore(ctx context.Context, before time.Time, limit int) ([]*models.OpenAPISpec, error) {
+	var specs []*models.OpenAPISpec
+	err := r.db.NewSelect().
+		Model(&specs).
+		Where("o.created_at < ?", before).
+		Where("EXISTS (SELECT 1 FROM spec_sources AS ss WHERE ss.id = CAST(o.metadata ->> 'source_id' AS BIGINT) AND ss.last_spec_id <> o.id)").
+		OrderExpr("o.created_at ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification", "failed to list superseded OpenAPI specs")
+	}
+
+	return specs, nil
+}
+
+// ListByOwnership returns OpenAPI specs whose recorded ownership matches filter
+func (r *OpenAPIRepository) ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.OpenAPISpec, error) {
+	var specs []*models.OpenAPISpec
+	err := whereOwnership(r.db.NewSelect().Model(&specs), filter).
 		OrderExpr("created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to search OpenAPI specs: %w", err)
+		return nil, dbError(err, "OpenAPI specification", "failed to list OpenAPI specs by ownership")
 	}
 
 	return specs, nil
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// OutboxRepository handles database operations for the event outbox
+type OutboxRepository struct {
+	db bun.IDB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *bun.DB) interfaces.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *OutboxRepository) WithTx(tx bun.Tx) interfaces.OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+// Create adds an event to the outbox
+func (r *OutboxRepository) Create(ctx context.Context, event *models.OutboxEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	_, err := r.db.NewInsert().
+		Model(event).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "outbox event", "failed to create outbox event")
+	}
+
+	return nil
+}
+
+// ListPending returns unpublished events, oldest first
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+	var events []*models.OutboxEvent
+	err := r.db.NewSelect().
+		Model(&events).
+		Where("published_at IS NULL").
+		OrderExpr("id ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "outbox event", "failed to list outbox events")
+	}
+
+	return events, nil
+}
+
+// MarkPublished records that the broker accepted an event
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxEvent)(nil)).
+		Set("published_at = ?", time.Now()).
+		Set("attempts = attempts + 1").
+		Set("last_error = NULL").
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "outbox event", "failed to mark outbox event published")
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt of an event
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxEvent)(nil)).
+		Set("attempts = attempts + 1").
+		Set("last_error = ?", reason).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "outbox event", "failed to mark outbox event failed")
+	}
+
+	return nil
+}
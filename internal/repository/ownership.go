@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"postman-api/internal/models"
+
+	"github.com/uptrace/bun"
+)
+
+// whereOwnership limits q to rows whose metadata records an ownership matching filter
+func whereOwnership(q *bun.SelectQuery, filter models.OwnershipFilter) *bun.SelectQuery {
+	q = q.Where("metadata -> ? IS NOT NULL", models.OwnershipMetadataKey)
+
+	if filter.Team != "" {
+		q = q.Where("metadata -> ? ->> 'team' = ?", models.OwnershipMetadataKey, filter.Team)
+	}
+	if filter.Tier != "" {
+		q = q.Where("metadata -> ? ->> 'tier' = ?", models.OwnershipMetadataKey, filter.Tier)
+	}
+	if filter.Lifecycle != "" {
+		q = q.Where("metadata -> ? ->> 'lifecycle' = ?", models.OwnershipMetadataKey, filter.Lifecycle)
+	}
+
+	return q
+}
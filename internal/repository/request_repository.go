@@ -2,7 +2,8 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
 	"time"
@@ -12,7 +13,7 @@ import (
 
 // RequestRepository handles database operations for requests
 type RequestRepository struct {
-	db *bun.DB
+	db bun.IDB
 }
 
 // NewRequestRepository creates a new request repository
@@ -20,21 +21,32 @@ func NewRequestRepository(db *bun.DB) interfaces.RequestRepository {
 	return &RequestRepository{db: db}
 }
 
-// Create adds a new request to the database
+// WithTx returns a repository running its queries in tx
+func (r *RequestRepository) WithTx(tx bun.Tx) interfaces.RequestRepository {
+	return &RequestRepository{db: tx}
+}
+
+// Create adds a new request to the database, with its saved responses as examples
 func (r *RequestRepository) Create(ctx context.Context, request *models.Request) error {
 	request.CreatedAt = time.Now()
 	request.UpdatedAt = time.Now()
 
-	_, err := r.db.NewInsert().
-		Model(request).
-		Returning("id").
-		Exec(ctx)
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(request).
+			Returning("id").
+			Exec(ctx)
 
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+		if err != nil {
+			return dbError(err, "request", "failed to create request")
+		}
 
-	return nil
+		if err := adjustRequestCount(ctx, tx, request.CollectionID, 1); err != nil {
+			return err
+		}
+
+		return insertExamples(ctx, tx, request.ID, request.Responses)
+	})
 }
 
 // GetByID retrieves a request by its ID
@@ -46,12 +58,36 @@ func (r *RequestRepository) GetByID(ctx context.Context, id int64) (*models.Requ
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get request by ID: %w", err)
+		return nil, dbError(err, "request", "failed to get request by ID")
+	}
+
+	if err := loadExamples(ctx, r.db, request); err != nil {
+		return nil, err
 	}
 
 	return request, nil
 }
 
+// GetByIDs retrieves the requests with the given IDs in one query; IDs
+// without a request are skipped
+func (r *RequestRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.Request, error) {
+	var requests []*models.Request
+	err := r.db.NewSelect().
+		Model(&requests).
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "request", "failed to get requests by ID")
+	}
+
+	if err := loadExamples(ctx, r.db, requests...); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
 // GetByIDWithCollection retrieves a request by its ID with collection data
 func (r *RequestRepository) GetByIDWithCollection(ctx context.Context, id int64) (*models.Request, error) {
 	request := &models.Request{}
@@ -62,7 +98,11 @@ func (r *RequestRepository) GetByIDWithCollection(ctx context.Context, id int64)
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get request with collection: %w", err)
+		return nil, dbError(err, "request", "failed to get request with collection")
+	}
+
+	if err := loadExamples(ctx, r.db, request); err != nil {
+		return nil, err
 	}
 
 	return request, nil
@@ -79,7 +119,11 @@ func (r *RequestRepository) List(ctx context.Context, offset, limit int) ([]*mod
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list requests: %w", err)
+		return nil, dbError(err, "request", "failed to list requests")
+	}
+
+	if err := loadExamples(ctx, r.db, requests...); err != nil {
+		return nil, err
 	}
 
 	return requests, nil
@@ -97,54 +141,240 @@ func (r *RequestRepository) ListByCollectionID(ctx context.Context, collectionID
 		Scan(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list requests by collection ID: %w", err)
+		return nil, dbError(err, "request", "failed to list requests by collection ID")
+	}
+
+	if err := loadExamples(ctx, r.db, requests...); err != nil {
+		return nil, err
 	}
 
 	return requests, nil
 }
 
-// Update modifies an existing request
-func (r *RequestRepository) Update(ctx context.Context, request *models.Request) error {
-	request.UpdatedAt = time.Now()
+// ListDeprecated returns requests flagged as deprecated, optionally limited to one collection
+func (r *RequestRepository) ListDeprecated(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Request, error) {
+	var requests []*models.Request
+	q := r.db.NewSelect().
+		Model(&requests).
+		Where("deprecated")
 
-	_, err := r.db.NewUpdate().
-		Model(request).
-		WherePK().
-		Exec(ctx)
+	if collectionID != 0 {
+		q = q.Where("collection_id = ?", collectionID)
+	}
+
+	err := q.
+		OrderExpr("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to update request: %w", err)
+		return nil, dbError(err, "request", "failed to list deprecated requests")
 	}
 
-	return nil
+	if err := loadExamples(ctx, r.db, requests...); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// Update modifies an existing request; its examples are left as they are.
+// A request moved to another collection is counted in its new collection.
+func (r *RequestRepository) Update(ctx context.Context, request *models.Request) error {
+	request.UpdatedAt = time.Now()
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		previous, err := lockRequestCollection(ctx, tx, request.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model(request).
+			WherePK().
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "request", "failed to update request")
+		}
+
+		if previous == 0 || previous == request.CollectionID {
+			return nil
+		}
+
+		if err := adjustRequestCount(ctx, tx, previous, -1); err != nil {
+			return err
+		}
+		return adjustRequestCount(ctx, tx, request.CollectionID, 1)
+	})
 }
 
 // Delete removes a request from the database
 func (r *RequestRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		collectionID, err := lockRequestCollection(ctx, tx, id)
+		if err != nil || collectionID == 0 {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model((*models.Request)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "request", "failed to delete request")
+		}
+
+		return adjustRequestCount(ctx, tx, collectionID, -1)
+	})
+}
+
+// DeleteByCollectionID removes all requests associated with a collection
+func (r *RequestRepository) DeleteByCollectionID(ctx context.Context, collectionID int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model((*models.Request)(nil)).
+			Where("collection_id = ?", collectionID).
+			Exec(ctx)
+
+		if err != nil {
+			return dbError(err, "request", "failed to delete requests by collection ID")
+		}
+
+		deleted, err := res.RowsAffected()
+		if err != nil {
+			return dbError(err, "request", "failed to delete requests by collection ID")
+		}
+
+		return adjustRequestCount(ctx, tx, collectionID, -int(deleted))
+	})
+}
+
+// DeleteByFolderIDs removes the requests of a collection filed directly in
+// any of the given folders, adjusting its request count once, and returns the
+// IDs of the requests deleted
+func (r *RequestRepository) DeleteByFolderIDs(ctx context.Context, collectionID int64, folderIDs []int64) ([]int64, error) {
+	ids := []int64{}
+	if len(folderIDs) == 0 {
+		return ids, nil
+	}
+
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*models.Request)(nil)).
+			Where("collection_id = ?", collectionID).
+			Where("folder_id IN (?)", bun.In(folderIDs)).
+			Returning("id").
+			Exec(ctx, &ids)
+
+		if err != nil {
+			return dbError(err, "request", "failed to delete requests by folder")
+		}
+
+		return adjustRequestCount(ctx, tx, collectionID, -len(ids))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// lockRequestCollection locks a request until the end of the transaction and
+// returns the ID of its collection, or zero when there is no such request
+func lockRequestCollection(ctx context.Context, tx bun.Tx, id int64) (int64, error) {
+	var collectionID int64
+	err := tx.NewSelect().
 		Model((*models.Request)(nil)).
+		Column("collection_id").
 		Where("id = ?", id).
+		Apply(lockRows("UPDATE")).
+		Scan(ctx, &collectionID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, dbError(err, "request", "failed to lock request")
+	}
+
+	return collectionID, nil
+}
+
+// adjustRequestCount changes the request count of a collection by delta in
+// place, so that concurrent changes add up rather than overwrite each other
+func adjustRequestCount(ctx context.Context, tx bun.Tx, collectionID int64, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+
+	_, err := tx.NewUpdate().
+		Model((*models.Collection)(nil)).
+		Set("request_count = request_count + ?", delta).
+		Where("id = ?", collectionID).
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete request: %w", err)
+		return dbError(err, "collection", "failed to update request count")
 	}
 
 	return nil
 }
 
-// DeleteByCollectionID removes all requests associated with a collection
-func (r *RequestRepository) DeleteByCollectionID(ctx context.Context, collectionID int64) error {
-	_, err := r.db.NewDelete().
+// SetFolderPath records path as the folder path of every request directly
+// in a folder, returning the number of requests updated
+func (r *RequestRepository) SetFolderPath(ctx context.Context, folderID int64, path string) (int, error) {
+	res, err := r.db.NewUpdate().
 		Model((*models.Request)(nil)).
-		Where("collection_id = ?", collectionID).
+		Set("folder_path = ?", path).
+		Set("updated_at = ?", time.Now()).
+		Where("folder_id = ?", folderID).
 		Exec(ctx)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete requests by collection ID: %w", err)
+		return 0, dbError(err, "request", "failed to set folder path")
 	}
 
-	return nil
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, dbError(err, "request", "failed to set folder path")
+	}
+
+	return int(affected), nil
+}
+
+// NextPositions returns, for each folder of a collection holding requests,
+// the position after its last request; top-level requests are under 0
+func (r *RequestRepository) NextPositions(ctx context.Context, collectionID int64) (map[int64]int, error) {
+	var rows []struct {
+		FolderID int64 `bun:"folder_id"`
+		Next     int   `bun:"next"`
+	}
+	err := r.db.NewSelect().
+		Model((*models.Request)(nil)).
+		ColumnExpr("COALESCE(folder_id, 0) AS folder_id").
+		ColumnExpr("MAX(position) + 1 AS next").
+		Where("collection_id = ?", collectionID).
+		GroupExpr("1").
+		Scan(ctx, &rows)
+
+	if err != nil {
+		return nil, dbError(err, "request", "failed to get request positions")
+	}
+
+	positions := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		positions[row.FolderID] = row.Next
+	}
+
+	return positions, nil
+}
+
+// EstimateCount returns the planner's estimate of the number of requests
+func (r *RequestRepository) EstimateCount(ctx context.Context) (int, error) {
+	return estimateTableRows(ctx, r.db, "requests")
 }
 
 // Count returns the total number of requests
@@ -154,12 +384,21 @@ func (r *RequestRepository) Count(ctx context.Context) (int, error) {
 		Count(ctx)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to count requests: %w", err)
+		return 0, dbError(err, "request", "failed to count requests")
 	}
 
 	return count, nil
 }
 
+// EstimateCountByCollectionID returns the planner's estimate of the number of requests in a collection
+func (r *RequestRepository) EstimateCountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	query := r.db.NewSelect().
+		Model((*models.Request)(nil)).
+		Where("collection_id = ?", collectionID)
+
+	return estimateQueryRows(ctx, r.db, query)
+}
+
 // CountByCollectionID returns the number of requests in a collection
 func (r *RequestRepository) CountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
 	count, err := r.db.NewSelect().
@@ -168,7 +407,7 @@ func (r *RequestRepository) CountByCollectionID(ctx context.Context, collectionI
 		Count(ctx)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to count requests by collection ID: %w", err)
+		return 0, dbError(err, "request", "failed to count requests by collection ID")
 	}
 
 	return count, nil
@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/resilience"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// guard routes repository calls through a circuit breaker. Reads are
+// idempotent and retried on transient failures; writes are attempted once.
+// When a replica is configured, reads the primary cannot serve fall back to it.
+type guard[R any] struct {
+	breaker    *resilience.Breaker
+	retry      resilience.RetryPolicy
+	replica    R
+	hasReplica bool
+}
+
+func newGuard[R any](breaker *resilience.Breaker, retry resilience.RetryPolicy, replica R, hasReplica bool) guard[R] {
+	return guard[R]{breaker: breaker, retry: retry, replica: replica, hasReplica: hasReplica}
+}
+
+func read[R, T any](ctx context.Context, g guard[R], primary R, fn func(repo R, ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := resilience.Do(ctx, g.breaker, g.retry, func(ctx context.Context) error {
+		var err error
+		result, err = fn(primary, ctx)
+		return err
+	})
+
+	if err != nil && g.hasReplica && (errors.Is(err, resilience.ErrCircuitOpen) || resilience.IsTransient(err)) {
+		replicaResult, replicaErr := fn(g.replica, ctx)
+		if replicaErr == nil {
+			resilience.MarkDegraded(ctx)
+			return replicaResult, nil
+		}
+	}
+
+	return result, err
+}
+
+func (g guard[R]) write(ctx context.Context, fn func(ctx context.Context) error) error {
+	return resilience.Do(ctx, g.breaker, resilience.RetryPolicy{MaxAttempts: 1}, fn)
+}
+
+// inTx returns the guard for a repository bound to a transaction: a failed
+// statement aborts the transaction, so nothing is retried or sent to the replica
+func (g guard[R]) inTx() guard[R] {
+	var replica R
+	return newGuard(g.breaker, resilience.RetryPolicy{MaxAttempts: 1}, replica, false)
+}
+
+// ResilientCollectionRepository wraps a CollectionRepository with retries and a circuit breaker
+type ResilientCollectionRepository struct {
+	interfaces.CollectionRepository
+	guard[interfaces.CollectionRepository]
+}
+
+// NewResilientCollectionRepository wraps repo with retries and a circuit breaker; replica may be nil
+func NewResilientCollectionRepository(repo, replica interfaces.CollectionRepository, breaker *resilience.Breaker, retry resilience.RetryPolicy) interfaces.CollectionRepository {
+	return &ResilientCollectionRepository{
+		CollectionRepository: repo,
+		guard:                newGuard(breaker, retry, replica, replica != nil),
+	}
+}
+
+func (r *ResilientCollectionRepository) WithTx(tx bun.Tx) interfaces.CollectionRepository {
+	return &ResilientCollectionRepository{CollectionRepository: r.CollectionRepository.WithTx(tx), guard: r.inTx()}
+}
+
+func (r *ResilientCollectionRepository) Create(ctx context.Context, collection *models.Collection) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.CollectionRepository.Create(ctx, collection) })
+}
+
+func (r *ResilientCollectionRepository) GetByID(ctx context.Context, id int64) (*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (*models.Collection, error) {
+		return repo.GetByID(ctx, id)
+	})
+}
+
+func (r *ResilientCollectionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) ([]*models.Collection, error) {
+		return repo.GetByIDs(ctx, ids)
+	})
+}
+
+func (r *ResilientCollectionRepository) GetWithRequests(ctx context.Context, id int64) (*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (*models.Collection, error) {
+		return repo.GetWithRequests(ctx, id)
+	})
+}
+
+func (r *ResilientCollectionRepository) List(ctx context.Context, offset, limit int, includeArchived bool) ([]*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) ([]*models.Collection, error) {
+		return repo.List(ctx, offset, limit, includeArchived)
+	})
+}
+
+func (r *ResilientCollectionRepository) Update(ctx context.Context, collection *models.Collection) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.CollectionRepository.Update(ctx, collection) })
+}
+
+func (r *ResilientCollectionRepository) Delete(ctx context.Context, id int64) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.CollectionRepository.Delete(ctx, id) })
+}
+
+func (r *ResilientCollectionRepository) SetArchived(ctx context.Context, ids []int64, archived bool) (int, error) {
+	var changed int
+	err := r.write(ctx, func(ctx context.Context) error {
+		var err error
+		changed, err = r.CollectionRepository.SetArchived(ctx, ids, archived)
+		return err
+	})
+	return changed, err
+}
+
+func (r *ResilientCollectionRepository) ListArchivedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) ([]*models.Collection, error) {
+		return repo.ListArchivedBefore(ctx, before, limit)
+	})
+}
+
+func (r *ResilientCollectionRepository) Count(ctx context.Context, includeArchived bool) (int, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (int, error) {
+		return repo.Count(ctx, includeArchived)
+	})
+}
+
+func (r *ResilientCollectionRepository) EstimateCount(ctx context.Context) (int, error) {
+	return read(ctx, r.guard, r.CollectionRepository, interfaces.CollectionRepository.EstimateCount)
+}
+
+func (r *ResilientCollectionRepository) ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) ([]*models.Collection, error) {
+		return repo.ListByOwnership(ctx, filter, offset, limit)
+	})
+}
+
+func (r *ResilientCollectionRepository) IDAvailable(ctx context.Context, id int64) (bool, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (bool, error) {
+		return repo.IDAvailable(ctx, id)
+	})
+}
+
+func (r *ResilientCollectionRepository) ListWithLegacyItems(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) ([]int64, error) {
+		return repo.ListWithLegacyItems(ctx, afterID, limit)
+	})
+}
+
+func (r *ResilientCollectionRepository) CountWithLegacyItems(ctx context.Context, afterID int64) (int, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (int, error) {
+		return repo.CountWithLegacyItems(ctx, afterID)
+	})
+}
+
+func (r *ResilientCollectionRepository) LockLegacyItems(ctx context.Context, id int64) ([]models.PostmanItem, error) {
+	var items []models.PostmanItem
+	err := r.write(ctx, func(ctx context.Context) error {
+		var err error
+		items, err = r.CollectionRepository.LockLegacyItems(ctx, id)
+		return err
+	})
+	return items, err
+}
+
+func (r *ResilientCollectionRepository) ClearItems(ctx context.Context, id int64) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.CollectionRepository.ClearItems(ctx, id) })
+}
+
+func (r *ResilientCollectionRepository) ExistsByMetadata(ctx context.Context, key, value string) (bool, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (bool, error) {
+		return repo.ExistsByMetadata(ctx, key, value)
+	})
+}
+
+func (r *ResilientCollectionRepository) FindByMetadata(ctx context.Context, key, value string) (*models.Collection, error) {
+	return read(ctx, r.guard, r.CollectionRepository, func(repo interfaces.CollectionRepository, ctx context.Context) (*models.Collection, error) {
+		return repo.FindByMetadata(ctx, key, value)
+	})
+}
+
+// ResilientRequestRepository wraps a RequestRepository with retries and a circuit breaker
+type ResilientRequestRepository struct {
+	interfaces.RequestRepository
+	guard[interfaces.RequestRepository]
+}
+
+// NewResilientRequestRepository wraps repo with retries and a circuit breaker; replica may be nil
+func NewResilientRequestRepository(repo, replica interfaces.RequestRepository, breaker *resilience.Breaker, retry resilience.RetryPolicy) interfaces.RequestRepository {
+	return &ResilientRequestRepository{
+		RequestRepository: repo,
+		guard:             newGuard(breaker, retry, replica, replica != nil),
+	}
+}
+
+func (r *ResilientRequestRepository) WithTx(tx bun.Tx) interfaces.RequestRepository {
+	return &ResilientRequestRepository{RequestRepository: r.RequestRepository.WithTx(tx), guard: r.inTx()}
+}
+
+func (r *ResilientRequestRepository) Create(ctx context.Context, request *models.Request) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.RequestRepository.Create(ctx, request) })
+}
+
+func (r *ResilientRequestRepository) GetByID(ctx context.Context, id int64) (*models.Request, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) (*models.Request, error) {
+		return repo.GetByID(ctx, id)
+	})
+}
+
+func (r *ResilientRequestRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.Request, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) ([]*models.Request, error) {
+		return repo.GetByIDs(ctx, ids)
+	})
+}
+
+func (r *ResilientRequestRepository) List(ctx context.Context, offset, limit int) ([]*models.Request, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) ([]*models.Request, error) {
+		return repo.List(ctx, offset, limit)
+	})
+}
+
+func (r *ResilientRequestRepository) ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Request, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) ([]*models.Request, error) {
+		return repo.ListByCollectionID(ctx, collectionID, offset, limit)
+	})
+}
+
+func (r *ResilientRequestRepository) ListDeprecated(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Request, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) ([]*models.Request, error) {
+		return repo.ListDeprecated(ctx, collectionID, offset, limit)
+	})
+}
+
+func (r *ResilientRequestRepository) Update(ctx context.Context, request *models.Request) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.RequestRepository.Update(ctx, request) })
+}
+
+func (r *ResilientRequestRepository) Delete(ctx context.Context, id int64) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.RequestRepository.Delete(ctx, id) })
+}
+
+func (r *ResilientRequestRepository) DeleteByCollectionID(ctx context.Context, collectionID int64) error {
+	return r.write(ctx, func(ctx context.Context) error {
+		return r.RequestRepository.DeleteByCollectionID(ctx, collectionID)
+	})
+}
+
+func (r *ResilientRequestRepository) DeleteByFolderIDs(ctx context.Context, collectionID int64, folderIDs []int64) ([]int64, error) {
+	var deleted []int64
+	err := r.write(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, err = r.RequestRepository.DeleteByFolderIDs(ctx, collectionID, folderIDs)
+		return err
+	})
+
+	return deleted, err
+}
+
+func (r *ResilientRequestRepository) SetFolderPath(ctx context.Context, folderID int64, path string) (int, error) {
+	var updated int
+	err := r.write(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = r.RequestRepository.SetFolderPath(ctx, folderID, path)
+		return err
+	})
+
+	return updated, err
+}
+
+func (r *ResilientRequestRepository) NextPositions(ctx context.Context, collectionID int64) (map[int64]int, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) (map[int64]int, error) {
+		return repo.NextPositions(ctx, collectionID)
+	})
+}
+
+func (r *ResilientRequestRepository) Count(ctx context.Context) (int, error) {
+	return read(ctx, r.guard, r.RequestRepository, interfaces.RequestRepository.Count)
+}
+
+func (r *ResilientRequestRepository) CountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) (int, error) {
+		return repo.CountByCollectionID(ctx, collectionID)
+	})
+}
+
+func (r *ResilientRequestRepository) EstimateCount(ctx context.Context) (int, error) {
+	return read(ctx, r.guard, r.RequestRepository, interfaces.RequestRepository.EstimateCount)
+}
+
+func (r *ResilientRequestRepository) EstimateCountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	return read(ctx, r.guard, r.RequestRepository, func(repo interfaces.RequestRepository, ctx context.Context) (int, error) {
+		return repo.EstimateCountByCollectionID(ctx, collectionID)
+	})
+}
+
+// ResilientOpenAPIRepository wraps an OpenAPIRepository with retries and a circuit breaker
+type ResilientOpenAPIRepository struct {
+	interfaces.OpenAPIRepository
+	guard[interfaces.OpenAPIRepository]
+}
+
+// NewResilientOpenAPIRepository wraps repo with retries and a circuit breaker; replica may be nil
+func NewResilientOpenAPIRepository(repo, replica interfaces.OpenAPIRepository, breaker *resilience.Breaker, retry resilience.RetryPolicy) interfaces.OpenAPIRepository {
+	return &ResilientOpenAPIRepository{
+		OpenAPIRepository: repo,
+		guard:             newGuard(breaker, retry, replica, replica != nil),
+	}
+}
+
+func (r *ResilientOpenAPIRepository) WithTx(tx bun.Tx) interfaces.OpenAPIRepository {
+	return &ResilientOpenAPIRepository{OpenAPIRepository: r.OpenAPIRepository.WithTx(tx), guard: r.inTx()}
+}
+
+func (r *ResilientOpenAPIRepository) Create(ctx context.Context, spec *models.OpenAPISpec) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.OpenAPIRepository.Create(ctx, spec) })
+}
+
+func (r *ResilientOpenAPIRepository) GetByID(ctx context.Context, id int64) (*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) (*models.OpenAPISpec, error) {
+		return repo.GetByID(ctx, id)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) ([]*models.OpenAPISpec, error) {
+		return repo.GetByIDs(ctx, ids)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) GetByTitle(ctx context.Context, title string) (*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) (*models.OpenAPISpec, error) {
+		return repo.GetByTitle(ctx, title)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) List(ctx context.Context, offset, limit int) ([]*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) ([]*models.OpenAPISpec, error) {
+		return repo.List(ctx, offset, limit)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) Update(ctx context.Context, spec *models.OpenAPISpec) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.OpenAPIRepository.Update(ctx, spec) })
+}
+
+func (r *ResilientOpenAPIRepository) Delete(ctx context.Context, id int64) error {
+	return r.write(ctx, func(ctx context.Context) error { return r.OpenAPIRepository.Delete(ctx, id) })
+}
+
+func (r *ResilientOpenAPIRepository) Count(ctx context.Context) (int, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, interfaces.OpenAPIRepository.Count)
+}
+
+func (r *ResilientOpenAPIRepository) EstimateCount(ctx context.Context) (int, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, interfaces.OpenAPIRepository.EstimateCount)
+}
+
+func (r *ResilientOpenAPIRepository) Search(ctx context.Context, query string, offset, limit int) ([]*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) ([]*models.OpenAPISpec, error) {
+		return repo.Search(ctx, query, offset, limit)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) CountSearch(ctx context.Context, query string) (int, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) (int, error) {
+		return repo.CountSearch(ctx, query)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) ListByOwnership(ctx context.Context, filter models.OwnershipFilter, offset, limit int) ([]*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) ([]*models.OpenAPISpec, error) {
+		return repo.ListByOwnership(ctx, filter, offset, limit)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) ExistsByMetadata(ctx context.Context, key, value string) (bool, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) (bool, error) {
+		return repo.ExistsByMetadata(ctx, key, value)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) FindByMetadata(ctx context.Context, key, value string) (*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) (*models.OpenAPISpec, error) {
+		return repo.FindByMetadata(ctx, key, value)
+	})
+}
+
+func (r *ResilientOpenAPIRepository) ListSupersededBefore(ctx context.Context, before time.Time, limit int) ([]*models.OpenAPISpec, error) {
+	return read(ctx, r.guard, r.OpenAPIRepository, func(repo interfaces.OpenAPIRepository, ctx context.Context) ([]*models.OpenAPISpec, error) {
+		return repo.ListSupersededBefore(ctx, before, limit)
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ResponseSnapshotRepository handles database operations for the response snapshots of requests
+type ResponseSnapshotRepository struct {
+	db *bun.DB
+}
+
+// NewResponseSnapshotRepository creates a new response snapshot repository
+func NewResponseSnapshotRepository(db *bun.DB) interfaces.ResponseSnapshotRepository {
+	return &ResponseSnapshotRepository{db: db}
+}
+
+// GetByRequestID retrieves the snapshot of a request
+func (r *ResponseSnapshotRepository) GetByRequestID(ctx context.Context, requestID int64) (*models.ResponseSnapshot, error) {
+	snapshot := &models.ResponseSnapshot{}
+	err := r.db.NewSelect().
+		Model(snapshot).
+		Where("request_id = ?", requestID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "response snapshot", "failed to get response snapshot")
+	}
+
+	return snapshot, nil
+}
+
+// Save records the snapshot of a request, replacing any previous one
+func (r *ResponseSnapshotRepository) Save(ctx context.Context, snapshot *models.ResponseSnapshot) error {
+	now := time.Now()
+	snapshot.CreatedAt, snapshot.UpdatedAt = now, now
+
+	_, err := r.db.NewInsert().
+		Model(snapshot).
+		On("CONFLICT (request_id) DO UPDATE").
+		Set("run_id = EXCLUDED.run_id").
+		Set("status_code = EXCLUDED.status_code").
+		Set("body = EXCLUDED.body").
+		Set("body_base64 = EXCLUDED.body_base64").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "response snapshot", "failed to save response snapshot")
+	}
+
+	return nil
+}
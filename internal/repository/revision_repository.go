@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// CollectionRevisionRepository handles database operations for collection revisions
+type CollectionRevisionRepository struct {
+	db bun.IDB
+}
+
+// NewCollectionRevisionRepository creates a new collection revision repository
+func NewCollectionRevisionRepository(db *bun.DB) interfaces.CollectionRevisionRepository {
+	return &CollectionRevisionRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *CollectionRevisionRepository) WithTx(tx bun.Tx) interfaces.CollectionRevisionRepository {
+	return &CollectionRevisionRepository{db: tx}
+}
+
+// Create adds a revision, numbering it after the last one of its collection
+func (r *CollectionRevisionRepository) Create(ctx context.Context, revision *models.CollectionRevision) error {
+	revision.CreatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(revision).
+		Value("revision", "(SELECT COALESCE(MAX(revision), 0) + 1 FROM collection_revisions WHERE collection_id = ?)", revision.CollectionID).
+		Returning("id, revision").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "collection revision", "failed to create collection revision")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a revision with its snapshot by ID
+func (r *CollectionRevisionRepository) GetByID(ctx context.Context, id int64) (*models.CollectionRevision, error) {
+	revision := &models.CollectionRevision{}
+	err := r.db.NewSelect().
+		Model(revision).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection revision", "failed to get collection revision by ID")
+	}
+
+	return revision, nil
+}
+
+// ListByCollectionID returns the revisions of a collection without their
+// snapshots, with pagination, newest first
+func (r *CollectionRevisionRepository) ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.CollectionRevision, error) {
+	var revisions []*models.CollectionRevision
+	err := r.db.NewSelect().
+		Model(&revisions).
+		ExcludeColumn("snapshot").
+		Where("collection_id = ?", collectionID).
+		OrderExpr("revision DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection revision", "failed to list collection revisions")
+	}
+
+	return revisions, nil
+}
+
+// CountByCollectionID returns the number of revisions of a collection
+func (r *CollectionRevisionRepository) CountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.CollectionRevision)(nil)).
+		Where("collection_id = ?", collectionID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "collection revision", "failed to count collection revisions")
+	}
+
+	return count, nil
+}
+
+// ListSince returns the revisions of a collection recorded in [since, until)
+// without their snapshots, oldest first
+func (r *CollectionRevisionRepository) ListSince(ctx context.Context, collectionID int64, since, until time.Time) ([]*models.CollectionRevision, error) {
+	var revisions []*models.CollectionRevision
+	err := r.db.NewSelect().
+		Model(&revisions).
+		ExcludeColumn("snapshot").
+		Where("collection_id = ?", collectionID).
+		Where("created_at >= ?", since).
+		Where("created_at < ?", until).
+		OrderExpr("revision").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "collection revision", "failed to list collection revisions")
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RunRepository handles database operations for collection runs
+type RunRepository struct {
+	db *bun.DB
+}
+
+// NewRunRepository creates a new run repository
+func NewRunRepository(db *bun.DB) interfaces.RunRepository {
+	return &RunRepository{db: db}
+}
+
+// Create adds a new run to the database
+func (r *RunRepository) Create(ctx context.Context, run *models.Run) error {
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now()
+	}
+
+	_, err := r.db.NewInsert().
+		Model(run).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "run", "failed to create run")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a run by its ID
+func (r *RunRepository) GetByID(ctx context.Context, id int64) (*models.Run, error) {
+	run := &models.Run{}
+	err := r.db.NewSelect().
+		Model(run).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "run", "failed to get run by ID")
+	}
+
+	return run, nil
+}
+
+// Update modifies an existing run
+func (r *RunRepository) Update(ctx context.Context, run *models.Run) error {
+	_, err := r.db.NewUpdate().
+		Model(run).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "run", "failed to update run")
+	}
+
+	return nil
+}
+
+// ListByCollectionID returns the runs of a collection with pagination, newest first
+func (r *RunRepository) ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.Run, error) {
+	var runs []*models.Run
+	err := r.db.NewSelect().
+		Model(&runs).
+		Where("collection_id = ?", collectionID).
+		OrderExpr("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "run", "failed to list runs")
+	}
+
+	return runs, nil
+}
+
+// CountByCollectionID returns the number of runs of a collection
+func (r *RunRepository) CountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.Run)(nil)).
+		Where("collection_id = ?", collectionID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "run", "failed to count runs")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SecurityFindingRepository handles database operations for security findings
+type SecurityFindingRepository struct {
+	db *bun.DB
+}
+
+// NewSecurityFindingRepository creates a new security finding repository
+func NewSecurityFindingRepository(db *bun.DB) interfaces.SecurityFindingRepository {
+	return &SecurityFindingRepository{db: db}
+}
+
+// CreateBatch adds findings to the database in a single statement
+func (r *SecurityFindingRepository) CreateBatch(ctx context.Context, findings []*models.SecurityFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, finding := range findings {
+		finding.CreatedAt = now
+	}
+
+	_, err := r.db.NewInsert().
+		Model(&findings).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "security finding", "failed to create security findings")
+	}
+
+	return nil
+}
+
+// ListByCollectionID returns the findings of a collection with pagination, newest first
+func (r *SecurityFindingRepository) ListByCollectionID(ctx context.Context, collectionID int64, offset, limit int) ([]*models.SecurityFinding, error) {
+	var findings []*models.SecurityFinding
+	err := r.db.NewSelect().
+		Model(&findings).
+		Where("collection_id = ?", collectionID).
+		OrderExpr("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "security finding", "failed to list security findings")
+	}
+
+	return findings, nil
+}
+
+// CountByCollectionID returns the number of findings of a collection
+func (r *SecurityFindingRepository) CountByCollectionID(ctx context.Context, collectionID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.SecurityFinding)(nil)).
+		Where("collection_id = ?", collectionID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "security finding", "failed to count security findings")
+	}
+
+	return count, nil
+}
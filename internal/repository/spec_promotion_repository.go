@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecPromotionRepository handles database operations for the promotions of
+// OpenAPI specs to labels; promotions are never changed, and the latest one
+// to a label is the content of record there
+type SpecPromotionRepository struct {
+	db bun.IDB
+}
+
+// NewSpecPromotionRepository creates a new spec promotion repository
+func NewSpecPromotionRepository(db *bun.DB) interfaces.SpecPromotionRepository {
+	return &SpecPromotionRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *SpecPromotionRepository) WithTx(tx bun.Tx) interfaces.SpecPromotionRepository {
+	return &SpecPromotionRepository{db: tx}
+}
+
+// Create records a promotion
+func (r *SpecPromotionRepository) Create(ctx context.Context, promotion *models.SpecPromotion) error {
+	promotion.CreatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(promotion).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "spec promotion", "failed to record spec promotion")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a promotion with its content by ID
+func (r *SpecPromotionRepository) GetByID(ctx context.Context, id int64) (*models.SpecPromotion, error) {
+	promotion := &models.SpecPromotion{}
+	err := r.db.NewSelect().
+		Model(promotion).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec promotion", "failed to get spec promotion")
+	}
+
+	return promotion, nil
+}
+
+// Current retrieves the latest promotion of a spec to label with its content
+func (r *SpecPromotionRepository) Current(ctx context.Context, specID int64, label string) (*models.SpecPromotion, error) {
+	promotion := &models.SpecPromotion{}
+	err := r.db.NewSelect().
+		Model(promotion).
+		Where("spec_id = ?", specID).
+		Where("label = ?", label).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec label", "failed to get spec label")
+	}
+
+	return promotion, nil
+}
+
+// ListCurrent returns the latest promotion of a spec to each label it was
+// promoted to, with their content
+func (r *SpecPromotionRepository) ListCurrent(ctx context.Context, specID int64) ([]*models.SpecPromotion, error) {
+	promotions := []*models.SpecPromotion{}
+	err := r.db.NewSelect().
+		Model(&promotions).
+		Where("id IN (SELECT MAX(id) FROM openapi_spec_promotions WHERE spec_id = ? GROUP BY label)", specID).
+		OrderExpr("label").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec label", "failed to list spec labels")
+	}
+
+	return promotions, nil
+}
+
+// ListBySpecID returns the promotions of a spec without their content, with
+// pagination, newest first
+func (r *SpecPromotionRepository) ListBySpecID(ctx context.Context, specID int64, offset, limit int) ([]*models.SpecPromotion, error) {
+	var promotions []*models.SpecPromotion
+	err := r.db.NewSelect().
+		Model(&promotions).
+		ExcludeColumn("content").
+		Where("spec_id = ?", specID).
+		OrderExpr("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec promotion", "failed to list spec promotions")
+	}
+
+	return promotions, nil
+}
+
+// CountBySpecID returns the number of promotions of a spec
+func (r *SpecPromotionRepository) CountBySpecID(ctx context.Context, specID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.SpecPromotion)(nil)).
+		Where("spec_id = ?", specID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "spec promotion", "failed to count spec promotions")
+	}
+
+	return count, nil
+}
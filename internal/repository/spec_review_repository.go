@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecReviewRepository handles database operations for the review decisions
+// of OpenAPI specs; the status of a spec is the outcome of its latest decision
+type SpecReviewRepository struct {
+	db bun.IDB
+}
+
+// NewSpecReviewRepository creates a new spec review repository
+func NewSpecReviewRepository(db *bun.DB) interfaces.SpecReviewRepository {
+	return &SpecReviewRepository{db: db}
+}
+
+// WithTx returns a repository running its queries in tx
+func (r *SpecReviewRepository) WithTx(tx bun.Tx) interfaces.SpecReviewRepository {
+	return &SpecReviewRepository{db: tx}
+}
+
+// LockSpec locks a spec until the transaction ends, serializing the decisions
+// taken on it
+func (r *SpecReviewRepository) LockSpec(ctx context.Context, specID int64) error {
+	var id int64
+	err := r.db.NewSelect().
+		Model((*models.OpenAPISpec)(nil)).
+		Column("id").
+		Where("id = ?", specID).
+		Apply(lockRows("UPDATE")).
+		Scan(ctx, &id)
+
+	if err != nil {
+		return dbError(err, "OpenAPI specification", "failed to lock OpenAPI spec")
+	}
+
+	return nil
+}
+
+// Status returns the review status of a spec, draft when it has no decisions
+func (r *SpecReviewRepository) Status(ctx context.Context, specID int64) (string, error) {
+	var statuses []string
+	err := r.db.NewSelect().
+		Model((*models.SpecReviewDecision)(nil)).
+		Column("to_status").
+		Where("spec_id = ?", specID).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx, &statuses)
+
+	if err != nil {
+		return "", dbError(err, "spec review", "failed to get spec review status")
+	}
+
+	if len(statuses) == 0 {
+		return models.ReviewDraft, nil
+	}
+
+	return statuses[0], nil
+}
+
+// Approvers returns the reviewers who approved a spec since it was last submitted
+func (r *SpecReviewRepository) Approvers(ctx context.Context, specID int64) ([]string, error) {
+	approvers := []string{}
+	err := r.db.NewSelect().
+		Model((*models.SpecReviewDecision)(nil)).
+		ColumnExpr("DISTINCT reviewer").
+		Where("spec_id = ?", specID).
+		Where("decision = ?", models.DecisionApprove).
+		Where("id > (SELECT COALESCE(MAX(id), 0) FROM openapi_spec_review_decisions WHERE spec_id = ? AND decision = ?)", specID, models.DecisionSubmit).
+		OrderExpr("reviewer").
+		Scan(ctx, &approvers)
+
+	if err != nil {
+		return nil, dbError(err, "spec review", "failed to list spec approvers")
+	}
+
+	return approvers, nil
+}
+
+// Create records a review decision
+func (r *SpecReviewRepository) Create(ctx context.Context, decision *models.SpecReviewDecision) error {
+	decision.CreatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(decision).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "spec review decision", "failed to record spec review decision")
+	}
+
+	return nil
+}
+
+// ListBySpecID returns the review decisions of a spec, oldest first
+func (r *SpecReviewRepository) ListBySpecID(ctx context.Context, specID int64) ([]*models.SpecReviewDecision, error) {
+	decisions := []*models.SpecReviewDecision{}
+	err := r.db.NewSelect().
+		Model(&decisions).
+		Where("spec_id = ?", specID).
+		OrderExpr("id").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec review decision", "failed to list spec review decisions")
+	}
+
+	return decisions, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecRevisionRepository handles database operations for OpenAPI spec
+// revisions, which the OpenAPI repository records as specs are updated
+type SpecRevisionRepository struct {
+	db *bun.DB
+}
+
+// NewSpecRevisionRepository creates a new spec revision repository
+func NewSpecRevisionRepository(db *bun.DB) interfaces.SpecRevisionRepository {
+	return &SpecRevisionRepository{db: db}
+}
+
+// GetByRevision retrieves a revision of a spec with its content by number
+func (r *SpecRevisionRepository) GetByRevision(ctx context.Context, specID int64, revision int) (*models.OpenAPISpecRevision, error) {
+	rev := &models.OpenAPISpecRevision{}
+	err := r.db.NewSelect().
+		Model(rev).
+		Where("spec_id = ?", specID).
+		Where("revision = ?", revision).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification revision", "failed to get OpenAPI spec revision")
+	}
+
+	return rev, nil
+}
+
+// ListBySpecID returns the revisions of a spec without their content, with
+// pagination, newest first
+func (r *SpecRevisionRepository) ListBySpecID(ctx context.Context, specID int64, offset, limit int) ([]*models.OpenAPISpecRevision, error) {
+	var revisions []*models.OpenAPISpecRevision
+	err := r.db.NewSelect().
+		Model(&revisions).
+		ExcludeColumn("content").
+		Where("spec_id = ?", specID).
+		OrderExpr("revision DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification revision", "failed to list OpenAPI spec revisions")
+	}
+
+	return revisions, nil
+}
+
+// CountBySpecID returns the number of revisions of a spec
+func (r *SpecRevisionRepository) CountBySpecID(ctx context.Context, specID int64) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.OpenAPISpecRevision)(nil)).
+		Where("spec_id = ?", specID).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "OpenAPI specification revision", "failed to count OpenAPI spec revisions")
+	}
+
+	return count, nil
+}
+
+// ListSince returns the revisions of a spec recorded in [since, until)
+// without their content, oldest first
+func (r *SpecRevisionRepository) ListSince(ctx context.Context, specID int64, since, until time.Time) ([]*models.OpenAPISpecRevision, error) {
+	var revisions []*models.OpenAPISpecRevision
+	err := r.db.NewSelect().
+		Model(&revisions).
+		ExcludeColumn("content").
+		Where("spec_id = ?", specID).
+		Where("created_at >= ?", since).
+		Where("created_at < ?", until).
+		OrderExpr("revision").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "OpenAPI specification revision", "failed to list OpenAPI spec revisions")
+	}
+
+	return revisions, nil
+}
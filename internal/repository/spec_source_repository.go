@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecSourceRepository handles database operations for spec sources
+type SpecSourceRepository struct {
+	db *bun.DB
+}
+
+// NewSpecSourceRepository creates a new spec source repository
+func NewSpecSourceRepository(db *bun.DB) interfaces.SpecSourceRepository {
+	return &SpecSourceRepository{db: db}
+}
+
+// Create adds a new spec source to the database
+func (r *SpecSourceRepository) Create(ctx context.Context, source *models.SpecSource) error {
+	source.CreatedAt = time.Now()
+	source.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().
+		Model(source).
+		Returning("id").
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "spec source", "failed to create spec source")
+	}
+
+	return nil
+}
+
+// GetByID retrieves a spec source by its ID
+func (r *SpecSourceRepository) GetByID(ctx context.Context, id int64) (*models.SpecSource, error) {
+	source := &models.SpecSource{}
+	err := r.db.NewSelect().
+		Model(source).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec source", "failed to get spec source by ID")
+	}
+
+	return source, nil
+}
+
+// List returns all spec sources with pagination
+func (r *SpecSourceRepository) List(ctx context.Context, offset, limit int) ([]*models.SpecSource, error) {
+	var sources []*models.SpecSource
+	err := r.db.NewSelect().
+		Model(&sources).
+		OrderExpr("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec source", "failed to list spec sources")
+	}
+
+	return sources, nil
+}
+
+// ListDue returns enabled spec sources whose poll interval has elapsed
+func (r *SpecSourceRepository) ListDue(ctx context.Context, now time.Time) ([]*models.SpecSource, error) {
+	due := "last_checked_at + poll_interval_seconds * INTERVAL '1 second' <= ?"
+	if isSQLite(r.db) {
+		due = "unixepoch(last_checked_at) + poll_interval_seconds <= unixepoch(?)"
+	}
+
+	var sources []*models.SpecSource
+	err := r.db.NewSelect().
+		Model(&sources).
+		Where("enabled = TRUE").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("last_checked_at IS NULL").
+				WhereOr(due, now)
+		}).
+		OrderExpr("last_checked_at ASC NULLS FIRST").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, dbError(err, "spec source", "failed to list due spec sources")
+	}
+
+	return sources, nil
+}
+
+// Update modifies an existing spec source
+func (r *SpecSourceRepository) Update(ctx context.Context, source *models.SpecSource) error {
+	source.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(source).
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "spec source", "failed to update spec source")
+	}
+
+	return nil
+}
+
+// Delete removes a spec source from the database
+func (r *SpecSourceRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.SpecSource)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	if err != nil {
+		return dbError(err, "spec source", "failed to delete spec source")
+	}
+
+	return nil
+}
+
+// Count returns the total number of spec sources
+func (r *SpecSourceRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.SpecSource)(nil)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, dbError(err, "spec source", "failed to count spec sources")
+	}
+
+	return count, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// collectionUsageSQL sizes every collection with its requests, their
+// examples and its runs, given the size of a collection, request, example
+// and run row in turn
+const collectionUsageSQL = `
+SELECT
+	c.id AS collection_id,
+	c.name,
+	COALESCE(c.metadata -> 'ownership' ->> 'team', '') AS team,
+	c.archived,
+	COALESCE(r.requests, 0) AS requests,
+	COALESCE(ru.runs, 0) AS runs,
+	%[1]s AS collection_bytes,
+	COALESCE(r.request_bytes, 0) AS request_bytes,
+	COALESCE(r.response_bytes, 0) AS response_bytes,
+	COALESCE(ru.run_bytes, 0) AS run_bytes,
+	%[1]s + COALESCE(r.request_bytes, 0) + COALESCE(r.response_bytes, 0) + COALESCE(ru.run_bytes, 0) AS total_bytes
+FROM collections c
+LEFT JOIN (
+	SELECT requests.collection_id, COUNT(*) AS requests,
+		SUM(%[2]s) AS request_bytes,
+		SUM(COALESCE(ex.example_bytes, 0)) AS response_bytes
+	FROM requests
+	LEFT JOIN (
+		SELECT request_id, SUM(%[3]s) AS example_bytes
+		FROM request_examples
+		GROUP BY request_id
+	) ex ON ex.request_id = requests.id
+	GROUP BY requests.collection_id
+) r ON r.collection_id = c.id
+LEFT JOIN (
+	SELECT collection_id, COUNT(*) AS runs, SUM(%[4]s) AS run_bytes
+	FROM runs
+	GROUP BY collection_id
+) ru ON ru.collection_id = c.id`
+
+// StorageRepository reports how much space the stored data takes up
+type StorageRepository struct {
+	db *bun.DB
+}
+
+// NewStorageRepository creates a new storage repository
+func NewStorageRepository(db *bun.DB) interfaces.StorageRepository {
+	return &StorageRepository{db: db}
+}
+
+// collectionUsageQuery returns the query sizing every collection.
+// pg_column_size reports the stored size of a row after TOAST compression,
+// which is what the collection actually costs on disk; SQLite compresses
+// nothing, so there a row takes the bytes of its values.
+func (r *StorageRepository) collectionUsageQuery() string {
+	rowSize := func(table string, model any) string {
+		return fmt.Sprintf("pg_column_size(%s.*)", table)
+	}
+	if isSQLite(r.db) {
+		rowSize = func(table string, model any) string {
+			var sizes []string
+			for _, field := range r.db.Table(reflect.TypeOf(model)).Fields {
+				sizes = append(sizes, fmt.Sprintf("COALESCE(length(CAST(%s.%s AS BLOB)), 0)", table, field.SQLName))
+			}
+			return "(" + strings.Join(sizes, " + ") + ")"
+		}
+	}
+
+	return fmt.Sprintf(collectionUsageSQL,
+		rowSize("c", (*models.Collection)(nil)),
+		rowSize("requests", (*models.Request)(nil)),
+		rowSize("request_examples", (*models.Example)(nil)),
+		rowSize("runs", (*models.Run)(nil)))
+}
+
+// TableUsage returns the size of every table of the schema, largest first
+func (r *StorageRepository) TableUsage(ctx context.Context) ([]models.TableStorage, error) {
+	if isSQLite(r.db) {
+		return r.sqliteTableUsage(ctx)
+	}
+
+	var tables []models.TableStorage
+	err := r.db.NewRaw(`
+		SELECT relname AS table_name, n_live_tup AS rows, pg_total_relation_size(relid) AS total_bytes
+		FROM pg_stat_user_tables
+		WHERE schemaname = current_schema()
+		ORDER BY total_bytes DESC, table_name`).
+		Scan(ctx, &tables)
+
+	if err != nil {
+		return nil, dbError(err, "table", "failed to get table sizes")
+	}
+
+	return tables, nil
+}
+
+// sqliteTableUsage sizes the tables of a SQLite database from the pages
+// dbstat counts for them and their indexes; their rows are counted
+func (r *StorageRepository) sqliteTableUsage(ctx context.Context) ([]models.TableStorage, error) {
+	var tables []models.TableStorage
+	err := r.db.NewRaw(`
+		SELECT s.tbl_name AS table_name, SUM(d.pgsize) AS total_bytes
+		FROM dbstat d
+		JOIN sqlite_schema s ON s.name = d.name
+		WHERE s.tbl_name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		GROUP BY s.tbl_name
+		ORDER BY total_bytes DESC, table_name`).
+		Scan(ctx, &tables)
+
+	if err != nil {
+		return nil, dbError(err, "table", "failed to get table sizes")
+	}
+
+	for i := range tables {
+		rows, err := estimateTableRows(ctx, r.db, tables[i].Table)
+		if err != nil {
+			return nil, err
+		}
+		tables[i].Rows = int64(rows)
+	}
+
+	return tables, nil
+}
+
+// TeamUsage sums collection usage by owning team, largest first
+func (r *StorageRepository) TeamUsage(ctx context.Context) ([]models.TeamStorage, error) {
+	var teams []models.TeamStorage
+	err := r.db.NewRaw(`
+		SELECT team, COUNT(*) AS collections, CAST(SUM(requests) AS BIGINT) AS requests,
+			CAST(SUM(runs) AS BIGINT) AS runs, CAST(SUM(total_bytes) AS BIGINT) AS total_bytes
+		FROM (`+r.collectionUsageQuery()+`) collection_usage
+		GROUP BY team
+		ORDER BY total_bytes DESC, team`).
+		Scan(ctx, &teams)
+
+	if err != nil {
+		return nil, dbError(err, "team", "failed to get team storage usage")
+	}
+
+	return teams, nil
+}
+
+// CollectionUsage returns the limit largest collections, largest first
+func (r *StorageRepository) CollectionUsage(ctx context.Context, limit int) ([]models.CollectionStorage, error) {
+	var collections []models.CollectionStorage
+	err := r.db.NewRaw(r.collectionUsageQuery()+`
+		ORDER BY total_bytes DESC, c.id
+		LIMIT ?`, limit).
+		Scan(ctx, &collections)
+
+	if err != nil {
+		return nil, dbError(err, "collection", "failed to get collection storage usage")
+	}
+
+	return collections, nil
+}
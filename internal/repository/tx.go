@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// Transactor runs work in a transaction of the primary database
+type Transactor struct {
+	db *bun.DB
+}
+
+// NewTransactor creates a transactor over db
+func NewTransactor(db *bun.DB) interfaces.Transactor {
+	return &Transactor{db: db}
+}
+
+// RunInTx runs fn in a transaction, committing it when fn succeeds and
+// rolling it back otherwise. Work deferred within fn, such as marking catalog
+// files to write, only runs once the transaction commits.
+func (t *Transactor) RunInTx(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error {
+	pending := &afterCommit{}
+	if err := t.db.RunInTx(context.WithValue(ctx, afterCommitKey{}, pending), nil, fn); err != nil {
+		return err
+	}
+
+	pending.run()
+	return nil
+}
+
+type afterCommitKey struct{}
+
+// afterCommit collects the work deferred until a transaction commits
+type afterCommit struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (a *afterCommit) add(fn func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.fns = append(a.fns, fn)
+}
+
+func (a *afterCommit) run() {
+	a.mu.Lock()
+	fns := a.fns
+	a.fns = nil
+	a.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// deferUntilCommit queues fn to run once the transaction of ctx commits, or
+// runs it at once when ctx carries no transaction
+func deferUntilCommit(ctx context.Context, fn func()) {
+	if pending, ok := ctx.Value(afterCommitKey{}).(*afterCommit); ok {
+		pending.add(fn)
+		return
+	}
+	fn()
+}
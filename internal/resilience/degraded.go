@@ -0,0 +1,27 @@
+package resilience
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type degradedKey struct{}
+
+// WithDegradedMarker returns a context in which MarkDegraded can record that
+// a read was served from a fallback instead of the primary database
+func WithDegradedMarker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, degradedKey{}, new(atomic.Bool))
+}
+
+// MarkDegraded records a fallback read on ctx; it is a no-op without a marker
+func MarkDegraded(ctx context.Context) {
+	if marker, ok := ctx.Value(degradedKey{}).(*atomic.Bool); ok {
+		marker.Store(true)
+	}
+}
+
+// IsDegraded reports whether any read on ctx was served from a fallback
+func IsDegraded(ctx context.Context) bool {
+	marker, ok := ctx.Value(degradedKey{}).(*atomic.Bool)
+	return ok && marker.Load()
+}
@@ -0,0 +1,281 @@
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	errTransient = &pgconn.PgError{Code: "40001"}
+	errPermanent = &pgconn.PgError{Code: "23505"}
+)
+
+// breakerStep is a call made on a breaker and the outcome expected of it
+type breakerStep struct {
+	// allow calls Allow and expects allowed; otherwise err is recorded
+	allow   bool
+	allowed bool
+	err     error
+	// state is the state expected after the step
+	state string
+}
+
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		cooldown  time.Duration
+		steps     []breakerStep
+	}{
+		{
+			name:      "stays closed below the threshold",
+			threshold: 3,
+			cooldown:  time.Hour,
+			steps: []breakerStep{
+				{err: errTransient, state: StateClosed},
+				{err: errTransient, state: StateClosed},
+				{allow: true, allowed: true, state: StateClosed},
+			},
+		},
+		{
+			name:      "opens at the threshold and rejects calls",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []breakerStep{
+				{err: errTransient, state: StateClosed},
+				{err: errTransient, state: StateOpen},
+				{allow: true, allowed: false, state: StateOpen},
+			},
+		},
+		{
+			name:      "a success resets the count",
+			threshold: 2,
+			cooldown:  time.Hour,
+			steps: []breakerStep{
+				{err: errTransient, state: StateClosed},
+				{err: nil, state: StateClosed},
+				{err: errTransient, state: StateClosed},
+			},
+		},
+		{
+			name:      "permanent errors are not failures",
+			threshold: 1,
+			cooldown:  time.Hour,
+			steps: []breakerStep{
+				{err: errPermanent, state: StateClosed},
+				{err: sql.ErrNoRows, state: StateClosed},
+				{allow: true, allowed: true, state: StateClosed},
+			},
+		},
+		{
+			name:      "a threshold below one is one",
+			threshold: 0,
+			cooldown:  time.Hour,
+			steps: []breakerStep{
+				{err: errTransient, state: StateOpen},
+			},
+		},
+		{
+			name:      "lets a single probe through after the cooldown",
+			threshold: 1,
+			steps: []breakerStep{
+				{err: errTransient, state: StateHalfOpen},
+				{allow: true, allowed: true, state: StateHalfOpen},
+				{allow: true, allowed: false, state: StateHalfOpen},
+			},
+		},
+		{
+			name:      "closes when the probe succeeds",
+			threshold: 1,
+			steps: []breakerStep{
+				{err: errTransient, state: StateHalfOpen},
+				{allow: true, allowed: true, state: StateHalfOpen},
+				{err: nil, state: StateClosed},
+				{allow: true, allowed: true, state: StateClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(tt.threshold, tt.cooldown)
+			if state := b.State(); state != StateClosed {
+				t.Fatalf("new breaker is %s, want %s", state, StateClosed)
+			}
+
+			for i, step := range tt.steps {
+				if step.allow {
+					if allowed := b.Allow(); allowed != step.allowed {
+						t.Fatalf("step %d: Allow() = %v, want %v", i, allowed, step.allowed)
+					}
+				} else {
+					b.Record(step.err)
+				}
+
+				if step.state == "" {
+					continue
+				}
+				if state := b.State(); state != step.state {
+					t.Fatalf("step %d: state = %s, want %s", i, state, step.state)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerReopensForCooldown(t *testing.T) {
+	b := NewBreaker(1, time.Hour)
+	b.Record(errTransient)
+
+	// Pretend the cooldown elapsed, then fail the probe
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	if !b.Allow() {
+		t.Fatal("Allow() after the cooldown = false, want a probe")
+	}
+	b.Record(errTransient)
+
+	if state := b.State(); state != StateOpen {
+		t.Errorf("state after a failed probe = %s, want %s", state, StateOpen)
+	}
+	if b.Allow() {
+		t.Error("Allow() after a failed probe = true, want false until the cooldown elapses again")
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		errs     []error
+		calls    int
+		wantErr  error
+		openWith int
+	}{
+		{
+			name:   "succeeds first time",
+			policy: policy,
+			errs:   []error{nil},
+			calls:  1,
+		},
+		{
+			name:   "retries transient failures",
+			policy: policy,
+			errs:   []error{errTransient, errTransient, nil},
+			calls:  3,
+		},
+		{
+			name:    "gives up after the last attempt",
+			policy:  policy,
+			errs:    []error{errTransient, errTransient, errTransient},
+			calls:   3,
+			wantErr: errTransient,
+		},
+		{
+			name:    "does not retry permanent errors",
+			policy:  policy,
+			errs:    []error{errPermanent},
+			calls:   1,
+			wantErr: errPermanent,
+		},
+		{
+			name:    "makes one attempt without a policy",
+			errs:    []error{errTransient},
+			calls:   1,
+			wantErr: errTransient,
+		},
+		{
+			name:     "stops once the breaker opens",
+			policy:   policy,
+			errs:     []error{errTransient, errTransient},
+			calls:    2,
+			wantErr:  ErrCircuitOpen,
+			openWith: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold := tt.openWith
+			if threshold == 0 {
+				threshold = 100
+			}
+			breaker := NewBreaker(threshold, time.Hour)
+
+			calls := 0
+			err := Do(context.Background(), breaker, tt.policy, func(ctx context.Context) error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.calls {
+				t.Errorf("Do() made %d calls, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestDoStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	breaker := NewBreaker(100, time.Hour)
+
+	calls := 0
+	err := Do(ctx, breaker, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errTransient
+	})
+
+	if !errors.Is(err, errTransient) {
+		t.Errorf("Do() = %v, want the last failure", err)
+	}
+	if calls != 1 {
+		t.Errorf("Do() made %d calls, want 1", calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"syntax error", &pgconn.PgError{Code: "42601"}, false},
+		{"wrapped deadlock", fmt.Errorf("failed to update: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"bad connection", driver.ErrBadConn, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"connection reset", errors.New("read tcp: connection reset by peer"), true},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"), true},
+		{"no rows", sql.ErrNoRows, false},
+		{"cancelled", context.Canceled, false},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
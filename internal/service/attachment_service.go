@@ -0,0 +1,376 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/storage"
+	"strings"
+)
+
+// Entries of a collection bundle archive
+const (
+	bundleCollectionFile = "collection.json"
+	bundleManifestFile   = "attachments.json"
+	bundleArchivalFile   = "archival.json"
+	bundleAttachmentsDir = "attachments/"
+
+	// bundleDocumentLimit bounds the collection and manifest entries of a bundle
+	bundleDocumentLimit = 64 << 20
+)
+
+// AttachmentService stores files referenced by form-data and file request bodies
+type AttachmentService struct {
+	attachmentRepo    interfaces.AttachmentRepository
+	collectionService interfaces.CollectionService
+	store             storage.BlobStore
+	maxBytes          int64
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(
+	attachmentRepo interfaces.AttachmentRepository,
+	collectionService interfaces.CollectionService,
+	store storage.BlobStore,
+	maxBytes int64,
+) interfaces.AttachmentService {
+	return &AttachmentService{
+		attachmentRepo:    attachmentRepo,
+		collectionService: collectionService,
+		store:             store,
+		maxBytes:          maxBytes,
+	}
+}
+
+// UploadAttachment stores a file by the hash of its content; uploading the
+// same content again returns the existing attachment
+func (s *AttachmentService) UploadAttachment(ctx context.Context, filename, contentType string, r io.Reader) (*models.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	if int64(len(data)) > s.maxBytes {
+		return nil, tooLarge(fmt.Sprintf("limit is %d bytes", s.maxBytes))
+	}
+
+	return s.save(ctx, filename, contentType, data)
+}
+
+func (s *AttachmentService) save(ctx context.Context, filename, contentType string, data []byte) (*models.Attachment, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	// Content goes in first so that a recorded attachment always has a blob
+	if err := s.store.Put(ctx, attachmentKey(hash), data); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		Hash:        hash,
+		Filename:    path.Base(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		return nil, err
+	}
+
+	attachment.Ref = models.AttachmentRefPrefix + attachment.Hash
+	return attachment, nil
+}
+
+// GetAttachment retrieves attachment metadata by ID
+func (s *AttachmentService) GetAttachment(ctx context.Context, id int64) (*models.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.Ref = models.AttachmentRefPrefix + attachment.Hash
+	return attachment, nil
+}
+
+// OpenAttachment streams the content of an attachment
+func (s *AttachmentService) OpenAttachment(ctx context.Context, id int64) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Open(ctx, attachmentKey(attachment.Hash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment content: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment and its content
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id int64) error {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, attachmentKey(attachment.Hash))
+}
+
+// ExportCollectionBundle exports a collection as a zip archive that also
+// carries every attachment its requests reference and, for an archived
+// collection, when it was archived and its ID
+func (s *AttachmentService) ExportCollectionBundle(ctx context.Context, collectionID int64) ([]byte, error) {
+	collection, err := s.collectionService.GetCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.collectionService.ExportPostmanCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to read exported collection: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	if err := writeZipEntry(archive, bundleCollectionFile, data); err != nil {
+		return nil, err
+	}
+
+	manifest := []*models.Attachment{}
+	for _, hash := range attachmentRefs(doc) {
+		attachment, err := s.attachmentRepo.GetByHash(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("referenced attachment %s is missing: %w", hash, err)
+		}
+
+		content, err := s.readBlob(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeZipEntry(archive, bundleAttachmentsDir+hash, content); err != nil {
+			return nil, err
+		}
+		manifest = append(manifest, attachment)
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeZipEntry(archive, bundleManifestFile, manifestData); err != nil {
+		return nil, err
+	}
+
+	if collection.Archived && collection.ArchivedAt != nil {
+		archivalData, err := json.MarshalIndent(models.BundleArchival{
+			CollectionID: collection.ID,
+			ArchivedAt:   *collection.ArchivedAt,
+		}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeZipEntry(archive, bundleArchivalFile, archivalData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ImportCollectionBundle restores the attachments of a bundle archive, then
+// imports its collection. The collection of an archived collection's bundle
+// goes back into the archive, unless opts.Unarchive is set, and keeps its
+// original ID when that is free.
+func (s *AttachmentService) ImportCollectionBundle(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, models.NewValidationError("invalid bundle: %v", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	collectionFile, ok := files[bundleCollectionFile]
+	if !ok {
+		return nil, models.NewValidationError("invalid bundle: missing %s", bundleCollectionFile)
+	}
+
+	var manifest []*models.Attachment
+	if manifestFile, ok := files[bundleManifestFile]; ok {
+		manifestData, err := readZipEntry(manifestFile, bundleDocumentLimit)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(manifestData, &manifest); err != nil {
+			return nil, models.NewValidationError("invalid bundle manifest: %v", err)
+		}
+	}
+
+	for _, entry := range manifest {
+		file, ok := files[bundleAttachmentsDir+entry.Hash]
+		if !ok {
+			return nil, models.NewValidationError("invalid bundle: missing content of attachment %s", entry.Hash)
+		}
+
+		content, err := readZipEntry(file, s.maxBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		saved, err := s.save(ctx, entry.Filename, entry.ContentType, content)
+		if err != nil {
+			return nil, err
+		}
+		if saved.Hash != entry.Hash {
+			return nil, models.NewValidationError("invalid bundle: content of attachment %s does not match its hash", entry.Hash)
+		}
+	}
+
+	collectionData, err := readZipEntry(collectionFile, bundleDocumentLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if archivalFile, ok := files[bundleArchivalFile]; ok {
+		archivalData, err := readZipEntry(archivalFile, bundleDocumentLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		var archival models.BundleArchival
+		if err := json.Unmarshal(archivalData, &archival); err != nil {
+			return nil, models.NewValidationError("invalid bundle archival record: %v", err)
+		}
+		opts.ArchivedAt = &archival.ArchivedAt
+		opts.PreferredID = archival.CollectionID
+	}
+
+	// The provenance of a bundle is that of the archive, not of the collection inside it
+	if opts.Provenance != nil && opts.Provenance.OriginalHash == "" {
+		provenance := *opts.Provenance
+		sum := sha256.Sum256(data)
+		provenance.OriginalHash = hex.EncodeToString(sum[:])
+		opts.Provenance = &provenance
+	}
+
+	return s.collectionService.ImportPostmanCollection(ctx, collectionData, opts)
+}
+
+func (s *AttachmentService) readBlob(ctx context.Context, hash string) ([]byte, error) {
+	content, err := s.store.Open(ctx, attachmentKey(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment %s: %w", hash, err)
+	}
+	defer content.Close()
+
+	return io.ReadAll(content)
+}
+
+// readZipEntry reads a bundle entry, refusing entries above limit
+func readZipEntry(file *zip.File, limit int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, tooLarge(fmt.Sprintf("%s exceeds %d bytes", file.Name, limit))
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, tooLarge(fmt.Sprintf("%s exceeds %d bytes", file.Name, limit))
+	}
+
+	return data, nil
+}
+
+func writeZipEntry(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to bundle: %w", name, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+
+	return nil
+}
+
+func attachmentKey(hash string) string {
+	return "attachments/" + hash
+}
+
+// attachmentRefs returns the distinct attachment hashes referenced anywhere
+// in a JSON document, in order of first appearance
+func attachmentRefs(doc any) []string {
+	var hashes []string
+	seen := map[string]bool{}
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch value := v.(type) {
+		case map[string]any:
+			for _, key := range sortedKeys(value) {
+				walk(value[key])
+			}
+		case []any:
+			for _, item := range value {
+				walk(item)
+			}
+		case string:
+			if hash, ok := strings.CutPrefix(value, models.AttachmentRefPrefix); ok && !seen[hash] {
+				seen[hash] = true
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+
+	walk(doc)
+	return hashes
+}
+
+// tooLarge reports a file over the attachment size limit with detail
+func tooLarge(detail string) error {
+	return &models.Error{
+		Code:    models.ErrCodeTooLarge,
+		Message: models.ErrAttachmentTooLarge.Message + ": " + detail,
+		Err:     models.ErrAttachmentTooLarge,
+	}
+}
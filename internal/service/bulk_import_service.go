@@ -0,0 +1,158 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+)
+
+const (
+	// maxBulkImportFiles bounds how many collections one bulk import may hold
+	maxBulkImportFiles = 200
+
+	// maxArchiveBytes bounds the total size of the entries read from an archive
+	maxArchiveBytes = 256 << 20
+)
+
+// zipMagic starts every zip archive
+var zipMagic = []byte("PK\x03\x04")
+
+// BulkImportService imports several collections in one go, from separate
+// uploads or from a zip archive of them, and whole Postman workspace dumps
+type BulkImportService struct {
+	converters         interfaces.ConverterRegistry
+	environmentService interfaces.EnvironmentService
+	globalService      interfaces.GlobalVariableService
+}
+
+// NewBulkImportService creates a new bulk import service reading each file
+// in the format converters detect
+func NewBulkImportService(
+	converters interfaces.ConverterRegistry,
+	environmentService interfaces.EnvironmentService,
+	globalService interfaces.GlobalVariableService,
+) interfaces.BulkImportService {
+	return &BulkImportService{
+		converters:         converters,
+		environmentService: environmentService,
+		globalService:      globalService,
+	}
+}
+
+// IsArchive reports whether data is a zip archive of collections rather
+// than the bundle of a single collection
+func (s *BulkImportService) IsArchive(data []byte) bool {
+	if !bytes.HasPrefix(data, zipMagic) {
+		return false
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return false
+	}
+
+	for _, file := range archive.File {
+		if file.Name == bundleCollectionFile {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportCollections imports each file in the format detected for it;
+// archives of collections are expanded into their .json and .zip entries. Each file is imported on its own, so one that
+// fails leaves nothing behind and does not stop the others.
+func (s *BulkImportService) ImportCollections(ctx context.Context, files []models.ImportFile, opts models.ImportOptions) (*models.BulkImportReport, error) {
+	var expanded []models.ImportFile
+	for _, file := range files {
+		if !s.IsArchive(file.Data) {
+			expanded = append(expanded, file)
+			continue
+		}
+
+		entries, err := archiveEntries(file)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, entries...)
+	}
+
+	if len(expanded) == 0 {
+		return nil, models.NewValidationError("no collections to import")
+	}
+
+	if len(expanded) > maxBulkImportFiles {
+		return nil, models.NewValidationError("at most %d collections can be imported at once, got %d", maxBulkImportFiles, len(expanded))
+	}
+
+	report := &models.BulkImportReport{Results: make([]*models.BulkImportResult, 0, len(expanded))}
+	for _, file := range expanded {
+		fileOpts := opts
+		if opts.Provenance != nil {
+			provenance := *opts.Provenance
+			provenance.Filename = file.Filename
+			fileOpts.Provenance = &provenance
+		}
+
+		var result *models.ImportResult
+		converter, err := s.converters.Detect(file.Data)
+		if err == nil {
+			result, err = converter.Import(ctx, file.Data, fileOpts)
+		}
+
+		entry := &models.BulkImportResult{Filename: file.Filename, Err: err}
+		if err != nil {
+			report.Failed++
+		} else {
+			entry.ID = result.ID
+			entry.Existing = result.Existing
+			report.Imported++
+		}
+		report.Results = append(report.Results, entry)
+	}
+
+	return report, nil
+}
+
+// archiveEntries returns the collections and bundles in a zip archive,
+// skipping directories, other files and macOS metadata
+func archiveEntries(file models.ImportFile) ([]models.ImportFile, error) {
+	archive, err := zip.NewReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+	if err != nil {
+		return nil, models.NewValidationError("invalid archive %s: %v", file.Filename, err)
+	}
+
+	var entries []models.ImportFile
+	var total int64
+	for _, entry := range archive.File {
+		name := entry.Name
+		if entry.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".json" && ext != ".zip" {
+			continue
+		}
+
+		if len(entries) == maxBulkImportFiles {
+			return nil, models.NewValidationError("at most %d collections can be imported at once", maxBulkImportFiles)
+		}
+
+		data, err := readZipEntry(entry, bundleDocumentLimit)
+		if err != nil {
+			return nil, err
+		}
+		if total += int64(len(data)); total > maxArchiveBytes {
+			return nil, tooLarge(fmt.Sprintf("archive %s expands to more than %d bytes", file.Filename, maxArchiveBytes))
+		}
+		entries = append(entries, models.ImportFile{Filename: path.Join(file.Filename, name), Data: data})
+	}
+
+	return entries, nil
+}
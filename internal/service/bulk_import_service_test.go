@@ -0,0 +1,163 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+// zipEntry is a file of a test archive; a size makes it a stored entry
+// declaring that uncompressed size whatever its data
+type zipEntry struct {
+	name string
+	data string
+	size uint64
+}
+
+func testArchive(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		var err error
+		if entry.size > 0 {
+			var w io.Writer
+			w, err = archive.CreateRaw(&zip.FileHeader{
+				Name:               entry.name,
+				Method:             zip.Store,
+				CompressedSize64:   uint64(len(entry.data)),
+				UncompressedSize64: entry.size,
+			})
+			if err == nil {
+				_, err = w.Write([]byte(entry.data))
+			}
+		} else {
+			err = writeZipEntry(archive, entry.name, []byte(entry.data))
+		}
+		if err != nil {
+			t.Fatalf("failed to write test archive: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("failed to write test archive: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestArchiveEntries(t *testing.T) {
+	tooMany := make([]zipEntry, maxBulkImportFiles+1)
+	for i := range tooMany {
+		tooMany[i] = zipEntry{name: fmt.Sprintf("c%03d.json", i), data: "{}"}
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []string
+		errCode models.ErrorCode
+	}{
+		{
+			name: "collections and bundles",
+			data: testArchive(t,
+				zipEntry{name: "orders.json", data: `{"info":{}}`},
+				zipEntry{name: "nested/users.JSON", data: `{"info":{}}`},
+				zipEntry{name: "bundle.zip", data: "PK"},
+				zipEntry{name: "docs/"},
+				zipEntry{name: "README.md", data: "# collections"},
+				zipEntry{name: "__MACOSX/._orders.json", data: "meta"},
+				zipEntry{name: "nested/.hidden.json", data: "{}"},
+			),
+			want: []string{"export.zip/orders.json", "export.zip/nested/users.JSON", "export.zip/bundle.zip"},
+		},
+		{
+			name: "nothing to import",
+			data: testArchive(t, zipEntry{name: "notes.txt", data: "none"}),
+		},
+		{
+			name:    "not an archive",
+			data:    []byte("PK\x03\x04 truncated"),
+			errCode: models.ErrCodeValidation,
+		},
+		{
+			name:    "too many collections",
+			data:    testArchive(t, tooMany...),
+			errCode: models.ErrCodeValidation,
+		},
+		{
+			name:    "collection larger than a document may be",
+			data:    testArchive(t, zipEntry{name: "huge.json", data: "{}", size: bundleDocumentLimit + 1}),
+			errCode: models.ErrCodeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := archiveEntries(models.ImportFile{Filename: "export.zip", Data: tt.data})
+
+			if tt.errCode != "" {
+				if code := models.ErrorCodeOf(err); code != tt.errCode {
+					t.Fatalf("archiveEntries() = %v, want a %s error", err, tt.errCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("archiveEntries() = %v", err)
+			}
+
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Filename)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("archiveEntries() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveEntriesAtTheLimit(t *testing.T) {
+	files := make([]zipEntry, maxBulkImportFiles)
+	for i := range files {
+		files[i] = zipEntry{name: fmt.Sprintf("c%03d.json", i), data: fmt.Sprintf(`{"n":%d}`, i)}
+	}
+
+	entries, err := archiveEntries(models.ImportFile{Filename: "export.zip", Data: testArchive(t, files...)})
+	if err != nil {
+		t.Fatalf("archiveEntries() = %v", err)
+	}
+	if len(entries) != maxBulkImportFiles {
+		t.Fatalf("archiveEntries() returned %d entries, want %d", len(entries), maxBulkImportFiles)
+	}
+	if got := string(entries[7].Data); got != `{"n":7}` {
+		t.Errorf("entry 7 = %s, want its content", got)
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"archive of collections", testArchive(t, zipEntry{name: "a.json", data: "{}"}), true},
+		{"bundle of one collection", testArchive(t, zipEntry{name: bundleCollectionFile, data: "{}"}, zipEntry{name: bundleManifestFile, data: "{}"}), false},
+		{"JSON collection", []byte(`{"info":{}}`), false},
+		{"corrupt zip", []byte("PK\x03\x04 truncated"), false},
+	}
+
+	s := &BulkImportService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsArchive(tt.data); got != tt.want {
+				t.Errorf("IsArchive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
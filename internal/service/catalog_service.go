@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+)
+
+const catalogBatchSize = 200
+
+// CatalogService records who owns collections, specs and inventory endpoints
+type CatalogService struct {
+	collectionRepo interfaces.CollectionRepository
+	openAPIRepo    interfaces.OpenAPIRepository
+	ownershipRepo  interfaces.InventoryOwnershipRepository
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(
+	collectionRepo interfaces.CollectionRepository,
+	openAPIRepo interfaces.OpenAPIRepository,
+	ownershipRepo interfaces.InventoryOwnershipRepository,
+) interfaces.CatalogService {
+	return &CatalogService{
+		collectionRepo: collectionRepo,
+		openAPIRepo:    openAPIRepo,
+		ownershipRepo:  ownershipRepo,
+	}
+}
+
+// SetCollectionOwnership records the ownership of a collection; an empty ownership clears it
+func (s *CatalogService) SetCollectionOwnership(ctx context.Context, id int64, ownership models.Ownership) error {
+	collection, err := s.collectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("collection not found: %w", err)
+	}
+
+	collection.Metadata = withOwnership(collection.Metadata, ownership)
+	return s.collectionRepo.Update(ctx, collection)
+}
+
+// SetSpecOwnership records the ownership of an OpenAPI spec; an empty ownership clears it
+func (s *CatalogService) SetSpecOwnership(ctx context.Context, id int64, ownership models.Ownership) error {
+	spec, err := s.openAPIRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("OpenAPI specification not found: %w", err)
+	}
+
+	spec.Metadata = withOwnership(spec.Metadata, ownership)
+	return s.openAPIRepo.Update(ctx, spec)
+}
+
+// SetEndpointOwnership records the ownership of an inventory entry by its key;
+// an empty ownership clears it
+func (s *CatalogService) SetEndpointOwnership(ctx context.Context, key string, ownership models.Ownership) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return models.NewValidationError("inventory key is required")
+	}
+
+	ownership = cleanOwnership(ownership)
+	if ownership.IsZero() {
+		return s.ownershipRepo.Delete(ctx, key)
+	}
+
+	return s.ownershipRepo.Upsert(ctx, &models.InventoryOwnership{Key: key, Ownership: ownership})
+}
+
+// ListCatalog lists owned collections, specs and endpoints matching filter
+func (s *CatalogService) ListCatalog(ctx context.Context, filter models.CatalogFilter) ([]*models.CatalogEntry, error) {
+	entries := []*models.CatalogEntry{}
+
+	if filter.Type == "" || filter.Type == models.CatalogTypeCollection {
+		for offset := 0; ; offset += catalogBatchSize {
+			collections, err := s.collectionRepo.ListByOwnership(ctx, filter.OwnershipFilter, offset, catalogBatchSize)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, collection := range collections {
+				if ownership := ownershipFromMetadata(collection.Metadata); ownership != nil {
+					entries = append(entries, &models.CatalogEntry{
+						Type:      models.CatalogTypeCollection,
+						ID:        collection.ID,
+						Name:      collection.Name,
+						Ownership: *ownership,
+					})
+				}
+			}
+
+			if len(collections) < catalogBatchSize {
+				break
+			}
+		}
+	}
+
+	if filter.Type == "" || filter.Type == models.CatalogTypeSpec {
+		for offset := 0; ; offset += catalogBatchSize {
+			specs, err := s.openAPIRepo.ListByOwnership(ctx, filter.OwnershipFilter, offset, catalogBatchSize)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, spec := range specs {
+				if ownership := ownershipFromMetadata(spec.Metadata); ownership != nil {
+					entries = append(entries, &models.CatalogEntry{
+						Type:      models.CatalogTypeSpec,
+						ID:        spec.ID,
+						Name:      spec.Title,
+						Ownership: *ownership,
+					})
+				}
+			}
+
+			if len(specs) < catalogBatchSize {
+				break
+			}
+		}
+	}
+
+	if filter.Type == "" || filter.Type == models.CatalogTypeEndpoint {
+		ownerships, err := s.ownershipRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, owned := range ownerships {
+			if filter.Matches(&owned.Ownership) {
+				entries = append(entries, &models.CatalogEntry{
+					Type:      models.CatalogTypeEndpoint,
+					Key:       owned.Key,
+					Name:      owned.Key,
+					Ownership: owned.Ownership,
+				})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// cleanOwnership trims the fields of an ownership
+func cleanOwnership(ownership models.Ownership) models.Ownership {
+	return models.Ownership{
+		Team:      strings.TrimSpace(ownership.Team),
+		Tier:      strings.TrimSpace(ownership.Tier),
+		Lifecycle: strings.TrimSpace(ownership.Lifecycle),
+		Contact:   strings.TrimSpace(ownership.Contact),
+	}
+}
+
+// withOwnership returns metadata with the ownership key set, or removed for an empty ownership
+func withOwnership(metadata models.JSONMap, ownership models.Ownership) models.JSONMap {
+	if metadata == nil {
+		metadata = models.JSONMap{}
+	}
+
+	ownership = cleanOwnership(ownership)
+	if ownership.IsZero() {
+		delete(metadata, models.OwnershipMetadataKey)
+		return metadata
+	}
+
+	value := map[string]any{}
+	if data, err := json.Marshal(ownership); err == nil {
+		json.Unmarshal(data, &value)
+	}
+	metadata[models.OwnershipMetadataKey] = value
+
+	return metadata
+}
+
+// ownershipFromMetadata reads the ownership recorded in metadata, if any
+func ownershipFromMetadata(metadata models.JSONMap) *models.Ownership {
+	value, ok := metadata[models.OwnershipMetadataKey]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var ownership models.Ownership
+	if err := json.Unmarshal(data, &ownership); err != nil || ownership.IsZero() {
+		return nil
+	}
+
+	return &ownership
+}
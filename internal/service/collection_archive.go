@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+)
+
+// maxBulkArchive caps the number of collections archived in one call
+const maxBulkArchive = 500
+
+// SetCollectionArchived archives or unarchives a single collection
+func (s *CollectionService) SetCollectionArchived(ctx context.Context, id int64, archived bool) (*models.Collection, error) {
+	if _, err := s.collectionRepo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if _, err := s.collectionRepo.SetArchived(ctx, []int64{id}, archived); err != nil {
+		return nil, err
+	}
+
+	return s.collectionRepo.GetByID(ctx, id)
+}
+
+// ArchiveCollections archives or unarchives collections in bulk and returns
+// how many changed state; unknown IDs are skipped
+func (s *CollectionService) ArchiveCollections(ctx context.Context, ids []int64, archived bool) (int, error) {
+	if len(ids) == 0 {
+		return 0, models.NewValidationError("ids must not be empty")
+	}
+
+	if len(ids) > maxBulkArchive {
+		return 0, models.NewValidationError("at most %d collections can be archived at once", maxBulkArchive)
+	}
+
+	return s.collectionRepo.SetArchived(ctx, ids, archived)
+}
+
+// editableCollection loads a collection, refusing archived ones
+func editableCollection(ctx context.Context, repo interfaces.CollectionRepository, id int64) (*models.Collection, error) {
+	collection, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if collection.Archived {
+		return nil, models.NewConflictError(fmt.Sprintf("collection %d is archived", id), nil)
+	}
+
+	return collection, nil
+}
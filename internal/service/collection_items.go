@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/models"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// ListCollectionItems returns every request of a collection as an addressable Postman item
+func (s *CollectionService) ListCollectionItems(ctx context.Context, collectionID int64) ([]*models.CollectionItem, error) {
+	if _, err := s.collectionRepo.GetByID(ctx, collectionID); err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	items := []*models.CollectionItem{}
+	for offset := 0; ; offset += itemBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		for _, req := range requests {
+			items = append(items, &models.CollectionItem{
+				RequestID:  req.ID,
+				FolderPath: req.FolderPath,
+				Item:       postmanItemFromRequest(req),
+			})
+		}
+
+		if len(requests) < itemBatchSize {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// AddCollectionItem adds a request, or a folder of requests, to a collection
+func (s *CollectionService) AddCollectionItem(ctx context.Context, collectionID int64, entry *models.CollectionItem) ([]int64, error) {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return nil, err
+	}
+
+	if entry.Item.Request == nil && len(entry.Item.Item) == 0 {
+		return nil, models.NewValidationError("item must contain a request or child items")
+	}
+
+	var errs models.FieldErrors
+	validateItem(&errs, "item", entry.Item)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tree.loadRequestPositions(ctx, s.requestRepo); err != nil {
+		return nil, err
+	}
+
+	parent, err := tree.ensure(ctx, s.folderRepo, cleanFolderPath(entry.FolderPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.processPostmanItems(ctx, []models.PostmanItem{entry.Item}, tree, parent, nil)
+}
+
+// UpdateCollectionItem replaces a request of a collection with the given Postman item
+func (s *CollectionService) UpdateCollectionItem(ctx context.Context, collectionID, requestID int64, entry *models.CollectionItem) error {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return err
+	}
+
+	existing, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil || existing.CollectionID != collectionID {
+		return models.NewNotFoundError(fmt.Sprintf("item %d in collection %d", requestID, collectionID), nil)
+	}
+
+	if entry.Item.Request == nil {
+		return models.NewValidationError("item must contain a request")
+	}
+
+	request := newRequestFromPostmanItem(entry.Item, collectionID, cleanFolderPath(entry.FolderPath))
+
+	var errs models.FieldErrors
+	validateRequest(&errs, "item", request)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if request.Responses, err = s.bodies.offload(ctx, request.Responses); err != nil {
+		return err
+	}
+	request.ID = existing.ID
+	request.CreatedAt = existing.CreatedAt
+	request.Assertions = existing.Assertions
+	request.RunSettings = existing.RunSettings
+	request.HeaderPresets = existing.HeaderPresets
+	request.Deprecated = existing.Deprecated
+	request.Sunset = existing.Sunset
+
+	// The request and its examples are replaced together, so a failure
+	// never leaves the request paired with stale examples
+	return s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+		if err := placeRequest(ctx, txs.folderRepo, txs.requestRepo, request, existing); err != nil {
+			return err
+		}
+
+		if err := txs.requestRepo.Update(ctx, request); err != nil {
+			return err
+		}
+
+		return txs.exampleRepo.ReplaceForRequest(ctx, request.ID, request.Responses)
+	})
+}
+
+// RemoveCollectionItem deletes a request from a collection
+func (s *CollectionService) RemoveCollectionItem(ctx context.Context, collectionID, requestID int64) error {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return err
+	}
+
+	existing, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil || existing.CollectionID != collectionID {
+		return models.NewNotFoundError(fmt.Sprintf("item %d in collection %d", requestID, collectionID), nil)
+	}
+
+	return s.requestRepo.Delete(ctx, requestID)
+}
+
+// RenameFolder renames a folder, moving its requests and subfolders to the
+// new path in one transaction
+func (s *CollectionService) RenameFolder(ctx context.Context, collectionID int64, from, to string) (int, error) {
+	moved := 0
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		moved, err = s.withTx(tx).renameFolder(ctx, collectionID, from, to)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+func (s *CollectionService) renameFolder(ctx context.Context, collectionID int64, from, to string) (int, error) {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return 0, err
+	}
+
+	from, to = cleanFolderPath(from), cleanFolderPath(to)
+	if from == "" || to == "" {
+		return 0, models.NewValidationError("both from and to folder paths are required")
+	}
+
+	if from == to {
+		return 0, models.NewValidationError("from and to folder paths are identical")
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	folder := tree.find(from)
+	if folder == nil {
+		return 0, models.NewNotFoundError(fmt.Sprintf("folder %q", from), nil)
+	}
+
+	if tree.find(to) != nil {
+		return 0, models.NewConflictError(fmt.Sprintf("folder %q already exists", to), nil)
+	}
+
+	if strings.HasPrefix(to, from+"/") {
+		return 0, models.NewValidationError("a folder cannot be moved into its own subfolder")
+	}
+
+	parentPath, name := "", to
+	if i := strings.LastIndex(to, "/"); i >= 0 {
+		parentPath, name = to[:i], to[i+1:]
+	}
+
+	parent, err := tree.ensure(ctx, s.folderRepo, parentPath)
+	if err != nil {
+		return 0, err
+	}
+
+	renamed := *folder
+	renamed.Name = name
+	renamed.ParentID = 0
+	if parent != nil {
+		renamed.ParentID = parent.ID
+	}
+	if renamed.ParentID != folder.ParentID {
+		if err := tree.loadRequestPositions(ctx, s.requestRepo); err != nil {
+			return 0, err
+		}
+		renamed.Position = tree.nextPosition(renamed.ParentID)
+	}
+
+	if err := s.folderRepo.Update(ctx, &renamed); err != nil {
+		return 0, fmt.Errorf("failed to rename folder: %w", err)
+	}
+	tree.replace(&renamed)
+
+	return syncFolderPaths(ctx, s.requestRepo, tree, renamed.ID)
+}
+
+// cleanFolderPath normalizes a "a/b/c" folder path, dropping empty segments
+func cleanFolderPath(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// newRequestFromPostmanItem converts a Postman request item into a stored request
+func newRequestFromPostmanItem(item models.PostmanItem, collectionID int64, folderPath string) *models.Request {
+	request := &models.Request{
+		CollectionID: collectionID,
+		Name:         item.Name,
+		Description:  item.Description,
+		FolderPath:   folderPath,
+		Method:       item.Request.Method,
+		PostmanID:    item.PostmanID,
+	}
+
+	var urlMap models.JSONMap
+
+	switch v := item.Request.URL.(type) {
+	case string:
+		if v != "" {
+			urlMap = models.JSONMap{
+				"raw": v,
+			}
+		} else {
+			urlMap = models.JSONMap{}
+		}
+	default:
+		urlBytes, err := json.Marshal(item.Request.URL)
+		if err == nil {
+			if err := json.Unmarshal(urlBytes, &urlMap); err != nil {
+				urlMap = models.JSONMap{}
+			}
+		} else {
+			urlMap = models.JSONMap{}
+		}
+	}
+
+	if urlMap == nil {
+		urlMap = models.JSONMap{}
+	}
+
+	request.URL = urlMap
+	request.Params = queryParams(urlMap)
+
+	if len(item.Request.Header) > 0 {
+		headers := make(map[string]string)
+		for _, kv := range item.Request.Header {
+			headers[kv.Key] = kv.Value
+		}
+		request.Headers = headers
+	}
+
+	bodyBytes, err := json.Marshal(item.Request.Body)
+	if err == nil {
+		var bodyMap models.JSONMap
+		if err := json.Unmarshal(bodyBytes, &bodyMap); err == nil {
+			request.Body = bodyMap
+		}
+	}
+
+	if item.Request.Auth != nil {
+		var authMap models.JSONMap
+		authBytes, err := json.Marshal(item.Request.Auth)
+		if err == nil {
+			if err := json.Unmarshal(authBytes, &authMap); err == nil {
+				request.Auth = authMap
+			}
+		}
+	}
+
+	if len(item.Event) > 0 {
+		request.Events = item.Event
+	}
+
+	if len(item.Response) > 0 {
+		request.Responses = item.Response
+	}
+
+	return request
+}
+
+// queryParams returns the enabled query parameters of a Postman URL keyed by
+// name, or nil when it has none
+func queryParams(urlMap models.JSONMap) models.JSONMap {
+	query, ok := urlMap["query"].([]any)
+	if !ok {
+		return nil
+	}
+
+	params := models.JSONMap{}
+	for _, param := range query {
+		p, ok := param.(map[string]any)
+		if !ok || p["disabled"] == true {
+			continue
+		}
+		if key, _ := p["key"].(string); key != "" {
+			params[key] = p["value"]
+		}
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	return params
+}
+
+// postmanItemFromRequest converts a stored request back into a Postman item
+func postmanItemFromRequest(req *models.Request) models.PostmanItem {
+	postmanReq := &models.PostmanRequest{
+		Method:      req.Method,
+		Description: req.Description,
+	}
+
+	if req.URL != nil {
+		if urlBytes, err := json.Marshal(req.URL); err == nil {
+			json.Unmarshal(urlBytes, &postmanReq.URL)
+		} else {
+			postmanReq.URL = ""
+		}
+	}
+
+	if req.Headers != nil {
+		var headerArr []models.KeyValuePair
+		for k, v := range req.Headers {
+			headerArr = append(headerArr, models.KeyValuePair{Key: k, Value: v})
+		}
+		postmanReq.Header = headerArr
+	}
+
+	if req.Body != nil {
+		bodyBytes, _ := json.Marshal(req.Body)
+		json.Unmarshal(bodyBytes, &postmanReq.Body)
+	}
+
+	if req.Auth != nil {
+		authBytes, _ := json.Marshal(req.Auth)
+		postmanReq.Auth = authBytes
+	}
+
+	item := models.PostmanItem{
+		Name:        req.Name,
+		Description: req.Description,
+		PostmanID:   req.PostmanID,
+		Request:     postmanReq,
+	}
+
+	if req.Events != nil {
+		item.Event = req.Events
+	}
+
+	if req.Responses != nil {
+		item.Response = req.Responses
+	}
+
+	return item
+}
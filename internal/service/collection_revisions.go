@@ -0,0 +1,296 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"postman-api/internal/models"
+	"slices"
+
+	"github.com/uptrace/bun"
+)
+
+// ListRevisions returns the revisions of a collection with pagination,
+// newest first; those of a deleted collection are still listed
+func (s *CollectionService) ListRevisions(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.CollectionRevision, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	total, err := s.revisionRepo.CountByCollectionID(ctx, collectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		if _, err := s.collectionRepo.GetByID(ctx, collectionID); err != nil {
+			return nil, 0, fmt.Errorf("collection not found: %w", err)
+		}
+	}
+
+	revisions, err := s.revisionRepo.ListByCollectionID(ctx, collectionID, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// GetRevision retrieves a revision with its snapshot
+func (s *CollectionService) GetRevision(ctx context.Context, id int64) (*models.CollectionRevision, error) {
+	return s.revisionRepo.GetByID(ctx, id)
+}
+
+// RollbackCollection restores a collection, its folders and its requests to
+// a revision, recreating the collection if it was deleted since. The state
+// it replaces is kept as a revision of its own, so a rollback can be undone.
+func (s *CollectionService) RollbackCollection(ctx context.Context, collectionID, revisionID int64) (*models.Collection, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision.CollectionID != collectionID {
+		return nil, models.NewNotFoundError("collection revision", nil)
+	}
+
+	if revision.Snapshot == nil || revision.Snapshot.Collection == nil {
+		return nil, models.NewValidationError("revision %d has no snapshot to roll back to", revisionID)
+	}
+
+	err = s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		return s.withTx(tx).restoreSnapshot(ctx, collectionID, revision.Snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.collectionRepo.GetByID(ctx, collectionID)
+}
+
+// recordRevision stores a snapshot of collection as it is now
+func (s *CollectionService) recordRevision(ctx context.Context, collection *models.Collection, action string) error {
+	folders, err := s.folderRepo.ListByCollectionID(ctx, collection.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	requests, err := s.collectionRequests(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+
+	kept := *collection
+	kept.Requests = nil
+	kept.Links = nil
+
+	revision := &models.CollectionRevision{
+		CollectionID: collection.ID,
+		Action:       action,
+		Name:         collection.Name,
+		RequestCount: len(requests),
+		Snapshot: &models.CollectionSnapshot{
+			Collection: &kept,
+			Folders:    folders,
+			Requests:   requests,
+		},
+	}
+
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record collection revision: %w", err)
+	}
+
+	return nil
+}
+
+// collectionRequests returns every request of a collection with its
+// examples, in the order they were added
+func (s *CollectionService) collectionRequests(ctx context.Context, collectionID int64) ([]*models.Request, error) {
+	var all []*models.Request
+	for offset := 0; ; offset += itemBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		all = append(all, requests...)
+
+		if len(requests) < itemBatchSize {
+			break
+		}
+	}
+
+	slices.SortFunc(all, func(a, b *models.Request) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return all, nil
+}
+
+// restoreSnapshot brings a collection back to snapshot. Folders and requests
+// keep the IDs they had; a request moved to another collection since is
+// restored as a copy.
+func (s *CollectionService) restoreSnapshot(ctx context.Context, collectionID int64, snapshot *models.CollectionSnapshot) error {
+	collection := *snapshot.Collection
+	collection.ID = collectionID
+
+	current, err := s.collectionRepo.GetByID(ctx, collectionID)
+	switch {
+	case err == nil:
+		if current.Archived {
+			return models.NewConflictError(fmt.Sprintf("collection %d is archived", collectionID), nil)
+		}
+
+		if err := s.recordRevision(ctx, current, models.RevisionRolledBack); err != nil {
+			return err
+		}
+
+		collection.Archived = false
+		collection.ArchivedAt = nil
+		if err := s.collectionRepo.Update(ctx, &collection); err != nil {
+			return err
+		}
+	case models.ErrorCodeOf(err) == models.ErrCodeNotFound:
+		if err := s.collectionRepo.Create(ctx, &collection); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	folders, err := s.folderRepo.ListByCollectionID(ctx, collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	requests, err := s.collectionRequests(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.restoreFolders(ctx, collectionID, snapshot.Folders, folders); err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool, len(snapshot.Requests))
+	existing := make(map[int64]bool, len(requests))
+	for _, request := range requests {
+		existing[request.ID] = true
+	}
+
+	for _, snapshotted := range snapshot.Requests {
+		request := *snapshotted
+		request.CollectionID = collectionID
+		request.Collection = nil
+		request.Links = nil
+
+		if existing[request.ID] {
+			kept[request.ID] = true
+			if err := s.requestRepo.Update(ctx, &request); err != nil {
+				return fmt.Errorf("failed to restore request %d: %w", request.ID, err)
+			}
+			if err := s.exampleRepo.ReplaceForRequest(ctx, request.ID, request.Responses); err != nil {
+				return fmt.Errorf("failed to restore examples of request %d: %w", request.ID, err)
+			}
+			continue
+		}
+
+		if _, err := s.requestRepo.GetByID(ctx, request.ID); err == nil {
+			request.ID = 0
+		} else if models.ErrorCodeOf(err) != models.ErrCodeNotFound {
+			return err
+		}
+
+		if err := s.requestRepo.Create(ctx, &request); err != nil {
+			return fmt.Errorf("failed to restore request %q: %w", request.Name, err)
+		}
+	}
+
+	for _, request := range requests {
+		if kept[request.ID] {
+			continue
+		}
+		if err := s.requestRepo.Delete(ctx, request.ID); err != nil {
+			return fmt.Errorf("failed to delete request %d: %w", request.ID, err)
+		}
+	}
+
+	return s.pruneFolders(ctx, snapshot.Folders, folders)
+}
+
+// restoreFolders updates or recreates the folders of snapshot, parents first
+func (s *CollectionService) restoreFolders(ctx context.Context, collectionID int64, snapshot, current []*models.Folder) error {
+	existing := make(map[int64]bool, len(current))
+	for _, folder := range current {
+		existing[folder.ID] = true
+	}
+
+	for _, snapshotted := range byDepth(snapshot) {
+		folder := *snapshotted
+		folder.CollectionID = collectionID
+		folder.Links = nil
+
+		var err error
+		if existing[folder.ID] {
+			err = s.folderRepo.Update(ctx, &folder)
+		} else {
+			err = s.folderRepo.Create(ctx, &folder)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore folder %q: %w", folder.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// pruneFolders deletes the current folders missing from snapshot, deepest
+// first; those already gone with a deleted parent are skipped
+func (s *CollectionService) pruneFolders(ctx context.Context, snapshot, current []*models.Folder) error {
+	kept := make(map[int64]bool, len(snapshot))
+	for _, folder := range snapshot {
+		kept[folder.ID] = true
+	}
+
+	ordered := byDepth(current)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		folder := ordered[i]
+		if kept[folder.ID] {
+			continue
+		}
+		if err := s.folderRepo.Delete(ctx, folder.ID); err != nil && models.ErrorCodeOf(err) != models.ErrCodeNotFound {
+			return fmt.Errorf("failed to delete folder %d: %w", folder.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// byDepth orders folders so that each comes after its parent
+func byDepth(folders []*models.Folder) []*models.Folder {
+	parents := make(map[int64]int64, len(folders))
+	for _, folder := range folders {
+		parents[folder.ID] = folder.ParentID
+	}
+
+	depth := func(folder *models.Folder) int {
+		d := 0
+		for id := folder.ParentID; id != 0 && d < len(folders); id = parents[id] {
+			d++
+		}
+		return d
+	}
+
+	ordered := slices.Clone(folders)
+	slices.SortStableFunc(ordered, func(a, b *models.Folder) int {
+		return cmp.Compare(depth(a), depth(b))
+	})
+
+	return ordered
+}
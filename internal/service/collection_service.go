@@ -1,33 +1,65 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
+	"postman-api/internal/storage"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
 )
 
+const itemBatchSize = 500
+
 // CollectionService handles business logic for collections
 type CollectionService struct {
 	collectionRepo interfaces.CollectionRepository
 	requestRepo    interfaces.RequestRepository
+	folderRepo     interfaces.FolderRepository
+	exampleRepo    interfaces.ExampleRepository
+	revisionRepo   interfaces.CollectionRevisionRepository
+	transactor     interfaces.Transactor
+	bodies         *responseBodies
+	deduplicate    bool
 }
 
-// NewCollectionService creates a new collection service
+// NewCollectionService creates a new collection service; saved response
+// bodies are stored according to policy, large ones in store. With
+// deduplicate set, re-importing an identical document returns the existing collection.
 func NewCollectionService(
 	collectionRepo interfaces.CollectionRepository,
 	requestRepo interfaces.RequestRepository,
+	folderRepo interfaces.FolderRepository,
+	exampleRepo interfaces.ExampleRepository,
+	revisionRepo interfaces.CollectionRevisionRepository,
+	transactor interfaces.Transactor,
+	store storage.BlobStore,
+	policy models.ResponseBodyPolicy,
+	deduplicate bool,
 ) interfaces.CollectionService {
 	return &CollectionService{
 		collectionRepo: collectionRepo,
 		requestRepo:    requestRepo,
+		folderRepo:     folderRepo,
+		exampleRepo:    exampleRepo,
+		revisionRepo:   revisionRepo,
+		transactor:     transactor,
+		bodies:         &responseBodies{store: store, policy: policy},
+		deduplicate:    deduplicate,
 	}
 }
 
 // CreateCollection creates a new collection
 func (s *CollectionService) CreateCollection(ctx context.Context, collection *models.Collection) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+
 	return s.collectionRepo.Create(ctx, collection)
 }
 
@@ -36,13 +68,22 @@ func (s *CollectionService) GetCollection(ctx context.Context, id int64) (*model
 	return s.collectionRepo.GetByID(ctx, id)
 }
 
+// GetCollections retrieves the collections with the given IDs in the order
+// asked for, skipping IDs without one
+func (s *CollectionService) GetCollections(ctx context.Context, ids []int64) ([]*models.Collection, error) {
+	return getByIDs(ctx, ids, s.collectionRepo.GetByIDs, func(collection *models.Collection) int64 {
+		return collection.ID
+	})
+}
+
 // GetCollectionWithRequests retrieves a collection with all its requests
 func (s *CollectionService) GetCollectionWithRequests(ctx context.Context, id int64) (*models.Collection, error) {
 	return s.collectionRepo.GetWithRequests(ctx, id)
 }
 
-// ListCollections returns all collections with pagination
-func (s *CollectionService) ListCollections(ctx context.Context, page, pageSize int) ([]*models.Collection, int, error) {
+// ListCollections returns collections with pagination; archived collections
+// are left out unless includeArchived is set
+func (s *CollectionService) ListCollections(ctx context.Context, page, pageSize int, countMode models.CountMode, includeArchived bool) ([]*models.Collection, models.Total, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -53,50 +94,101 @@ func (s *CollectionService) ListCollections(ctx context.Context, page, pageSize
 
 	offset := (page - 1) * pageSize
 
-	collections, err := s.collectionRepo.List(ctx, offset, pageSize)
+	collections, err := s.collectionRepo.List(ctx, offset, pageSize, includeArchived)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
-	total, err := s.collectionRepo.Count(ctx)
+	total, err := resolveTotal(ctx, countMode,
+		func(ctx context.Context) (int, error) {
+			return s.collectionRepo.Count(ctx, includeArchived)
+		},
+		s.collectionRepo.EstimateCount,
+	)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
 	return collections, total, nil
 }
 
-// UpdateCollection updates an existing collection
+// UpdateCollection updates an existing collection, keeping a revision of
+// what it replaces
 func (s *CollectionService) UpdateCollection(ctx context.Context, collection *models.Collection) error {
-	existingCollection, err := s.collectionRepo.GetByID(ctx, collection.ID)
-	if err != nil {
-		return fmt.Errorf("collection not found: %w", err)
+	if err := validateCollection(collection); err != nil {
+		return err
 	}
 
-	collection.Items = existingCollection.Items
+	return s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+		existingCollection, err := editableCollection(ctx, txs.collectionRepo, collection.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := txs.recordRevision(ctx, existingCollection, models.RevisionUpdated); err != nil {
+			return err
+		}
 
-	return s.collectionRepo.Update(ctx, collection)
+		collection.Items = existingCollection.Items
+		collection.Archived = existingCollection.Archived
+		collection.ArchivedAt = existingCollection.ArchivedAt
+		if collection.Metadata == nil {
+			collection.Metadata = existingCollection.Metadata
+		}
+		collection.Metadata = keepProvenance(collection.Metadata, existingCollection.Metadata)
+
+		return txs.collectionRepo.Update(ctx, collection)
+	})
 }
 
-// DeleteCollection removes a collection and all its requests
+// DeleteCollection removes a collection and all its requests, keeping a
+// revision to restore them from; archived collections must be unarchived first
 func (s *CollectionService) DeleteCollection(ctx context.Context, id int64) error {
-	err := s.requestRepo.DeleteByCollectionID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete requests in collection: %w", err)
-	}
+	return s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+		collection, err := editableCollection(ctx, txs.collectionRepo, id)
+		if err != nil {
+			return err
+		}
+
+		if err := txs.recordRevision(ctx, collection, models.RevisionDeleted); err != nil {
+			return err
+		}
 
-	return s.collectionRepo.Delete(ctx, id)
+		if err := txs.requestRepo.DeleteByCollectionID(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete requests in collection: %w", err)
+		}
+
+		return txs.collectionRepo.Delete(ctx, id)
+	})
 }
 
 // ImportPostmanCollection imports a Postman collection from JSON
-func (s *CollectionService) ImportPostmanCollection(ctx context.Context, data []byte) (int64, error) {
-	var postmanCollection models.PostmanCollection
-	if err := json.Unmarshal(data, &postmanCollection); err != nil {
-		return 0, fmt.Errorf("invalid Postman collection format: %w", err)
+func (s *CollectionService) ImportPostmanCollection(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	hash := importHash(data, opts)
+	if s.deduplicate {
+		existing, err := s.collectionRepo.FindByMetadata(ctx, models.ContentHashMetadataKey, hash)
+		if err == nil {
+			return &models.ImportResult{ID: existing.ID, Existing: true}, nil
+		}
+		if models.ErrorCodeOf(err) != models.ErrCodeNotFound {
+			return nil, err
+		}
+	}
+
+	parsed, result := parsePostmanCollection(data)
+	if !result.Valid {
+		if result.Errors[0].Path == "" {
+			return nil, models.NewValidationError("invalid Postman collection format: %s", result.Errors[0].Message)
+		}
+		return nil, models.NewValidationError("%s", result.Errors[0].Message)
 	}
+	postmanCollection := *parsed
+	reportParsed(opts, result)
 
-	if postmanCollection.Info.Name == "" {
-		return 0, errors.New("collection name is required")
+	if opts.StripSecrets {
+		stripSecrets(&postmanCollection)
 	}
 
 	variables := make(models.JSONMap)
@@ -139,128 +231,123 @@ func (s *CollectionService) ImportPostmanCollection(ctx context.Context, data []
 		Items:       items,
 		PostmanID:   postmanCollection.Info.PostmanID,
 		ExporterID:  postmanCollection.Info.ExporterID,
+		Metadata:    withProvenance(withContentHash(opts.Metadata, hash), opts.Provenance, data),
 	}
-
-	if err := s.collectionRepo.Create(ctx, collection); err != nil {
-		return 0, fmt.Errorf("failed to create collection: %w", err)
+	if opts.ArchivedAt != nil && !opts.Unarchive {
+		collection.Archived = true
+		collection.ArchivedAt = opts.ArchivedAt
 	}
 
-	if err := s.processPostmanItems(ctx, postmanCollection.Item, collection.ID, ""); err != nil {
-		return 0, err
+	total := result.Summary["requests"]
+	created := 0
+	onCreate := func(request *models.Request) {
+		created++
+		opts.Report(models.ImportProgress{Stage: models.ImportStageRequest, Name: request.Name, Created: created, Total: total})
 	}
 
-	return collection.ID, nil
-}
-
-// processPostmanItems processes items in a Postman collection, handling nested folders
-func (s *CollectionService) processPostmanItems(ctx context.Context, items []models.PostmanItem, collectionID int64, parentPath string) error {
-	for _, item := range items {
-		currentPath := parentPath
-		if currentPath != "" {
-			currentPath += "/"
-		}
-		currentPath += item.Name
-
-		if len(item.Item) > 0 {
-			if err := s.processPostmanItems(ctx, item.Item, collectionID, currentPath); err != nil {
+	// The collection, its folders and requests are created in one
+	// transaction, so a failed import leaves nothing behind
+	err = s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+		if opts.PreferredID > 0 {
+			available, err := txs.collectionRepo.IDAvailable(ctx, opts.PreferredID)
+			if err != nil {
 				return err
 			}
-			continue
+			if available {
+				collection.ID = opts.PreferredID
+			}
 		}
 
-		if item.Request == nil {
-			continue
+		if err := txs.collectionRepo.Create(ctx, collection); err != nil {
+			return fmt.Errorf("failed to create collection: %w", err)
 		}
 
-		request := &models.Request{
-			CollectionID: collectionID,
-			Name:         item.Name,
-			Description:  item.Description,
-			FolderPath:   parentPath,
-			Method:       item.Request.Method,
-			PostmanID:    item.PostmanID,
-		}
+		tree := newFolderTree(collection.ID, nil)
+		_, err := txs.processPostmanItems(ctx, postmanCollection.Item, tree, nil, onCreate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		var urlMap models.JSONMap
+	return &models.ImportResult{ID: collection.ID, Archived: collection.Archived}, nil
+}
 
-		switch v := item.Request.URL.(type) {
-		case string:
-			if v != "" {
-				urlMap = models.JSONMap{
-					"raw": v,
-				}
-			} else {
-				urlMap = models.JSONMap{}
-			}
-		default:
-			urlBytes, err := json.Marshal(item.Request.URL)
-			if err == nil {
-				if err := json.Unmarshal(urlBytes, &urlMap); err != nil {
-					urlMap = models.JSONMap{}
-				}
-			} else {
-				urlMap = models.JSONMap{}
-			}
-		}
+// withTx returns a copy of the service whose repositories run in tx
+func (s *CollectionService) withTx(tx bun.Tx) *CollectionService {
+	txs := *s
+	txs.collectionRepo = s.collectionRepo.WithTx(tx)
+	txs.requestRepo = s.requestRepo.WithTx(tx)
+	txs.folderRepo = s.folderRepo.WithTx(tx)
+	txs.exampleRepo = s.exampleRepo.WithTx(tx)
+	txs.revisionRepo = s.revisionRepo.WithTx(tx)
+	return &txs
+}
 
-		if urlMap == nil {
-			urlMap = models.JSONMap{}
-		}
+// ValidatePostmanCollection runs the import validation pipeline without persisting anything
+func (s *CollectionService) ValidatePostmanCollection(ctx context.Context, data []byte) (*models.ValidationResult, error) {
+	_, result := parsePostmanCollection(data)
+	return result, nil
+}
 
-		request.URL = urlMap
+// processPostmanItems processes items in a Postman collection, creating a
+// folder row under parent (nil for the top level) for each folder item;
+// onCreate, when set, is called for each created request
+func (s *CollectionService) processPostmanItems(ctx context.Context, items []models.PostmanItem, tree *folderTree, parent *models.Folder, onCreate func(*models.Request)) ([]int64, error) {
+	var parentID int64
+	var parentPath string
+	if parent != nil {
+		parentID, parentPath = parent.ID, parent.Path
+	}
 
-		if len(item.Request.Header) > 0 {
-			headers := make(map[string]string)
-			for _, kv := range item.Request.Header {
-				headers[kv.Key] = kv.Value
+	var created []int64
+	for _, item := range items {
+		if len(item.Item) > 0 || (item.Request == nil && item.Item != nil) {
+			folder := &models.Folder{
+				CollectionID: tree.collectionID,
+				ParentID:     parentID,
+				Name:         item.Name,
+				Description:  item.Description,
+				Position:     tree.nextPosition(parentID),
+				PostmanID:    item.PostmanID,
 			}
-			request.Headers = headers
-		}
-
-		bodyBytes, err := json.Marshal(item.Request.Body)
-		if err == nil {
-			var bodyMap models.JSONMap
-			if err := json.Unmarshal(bodyBytes, &bodyMap); err == nil {
-				request.Body = bodyMap
+			if err := s.folderRepo.Create(ctx, folder); err != nil {
+				return nil, fmt.Errorf("failed to create folder: %w", err)
 			}
-		}
+			tree.add(folder)
 
-		if item.Request.Auth != nil {
-			var authMap models.JSONMap
-			authBytes, err := json.Marshal(item.Request.Auth)
-			if err == nil {
-				if err := json.Unmarshal(authBytes, &authMap); err == nil {
-					request.Auth = authMap
-				}
+			ids, err := s.processPostmanItems(ctx, item.Item, tree, folder, onCreate)
+			if err != nil {
+				return nil, err
 			}
+			created = append(created, ids...)
+			continue
 		}
 
-		if len(item.Event) > 0 {
-			eventsBytes, err := json.Marshal(item.Event)
-			if err == nil {
-				var eventsMap models.JSONMap
-				if err := json.Unmarshal(eventsBytes, &eventsMap); err == nil {
-					request.Events = eventsMap
-				}
-			}
+		if item.Request == nil {
+			continue
 		}
 
-		if len(item.Response) > 0 {
-			responsesBytes, err := json.Marshal(item.Response)
-			if err == nil {
-				var responsesMap models.JSONMap
-				if err := json.Unmarshal(responsesBytes, &responsesMap); err == nil {
-					request.Responses = responsesMap
-				}
-			}
+		request := newRequestFromPostmanItem(item, tree.collectionID, parentPath)
+		request.FolderID = parentID
+		tree.appendRequest(request)
+
+		var err error
+		if request.Responses, err = s.bodies.offload(ctx, request.Responses); err != nil {
+			return nil, err
 		}
 
 		if err := s.requestRepo.Create(ctx, request); err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		created = append(created, request.ID)
+		if onCreate != nil {
+			onCreate(request)
 		}
 	}
 
-	return nil
+	return created, nil
 }
 
 // ExportPostmanCollection exports a collection to Postman format
@@ -312,80 +399,36 @@ func (s *CollectionService) ExportPostmanCollection(ctx context.Context, id int6
 		}
 	}
 
-	requests, err := s.requestRepo.ListByCollectionID(ctx, id, 0, 1000)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get requests: %w", err)
-	}
-
-	folderMap := make(map[string][]models.PostmanItem)
-	for _, req := range requests {
-		postmanReq := &models.PostmanRequest{
-			Method:      req.Method,
-			Description: req.Description,
-		}
-
-		if req.URL != nil {
-			if urlBytes, err := json.Marshal(req.URL); err == nil {
-				json.Unmarshal(urlBytes, &postmanReq.URL)
-			} else {
-				postmanReq.URL = ""
-			}
-		}
-
-		if req.Headers != nil {
-			var headerArr []models.KeyValuePair
-			for k, v := range req.Headers {
-				headerArr = append(headerArr, models.KeyValuePair{Key: k, Value: v})
-			}
-			postmanReq.Header = headerArr
-		}
-
-		if req.Body != nil {
-			bodyBytes, _ := json.Marshal(req.Body)
-			json.Unmarshal(bodyBytes, &postmanReq.Body)
-		}
-
-		if req.Auth != nil {
-			authBytes, _ := json.Marshal(req.Auth)
-			postmanReq.Auth = authBytes
-		}
-
-		item := models.PostmanItem{
-			Name:        req.Name,
-			Description: req.Description,
-			PostmanID:   req.PostmanID,
-			Request:     postmanReq,
+	var requests []*models.Request
+	for offset := 0; ; offset += itemBatchSize {
+		batch, err := s.requestRepo.ListByCollectionID(ctx, id, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
 		}
 
-		if req.Events != nil {
-			eventsBytes, _ := json.Marshal(req.Events)
-			json.Unmarshal(eventsBytes, &item.Event)
-		}
+		requests = append(requests, batch...)
 
-		if req.Responses != nil {
-			responsesBytes, _ := json.Marshal(req.Responses)
-			json.Unmarshal(responsesBytes, &item.Response)
+		if len(batch) < itemBatchSize {
+			break
 		}
-
-		folderPath := req.FolderPath
-		folderMap[folderPath] = append(folderMap[folderPath], item)
 	}
 
-	postmanCollection.Item = folderMap[""]
-
-	for path, items := range folderMap {
-		if path == "" {
-			continue
-		}
+	tree, err := loadFolderTree(ctx, s.folderRepo, id)
+	if err != nil {
+		return nil, err
+	}
 
-		folder := models.PostmanItem{
-			Name: path,
-			Item: items,
+	exported := make(map[int64]models.PostmanItem, len(requests))
+	for _, req := range requests {
+		item := postmanItemFromRequest(req)
+		if item.Response, err = s.bodies.inline(ctx, item.Response); err != nil {
+			return nil, err
 		}
-
-		postmanCollection.Item = append(postmanCollection.Item, folder)
+		exported[req.ID] = item
 	}
 
+	postmanCollection.Item = postmanFolderItems(tree, requestsByFolder(tree, requests), exported, 0)
+
 	if collection.Variables != nil {
 		for k, v := range collection.Variables {
 			postmanCollection.Variable = append(postmanCollection.Variable, models.KeyValuePair{
@@ -407,3 +450,68 @@ func (s *CollectionService) ExportPostmanCollection(ctx context.Context, id int6
 
 	return json.MarshalIndent(postmanCollection, "", "  ")
 }
+
+// requestsByFolder groups requests by the folder they are exported under, in
+// position order, the earliest added first among requests at the same position
+func requestsByFolder(tree *folderTree, requests []*models.Request) map[int64][]*models.Request {
+	sorted := slices.Clone(requests)
+	slices.SortFunc(sorted, func(a, b *models.Request) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+	})
+
+	grouped := make(map[int64][]*models.Request)
+	for _, request := range sorted {
+		folderID := request.FolderID
+		if _, ok := tree.folders[folderID]; !ok {
+			folderID = exportFolderID(tree, cleanFolderPath(request.FolderPath))
+		}
+		grouped[folderID] = append(grouped[folderID], request)
+	}
+
+	return grouped
+}
+
+// exportFolderID returns the folder to export a request with a folder path
+// but no folder row under, nesting unsaved folders into the tree for the
+// segments of the path that have no row either
+func exportFolderID(tree *folderTree, path string) int64 {
+	if path == "" {
+		return 0
+	}
+
+	var parentID int64
+	for _, name := range strings.Split(path, "/") {
+		next := tree.child(parentID, name)
+		if next == nil {
+			// Unsaved folders take negative IDs so they cannot clash with rows
+			next = &models.Folder{
+				ID:           -int64(len(tree.folders) + 1),
+				CollectionID: tree.collectionID,
+				ParentID:     parentID,
+				Name:         name,
+				Position:     tree.nextPosition(parentID),
+			}
+			tree.add(next)
+		}
+		parentID = next.ID
+	}
+
+	return parentID
+}
+
+// postmanFolderItems nests the exported items of a folder (0 for the top
+// level): its requests and subfolders in position order
+func postmanFolderItems(tree *folderTree, folderRequests map[int64][]*models.Request, exported map[int64]models.PostmanItem, folderID int64) []models.PostmanItem {
+	var items []models.PostmanItem
+	tree.eachChild(folderID, folderRequests[folderID], func(request *models.Request) {
+		items = append(items, exported[request.ID])
+	}, func(folder *models.Folder) {
+		items = append(items, models.PostmanItem{
+			Name:        folder.Name,
+			Description: folder.Description,
+			PostmanID:   folder.PostmanID,
+			Item:        postmanFolderItems(tree, folderRequests, exported, folder.ID),
+		})
+	})
+	return items
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/models"
+	"strings"
+)
+
+var postmanMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "COPY": true, "LINK": true, "UNLINK": true,
+	"PURGE": true, "LOCK": true, "UNLOCK": true, "PROPFIND": true, "VIEW": true,
+}
+
+// parsePostmanCollection runs the import validation pipeline over a Postman collection.
+// Errors make the document unimportable; warnings are reported but tolerated.
+func parsePostmanCollection(data []byte) (*models.PostmanCollection, *models.ValidationResult) {
+	result := &models.ValidationResult{
+		Errors:   []models.ValidationIssue{},
+		Warnings: []models.ValidationIssue{},
+		Summary:  make(map[string]int),
+	}
+
+	var collection models.PostmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		result.AddError("", err.Error())
+		return nil, result
+	}
+
+	if collection.Info.Name == "" {
+		result.AddError("info.name", "collection name is required")
+	}
+
+	if collection.Info.Schema == "" && collection.Schema == "" {
+		result.AddWarning("info.schema", "missing collection schema URL")
+	}
+
+	validatePostmanItems(collection.Item, "item", result)
+	result.Summary["variables"] = len(collection.Variable)
+
+	result.Valid = len(result.Errors) == 0
+	if !result.Valid {
+		return nil, result
+	}
+
+	return &collection, result
+}
+
+func validatePostmanItems(items []models.PostmanItem, location string, result *models.ValidationResult) {
+	for i, item := range items {
+		itemLocation := fmt.Sprintf("%s[%d]", location, i)
+
+		if item.Name == "" {
+			result.AddWarning(itemLocation+".name", "item has no name")
+		}
+
+		if len(item.Item) > 0 {
+			result.Summary["folders"]++
+			validatePostmanItems(item.Item, itemLocation+".item", result)
+			continue
+		}
+
+		if item.Request == nil {
+			if item.Item != nil {
+				result.Summary["folders"]++
+				result.AddWarning(itemLocation, "folder is empty")
+			} else {
+				result.AddWarning(itemLocation, "item has neither a request nor child items and will be skipped")
+			}
+			continue
+		}
+
+		result.Summary["requests"]++
+
+		method := strings.ToUpper(item.Request.Method)
+		if method == "" {
+			result.AddWarning(itemLocation+".request.method", "request has no method")
+		} else if !postmanMethods[method] {
+			result.AddWarning(itemLocation+".request.method", fmt.Sprintf("unknown method %q", item.Request.Method))
+		}
+
+		if item.Request.URL == nil || item.Request.URL == "" {
+			result.AddWarning(itemLocation+".request.url", "request has no URL")
+		}
+	}
+}
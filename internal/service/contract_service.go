@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"postman-api/internal/codegen"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+)
+
+// ContractTestService generates runnable test collections from OpenAPI specifications
+type ContractTestService struct {
+	openAPIRepo    interfaces.OpenAPIRepository
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+}
+
+// NewContractTestService creates a new contract test service
+func NewContractTestService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+) interfaces.ContractTestService {
+	return &ContractTestService{
+		openAPIRepo:    openAPIRepo,
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+	}
+}
+
+// GenerateContractTests stores a collection with one request per operation
+// of a spec. Each request carries sample inputs, assertions on its success
+// status and the required properties of its response, and an equivalent
+// Postman test script so exports run under Newman as well.
+func (s *ContractTestService) GenerateContractTests(ctx context.Context, specID int64) (*models.Collection, error) {
+	spec, err := s.openAPIRepo.GetByID(ctx, specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI spec: %w", err)
+	}
+
+	if spec.Content == nil {
+		return nil, models.NewValidationError("OpenAPI spec has no content")
+	}
+
+	tests := codegen.ContractTests(spec.Content)
+	if len(tests) == 0 {
+		return nil, models.NewValidationError("OpenAPI spec %d has no operations", specID)
+	}
+
+	variables := models.JSONMap{"baseUrl": codegen.ServerURL(spec.Content)}
+	for _, test := range tests {
+		for name, value := range test.PathParams {
+			if _, ok := variables[name]; !ok {
+				variables[name] = value
+			}
+		}
+	}
+
+	collection := &models.Collection{
+		Name:        spec.Title + " contract tests",
+		Description: fmt.Sprintf("Contract tests generated from %s %s", spec.Title, spec.Version),
+		Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		Variables:   variables,
+		Metadata:    withProvenance(models.JSONMap{models.SourceSpecMetadataKey: spec.ID}, generatedProvenance(spec), nil),
+	}
+
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	for i, test := range tests {
+		request := contractRequest(test, collection.ID)
+		request.Position = i
+		if err := s.requestRepo.Create(ctx, request); err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		collection.Requests = append(collection.Requests, request)
+	}
+
+	return collection, nil
+}
+
+// contractRequest turns a contract test into a stored request
+func contractRequest(test codegen.ContractTest, collectionID int64) *models.Request {
+	raw := "{{baseUrl}}" + test.Path
+	if len(test.Query) > 0 {
+		query := url.Values{}
+		for name, value := range test.Query {
+			query.Set(name, value)
+		}
+		raw += "?" + query.Encode()
+	}
+
+	request := &models.Request{
+		CollectionID: collectionID,
+		Name:         test.Name,
+		Description:  test.Summary,
+		Method:       test.Method,
+		URL:          models.JSONMap{"raw": raw},
+	}
+
+	headers := make(map[string]string, len(test.Headers)+1)
+	for name, value := range test.Headers {
+		headers[name] = value
+	}
+
+	if test.Body != nil {
+		body, err := json.MarshalIndent(test.Body, "", "  ")
+		if err == nil {
+			headers["Content-Type"] = "application/json"
+			request.Body = models.JSONMap{
+				"mode": "raw",
+				"raw":  string(body),
+				"options": map[string]any{
+					"raw": map[string]any{"language": "json"},
+				},
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+
+	if test.Status != 0 {
+		request.Assertions = append(request.Assertions, models.Assertion{Type: models.AssertionStatusEquals, Equals: test.Status})
+	}
+	if test.JSON {
+		request.Assertions = append(request.Assertions, models.Assertion{Type: models.AssertionHeaderPresent, Header: "Content-Type"})
+	}
+	for _, name := range test.Required {
+		request.Assertions = append(request.Assertions, models.Assertion{Type: models.AssertionJSONPath, Path: codegen.JSONPathKey(name)})
+	}
+
+	if script := contractScript(test); len(script) > 0 {
+		request.Events = []models.PostmanEvent{{
+			Listen: "test",
+			Script: models.PostmanScript{Type: "text/javascript", Exec: script},
+		}}
+	}
+
+	return request
+}
+
+// contractScript renders the assertions of a contract test as a Postman test script
+func contractScript(test codegen.ContractTest) []string {
+	var lines []string
+	if test.Status != 0 {
+		lines = append(lines,
+			fmt.Sprintf("pm.test(\"status is %d\", function () {", test.Status),
+			fmt.Sprintf("    pm.response.to.have.status(%d);", test.Status),
+			"});",
+		)
+	}
+
+	if len(test.Required) > 0 {
+		quoted, _ := json.Marshal(test.Required)
+
+		lines = append(lines,
+			"pm.test(\"response has required properties\", function () {",
+			"    var body = pm.response.json();",
+			fmt.Sprintf("    %s.forEach(function (name) {", quoted),
+			"        pm.expect(body).to.have.property(name);",
+			"    });",
+			"});",
+		)
+	}
+
+	return lines
+}
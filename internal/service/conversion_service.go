@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"postman-api/internal/codegen"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/uptrace/bun"
+)
+
+// ConversionService turns OpenAPI specifications into Postman collections
+type ConversionService struct {
+	openAPIRepo    interfaces.OpenAPIRepository
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+	folderRepo     interfaces.FolderRepository
+	transactor     interfaces.Transactor
+}
+
+// NewConversionService creates a new conversion service
+func NewConversionService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	folderRepo interfaces.FolderRepository,
+	transactor interfaces.Transactor,
+) interfaces.ConversionService {
+	return &ConversionService{
+		openAPIRepo:    openAPIRepo,
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+		folderRepo:     folderRepo,
+		transactor:     transactor,
+	}
+}
+
+// withTx returns a copy of the service whose repositories run in tx
+func (s *ConversionService) withTx(tx bun.Tx) *ConversionService {
+	txs := *s
+	txs.collectionRepo = s.collectionRepo.WithTx(tx)
+	txs.requestRepo = s.requestRepo.WithTx(tx)
+	txs.folderRepo = s.folderRepo.WithTx(tx)
+	return &txs
+}
+
+// ConvertToCollection stores a collection with a folder per tag and a
+// request per operation of a spec. The server URL becomes the baseUrl
+// variable, with server and path parameters as collection variables.
+func (s *ConversionService) ConvertToCollection(ctx context.Context, specID int64) (*models.Collection, error) {
+	spec, err := s.openAPIRepo.GetByID(ctx, specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI spec: %w", err)
+	}
+
+	if spec.Content == nil {
+		return nil, models.NewValidationError("OpenAPI spec has no content")
+	}
+
+	items := codegen.CollectionItems(spec.Content)
+	if len(items) == 0 {
+		return nil, models.NewValidationError("OpenAPI spec %d has no operations", specID)
+	}
+
+	description := spec.Description
+	if description == "" {
+		description = fmt.Sprintf("Generated from %s %s", spec.Title, spec.Version)
+	}
+
+	collection := &models.Collection{
+		Name:        spec.Title,
+		Description: description,
+		Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		Variables:   convertedVariables(spec, items),
+		Metadata: withProvenance(models.JSONMap{
+			models.SourceSpecMetadataKey:     spec.ID,
+			models.SpecOperationsMetadataKey: operationKeys(items),
+		}, generatedProvenance(spec), nil),
+	}
+
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	tree := newFolderTree(collection.ID, nil)
+	for _, item := range items {
+		request := convertedRequest(item, collection.ID)
+		folder, err := tree.ensure(ctx, s.folderRepo, cleanFolderPath(request.FolderPath))
+		if err != nil {
+			return nil, err
+		}
+		if folder != nil {
+			request.FolderID, request.FolderPath = folder.ID, folder.Path
+		}
+		tree.appendRequest(request)
+
+		if err := s.requestRepo.Create(ctx, request); err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		collection.Requests = append(collection.Requests, request)
+	}
+
+	return collection, nil
+}
+
+// convertedVariables returns the collection variables of a spec: the server
+// URL as baseUrl, then server and path parameters with sample values
+func convertedVariables(spec *models.OpenAPISpec, items []codegen.CollectionItem) models.JSONMap {
+	baseURL, serverVariables := codegen.ServerTemplate(spec.Content)
+	variables := models.JSONMap{"baseUrl": baseURL}
+	for name, value := range serverVariables {
+		variables[name] = value
+	}
+	for _, item := range items {
+		for name, value := range item.PathParams {
+			if _, ok := variables[name]; !ok {
+				variables[name] = value
+			}
+		}
+	}
+	return variables
+}
+
+// convertedRequest turns a generated collection item into a stored request;
+// optional query parameters are kept disabled and optional headers left out
+func convertedRequest(item codegen.CollectionItem, collectionID int64) *models.Request {
+	raw := "{{baseUrl}}" + item.Path
+	required := url.Values{}
+	var query []any
+	for _, param := range item.Query {
+		entry := map[string]any{"key": param.Name, "value": param.Value}
+		if param.Description != "" {
+			entry["description"] = param.Description
+		}
+		if param.Required {
+			required.Set(param.Name, param.Value)
+		} else {
+			entry["disabled"] = true
+		}
+		query = append(query, entry)
+	}
+	if len(required) > 0 {
+		raw += "?" + required.Encode()
+	}
+
+	requestURL := models.JSONMap{"raw": raw}
+	if len(query) > 0 {
+		requestURL["query"] = query
+	}
+
+	request := &models.Request{
+		CollectionID: collectionID,
+		Name:         item.Name,
+		Description:  item.Description,
+		FolderPath:   item.Folder,
+		Method:       item.Method,
+		URL:          requestURL,
+	}
+
+	headers := map[string]string{}
+	for _, param := range item.Headers {
+		if param.Required {
+			headers[param.Name] = param.Value
+		}
+	}
+
+	if item.Body != nil {
+		body, err := json.MarshalIndent(item.Body, "", "  ")
+		if err == nil {
+			headers["Content-Type"] = "application/json"
+			request.Body = models.JSONMap{
+				"mode": "raw",
+				"raw":  string(body),
+				"options": map[string]any{
+					"raw": map[string]any{"language": "json"},
+				},
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+
+	return request
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/codegen"
+	"postman-api/internal/models"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// SyncFromSpec regenerates a collection from the current content of the spec
+// it was generated from. Requests of operations still in the spec are
+// regenerated in place and keep the headers, scripts, assertions and
+// examples added to them; operations new to the spec get a request in the
+// folder of their tag, and the requests of operations gone from it are
+// deleted. Requests users added themselves are left alone.
+func (s *ConversionService) SyncFromSpec(ctx context.Context, collectionID int64) (*models.SpecSyncReport, error) {
+	collection, err := editableCollection(ctx, s.collectionRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contract test collections record their spec too, but not the
+	// operations they were converted from
+	specID, ok := sourceSpecID(collection.Metadata)
+	previous, converted := recordedOperations(collection.Metadata)
+	if !ok || !converted {
+		return nil, models.NewValidationError("collection %d was not converted from an OpenAPI spec", collectionID)
+	}
+
+	spec, err := s.openAPIRepo.GetByID(ctx, specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI spec: %w", err)
+	}
+
+	if spec.Content == nil {
+		return nil, models.NewValidationError("OpenAPI spec has no content")
+	}
+
+	items := codegen.CollectionItems(spec.Content)
+	if len(items) == 0 {
+		return nil, models.NewValidationError("OpenAPI spec %d has no operations", specID)
+	}
+
+	report := &models.SpecSyncReport{
+		CollectionID: collectionID,
+		SpecID:       specID,
+		Added:        []models.SpecOperation{},
+		Removed:      []models.SpecOperation{},
+	}
+
+	err = s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		return s.withTx(tx).syncFromSpec(ctx, collection, spec, items, previous, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// syncFromSpec applies items to the requests of a collection last converted
+// from the previous operations
+func (s *ConversionService) syncFromSpec(ctx context.Context, collection *models.Collection, spec *models.OpenAPISpec, items []codegen.CollectionItem, previous map[string]bool, report *models.SpecSyncReport) error {
+	requests, err := s.allRequests(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+
+	current := map[string]codegen.CollectionItem{}
+	for _, item := range items {
+		current[operationKey(item.Method, item.Path)] = item
+	}
+
+	// The oldest request of an operation is the one kept up to date
+	existing := map[string]*models.Request{}
+	for _, request := range requests {
+		key := requestOperationKey(request)
+		if !previous[key] {
+			continue
+		}
+
+		item, ok := current[key]
+		if !ok {
+			if err := s.requestRepo.Delete(ctx, request.ID); err != nil {
+				return fmt.Errorf("failed to delete request: %w", err)
+			}
+			report.Removed = append(report.Removed, specOperation(request))
+			continue
+		}
+
+		if _, seen := existing[key]; seen {
+			continue
+		}
+		existing[key] = request
+
+		syncRequest(request, convertedRequest(item, collection.ID))
+		if err := s.requestRepo.Update(ctx, request); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+		report.Kept++
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collection.ID)
+	if err != nil {
+		return err
+	}
+	if err := tree.loadRequestPositions(ctx, s.requestRepo); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if _, ok := existing[operationKey(item.Method, item.Path)]; ok {
+			continue
+		}
+
+		request := convertedRequest(item, collection.ID)
+		folder, err := tree.ensure(ctx, s.folderRepo, cleanFolderPath(request.FolderPath))
+		if err != nil {
+			return err
+		}
+		if folder != nil {
+			request.FolderID, request.FolderPath = folder.ID, folder.Path
+		}
+		tree.appendRequest(request)
+
+		if err := s.requestRepo.Create(ctx, request); err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		report.Added = append(report.Added, specOperation(request))
+	}
+
+	// Variables added since are filled in; values users changed stay
+	if collection.Variables == nil {
+		collection.Variables = models.JSONMap{}
+	}
+	for name, value := range convertedVariables(spec, items) {
+		if _, ok := collection.Variables[name]; !ok {
+			collection.Variables[name] = value
+		}
+	}
+
+	metadata := models.JSONMap{}
+	for name, value := range collection.Metadata {
+		metadata[name] = value
+	}
+	metadata[models.SpecOperationsMetadataKey] = operationKeys(items)
+	collection.Metadata = metadata
+
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	return nil
+}
+
+// allRequests returns every request of a collection in the order they were added
+func (s *ConversionService) allRequests(ctx context.Context, collectionID int64) ([]*models.Request, error) {
+	var all []*models.Request
+	for offset := 0; ; offset += itemBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		all = append(all, requests...)
+
+		if len(requests) < itemBatchSize {
+			break
+		}
+	}
+
+	slices.SortFunc(all, func(a, b *models.Request) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return all, nil
+}
+
+// syncRequest regenerates a request from its operation. The headers the
+// spec defines take their generated values and other headers stay; scripts,
+// assertions, auth, presets, run settings and the folder are kept.
+func syncRequest(request, generated *models.Request) {
+	headers := map[string]string{}
+	for name, value := range request.Headers {
+		if _, ok := headerValue(generated.Headers, name); !ok {
+			headers[name] = value
+		}
+	}
+	for name, value := range generated.Headers {
+		headers[name] = value
+	}
+	if len(headers) == 0 {
+		headers = nil
+	}
+
+	request.Name = generated.Name
+	request.Description = generated.Description
+	request.Method = generated.Method
+	request.URL = generated.URL
+	request.Body = generated.Body
+	request.Headers = headers
+}
+
+// headerValue looks a header up by its case-insensitive name
+func headerValue(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// operationKeys returns the keys of the operations of items, sorted
+func operationKeys(items []codegen.CollectionItem) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, operationKey(item.Method, item.Path))
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// operationKey identifies an operation by its method and path template
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// requestOperationKey returns the key of the operation a generated request
+// was made from, taking the path from its URL
+func requestOperationKey(request *models.Request) string {
+	return operationKey(request.Method, requestOperationPath(request))
+}
+
+func requestOperationPath(request *models.Request) string {
+	raw, _ := request.URL["raw"].(string)
+	path, _, _ := strings.Cut(strings.TrimPrefix(raw, "{{baseUrl}}"), "?")
+	return path
+}
+
+func specOperation(request *models.Request) models.SpecOperation {
+	return models.SpecOperation{
+		Method:    strings.ToUpper(request.Method),
+		Path:      requestOperationPath(request),
+		Name:      request.Name,
+		RequestID: request.ID,
+	}
+}
+
+// recordedOperations returns the operations a collection was last converted
+// from, and whether it records them at all
+func recordedOperations(metadata models.JSONMap) (map[string]bool, bool) {
+	operations := map[string]bool{}
+	switch keys := metadata[models.SpecOperationsMetadataKey].(type) {
+	case []string:
+		for _, key := range keys {
+			operations[key] = true
+		}
+	case []any:
+		for _, key := range keys {
+			if key, ok := key.(string); ok {
+				operations[key] = true
+			}
+		}
+	default:
+		return nil, false
+	}
+	return operations, true
+}
+
+// sourceSpecID returns the ID of the spec a collection was generated from,
+// which reads back from JSON as a float64
+func sourceSpecID(metadata models.JSONMap) (int64, bool) {
+	switch id := metadata[models.SourceSpecMetadataKey].(type) {
+	case int64:
+		return id, true
+	case float64:
+		return int64(id), id > 0
+	case json.Number:
+		n, err := id.Int64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
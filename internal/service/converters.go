@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ConverterRegistry looks up collection converters by format name
+type ConverterRegistry struct {
+	mu         sync.RWMutex
+	converters []interfaces.Converter
+}
+
+// NewConverterRegistry creates an empty converter registry
+func NewConverterRegistry() interfaces.ConverterRegistry {
+	return &ConverterRegistry{}
+}
+
+// Register adds a converter, replacing any registered for the same format
+func (r *ConverterRegistry) Register(converter interfaces.Converter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := converter.Format().Name
+	r.converters = slices.DeleteFunc(r.converters, func(c interfaces.Converter) bool {
+		return c.Format().Name == name
+	})
+	r.converters = append(r.converters, converter)
+}
+
+// Converter returns the converter of the named format
+func (r *ConverterRegistry) Converter(name string) (interfaces.Converter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, converter := range r.converters {
+		if converter.Format().Name == name {
+			return converter, nil
+		}
+	}
+
+	return nil, models.NewValidationError("unknown format %q, expected one of: %s", name, strings.Join(r.names(), ", "))
+}
+
+// Detect returns the first converter, in the order they were registered,
+// that recognises data
+func (r *ConverterRegistry) Detect(data []byte) (interfaces.Converter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, converter := range r.converters {
+		if converter.Format().Import && converter.Detect(data) {
+			return converter, nil
+		}
+	}
+
+	return nil, models.NewValidationError("unrecognised document format, set format to one of: %s", strings.Join(r.names(), ", "))
+}
+
+// Formats describes the registered formats in the order they were registered
+func (r *ConverterRegistry) Formats() []models.Format {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	formats := make([]models.Format, len(r.converters))
+	for i, converter := range r.converters {
+		formats[i] = converter.Format()
+	}
+	return formats
+}
+
+func (r *ConverterRegistry) names() []string {
+	names := make([]string, len(r.converters))
+	for i, converter := range r.converters {
+		names[i] = converter.Format().Name
+	}
+	return names
+}
+
+// postmanConverter reads and writes Postman collection v2.1 JSON
+type postmanConverter struct {
+	collectionService interfaces.CollectionService
+}
+
+// PostmanConverter converts collections to and from Postman collection JSON
+func PostmanConverter(collectionService interfaces.CollectionService) interfaces.Converter {
+	return &postmanConverter{collectionService: collectionService}
+}
+
+func (c *postmanConverter) Format() models.Format {
+	return models.Format{
+		Name:        models.FormatPostman,
+		Description: "Postman collection v2.1 JSON",
+		Extension:   ".postman_collection.json",
+		ContentType: "application/json",
+		Import:      true,
+		Export:      true,
+		ExportKind:  models.ExportKindCollection,
+	}
+}
+
+// Detect recognises JSON objects
+func (c *postmanConverter) Detect(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func (c *postmanConverter) Import(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	return c.collectionService.ImportPostmanCollection(ctx, data, opts)
+}
+
+func (c *postmanConverter) Export(ctx context.Context, collectionID int64) ([]byte, error) {
+	return c.collectionService.ExportPostmanCollection(ctx, collectionID)
+}
+
+// bundleConverter reads and writes zip bundles of a collection with its attachments
+type bundleConverter struct {
+	attachmentService interfaces.AttachmentService
+}
+
+// BundleConverter converts collections to and from zip bundles that also
+// carry their attachments
+func BundleConverter(attachmentService interfaces.AttachmentService) interfaces.Converter {
+	return &bundleConverter{attachmentService: attachmentService}
+}
+
+func (c *bundleConverter) Format() models.Format {
+	return models.Format{
+		Name:        models.FormatBundle,
+		Description: "Zip bundle of a Postman collection and its attachments",
+		Extension:   ".postman_collection.zip",
+		ContentType: "application/zip",
+		Import:      true,
+		Export:      true,
+		ExportKind:  models.ExportKindCollectionBundle,
+	}
+}
+
+// Detect recognises zip archives
+func (c *bundleConverter) Detect(data []byte) bool {
+	return bytes.HasPrefix(data, zipMagic)
+}
+
+func (c *bundleConverter) Import(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	return c.attachmentService.ImportCollectionBundle(ctx, data, opts)
+}
+
+func (c *bundleConverter) Export(ctx context.Context, collectionID int64) ([]byte, error) {
+	return c.attachmentService.ExportCollectionBundle(ctx, collectionID)
+}
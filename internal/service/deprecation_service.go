@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+const deprecationBatchSize = 200
+
+// sunsetExtensions are the spec extensions read for an operation's sunset date, in order
+var sunsetExtensions = []string{"x-sunset", "x-sunset-date"}
+
+// openAPIMethods are the operation keys of an OpenAPI path item
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// DeprecationService reports deprecated operations and requests across the catalog
+type DeprecationService struct {
+	openAPIRepo interfaces.OpenAPIRepository
+	requestRepo interfaces.RequestRepository
+}
+
+// NewDeprecationService creates a new deprecation service
+func NewDeprecationService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	requestRepo interfaces.RequestRepository,
+) interfaces.DeprecationService {
+	return &DeprecationService{
+		openAPIRepo: openAPIRepo,
+		requestRepo: requestRepo,
+	}
+}
+
+// ListDeprecated lists spec operations marked deprecated and requests flagged
+// as deprecated, soonest sunset first
+func (s *DeprecationService) ListDeprecated(ctx context.Context, filter models.DeprecationFilter) ([]*models.DeprecatedEndpoint, error) {
+	endpoints := []*models.DeprecatedEndpoint{}
+
+	if filter.Source == "" || filter.Source == models.DeprecationSourceSpec {
+		found, err := s.deprecatedOperations(ctx, filter.SpecID)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, found...)
+	}
+
+	if filter.Source == "" || filter.Source == models.DeprecationSourceRequest {
+		found, err := s.deprecatedRequests(ctx, filter.CollectionID)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, found...)
+	}
+
+	if filter.SunsetBefore != nil {
+		kept := endpoints[:0]
+		for _, endpoint := range endpoints {
+			if endpoint.Sunset != nil && endpoint.Sunset.Before(*filter.SunsetBefore) {
+				kept = append(kept, endpoint)
+			}
+		}
+		endpoints = kept
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if (a.Sunset == nil) != (b.Sunset == nil) {
+			return a.Sunset != nil
+		}
+		if a.Sunset != nil && !a.Sunset.Equal(*b.Sunset) {
+			return a.Sunset.Before(*b.Sunset)
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	return endpoints, nil
+}
+
+// deprecatedOperations walks stored specs, or only specID when set
+func (s *DeprecationService) deprecatedOperations(ctx context.Context, specID int64) ([]*models.DeprecatedEndpoint, error) {
+	if specID != 0 {
+		spec, err := s.openAPIRepo.GetByID(ctx, specID)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAPI specification not found: %w", err)
+		}
+		return specDeprecations(spec), nil
+	}
+
+	var endpoints []*models.DeprecatedEndpoint
+	for offset := 0; ; offset += deprecationBatchSize {
+		specs, err := s.openAPIRepo.List(ctx, offset, deprecationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			endpoints = append(endpoints, specDeprecations(spec)...)
+		}
+
+		if len(specs) < deprecationBatchSize {
+			return endpoints, nil
+		}
+	}
+}
+
+// deprecatedRequests lists flagged requests, or only those of collectionID when set
+func (s *DeprecationService) deprecatedRequests(ctx context.Context, collectionID int64) ([]*models.DeprecatedEndpoint, error) {
+	var endpoints []*models.DeprecatedEndpoint
+	for offset := 0; ; offset += deprecationBatchSize {
+		requests, err := s.requestRepo.ListDeprecated(ctx, collectionID, offset, deprecationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, req := range requests {
+			raw, _ := req.URL["raw"].(string)
+			endpoints = append(endpoints, &models.DeprecatedEndpoint{
+				Source:       models.DeprecationSourceRequest,
+				Method:       strings.ToUpper(req.Method),
+				Path:         raw,
+				Name:         req.Name,
+				CollectionID: req.CollectionID,
+				RequestID:    req.ID,
+				Sunset:       req.Sunset,
+			})
+		}
+
+		if len(requests) < deprecationBatchSize {
+			return endpoints, nil
+		}
+	}
+}
+
+// specDeprecations returns the operations of a spec marked deprecated: true
+func specDeprecations(spec *models.OpenAPISpec) []*models.DeprecatedEndpoint {
+	paths, _ := spec.Content["paths"].(map[string]any)
+
+	var endpoints []*models.DeprecatedEndpoint
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if deprecated, _ := op["deprecated"].(bool); !deprecated {
+				continue
+			}
+
+			operationID, _ := op["operationId"].(string)
+			summary, _ := op["summary"].(string)
+			endpoints = append(endpoints, &models.DeprecatedEndpoint{
+				Source:      models.DeprecationSourceSpec,
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				Name:        summary,
+				SpecID:      spec.ID,
+				SpecTitle:   spec.Title,
+				OperationID: operationID,
+				Sunset:      sunsetOf(op, item),
+			})
+		}
+	}
+
+	return endpoints
+}
+
+// sunsetOf reads the sunset extension of an operation, falling back to its path item
+func sunsetOf(objects ...map[string]any) *time.Time {
+	for _, object := range objects {
+		for _, key := range sunsetExtensions {
+			if value, ok := object[key].(string); ok {
+				if sunset, ok := parseSunset(value); ok {
+					return &sunset
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseSunset accepts a date, an RFC 3339 timestamp or an HTTP date as used by the Sunset header
+func parseSunset(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.DateOnly, time.RFC3339, http.TimeFormat} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+	"time"
+)
+
+// DigestService sends digest subscriptions the changes to their collections
+// and specs, read from collection and spec revisions
+type DigestService struct {
+	digestRepo             interfaces.DigestRepository
+	collectionRepo         interfaces.CollectionRepository
+	collectionRevisionRepo interfaces.CollectionRevisionRepository
+	specRevisionRepo       interfaces.SpecRevisionRepository
+	openAPIService         interfaces.OpenAPIService
+	httpClient             *http.Client
+	smtp                   models.SMTPServer
+}
+
+// NewDigestService creates a new digest service; webhooks are called through
+// proxy and emails sent through smtpServer
+func NewDigestService(
+	digestRepo interfaces.DigestRepository,
+	collectionRepo interfaces.CollectionRepository,
+	collectionRevisionRepo interfaces.CollectionRevisionRepository,
+	specRevisionRepo interfaces.SpecRevisionRepository,
+	openAPIService interfaces.OpenAPIService,
+	proxy models.OutboundProxy,
+	smtpServer models.SMTPServer,
+) interfaces.DigestService {
+	return &DigestService{
+		digestRepo:             digestRepo,
+		collectionRepo:         collectionRepo,
+		collectionRevisionRepo: collectionRevisionRepo,
+		specRevisionRepo:       specRevisionRepo,
+		openAPIService:         openAPIService,
+		httpClient:             newFetchClient(proxy),
+		smtp:                   smtpServer,
+	}
+}
+
+// CreateDigestSubscription registers a new digest subscription; its first
+// digest covers the period from now
+func (s *DigestService) CreateDigestSubscription(ctx context.Context, subscription *models.DigestSubscription) error {
+	if err := s.validate(subscription); err != nil {
+		return err
+	}
+
+	return s.digestRepo.Create(ctx, subscription)
+}
+
+// GetDigestSubscription retrieves a digest subscription by ID
+func (s *DigestService) GetDigestSubscription(ctx context.Context, id int64) (*models.DigestSubscription, error) {
+	return s.digestRepo.GetByID(ctx, id)
+}
+
+// ListDigestSubscriptions returns all digest subscriptions with pagination
+func (s *DigestService) ListDigestSubscriptions(ctx context.Context, page, pageSize int) ([]*models.DigestSubscription, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	subscriptions, err := s.digestRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.digestRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, total, nil
+}
+
+// UpdateDigestSubscription updates the configuration of a subscription,
+// keeping the period its next digest covers
+func (s *DigestService) UpdateDigestSubscription(ctx context.Context, subscription *models.DigestSubscription) error {
+	existing, err := s.digestRepo.GetByID(ctx, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("digest subscription not found: %w", err)
+	}
+
+	if err := s.validate(subscription); err != nil {
+		return err
+	}
+
+	subscription.LastSentAt = existing.LastSentAt
+	subscription.LastError = existing.LastError
+	subscription.CreatedAt = existing.CreatedAt
+
+	return s.digestRepo.Update(ctx, subscription)
+}
+
+// DeleteDigestSubscription removes a digest subscription
+func (s *DigestService) DeleteDigestSubscription(ctx context.Context, id int64) error {
+	return s.digestRepo.Delete(ctx, id)
+}
+
+// PreviewDigest builds the digest a subscription would be sent now without
+// sending it
+func (s *DigestService) PreviewDigest(ctx context.Context, id int64) (*models.Digest, error) {
+	subscription, err := s.digestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("digest subscription not found: %w", err)
+	}
+
+	return s.build(ctx, subscription, time.Now())
+}
+
+// SendDigest sends a subscription its digest now, even when nothing changed,
+// and starts its next period
+func (s *DigestService) SendDigest(ctx context.Context, id int64) (*models.Digest, error) {
+	subscription, err := s.digestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("digest subscription not found: %w", err)
+	}
+
+	return s.send(ctx, subscription, true)
+}
+
+// SendDueDigests sends the digest of every enabled subscription whose period
+// has elapsed; periods without changes are skipped silently
+func (s *DigestService) SendDueDigests(ctx context.Context) error {
+	subscriptions, err := s.digestRepo.ListDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if _, err := s.send(ctx, subscription, false); err != nil {
+			log.Printf("Failed to send digest %d: %v", subscription.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// RunScheduler sends due digests every interval until ctx is cancelled
+func (s *DigestService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDueDigests(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to send digests: %v", err)
+			}
+		}
+	}
+}
+
+// send builds and delivers a digest; a failed delivery is kept on the
+// subscription and its period left open, to be retried
+func (s *DigestService) send(ctx context.Context, subscription *models.DigestSubscription, always bool) (*models.Digest, error) {
+	now := time.Now()
+	digest, err := s.build(ctx, subscription, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if always || !digest.Empty() {
+		if err := s.deliver(ctx, subscription, digest); err != nil {
+			subscription.LastError = err.Error()
+			if updateErr := s.digestRepo.Update(ctx, subscription); updateErr != nil {
+				return nil, updateErr
+			}
+			return nil, err
+		}
+	}
+
+	subscription.LastSentAt = &now
+	subscription.LastError = ""
+	if err := s.digestRepo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// build summarizes the revisions of a subscription's collections and specs
+// since its last digest. A spec is diffed from the content its first
+// revision in the period kept, which is the content the period started with.
+func (s *DigestService) build(ctx context.Context, subscription *models.DigestSubscription, until time.Time) (*models.Digest, error) {
+	since := subscription.CreatedAt
+	if subscription.LastSentAt != nil {
+		since = *subscription.LastSentAt
+	}
+
+	digest := &models.Digest{
+		SubscriptionID: subscription.ID,
+		Name:           subscription.Name,
+		Since:          since,
+		Until:          until,
+		Collections:    []models.DigestCollection{},
+		Specs:          []models.DigestSpec{},
+	}
+
+	for _, id := range subscription.CollectionIDs {
+		revisions, err := s.collectionRevisionRepo.ListSince(ctx, id, since, until)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			continue
+		}
+
+		last := revisions[len(revisions)-1]
+		entry := models.DigestCollection{
+			CollectionID: id,
+			Name:         last.Name,
+			Revisions:    len(revisions),
+			Deleted:      last.Action == models.RevisionDeleted,
+		}
+		if !entry.Deleted {
+			if collection, err := s.collectionRepo.GetByID(ctx, id); err == nil {
+				entry.Name = collection.Name
+			}
+		}
+		digest.Collections = append(digest.Collections, entry)
+	}
+
+	for _, id := range subscription.SpecIDs {
+		revisions, err := s.specRevisionRepo.ListSince(ctx, id, since, until)
+		if err != nil {
+			return nil, err
+		}
+		if len(revisions) == 0 {
+			continue
+		}
+
+		spec, err := s.openAPIService.GetOpenAPISpec(ctx, id)
+		if models.ErrorCodeOf(err) == models.ErrCodeNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		diff, err := s.openAPIService.DiffOpenAPISpec(ctx, id, revisions[0].Revision, 0)
+		if err != nil {
+			return nil, err
+		}
+		comparison, err := s.openAPIService.CompareOpenAPISpec(ctx, id, 0, revisions[0].Revision)
+		if err != nil {
+			return nil, err
+		}
+
+		digest.Specs = append(digest.Specs, models.DigestSpec{
+			SpecID:        id,
+			Title:         spec.Title,
+			Revisions:     len(revisions),
+			Diff:          diff,
+			BreakingCount: comparison.BreakingCount,
+		})
+	}
+
+	return digest, nil
+}
+
+// deliver posts a digest to a webhook as JSON or mails it as plain text
+func (s *DigestService) deliver(ctx context.Context, subscription *models.DigestSubscription, digest *models.Digest) error {
+	if subscription.Channel == models.DigestEmail {
+		return s.mail(subscription.Target, digest)
+	}
+
+	body, err := json.Marshal(digest)
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Target, bytes.NewReader(body))
+	if err != nil {
+		return models.NewValidationError("invalid webhook URL: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return &models.Error{Code: models.ErrCodeUpstream, Message: "failed to call digest webhook: " + err.Error(), Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return models.NewError(models.ErrCodeUpstream, fmt.Sprintf("digest webhook answered %s", resp.Status))
+	}
+
+	return nil
+}
+
+func (s *DigestService) mail(to string, digest *models.Digest) error {
+	if s.smtp.Addr == "" {
+		return models.NewError(models.ErrCodeUnavailable, "email digests are not configured")
+	}
+
+	address, err := mail.ParseAddress(to)
+	if err != nil {
+		return models.NewValidationError("invalid email address %q", to)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", address.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mimeHeader("Digest: "+digest.Name))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(digestText(digest), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		host, _, _ := net.SplitHostPort(s.smtp.Addr)
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, host)
+	}
+
+	if err := smtp.SendMail(s.smtp.Addr, auth, s.smtp.From, []string{address.Address}, []byte(msg.String())); err != nil {
+		return &models.Error{Code: models.ErrCodeUpstream, Message: "failed to send digest email: " + err.Error(), Err: err}
+	}
+
+	return nil
+}
+
+// digestText renders a digest for email
+func digestText(digest *models.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes from %s to %s\n", digest.Since.Format(time.RFC1123), digest.Until.Format(time.RFC1123))
+	if digest.Empty() {
+		b.WriteString("\nNothing changed.\n")
+		return b.String()
+	}
+
+	if len(digest.Collections) > 0 {
+		b.WriteString("\nCollections\n")
+		for _, c := range digest.Collections {
+			if c.Deleted {
+				fmt.Fprintf(&b, "- %s (#%d): deleted\n", c.Name, c.CollectionID)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s (#%d): %s\n", c.Name, c.CollectionID, plural(c.Revisions, "revision"))
+		}
+	}
+
+	if len(digest.Specs) > 0 {
+		b.WriteString("\nSpecs\n")
+		for _, spec := range digest.Specs {
+			fmt.Fprintf(&b, "- %s (#%d): %s", spec.Title, spec.SpecID, plural(spec.Revisions, "revision"))
+			if spec.Diff.FromVersion != spec.Diff.ToVersion {
+				fmt.Fprintf(&b, ", version %s to %s", spec.Diff.FromVersion, spec.Diff.ToVersion)
+			}
+			if spec.BreakingCount > 0 {
+				fmt.Fprintf(&b, ", %s", plural(spec.BreakingCount, "breaking change"))
+			}
+			b.WriteString("\n")
+			for _, entries := range []struct {
+				name string
+				diff models.DiffEntries
+			}{{"paths", spec.Diff.Paths}, {"operations", spec.Diff.Operations}, {"schemas", spec.Diff.Schemas}} {
+				if len(entries.diff.Added)+len(entries.diff.Removed)+len(entries.diff.Changed) == 0 {
+					continue
+				}
+				fmt.Fprintf(&b, "  %s: %d added, %d removed, %d changed\n", entries.name,
+					len(entries.diff.Added), len(entries.diff.Removed), len(entries.diff.Changed))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// mimeHeader encodes a header value that is not plain ASCII
+func mimeHeader(value string) string {
+	for _, r := range value {
+		if r > 127 || r < 32 {
+			return mime.QEncoding.Encode("utf-8", value)
+		}
+	}
+	return value
+}
+
+func (s *DigestService) validate(subscription *models.DigestSubscription) error {
+	var errs models.FieldErrors
+	validateName(&errs, "name", subscription.Name)
+
+	if subscription.Frequency != models.DigestDaily && subscription.Frequency != models.DigestWeekly {
+		errs.Add("frequency", "must be daily or weekly")
+	}
+
+	switch subscription.Channel {
+	case models.DigestWebhook:
+		u, err := url.Parse(subscription.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add("target", "must be an absolute http(s) URL")
+		}
+	case models.DigestEmail:
+		if s.smtp.Addr == "" {
+			errs.Add("channel", "email digests need SMTP_ADDR to be configured")
+		} else if _, err := mail.ParseAddress(subscription.Target); err != nil {
+			errs.Add("target", "must be an email address")
+		}
+	default:
+		errs.Add("channel", "must be webhook or email")
+	}
+
+	if len(subscription.CollectionIDs) == 0 && len(subscription.SpecIDs) == 0 {
+		errs.Add("collection_ids", "at least one collection or spec is required")
+	}
+	if subscription.CollectionIDs == nil {
+		subscription.CollectionIDs = []int64{}
+	}
+	if subscription.SpecIDs == nil {
+		subscription.SpecIDs = []int64{}
+	}
+
+	return errs.Err()
+}
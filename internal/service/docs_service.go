@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/codegen"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/variables"
+	"slices"
+	"strings"
+)
+
+// DocsService renders the published documentation of collections
+type DocsService struct {
+	collectionRepo     interfaces.CollectionRepository
+	requestRepo        interfaces.RequestRepository
+	folderRepo         interfaces.FolderRepository
+	presetRepo         interfaces.HeaderPresetRepository
+	environmentService interfaces.EnvironmentService
+	globalService      interfaces.GlobalVariableService
+}
+
+// NewDocsService creates a new docs service resolving requests the way the
+// flatten service does
+func NewDocsService(
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	folderRepo interfaces.FolderRepository,
+	presetRepo interfaces.HeaderPresetRepository,
+	environmentService interfaces.EnvironmentService,
+	globalService interfaces.GlobalVariableService,
+) interfaces.DocsService {
+	return &DocsService{
+		collectionRepo:     collectionRepo,
+		requestRepo:        requestRepo,
+		folderRepo:         folderRepo,
+		presetRepo:         presetRepo,
+		environmentService: environmentService,
+		globalService:      globalService,
+	}
+}
+
+// CollectionDocs renders the documentation of a collection as Markdown or
+// HTML: its variables, then every request in run order with its description,
+// code snippets and saved examples. Variables stay {{name}} references unless
+// environmentID is non-zero, in which case requests are resolved like
+// FlattenCollection does. Credentials are always shown as placeholders.
+func (s *DocsService) CollectionDocs(ctx context.Context, collectionID, environmentID int64, format string) ([]byte, error) {
+	if !codegen.ValidDocsFormat(format) {
+		return nil, models.NewValidationError("unsupported documentation format %q", format)
+	}
+
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	var scopes []variables.Scope
+	if environmentID != 0 {
+		if scopes, err = variableScopes(ctx, s.environmentService, s.globalService, collection, environmentID); err != nil {
+			return nil, err
+		}
+	}
+
+	requests, err := runOrder(ctx, s.folderRepo, s.requestRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := codegen.Docs{
+		Name:        collection.Name,
+		Description: collection.Description,
+		Variables:   docsVariables(collection.Variables),
+	}
+
+	stripper := &secretStripper{placeholders: map[string]bool{}}
+	collectionAuth := stripAuthMap(stripper, collection.Auth)
+	presets := newHeaderPresets(s.presetRepo)
+	for _, request := range requests {
+		applied, err := presets.apply(ctx, request, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		flat := flattenRequest(redactRequest(stripper, applied), collectionAuth, variables.New(scopes...))
+		// A URL starting with an unresolved variable, usually {{baseUrl}},
+		// carries its own scheme
+		if strings.HasPrefix(flat.URL, "http://{{") {
+			flat.URL = strings.TrimPrefix(flat.URL, "http://")
+		}
+
+		entry := codegen.DocsRequest{
+			Name:        request.Name,
+			Folder:      request.FolderPath,
+			Description: request.Description,
+			Request: codegen.SnippetRequest{
+				Method:  flat.Method,
+				URL:     flat.URL,
+				Headers: flat.Headers,
+				Body:    flat.Body,
+			},
+			Deprecated: request.Deprecated,
+		}
+		if request.Sunset != nil {
+			entry.Sunset = request.Sunset.Format("2006-01-02")
+		}
+
+		for _, resp := range request.Responses {
+			example := codegen.DocsExample{
+				Name:   resp.Name,
+				Code:   resp.Code,
+				Status: resp.Status,
+				Body:   resp.Body,
+			}
+			if resp.BodyRef != "" {
+				example.OmittedBytes = resp.BodySize
+			}
+			entry.Examples = append(entry.Examples, example)
+		}
+
+		docs.Requests = append(docs.Requests, entry)
+	}
+
+	return codegen.Documentation(docs, format)
+}
+
+// docsVariables lists collection variables by name, leaving out the values
+// of those that look like secrets
+func docsVariables(vars models.JSONMap) []codegen.DocsVariable {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	out := make([]codegen.DocsVariable, len(names))
+	for i, name := range names {
+		value := fmt.Sprint(vars[name])
+		if vars[name] == nil || (isSecretName(name) && !isPlaceholder(value)) {
+			value = ""
+		}
+		out[i] = codegen.DocsVariable{Name: name, Value: value}
+	}
+
+	return out
+}
+
+// redactRequest returns a copy of a request with the credentials of its auth
+// and headers replaced by {{placeholder}} references
+func redactRequest(stripper *secretStripper, request *models.Request) *models.Request {
+	redacted := *request
+	redacted.Auth = stripAuthMap(stripper, request.Auth)
+
+	if request.Headers != nil {
+		redacted.Headers = make(map[string]string, len(request.Headers))
+		for key, value := range request.Headers {
+			if isSecretName(key) {
+				value = stripper.placeholderFor(headerPlaceholder(key), value).(string)
+			}
+			redacted.Headers[key] = value
+		}
+	}
+
+	return &redacted
+}
+
+// stripAuthMap replaces the credential attributes of a stored auth object
+func stripAuthMap(stripper *secretStripper, auth models.JSONMap) models.JSONMap {
+	if auth == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(auth)
+	if err != nil {
+		return nil
+	}
+
+	var stripped models.JSONMap
+	if err := json.Unmarshal(stripper.stripAuth(raw), &stripped); err != nil {
+		return nil
+	}
+
+	return stripped
+}
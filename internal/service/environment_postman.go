@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/models"
+	"postman-api/internal/version"
+	"time"
+)
+
+// Postman variable scopes accepted on import
+const (
+	postmanScopeEnvironment = "environment"
+	postmanScopeGlobals     = "globals"
+)
+
+// ImportPostmanEnvironment imports a *.postman_environment.json document
+func (s *EnvironmentService) ImportPostmanEnvironment(ctx context.Context, data []byte, opts models.ImportOptions) (int64, error) {
+	var doc models.PostmanEnvironment
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, models.NewValidationError("invalid Postman environment format: %v", err)
+	}
+
+	if doc.Scope != "" && doc.Scope != postmanScopeEnvironment && doc.Scope != postmanScopeGlobals {
+		return 0, models.NewValidationError("unsupported variable scope %q", doc.Scope)
+	}
+
+	if doc.Name == "" {
+		return 0, models.NewValidationError("environment name is required")
+	}
+
+	env := &models.Environment{
+		Name:      doc.Name,
+		Variables: environmentVariablesFromPostman(doc.Values, opts.StripSecrets),
+		PostmanID: doc.ID,
+		Metadata:  opts.Metadata,
+	}
+
+	if err := s.CreateEnvironment(ctx, env); err != nil {
+		return 0, fmt.Errorf("failed to create environment: %w", err)
+	}
+
+	return env.ID, nil
+}
+
+// ExportPostmanEnvironment exports an environment to Postman format; secret
+// values are left empty unless includeSecrets is set
+func (s *EnvironmentService) ExportPostmanEnvironment(ctx context.Context, id int64, includeSecrets bool) ([]byte, error) {
+	env, err := s.environmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment: %w", err)
+	}
+
+	doc := models.PostmanEnvironment{
+		ID:            env.PostmanID,
+		Name:          env.Name,
+		Values:        make([]models.PostmanEnvironmentValue, 0, len(env.Variables)),
+		Scope:         postmanScopeEnvironment,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+		ExportedUsing: version.ServerHeader(),
+	}
+
+	for _, v := range env.Variables {
+		value := v.Value
+		if v.IsSecret() {
+			value = ""
+			if includeSecrets {
+				if s.cipher == nil {
+					return nil, models.ErrSecretsDisabled
+				}
+				if value, err = s.cipher.Decrypt(v.Value); err != nil {
+					return nil, fmt.Errorf("failed to decrypt variable %q: %w", v.Key, err)
+				}
+			}
+		}
+
+		enabled := !v.Disabled
+		doc.Values = append(doc.Values, models.PostmanEnvironmentValue{
+			Key:     v.Key,
+			Value:   value,
+			Type:    v.Type,
+			Enabled: &enabled,
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// environmentVariablesFromPostman converts Postman environment values,
+// keeping the last definition of a repeated key
+func environmentVariablesFromPostman(values []models.PostmanEnvironmentValue, stripSecrets bool) []models.EnvironmentVariable {
+	variables := []models.EnvironmentVariable{}
+	index := map[string]int{}
+
+	for _, value := range values {
+		if value.Key == "" {
+			continue
+		}
+
+		v := models.EnvironmentVariable{
+			Key:      value.Key,
+			Value:    postmanValueString(value.Value),
+			Type:     models.VariableTypeDefault,
+			Disabled: value.Enabled != nil && !*value.Enabled,
+		}
+
+		if value.Type == models.VariableTypeSecret {
+			v.Type = models.VariableTypeSecret
+			if stripSecrets {
+				v.Value = ""
+			}
+		}
+
+		if i, ok := index[v.Key]; ok {
+			variables[i] = v
+			continue
+		}
+		index[v.Key] = len(variables)
+		variables = append(variables, v)
+	}
+
+	return variables
+}
+
+// postmanValueString renders a variable value, which Postman may store as any JSON scalar
+func postmanValueString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/secrets"
+	"strings"
+	"time"
+)
+
+// EnvironmentService handles business logic for environments
+type EnvironmentService struct {
+	environmentRepo interfaces.EnvironmentRepository
+	cipher          *secrets.Cipher
+}
+
+// NewEnvironmentService creates a new environment service; without a cipher
+// secret variables are rejected
+func NewEnvironmentService(
+	environmentRepo interfaces.EnvironmentRepository,
+	cipher *secrets.Cipher,
+) interfaces.EnvironmentService {
+	return &EnvironmentService{
+		environmentRepo: environmentRepo,
+		cipher:          cipher,
+	}
+}
+
+// CreateEnvironment stores a new environment, encrypting its secret values
+func (s *EnvironmentService) CreateEnvironment(ctx context.Context, env *models.Environment) error {
+	var errs models.FieldErrors
+	validateHostOverrides(&errs, "host_overrides", env.HostOverrides)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if err := s.sealVariables(env, nil); err != nil {
+		return err
+	}
+
+	if err := s.environmentRepo.Create(ctx, env); err != nil {
+		return err
+	}
+
+	maskSecrets(env)
+	return nil
+}
+
+// GetEnvironment retrieves an environment by ID with secret values masked
+func (s *EnvironmentService) GetEnvironment(ctx context.Context, id int64) (*models.Environment, error) {
+	env, err := s.environmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	maskSecrets(env)
+	return env, nil
+}
+
+// ListEnvironments returns all environments with pagination and secret values masked
+func (s *EnvironmentService) ListEnvironments(ctx context.Context, page, pageSize int) ([]*models.Environment, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	envs, err := s.environmentRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.environmentRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, env := range envs {
+		maskSecrets(env)
+	}
+
+	return envs, total, nil
+}
+
+// UpdateEnvironment replaces the variables of an environment; a secret sent
+// back as the mask keeps its stored value
+func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, env *models.Environment) error {
+	var errs models.FieldErrors
+	validateHostOverrides(&errs, "host_overrides", env.HostOverrides)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	existing, err := s.environmentRepo.GetByID(ctx, env.ID)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+
+	if err := s.sealVariables(env, existing); err != nil {
+		return err
+	}
+
+	env.CreatedAt = existing.CreatedAt
+	if env.PostmanID == "" {
+		env.PostmanID = existing.PostmanID
+	}
+	if env.Metadata == nil {
+		env.Metadata = existing.Metadata
+	}
+	if env.HostOverrides == nil {
+		env.HostOverrides = existing.HostOverrides
+	}
+
+	if err := s.environmentRepo.Update(ctx, env); err != nil {
+		return err
+	}
+
+	maskSecrets(env)
+	return nil
+}
+
+// DeleteEnvironment removes an environment
+func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id int64) error {
+	return s.environmentRepo.Delete(ctx, id)
+}
+
+// ResolveEnvironment returns the enabled variables of an environment with
+// secrets decrypted, for use when executing requests
+func (s *EnvironmentService) ResolveEnvironment(ctx context.Context, id int64) (map[string]string, error) {
+	env, err := s.environmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(env.Variables))
+	for _, v := range env.Variables {
+		if v.Disabled {
+			continue
+		}
+
+		value := v.Value
+		if v.IsSecret() {
+			if s.cipher == nil {
+				return nil, models.ErrSecretsDisabled
+			}
+			if value, err = s.cipher.Decrypt(v.Value); err != nil {
+				return nil, fmt.Errorf("failed to decrypt variable %q: %w", v.Key, err)
+			}
+		}
+		values[v.Key] = value
+	}
+
+	return values, nil
+}
+
+// sealVariables validates the variables of env and encrypts secret values,
+// reusing the stored value of secrets sent back as the mask
+func (s *EnvironmentService) sealVariables(env *models.Environment, existing *models.Environment) error {
+	env.Name = strings.TrimSpace(env.Name)
+
+	stored := map[string]string{}
+	if existing != nil {
+		for _, v := range existing.Variables {
+			if v.IsSecret() {
+				stored[v.Key] = v.Value
+			}
+		}
+	}
+
+	var errs models.FieldErrors
+	validateName(&errs, "name", env.Name)
+
+	seen := map[string]bool{}
+	for i, v := range env.Variables {
+		path := fmt.Sprintf("variables[%d]", i)
+		switch {
+		case v.Key == "":
+			errs.Add(path+".key", "is required")
+		case seen[v.Key]:
+			errs.Add(path+".key", "%q is defined more than once", v.Key)
+		}
+		seen[v.Key] = true
+
+		if _, ok := stored[v.Key]; v.IsSecret() && v.Value == models.SecretMask && !ok {
+			errs.Add(path+".value", "secret variable %q has no stored value to keep", v.Key)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	for i := range env.Variables {
+		v := &env.Variables[i]
+		if v.Type == "" {
+			v.Type = models.VariableTypeDefault
+		}
+		if !v.IsSecret() {
+			continue
+		}
+
+		if v.Value == models.SecretMask {
+			v.Value = stored[v.Key]
+			continue
+		}
+
+		if s.cipher == nil {
+			return models.ErrSecretsDisabled
+		}
+
+		sealed, err := s.cipher.Encrypt(v.Value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt variable %q: %w", v.Key, err)
+		}
+		v.Value = sealed
+	}
+
+	if env.Variables == nil {
+		env.Variables = []models.EnvironmentVariable{}
+	}
+	env.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// maskSecrets replaces secret values with the mask before they leave the service
+func maskSecrets(env *models.Environment) {
+	for i := range env.Variables {
+		if env.Variables[i].IsSecret() {
+			env.Variables[i].Value = models.SecretMask
+		}
+	}
+}
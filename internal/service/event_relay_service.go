@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"postman-api/internal/events"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strconv"
+	"time"
+)
+
+// eventRelayBatchSize bounds how many outbox events one relay pass delivers
+const eventRelayBatchSize = 100
+
+// EventRelayService stores events in the outbox and relays them to an event
+// bus, so an event is delivered at least once even when the broker is down
+// at the time of the change
+type EventRelayService struct {
+	outboxRepo  interfaces.OutboxRepository
+	broker      events.Broker
+	topicPrefix string
+}
+
+// NewEventRelayService creates a relay delivering to broker; each entity type
+// gets its own topic, named topicPrefix.entity_type
+func NewEventRelayService(
+	outboxRepo interfaces.OutboxRepository,
+	broker events.Broker,
+	topicPrefix string,
+) interfaces.EventRelayService {
+	return &EventRelayService{
+		outboxRepo:  outboxRepo,
+		broker:      broker,
+		topicPrefix: topicPrefix,
+	}
+}
+
+// Publish stores the event in the outbox for the relay to deliver
+func (s *EventRelayService) Publish(ctx context.Context, event events.Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	return s.outboxRepo.Create(ctx, &models.OutboxEvent{
+		Type:       event.Type,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Payload:    event.Payload,
+		OccurredAt: event.OccurredAt,
+	})
+}
+
+// RunRelay delivers pending outbox events every interval until ctx is
+// cancelled, closing the broker on return
+func (s *EventRelayService) RunRelay(ctx context.Context, interval time.Duration) {
+	defer s.broker.Close()
+
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, err := s.relay(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to relay events: %v", err)
+			}
+			if delivered > 0 {
+				log.Printf("Relayed %d events", delivered)
+			}
+		}
+	}
+}
+
+// relay delivers pending events in order and stops at the first failure, so
+// later changes of an entity never overtake earlier ones
+func (s *EventRelayService) relay(ctx context.Context) (int, error) {
+	pending, err := s.outboxRepo.ListPending(ctx, eventRelayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, row := range pending {
+		data, err := json.Marshal(events.Event{
+			ID:         row.ID,
+			Type:       row.Type,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Payload:    row.Payload,
+			OccurredAt: row.OccurredAt,
+		})
+		if err != nil {
+			return i, fmt.Errorf("failed to encode event %d: %w", row.ID, err)
+		}
+
+		topic := s.topicPrefix + "." + row.EntityType
+		if err := s.broker.Send(ctx, topic, strconv.FormatInt(row.EntityID, 10), data); err != nil {
+			if markErr := s.outboxRepo.MarkFailed(ctx, row.ID, err.Error()); markErr != nil {
+				log.Printf("Failed to record delivery failure of event %d: %v", row.ID, markErr)
+			}
+			return i, fmt.Errorf("failed to deliver event %d: %w", row.ID, err)
+		}
+
+		if err := s.outboxRepo.MarkPublished(ctx, row.ID); err != nil {
+			return i, err
+		}
+	}
+
+	return len(pending), nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/storage"
+)
+
+// ExampleService handles business logic for the saved examples of requests
+type ExampleService struct {
+	exampleRepo    interfaces.ExampleRepository
+	requestRepo    interfaces.RequestRepository
+	collectionRepo interfaces.CollectionRepository
+	bodies         *responseBodies
+}
+
+// NewExampleService creates a new example service; example bodies are
+// stored according to policy, large ones in store
+func NewExampleService(
+	exampleRepo interfaces.ExampleRepository,
+	requestRepo interfaces.RequestRepository,
+	collectionRepo interfaces.CollectionRepository,
+	store storage.BlobStore,
+	policy models.ResponseBodyPolicy,
+) interfaces.ExampleService {
+	return &ExampleService{
+		exampleRepo:    exampleRepo,
+		requestRepo:    requestRepo,
+		collectionRepo: collectionRepo,
+		bodies:         &responseBodies{store: store, policy: policy},
+	}
+}
+
+// ListExamples returns the examples of a request in order
+func (s *ExampleService) ListExamples(ctx context.Context, requestID int64) ([]*models.Example, error) {
+	if _, err := s.requestRepo.GetByID(ctx, requestID); err != nil {
+		return nil, fmt.Errorf("request not found: %w", err)
+	}
+
+	return s.exampleRepo.ListByRequestID(ctx, requestID)
+}
+
+// GetExample retrieves an example of a request
+func (s *ExampleService) GetExample(ctx context.Context, requestID, id int64) (*models.Example, error) {
+	example, err := s.exampleRepo.GetByID(ctx, id)
+	if err != nil || example.RequestID != requestID {
+		return nil, models.NewNotFoundError(fmt.Sprintf("example %d of request %d", id, requestID), nil)
+	}
+
+	return example, nil
+}
+
+// CreateExample adds an example after the existing ones of a request,
+// dropping the oldest when the retention limit is exceeded
+func (s *ExampleService) CreateExample(ctx context.Context, requestID int64, example *models.Example) error {
+	if err := validateExample(example); err != nil {
+		return err
+	}
+
+	if err := s.editableRequest(ctx, requestID); err != nil {
+		return err
+	}
+
+	existing, err := s.exampleRepo.ListByRequestID(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	position := 0
+	if len(existing) > 0 {
+		position = existing[len(existing)-1].Position + 1
+	}
+
+	if err := s.storeBody(ctx, example); err != nil {
+		return err
+	}
+	example.ID = 0
+	example.RequestID = requestID
+	example.Position = position
+
+	if err := s.exampleRepo.Create(ctx, example); err != nil {
+		return fmt.Errorf("failed to create example: %w", err)
+	}
+
+	if retain := s.bodies.policy.Retain; retain > 0 && len(existing)+1 > retain {
+		for _, old := range existing[:len(existing)+1-retain] {
+			if err := s.exampleRepo.Delete(ctx, old.ID); err != nil {
+				return fmt.Errorf("failed to drop example %d: %w", old.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpdateExample replaces the content of an example, keeping its place
+func (s *ExampleService) UpdateExample(ctx context.Context, requestID, id int64, example *models.Example) error {
+	if err := validateExample(example); err != nil {
+		return err
+	}
+
+	if err := s.editableRequest(ctx, requestID); err != nil {
+		return err
+	}
+
+	existing, err := s.GetExample(ctx, requestID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storeBody(ctx, example); err != nil {
+		return err
+	}
+	example.ID = existing.ID
+	example.RequestID = existing.RequestID
+	example.Position = existing.Position
+	example.CreatedAt = existing.CreatedAt
+
+	return s.exampleRepo.Update(ctx, example)
+}
+
+// DeleteExample removes an example from a request
+func (s *ExampleService) DeleteExample(ctx context.Context, requestID, id int64) error {
+	if err := s.editableRequest(ctx, requestID); err != nil {
+		return err
+	}
+
+	if _, err := s.GetExample(ctx, requestID, id); err != nil {
+		return err
+	}
+
+	return s.exampleRepo.Delete(ctx, id)
+}
+
+// OpenExampleBody streams the body of an example, including bodies
+// offloaded to blob storage
+func (s *ExampleService) OpenExampleBody(ctx context.Context, requestID, id int64) (*models.Example, io.ReadCloser, error) {
+	example, err := s.GetExample(ctx, requestID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := example.Response()
+	body, err := s.bodies.open(ctx, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return example, body, nil
+}
+
+// storeBody applies the body policy to an example sent by a client; bodies
+// are always sent inline, so any offloaded reference given is dropped
+func (s *ExampleService) storeBody(ctx context.Context, example *models.Example) error {
+	example.BodyRef = ""
+	example.BodySize = 0
+	example.BodyTruncated = false
+
+	stored, err := s.bodies.offload(ctx, []models.PostmanResponse{example.Response()})
+	if err != nil {
+		return err
+	}
+
+	example.Body = stored[0].Body
+	example.BodyRef = stored[0].BodyRef
+	example.BodySize = stored[0].BodySize
+	example.BodyTruncated = stored[0].BodyTruncated
+
+	return nil
+}
+
+// editableRequest checks that a request exists and its collection is not archived
+func (s *ExampleService) editableRequest(ctx context.Context, requestID int64) error {
+	request, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return fmt.Errorf("request not found: %w", err)
+	}
+
+	_, err = editableCollection(ctx, s.collectionRepo, request.CollectionID)
+	return err
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"postman-api/internal/models"
+	"postman-api/internal/secrets"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const maxFetchedDocumentSize = 10 << 20
+
+// fetchTimeout bounds a single document download
+const fetchTimeout = 30 * time.Second
+
+// newFetchClient returns the client used to download documents; without a
+// configured proxy it honors the HTTP_PROXY family of environment variables
+func newFetchClient(proxy models.OutboundProxy) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy.URL != "" {
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxy.URL,
+			HTTPSProxy: proxy.URL,
+			NoProxy:    strings.Join(proxy.NoProxy, ","),
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	return &http.Client{Timeout: fetchTimeout, Transport: transport}
+}
+
+// fetchDocument downloads a remote JSON document, applying the given auth
+func fetchDocument(ctx context.Context, client *http.Client, url string, auth models.JSONMap) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, models.NewValidationError("invalid document URL: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	applyFetchAuth(req, auth)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &models.Error{Code: models.ErrCodeUpstream, Message: "failed to fetch document: " + err.Error(), Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, models.NewError(models.ErrCodeUpstream, fmt.Sprintf("failed to fetch document: unexpected status %s", resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchedDocumentSize+1))
+	if err != nil {
+		return nil, &models.Error{Code: models.ErrCodeUpstream, Message: "failed to read document: " + err.Error(), Err: err}
+	}
+
+	if len(data) > maxFetchedDocumentSize {
+		return nil, models.NewError(models.ErrCodeUpstream, fmt.Sprintf("document exceeds %d bytes", maxFetchedDocumentSize))
+	}
+
+	return data, nil
+}
+
+// fetchAuthSecrets maps the auth types of document fetches to the attribute
+// holding their credential, which is encrypted whenever the auth is stored
+var fetchAuthSecrets = map[string]string{
+	"bearer": "token",
+	"basic":  "password",
+	"header": "value",
+}
+
+// sealFetchAuth returns a copy of auth with its credential encrypted, or nil
+// for empty auth; credentials cannot be stored without a cipher
+func sealFetchAuth(cipher *secrets.Cipher, auth models.JSONMap) (models.JSONMap, error) {
+	if len(auth) == 0 {
+		return nil, nil
+	}
+
+	if cipher == nil {
+		return nil, models.ErrCredentialsDisabled
+	}
+
+	sealed := models.JSONMap{}
+	maps.Copy(sealed, auth)
+
+	key := fetchAuthSecrets[fmt.Sprint(auth["type"])]
+	value, _ := auth[key].(string)
+
+	encrypted, err := cipher.Encrypt(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+	sealed[key] = encrypted
+
+	return sealed, nil
+}
+
+// openFetchAuth returns a copy of stored auth with its credential decrypted,
+// passing through values stored before credentials were encrypted
+func openFetchAuth(cipher *secrets.Cipher, auth models.JSONMap) (models.JSONMap, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	opened := models.JSONMap{}
+	maps.Copy(opened, auth)
+
+	key := fetchAuthSecrets[fmt.Sprint(auth["type"])]
+	value, _ := auth[key].(string)
+	if !secrets.IsEncrypted(value) {
+		return opened, nil
+	}
+
+	if cipher == nil {
+		return nil, models.ErrCredentialsDisabled
+	}
+
+	plaintext, err := cipher.Decrypt(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+	opened[key] = plaintext
+
+	return opened, nil
+}
+
+// describeFetchAuth describes stored auth without revealing its credential,
+// or returns nil when there is none
+func describeFetchAuth(auth models.JSONMap) *models.CredentialStatus {
+	if len(auth) == 0 {
+		return nil
+	}
+
+	authType, _ := auth["type"].(string)
+	return &models.CredentialStatus{Type: authType, Configured: true}
+}
+
+// applyFetchAuth sets credentials described by a bearer, basic or header auth map
+func applyFetchAuth(req *http.Request, auth models.JSONMap) {
+	if auth == nil {
+		return
+	}
+
+	str := func(key string) string {
+		v, _ := auth[key].(string)
+		return v
+	}
+
+	switch str("type") {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+str("token"))
+	case "basic":
+		credentials := base64.StdEncoding.EncodeToString([]byte(str("username") + ":" + str("password")))
+		req.Header.Set("Authorization", "Basic "+credentials)
+	case "header":
+		req.Header.Set(str("name"), str("value"))
+	}
+}
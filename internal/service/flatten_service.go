@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/variables"
+	"strings"
+)
+
+// FlattenService resolves the requests of a collection into plain HTTP requests
+type FlattenService struct {
+	collectionRepo     interfaces.CollectionRepository
+	requestRepo        interfaces.RequestRepository
+	presetRepo         interfaces.HeaderPresetRepository
+	environmentService interfaces.EnvironmentService
+	globalService      interfaces.GlobalVariableService
+}
+
+// NewFlattenService creates a new flatten service; the globals of
+// globalService are the variables of lowest precedence, shared by every collection
+func NewFlattenService(
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	presetRepo interfaces.HeaderPresetRepository,
+	environmentService interfaces.EnvironmentService,
+	globalService interfaces.GlobalVariableService,
+) interfaces.FlattenService {
+	return &FlattenService{
+		collectionRepo:     collectionRepo,
+		requestRepo:        requestRepo,
+		presetRepo:         presetRepo,
+		environmentService: environmentService,
+		globalService:      globalService,
+	}
+}
+
+// FlattenCollection returns every request of a collection with its method,
+// absolute URL, headers and body resolved. Variables come from the globals,
+// the collection and, when environmentID is non-zero, the environment, each
+// taking precedence over the one before. Requests without auth inherit the
+// collection's, and get the headers of their presets beneath their own.
+func (s *FlattenService) FlattenCollection(ctx context.Context, collectionID, environmentID int64) ([]*models.FlatRequest, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	scopes, err := variableScopes(ctx, s.environmentService, s.globalService, collection, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	presets := newHeaderPresets(s.presetRepo)
+	flat := []*models.FlatRequest{}
+	for offset := 0; ; offset += itemBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		for _, req := range requests {
+			if req, err = presets.apply(ctx, req, nil); err != nil {
+				return nil, err
+			}
+			flat = append(flat, flattenRequest(req, collection.Auth, variables.New(scopes...)))
+		}
+
+		if len(requests) < itemBatchSize {
+			break
+		}
+	}
+
+	return flat, nil
+}
+
+// ResolveRequest previews a request the way FlattenCollection resolves it,
+// along with its params and the variables used
+func (s *FlattenService) ResolveRequest(ctx context.Context, requestID, environmentID int64) (*models.ResolvedRequest, error) {
+	request, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionRepo.GetByID(ctx, request.CollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	scopes, err := variableScopes(ctx, s.environmentService, s.globalService, collection, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request, err = newHeaderPresets(s.presetRepo).apply(ctx, request, nil); err != nil {
+		return nil, err
+	}
+
+	resolver := variables.New(scopes...)
+	resolved := &models.ResolvedRequest{}
+	if request.Params != nil {
+		resolved.Params, _ = resolver.Value(map[string]any(request.Params)).(map[string]any)
+	}
+	resolved.FlatRequest = *flattenRequest(request, collection.Auth, resolver)
+	resolved.Variables = resolver.Used()
+
+	return resolved, nil
+}
+
+// variableScopes returns the variable scopes of a collection in order of
+// precedence: the globals, the collection and, when environmentID is
+// non-zero, the environment
+func variableScopes(
+	ctx context.Context,
+	environmentService interfaces.EnvironmentService,
+	globalService interfaces.GlobalVariableService,
+	collection *models.Collection,
+	environmentID int64,
+) ([]variables.Scope, error) {
+	collectionValues := make(map[string]string, len(collection.Variables))
+	for key, value := range collection.Variables {
+		collectionValues[key] = fmt.Sprint(value)
+	}
+
+	globals, err := globalService.ResolveGlobals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve globals: %w", err)
+	}
+
+	scopes := []variables.Scope{
+		{Name: variables.ScopeGlobal, Values: globals},
+		{Name: variables.ScopeCollection, Values: collectionValues},
+	}
+
+	if environmentID != 0 {
+		envValues, err := environmentService.ResolveEnvironment(ctx, environmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve environment: %w", err)
+		}
+		scopes = append(scopes, variables.Scope{Name: variables.ScopeEnvironment, Values: envValues})
+	}
+
+	return scopes, nil
+}
+
+// flattenRequest resolves a single request with resolver
+func flattenRequest(req *models.Request, collectionAuth models.JSONMap, resolver *variables.Resolver) *models.FlatRequest {
+	resolve := resolver.String
+
+	flat := &models.FlatRequest{
+		RequestID:  req.ID,
+		Name:       req.Name,
+		FolderPath: req.FolderPath,
+		Method:     strings.ToUpper(req.Method),
+		Headers:    map[string]string{},
+	}
+	if flat.Method == "" {
+		flat.Method = "GET"
+	}
+
+	for key, value := range req.Headers {
+		flat.Headers[resolve(key)] = resolve(value)
+	}
+
+	query := url.Values{}
+	auth := req.Auth
+	if auth == nil || authType(auth) == "inherit" {
+		auth = collectionAuth
+	}
+	applyRequestAuth(auth, resolve, flat.Headers, query)
+
+	flat.URL = absoluteURL(resolve(rawRequestURL(req.URL)), query)
+	flat.Body = requestBody(req.Body, resolve, flat.Headers)
+
+	if len(flat.Headers) == 0 {
+		flat.Headers = nil
+	}
+	flat.Unresolved = resolver.Unresolved()
+
+	return flat
+}
+
+// rawRequestURL returns the raw form of a stored Postman request URL,
+// rebuilding it from its parts when raw is missing
+func rawRequestURL(u models.JSONMap) string {
+	if raw, ok := u["raw"].(string); ok && raw != "" {
+		return raw
+	}
+
+	host, path := requestHostPath(u)
+	raw := host + path
+	if protocol, ok := u["protocol"].(string); ok && protocol != "" {
+		raw = protocol + "://" + raw
+	}
+
+	if params, ok := u["query"].([]any); ok {
+		var pairs []string
+		for _, param := range params {
+			p, ok := param.(map[string]any)
+			if !ok || p["disabled"] == true {
+				continue
+			}
+			key, _ := p["key"].(string)
+			value, _ := p["value"].(string)
+			pairs = append(pairs, key+"="+value)
+		}
+		if len(pairs) > 0 {
+			raw += "?" + strings.Join(pairs, "&")
+		}
+	}
+
+	return raw
+}
+
+// absoluteURL adds the default scheme to a URL without one, as Postman does,
+// and appends query
+func absoluteURL(raw string, query url.Values) string {
+	raw = strings.TrimSpace(raw)
+	if raw != "" && !strings.Contains(raw, "://") {
+		raw = "http://" + strings.TrimPrefix(raw, "//")
+	}
+
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(raw, "?") {
+			separator = "&"
+		}
+		raw += separator + query.Encode()
+	}
+
+	return raw
+}
+
+// authType returns the type of a Postman auth object
+func authType(auth models.JSONMap) string {
+	t, _ := auth["type"].(string)
+	return t
+}
+
+// authParams returns the parameters of a Postman auth object, stored either
+// as a list of key/value pairs or as a map
+func authParams(auth models.JSONMap) map[string]string {
+	params := map[string]string{}
+	switch v := auth[authType(auth)].(type) {
+	case []any:
+		for _, entry := range v {
+			if kv, ok := entry.(map[string]any); ok {
+				key, _ := kv["key"].(string)
+				params[key] = fmt.Sprint(kv["value"])
+			}
+		}
+	case map[string]any:
+		for key, value := range v {
+			params[key] = fmt.Sprint(value)
+		}
+	}
+	return params
+}
+
+// applyRequestAuth adds the credentials of a bearer, basic or API key auth
+// object to headers or query; other auth types are left out
+func applyRequestAuth(auth models.JSONMap, resolve func(string) string, headers map[string]string, query url.Values) {
+	if auth == nil {
+		return
+	}
+
+	params := authParams(auth)
+	switch authType(auth) {
+	case "bearer":
+		headers["Authorization"] = "Bearer " + resolve(params["token"])
+	case "basic":
+		credentials := resolve(params["username"]) + ":" + resolve(params["password"])
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	case "apikey":
+		key, value := resolve(params["key"]), resolve(params["value"])
+		if params["in"] == "query" {
+			query.Set(key, value)
+		} else {
+			headers[key] = value
+		}
+	}
+}
+
+// requestBody renders a raw, urlencoded or GraphQL Postman body and sets its
+// Content-Type when the request has none; form-data and file bodies are left out
+func requestBody(stored models.JSONMap, resolve func(string) string, headers map[string]string) string {
+	if stored == nil {
+		return ""
+	}
+
+	var body models.PostmanBody
+	data, _ := json.Marshal(stored)
+	if err := json.Unmarshal(data, &body); err != nil {
+		return ""
+	}
+
+	var rendered, contentType string
+	switch body.Mode {
+	case "raw":
+		rendered = resolve(body.Raw)
+		var options struct {
+			Raw struct {
+				Language string `json:"language"`
+			} `json:"raw"`
+		}
+		json.Unmarshal(body.Options, &options)
+		switch options.Raw.Language {
+		case "json":
+			contentType = "application/json"
+		case "xml":
+			contentType = "application/xml"
+		default:
+			contentType = "text/plain"
+		}
+	case "urlencoded":
+		form := url.Values{}
+		for _, kv := range body.URLEncoded {
+			if !kv.Disabled {
+				form.Add(resolve(kv.Key), resolve(kv.Value))
+			}
+		}
+		rendered = form.Encode()
+		contentType = "application/x-www-form-urlencoded"
+	case "graphql":
+		rendered = resolve(string(body.GraphQL))
+		contentType = "application/json"
+	default:
+		return ""
+	}
+
+	if !hasHeader(headers, "Content-Type") {
+		headers["Content-Type"] = contentType
+	}
+
+	return rendered
+}
+
+// hasHeader reports whether headers contain name, ignoring case
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// maxFolderDepth bounds how deeply folders may be nested, which also stops
+// a corrupt parent chain from being followed forever
+const maxFolderDepth = 64
+
+// FolderService handles business logic for the folders of a collection
+type FolderService struct {
+	folderRepo     interfaces.FolderRepository
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+	transactor     interfaces.Transactor
+}
+
+// NewFolderService creates a new folder service
+func NewFolderService(
+	folderRepo interfaces.FolderRepository,
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	transactor interfaces.Transactor,
+) interfaces.FolderService {
+	return &FolderService{
+		folderRepo:     folderRepo,
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+		transactor:     transactor,
+	}
+}
+
+// withTx returns a copy of the service whose repositories run in tx
+func (s *FolderService) withTx(tx bun.Tx) *FolderService {
+	txs := *s
+	txs.folderRepo = s.folderRepo.WithTx(tx)
+	txs.collectionRepo = s.collectionRepo.WithTx(tx)
+	txs.requestRepo = s.requestRepo.WithTx(tx)
+	return &txs
+}
+
+// ListFolders returns every folder of a collection, each followed by its
+// subfolders, in position order
+func (s *FolderService) ListFolders(ctx context.Context, collectionID int64) ([]*models.Folder, error) {
+	if _, err := s.collectionRepo.GetByID(ctx, collectionID); err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []*models.Folder{}
+	var walk func(parentID int64)
+	walk = func(parentID int64) {
+		for _, folder := range tree.children[parentID] {
+			folders = append(folders, folder)
+			walk(folder.ID)
+		}
+	}
+	walk(0)
+
+	return folders, nil
+}
+
+// GetFolder retrieves a folder of a collection with its path
+func (s *FolderService) GetFolder(ctx context.Context, collectionID, id int64) (*models.Folder, error) {
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.get(id)
+}
+
+// CreateFolder adds a folder to a collection, at the end of its parent
+// unless a position is given
+func (s *FolderService) CreateFolder(ctx context.Context, collectionID int64, input *models.FolderInput) (*models.Folder, error) {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return nil, err
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &models.Folder{CollectionID: collectionID}
+	if input.Name != nil {
+		folder.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		folder.Description = *input.Description
+	}
+	if input.ParentID != nil {
+		folder.ParentID = *input.ParentID
+	}
+	if input.Position != nil {
+		folder.Position = *input.Position
+	} else {
+		if err := tree.loadRequestPositions(ctx, s.requestRepo); err != nil {
+			return nil, err
+		}
+		folder.Position = tree.nextPosition(folder.ParentID)
+	}
+
+	if err := tree.validate(folder); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Create(ctx, folder); err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+	tree.add(folder)
+
+	return folder, nil
+}
+
+// UpdateFolder renames, moves or repositions a folder; the requests in it
+// and its subfolders follow it to its new path in the same transaction
+func (s *FolderService) UpdateFolder(ctx context.Context, collectionID, id int64, input *models.FolderInput) (*models.Folder, error) {
+	var updated *models.Folder
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		updated, err = s.withTx(tx).updateFolder(ctx, collectionID, id, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *FolderService) updateFolder(ctx context.Context, collectionID, id int64, input *models.FolderInput) (*models.Folder, error) {
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return nil, err
+	}
+
+	tree, err := loadFolderTree(ctx, s.folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := tree.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := *existing
+	if input.Name != nil {
+		folder.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		folder.Description = *input.Description
+	}
+	if input.ParentID != nil {
+		folder.ParentID = *input.ParentID
+	}
+	if input.Position != nil {
+		folder.Position = *input.Position
+	}
+
+	if err := tree.validate(&folder); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.Update(ctx, &folder); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	moved := folder.Name != existing.Name || folder.ParentID != existing.ParentID
+	tree.replace(&folder)
+	if moved {
+		if _, err := syncFolderPaths(ctx, s.requestRepo, tree, folder.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &folder, nil
+}
+
+// DeleteFolder removes a folder with its subfolders and every request in
+// them in one transaction, returning the number of requests deleted
+func (s *FolderService) DeleteFolder(ctx context.Context, collectionID, id int64) (int, error) {
+	deleted := 0
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+		if _, err := editableCollection(ctx, txs.collectionRepo, collectionID); err != nil {
+			return err
+		}
+
+		tree, err := loadFolderTree(ctx, txs.folderRepo, collectionID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tree.get(id); err != nil {
+			return err
+		}
+
+		var folderIDs []int64
+		for _, folder := range tree.subtree(id) {
+			folderIDs = append(folderIDs, folder.ID)
+		}
+
+		// Requests are deleted in one statement, rather than through the
+		// foreign key, so that the request count and their deletion events
+		// are kept like for any other
+		requestIDs, err := txs.requestRepo.DeleteByFolderIDs(ctx, collectionID, folderIDs)
+		if err != nil {
+			return fmt.Errorf("failed to delete requests in folder: %w", err)
+		}
+		deleted = len(requestIDs)
+
+		return txs.folderRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// folderTree is the folder hierarchy of a collection
+type folderTree struct {
+	collectionID int64
+	folders      map[int64]*models.Folder
+	// children holds the folders of each parent in position order; top-level
+	// folders are under 0
+	children map[int64][]*models.Folder
+	// requestPositions holds the position after the last request of each
+	// parent, see loadRequestPositions
+	requestPositions map[int64]int
+}
+
+// loadFolderTree loads the folders of a collection with their paths
+func loadFolderTree(ctx context.Context, folderRepo interfaces.FolderRepository, collectionID int64) (*folderTree, error) {
+	folders, err := folderRepo.ListByCollectionID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	return newFolderTree(collectionID, folders), nil
+}
+
+// newFolderTree builds the hierarchy of folders, given in position order
+func newFolderTree(collectionID int64, folders []*models.Folder) *folderTree {
+	tree := &folderTree{
+		collectionID:     collectionID,
+		folders:          make(map[int64]*models.Folder, len(folders)),
+		children:         map[int64][]*models.Folder{},
+		requestPositions: map[int64]int{},
+	}
+	for _, folder := range folders {
+		tree.folders[folder.ID] = folder
+		tree.children[folder.ParentID] = append(tree.children[folder.ParentID], folder)
+	}
+	for _, folder := range folders {
+		folder.Path = tree.path(folder)
+	}
+
+	return tree
+}
+
+// loadRequestPositions makes nextPosition account for the requests of the
+// collection, which share positions with the folders beside them
+func (t *folderTree) loadRequestPositions(ctx context.Context, requestRepo interfaces.RequestRepository) error {
+	positions, err := requestRepo.NextPositions(ctx, t.collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get request positions: %w", err)
+	}
+
+	t.requestPositions = positions
+	return nil
+}
+
+// get returns a folder of the collection
+func (t *folderTree) get(id int64) (*models.Folder, error) {
+	folder, ok := t.folders[id]
+	if !ok {
+		return nil, models.NewNotFoundError(fmt.Sprintf("folder %d in collection %d", id, t.collectionID), nil)
+	}
+	return folder, nil
+}
+
+// path joins the names of a folder and its ancestors
+func (t *folderTree) path(folder *models.Folder) string {
+	names := []string{folder.Name}
+	for parent := t.folders[folder.ParentID]; parent != nil && len(names) < maxFolderDepth; parent = t.folders[parent.ParentID] {
+		names = append(names, parent.Name)
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, "/")
+}
+
+// find returns the first folder at a "a/b/c" path, or nil
+func (t *folderTree) find(path string) *models.Folder {
+	var found *models.Folder
+	var parentID int64
+	for _, name := range strings.Split(path, "/") {
+		if found = t.child(parentID, name); found == nil {
+			return nil
+		}
+		parentID = found.ID
+	}
+	return found
+}
+
+// child returns the first folder of a parent with the given name, or nil
+func (t *folderTree) child(parentID int64, name string) *models.Folder {
+	for _, child := range t.children[parentID] {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// ensure returns the folder at a clean "a/b/c" path, creating the folders
+// missing along it; it returns nil for the empty path of the top level
+func (t *folderTree) ensure(ctx context.Context, folderRepo interfaces.FolderRepository, path string) (*models.Folder, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	var parent *models.Folder
+	for _, name := range strings.Split(path, "/") {
+		var parentID int64
+		if parent != nil {
+			parentID = parent.ID
+		}
+
+		next := t.child(parentID, name)
+		if next == nil {
+			next = &models.Folder{
+				CollectionID: t.collectionID,
+				ParentID:     parentID,
+				Name:         name,
+				Position:     t.nextPosition(parentID),
+			}
+			if err := folderRepo.Create(ctx, next); err != nil {
+				return nil, fmt.Errorf("failed to create folder: %w", err)
+			}
+			t.add(next)
+		}
+		parent = next
+	}
+
+	return parent, nil
+}
+
+// add inserts a created folder into the tree and sets its path
+func (t *folderTree) add(folder *models.Folder) {
+	t.folders[folder.ID] = folder
+	t.children[folder.ParentID] = append(t.children[folder.ParentID], folder)
+	folder.Path = t.path(folder)
+}
+
+// replace swaps in an updated folder and refreshes the paths beneath it
+func (t *folderTree) replace(folder *models.Folder) {
+	old := t.folders[folder.ID]
+	siblings := t.children[old.ParentID]
+	for i, sibling := range siblings {
+		if sibling.ID == folder.ID {
+			t.children[old.ParentID] = append(siblings[:i:i], siblings[i+1:]...)
+			break
+		}
+	}
+
+	t.folders[folder.ID] = folder
+	t.children[folder.ParentID] = append(t.children[folder.ParentID], folder)
+	for _, f := range t.subtree(folder.ID) {
+		f.Path = t.path(f)
+	}
+}
+
+// subtree returns a folder followed by every folder beneath it
+func (t *folderTree) subtree(id int64) []*models.Folder {
+	folders := []*models.Folder{t.folders[id]}
+	for i := 0; i < len(folders); i++ {
+		folders = append(folders, t.children[folders[i].ID]...)
+	}
+	return folders
+}
+
+// nextPosition returns the position after the last folder or request of a parent
+func (t *folderTree) nextPosition(parentID int64) int {
+	position := t.requestPositions[parentID]
+	for _, child := range t.children[parentID] {
+		position = max(position, child.Position+1)
+	}
+	return position
+}
+
+// eachChild visits the requests of a folder, given in position order, and its
+// subfolders interleaved by position; requests come first among items at the
+// same position
+func (t *folderTree) eachChild(folderID int64, requests []*models.Request, onRequest func(*models.Request), onFolder func(*models.Folder)) {
+	folders := t.children[folderID]
+	for len(requests) > 0 || len(folders) > 0 {
+		if len(folders) == 0 || (len(requests) > 0 && requests[0].Position <= folders[0].Position) {
+			onRequest(requests[0])
+			requests = requests[1:]
+			continue
+		}
+
+		onFolder(folders[0])
+		folders = folders[1:]
+	}
+}
+
+// appendRequest places a new request after the last item of its folder
+func (t *folderTree) appendRequest(request *models.Request) {
+	request.Position = t.nextPosition(request.FolderID)
+	t.requestPositions[request.FolderID] = request.Position + 1
+}
+
+// validate checks the name and parent of a new or changed folder: the parent
+// must be in the same collection and not the folder or one beneath it, and
+// siblings must have distinct names
+func (t *folderTree) validate(folder *models.Folder) error {
+	var errs models.FieldErrors
+	validateName(&errs, "name", folder.Name)
+	if strings.Contains(folder.Name, "/") {
+		errs.Add("name", "must not contain /")
+	}
+	if folder.Position < 0 {
+		errs.Add("position", "must not be negative")
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	depth := 1
+	for parentID := folder.ParentID; parentID != 0; depth++ {
+		parent, ok := t.folders[parentID]
+		if !ok {
+			return models.NewValidationError("parent folder %d is not in collection %d", folder.ParentID, t.collectionID)
+		}
+		if parent.ID == folder.ID {
+			return models.NewValidationError("a folder cannot be moved into itself or its own subfolder")
+		}
+		if depth >= maxFolderDepth {
+			return models.NewValidationError("folders cannot be nested more than %d deep", maxFolderDepth)
+		}
+		parentID = parent.ParentID
+	}
+
+	for _, sibling := range t.children[folder.ParentID] {
+		if sibling.ID != folder.ID && sibling.Name == folder.Name {
+			return models.NewConflictError(fmt.Sprintf("folder %q already exists", t.path(folder)), nil)
+		}
+	}
+
+	return nil
+}
+
+// syncFolderPaths records the paths of a folder and its subfolders on their
+// requests, returning the number of requests updated
+func syncFolderPaths(ctx context.Context, requestRepo interfaces.RequestRepository, tree *folderTree, id int64) (int, error) {
+	updated := 0
+	for _, folder := range tree.subtree(id) {
+		n, err := requestRepo.SetFolderPath(ctx, folder.ID, folder.Path)
+		if err != nil {
+			return 0, err
+		}
+		updated += n
+	}
+	return updated, nil
+}
+
+// placeRequest files a request under its FolderID or, failing that, its
+// FolderPath, creating the folders missing along the path, and makes the two
+// agree. A new request, or one leaving the folder of previous, goes after the
+// last item of its folder.
+func placeRequest(ctx context.Context, folderRepo interfaces.FolderRepository, requestRepo interfaces.RequestRepository, request, previous *models.Request) error {
+	request.FolderPath = cleanFolderPath(request.FolderPath)
+
+	tree, err := loadFolderTree(ctx, folderRepo, request.CollectionID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case request.FolderID != 0:
+		folder, ok := tree.folders[request.FolderID]
+		if !ok {
+			return models.NewValidationError("folder %d is not in collection %d", request.FolderID, request.CollectionID)
+		}
+		request.FolderPath = folder.Path
+	case request.FolderPath != "":
+		folder, err := tree.ensure(ctx, folderRepo, request.FolderPath)
+		if err != nil {
+			return err
+		}
+		request.FolderID = folder.ID
+	}
+
+	if previous != nil && previous.CollectionID == request.CollectionID && previous.FolderID == request.FolderID {
+		request.Position = previous.Position
+		return nil
+	}
+
+	if err := tree.loadRequestPositions(ctx, requestRepo); err != nil {
+		return err
+	}
+	tree.appendRequest(request)
+
+	return nil
+}
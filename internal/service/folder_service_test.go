@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+
+	"github.com/uptrace/bun"
+)
+
+// testFolders returns a collection with the folders
+//
+//	api (1)
+//	  users (2)
+//	    admin (4)
+//	  orders (3)
+//	docs (5)
+func testFolders() []*models.Folder {
+	return []*models.Folder{
+		{ID: 1, CollectionID: 7, Name: "api", Position: 0},
+		{ID: 2, CollectionID: 7, ParentID: 1, Name: "users", Position: 0},
+		{ID: 3, CollectionID: 7, ParentID: 1, Name: "orders", Position: 2},
+		{ID: 4, CollectionID: 7, ParentID: 2, Name: "admin", Position: 0},
+		{ID: 5, CollectionID: 7, Name: "docs", Position: 3},
+	}
+}
+
+func TestNewFolderTreePaths(t *testing.T) {
+	tree := newFolderTree(7, testFolders())
+
+	want := map[int64]string{1: "api", 2: "api/users", 3: "api/orders", 4: "api/users/admin", 5: "docs"}
+	for id, path := range want {
+		if got := tree.folders[id].Path; got != path {
+			t.Errorf("path of folder %d = %q, want %q", id, got, path)
+		}
+	}
+}
+
+func TestFolderTreeFind(t *testing.T) {
+	tree := newFolderTree(7, testFolders())
+
+	tests := []struct {
+		path string
+		want int64
+	}{
+		{"api", 1},
+		{"api/users/admin", 4},
+		{"api/orders", 3},
+		{"docs", 5},
+		{"api/admin", 0},
+		{"users", 0},
+		{"api/users/admin/more", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got int64
+			if folder := tree.find(tt.path); folder != nil {
+				got = folder.ID
+			}
+			if got != tt.want {
+				t.Errorf("find(%q) = folder %d, want %d", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFolderTreeValidate(t *testing.T) {
+	// A chain of maxFolderDepth folders, the deepest of which takes no children
+	var chain []*models.Folder
+	for i := int64(1); i <= maxFolderDepth; i++ {
+		chain = append(chain, &models.Folder{ID: i, CollectionID: 7, ParentID: i - 1, Name: "level"})
+	}
+
+	tests := []struct {
+		name    string
+		folders []*models.Folder
+		folder  *models.Folder
+		code    models.ErrorCode
+		message string
+	}{
+		{
+			name:   "new top-level folder",
+			folder: &models.Folder{Name: "new"},
+		},
+		{
+			name:   "new nested folder",
+			folder: &models.Folder{ParentID: 4, Name: "new"},
+		},
+		{
+			name:   "unchanged folder keeps its own name",
+			folder: &models.Folder{ID: 2, ParentID: 1, Name: "users"},
+		},
+		{
+			name:   "moved to another parent",
+			folder: &models.Folder{ID: 4, ParentID: 5, Name: "admin"},
+		},
+		{
+			name:    "empty name",
+			folder:  &models.Folder{Name: ""},
+			code:    models.ErrCodeValidation,
+			message: "name",
+		},
+		{
+			name:    "name with a slash",
+			folder:  &models.Folder{Name: "a/b"},
+			code:    models.ErrCodeValidation,
+			message: "must not contain /",
+		},
+		{
+			name:    "negative position",
+			folder:  &models.Folder{Name: "new", Position: -1},
+			code:    models.ErrCodeValidation,
+			message: "must not be negative",
+		},
+		{
+			name:    "parent in another collection",
+			folder:  &models.Folder{ParentID: 99, Name: "new"},
+			code:    models.ErrCodeValidation,
+			message: "parent folder 99 is not in collection 7",
+		},
+		{
+			name:    "moved into itself",
+			folder:  &models.Folder{ID: 2, ParentID: 2, Name: "users"},
+			code:    models.ErrCodeValidation,
+			message: "cannot be moved into itself",
+		},
+		{
+			name:    "moved into its own subfolder",
+			folder:  &models.Folder{ID: 1, ParentID: 4, Name: "api"},
+			code:    models.ErrCodeValidation,
+			message: "cannot be moved into itself",
+		},
+		{
+			name:    "sibling with the same name",
+			folder:  &models.Folder{ParentID: 1, Name: "orders"},
+			code:    models.ErrCodeConflict,
+			message: `folder "api/orders" already exists`,
+		},
+		{
+			name:    "renamed onto a sibling",
+			folder:  &models.Folder{ID: 5, Name: "api"},
+			code:    models.ErrCodeConflict,
+			message: `folder "api" already exists`,
+		},
+		{
+			name:    "nested too deep",
+			folders: chain,
+			folder:  &models.Folder{ParentID: maxFolderDepth, Name: "deeper"},
+			code:    models.ErrCodeValidation,
+			message: "cannot be nested more than",
+		},
+		{
+			name:    "nested as deep as allowed",
+			folders: chain,
+			folder:  &models.Folder{ParentID: maxFolderDepth - 1, Name: "sibling"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folders := tt.folders
+			if folders == nil {
+				folders = testFolders()
+			}
+			tree := newFolderTree(7, folders)
+
+			err := tree.validate(tt.folder)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("validate() = %v, want no error", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("validate() = nil, want %s error", tt.code)
+			}
+			if code := models.ErrorCodeOf(err); code != tt.code {
+				t.Errorf("validate() code = %s, want %s", code, tt.code)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("validate() = %q, want it to mention %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestFolderTreeReplace(t *testing.T) {
+	tree := newFolderTree(7, testFolders())
+
+	moved := *tree.folders[2]
+	moved.ParentID = 5
+	moved.Name = "people"
+	tree.replace(&moved)
+
+	if got := tree.folders[2].Path; got != "docs/people" {
+		t.Errorf("path of moved folder = %q, want %q", got, "docs/people")
+	}
+	if got := tree.folders[4].Path; got != "docs/people/admin" {
+		t.Errorf("path of subfolder = %q, want %q", got, "docs/people/admin")
+	}
+	if got := tree.find("api/users"); got != nil {
+		t.Errorf("find(api/users) = folder %d, want none", got.ID)
+	}
+
+	var ids []int64
+	for _, folder := range tree.subtree(5) {
+		ids = append(ids, folder.ID)
+	}
+	if want := []int64{5, 2, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("subtree(5) = %v, want %v", ids, want)
+	}
+}
+
+func TestFolderTreeNextPosition(t *testing.T) {
+	tree := newFolderTree(7, testFolders())
+	tree.requestPositions = map[int64]int{1: 5, 4: 2}
+
+	tests := []struct {
+		parentID int64
+		want     int
+	}{
+		{0, 4},  // after docs
+		{1, 5},  // after the requests of api, past orders
+		{2, 1},  // after admin
+		{4, 2},  // requests only
+		{5, 0},  // empty
+		{99, 0}, // unknown
+	}
+
+	for _, tt := range tests {
+		if got := tree.nextPosition(tt.parentID); got != tt.want {
+			t.Errorf("nextPosition(%d) = %d, want %d", tt.parentID, got, tt.want)
+		}
+	}
+}
+
+func TestFolderTreeEachChild(t *testing.T) {
+	tree := newFolderTree(7, testFolders())
+	requests := []*models.Request{
+		{ID: 10, Name: "first", Position: 0},
+		{ID: 11, Name: "between", Position: 1},
+		{ID: 12, Name: "last", Position: 3},
+	}
+
+	var order []string
+	tree.eachChild(1, requests,
+		func(r *models.Request) { order = append(order, "request "+r.Name) },
+		func(f *models.Folder) { order = append(order, "folder "+f.Name) },
+	)
+
+	// Requests come before folders at the same position
+	want := []string{"request first", "folder users", "request between", "folder orders", "request last"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("eachChild() visited %v, want %v", order, want)
+	}
+}
+
+func TestFolderTreeEnsure(t *testing.T) {
+	repo := &fakeFolderRepository{nextID: 100}
+	tree := newFolderTree(7, testFolders())
+	tree.requestPositions = map[int64]int{1: 5}
+
+	folder, err := tree.ensure(context.Background(), repo, "api/users/guests/vip")
+	if err != nil {
+		t.Fatalf("ensure() = %v", err)
+	}
+
+	if folder.Path != "api/users/guests/vip" {
+		t.Errorf("path = %q, want %q", folder.Path, "api/users/guests/vip")
+	}
+
+	var created []string
+	for _, f := range repo.created {
+		created = append(created, f.Name)
+	}
+	if want := []string{"guests", "vip"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("created %v, want %v", created, want)
+	}
+	if guests := repo.created[0]; guests.ParentID != 2 || guests.Position != 1 {
+		t.Errorf("guests has parent %d at %d, want parent 2 at 1", guests.ParentID, guests.Position)
+	}
+
+	again, err := tree.ensure(context.Background(), repo, "api/users/guests/vip")
+	if err != nil {
+		t.Fatalf("ensure() again = %v", err)
+	}
+	if again != folder || len(repo.created) != 2 {
+		t.Errorf("ensure() again created %d folders, want the existing one", len(repo.created)-2)
+	}
+
+	if top, err := tree.ensure(context.Background(), repo, ""); top != nil || err != nil {
+		t.Errorf("ensure(\"\") = %v, %v, want the top level", top, err)
+	}
+
+	folder, err = tree.ensure(context.Background(), repo, "api/reports")
+	if err != nil {
+		t.Fatalf("ensure() = %v", err)
+	}
+	if folder.Position != 5 {
+		t.Errorf("position after the requests of api = %d, want 5", folder.Position)
+	}
+}
+
+func TestCleanFolderPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"a/b/c", "a/b/c"},
+		{"/a//b/", "a/b"},
+		{" a / b ", "a/b"},
+		{" / ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := cleanFolderPath(tt.path); got != tt.want {
+			t.Errorf("cleanFolderPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// fakeFolderRepository records the folders created through it
+type fakeFolderRepository struct {
+	nextID  int64
+	created []*models.Folder
+}
+
+func (r *fakeFolderRepository) Create(ctx context.Context, folder *models.Folder) error {
+	folder.ID = r.nextID
+	r.nextID++
+	r.created = append(r.created, folder)
+	return nil
+}
+
+func (r *fakeFolderRepository) GetByID(ctx context.Context, id int64) (*models.Folder, error) {
+	return nil, models.NewNotFoundError("folder", nil)
+}
+
+func (r *fakeFolderRepository) ListByCollectionID(ctx context.Context, collectionID int64) ([]*models.Folder, error) {
+	return r.created, nil
+}
+
+func (r *fakeFolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+	return nil
+}
+
+func (r *fakeFolderRepository) Delete(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (r *fakeFolderRepository) WithTx(tx bun.Tx) interfaces.FolderRepository {
+	return r
+}
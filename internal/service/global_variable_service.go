@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// GlobalVariableService handles business logic for global variables
+type GlobalVariableService struct {
+	globalRepo interfaces.GlobalVariableRepository
+	configured map[string]string
+}
+
+// NewGlobalVariableService creates a new global variable service; configured
+// are the globals from the configuration, which managed globals override
+func NewGlobalVariableService(globalRepo interfaces.GlobalVariableRepository, configured map[string]string) interfaces.GlobalVariableService {
+	return &GlobalVariableService{
+		globalRepo: globalRepo,
+		configured: configured,
+	}
+}
+
+// ListGlobals returns the managed global variables with pagination, by key
+func (s *GlobalVariableService) ListGlobals(ctx context.Context, page, pageSize int) ([]*models.GlobalVariable, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	variables, err := s.globalRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.globalRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return variables, total, nil
+}
+
+// GetGlobal retrieves a managed global variable by key
+func (s *GlobalVariableService) GetGlobal(ctx context.Context, key string) (*models.GlobalVariable, error) {
+	return s.globalRepo.GetByKey(ctx, key)
+}
+
+// SetGlobal creates or replaces a managed global variable, recording the change
+func (s *GlobalVariableService) SetGlobal(ctx context.Context, variable *models.GlobalVariable) error {
+	var errs models.FieldErrors
+	validateVariableKey(&errs, "key", variable.Key)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if _, err := s.globalRepo.Set(ctx, variable); err != nil {
+		return fmt.Errorf("failed to set global variable: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteGlobal removes a managed global variable, recording the change; a
+// configured global of the same key applies again afterwards
+func (s *GlobalVariableService) DeleteGlobal(ctx context.Context, key string) error {
+	return s.globalRepo.Delete(ctx, key)
+}
+
+// ListGlobalChanges returns the history of a managed global variable with
+// pagination, newest first; it outlives the variable
+func (s *GlobalVariableService) ListGlobalChanges(ctx context.Context, key string, page, pageSize int) ([]*models.GlobalVariableChange, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	changes, err := s.globalRepo.ListChanges(ctx, key, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.globalRepo.CountChanges(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return changes, total, nil
+}
+
+// ResolveGlobals returns the values of the global scope: the configured
+// globals overridden by the managed ones
+func (s *GlobalVariableService) ResolveGlobals(ctx context.Context) (map[string]string, error) {
+	variables, err := s.globalRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(s.configured)+len(variables))
+	maps.Copy(values, s.configured)
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+
+	return values, nil
+}
+
+// validateVariableKey checks that a variable key can be referenced as {{key}}
+func validateVariableKey(errs *models.FieldErrors, path, key string) {
+	switch {
+	case key == "":
+		errs.Add(path, "is required")
+	case utf8.RuneCountInString(key) > maxNameLength:
+		errs.Add(path, "must be at most %d characters", maxNameLength)
+	case strings.TrimSpace(key) != key:
+		errs.Add(path, "must not start or end with whitespace")
+	case strings.ContainsAny(key, "{}"):
+		errs.Add(path, "must not contain braces")
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+)
+
+// maxHeaderPresets bounds how many presets a request or run may apply
+const maxHeaderPresets = 20
+
+// HeaderPresetService handles business logic for header presets
+type HeaderPresetService struct {
+	presetRepo interfaces.HeaderPresetRepository
+}
+
+// NewHeaderPresetService creates a new header preset service
+func NewHeaderPresetService(presetRepo interfaces.HeaderPresetRepository) interfaces.HeaderPresetService {
+	return &HeaderPresetService{
+		presetRepo: presetRepo,
+	}
+}
+
+// CreatePreset stores a new header preset; names are unique
+func (s *HeaderPresetService) CreatePreset(ctx context.Context, preset *models.HeaderPreset) error {
+	if err := validateHeaderPreset(preset); err != nil {
+		return err
+	}
+
+	return s.presetRepo.Create(ctx, preset)
+}
+
+// GetPreset retrieves a header preset by ID
+func (s *HeaderPresetService) GetPreset(ctx context.Context, id int64) (*models.HeaderPreset, error) {
+	return s.presetRepo.GetByID(ctx, id)
+}
+
+// ListPresets returns all header presets with pagination, by name
+func (s *HeaderPresetService) ListPresets(ctx context.Context, page, pageSize int) ([]*models.HeaderPreset, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	presets, err := s.presetRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.presetRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return presets, total, nil
+}
+
+// UpdatePreset replaces the name, description and headers of a preset; the
+// requests it is attached to pick up the change
+func (s *HeaderPresetService) UpdatePreset(ctx context.Context, preset *models.HeaderPreset) error {
+	if err := validateHeaderPreset(preset); err != nil {
+		return err
+	}
+
+	existing, err := s.presetRepo.GetByID(ctx, preset.ID)
+	if err != nil {
+		return err
+	}
+	preset.CreatedAt = existing.CreatedAt
+
+	return s.presetRepo.Update(ctx, preset)
+}
+
+// DeletePreset removes a header preset that no request is attached to
+func (s *HeaderPresetService) DeletePreset(ctx context.Context, id int64) error {
+	if _, err := s.presetRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	used, err := s.presetRepo.CountRequests(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if used > 0 {
+		return models.NewConflictError(fmt.Sprintf("header preset %d is attached to %d requests", id, used), nil)
+	}
+
+	return s.presetRepo.Delete(ctx, id)
+}
+
+// validateHeaderPreset checks the name and headers of a preset
+func validateHeaderPreset(preset *models.HeaderPreset) error {
+	preset.Name = strings.TrimSpace(preset.Name)
+
+	var errs models.FieldErrors
+	validateName(&errs, "name", preset.Name)
+	if len(preset.Headers) == 0 {
+		errs.Add("headers", "is required")
+	}
+	validateHeaders(&errs, "headers", preset.Headers)
+	return errs.Err()
+}
+
+// checkHeaderPresets checks that ids name distinct, existing presets
+func checkHeaderPresets(ctx context.Context, presetRepo interfaces.HeaderPresetRepository, path string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if len(ids) > maxHeaderPresets {
+		return models.NewValidationError("%s: at most %d header presets can be applied", path, maxHeaderPresets)
+	}
+
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return models.NewValidationError("%s: header preset %d is listed twice", path, id)
+		}
+		seen[id] = true
+	}
+
+	presets, err := presetRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, preset := range presets {
+		delete(seen, preset.ID)
+	}
+	for _, id := range ids {
+		if seen[id] {
+			return models.NewValidationError("%s: header preset %d does not exist", path, id)
+		}
+	}
+
+	return nil
+}
+
+// headerPresets caches presets by ID while the requests using them are resolved
+type headerPresets struct {
+	repo   interfaces.HeaderPresetRepository
+	loaded map[int64]*models.HeaderPreset
+}
+
+func newHeaderPresets(repo interfaces.HeaderPresetRepository) *headerPresets {
+	return &headerPresets{repo: repo, loaded: map[int64]*models.HeaderPreset{}}
+}
+
+// apply returns req with the headers of its presets, followed by extra,
+// beneath its own headers: later presets override earlier ones and the
+// request's headers override them all. Presets deleted since are skipped.
+func (p *headerPresets) apply(ctx context.Context, req *models.Request, extra []int64) (*models.Request, error) {
+	ids := append(append([]int64{}, req.HeaderPresets...), extra...)
+	if len(ids) == 0 {
+		return req, nil
+	}
+
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := p.loaded[id]; !ok {
+			missing = append(missing, id)
+			p.loaded[id] = nil
+		}
+	}
+
+	if len(missing) > 0 {
+		presets, err := p.repo.ListByIDs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header presets: %w", err)
+		}
+		for _, preset := range presets {
+			p.loaded[preset.ID] = preset
+		}
+	}
+
+	headers := map[string]string{}
+	for _, id := range ids {
+		if preset := p.loaded[id]; preset != nil {
+			for key, value := range preset.Headers {
+				setHeader(headers, key, value)
+			}
+		}
+	}
+	for key, value := range req.Headers {
+		setHeader(headers, key, value)
+	}
+
+	applied := *req
+	applied.Headers = headers
+	return &applied, nil
+}
+
+// setHeader sets a header, replacing any set under a different case
+func setHeader(headers map[string]string, key, value string) {
+	for existing := range headers {
+		if strings.EqualFold(existing, key) {
+			delete(headers, existing)
+		}
+	}
+	headers[key] = value
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/resilience"
+	"time"
+)
+
+const readinessPingTimeout = 2 * time.Second
+
+// pinger is satisfied by *bun.DB and *sql.DB
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthService reports readiness from the database and its circuit breaker
+type HealthService struct {
+	db      pinger
+	breaker *resilience.Breaker
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(db pinger, breaker *resilience.Breaker) interfaces.HealthService {
+	return &HealthService{
+		db:      db,
+		breaker: breaker,
+	}
+}
+
+// Readiness pings the database and combines the result with the breaker state.
+// An open or half-open circuit reports degraded even if the ping succeeds.
+func (s *HealthService) Readiness(ctx context.Context) *models.Readiness {
+	readiness := &models.Readiness{
+		Status:   models.ReadinessOK,
+		Database: "up",
+		Circuit:  s.breaker.State(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		readiness.Status = models.ReadinessUnavailable
+		readiness.Database = "down"
+		readiness.Error = err.Error()
+		return readiness
+	}
+
+	if readiness.Circuit != resilience.StateClosed {
+		readiness.Status = models.ReadinessDegraded
+	}
+
+	return readiness
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"postman-api/internal/models"
+	"time"
+)
+
+// importHash returns the content hash recorded on an imported document;
+// imports with secrets stripped are stored differently and hash differently
+func importHash(data []byte, opts models.ImportOptions) string {
+	h := sha256.New()
+	h.Write(data)
+	if opts.StripSecrets {
+		h.Write([]byte("\x00strip_secrets"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// reportParsed reports the summary and warnings of a validated document
+func reportParsed(opts models.ImportOptions, result *models.ValidationResult) {
+	opts.Report(models.ImportProgress{Stage: models.ImportStageParsed, Summary: result.Summary})
+	for _, warning := range result.Warnings {
+		opts.Report(models.ImportProgress{Stage: models.ImportStageWarning, Warning: &warning})
+	}
+}
+
+// withContentHash returns a copy of metadata recording the content hash
+func withContentHash(metadata models.JSONMap, hash string) models.JSONMap {
+	out := models.JSONMap{}
+	maps.Copy(out, metadata)
+	out[models.ContentHashMetadataKey] = hash
+	return out
+}
+
+// withProvenance returns a copy of metadata recording provenance, with the
+// hash of data as received, when given, and the import time filled in;
+// metadata is returned unchanged when provenance is nil
+func withProvenance(metadata models.JSONMap, provenance *models.Provenance, data []byte) models.JSONMap {
+	if provenance == nil {
+		return metadata
+	}
+
+	p := *provenance
+	if p.OriginalHash == "" && data != nil {
+		sum := sha256.Sum256(data)
+		p.OriginalHash = hex.EncodeToString(sum[:])
+	}
+	if p.ImportedAt.IsZero() {
+		p.ImportedAt = time.Now().UTC()
+	}
+
+	value := map[string]any{}
+	if encoded, err := json.Marshal(p); err == nil {
+		json.Unmarshal(encoded, &value)
+	}
+
+	out := models.JSONMap{}
+	maps.Copy(out, metadata)
+	out[models.ProvenanceMetadataKey] = value
+	return out
+}
+
+// generatedProvenance describes a collection generated from spec, carrying
+// over the hash of the document the spec was imported from
+func generatedProvenance(spec *models.OpenAPISpec) *models.Provenance {
+	hash, _ := spec.Metadata[models.ContentHashMetadataKey].(string)
+	return &models.Provenance{
+		Source:       models.ProvenanceGenerated,
+		SpecID:       spec.ID,
+		OriginalHash: hash,
+	}
+}
+
+// keepProvenance returns metadata with the provenance of existing, so that
+// updates can neither drop nor rewrite where a document came from
+func keepProvenance(metadata, existing models.JSONMap) models.JSONMap {
+	provenance, ok := existing[models.ProvenanceMetadataKey]
+	if !ok {
+		if _, set := metadata[models.ProvenanceMetadataKey]; !set {
+			return metadata
+		}
+	}
+
+	out := models.JSONMap{}
+	maps.Copy(out, metadata)
+	delete(out, models.ProvenanceMetadataKey)
+	if ok {
+		out[models.ProvenanceMetadataKey] = provenance
+	}
+	return out
+}
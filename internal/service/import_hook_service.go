@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/secrets"
+)
+
+// ImportHookService imports documents pushed by CI pipelines
+type ImportHookService struct {
+	collectionService interfaces.CollectionService
+	openAPIService    interfaces.OpenAPIService
+	cipher            *secrets.Cipher
+	httpClient        *http.Client
+}
+
+// NewImportHookService creates a new import hook service; artifacts are
+// fetched through proxy. Without a cipher, queued imports carrying
+// credentials are rejected.
+func NewImportHookService(
+	collectionService interfaces.CollectionService,
+	openAPIService interfaces.OpenAPIService,
+	cipher *secrets.Cipher,
+	proxy models.OutboundProxy,
+) interfaces.ImportHookService {
+	return &ImportHookService{
+		collectionService: collectionService,
+		openAPIService:    openAPIService,
+		cipher:            cipher,
+		httpClient:        newFetchClient(proxy),
+	}
+}
+
+// SealPayload returns the job payload queuing an import, with the
+// credentials for the artifact URL encrypted
+func (s *ImportHookService) SealPayload(payload *models.ImportHookPayload) (models.JSONMap, error) {
+	sealed := *payload
+	auth, err := sealFetchAuth(s.cipher, payload.Auth)
+	if err != nil {
+		return nil, err
+	}
+	sealed.Auth = auth
+
+	return jobPayload(sealed)
+}
+
+// HandleQueuedImport runs an import queued with a payload from SealPayload
+func (s *ImportHookService) HandleQueuedImport(ctx context.Context, data models.JSONMap) (*models.ImportHookResult, error) {
+	var payload models.ImportHookPayload
+	if err := decodeJobPayload(data, &payload); err != nil {
+		return nil, err
+	}
+
+	auth, err := openFetchAuth(s.cipher, payload.Auth)
+	if err != nil {
+		return nil, err
+	}
+	payload.Auth = auth
+
+	return s.HandleImport(ctx, &payload)
+}
+
+// HandleImport imports the inline document, or the artifact at payload.URL
+func (s *ImportHookService) HandleImport(ctx context.Context, payload *models.ImportHookPayload) (*models.ImportHookResult, error) {
+	hasDocument := len(payload.Document) > 0 && string(payload.Document) != "null"
+	if hasDocument == (payload.URL != "") {
+		return nil, models.NewValidationError("exactly one of url or document is required")
+	}
+
+	metadata := models.JSONMap{"source": "webhook"}
+
+	data := []byte(payload.Document)
+	if payload.URL != "" {
+		fetched, err := fetchDocument(ctx, s.httpClient, payload.URL, payload.Auth)
+		if err != nil {
+			return nil, err
+		}
+		data = fetched
+		metadata["source_url"] = payload.URL
+	}
+
+	provenance := models.Provenance{}
+	if payload.Provenance != nil {
+		provenance = *payload.Provenance
+	}
+	provenance.Source = models.ProvenanceWebhook
+	if payload.URL != "" {
+		provenance.URL = payload.URL
+	}
+
+	opts := models.ImportOptions{
+		StripSecrets: payload.StripSecrets,
+		Metadata:     metadata,
+		Provenance:   &provenance,
+	}
+
+	var result *models.ImportResult
+	var err error
+	switch payload.Type {
+	case models.DocumentTypeOpenAPI:
+		result, err = s.openAPIService.ImportOpenAPISpec(ctx, data, opts)
+	case models.DocumentTypePostman:
+		result, err = s.collectionService.ImportPostmanCollection(ctx, data, opts)
+	default:
+		return nil, models.NewValidationError("unsupported document type %q", payload.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ImportHookResult{Type: payload.Type, ID: result.ID, Existing: result.Existing}, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"sort"
+	"strings"
+)
+
+const inventoryBatchSize = 200
+
+// InventoryService aggregates every endpoint seen across collections and specs
+type InventoryService struct {
+	openAPIRepo   interfaces.OpenAPIRepository
+	requestRepo   interfaces.RequestRepository
+	ownershipRepo interfaces.InventoryOwnershipRepository
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	requestRepo interfaces.RequestRepository,
+	ownershipRepo interfaces.InventoryOwnershipRepository,
+) interfaces.InventoryService {
+	return &InventoryService{
+		openAPIRepo:   openAPIRepo,
+		requestRepo:   requestRepo,
+		ownershipRepo: ownershipRepo,
+	}
+}
+
+// inventory deduplicates endpoints by method, host and path shape
+type inventory struct {
+	entries map[string]*models.InventoryEntry
+}
+
+func (inv *inventory) add(method, host, path string, source models.InventorySource) {
+	method = strings.ToUpper(method)
+	if !strings.Contains(host, "{{") {
+		host = strings.ToLower(host)
+	}
+	path, shape := normalizeInventoryPath(path)
+
+	key := models.InventoryKey(method, host, shape)
+	entry, ok := inv.entries[key]
+	if !ok {
+		entry = &models.InventoryEntry{
+			Key:    key,
+			Method: method,
+			Host:   host,
+			Path:   path,
+		}
+		inv.entries[key] = entry
+	}
+
+	entry.Sources = append(entry.Sources, source)
+}
+
+// ListInventory returns the deduplicated endpoint inventory with recorded
+// ownership, sorted by host, path and method
+func (s *InventoryService) ListInventory(ctx context.Context, filter models.InventoryFilter) ([]*models.InventoryEntry, error) {
+	inv := &inventory{entries: map[string]*models.InventoryEntry{}}
+
+	for offset := 0; ; offset += inventoryBatchSize {
+		specs, err := s.openAPIRepo.List(ctx, offset, inventoryBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			addSpecOperations(inv, spec)
+		}
+
+		if len(specs) < inventoryBatchSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += inventoryBatchSize {
+		requests, err := s.requestRepo.List(ctx, offset, inventoryBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, req := range requests {
+			host, path := requestHostPath(req.URL)
+			inv.add(req.Method, host, path, models.InventorySource{
+				Type:         models.InventorySourceRequest,
+				CollectionID: req.CollectionID,
+				RequestID:    req.ID,
+				Name:         req.Name,
+			})
+		}
+
+		if len(requests) < inventoryBatchSize {
+			break
+		}
+	}
+
+	ownerships, err := s.ownershipRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, owned := range ownerships {
+		if entry, ok := inv.entries[owned.Key]; ok {
+			entry.Ownership = &owned.Ownership
+		}
+	}
+
+	entries := make([]*models.InventoryEntry, 0, len(inv.entries))
+	for _, entry := range inv.entries {
+		if !filter.Matches(entry.Ownership) {
+			continue
+		}
+		if filter.Host != "" && !strings.EqualFold(entry.Host, filter.Host) {
+			continue
+		}
+		if filter.Method != "" && entry.Method != strings.ToUpper(filter.Method) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	return entries, nil
+}
+
+// addSpecOperations adds every operation of a spec once per server it declares
+func addSpecOperations(inv *inventory, spec *models.OpenAPISpec) {
+	paths, _ := spec.Content["paths"].(map[string]any)
+	servers := specServers(spec.Content)
+
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			operationID, _ := op["operationId"].(string)
+			summary, _ := op["summary"].(string)
+			for _, server := range servers {
+				inv.add(method, server.host, server.basePath+path, models.InventorySource{
+					Type:        models.InventorySourceSpec,
+					SpecID:      spec.ID,
+					SpecTitle:   spec.Title,
+					OperationID: operationID,
+					Name:        summary,
+				})
+			}
+		}
+	}
+}
+
+type specServer struct {
+	host     string
+	basePath string
+}
+
+// specServers resolves the hosts and base paths of a spec from OpenAPI 3
+// servers, with variables at their defaults, or Swagger 2 host and basePath
+func specServers(doc models.JSONMap) []specServer {
+	var servers []specServer
+
+	if list, ok := doc["servers"].([]any); ok {
+		for _, raw := range list {
+			server, _ := raw.(map[string]any)
+			serverURL, _ := server["url"].(string)
+			if serverURL == "" {
+				continue
+			}
+
+			variables, _ := server["variables"].(map[string]any)
+			for name, v := range variables {
+				variable, _ := v.(map[string]any)
+				if def, ok := variable["default"].(string); ok {
+					serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", def)
+				}
+			}
+
+			host, basePath := splitHostPath(serverURL)
+			servers = append(servers, specServer{host: host, basePath: strings.TrimSuffix(basePath, "/")})
+		}
+	} else if host, ok := doc["host"].(string); ok {
+		basePath, _ := doc["basePath"].(string)
+		servers = append(servers, specServer{host: host, basePath: strings.TrimSuffix(basePath, "/")})
+	}
+
+	if len(servers) == 0 {
+		servers = append(servers, specServer{})
+	}
+
+	return servers
+}
+
+// requestHostPath extracts the host and path of a stored Postman request URL
+func requestHostPath(u models.JSONMap) (string, string) {
+	if raw, ok := u["raw"].(string); ok && raw != "" {
+		return splitHostPath(raw)
+	}
+
+	var host, path []string
+	if parts, ok := u["host"].([]any); ok {
+		for _, part := range parts {
+			if s, ok := part.(string); ok {
+				host = append(host, s)
+			}
+		}
+	}
+	if parts, ok := u["path"].([]any); ok {
+		for _, part := range parts {
+			if s, ok := part.(string); ok {
+				path = append(path, s)
+			}
+		}
+	}
+
+	return strings.Join(host, "."), "/" + strings.Join(path, "/")
+}
+
+// splitHostPath splits a URL, which may be relative or start with a
+// {{variable}}, into its host and path, dropping query and fragment
+func splitHostPath(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	if i := strings.IndexAny(raw, "?#"); i >= 0 {
+		raw = raw[:i]
+	}
+
+	if strings.Contains(raw, "://") {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			return u.Host, u.Path
+		}
+		raw = raw[strings.Index(raw, "://")+3:]
+	} else if strings.HasPrefix(raw, "/") {
+		return "", raw
+	}
+
+	host, path, _ := strings.Cut(raw, "/")
+	return host, "/" + path
+}
+
+// normalizeInventoryPath cleans a path for display and returns its shape, in
+// which every parameter segment, :name, {name} or {{name}}, reads {}
+func normalizeInventoryPath(path string) (string, string) {
+	var display, shape []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}"):
+			display = append(display, "{"+strings.TrimSuffix(strings.TrimPrefix(segment, "{{"), "}}")+"}")
+			shape = append(shape, "{}")
+		case strings.HasPrefix(segment, ":") && len(segment) > 1:
+			display = append(display, "{"+segment[1:]+"}")
+			shape = append(shape, "{}")
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			display = append(display, segment)
+			shape = append(shape, "{}")
+		default:
+			display = append(display, segment)
+			shape = append(shape, segment)
+		}
+	}
+
+	return "/" + strings.Join(display, "/"), "/" + strings.Join(shape, "/")
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/models"
+
+	"github.com/uptrace/bun"
+)
+
+// itemsMigrationBatchSize is how many legacy collections are listed at a time
+const itemsMigrationBatchSize = 100
+
+// MigrateLegacyItems moves the folders and requests of collections stored
+// only in their items blob onto folder and request rows, so that they can be
+// edited like imported ones. Each collection is migrated in a transaction of
+// its own that also clears the blob; collections that already have rows are
+// skipped and keep it. The run continues after migration.AfterID and report
+// is called after every collection.
+func (s *CollectionService) MigrateLegacyItems(ctx context.Context, migration *models.ItemsMigration, report func(*models.ItemsMigration) error) error {
+	remaining, err := s.collectionRepo.CountWithLegacyItems(ctx, migration.AfterID)
+	if err != nil {
+		return err
+	}
+	migration.Total = migration.Migrated + len(migration.Skipped) + remaining
+
+	for {
+		ids, err := s.collectionRepo.ListWithLegacyItems(ctx, migration.AfterID, itemsMigrationBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			created, migrated, err := s.migrateLegacyCollection(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to migrate items of collection %d: %w", id, err)
+			}
+
+			if migrated {
+				migration.Migrated++
+				migration.Requests += created
+			} else {
+				migration.Skipped = append(migration.Skipped, id)
+			}
+			migration.AfterID = id
+
+			if err := report(migration); err != nil {
+				return err
+			}
+		}
+
+		if len(ids) < itemsMigrationBatchSize {
+			return nil
+		}
+	}
+}
+
+// migrateLegacyCollection migrates the items of one collection and returns
+// the number of requests created; it reports false when the collection
+// already has rows or was migrated meanwhile
+func (s *CollectionService) migrateLegacyCollection(ctx context.Context, id int64) (int, bool, error) {
+	var created int
+	var migrated bool
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		txs := s.withTx(tx)
+
+		// The lock keeps concurrent runs from migrating the collection twice
+		items, err := txs.collectionRepo.LockLegacyItems(ctx, id)
+		if models.ErrorCodeOf(err) == models.ErrCodeNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		requests, err := txs.requestRepo.CountByCollectionID(ctx, id)
+		if err != nil {
+			return err
+		}
+		folders, err := txs.folderRepo.ListByCollectionID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if requests > 0 || len(folders) > 0 {
+			return nil
+		}
+
+		ids, err := txs.processPostmanItems(ctx, items, newFolderTree(id, nil), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := txs.collectionRepo.ClearItems(ctx, id); err != nil {
+			return err
+		}
+
+		created, migrated = len(ids), true
+		return nil
+	})
+
+	return created, migrated, err
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"sync"
+	"time"
+)
+
+const (
+	// jobRetryBaseDelay is the wait before the first retry of a failed job;
+	// it doubles with every further attempt up to jobRetryMaxDelay
+	jobRetryBaseDelay = 10 * time.Second
+	jobRetryMaxDelay  = time.Hour
+
+	// jobStaleAfter is how long a job may stay running before it is assumed
+	// lost with its worker and queued again
+	jobStaleAfter = 30 * time.Minute
+)
+
+// JobService queues background work in the database and runs it on a pool
+// of workers, retrying failures with exponential backoff
+type JobService struct {
+	jobRepo     interfaces.JobRepository
+	maxAttempts int
+
+	mu       sync.RWMutex
+	handlers map[string]interfaces.JobHandler
+}
+
+// NewJobService creates a new job service giving each job maxAttempts tries
+func NewJobService(jobRepo interfaces.JobRepository, maxAttempts int) interfaces.JobService {
+	return &JobService{
+		jobRepo:     jobRepo,
+		maxAttempts: max(maxAttempts, 1),
+		handlers:    map[string]interfaces.JobHandler{},
+	}
+}
+
+// Register sets the handler running jobs of jobType
+func (s *JobService) Register(jobType string, handler interfaces.JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[jobType] = handler
+}
+
+// Enqueue queues a job of a registered type to run as soon as a worker is free
+func (s *JobService) Enqueue(ctx context.Context, jobType string, payload models.JSONMap) (*models.Job, error) {
+	if s.handler(jobType) == nil {
+		return nil, models.NewValidationError("unknown job type %q", jobType)
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     payload,
+		Status:      models.JobPending,
+		MaxAttempts: s.maxAttempts,
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return redactJob(job), nil
+}
+
+// GetJob retrieves a job by ID
+func (s *JobService) GetJob(ctx context.Context, id int64) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return redactJob(job), nil
+}
+
+// ListJobs returns the jobs matching filter with pagination
+func (s *JobService) ListJobs(ctx context.Context, filter models.JobFilter, page, pageSize int) ([]*models.Job, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	jobs, err := s.jobRepo.List(ctx, filter, offset, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	total, err := s.jobRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		redactJob(job)
+	}
+
+	return jobs, total, nil
+}
+
+// RetryJob queues a dead or cancelled job again with a fresh set of
+// attempts; jobs recording their progress resume where they stopped
+func (s *JobService) RetryJob(ctx context.Context, id int64) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != models.JobDead && job.Status != models.JobCancelled {
+		return nil, models.NewConflictError(fmt.Sprintf("job %d is %s, only dead or cancelled jobs can be retried", id, job.Status), nil)
+	}
+
+	job.Status = models.JobPending
+	job.Attempts = 0
+	job.MaxAttempts = s.maxAttempts
+	job.RunAt = time.Now()
+	job.FinishedAt = nil
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	return redactJob(job), nil
+}
+
+// CancelJob stops a pending job from running; running and finished jobs are left alone
+func (s *JobService) CancelJob(ctx context.Context, id int64) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != models.JobPending {
+		return nil, models.NewConflictError(fmt.Sprintf("job %d is %s, only pending jobs can be cancelled", id, job.Status), nil)
+	}
+
+	now := time.Now()
+	job.Status = models.JobCancelled
+	job.FinishedAt = &now
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	return redactJob(job), nil
+}
+
+// RunWorkers runs workers that take due jobs off the queue, polling every
+// pollInterval while it is empty, until ctx is cancelled. Jobs run under
+// jobCtx, so those in flight when ctx is cancelled finish before RunWorkers
+// returns; cancelling jobCtx interrupts them and returns them to the queue.
+func (s *JobService) RunWorkers(ctx, jobCtx context.Context, workers int, pollInterval time.Duration) {
+	if workers <= 0 || pollInterval <= 0 {
+		return
+	}
+
+	if n, err := s.jobRepo.RequeueStale(ctx, time.Now().Add(-jobStaleAfter)); err != nil {
+		log.Printf("Failed to requeue stale jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("Requeued %d stale jobs", n)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			s.work(ctx, jobCtx, pollInterval)
+		}()
+	}
+	wg.Wait()
+}
+
+// work runs jobs back to back while any are due and waits pollInterval otherwise
+func (s *JobService) work(ctx, jobCtx context.Context, pollInterval time.Duration) {
+	for ctx.Err() == nil {
+		job, err := s.jobRepo.Claim(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+
+		if job != nil {
+			s.run(jobCtx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// run executes a claimed job and records its outcome. Jobs interrupted by
+// cancelling ctx go back to the queue; failures are retried with backoff,
+// except for errors retrying cannot fix, which leave the job dead at once.
+func (s *JobService) run(ctx context.Context, job *models.Job) {
+	var result models.JSONMap
+	var err error
+	if handler := s.handler(job.Type); handler == nil {
+		err = models.NewValidationError("no handler registered for job type %q", job.Type)
+	} else {
+		result, err = handler(context.WithValue(ctx, runningJobKey{}, &runningJob{jobRepo: s.jobRepo, job: job}), job.Payload)
+	}
+
+	// The outcome is recorded even when ctx was cancelled
+	interrupted := ctx.Err() != nil
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.Result = result
+		job.LastError = ""
+		job.FinishedAt = &now
+	case interrupted:
+		job.Status = models.JobPending
+		job.Attempts--
+		job.LastError = err.Error()
+		job.RunAt = now
+	case job.Attempts < job.MaxAttempts && retryable(err):
+		job.Status = models.JobPending
+		job.LastError = err.Error()
+		job.RunAt = now.Add(retryDelay(job.Attempts))
+	default:
+		job.Status = models.JobDead
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+	}
+
+	if err != nil {
+		log.Printf("Job %d (%s) failed on attempt %d: %v", job.ID, job.Type, job.Attempts, err)
+	}
+
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Failed to record outcome of job %d: %v", job.ID, err)
+	}
+}
+
+func (s *JobService) handler(jobType string) interfaces.JobHandler {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.handlers[jobType]
+}
+
+type runningJobKey struct{}
+
+// runningJob lets a job handler record and read back its progress
+type runningJob struct {
+	jobRepo interfaces.JobRepository
+	job     *models.Job
+}
+
+// jobProgress fills v from the progress last recorded by the job running
+// under ctx, so that a job run again after an interruption or a retry can
+// resume; it reports whether there was any
+func jobProgress(ctx context.Context, v any) (bool, error) {
+	running, ok := ctx.Value(runningJobKey{}).(*runningJob)
+	if !ok || len(running.job.Progress) == 0 {
+		return false, nil
+	}
+
+	return true, decodeJobPayload(running.job.Progress, v)
+}
+
+// recordJobProgress records v as the progress of the job running under ctx;
+// outside of a job it does nothing
+func recordJobProgress(ctx context.Context, v any) error {
+	running, ok := ctx.Value(runningJobKey{}).(*runningJob)
+	if !ok {
+		return nil
+	}
+
+	progress, err := jobPayload(v)
+	if err != nil {
+		return err
+	}
+
+	if err := running.jobRepo.SetProgress(ctx, running.job.ID, progress); err != nil {
+		return err
+	}
+	running.job.Progress = progress
+
+	return nil
+}
+
+// retryable reports whether a failed job may succeed on another attempt;
+// invalid input and missing resources stay that way
+func retryable(err error) bool {
+	switch models.ErrorCodeOf(err) {
+	case models.ErrCodeValidation, models.ErrCodeNotFound, models.ErrCodeConflict,
+		models.ErrCodeTooLarge, models.ErrCodeUnauthorized:
+		return false
+	default:
+		return true
+	}
+}
+
+// retryDelay returns the backoff before the attempt following attempt
+func retryDelay(attempt int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempt && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, jobRetryMaxDelay)
+}
+
+// jobPayload converts v to a job payload or result through its JSON form
+func jobPayload(v any) (models.JSONMap, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	var payload models.JSONMap
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// redactJob replaces the credentials in the payload of a job with a
+// description of them before it leaves the service
+func redactJob(job *models.Job) *models.Job {
+	auth, ok := job.Payload["auth"].(map[string]any)
+	if !ok {
+		return job
+	}
+
+	payload := models.JSONMap{}
+	maps.Copy(payload, job.Payload)
+	payload["auth"] = describeFetchAuth(auth)
+	job.Payload = payload
+
+	return job
+}
+
+// decodeJobPayload fills v from a job payload
+func decodeJobPayload(payload models.JSONMap, v any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.NewValidationError("invalid job payload: %s", err.Error())
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return models.NewValidationError("invalid job payload: %s", err.Error())
+	}
+
+	return nil
+}
+
+// ImportHookJob runs webhook imports queued as jobs
+func ImportHookJob(importHookService interfaces.ImportHookService) interfaces.JobHandler {
+	return func(ctx context.Context, payload models.JSONMap) (models.JSONMap, error) {
+		result, err := importHookService.HandleQueuedImport(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return jobPayload(result)
+	}
+}
+
+// RetentionJob runs retention enforcement queued as a job
+func RetentionJob(retentionService interfaces.RetentionService) interfaces.JobHandler {
+	return func(ctx context.Context, _ models.JSONMap) (models.JSONMap, error) {
+		report, err := retentionService.Enforce(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return jobPayload(report)
+	}
+}
+
+// MigrateItemsJob runs the migration of legacy collection items queued as a
+// job, resuming from the progress of an earlier, interrupted run
+func MigrateItemsJob(collectionService interfaces.CollectionService) interfaces.JobHandler {
+	return func(ctx context.Context, _ models.JSONMap) (models.JSONMap, error) {
+		migration := &models.ItemsMigration{}
+		if _, err := jobProgress(ctx, migration); err != nil {
+			return nil, err
+		}
+
+		report := func(migration *models.ItemsMigration) error {
+			return recordJobProgress(ctx, migration)
+		}
+		if err := collectionService.MigrateLegacyItems(ctx, migration, report); err != nil {
+			return nil, err
+		}
+
+		return jobPayload(migration)
+	}
+}
+
+// collectionRun is the payload of a queued collection run
+type collectionRun struct {
+	RunID   int64             `json:"run_id"`
+	Options models.RunOptions `json:"options"`
+}
+
+// RunCollectionJob carries out the collection runs queued by the runner
+func RunCollectionJob(runnerService interfaces.RunnerService) interfaces.JobHandler {
+	return func(ctx context.Context, payload models.JSONMap) (models.JSONMap, error) {
+		var queued collectionRun
+		if err := decodeJobPayload(payload, &queued); err != nil {
+			return nil, err
+		}
+
+		run, err := runnerService.ExecuteRun(ctx, queued.RunID, queued.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		return models.JSONMap{"run_id": run.ID, "status": run.Status}, nil
+	}
+}
+
+// queuedLoadTest is the payload of a queued load test
+type queuedLoadTest struct {
+	TestID  int64                  `json:"load_test_id"`
+	Options models.LoadTestOptions `json:"options"`
+}
+
+// LoadTestJob carries out the load tests queued by the runner
+func LoadTestJob(runnerService interfaces.RunnerService) interfaces.JobHandler {
+	return func(ctx context.Context, payload models.JSONMap) (models.JSONMap, error) {
+		var queued queuedLoadTest
+		if err := decodeJobPayload(payload, &queued); err != nil {
+			return nil, err
+		}
+
+		test, err := runnerService.ExecuteLoadTest(ctx, queued.TestID, queued.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		return models.JSONMap{"load_test_id": test.ID, "status": test.Status}, nil
+	}
+}
+
+// PromotionExportJob runs the downstream exports queued by spec promotions
+func PromotionExportJob(promotionService interfaces.SpecPromotionService) interfaces.JobHandler {
+	return func(ctx context.Context, payload models.JSONMap) (models.JSONMap, error) {
+		var export struct {
+			PromotionID int64  `json:"promotion_id"`
+			Export      string `json:"export"`
+		}
+		if err := decodeJobPayload(payload, &export); err != nil {
+			return nil, err
+		}
+
+		path, err := promotionService.RunPromotionExport(ctx, export.PromotionID, export.Export)
+		if err != nil {
+			return nil, err
+		}
+
+		return models.JSONMap{"path": path}, nil
+	}
+}
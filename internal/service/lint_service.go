@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const lintBatchSize = 500
+
+// defaultNames are the placeholder names Postman gives new items
+var defaultNames = map[string]bool{
+	"new request":      true,
+	"untitled request": true,
+	"new folder":       true,
+	"untitled folder":  true,
+}
+
+var namingStyles = map[string]*regexp.Regexp{
+	models.NamingStyleKebab: regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`),
+	models.NamingStyleSnake: regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`),
+	models.NamingStyleCamel: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+}
+
+// LintService checks stored collections against naming and authoring conventions
+type LintService struct {
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+}
+
+// NewLintService creates a new lint service
+func NewLintService(
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+) interfaces.LintService {
+	return &LintService{
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+	}
+}
+
+// LintCollection runs the enabled rules over a collection's folders and requests
+func (s *LintService) LintCollection(ctx context.Context, collectionID int64, opts models.LintOptions) (*models.LintReport, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	for _, rule := range opts.Rules {
+		if !slices.Contains(models.LintRules, rule) {
+			return nil, models.NewValidationError("unknown lint rule %q", rule)
+		}
+	}
+	if opts.Naming != "" && !models.ValidNamingStyle(opts.Naming) {
+		return nil, models.NewValidationError("unknown naming style %q", opts.Naming)
+	}
+
+	report := &models.LintReport{
+		CollectionID: collection.ID,
+		Findings:     []models.LintFinding{},
+		Summary:      make(map[string]int),
+	}
+
+	enabled := func(rule string) bool {
+		return len(opts.Rules) == 0 || slices.Contains(opts.Rules, rule)
+	}
+	add := func(finding models.LintFinding) {
+		if enabled(finding.Rule) {
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	folders := map[string]bool{}
+	names := map[string]int{}
+
+	for offset := 0; ; offset += lintBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, lintBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list requests: %w", err)
+		}
+
+		for _, req := range requests {
+			report.LintedRequests++
+			names[req.FolderPath+"\x00"+req.Name]++
+			for path := req.FolderPath; path != ""; path = parentFolder(path) {
+				folders[path] = true
+			}
+
+			for _, finding := range lintRequest(req, opts.Naming) {
+				add(finding)
+			}
+		}
+
+		if len(requests) < lintBatchSize {
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		count := names[key]
+		if count < 2 {
+			continue
+		}
+		folder, name, _ := strings.Cut(key, "\x00")
+		add(models.LintFinding{
+			RequestName: name,
+			Folder:      folder,
+			Rule:        models.LintRuleDuplicateName,
+			Severity:    models.LintSeverityWarning,
+			Message:     fmt.Sprintf("%d requests in this folder share the name %q", count, name),
+		})
+	}
+
+	paths := make([]string, 0, len(folders))
+	for path := range folders {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		name := path[strings.LastIndex(path, "/")+1:]
+		for _, finding := range lintName(name, "folder", opts.Naming) {
+			finding.Folder = path
+			add(finding)
+		}
+	}
+
+	for _, f := range report.Findings {
+		report.Summary[f.Rule]++
+	}
+
+	return report, nil
+}
+
+// lintRequest checks a single request
+func lintRequest(req *models.Request, naming string) []models.LintFinding {
+	findings := lintName(req.Name, "request", naming)
+
+	if strings.TrimSpace(req.Description) == "" {
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintRuleMissingDescription,
+			Severity: models.LintSeverityInfo,
+			Message:  "request has no description",
+		})
+	}
+
+	if raw, _ := req.URL["raw"].(string); isHardcodedURL(raw) {
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintRuleHardcodedURL,
+			Severity: models.LintSeverityWarning,
+			Message:  fmt.Sprintf("URL %q is hard-coded; start it with a variable such as {{baseUrl}}", raw),
+		})
+	}
+
+	if len(req.Assertions) == 0 && !hasTests(req.Events) {
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintRuleMissingTests,
+			Severity: models.LintSeverityInfo,
+			Message:  "request has no test script or assertions",
+		})
+	}
+
+	for i := range findings {
+		findings[i].RequestID = req.ID
+		findings[i].RequestName = req.Name
+		findings[i].Folder = req.FolderPath
+	}
+
+	return findings
+}
+
+// lintName checks a folder or request name against the naming rules
+func lintName(name, kind, naming string) []models.LintFinding {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return []models.LintFinding{{
+			Rule:     models.LintRuleEmptyName,
+			Severity: models.LintSeverityError,
+			Message:  kind + " has no name",
+		}}
+	}
+
+	var findings []models.LintFinding
+	if defaultNames[strings.ToLower(trimmed)] {
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintRuleDefaultName,
+			Severity: models.LintSeverityWarning,
+			Message:  fmt.Sprintf("%s still has the placeholder name %q", kind, trimmed),
+		})
+	}
+
+	if naming != "" && !matchesNamingStyle(name, naming) {
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintRuleNamingStyle,
+			Severity: models.LintSeverityWarning,
+			Message:  fmt.Sprintf("%s name %q is not %s case", kind, name, naming),
+		})
+	}
+
+	return findings
+}
+
+// matchesNamingStyle reports whether name follows style
+func matchesNamingStyle(name, style string) bool {
+	if name != strings.TrimSpace(name) {
+		return false
+	}
+
+	switch style {
+	case models.NamingStyleTitle:
+		for _, word := range strings.Fields(name) {
+			if r, _ := utf8.DecodeRuneInString(word); unicode.IsLower(r) {
+				return false
+			}
+		}
+		return true
+	case models.NamingStyleSentence:
+		r, _ := utf8.DecodeRuneInString(name)
+		return !unicode.IsLower(r)
+	default:
+		return namingStyles[style].MatchString(name)
+	}
+}
+
+// isHardcodedURL reports whether a raw URL names a literal host instead of a variable
+func isHardcodedURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "{{") {
+		return false
+	}
+
+	lower := strings.ToLower(raw)
+	if scheme, rest, ok := strings.Cut(lower, "://"); ok && (scheme == "http" || scheme == "https") {
+		return !strings.HasPrefix(rest, "{{")
+	}
+
+	return !strings.HasPrefix(raw, "/")
+}
+
+// hasTests reports whether any enabled test event has a non-blank script
+func hasTests(events []models.PostmanEvent) bool {
+	for _, event := range events {
+		if event.Listen != "test" || event.Disabled {
+			continue
+		}
+		for _, line := range event.Script.Exec {
+			if strings.TrimSpace(line) != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parentFolder returns the path of the folder containing path
+func parentFolder(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -4,27 +4,45 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"postman-api/internal/codegen"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
+	"postman-api/internal/specdiff"
+	"strings"
 	"time"
 )
 
 // OpenAPIService handles business logic for OpenAPI specifications
 type OpenAPIService struct {
-	openAPIRepo interfaces.OpenAPIRepository
+	openAPIRepo   interfaces.OpenAPIRepository
+	revisionRepo  interfaces.SpecRevisionRepository
+	reviewService interfaces.SpecReviewService
+	deduplicate   bool
 }
 
-// NewOpenAPIService creates a new OpenAPI service
+// NewOpenAPIService creates a new OpenAPI service; with deduplicate set,
+// re-importing an identical document returns the existing spec. Gateway
+// exports wait for the approval reviewService requires.
 func NewOpenAPIService(
 	openAPIRepo interfaces.OpenAPIRepository,
+	revisionRepo interfaces.SpecRevisionRepository,
+	reviewService interfaces.SpecReviewService,
+	deduplicate bool,
 ) interfaces.OpenAPIService {
 	return &OpenAPIService{
-		openAPIRepo: openAPIRepo,
+		openAPIRepo:   openAPIRepo,
+		revisionRepo:  revisionRepo,
+		reviewService: reviewService,
+		deduplicate:   deduplicate,
 	}
 }
 
 // CreateOpenAPISpec creates a new OpenAPI specification
 func (s *OpenAPIService) CreateOpenAPISpec(ctx context.Context, spec *models.OpenAPISpec) error {
+	if err := validateSpec(spec); err != nil {
+		return err
+	}
+
 	return s.openAPIRepo.Create(ctx, spec)
 }
 
@@ -33,13 +51,21 @@ func (s *OpenAPIService) GetOpenAPISpec(ctx context.Context, id int64) (*models.
 	return s.openAPIRepo.GetByID(ctx, id)
 }
 
+// GetOpenAPISpecs retrieves the OpenAPI specifications with the given IDs in
+// the order asked for, skipping IDs without one
+func (s *OpenAPIService) GetOpenAPISpecs(ctx context.Context, ids []int64) ([]*models.OpenAPISpec, error) {
+	return getByIDs(ctx, ids, s.openAPIRepo.GetByIDs, func(spec *models.OpenAPISpec) int64 {
+		return spec.ID
+	})
+}
+
 // GetOpenAPISpecByTitle retrieves an OpenAPI specification by title
 func (s *OpenAPIService) GetOpenAPISpecByTitle(ctx context.Context, title string) (*models.OpenAPISpec, error) {
 	return s.openAPIRepo.GetByTitle(ctx, title)
 }
 
 // ListOpenAPISpecs returns all OpenAPI specifications with pagination
-func (s *OpenAPIService) ListOpenAPISpecs(ctx context.Context, page, pageSize int) ([]*models.OpenAPISpec, int, error) {
+func (s *OpenAPIService) ListOpenAPISpecs(ctx context.Context, page, pageSize int, countMode models.CountMode) ([]*models.OpenAPISpec, models.Total, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -51,11 +77,42 @@ func (s *OpenAPIService) ListOpenAPISpecs(ctx context.Context, page, pageSize in
 	offset := (page - 1) * pageSize
 
 	specs, err := s.openAPIRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, models.Total{}, err
+	}
+
+	total, err := resolveTotal(ctx, countMode, s.openAPIRepo.Count, s.openAPIRepo.EstimateCount)
+	if err != nil {
+		return nil, models.Total{}, err
+	}
+
+	return specs, total, nil
+}
+
+// SearchOpenAPISpecs returns the specifications whose title or description
+// contains query with pagination, newest first
+func (s *OpenAPIService) SearchOpenAPISpecs(ctx context.Context, query string, page, pageSize int) ([]*models.OpenAPISpec, int, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, models.NewValidationError("search query q is required")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	specs, err := s.openAPIRepo.Search(ctx, query, offset, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.openAPIRepo.Count(ctx)
+	total, err := s.openAPIRepo.CountSearch(ctx, query)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -65,12 +122,20 @@ func (s *OpenAPIService) ListOpenAPISpecs(ctx context.Context, page, pageSize in
 
 // UpdateOpenAPISpec updates an existing OpenAPI specification
 func (s *OpenAPIService) UpdateOpenAPISpec(ctx context.Context, spec *models.OpenAPISpec) error {
+	if err := validateSpec(spec); err != nil {
+		return err
+	}
+
 	existingSpec, err := s.openAPIRepo.GetByID(ctx, spec.ID)
 	if err != nil {
 		return fmt.Errorf("OpenAPI specification not found: %w", err)
 	}
 
 	spec.CreatedAt = existingSpec.CreatedAt
+	if spec.Metadata == nil {
+		spec.Metadata = existingSpec.Metadata
+	}
+	spec.Metadata = keepProvenance(spec.Metadata, existingSpec.Metadata)
 	spec.UpdatedAt = time.Now()
 
 	return s.openAPIRepo.Update(ctx, spec)
@@ -81,47 +146,175 @@ func (s *OpenAPIService) DeleteOpenAPISpec(ctx context.Context, id int64) error
 	return s.openAPIRepo.Delete(ctx, id)
 }
 
-// ImportOpenAPISpec imports an OpenAPI specification from JSON
-func (s *OpenAPIService) ImportOpenAPISpec(ctx context.Context, data []byte) (int64, error) {
-	var content models.JSONMap
-	if err := json.Unmarshal(data, &content); err != nil {
-		return 0, fmt.Errorf("invalid OpenAPI format: %w", err)
+// ListSpecRevisions returns the earlier contents of a spec with pagination,
+// newest first, without the contents themselves
+func (s *OpenAPIService) ListSpecRevisions(ctx context.Context, id int64, page, pageSize int) ([]*models.OpenAPISpecRevision, int, error) {
+	if _, err := s.openAPIRepo.GetByID(ctx, id); err != nil {
+		return nil, 0, fmt.Errorf("OpenAPI specification not found: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	revisions, err := s.revisionRepo.ListBySpecID(ctx, id, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.revisionRepo.CountBySpecID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// DiffOpenAPISpec compares two contents of a spec by revision number. A from
+// of zero is the latest revision and a to of zero the current content.
+func (s *OpenAPIService) DiffOpenAPISpec(ctx context.Context, id int64, from, to int) (*models.SpecDiff, error) {
+	spec, err := s.openAPIRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAPI specification not found: %w", err)
 	}
 
-	info, ok := content["info"].(map[string]any)
-	if !ok {
-		return 0, fmt.Errorf("invalid OpenAPI format: missing or invalid 'info' object")
+	if from == 0 {
+		latest, err := s.revisionRepo.ListBySpecID(ctx, id, 0, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(latest) == 0 {
+			return nil, models.NewValidationError("OpenAPI specification %d has no earlier revisions to compare with", id)
+		}
+		from = latest[0].Revision
 	}
 
-	title, ok := info["title"].(string)
-	if !ok || title == "" {
-		return 0, fmt.Errorf("invalid OpenAPI format: missing or invalid 'title'")
+	diff := &models.SpecDiff{SpecID: id, From: from, To: to}
+
+	older, err := s.revisionRepo.GetByRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
 	}
+	diff.FromVersion = older.Version
 
-	version, ok := info["version"].(string)
-	if !ok || version == "" {
-		return 0, fmt.Errorf("invalid OpenAPI format: missing or invalid 'version'")
+	newer := spec.Content
+	diff.ToVersion = spec.Version
+	if to != 0 {
+		revision, err := s.revisionRepo.GetByRevision(ctx, id, to)
+		if err != nil {
+			return nil, err
+		}
+		newer = revision.Content
+		diff.ToVersion = revision.Version
 	}
 
-	description := ""
-	if desc, ok := info["description"].(string); ok {
-		description = desc
+	diff.Paths, diff.Operations, diff.Schemas = specdiff.Diff(older.Content, newer)
+
+	return diff, nil
+}
+
+// CompareOpenAPISpec classifies the changes to a spec since another spec,
+// when againstSpecID is non-zero, or one of its own revisions; a revision of
+// zero is the latest one
+func (s *OpenAPIService) CompareOpenAPISpec(ctx context.Context, id, againstSpecID int64, againstRevision int) (*models.SpecComparison, error) {
+	if againstSpecID != 0 && againstRevision != 0 {
+		return nil, models.NewValidationError("against_spec_id and against_revision cannot both be set")
+	}
+
+	spec, err := s.openAPIRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAPI specification not found: %w", err)
 	}
 
+	comparison := &models.SpecComparison{SpecID: id}
+	var base models.JSONMap
+	if againstSpecID != 0 {
+		against, err := s.openAPIRepo.GetByID(ctx, againstSpecID)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAPI specification to compare against not found: %w", err)
+		}
+		base = against.Content
+		comparison.AgainstSpecID = againstSpecID
+	} else {
+		if againstRevision == 0 {
+			latest, err := s.revisionRepo.ListBySpecID(ctx, id, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(latest) == 0 {
+				return nil, models.NewValidationError("OpenAPI specification %d has no earlier revisions to compare with", id)
+			}
+			againstRevision = latest[0].Revision
+		}
+
+		revision, err := s.revisionRepo.GetByRevision(ctx, id, againstRevision)
+		if err != nil {
+			return nil, err
+		}
+		base = revision.Content
+		comparison.AgainstRevision = againstRevision
+	}
+
+	comparison.Changes = specdiff.Compare(base, spec.Content)
+	if comparison.Changes == nil {
+		comparison.Changes = []models.SpecChange{}
+	}
+	for _, change := range comparison.Changes {
+		if change.Breaking {
+			comparison.BreakingCount++
+		}
+	}
+	comparison.Breaking = comparison.BreakingCount > 0
+
+	return comparison, nil
+}
+
+// ImportOpenAPISpec imports an OpenAPI specification from JSON
+func (s *OpenAPIService) ImportOpenAPISpec(ctx context.Context, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	hash := importHash(data, opts)
+	if s.deduplicate {
+		existing, err := s.openAPIRepo.FindByMetadata(ctx, models.ContentHashMetadataKey, hash)
+		if err == nil {
+			return &models.ImportResult{ID: existing.ID, Existing: true}, nil
+		}
+		if models.ErrorCodeOf(err) != models.ErrCodeNotFound {
+			return nil, err
+		}
+	}
+
+	doc, result := parseOpenAPISpec(data)
+	if !result.Valid {
+		return nil, models.NewValidationError("invalid OpenAPI format: %s", result.Errors[0].Message)
+	}
+	reportParsed(opts, result)
+
 	spec := &models.OpenAPISpec{
-		Title:       title,
-		Description: description,
-		Version:     version,
-		Content:     content,
+		Title:       doc.title,
+		Description: doc.description,
+		Version:     doc.version,
+		Content:     doc.content,
+		Metadata:    withProvenance(withContentHash(opts.Metadata, hash), opts.Provenance, data),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
 	if err := s.openAPIRepo.Create(ctx, spec); err != nil {
-		return 0, fmt.Errorf("failed to create OpenAPI spec: %w", err)
+		return nil, fmt.Errorf("failed to create OpenAPI spec: %w", err)
 	}
 
-	return spec.ID, nil
+	return &models.ImportResult{ID: spec.ID}, nil
+}
+
+// ValidateOpenAPISpec runs the import validation pipeline without persisting anything
+func (s *OpenAPIService) ValidateOpenAPISpec(ctx context.Context, data []byte) (*models.ValidationResult, error) {
+	_, result := parseOpenAPISpec(data)
+	return result, nil
 }
 
 // ExportOpenAPISpec exports an OpenAPI specification to JSON
@@ -137,3 +330,118 @@ func (s *OpenAPIService) ExportOpenAPISpec(ctx context.Context, id int64) ([]byt
 
 	return json.MarshalIndent(spec.Content, "", "  ")
 }
+
+// GenerateTypeScript renders TypeScript declarations for the spec's component schemas
+func (s *OpenAPIService) GenerateTypeScript(ctx context.Context, id int64) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.TypeScript(content), nil
+}
+
+// GenerateGoClient renders Go types and a typed client for the spec as a zip archive
+func (s *OpenAPIService) GenerateGoClient(ctx context.Context, id int64, pkg string) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := codegen.Go(content, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.Zip(pkg, files)
+}
+
+// GenerateGoServer renders Go handler stubs and route registration for the spec as a zip archive
+func (s *OpenAPIService) GenerateGoServer(ctx context.Context, id int64, pkg, framework string) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := codegen.GoServer(content, pkg, framework)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.Zip(pkg, files)
+}
+
+// GenerateProto renders a .proto file with messages and an HTTP-annotated service for the spec
+func (s *OpenAPIService) GenerateProto(ctx context.Context, id int64, pkg string) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.Proto(content, pkg)
+}
+
+// ExportGateway renders configuration that provisions the spec's operations
+// on an API gateway, once the spec is approved
+func (s *OpenAPIService) ExportGateway(ctx context.Context, id int64, target string) ([]byte, error) {
+	if err := s.reviewService.RequireApproved(ctx, id); err != nil {
+		return nil, err
+	}
+
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := codegen.Gateway(content, target)
+	if err != nil {
+		return nil, models.NewValidationError("cannot export for %s: %s", target, err.Error())
+	}
+
+	return data, nil
+}
+
+// ExportKubernetes renders Kubernetes manifests routing the spec's paths to a backend service
+func (s *OpenAPIService) ExportKubernetes(ctx context.Context, id int64, opts models.ManifestOptions) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return codegen.Kubernetes(content, codegen.KubernetesOptions{
+		Kind:      opts.Kind,
+		Namespace: opts.Namespace,
+		Service:   opts.Service,
+		Port:      opts.Port,
+		Gateway:   opts.Gateway,
+	})
+}
+
+// GenerateTerraform renders Terraform resources that provision the spec's operations
+func (s *OpenAPIService) GenerateTerraform(ctx context.Context, id int64, provider string) ([]byte, error) {
+	content, err := s.specContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := codegen.Terraform(content, provider)
+	if err != nil {
+		return nil, models.NewValidationError("cannot generate terraform for %s: %s", provider, err.Error())
+	}
+
+	return data, nil
+}
+
+// specContent loads the stored document of a spec for code generation
+func (s *OpenAPIService) specContent(ctx context.Context, id int64) (models.JSONMap, error) {
+	spec, err := s.GetOpenAPISpec(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI spec: %w", err)
+	}
+
+	if spec.Content == nil {
+		return nil, fmt.Errorf("OpenAPI spec has no content")
+	}
+
+	return spec.Content, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/models"
+	"sort"
+	"strings"
+)
+
+var openAPIOperationMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// parsedOpenAPISpec holds the fields extracted from a valid OpenAPI document
+type parsedOpenAPISpec struct {
+	content     models.JSONMap
+	title       string
+	version     string
+	description string
+}
+
+// parseOpenAPISpec runs the import validation pipeline over an OpenAPI document.
+// Errors make the document unimportable; warnings are reported but tolerated.
+func parseOpenAPISpec(data []byte) (*parsedOpenAPISpec, *models.ValidationResult) {
+	result := &models.ValidationResult{
+		Errors:   []models.ValidationIssue{},
+		Warnings: []models.ValidationIssue{},
+		Summary:  make(map[string]int),
+	}
+
+	var content models.JSONMap
+	if err := json.Unmarshal(data, &content); err != nil {
+		result.AddError("", err.Error())
+		return nil, result
+	}
+
+	info, ok := content["info"].(map[string]any)
+	if !ok {
+		result.AddError("info", "missing or invalid 'info' object")
+		return nil, result
+	}
+
+	title, ok := info["title"].(string)
+	if !ok || title == "" {
+		result.AddError("info.title", "missing or invalid 'title'")
+	}
+
+	version, ok := info["version"].(string)
+	if !ok || version == "" {
+		result.AddError("info.version", "missing or invalid 'version'")
+	}
+
+	description, _ := info["description"].(string)
+
+	if _, ok := content["openapi"].(string); !ok {
+		if _, ok := content["swagger"].(string); !ok {
+			result.AddWarning("openapi", "missing 'openapi' or 'swagger' version field")
+		}
+	}
+
+	validateOpenAPIPaths(content, result)
+	validateOpenAPIRefs(content, content, "", result)
+
+	result.Valid = len(result.Errors) == 0
+	if !result.Valid {
+		return nil, result
+	}
+
+	return &parsedOpenAPISpec{
+		content:     content,
+		title:       title,
+		version:     version,
+		description: description,
+	}, result
+}
+
+func validateOpenAPIPaths(content models.JSONMap, result *models.ValidationResult) {
+	paths, ok := content["paths"].(map[string]any)
+	if !ok || len(paths) == 0 {
+		result.AddWarning("paths", "document defines no paths")
+		return
+	}
+
+	operationIDs := make(map[string]string)
+	for _, path := range sortedKeys(paths) {
+		rawItem := paths[path]
+		result.Summary["paths"]++
+
+		if !strings.HasPrefix(path, "/") {
+			result.AddWarning("paths."+path, "path should start with '/'")
+		}
+
+		item, ok := rawItem.(map[string]any)
+		if !ok {
+			result.AddWarning("paths."+path, "path item must be an object")
+			continue
+		}
+
+		for _, key := range sortedKeys(item) {
+			rawOp := item[key]
+			if key == "parameters" || key == "summary" || key == "description" || key == "servers" || key == "$ref" || strings.HasPrefix(key, "x-") {
+				continue
+			}
+
+			location := fmt.Sprintf("paths.%s.%s", path, key)
+			if !openAPIOperationMethods[key] {
+				result.AddWarning(location, fmt.Sprintf("unknown operation method %q", key))
+				continue
+			}
+
+			result.Summary["operations"]++
+
+			op, ok := rawOp.(map[string]any)
+			if !ok {
+				result.AddWarning(location, "operation must be an object")
+				continue
+			}
+
+			if responses, ok := op["responses"].(map[string]any); !ok || len(responses) == 0 {
+				result.AddWarning(location+".responses", "operation defines no responses")
+			}
+
+			if id, ok := op["operationId"].(string); ok && id != "" {
+				if previous, exists := operationIDs[id]; exists {
+					result.AddWarning(location+".operationId", fmt.Sprintf("duplicate operationId %q, also used by %s", id, previous))
+				} else {
+					operationIDs[id] = location
+				}
+			}
+		}
+	}
+}
+
+// validateOpenAPIRefs reports local $ref pointers that don't resolve within the document
+func validateOpenAPIRefs(root models.JSONMap, node any, location string, result *models.ValidationResult) {
+	switch v := node.(type) {
+	case models.JSONMap:
+		validateOpenAPIRefs(root, map[string]any(v), location, result)
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			if _, found := resolveLocalRef(root, ref); !found {
+				result.AddWarning(location, fmt.Sprintf("unresolved reference %q", ref))
+			}
+		}
+		for _, key := range sortedKeys(v) {
+			validateOpenAPIRefs(root, v[key], joinLocation(location, key), result)
+		}
+	case []any:
+		for i, child := range v {
+			validateOpenAPIRefs(root, child, fmt.Sprintf("%s[%d]", location, i), result)
+		}
+	}
+}
+
+// resolveLocalRef follows a "#/a/b" JSON pointer within the document
+func resolveLocalRef(root models.JSONMap, ref string) (any, bool) {
+	var current any = map[string]any(root)
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func joinLocation(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"postman-api/internal/models"
+)
+
+// estimatedCountThreshold is the estimated row count above which CountAuto
+// reports the planner estimate instead of running an exact COUNT(*)
+const estimatedCountThreshold = 100000
+
+// maxBatchIDs bounds how many resources can be fetched by ID in one call
+const maxBatchIDs = 100
+
+// getByIDs fetches the resources with the given IDs in one call to fetch and
+// returns them in the order asked for; unknown and repeated IDs are skipped
+func getByIDs[T any](
+	ctx context.Context,
+	ids []int64,
+	fetch func(ctx context.Context, ids []int64) ([]T, error),
+	idOf func(T) int64,
+) ([]T, error) {
+	if len(ids) == 0 {
+		return nil, models.NewValidationError("ids must not be empty")
+	}
+
+	if len(ids) > maxBatchIDs {
+		return nil, models.NewValidationError("at most %d ids can be fetched at once", maxBatchIDs)
+	}
+
+	found, err := fetch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]T, len(found))
+	for _, item := range found {
+		byID[idOf(item)] = item
+	}
+
+	ordered := make([]T, 0, len(found))
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			ordered = append(ordered, item)
+			delete(byID, id)
+		}
+	}
+
+	return ordered, nil
+}
+
+// resolveTotal computes a list total according to mode, falling back to an
+// exact count whenever no estimate is available
+func resolveTotal(
+	ctx context.Context,
+	mode models.CountMode,
+	exact func(ctx context.Context) (int, error),
+	estimate func(ctx context.Context) (int, error),
+) (models.Total, error) {
+	if mode != models.CountExact {
+		estimated, err := estimate(ctx)
+		if err == nil && (mode == models.CountEstimate || estimated >= estimatedCountThreshold) {
+			return models.Total{Count: estimated, Estimated: true}, nil
+		}
+	}
+
+	count, err := exact(ctx)
+	if err != nil {
+		return models.Total{}, err
+	}
+
+	return models.Total{Count: count}, nil
+}
@@ -3,34 +3,58 @@ package service
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
+	"postman-api/internal/assertions"
 	"postman-api/internal/interfaces"
 	"postman-api/internal/models"
+	"postman-api/internal/storage"
+	"time"
 )
 
 // RequestService handles business logic for API requests
 type RequestService struct {
 	requestRepo    interfaces.RequestRepository
 	collectionRepo interfaces.CollectionRepository
+	folderRepo     interfaces.FolderRepository
+	presetRepo     interfaces.HeaderPresetRepository
+	bodies         *responseBodies
 }
 
-// NewRequestService creates a new request service
+// NewRequestService creates a new request service; saved response bodies
+// are stored according to policy, large ones in store
 func NewRequestService(
 	requestRepo interfaces.RequestRepository,
 	collectionRepo interfaces.CollectionRepository,
+	folderRepo interfaces.FolderRepository,
+	presetRepo interfaces.HeaderPresetRepository,
+	store storage.BlobStore,
+	policy models.ResponseBodyPolicy,
 ) interfaces.RequestService {
 	return &RequestService{
 		requestRepo:    requestRepo,
 		collectionRepo: collectionRepo,
+		folderRepo:     folderRepo,
+		presetRepo:     presetRepo,
+		bodies:         &responseBodies{store: store, policy: policy},
 	}
 }
 
 // CreateRequest creates a new API request
 func (s *RequestService) CreateRequest(ctx context.Context, request *models.Request) error {
-	_, err := s.collectionRepo.GetByID(ctx, request.CollectionID)
+	var errs models.FieldErrors
+	validateRequest(&errs, "", request)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	_, err := editableCollection(ctx, s.collectionRepo, request.CollectionID)
 	if err != nil {
-		return fmt.Errorf("collection not found: %w", err)
+		return err
+	}
+
+	if err := checkHeaderPresets(ctx, s.presetRepo, "header_presets", request.HeaderPresets); err != nil {
+		return err
 	}
 
 	// Validate URL is valid JSON
@@ -48,6 +72,14 @@ func (s *RequestService) CreateRequest(ctx context.Context, request *models.Requ
 		request.URL = models.JSONMap{}
 	}
 
+	if err := placeRequest(ctx, s.folderRepo, s.requestRepo, request, nil); err != nil {
+		return err
+	}
+
+	if request.Responses, err = s.bodies.offload(ctx, request.Responses); err != nil {
+		return err
+	}
+
 	return s.requestRepo.Create(ctx, request)
 }
 
@@ -56,8 +88,37 @@ func (s *RequestService) GetRequest(ctx context.Context, id int64) (*models.Requ
 	return s.requestRepo.GetByID(ctx, id)
 }
 
+// GetRequests retrieves the requests with the given IDs in the order asked
+// for, skipping IDs without one
+func (s *RequestService) GetRequests(ctx context.Context, ids []int64) ([]*models.Request, error) {
+	return getByIDs(ctx, ids, s.requestRepo.GetByIDs, func(request *models.Request) int64 {
+		return request.ID
+	})
+}
+
+// OpenResponseBody streams the body of a request's saved response, including
+// bodies offloaded to blob storage
+func (s *RequestService) OpenResponseBody(ctx context.Context, id int64, index int) (*models.PostmanResponse, io.ReadCloser, error) {
+	request, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request not found: %w", err)
+	}
+
+	if index < 0 || index >= len(request.Responses) {
+		return nil, nil, models.NewNotFoundError(fmt.Sprintf("saved response %d of request %d", index, id), nil)
+	}
+
+	resp := &request.Responses[index]
+	body, err := s.bodies.open(ctx, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, body, nil
+}
+
 // ListRequests returns all requests with pagination
-func (s *RequestService) ListRequests(ctx context.Context, page, pageSize int) ([]*models.Request, int, error) {
+func (s *RequestService) ListRequests(ctx context.Context, page, pageSize int, countMode models.CountMode) ([]*models.Request, models.Total, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -70,22 +131,22 @@ func (s *RequestService) ListRequests(ctx context.Context, page, pageSize int) (
 
 	requests, err := s.requestRepo.List(ctx, offset, pageSize)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
-	total, err := s.requestRepo.Count(ctx)
+	total, err := resolveTotal(ctx, countMode, s.requestRepo.Count, s.requestRepo.EstimateCount)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
 	return requests, total, nil
 }
 
 // ListRequestsByCollection returns all requests in a collection with pagination
-func (s *RequestService) ListRequestsByCollection(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.Request, int, error) {
+func (s *RequestService) ListRequestsByCollection(ctx context.Context, collectionID int64, page, pageSize int, countMode models.CountMode) ([]*models.Request, models.Total, error) {
 	_, err := s.collectionRepo.GetByID(ctx, collectionID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("collection not found: %w", err)
+		return nil, models.Total{}, fmt.Errorf("collection not found: %w", err)
 	}
 
 	if page < 1 {
@@ -100,12 +161,19 @@ func (s *RequestService) ListRequestsByCollection(ctx context.Context, collectio
 
 	requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, pageSize)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
-	total, err := s.requestRepo.CountByCollectionID(ctx, collectionID)
+	total, err := resolveTotal(ctx, countMode,
+		func(ctx context.Context) (int, error) {
+			return s.requestRepo.CountByCollectionID(ctx, collectionID)
+		},
+		func(ctx context.Context) (int, error) {
+			return s.requestRepo.EstimateCountByCollectionID(ctx, collectionID)
+		},
+	)
 	if err != nil {
-		return nil, 0, err
+		return nil, models.Total{}, err
 	}
 
 	return requests, total, nil
@@ -145,9 +213,8 @@ func (s *RequestService) ListRequestsByCollection(ctx context.Context, collectio
 
 // DeleteRequest removes a request
 func (s *RequestService) DeleteRequest(ctx context.Context, id int64) error {
-	_, err := s.requestRepo.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("request not found: %w", err)
+	if _, err := s.editableRequest(ctx, id); err != nil {
+		return err
 	}
 
 	return s.requestRepo.Delete(ctx, id)
@@ -156,12 +223,12 @@ func (s *RequestService) DeleteRequest(ctx context.Context, id int64) error {
 // UpdateRequestPayload updates only the payload (body) of a request
 func (s *RequestService) UpdateRequestPayload(ctx context.Context, id int64, body models.JSONMap) error {
 	if body == nil {
-		return errors.New("body cannot be nil")
+		return models.NewValidationError("body cannot be nil")
 	}
 
-	request, err := s.requestRepo.GetByID(ctx, id)
+	request, err := s.editableRequest(ctx, id)
 	if err != nil {
-		return fmt.Errorf("request not found: %w", err)
+		return err
 	}
 
 	request.Body = body
@@ -171,40 +238,140 @@ func (s *RequestService) UpdateRequestPayload(ctx context.Context, id int64, bod
 // UpdateRequestHeaders updates only the headers of a request
 func (s *RequestService) UpdateRequestHeaders(ctx context.Context, id int64, headers map[string]string) error {
 	if headers == nil {
-		return errors.New("headers cannot be nil")
+		return models.NewValidationError("headers cannot be nil")
 	}
 
-	request, err := s.requestRepo.GetByID(ctx, id)
+	var errs models.FieldErrors
+	validateHeaders(&errs, "headers", headers)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	request, err := s.editableRequest(ctx, id)
 	if err != nil {
-		return fmt.Errorf("request not found: %w", err)
+		return err
 	}
 
 	request.Headers = headers
 	return s.requestRepo.Update(ctx, request)
 }
 
+// UpdateRequestHeaderPresets replaces the header presets attached to a
+// request, applied in order beneath its own headers
+func (s *RequestService) UpdateRequestHeaderPresets(ctx context.Context, id int64, presetIDs []int64) error {
+	if presetIDs == nil {
+		return models.NewValidationError("header_presets cannot be nil")
+	}
+
+	if err := checkHeaderPresets(ctx, s.presetRepo, "header_presets", presetIDs); err != nil {
+		return err
+	}
+
+	request, err := s.editableRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	request.HeaderPresets = presetIDs
+	return s.requestRepo.Update(ctx, request)
+}
+
 // UpdateRequestParams updates only the query parameters of a request
 func (s *RequestService) UpdateRequestParams(ctx context.Context, id int64, params models.JSONMap) error {
 	if params == nil {
-		return errors.New("params cannot be nil")
+		return models.NewValidationError("params cannot be nil")
 	}
 
-	request, err := s.requestRepo.GetByID(ctx, id)
+	request, err := s.editableRequest(ctx, id)
 	if err != nil {
-		return fmt.Errorf("request not found: %w", err)
+		return err
 	}
 
 	request.Params = params
 	return s.requestRepo.Update(ctx, request)
 }
 
-// CloneRequest creates a copy of an existing request
-func (s *RequestService) CloneRequest(ctx context.Context, id int64, newName string) (int64, error) {
+// UpdateRequestAssertions replaces the declarative assertions of a request
+func (s *RequestService) UpdateRequestAssertions(ctx context.Context, id int64, list []models.Assertion) error {
+	if list == nil {
+		return models.NewValidationError("assertions cannot be nil")
+	}
+
+	if err := assertions.Validate(list); err != nil {
+		return models.NewValidationError("invalid assertions: %v", err)
+	}
+
+	request, err := s.editableRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	request.Assertions = list
+	return s.requestRepo.Update(ctx, request)
+}
+
+// UpdateRequestRunSettings replaces the overrides of how the runner sends a
+// request; empty settings remove them
+func (s *RequestService) UpdateRequestRunSettings(ctx context.Context, id int64, settings models.RunSettings) error {
+	var errs models.FieldErrors
+	validateRunSettings(&errs, "", &settings)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	request, err := s.editableRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	request.RunSettings = &settings
+	if settings == (models.RunSettings{}) {
+		request.RunSettings = nil
+	}
+	return s.requestRepo.Update(ctx, request)
+}
+
+// UpdateRequestDeprecation flags or unflags a request as deprecated; the
+// sunset date is cleared when the flag is removed
+func (s *RequestService) UpdateRequestDeprecation(ctx context.Context, id int64, deprecated bool, sunset *time.Time) error {
+	request, err := s.editableRequest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !deprecated {
+		sunset = nil
+	}
+
+	request.Deprecated = deprecated
+	request.Sunset = sunset
+	return s.requestRepo.Update(ctx, request)
+}
+
+// CloneRequest creates a copy of a request, in targetCollectionID when set.
+// The copy goes to the folder at folderPath, created if need be, or when
+// folderPath is nil to the original's folder or the one at the same path.
+func (s *RequestService) CloneRequest(ctx context.Context, id int64, newName string, targetCollectionID int64, folderPath *string) (int64, error) {
+	var errs models.FieldErrors
+	validateName(&errs, "name", newName)
+	if err := errs.Err(); err != nil {
+		return 0, err
+	}
+
 	original, err := s.requestRepo.GetByID(ctx, id)
 	if err != nil {
 		return 0, fmt.Errorf("request not found: %w", err)
 	}
 
+	collectionID := original.CollectionID
+	if targetCollectionID != 0 {
+		collectionID = targetCollectionID
+	}
+
+	if _, err := editableCollection(ctx, s.collectionRepo, collectionID); err != nil {
+		return 0, err
+	}
+
 	urlData := models.JSONMap{}
 	if original.URL != nil {
 		if _, err := json.Marshal(original.URL); err == nil {
@@ -213,14 +380,29 @@ func (s *RequestService) CloneRequest(ctx context.Context, id int64, newName str
 	}
 
 	cloned := &models.Request{
-		CollectionID: original.CollectionID,
-		Name:         newName,
-		Description:  original.Description + " (Cloned)",
-		URL:          urlData,
-		Method:       original.Method,
-		Headers:      original.Headers,
-		Params:       original.Params,
-		Body:         original.Body,
+		CollectionID:  original.CollectionID,
+		Name:          newName,
+		Description:   original.Description + " (Cloned)",
+		FolderID:      original.FolderID,
+		FolderPath:    original.FolderPath,
+		URL:           urlData,
+		Method:        original.Method,
+		Headers:       original.Headers,
+		HeaderPresets: original.HeaderPresets,
+		Params:        original.Params,
+		Body:          original.Body,
+		Auth:          original.Auth,
+		Events:        original.Events,
+		Responses:     original.Responses,
+		Assertions:    original.Assertions,
+		RunSettings:   original.RunSettings,
+		Deprecated:    original.Deprecated,
+		Sunset:        original.Sunset,
+	}
+
+	relocateRequest(cloned, collectionID, folderPath)
+	if err := placeRequest(ctx, s.folderRepo, s.requestRepo, cloned, nil); err != nil {
+		return 0, err
 	}
 
 	if err := s.requestRepo.Create(ctx, cloned); err != nil {
@@ -229,3 +411,64 @@ func (s *RequestService) CloneRequest(ctx context.Context, id int64, newName str
 
 	return cloned.ID, nil
 }
+
+// MoveRequest moves a request to targetCollectionID, or within its own
+// collection when that is zero. It goes to the folder at folderPath, created
+// if need be, or when folderPath is nil to the folder at the same path.
+func (s *RequestService) MoveRequest(ctx context.Context, id int64, targetCollectionID int64, folderPath *string) (*models.Request, error) {
+	request, err := s.editableRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetCollectionID == 0 {
+		targetCollectionID = request.CollectionID
+	}
+
+	if targetCollectionID == request.CollectionID && folderPath == nil {
+		return request, nil
+	}
+
+	if _, err := editableCollection(ctx, s.collectionRepo, targetCollectionID); err != nil {
+		return nil, err
+	}
+
+	previous := *request
+	relocateRequest(request, targetCollectionID, folderPath)
+	if err := placeRequest(ctx, s.folderRepo, s.requestRepo, request, &previous); err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to move request: %w", err)
+	}
+
+	return request, nil
+}
+
+// relocateRequest points a request at collectionID and the folder at
+// folderPath for placeRequest to resolve; with a nil folderPath it keeps its
+// folder within its collection and its folder path elsewhere
+func relocateRequest(request *models.Request, collectionID int64, folderPath *string) {
+	if folderPath != nil {
+		request.FolderID = 0
+		request.FolderPath = *folderPath
+	} else if collectionID != request.CollectionID {
+		request.FolderID = 0
+	}
+	request.CollectionID = collectionID
+}
+
+// editableRequest loads a request, refusing requests of archived collections
+func (s *RequestService) editableRequest(ctx context.Context, id int64) (*models.Request, error) {
+	request, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("request not found: %w", err)
+	}
+
+	if _, err := editableCollection(ctx, s.collectionRepo, request.CollectionID); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"postman-api/internal/models"
+	"postman-api/internal/storage"
+	"strings"
+	"unicode/utf8"
+)
+
+// responseBodies applies the truncation, retention and offloading policy to
+// saved response bodies so large payloads stay out of the responses column
+type responseBodies struct {
+	store  storage.BlobStore
+	policy models.ResponseBodyPolicy
+}
+
+// offload trims responses to the retention limit, truncates oversized bodies
+// and moves the remaining large bodies to blob storage
+func (b *responseBodies) offload(ctx context.Context, responses []models.PostmanResponse) ([]models.PostmanResponse, error) {
+	if b.policy.Retain > 0 && len(responses) > b.policy.Retain {
+		responses = responses[len(responses)-b.policy.Retain:]
+	}
+
+	kept := make([]models.PostmanResponse, len(responses))
+	for i, resp := range responses {
+		if resp.BodyRef == "" {
+			size := int64(len(resp.Body))
+			if b.policy.MaxBytes > 0 && size > b.policy.MaxBytes {
+				resp.Body = truncateUTF8(resp.Body, b.policy.MaxBytes)
+				resp.BodySize = size
+				resp.BodyTruncated = true
+			}
+
+			if b.policy.OffloadBytes > 0 && int64(len(resp.Body)) > b.policy.OffloadBytes {
+				sum := sha256.Sum256([]byte(resp.Body))
+				hash := hex.EncodeToString(sum[:])
+
+				if err := b.store.Put(ctx, responseBodyKey(hash), []byte(resp.Body)); err != nil {
+					return nil, fmt.Errorf("failed to offload response body: %w", err)
+				}
+
+				if !resp.BodyTruncated {
+					resp.BodySize = size
+				}
+				resp.BodyRef = hash
+				resp.Body = ""
+			}
+		}
+		kept[i] = resp
+	}
+
+	return kept, nil
+}
+
+// inline loads offloaded bodies back into their responses, for exports
+func (b *responseBodies) inline(ctx context.Context, responses []models.PostmanResponse) ([]models.PostmanResponse, error) {
+	inlined := make([]models.PostmanResponse, len(responses))
+	for i, resp := range responses {
+		if resp.BodyRef != "" {
+			body, err := b.read(ctx, resp.BodyRef)
+			if err != nil {
+				return nil, err
+			}
+
+			resp.Body = body
+			resp.BodyRef = ""
+			if !resp.BodyTruncated {
+				resp.BodySize = 0
+			}
+		}
+		inlined[i] = resp
+	}
+
+	return inlined, nil
+}
+
+// open streams the body of a saved response, wherever it is stored
+func (b *responseBodies) open(ctx context.Context, resp *models.PostmanResponse) (io.ReadCloser, error) {
+	if resp.BodyRef == "" {
+		return io.NopCloser(strings.NewReader(resp.Body)), nil
+	}
+
+	content, err := b.store.Open(ctx, responseBodyKey(resp.BodyRef))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open response body: %w", err)
+	}
+
+	return content, nil
+}
+
+func (b *responseBodies) read(ctx context.Context, hash string) (string, error) {
+	content, err := b.store.Open(ctx, responseBodyKey(hash))
+	if err != nil {
+		return "", fmt.Errorf("failed to open response body: %w", err)
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func responseBodyKey(hash string) string {
+	return "responses/" + hash
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune
+func truncateUTF8(s string, n int64) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"time"
+)
+
+// retentionBatchSize caps how many items of each kind a single run purges;
+// the rest are picked up by later runs
+const retentionBatchSize = 500
+
+// RetentionService purges archived collections and superseded spec imports
+// once they are older than the retention policy allows
+type RetentionService struct {
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+	openAPIRepo    interfaces.OpenAPIRepository
+	policy         models.RetentionPolicy
+}
+
+// NewRetentionService creates a new retention service enforcing policy
+func NewRetentionService(
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+	openAPIRepo interfaces.OpenAPIRepository,
+	policy models.RetentionPolicy,
+) interfaces.RetentionService {
+	return &RetentionService{
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+		openAPIRepo:    openAPIRepo,
+		policy:         policy,
+	}
+}
+
+// Report lists the items the next enforcement would purge, without deleting anything
+func (s *RetentionService) Report(ctx context.Context) (*models.RetentionReport, error) {
+	return s.run(ctx, true)
+}
+
+// Enforce purges the items past their retention
+func (s *RetentionService) Enforce(ctx context.Context) (*models.RetentionReport, error) {
+	return s.run(ctx, false)
+}
+
+// RunScheduler enforces the policy every interval until ctx is cancelled; it
+// returns at once when the policy purges nothing
+func (s *RetentionService) RunScheduler(ctx context.Context, interval time.Duration) {
+	if !s.policy.Enabled() || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Enforce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to enforce retention policy: %v", err)
+				}
+				continue
+			}
+			if len(report.Collections)+len(report.Specs) > 0 {
+				log.Printf("Retention purged %d collections and %d specs", len(report.Collections), len(report.Specs))
+			}
+		}
+	}
+}
+
+func (s *RetentionService) run(ctx context.Context, dryRun bool) (*models.RetentionReport, error) {
+	report := &models.RetentionReport{
+		DryRun:      dryRun,
+		Collections: []models.RetentionItem{},
+		Specs:       []models.RetentionItem{},
+	}
+	now := time.Now()
+
+	if s.policy.ArchivedCollections > 0 {
+		collections, err := s.collectionRepo.ListArchivedBefore(ctx, now.Add(-s.policy.ArchivedCollections), retentionBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, collection := range collections {
+			if !dryRun {
+				if err := s.requestRepo.DeleteByCollectionID(ctx, collection.ID); err != nil {
+					return nil, fmt.Errorf("failed to delete requests in collection: %w", err)
+				}
+				if err := s.collectionRepo.Delete(ctx, collection.ID); err != nil {
+					return nil, err
+				}
+			}
+
+			report.Collections = append(report.Collections, models.RetentionItem{
+				ID:    collection.ID,
+				Name:  collection.Name,
+				Since: *collection.ArchivedAt,
+			})
+		}
+	}
+
+	if s.policy.SupersededSpecs > 0 {
+		specs, err := s.openAPIRepo.ListSupersededBefore(ctx, now.Add(-s.policy.SupersededSpecs), retentionBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			if !dryRun {
+				if err := s.openAPIRepo.Delete(ctx, spec.ID); err != nil {
+					return nil, err
+				}
+			}
+
+			report.Specs = append(report.Specs, models.RetentionItem{
+				ID:    spec.ID,
+				Name:  spec.Title,
+				Since: spec.CreatedAt,
+			})
+		}
+	}
+
+	return report, nil
+}
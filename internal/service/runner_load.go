@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"postman-api/internal/models"
+	"postman-api/internal/variables"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxLoadTestDuration bounds how long a load test sends requests
+const maxLoadTestDuration = 10 * time.Minute
+
+// maxLoadConcurrency bounds the requests of a load test in flight at once;
+// sends due while that many are in flight are dropped
+const maxLoadConcurrency = 100
+
+// loadStatsInterval is how often the stats of a running load test are saved
+const loadStatsInterval = time.Second
+
+// loadTarget is a request of a load test, resolved once for all its sends
+type loadTarget struct {
+	flat    *models.FlatRequest
+	checks  []models.Assertion
+	timeout time.Duration
+}
+
+// LoadTestCollection queues a load test sending the requests of a
+// collection in folder order, in turn, and returns it; ExecuteLoadTest
+// carries it out
+func (s *RunnerService) LoadTestCollection(ctx context.Context, collectionID int64, opts models.LoadTestOptions) (*models.LoadTest, error) {
+	return s.queueLoadTest(ctx, collectionID, 0, opts)
+}
+
+// LoadTestRequest queues a load test sending a single request and returns
+// it; ExecuteLoadTest carries it out
+func (s *RunnerService) LoadTestRequest(ctx context.Context, requestID int64, opts models.LoadTestOptions) (*models.LoadTest, error) {
+	request, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.queueLoadTest(ctx, request.CollectionID, request.ID, opts)
+}
+
+// queueLoadTest validates the options of a load test of a collection, or of
+// one of its requests, and queues it on the job queue
+func (s *RunnerService) queueLoadTest(ctx context.Context, collectionID, requestID int64, opts models.LoadTestOptions) (*models.LoadTest, error) {
+	if err := validateRunTimeout(opts.TimeoutMs); err != nil {
+		return nil, err
+	}
+
+	var errs models.FieldErrors
+	if opts.RPS < 1 || opts.RPS > maxRunRPS {
+		errs.Add("rps", "must be between 1 and %d", maxRunRPS)
+	}
+	if opts.DurationMs < 1 || time.Duration(opts.DurationMs)*time.Millisecond > maxLoadTestDuration {
+		errs.Add("duration_ms", "must be between 1 and %d", maxLoadTestDuration.Milliseconds())
+	}
+	validateHostOverrides(&errs, "host_overrides", opts.HostOverrides)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := checkHeaderPresets(ctx, s.presetRepo, "header_presets", opts.HeaderPresets); err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if _, err := variableScopes(ctx, s.environmentService, s.globalService, collection, opts.EnvironmentID); err != nil {
+		return nil, err
+	}
+
+	test := &models.LoadTest{
+		CollectionID: collectionID,
+		RequestID:    requestID,
+		Status:       models.LoadTestQueued,
+		RPS:          opts.RPS,
+		DurationMs:   int64(opts.DurationMs),
+		StartedAt:    time.Now(),
+	}
+	if err := s.loadTestRepo.Create(ctx, test); err != nil {
+		return nil, fmt.Errorf("failed to create load test: %w", err)
+	}
+
+	payload, err := jobPayload(queuedLoadTest{TestID: test.ID, Options: opts})
+	if err == nil {
+		test.Job, err = s.jobService.Enqueue(ctx, models.JobLoadTest, payload)
+	}
+	if err != nil {
+		test.Error = "failed to queue load test: " + err.Error()
+		s.finishLoadTest(ctx, test, nil)
+		return nil, fmt.Errorf("failed to queue load test: %w", err)
+	}
+
+	return test, nil
+}
+
+// ExecuteLoadTest sends the requests of a queued load test at its rate for
+// its duration, saving its stats every loadStatsInterval as they accumulate.
+// A load test that cannot be carried out is stored as failed with its error;
+// one already finished is returned as it is.
+func (s *RunnerService) ExecuteLoadTest(ctx context.Context, testID int64, opts models.LoadTestOptions) (*models.LoadTest, error) {
+	test, err := s.loadTestRepo.GetByID(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A load test whose worker was lost is started over
+	if test.Status != models.LoadTestQueued && test.Status != models.LoadTestRunning {
+		return test, nil
+	}
+
+	stats := newLoadStats()
+	test.Status = models.LoadTestRunning
+	test.Stats = stats.snapshot()
+	test.StartedAt = stats.start
+	if err := s.loadTestRepo.Update(ctx, test); err != nil {
+		return nil, fmt.Errorf("failed to start load test: %w", err)
+	}
+
+	if err := s.sendLoad(ctx, test, opts, stats); err != nil {
+		test.Error = err.Error()
+	}
+
+	return s.finishLoadTest(ctx, test, stats), nil
+}
+
+// GetLoadTest retrieves a load test with its stats so far
+func (s *RunnerService) GetLoadTest(ctx context.Context, id int64) (*models.LoadTest, error) {
+	return s.loadTestRepo.GetByID(ctx, id)
+}
+
+// sendLoad sends the requests of a load test, recording each send in
+// stats; it fails when the load test cannot be set up
+func (s *RunnerService) sendLoad(ctx context.Context, test *models.LoadTest, opts models.LoadTestOptions, stats *loadStats) error {
+	targets, err := s.loadTargets(ctx, test, opts)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return models.NewValidationError("collection %d has no requests to send", test.CollectionID)
+	}
+
+	overrides, err := s.hostOverrides(ctx, opts.EnvironmentID, opts.HostOverrides)
+	if err != nil {
+		return err
+	}
+
+	client, release := s.client(overrides, nil)
+	defer release()
+	client, release = loadClient(client)
+	defer release()
+
+	tick := time.NewTicker(time.Second / time.Duration(test.RPS))
+	defer tick.Stop()
+	report := time.NewTicker(loadStatsInterval)
+	defer report.Stop()
+	end := time.NewTimer(time.Duration(test.DurationMs) * time.Millisecond)
+	defer end.Stop()
+
+	inFlight := make(chan struct{}, maxLoadConcurrency)
+	var wg sync.WaitGroup
+	next := 0
+send:
+	for {
+		select {
+		case <-ctx.Done():
+			break send
+		case <-end.C:
+			break send
+		case <-report.C:
+			s.saveLoadStats(ctx, test, stats)
+		case <-tick.C:
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				stats.drop()
+				continue
+			}
+
+			target := targets[next%len(targets)]
+			next++
+			stats.send()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-inFlight }()
+
+				executed, err := s.send(ctx, client, target.flat, target.checks, target.timeout)
+				stats.record(executed, err)
+			}()
+		}
+	}
+
+	// The sends in flight are waited for, up to their timeouts
+	wg.Wait()
+
+	return nil
+}
+
+// loadTargets resolves the requests a load test sends, in the order it
+// sends them
+func (s *RunnerService) loadTargets(ctx context.Context, test *models.LoadTest, opts models.LoadTestOptions) ([]loadTarget, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, test.CollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	scopes, err := variableScopes(ctx, s.environmentService, s.globalService, collection, opts.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []*models.Request
+	if test.RequestID != 0 {
+		request, err := s.requestRepo.GetByID(ctx, test.RequestID)
+		if err != nil {
+			return nil, err
+		}
+		requests = []*models.Request{request}
+	} else if requests, err = runOrder(ctx, s.folderRepo, s.requestRepo, test.CollectionID); err != nil {
+		return nil, err
+	}
+
+	presets := newHeaderPresets(s.presetRepo)
+	targets := make([]loadTarget, 0, len(requests))
+	for _, request := range requests {
+		applied, err := presets.apply(ctx, request, opts.HeaderPresets)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", request.ID, err)
+		}
+
+		targets = append(targets, loadTarget{
+			flat:    flattenRequest(applied, collection.Auth, variables.New(scopes...)),
+			checks:  request.Assertions,
+			timeout: s.timeout(request, opts.TimeoutMs),
+		})
+	}
+
+	return targets, nil
+}
+
+// saveLoadStats stores the stats of a running load test so far
+func (s *RunnerService) saveLoadStats(ctx context.Context, test *models.LoadTest, stats *loadStats) {
+	test.Stats = stats.snapshot()
+	if err := s.loadTestRepo.Update(ctx, test); err != nil {
+		log.Printf("Failed to save load test %d: %v", test.ID, err)
+	}
+}
+
+// finishLoadTest stores the outcome of a load test with its final stats,
+// when it got to send any
+func (s *RunnerService) finishLoadTest(ctx context.Context, test *models.LoadTest, stats *loadStats) *models.LoadTest {
+	finished := time.Now()
+	test.FinishedAt = &finished
+	if stats != nil {
+		test.Stats = stats.snapshot()
+	}
+	switch {
+	case ctx.Err() != nil:
+		test.Status = models.LoadTestAborted
+	case test.Error != "":
+		test.Status = models.LoadTestFailed
+	default:
+		test.Status = models.LoadTestCompleted
+	}
+
+	// The stats are kept even when the worker was stopped mid-test
+	if err := s.loadTestRepo.Update(context.WithoutCancel(ctx), test); err != nil {
+		log.Printf("Failed to save load test %d: %v", test.ID, err)
+	}
+
+	return test
+}
+
+// loadClient returns a client like client keeping enough idle connections
+// for the sends of a load test, and a function closing them once done
+func loadClient(client *http.Client) (*http.Client, func()) {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client, func() {}
+	}
+
+	transport = transport.Clone()
+	transport.MaxIdleConnsPerHost = maxLoadConcurrency
+	loaded := *client
+	loaded.Transport = transport
+
+	return &loaded, transport.CloseIdleConnections
+}
+
+// loadStats accumulates the sends of a load test; sends record their
+// outcome concurrently
+type loadStats struct {
+	mu          sync.Mutex
+	start       time.Time
+	sent        int
+	dropped     int
+	completed   int
+	errors      int
+	latencies   []int64
+	statusCodes map[int]int
+}
+
+// newLoadStats returns the stats of a load test starting now
+func newLoadStats() *loadStats {
+	return &loadStats{start: time.Now(), statusCodes: map[int]int{}}
+}
+
+// send counts a send started
+func (l *loadStats) send() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sent++
+}
+
+// drop counts a send skipped for too many in flight
+func (l *loadStats) drop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dropped++
+}
+
+// record counts the outcome of a send: an error when it got no response or
+// its assertions failed
+func (l *loadStats) record(executed *models.ExecutionResult, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.completed++
+	if err != nil {
+		l.errors++
+		l.statusCodes[0]++
+		return
+	}
+
+	if !executed.Passed {
+		l.errors++
+	}
+	l.statusCodes[executed.StatusCode]++
+	l.latencies = append(l.latencies, executed.LatencyMs)
+}
+
+// snapshot returns the stats so far
+func (l *loadStats) snapshot() *models.LoadTestStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elapsed := time.Since(l.start)
+	stats := &models.LoadTestStats{
+		ElapsedMs:   elapsed.Milliseconds(),
+		Sent:        l.sent,
+		Dropped:     l.dropped,
+		Completed:   l.completed,
+		Errors:      l.errors,
+		Latency:     latencyPercentiles(l.latencies),
+		StatusCodes: make(map[int]int, len(l.statusCodes)),
+	}
+	for status, count := range l.statusCodes {
+		stats.StatusCodes[status] = count
+	}
+	if l.completed > 0 {
+		stats.ErrorRate = float64(l.errors) / float64(l.completed)
+	}
+	if elapsed > 0 {
+		stats.Throughput = float64(l.completed) / elapsed.Seconds()
+	}
+
+	return stats
+}
+
+// latencyPercentiles summarizes latencies, taking the nearest rank for
+// each percentile
+func latencyPercentiles(latencies []int64) models.LatencyPercentiles {
+	if len(latencies) == 0 {
+		return models.LatencyPercentiles{}
+	}
+
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var sum int64
+	for _, latency := range sorted {
+		sum += latency
+	}
+
+	rank := func(p float64) int64 {
+		return sorted[int(math.Ceil(p/100*float64(len(sorted))))-1]
+	}
+
+	return models.LatencyPercentiles{
+		P50:  rank(50),
+		P95:  rank(95),
+		P99:  rank(99),
+		Max:  sorted[len(sorted)-1],
+		Mean: sum / int64(len(sorted)),
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+func TestLatencyPercentiles(t *testing.T) {
+	hundred := make([]int64, 100)
+	for i := range hundred {
+		hundred[i] = int64(100 - i)
+	}
+
+	tests := []struct {
+		name      string
+		latencies []int64
+		want      models.LatencyPercentiles
+	}{
+		{
+			name: "no responses",
+		},
+		{
+			name:      "a single response",
+			latencies: []int64{42},
+			want:      models.LatencyPercentiles{P50: 42, P95: 42, P99: 42, Max: 42, Mean: 42},
+		},
+		{
+			name:      "nearest rank of unsorted latencies",
+			latencies: hundred,
+			want:      models.LatencyPercentiles{P50: 50, P95: 95, P99: 99, Max: 100, Mean: 50},
+		},
+		{
+			name:      "a slow tail",
+			latencies: []int64{10, 10, 10, 10, 10, 10, 10, 10, 10, 1000},
+			want:      models.LatencyPercentiles{P50: 10, P95: 1000, P99: 1000, Max: 1000, Mean: 109},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latencyPercentiles(tt.latencies); got != tt.want {
+				t.Errorf("latencyPercentiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if hundred[0] != 100 {
+		t.Error("latencyPercentiles() sorted its input")
+	}
+}
+
+func TestLoadStats(t *testing.T) {
+	stats := newLoadStats()
+	for range 4 {
+		stats.send()
+	}
+	stats.drop()
+	stats.record(&models.ExecutionResult{StatusCode: 200, LatencyMs: 20, Passed: true}, nil)
+	stats.record(&models.ExecutionResult{StatusCode: 200, LatencyMs: 40, Passed: true}, nil)
+	stats.record(&models.ExecutionResult{StatusCode: 500, LatencyMs: 10, Passed: false}, nil)
+	stats.record(nil, errors.New("connection refused"))
+
+	got := stats.snapshot()
+	if got.Sent != 4 || got.Dropped != 1 || got.Completed != 4 || got.Errors != 2 {
+		t.Errorf("snapshot() counts = sent %d, dropped %d, completed %d, errors %d, want 4, 1, 4, 2", got.Sent, got.Dropped, got.Completed, got.Errors)
+	}
+	if got.ErrorRate != 0.5 {
+		t.Errorf("snapshot() error rate = %v, want 0.5", got.ErrorRate)
+	}
+	if want := map[int]int{0: 1, 200: 2, 500: 1}; !reflect.DeepEqual(got.StatusCodes, want) {
+		t.Errorf("snapshot() status codes = %v, want %v", got.StatusCodes, want)
+	}
+	if want := (models.LatencyPercentiles{P50: 20, P95: 40, P99: 40, Max: 40, Mean: 23}); got.Latency != want {
+		t.Errorf("snapshot() latency = %+v, want %+v", got.Latency, want)
+	}
+	if got.Throughput <= 0 {
+		t.Errorf("snapshot() throughput = %v, want it positive", got.Throughput)
+	}
+}
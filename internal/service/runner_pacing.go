@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRunRPS bounds the requests per second a run may send to a host
+const maxRunRPS = 1000
+
+// hostPacer spaces out the requests of a run to each host, to at most a
+// number per second and no sooner than a Retry-After the host answered with.
+// A run sends one request at a time, so it needs no locking.
+type hostPacer struct {
+	// interval is the least time between two requests to a host; zero
+	// leaves them unspaced
+	interval time.Duration
+	// next holds the earliest time of the next request to each host
+	next map[string]time.Time
+}
+
+// newHostPacer returns a pacer sending at most rps requests per second to
+// each host, or any number for zero
+func newHostPacer(rps int) *hostPacer {
+	pacer := &hostPacer{next: map[string]time.Time{}}
+	if rps > 0 {
+		pacer.interval = time.Second / time.Duration(rps)
+	}
+	return pacer
+}
+
+// wait blocks until a request to rawURL may be sent, or ctx is cancelled
+func (p *hostPacer) wait(ctx context.Context, rawURL string) {
+	next, ok := p.next[pacedHost(rawURL)]
+	if !ok {
+		return
+	}
+
+	delay := time.Until(next)
+	if delay <= 0 {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+// sent records a request to rawURL that was answered with header, nil when
+// it got no response, for the requests to the same host after it
+func (p *hostPacer) sent(rawURL string, header http.Header) {
+	now := time.Now()
+	next := now.Add(p.interval)
+	if retryAfter := parseRetryAfter(header.Get("Retry-After"), now); retryAfter > 0 {
+		next = now.Add(max(min(retryAfter, maxRunDelay), p.interval))
+	}
+
+	if next.After(now) {
+		p.next[pacedHost(rawURL)] = next
+	}
+}
+
+// pacedHost returns the host requests to rawURL are paced by
+func pacedHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// parseRetryAfter returns the wait a Retry-After header asks for, given in
+// seconds or as an HTTP date, or zero
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
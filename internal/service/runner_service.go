@@ -0,0 +1,732 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"postman-api/internal/assertions"
+	"postman-api/internal/events"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/variables"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxExecutedResponseSize bounds how much of a response body the runner keeps
+const maxExecutedResponseSize = 1 << 20
+
+// maxRunDelay bounds the delay between the requests of a collection run
+const maxRunDelay = time.Minute
+
+// maxRequestTimeout bounds the timeout given for a request sent by the runner
+const maxRequestTimeout = 10 * time.Minute
+
+// maxRetryAttempts bounds how many times a run sends a failing request
+const maxRetryAttempts = 10
+
+// RunnerService sends stored requests over HTTP and checks their assertions
+type RunnerService struct {
+	requestRepo        interfaces.RequestRepository
+	collectionRepo     interfaces.CollectionRepository
+	folderRepo         interfaces.FolderRepository
+	runRepo            interfaces.RunRepository
+	snapshotRepo       interfaces.ResponseSnapshotRepository
+	loadTestRepo       interfaces.LoadTestRepository
+	presetRepo         interfaces.HeaderPresetRepository
+	environmentService interfaces.EnvironmentService
+	globalService      interfaces.GlobalVariableService
+	jobService         interfaces.JobService
+	publisher          events.Publisher
+	httpClient         *http.Client
+	// defaultTimeout bounds requests given no timeout by themselves or their run
+	defaultTimeout time.Duration
+}
+
+// NewRunnerService creates a new runner service resolving requests with
+// globals the way the flatten service does; collection runs and load tests
+// are queued on jobService, requests go out through proxy, bounded by defaultTimeout unless
+// given another, and finished runs are published as events
+func NewRunnerService(
+	requestRepo interfaces.RequestRepository,
+	collectionRepo interfaces.CollectionRepository,
+	folderRepo interfaces.FolderRepository,
+	runRepo interfaces.RunRepository,
+	snapshotRepo interfaces.ResponseSnapshotRepository,
+	loadTestRepo interfaces.LoadTestRepository,
+	presetRepo interfaces.HeaderPresetRepository,
+	environmentService interfaces.EnvironmentService,
+	globalService interfaces.GlobalVariableService,
+	jobService interfaces.JobService,
+	publisher events.Publisher,
+	proxy models.OutboundProxy,
+	defaultTimeout time.Duration,
+) interfaces.RunnerService {
+	// Requests are bounded by their own timeouts rather than the client's
+	httpClient := newFetchClient(proxy)
+	httpClient.Timeout = 0
+
+	return &RunnerService{
+		requestRepo:        requestRepo,
+		collectionRepo:     collectionRepo,
+		folderRepo:         folderRepo,
+		runRepo:            runRepo,
+		snapshotRepo:       snapshotRepo,
+		loadTestRepo:       loadTestRepo,
+		presetRepo:         presetRepo,
+		environmentService: environmentService,
+		globalService:      globalService,
+		jobService:         jobService,
+		publisher:          publisher,
+		httpClient:         httpClient,
+		defaultTimeout:     defaultTimeout,
+	}
+}
+
+// ExecuteRequest resolves a request the way FlattenCollection does, sends it
+// and returns the response with the outcome of the request's assertions. The
+// presets in opts apply after the request's own, beneath its headers. The
+// request is abandoned when ctx is cancelled, e.g. by the client going away.
+func (s *RunnerService) ExecuteRequest(ctx context.Context, id int64, opts models.ExecuteOptions) (*models.ExecutionResult, error) {
+	if err := validateRunTimeout(opts.TimeoutMs); err != nil {
+		return nil, err
+	}
+
+	if err := checkHeaderPresets(ctx, s.presetRepo, "header_presets", opts.HeaderPresets); err != nil {
+		return nil, err
+	}
+
+	request, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionRepo.GetByID(ctx, request.CollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	scopes, err := variableScopes(ctx, s.environmentService, s.globalService, collection, opts.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := newHeaderPresets(s.presetRepo).apply(ctx, request, opts.HeaderPresets)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.hostOverrides(ctx, opts.EnvironmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client, release := s.client(overrides, nil)
+	defer release()
+
+	return s.send(ctx, client, flattenRequest(applied, collection.Auth, variables.New(scopes...)), request.Assertions, s.timeout(request, opts.TimeoutMs))
+}
+
+// RunCollection queues a run of every request of a collection on the job
+// queue and returns it; ExecuteRun carries it out
+func (s *RunnerService) RunCollection(ctx context.Context, collectionID int64, opts models.RunOptions) (*models.Run, error) {
+	if opts.DelayMs < 0 || time.Duration(opts.DelayMs)*time.Millisecond > maxRunDelay {
+		return nil, models.NewValidationError("delay_ms must be between 0 and %d", maxRunDelay.Milliseconds())
+	}
+
+	if err := validateRunTimeout(opts.TimeoutMs); err != nil {
+		return nil, err
+	}
+
+	var errs models.FieldErrors
+	validateRetryPolicy(&errs, "retry", opts.Retry)
+	if opts.MaxRPSPerHost < 0 || opts.MaxRPSPerHost > maxRunRPS {
+		errs.Add("max_rps_per_host", "must be between 0 and %d", maxRunRPS)
+	}
+	validateHostOverrides(&errs, "host_overrides", opts.HostOverrides)
+	if opts.Snapshot != nil {
+		if _, err := parseSnapshotIgnore(opts.Snapshot.Ignore); err != nil {
+			errs.Add("snapshot.ignore", "%v", err)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := checkHeaderPresets(ctx, s.presetRepo, "header_presets", opts.HeaderPresets); err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if _, err := variableScopes(ctx, s.environmentService, s.globalService, collection, opts.EnvironmentID); err != nil {
+		return nil, err
+	}
+
+	run := &models.Run{
+		CollectionID:  collectionID,
+		EnvironmentID: opts.EnvironmentID,
+		Status:        models.RunQueued,
+		DelayMs:       opts.DelayMs,
+		StopOnFailure: opts.StopOnFailure,
+		Results:       []models.RunResult{},
+		StartedAt:     time.Now(),
+	}
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create run: %w", err)
+	}
+
+	payload, err := jobPayload(collectionRun{RunID: run.ID, Options: opts})
+	if err == nil {
+		run.Job, err = s.jobService.Enqueue(ctx, models.JobRunCollection, payload)
+	}
+	if err != nil {
+		run.Error = "failed to queue run: " + err.Error()
+		s.finishRun(ctx, run)
+		return nil, fmt.Errorf("failed to queue run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ExecuteRun sends the requests of a queued run in folder order and stores
+// the run with a result per request. A request passes when it gets a
+// response and all its assertions hold, and in a snapshot run when its
+// response matches its snapshot; with StopOnFailure the requests after
+// the first failure are skipped. A run that cannot be carried out is stored
+// as failed with its error; one already finished is returned as it is.
+func (s *RunnerService) ExecuteRun(ctx context.Context, runID int64, opts models.RunOptions) (*models.Run, error) {
+	run, err := s.runRepo.GetByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A run whose worker was lost is started over
+	if run.Status != models.RunQueued && run.Status != models.RunRunning {
+		return run, nil
+	}
+
+	run.Status = models.RunRunning
+	run.Total, run.Passed, run.Failed, run.Skipped = 0, 0, 0, 0
+	run.Results = []models.RunResult{}
+	run.StartedAt = time.Now()
+	if err := s.runRepo.Update(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to start run: %w", err)
+	}
+
+	if err := s.runRequests(ctx, run, opts); err != nil {
+		run.Error = err.Error()
+	}
+
+	return s.finishRun(ctx, run), nil
+}
+
+// runRequests sends the requests of a collection for run, recording the
+// result of each on it; it fails when the run cannot be set up
+func (s *RunnerService) runRequests(ctx context.Context, run *models.Run, opts models.RunOptions) error {
+	collection, err := s.collectionRepo.GetByID(ctx, run.CollectionID)
+	if err != nil {
+		return fmt.Errorf("collection not found: %w", err)
+	}
+
+	scopes, err := variableScopes(ctx, s.environmentService, s.globalService, collection, opts.EnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	requests, err := runOrder(ctx, s.folderRepo, s.requestRepo, run.CollectionID)
+	if err != nil {
+		return err
+	}
+	run.Total = len(requests)
+
+	presets := newHeaderPresets(s.presetRepo)
+	overrides, err := s.hostOverrides(ctx, opts.EnvironmentID, opts.HostOverrides)
+	if err != nil {
+		return err
+	}
+
+	// Cookies set by responses are sent with the later requests of the run,
+	// as the Postman runner does
+	var jar http.CookieJar
+	if !opts.DisableCookies {
+		if jar, err = cookiejar.New(nil); err != nil {
+			return fmt.Errorf("failed to create cookie jar: %w", err)
+		}
+	}
+
+	client, release := s.client(overrides, jar)
+	defer release()
+
+	var ignore snapshotIgnore
+	if opts.Snapshot != nil {
+		if ignore, err = parseSnapshotIgnore(opts.Snapshot.Ignore); err != nil {
+			return models.NewValidationError("invalid snapshot.ignore: %v", err)
+		}
+	}
+
+	pacer := newHostPacer(opts.MaxRPSPerHost)
+	delay := time.Duration(opts.DelayMs) * time.Millisecond
+	stopped := false
+	for i, request := range requests {
+		result := models.RunResult{
+			RequestID:  request.ID,
+			Name:       request.Name,
+			FolderPath: request.FolderPath,
+			Method:     request.Method,
+		}
+
+		// A request whose presets cannot be applied fails on its own
+		// rather than the whole run
+		var flat *models.FlatRequest
+		applied, err := presets.apply(ctx, request, opts.HeaderPresets)
+		if err == nil {
+			flat = flattenRequest(applied, collection.Auth, variables.New(scopes...))
+			result.Method, result.URL = flat.Method, flat.URL
+		}
+
+		if stopped || ctx.Err() != nil {
+			result.Skipped = true
+			run.Skipped++
+			run.Results = append(run.Results, result)
+			continue
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			run.Failed++
+			stopped = opts.StopOnFailure
+			run.Results = append(run.Results, result)
+			continue
+		}
+
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
+
+		executed, err := s.sendWithRetry(ctx, client, flat, request, opts, pacer, &result)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.StatusCode = executed.StatusCode
+			result.LatencyMs = executed.LatencyMs
+			result.Assertions = executed.Assertions
+			result.Passed = executed.Passed
+
+			if opts.Snapshot != nil {
+				if err := s.checkSnapshot(ctx, run, opts.Snapshot, ignore, executed, &result); err != nil {
+					result.Error = err.Error()
+					result.Passed = false
+				}
+			}
+		}
+
+		if result.Passed {
+			run.Passed++
+		} else {
+			run.Failed++
+			stopped = opts.StopOnFailure
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return nil
+}
+
+// finishRun stores the outcome of a run and publishes its completion
+func (s *RunnerService) finishRun(ctx context.Context, run *models.Run) *models.Run {
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	switch {
+	case ctx.Err() != nil:
+		run.Status = models.RunAborted
+	case run.Error != "" || run.Failed > 0:
+		run.Status = models.RunFailed
+	default:
+		run.Status = models.RunPassed
+	}
+
+	run.Metrics = runMetrics(run)
+
+	// The report is kept, and its completion published, even when the
+	// worker was stopped mid-run
+	ctx = context.WithoutCancel(ctx)
+	if err := s.runRepo.Update(ctx, run); err != nil {
+		log.Printf("Failed to save run %d: %v", run.ID, err)
+	}
+
+	payload := map[string]any{
+		"collection_id": run.CollectionID,
+		"status":        run.Status,
+		"total":         run.Total,
+		"passed":        run.Passed,
+		"failed":        run.Failed,
+		"skipped":       run.Skipped,
+	}
+	if run.Error != "" {
+		payload["error"] = run.Error
+	}
+
+	event := events.Event{
+		Type:       events.RunCompleted,
+		EntityType: "run",
+		EntityID:   run.ID,
+		Payload:    payload,
+		OccurredAt: finished,
+	}
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event: %v", event.Type, err)
+	}
+
+	return run
+}
+
+// GetRun retrieves a run with its per-request results
+func (s *RunnerService) GetRun(ctx context.Context, id int64) (*models.Run, error) {
+	return s.runRepo.GetByID(ctx, id)
+}
+
+// GetSnapshot retrieves the response snapshot of a request
+func (s *RunnerService) GetSnapshot(ctx context.Context, requestID int64) (*models.ResponseSnapshot, error) {
+	return s.snapshotRepo.GetByRequestID(ctx, requestID)
+}
+
+// GetRunMetrics returns the chart data of a run, aggregated when it finished
+func (s *RunnerService) GetRunMetrics(ctx context.Context, id int64) (*models.RunMetrics, error) {
+	run, err := s.runRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Metrics == nil {
+		return nil, models.NewConflictError(fmt.Sprintf("run %d is %s, metrics are available once it finishes", id, run.Status), nil)
+	}
+
+	return run.Metrics, nil
+}
+
+// ListRuns returns the runs of a collection with pagination, newest first
+func (s *RunnerService) ListRuns(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.Run, int, error) {
+	if _, err := s.collectionRepo.GetByID(ctx, collectionID); err != nil {
+		return nil, 0, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	runs, err := s.runRepo.ListByCollectionID(ctx, collectionID, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.runRepo.CountByCollectionID(ctx, collectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// runOrder returns the requests of a collection in folder order: depth
+// first through the folder tree, the requests and subfolders of each folder
+// in position order
+func runOrder(ctx context.Context, folderRepo interfaces.FolderRepository, requestRepo interfaces.RequestRepository, collectionID int64) ([]*models.Request, error) {
+	var all []*models.Request
+	for offset := 0; ; offset += itemBatchSize {
+		requests, err := requestRepo.ListByCollectionID(ctx, collectionID, offset, itemBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		all = append(all, requests...)
+
+		if len(requests) < itemBatchSize {
+			break
+		}
+	}
+
+	tree, err := loadFolderTree(ctx, folderRepo, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	folderRequests := requestsByFolder(tree, all)
+	ordered := make([]*models.Request, 0, len(all))
+	var walk func(folderID int64)
+	walk = func(folderID int64) {
+		tree.eachChild(folderID, folderRequests[folderID], func(request *models.Request) {
+			ordered = append(ordered, request)
+		}, func(folder *models.Folder) {
+			walk(folder.ID)
+		})
+	}
+	walk(0)
+
+	return ordered, nil
+}
+
+// sendWithRetry sends a request of a run, paced by pacer, under its retry
+// policy or that of the run, recording the attempts on result; it returns the
+// outcome of the last attempt
+func (s *RunnerService) sendWithRetry(ctx context.Context, client *http.Client, flat *models.FlatRequest, request *models.Request, opts models.RunOptions, pacer *hostPacer, result *models.RunResult) (*models.ExecutionResult, error) {
+	policy := opts.Retry
+	if request.RunSettings != nil && request.RunSettings.Retry != nil {
+		policy = request.RunSettings.Retry
+	}
+
+	timeout := s.timeout(request, opts.TimeoutMs)
+	backoff := time.Duration(0)
+	for attempt := 1; ; attempt++ {
+		pacer.wait(ctx, flat.URL)
+		start := time.Now()
+		executed, err := s.send(ctx, client, flat, request.Assertions, timeout)
+		result.Attempts = attempt
+		result.SentAt = &start
+		var header http.Header
+		if executed != nil {
+			header = executed.Headers
+		}
+		pacer.sent(flat.URL, header)
+
+		if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !retryableSend(executed, err, policy) {
+			return executed, err
+		}
+
+		retry := models.RunAttempt{LatencyMs: time.Since(start).Milliseconds(), SentAt: start}
+		if err != nil {
+			retry.Error = err.Error()
+		} else {
+			retry.StatusCode, retry.LatencyMs = executed.StatusCode, executed.LatencyMs
+		}
+		result.Retries = append(result.Retries, retry)
+
+		if backoff == 0 {
+			backoff = time.Duration(policy.BackoffMs) * time.Millisecond
+		} else {
+			backoff = min(backoff*2, maxRunDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return executed, err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// retryableSend reports whether a send is worth retrying under policy: it got
+// no response, for a reason other than an invalid request, or a listed status
+func retryableSend(executed *models.ExecutionResult, err error, policy *models.RetryPolicy) bool {
+	if err != nil {
+		return models.ErrorCodeOf(err) == models.ErrCodeUpstream
+	}
+	return slices.Contains(policy.RetryOn, executed.StatusCode)
+}
+
+// runMetrics aggregates the results of a run into its chart data
+func runMetrics(run *models.Run) *models.RunMetrics {
+	metrics := &models.RunMetrics{RunID: run.ID, DurationMs: run.DurationMs, Requests: []models.RequestMetrics{}}
+	for _, result := range run.Results {
+		if result.SentAt == nil {
+			continue
+		}
+
+		request := models.RequestMetrics{
+			RequestID:   result.RequestID,
+			Name:        result.Name,
+			StatusCodes: map[int]int{},
+		}
+		for _, retry := range result.Retries {
+			request.Latency = append(request.Latency, models.LatencyPoint{
+				OffsetMs:   retry.SentAt.Sub(run.StartedAt).Milliseconds(),
+				LatencyMs:  retry.LatencyMs,
+				StatusCode: retry.StatusCode,
+			})
+			request.StatusCodes[retry.StatusCode]++
+		}
+		request.Latency = append(request.Latency, models.LatencyPoint{
+			OffsetMs:   result.SentAt.Sub(run.StartedAt).Milliseconds(),
+			LatencyMs:  result.LatencyMs,
+			StatusCode: result.StatusCode,
+		})
+		request.StatusCodes[result.StatusCode]++
+
+		metrics.Requests = append(metrics.Requests, request)
+	}
+
+	return metrics
+}
+
+// hostOverrides returns the host overrides of an environment, when given,
+// with overrides taking precedence
+func (s *RunnerService) hostOverrides(ctx context.Context, environmentID int64, overrides map[string]string) (map[string]string, error) {
+	merged := map[string]string{}
+	if environmentID != 0 {
+		env, err := s.environmentService.GetEnvironment(ctx, environmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environment: %w", err)
+		}
+		for host, ip := range env.HostOverrides {
+			merged[strings.ToLower(host)] = ip
+		}
+	}
+	for host, ip := range overrides {
+		merged[strings.ToLower(host)] = ip
+	}
+
+	return merged, nil
+}
+
+// client returns the client sending requests that connects to overridden
+// hosts at their addresses and keeps cookies in jar, when set, and a function
+// releasing its connections once done. Hosts reached through the outbound
+// proxy are resolved by the proxy.
+func (s *RunnerService) client(overrides map[string]string, jar http.CookieJar) (*http.Client, func()) {
+	if len(overrides) == 0 && jar == nil {
+		return s.httpClient, func() {}
+	}
+
+	client := *s.httpClient
+	client.Jar = jar
+	if len(overrides) > 0 {
+		transport := s.httpClient.Transport.(*http.Transport).Clone()
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if host, port, err := net.SplitHostPort(addr); err == nil {
+				if ip, ok := overrides[strings.ToLower(host)]; ok {
+					addr = net.JoinHostPort(ip, port)
+				}
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+		client.Transport = transport
+		return &client, transport.CloseIdleConnections
+	}
+
+	return &client, func() {}
+}
+
+// validateRunTimeout checks the timeout given for the requests of a run or execution
+func validateRunTimeout(timeoutMs int) error {
+	if timeoutMs < 0 || time.Duration(timeoutMs)*time.Millisecond > maxRequestTimeout {
+		return models.NewValidationError("timeout_ms must be between 0 and %d", maxRequestTimeout.Milliseconds())
+	}
+	return nil
+}
+
+// timeout returns the timeout of a request: its own, else timeoutMs given
+// for its run or execution, else the default
+func (s *RunnerService) timeout(request *models.Request, timeoutMs int) time.Duration {
+	if request.RunSettings != nil && request.RunSettings.TimeoutMs > 0 {
+		timeoutMs = request.RunSettings.TimeoutMs
+	}
+	if timeoutMs > 0 {
+		return time.Duration(timeoutMs) * time.Millisecond
+	}
+	return s.defaultTimeout
+}
+
+// send dispatches a resolved request through client and records its
+// response, giving up when timeout passes before the response is read
+func (s *RunnerService) send(ctx context.Context, client *http.Client, flat *models.FlatRequest, checks []models.Assertion, timeout time.Duration) (*models.ExecutionResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if flat.Body != "" {
+		body = strings.NewReader(flat.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, flat.Method, flat.URL, body)
+	if err != nil {
+		if len(flat.Unresolved) > 0 {
+			return nil, models.NewValidationError("invalid request URL %q, unresolved variables: %s", flat.URL, strings.Join(flat.Unresolved, ", "))
+		}
+		return nil, models.NewValidationError("invalid request URL %q: %v", flat.URL, err)
+	}
+	for key, value := range flat.Headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, exchangeError("failed to send request", err, timeout)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxExecutedResponseSize+1))
+	latency := time.Since(start)
+	if err != nil {
+		return nil, exchangeError("failed to read response", err, timeout)
+	}
+
+	result := &models.ExecutionResult{
+		RequestID:  flat.RequestID,
+		Method:     flat.Method,
+		URL:        flat.URL,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		Size:       int64(len(data)),
+		LatencyMs:  latency.Milliseconds(),
+		Unresolved: flat.Unresolved,
+	}
+
+	if len(data) > maxExecutedResponseSize {
+		data = data[:maxExecutedResponseSize]
+		result.Truncated = true
+		result.Size = max(resp.ContentLength, result.Size)
+	}
+
+	if utf8.Valid(data) {
+		result.Body = string(data)
+	} else {
+		result.Body = base64.StdEncoding.EncodeToString(data)
+		result.BodyBase64 = true
+	}
+
+	result.Assertions = assertions.Evaluate(checks, &assertions.Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+		Latency:    latency,
+	})
+	result.Passed = assertions.Passed(result.Assertions)
+
+	return result, nil
+}
+
+// exchangeError describes a failure to send a request or read its response,
+// telling a timeout apart from other failures
+func exchangeError(action string, err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &models.Error{Code: models.ErrCodeUpstream, Message: fmt.Sprintf("%s: timed out after %s", action, timeout), Err: err}
+	}
+	return &models.Error{Code: models.ErrCodeUpstream, Message: action + ": " + err.Error(), Err: err}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/models"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSnapshotDrift bounds how many differences are reported for a response
+const maxSnapshotDrift = 20
+
+// maxDriftValue bounds how much of a changed value a difference quotes
+const maxDriftValue = 80
+
+// fieldStep is a step into a JSON document: a key, or an index when index
+// is not negative. In ignore patterns, wild matches any key or index.
+type fieldStep struct {
+	key   string
+	index int
+	wild  bool
+}
+
+// snapshotIgnore holds the volatile fields a snapshot run leaves out of the
+// diff: field names ignored at any depth, and paths from the document root
+type snapshotIgnore struct {
+	names []string
+	paths [][]fieldStep
+}
+
+// parseSnapshotIgnore parses the ignored fields of snapshot options: bare
+// names, or JSON paths of keys, ['quoted keys'] and [indexes], where * and
+// [*] match any key or index
+func parseSnapshotIgnore(fields []string) (snapshotIgnore, error) {
+	var ignore snapshotIgnore
+	for _, raw := range fields {
+		field := strings.TrimSpace(raw)
+		if field == "" {
+			return ignore, fmt.Errorf("ignored fields must not be empty")
+		}
+
+		if !strings.HasPrefix(field, "$") {
+			ignore.names = append(ignore.names, field)
+			continue
+		}
+
+		path, err := parseFieldPath(field)
+		if err != nil {
+			return ignore, err
+		}
+		ignore.paths = append(ignore.paths, path)
+	}
+
+	return ignore, nil
+}
+
+// parseFieldPath parses a JSON path of an ignored field, in the syntax of
+// JSON path assertions with * and [*] as wildcards
+func parseFieldPath(path string) ([]fieldStep, error) {
+	var steps []fieldStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			key := rest[1:]
+			if end != -1 {
+				key = rest[1 : end+1]
+			}
+			if key == "" {
+				return nil, fmt.Errorf("empty key in path %q", path)
+			}
+			rest = rest[1+len(key):]
+			steps = append(steps, fieldStep{key: key, index: -1, wild: key == "*"})
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated bracket in path %q", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if inner == "*" {
+				steps = append(steps, fieldStep{index: -1, wild: true})
+			} else if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, fieldStep{key: inner[1 : len(inner)-1], index: -1})
+			} else {
+				i, err := strconv.Atoi(inner)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+				}
+				steps = append(steps, fieldStep{index: i})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", rest[0], path)
+		}
+	}
+
+	return steps, nil
+}
+
+// ignores reports whether the field at path is left out of the diff
+func (ig snapshotIgnore) ignores(path []fieldStep) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	if last := path[len(path)-1]; last.index < 0 && slices.Contains(ig.names, last.key) {
+		return true
+	}
+
+	for _, pattern := range ig.paths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, step := range pattern {
+			if !step.wild && step != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffSnapshot describes how a response differs from the snapshot of its
+// request: its status, then its body field by field when both bodies are
+// JSON, else as a whole
+func diffSnapshot(snapshot *models.ResponseSnapshot, executed *models.ExecutionResult, ignore snapshotIgnore) []string {
+	d := &snapshotDiff{ignore: ignore}
+	if snapshot.StatusCode != executed.StatusCode {
+		d.add(fmt.Sprintf("status changed from %d to %d", snapshot.StatusCode, executed.StatusCode))
+	}
+
+	var want, got any
+	if snapshot.BodyBase64 || executed.BodyBase64 ||
+		json.Unmarshal([]byte(snapshot.Body), &want) != nil ||
+		json.Unmarshal([]byte(executed.Body), &got) != nil {
+		if snapshot.Body != executed.Body || snapshot.BodyBase64 != executed.BodyBase64 {
+			d.add("body changed")
+		}
+	} else {
+		d.compare(nil, want, got)
+	}
+
+	return d.report()
+}
+
+// snapshotDiff collects the differences between a snapshot and a response
+type snapshotDiff struct {
+	ignore snapshotIgnore
+	drift  []string
+	// more counts the differences past maxSnapshotDrift
+	more int
+}
+
+// compare records the differences between the values at path in the
+// snapshot, want, and in the response, got
+func (d *snapshotDiff) compare(path []fieldStep, want, got any) {
+	if d.ignore.ignores(path) {
+		return
+	}
+
+	switch w := want.(type) {
+	case map[string]any:
+		if g, ok := got.(map[string]any); ok {
+			keys := make([]string, 0, len(w)+len(g))
+			for key := range w {
+				keys = append(keys, key)
+			}
+			for key := range g {
+				if _, ok := w[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				child := append(slices.Clip(path), fieldStep{key: key, index: -1})
+				wv, inWant := w[key]
+				gv, inGot := g[key]
+				switch {
+				case !inGot:
+					d.field(child, "removed")
+				case !inWant:
+					d.field(child, "added")
+				default:
+					d.compare(child, wv, gv)
+				}
+			}
+			return
+		}
+	case []any:
+		if g, ok := got.([]any); ok {
+			for i := range max(len(w), len(g)) {
+				child := append(slices.Clip(path), fieldStep{index: i})
+				switch {
+				case i >= len(g):
+					d.field(child, "removed")
+				case i >= len(w):
+					d.field(child, "added")
+				default:
+					d.compare(child, w[i], g[i])
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		d.field(path, fmt.Sprintf("changed from %s to %s", driftValue(want), driftValue(got)))
+	}
+}
+
+// field records a difference at path unless the field is ignored
+func (d *snapshotDiff) field(path []fieldStep, change string) {
+	if d.ignore.ignores(path) {
+		return
+	}
+	d.add(fieldPath(path) + " " + change)
+}
+
+// add records a difference, counting those past the limit
+func (d *snapshotDiff) add(difference string) {
+	if len(d.drift) >= maxSnapshotDrift {
+		d.more++
+		return
+	}
+	d.drift = append(d.drift, difference)
+}
+
+// report returns the recorded differences, noting how many were left out
+func (d *snapshotDiff) report() []string {
+	if d.more > 0 {
+		return append(d.drift, fmt.Sprintf("and %d more differences", d.more))
+	}
+	return d.drift
+}
+
+// fieldPath renders path as a JSON path
+func fieldPath(path []fieldStep) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, step := range path {
+		switch {
+		case step.index >= 0:
+			fmt.Fprintf(&b, "[%d]", step.index)
+		case step.key != "" && !strings.ContainsAny(step.key, ".[]'\" "):
+			b.WriteString("." + step.key)
+		default:
+			b.WriteString("['" + step.key + "']")
+		}
+	}
+	return b.String()
+}
+
+// driftValue renders a changed value as JSON, shortened to maxDriftValue bytes
+func driftValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) > maxDriftValue {
+		return string(data[:maxDriftValue]) + "..."
+	}
+	return string(data)
+}
+
+// checkSnapshot diffs the response a request of a snapshot run got against
+// the snapshot of the request, failing result when it drifted. A request
+// without a snapshot, or any with opts.Update, gets its response recorded
+// instead, provided it passed: a failing response makes no baseline.
+func (s *RunnerService) checkSnapshot(ctx context.Context, run *models.Run, opts *models.SnapshotOptions, ignore snapshotIgnore, executed *models.ExecutionResult, result *models.RunResult) error {
+	if !opts.Update {
+		snapshot, err := s.snapshotRepo.GetByRequestID(ctx, result.RequestID)
+		if err == nil {
+			result.Drift = diffSnapshot(snapshot, executed, ignore)
+			if len(result.Drift) > 0 {
+				result.Snapshot = models.SnapshotDrifted
+				result.Passed = false
+			} else {
+				result.Snapshot = models.SnapshotMatched
+			}
+			return nil
+		}
+		if models.ErrorCodeOf(err) != models.ErrCodeNotFound {
+			return err
+		}
+	}
+
+	if !result.Passed {
+		return nil
+	}
+
+	snapshot := &models.ResponseSnapshot{
+		RequestID:  result.RequestID,
+		RunID:      run.ID,
+		StatusCode: executed.StatusCode,
+		Body:       executed.Body,
+		BodyBase64: executed.BodyBase64,
+	}
+	if err := s.snapshotRepo.Save(ctx, snapshot); err != nil {
+		return err
+	}
+	result.Snapshot = models.SnapshotRecorded
+
+	return nil
+}
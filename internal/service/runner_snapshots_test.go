@@ -0,0 +1,120 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot models.ResponseSnapshot
+		response models.ExecutionResult
+		ignore   []string
+		want     []string
+	}{
+		{
+			name:     "identical JSON with keys reordered",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{"id":1,"name":"Rex"}`},
+			response: models.ExecutionResult{StatusCode: 200, Body: `{"name": "Rex", "id": 1}`},
+		},
+		{
+			name:     "fields changed, added and removed",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{"name":"Rex","tags":["a","b"],"owner":{"id":1}}`},
+			response: models.ExecutionResult{StatusCode: 200, Body: `{"name":"Max","tags":["a"],"owner":{"id":1,"email":"o@example.com"}}`},
+			want: []string{
+				`$.name changed from "Rex" to "Max"`,
+				`$.owner.email added`,
+				`$.tags[1] removed`,
+			},
+		},
+		{
+			name:     "status changed",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{}`},
+			response: models.ExecutionResult{StatusCode: 404, Body: `{}`},
+			want:     []string{"status changed from 200 to 404"},
+		},
+		{
+			name:     "type changed",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{"items":[]}`},
+			response: models.ExecutionResult{StatusCode: 200, Body: `{"items":{}}`},
+			want:     []string{`$.items changed from [] to {}`},
+		},
+		{
+			name:     "volatile fields ignored by name at any depth",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{"id":1,"updated_at":"2026-01-01","items":[{"id":7,"updated_at":"2026-01-01"}]}`},
+			response: models.ExecutionResult{StatusCode: 200, Body: `{"id":2,"items":[{"id":8,"updated_at":"2026-02-01"}]}`},
+			ignore:   []string{"id", "updated_at"},
+		},
+		{
+			name:     "volatile fields ignored by path",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: `{"id":1,"items":[{"id":7},{"id":8}],"meta":{"request-id":"a"}}`},
+			response: models.ExecutionResult{StatusCode: 200, Body: `{"id":2,"items":[{"id":9},{"id":10}],"meta":{"request-id":"b"}}`},
+			ignore:   []string{"$.items[*].id", "$.meta['request-id']"},
+			want:     []string{`$.id changed from 1 to 2`},
+		},
+		{
+			name:     "text bodies compared whole",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: "ok"},
+			response: models.ExecutionResult{StatusCode: 200, Body: "OK"},
+			want:     []string{"body changed"},
+		},
+		{
+			name:     "binary bodies compared whole",
+			snapshot: models.ResponseSnapshot{StatusCode: 200, Body: "iVBORw==", BodyBase64: true},
+			response: models.ExecutionResult{StatusCode: 200, Body: "iVBORw==", BodyBase64: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignore, err := parseSnapshotIgnore(tt.ignore)
+			if err != nil {
+				t.Fatalf("parseSnapshotIgnore(%v) = %v", tt.ignore, err)
+			}
+
+			if got := diffSnapshot(&tt.snapshot, &tt.response, ignore); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffSnapshot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSnapshotLimit(t *testing.T) {
+	snapshot := &models.ResponseSnapshot{StatusCode: 200, Body: `[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25]`}
+	response := &models.ExecutionResult{StatusCode: 200, Body: `[]`}
+
+	drift := diffSnapshot(snapshot, response, snapshotIgnore{})
+	if len(drift) != maxSnapshotDrift+1 {
+		t.Fatalf("diffSnapshot() reported %d differences, want %d", len(drift), maxSnapshotDrift+1)
+	}
+	if last := drift[len(drift)-1]; last != "and 5 more differences" {
+		t.Errorf("last difference = %q, want the count of those left out", last)
+	}
+}
+
+func TestParseSnapshotIgnore(t *testing.T) {
+	tests := []struct {
+		field   string
+		wantErr bool
+	}{
+		{"updated_at", false},
+		{"$.items[*].id", false},
+		{"$.*.id", false},
+		{"$['x-request-id']", false},
+		{"$.data[0]", false},
+		{"", true},
+		{"$.", true},
+		{"$.items[", true},
+		{"$.items[-1]", true},
+		{"$items", true},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseSnapshotIgnore([]string{tt.field}); (err != nil) != tt.wantErr {
+			t.Errorf("parseSnapshotIgnore(%q) = %v, want error %v", tt.field, err, tt.wantErr)
+		}
+	}
+}
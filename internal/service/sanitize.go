@@ -0,0 +1,254 @@
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"postman-api/internal/models"
+)
+
+// secretAuthAttributes maps Postman auth types to the attributes holding
+// credentials and the collection variable used as their placeholder
+var secretAuthAttributes = map[string]map[string]string{
+	"bearer": {"token": "bearerToken"},
+	"basic":  {"password": "basicPassword"},
+	"digest": {"password": "digestPassword"},
+	"ntlm":   {"password": "ntlmPassword"},
+	"apikey": {"value": "apiKeyValue"},
+	"oauth1": {"consumerSecret": "oauth1ConsumerSecret", "tokenSecret": "oauth1TokenSecret"},
+	"oauth2": {"accessToken": "oauth2AccessToken", "clientSecret": "oauth2ClientSecret", "password": "oauth2Password"},
+	"awsv4":  {"secretKey": "awsSecretKey", "sessionToken": "awsSessionToken"},
+}
+
+var (
+	// secretNamePattern matches whole segments of a normalised name, so
+	// "Authorization" and "api_key" match while "author" and "oauth_redirect_uri" do not
+	secretNamePattern  = regexp.MustCompile(`(^|_)(secrets?|passw(or)?d|pwd|tokens?|api_?key|private_?key|credentials?|auth|authorization)(_|$)`)
+	placeholderPattern = regexp.MustCompile(`^\{\{[^{}]+\}\}$`)
+	nonIdentPattern    = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	camelLowerPattern  = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	camelUpperPattern  = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+)
+
+// secretStripper removes credentials from a Postman collection, remembering
+// which placeholder variables need to be declared on the collection
+type secretStripper struct {
+	placeholders map[string]bool
+}
+
+// stripSecrets replaces credentials in auth blocks, headers, variables and
+// the requests of saved responses with {{placeholder}} references, and drops
+// the cookies and credential headers of saved responses, so they are never persisted
+func stripSecrets(collection *models.PostmanCollection) {
+	s := &secretStripper{placeholders: make(map[string]bool)}
+
+	collection.Auth = s.stripAuth(collection.Auth)
+	collection.Variable = s.stripVariables(collection.Variable)
+	s.stripItems(collection.Item)
+
+	declared := make(map[string]bool)
+	for _, v := range collection.Variable {
+		declared[v.Key] = true
+	}
+
+	names := make([]string, 0, len(s.placeholders))
+	for name := range s.placeholders {
+		if !declared[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		collection.Variable = append(collection.Variable, models.KeyValuePair{
+			Key:   name,
+			Value: "",
+			Type:  "secret",
+		})
+	}
+}
+
+// stripItems walks folders and requests recursively
+func (s *secretStripper) stripItems(items []models.PostmanItem) {
+	for i := range items {
+		item := &items[i]
+		item.Auth = s.stripAuth(item.Auth)
+		item.Variable = s.stripVariables(item.Variable)
+
+		if item.Request != nil {
+			item.Request.Auth = s.stripAuth(item.Request.Auth)
+			item.Request.Header = s.stripHeaders(item.Request.Header)
+		}
+
+		for j := range item.Response {
+			s.stripResponse(&item.Response[j])
+		}
+
+		s.stripItems(item.Item)
+	}
+}
+
+// stripAuth replaces credential attributes of a Postman auth block
+func (s *secretStripper) stripAuth(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+
+	var auth map[string]any
+	if err := json.Unmarshal(raw, &auth); err != nil {
+		return raw
+	}
+
+	authType, _ := auth["type"].(string)
+	attributes, ok := secretAuthAttributes[authType]
+	if !ok {
+		return raw
+	}
+
+	switch params := auth[authType].(type) {
+	case []any:
+		// v2.1 format: [{"key": "token", "value": "..."}]
+		for _, p := range params {
+			param, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			key, _ := param["key"].(string)
+			if name, ok := attributes[key]; ok {
+				param["value"] = s.placeholderFor(name, param["value"])
+			}
+		}
+	case map[string]any:
+		// v2.0 format: {"token": "..."}
+		for key, name := range attributes {
+			if value, ok := params[key]; ok {
+				params[key] = s.placeholderFor(name, value)
+			}
+		}
+	}
+
+	sanitized, err := json.Marshal(auth)
+	if err != nil {
+		return raw
+	}
+
+	return sanitized
+}
+
+// stripHeaders replaces the values of credential-bearing headers
+func (s *secretStripper) stripHeaders(headers []models.KeyValuePair) []models.KeyValuePair {
+	for i, h := range headers {
+		if !isSecretName(h.Key) {
+			continue
+		}
+		headers[i].Value = s.placeholderFor(headerPlaceholder(h.Key), h.Value).(string)
+	}
+
+	return headers
+}
+
+// stripResponse removes credentials from a saved response: the request it
+// was saved from is stripped like any other, while session cookies and
+// credential headers the server sent back are dropped
+func (s *secretStripper) stripResponse(resp *models.PostmanResponse) {
+	resp.OriginalReq = s.stripOriginalRequest(resp.OriginalReq)
+	resp.Cookie = nil
+
+	kept := resp.Header[:0]
+	for _, h := range resp.Header {
+		if strings.EqualFold(h.Key, "Set-Cookie") || isSecretName(h.Key) {
+			continue
+		}
+		kept = append(kept, h)
+	}
+	resp.Header = kept
+}
+
+// stripOriginalRequest strips the auth block and headers of the request a
+// response was saved from; requests given as a plain URL are left as they are
+func (s *secretStripper) stripOriginalRequest(raw json.RawMessage) json.RawMessage {
+	var request map[string]any
+	if len(raw) == 0 || json.Unmarshal(raw, &request) != nil {
+		return raw
+	}
+
+	if auth, ok := request["auth"]; ok {
+		if encoded, err := json.Marshal(auth); err == nil {
+			var stripped any
+			if json.Unmarshal(s.stripAuth(encoded), &stripped) == nil {
+				request["auth"] = stripped
+			}
+		}
+	}
+
+	if header, ok := request["header"].([]any); ok {
+		encoded, _ := json.Marshal(header)
+		var headers []models.KeyValuePair
+		if json.Unmarshal(encoded, &headers) == nil {
+			request["header"] = s.stripHeaders(headers)
+		}
+	}
+
+	sanitized, err := json.Marshal(request)
+	if err != nil {
+		return raw
+	}
+
+	return sanitized
+}
+
+// stripVariables blanks the values of variables whose names look like secrets
+func (s *secretStripper) stripVariables(variables []models.KeyValuePair) []models.KeyValuePair {
+	for i, v := range variables {
+		if isSecretName(v.Key) && !isPlaceholder(v.Value) {
+			variables[i].Value = ""
+		}
+	}
+
+	return variables
+}
+
+// placeholderFor returns a {{name}} reference for value, leaving values that
+// already reference a variable untouched
+func (s *secretStripper) placeholderFor(name string, value any) any {
+	if str, ok := value.(string); ok && (str == "" || isPlaceholder(str)) {
+		return str
+	}
+
+	s.placeholders[name] = true
+	return "{{" + name + "}}"
+}
+
+// headerPlaceholder derives a variable name from a header name,
+// e.g. "X-API-Key" becomes "headerXApiKey"
+func headerPlaceholder(header string) string {
+	var b strings.Builder
+	b.WriteString("header")
+	for _, part := range nonIdentPattern.Split(header, -1) {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(strings.ToLower(part[1:]))
+	}
+
+	return b.String()
+}
+
+// isSecretName reports whether a header or variable name looks like it holds
+// a credential. Names are split on separators and camelCase boundaries first,
+// e.g. "X-API-Key" becomes "x_api_key" and "clientSecret" becomes "client_secret"
+func isSecretName(name string) bool {
+	normalised := camelUpperPattern.ReplaceAllString(name, "${1}_${2}")
+	normalised = camelLowerPattern.ReplaceAllString(normalised, "${1}_${2}")
+	normalised = strings.Trim(nonIdentPattern.ReplaceAllString(normalised, "_"), "_")
+
+	return secretNamePattern.MatchString(strings.ToLower(normalised))
+}
+
+// isPlaceholder reports whether value is a single {{variable}} reference
+func isPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(strings.TrimSpace(value))
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+func TestIsSecretName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Authorization", true},
+		{"Proxy-Authorization", true},
+		{"X-API-Key", true},
+		{"api_key", true},
+		{"apiKey", true},
+		{"clientSecret", true},
+		{"client_secret", true},
+		{"password", true},
+		{"db.passwd", true},
+		{"access_token", true},
+		{"refreshTokens", true},
+		{"PRIVATE_KEY", true},
+		{"credentials", true},
+		{"basic-auth", true},
+		{"author", false},
+		{"authority", false},
+		{"oauth_redirect_uri", false},
+		{"Content-Type", false},
+		{"baseUrl", false},
+		{"tokenizer", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSecretName(tt.name); got != tt.want {
+				t.Errorf("isSecretName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderPlaceholder(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Authorization", "headerAuthorization"},
+		{"X-API-Key", "headerXApiKey"},
+		{"x_auth_token", "headerXAuthToken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := headerPlaceholder(tt.header); got != tt.want {
+				t.Errorf("headerPlaceholder(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		auth         string
+		want         string
+		placeholders []string
+	}{
+		{
+			name:         "bearer v2.1",
+			auth:         `{"type":"bearer","bearer":[{"key":"token","value":"s3cr3t","type":"string"}]}`,
+			want:         `{"type":"bearer","bearer":[{"key":"token","value":"{{bearerToken}}","type":"string"}]}`,
+			placeholders: []string{"bearerToken"},
+		},
+		{
+			name:         "basic v2.0 keeps username",
+			auth:         `{"type":"basic","basic":{"username":"sam","password":"hunter2"}}`,
+			want:         `{"type":"basic","basic":{"username":"sam","password":"{{basicPassword}}"}}`,
+			placeholders: []string{"basicPassword"},
+		},
+		{
+			name:         "oauth2 strips every credential",
+			auth:         `{"type":"oauth2","oauth2":[{"key":"accessToken","value":"a"},{"key":"clientSecret","value":"b"},{"key":"clientId","value":"c"}]}`,
+			want:         `{"type":"oauth2","oauth2":[{"key":"accessToken","value":"{{oauth2AccessToken}}"},{"key":"clientSecret","value":"{{oauth2ClientSecret}}"},{"key":"clientId","value":"c"}]}`,
+			placeholders: []string{"oauth2AccessToken", "oauth2ClientSecret"},
+		},
+		{
+			name: "existing placeholder is kept",
+			auth: `{"type":"bearer","bearer":[{"key":"token","value":"{{myToken}}"}]}`,
+			want: `{"type":"bearer","bearer":[{"key":"token","value":"{{myToken}}"}]}`,
+		},
+		{
+			name: "empty credential is kept",
+			auth: `{"type":"apikey","apikey":[{"key":"value","value":""}]}`,
+			want: `{"type":"apikey","apikey":[{"key":"value","value":""}]}`,
+		},
+		{
+			name: "noauth is left alone",
+			auth: `{"type":"noauth"}`,
+			want: `{"type":"noauth"}`,
+		},
+		{
+			name: "invalid JSON is left alone",
+			auth: `{"type":`,
+			want: `{"type":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &secretStripper{placeholders: map[string]bool{}}
+			got := s.stripAuth(json.RawMessage(tt.auth))
+
+			assertJSONEqual(t, got, tt.want)
+			assertPlaceholders(t, s.placeholders, tt.placeholders)
+		})
+	}
+}
+
+func TestStripSecrets(t *testing.T) {
+	collection := &models.PostmanCollection{
+		Info: models.CollectionInfo{Name: "Shop"},
+		Auth: json.RawMessage(`{"type":"bearer","bearer":[{"key":"token","value":"collection-token"}]}`),
+		Variable: []models.KeyValuePair{
+			{Key: "baseUrl", Value: "https://shop.example.com"},
+			{Key: "apiKey", Value: "k-123"},
+			{Key: "sessionToken", Value: "{{token}}"},
+			{Key: "basicPassword", Value: "declared"},
+		},
+		Item: []models.PostmanItem{{
+			Name: "Orders",
+			Item: []models.PostmanItem{{
+				Name: "List orders",
+				Request: &models.PostmanRequest{
+					Method: "GET",
+					URL:    "{{baseUrl}}/orders",
+					Auth:   json.RawMessage(`{"type":"basic","basic":[{"key":"password","value":"pw"}]}`),
+					Header: []models.KeyValuePair{
+						{Key: "Authorization", Value: "Bearer abc"},
+						{Key: "Accept", Value: "application/json"},
+						{Key: "X-Author", Value: "sam"},
+					},
+				},
+				Response: []models.PostmanResponse{{
+					Name:        "OK",
+					OriginalReq: json.RawMessage(`{"method":"GET","url":"{{baseUrl}}/orders","header":[{"key":"Authorization","value":"Bearer abc"}],"auth":{"type":"bearer","bearer":[{"key":"token","value":"t"}]}}`),
+					Header: []models.KeyValuePair{
+						{Key: "Content-Type", Value: "application/json"},
+						{Key: "Set-Cookie", Value: "session=1"},
+						{Key: "X-Auth-Token", Value: "issued"},
+					},
+					Cookie: []json.RawMessage{json.RawMessage(`{"name":"session","value":"1"}`)},
+				}},
+			}},
+		}},
+	}
+
+	stripSecrets(collection)
+
+	assertJSONEqual(t, collection.Auth, `{"type":"bearer","bearer":[{"key":"token","value":"{{bearerToken}}"}]}`)
+
+	wantVariables := []models.KeyValuePair{
+		{Key: "baseUrl", Value: "https://shop.example.com"},
+		{Key: "apiKey", Value: ""},
+		{Key: "sessionToken", Value: "{{token}}"},
+		{Key: "basicPassword", Value: ""},
+		{Key: "bearerToken", Value: "", Type: "secret"},
+		{Key: "headerAuthorization", Value: "", Type: "secret"},
+	}
+	if !reflect.DeepEqual(collection.Variable, wantVariables) {
+		t.Errorf("variables = %+v, want %+v", collection.Variable, wantVariables)
+	}
+
+	request := collection.Item[0].Item[0].Request
+	assertJSONEqual(t, request.Auth, `{"type":"basic","basic":[{"key":"password","value":"{{basicPassword}}"}]}`)
+
+	wantHeaders := []models.KeyValuePair{
+		{Key: "Authorization", Value: "{{headerAuthorization}}"},
+		{Key: "Accept", Value: "application/json"},
+		{Key: "X-Author", Value: "sam"},
+	}
+	if !reflect.DeepEqual(request.Header, wantHeaders) {
+		t.Errorf("request headers = %+v, want %+v", request.Header, wantHeaders)
+	}
+
+	response := collection.Item[0].Item[0].Response[0]
+	assertJSONEqual(t, response.OriginalReq, `{"method":"GET","url":"{{baseUrl}}/orders","header":[{"key":"Authorization","value":"{{headerAuthorization}}"}],"auth":{"type":"bearer","bearer":[{"key":"token","value":"{{bearerToken}}"}]}}`)
+
+	wantResponseHeaders := []models.KeyValuePair{{Key: "Content-Type", Value: "application/json"}}
+	if !reflect.DeepEqual(response.Header, wantResponseHeaders) {
+		t.Errorf("response headers = %+v, want %+v", response.Header, wantResponseHeaders)
+	}
+	if response.Cookie != nil {
+		t.Errorf("response cookies = %s, want none", response.Cookie)
+	}
+}
+
+func assertJSONEqual(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		if string(got) != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		return
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON %s: %v", want, err)
+	}
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func assertPlaceholders(t *testing.T, got map[string]bool, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("placeholders = %v, want %v", got, want)
+		return
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("placeholders = %v, want %v", got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+)
+
+const scanBatchSize = 500
+
+// scanRule describes a pattern that flags a likely secret or piece of PII
+type scanRule struct {
+	name     string
+	category string
+	pattern  *regexp.Regexp
+	verify   func(match string) bool
+}
+
+var scanRules = []scanRule{
+	{name: "jwt", category: models.FindingCategorySecret, pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]+`)},
+	{name: "aws_access_key", category: models.FindingCategorySecret, pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "private_key", category: models.FindingCategorySecret, pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{name: "bearer_token", category: models.FindingCategorySecret, pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`)},
+	{name: "api_key", category: models.FindingCategorySecret, pattern: regexp.MustCompile(`(?i)(?:api[_-]?key|secret|access[_-]?token)["']?\s*[:=]\s*["']?[A-Za-z0-9_\-]{16,}`)},
+	{name: "email", category: models.FindingCategoryPII, pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{name: "us_ssn", category: models.FindingCategoryPII, pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{name: "credit_card", category: models.FindingCategoryPII, pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), verify: luhnValid},
+}
+
+// ScannerService inspects stored requests for secrets and PII
+type ScannerService struct {
+	collectionRepo interfaces.CollectionRepository
+	requestRepo    interfaces.RequestRepository
+}
+
+// NewScannerService creates a new scanner service
+func NewScannerService(
+	collectionRepo interfaces.CollectionRepository,
+	requestRepo interfaces.RequestRepository,
+) interfaces.ScannerService {
+	return &ScannerService{
+		collectionRepo: collectionRepo,
+		requestRepo:    requestRepo,
+	}
+}
+
+// ScanCollection scans a collection's variables, auth and every request's
+// URL, headers, body and saved responses
+func (s *ScannerService) ScanCollection(ctx context.Context, collectionID int64) (*models.ScanReport, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	report := &models.ScanReport{
+		CollectionID: collection.ID,
+		Findings:     []models.ScanFinding{},
+		Summary:      make(map[string]int),
+	}
+
+	record := findingRecorder(report, nil)
+	scanValue(collection.Variables, "variables", record)
+	scanValue(collection.Auth, "auth", record)
+
+	for offset := 0; ; offset += scanBatchSize {
+		requests, err := s.requestRepo.ListByCollectionID(ctx, collectionID, offset, scanBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list requests: %w", err)
+		}
+
+		for _, req := range requests {
+			report.ScannedRequests++
+			scanRequest(req, findingRecorder(report, req))
+		}
+
+		if len(requests) < scanBatchSize {
+			break
+		}
+	}
+
+	for _, f := range report.Findings {
+		report.Summary[f.Rule]++
+	}
+
+	return report, nil
+}
+
+// findingRecorder returns a callback appending findings to report,
+// attributed to req when it is not nil
+func findingRecorder(report *models.ScanReport, req *models.Request) func(location, rule, category, match string) {
+	return func(location, rule, category, match string) {
+		finding := models.ScanFinding{
+			Location: location,
+			Rule:     rule,
+			Category: category,
+			Match:    maskMatch(match),
+		}
+		if req != nil {
+			finding.RequestID = req.ID
+			finding.RequestName = req.Name
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+}
+
+// scanRequest scans a request's URL, headers, params, body, auth and saved responses
+func scanRequest(req *models.Request, record func(location, rule, category, match string)) {
+	headers := make(map[string]any, len(req.Headers))
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+
+	scanValue(req.URL, "url", record)
+	scanValue(headers, "headers", record)
+	scanValue(req.Params, "params", record)
+	scanValue(req.Body, "body", record)
+	scanValue(req.Auth, "auth", record)
+
+	var responses any
+	if data, err := json.Marshal(req.Responses); err == nil {
+		json.Unmarshal(data, &responses)
+	}
+	scanValue(responses, "responses", record)
+}
+
+// scanValue walks a decoded JSON value and reports rule matches in every string leaf
+func scanValue(value any, location string, record func(location, rule, category, match string)) {
+	switch v := value.(type) {
+	case models.JSONMap:
+		scanValue(map[string]any(v), location, record)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			scanValue(v[k], location+"."+k, record)
+		}
+	case []any:
+		for i, item := range v {
+			scanValue(item, location+"["+strconv.Itoa(i)+"]", record)
+		}
+	case string:
+		for _, rule := range scanRules {
+			for _, match := range rule.pattern.FindAllString(v, -1) {
+				if rule.verify != nil && !rule.verify(match) {
+					continue
+				}
+				record(location, rule.name, rule.category, match)
+			}
+		}
+	}
+}
+
+// maskMatch keeps only a short prefix of a match so reports don't leak the secret itself
+func maskMatch(match string) string {
+	if len(match) <= 4 {
+		return strings.Repeat("*", len(match))
+	}
+
+	return match[:4] + strings.Repeat("*", min(len(match)-4, 12))
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double, digits := 0, false, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"regexp"
+	"strings"
+)
+
+// maxIngestFindings caps the number of findings accepted in one batch
+const maxIngestFindings = 5000
+
+// SecurityService exports collections as security scanner targets and
+// ingests the findings scanners report back
+type SecurityService struct {
+	collectionRepo interfaces.CollectionRepository
+	findingRepo    interfaces.SecurityFindingRepository
+	flattenService interfaces.FlattenService
+}
+
+// NewSecurityService creates a new security service
+func NewSecurityService(
+	collectionRepo interfaces.CollectionRepository,
+	findingRepo interfaces.SecurityFindingRepository,
+	flattenService interfaces.FlattenService,
+) interfaces.SecurityService {
+	return &SecurityService{
+		collectionRepo: collectionRepo,
+		findingRepo:    findingRepo,
+		flattenService: flattenService,
+	}
+}
+
+// ExportTargets renders the flattened requests of a collection as a ZAP
+// automation plan or a Burp REST API scan definition
+func (s *SecurityService) ExportTargets(ctx context.Context, collectionID, environmentID int64, format string) ([]byte, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("collection not found: %w", err)
+	}
+
+	requests, err := s.flattenService.FlattenCollection(ctx, collectionID, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target any
+	switch format {
+	case models.SecurityTargetZAP:
+		target = zapPlan(collection.Name, requests)
+	case models.SecurityTargetBurp:
+		target = burpScan(requests)
+	default:
+		return nil, models.NewValidationError("unsupported target format %q, use %s or %s", format, models.SecurityTargetZAP, models.SecurityTargetBurp)
+	}
+
+	return json.MarshalIndent(target, "", "  ")
+}
+
+// IngestFindings stores the findings a scanner reported for a collection,
+// linking each to the request whose method and URL it matches
+func (s *SecurityService) IngestFindings(ctx context.Context, collectionID int64, payload *models.SecurityFindingsPayload) (*models.SecurityIngestResult, error) {
+	findings := payload.Findings
+	if len(payload.Report) > 0 {
+		parsed, err := parseZAPReport(payload.Report)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, parsed...)
+	}
+
+	if len(findings) == 0 {
+		return nil, models.NewValidationError("findings or report must contain at least one finding")
+	}
+
+	if len(findings) > maxIngestFindings {
+		return nil, models.NewValidationError("at most %d findings can be ingested at once", maxIngestFindings)
+	}
+
+	var errs models.FieldErrors
+	for i, finding := range findings {
+		if finding.URL == "" {
+			errs.Add(fmt.Sprintf("findings[%d].url", i), "is required")
+		}
+		if finding.Rule == "" {
+			errs.Add(fmt.Sprintf("findings[%d].rule", i), "is required")
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.flattenService.FlattenCollection(ctx, collectionID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SecurityIngestResult{}
+	batch := make([]*models.SecurityFinding, len(findings))
+	for i := range findings {
+		finding := findings[i]
+		finding.ID = 0
+		finding.CollectionID = collectionID
+		finding.Tool = payload.Tool
+		finding.Method = strings.ToUpper(finding.Method)
+		finding.RequestID = matchFindingRequest(requests, finding.Method, finding.URL)
+		if finding.RequestID != 0 {
+			result.Linked++
+		}
+		batch[i] = &finding
+	}
+
+	if err := s.findingRepo.CreateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	result.Ingested = len(batch)
+
+	return result, nil
+}
+
+// ListFindings returns the findings of a collection with pagination
+func (s *SecurityService) ListFindings(ctx context.Context, collectionID int64, page, pageSize int) ([]*models.SecurityFinding, int, error) {
+	if _, err := s.collectionRepo.GetByID(ctx, collectionID); err != nil {
+		return nil, 0, fmt.Errorf("collection not found: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	findings, err := s.findingRepo.ListByCollectionID(ctx, collectionID, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.findingRepo.CountByCollectionID(ctx, collectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return findings, total, nil
+}
+
+// zapPlan builds a ZAP automation framework plan, in its JSON form, that
+// sends every request and then actively scans the collection's origins.
+// Authorization headers are replayed on scanner traffic through replacer rules.
+func zapPlan(name string, requests []*models.FlatRequest) map[string]any {
+	var origins []string
+	seen := map[string]bool{}
+	var zapRequests, rules []map[string]any
+
+	for _, req := range requests {
+		origin := requestOrigin(req.URL)
+		if origin != "" && !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+
+			for key, value := range req.Headers {
+				if strings.EqualFold(key, "Authorization") {
+					rules = append(rules, map[string]any{
+						"description":       "Authorization for " + origin,
+						"url":               regexp.QuoteMeta(origin) + ".*",
+						"matchType":         "req_header",
+						"matchString":       key,
+						"replacementString": value,
+					})
+				}
+			}
+		}
+
+		headers := make([]string, 0, len(req.Headers))
+		for key, value := range req.Headers {
+			headers = append(headers, key+": "+value)
+		}
+		zapRequest := map[string]any{
+			"name":    req.Name,
+			"url":     req.URL,
+			"method":  req.Method,
+			"headers": headers,
+		}
+		if req.Body != "" {
+			zapRequest["data"] = req.Body
+		}
+		zapRequests = append(zapRequests, zapRequest)
+	}
+
+	includePaths := make([]string, len(origins))
+	for i, origin := range origins {
+		includePaths[i] = regexp.QuoteMeta(origin) + ".*"
+	}
+
+	var jobs []map[string]any
+	if len(rules) > 0 {
+		jobs = append(jobs, map[string]any{"type": "replacer", "rules": rules})
+	}
+	jobs = append(jobs,
+		map[string]any{"type": "requestor", "requests": zapRequests},
+		map[string]any{"type": "passiveScan-wait"},
+		map[string]any{"type": "activeScan", "parameters": map[string]any{"context": name}},
+	)
+
+	return map[string]any{
+		"env": map[string]any{
+			"contexts": []map[string]any{{
+				"name":         name,
+				"urls":         origins,
+				"includePaths": includePaths,
+			}},
+			"parameters": map[string]any{"failOnError": false},
+		},
+		"jobs": jobs,
+	}
+}
+
+// burpScan builds the body of a Burp Suite REST API scan request covering
+// every request URL, scoped to the collection's origins
+func burpScan(requests []*models.FlatRequest) map[string]any {
+	urls := []string{}
+	include := []map[string]any{}
+	seenURL, seenOrigin := map[string]bool{}, map[string]bool{}
+
+	for _, req := range requests {
+		if req.URL != "" && !seenURL[req.URL] {
+			seenURL[req.URL] = true
+			urls = append(urls, req.URL)
+		}
+
+		if origin := requestOrigin(req.URL); origin != "" && !seenOrigin[origin] {
+			seenOrigin[origin] = true
+			include = append(include, map[string]any{"rule": origin + "/", "type": "SimpleScopeDef"})
+		}
+	}
+
+	return map[string]any{
+		"urls": urls,
+		"scope": map[string]any{
+			"type":    "SimpleScope",
+			"include": include,
+		},
+	}
+}
+
+// requestOrigin returns the scheme and host of an absolute URL
+func requestOrigin(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// matchFindingRequest returns the ID of the request a finding was reported
+// on, comparing host and path and treating variable segments as wildcards;
+// method is compared when both sides have one
+func matchFindingRequest(requests []*models.FlatRequest, method, rawURL string) int64 {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+	targetPath := strings.Split(strings.Trim(target.Path, "/"), "/")
+
+	for _, req := range requests {
+		if method != "" && req.Method != method {
+			continue
+		}
+
+		candidate, err := url.Parse(req.URL)
+		if err != nil || !strings.EqualFold(candidate.Host, target.Host) {
+			continue
+		}
+
+		_, shape := normalizeInventoryPath(candidate.Path)
+		segments := strings.Split(strings.Trim(shape, "/"), "/")
+		if len(segments) != len(targetPath) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range segments {
+			if segment != "{}" && segment != targetPath[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return req.RequestID
+		}
+	}
+
+	return 0
+}
+
+// zapReport is the subset of ZAP's traditional JSON report read on ingest
+type zapReport struct {
+	Site []struct {
+		Alerts []struct {
+			PluginID  string `json:"pluginid"`
+			Name      string `json:"name"`
+			Alert     string `json:"alert"`
+			RiskDesc  string `json:"riskdesc"`
+			Desc      string `json:"desc"`
+			Instances []struct {
+				URI      string `json:"uri"`
+				Method   string `json:"method"`
+				Evidence string `json:"evidence"`
+			} `json:"instances"`
+		} `json:"alerts"`
+	} `json:"site"`
+}
+
+// parseZAPReport converts the alert instances of a ZAP JSON report to findings
+func parseZAPReport(data []byte) ([]models.SecurityFinding, error) {
+	var report zapReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, models.NewValidationError("invalid ZAP report: %v", err)
+	}
+
+	var findings []models.SecurityFinding
+	for _, site := range report.Site {
+		for _, alert := range site.Alerts {
+			rule := alert.Name
+			if rule == "" {
+				rule = alert.Alert
+			}
+			if alert.PluginID != "" {
+				rule = alert.PluginID + ": " + rule
+			}
+			severity, _, _ := strings.Cut(alert.RiskDesc, " ")
+
+			for _, instance := range alert.Instances {
+				findings = append(findings, models.SecurityFinding{
+					Rule:        rule,
+					Severity:    strings.ToLower(severity),
+					Method:      instance.Method,
+					URL:         instance.URI,
+					Description: alert.Desc,
+					Evidence:    instance.Evidence,
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
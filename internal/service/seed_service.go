@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"strings"
+)
+
+// seedFileKey is the metadata key recording which fixture file a record came from
+const seedFileKey = "seed_file"
+
+// SeedService loads example collections, specs and environments from a fixture directory
+type SeedService struct {
+	collectionRepo     interfaces.CollectionRepository
+	openAPIRepo        interfaces.OpenAPIRepository
+	environmentRepo    interfaces.EnvironmentRepository
+	collectionService  interfaces.CollectionService
+	openAPIService     interfaces.OpenAPIService
+	environmentService interfaces.EnvironmentService
+}
+
+// NewSeedService creates a new seed service
+func NewSeedService(
+	collectionRepo interfaces.CollectionRepository,
+	openAPIRepo interfaces.OpenAPIRepository,
+	environmentRepo interfaces.EnvironmentRepository,
+	collectionService interfaces.CollectionService,
+	openAPIService interfaces.OpenAPIService,
+	environmentService interfaces.EnvironmentService,
+) interfaces.SeedService {
+	return &SeedService{
+		collectionRepo:     collectionRepo,
+		openAPIRepo:        openAPIRepo,
+		environmentRepo:    environmentRepo,
+		collectionService:  collectionService,
+		openAPIService:     openAPIService,
+		environmentService: environmentService,
+	}
+}
+
+// Seed imports every JSON fixture under dir. Each record is tagged with its
+// fixture path, so files loaded by an earlier run are skipped.
+func (s *SeedService) Seed(ctx context.Context, dir string) (*models.SeedReport, error) {
+	report := &models.SeedReport{}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		loaded, err := s.seedFile(ctx, path, rel)
+		switch {
+		case err != nil:
+			log.Printf("seed: failed to load %s: %v", rel, err)
+			report.Failed++
+		case loaded:
+			log.Printf("seed: loaded %s", rel)
+			report.Loaded++
+		default:
+			report.Skipped++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return report, fmt.Errorf("failed to read seed directory: %w", err)
+	}
+
+	return report, nil
+}
+
+// seedFile imports one fixture, reporting false when it was already loaded
+func (s *SeedService) seedFile(ctx context.Context, path, rel string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	opts := models.ImportOptions{
+		Metadata:   models.JSONMap{"source": "seed", seedFileKey: rel},
+		Provenance: &models.Provenance{Source: models.ProvenanceSeed, Filename: rel},
+	}
+
+	switch docType := detectDocumentType(data); docType {
+	case models.DocumentTypeOpenAPI:
+		exists, err := s.openAPIRepo.ExistsByMetadata(ctx, seedFileKey, rel)
+		if err != nil || exists {
+			return false, err
+		}
+		_, err = s.openAPIService.ImportOpenAPISpec(ctx, data, opts)
+		return err == nil, err
+	case models.DocumentTypePostman:
+		exists, err := s.collectionRepo.ExistsByMetadata(ctx, seedFileKey, rel)
+		if err != nil || exists {
+			return false, err
+		}
+		_, err = s.collectionService.ImportPostmanCollection(ctx, data, opts)
+		return err == nil, err
+	case models.DocumentTypeEnvironment:
+		exists, err := s.environmentRepo.ExistsByMetadata(ctx, seedFileKey, rel)
+		if err != nil || exists {
+			return false, err
+		}
+		_, err = s.environmentService.ImportPostmanEnvironment(ctx, data, opts)
+		return err == nil, err
+	default:
+		return false, models.NewValidationError("unrecognized document: expected an OpenAPI spec, a Postman collection or a Postman environment")
+	}
+}
+
+// detectDocumentType tells OpenAPI specs, Postman collections and Postman
+// environments apart by their top-level keys
+func detectDocumentType(data []byte) string {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+
+	if _, ok := doc["openapi"]; ok {
+		return models.DocumentTypeOpenAPI
+	}
+	if _, ok := doc["swagger"]; ok {
+		return models.DocumentTypeOpenAPI
+	}
+
+	_, hasInfo := doc["info"]
+	_, hasItems := doc["item"]
+	if hasInfo && hasItems {
+		return models.DocumentTypePostman
+	}
+
+	_, hasName := doc["name"]
+	_, hasValues := doc["values"]
+	if hasName && hasValues {
+		return models.DocumentTypeEnvironment
+	}
+
+	return ""
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/signing"
+	"postman-api/internal/version"
+	"time"
+)
+
+// SigningService signs exported documents with a manifest recording their
+// hash, exporter and export time
+type SigningService struct {
+	signer   *signing.Signer
+	exporter string
+}
+
+// NewSigningService creates a new signing service; with a nil signer every
+// operation fails with models.ErrSigningDisabled
+func NewSigningService(signer *signing.Signer, exporter string) interfaces.SigningService {
+	return &SigningService{
+		signer:   signer,
+		exporter: exporter,
+	}
+}
+
+// SignExport completes manifest for data and returns data wrapped with the
+// manifest and its signature; the kind, ID, filename and content type of
+// manifest are kept as given
+func (s *SigningService) SignExport(ctx context.Context, manifest models.ExportManifest, data []byte) (*models.SignedExport, error) {
+	if s.signer == nil {
+		return nil, models.ErrSigningDisabled
+	}
+
+	sum := sha256.Sum256(data)
+	manifest.SHA256 = hex.EncodeToString(sum[:])
+	manifest.Size = int64(len(data))
+	manifest.Exporter = s.exporter
+	manifest.Version = version.Version
+	manifest.ExportedAt = time.Now().UTC().Truncate(time.Second)
+	manifest.Algorithm = s.signer.Algorithm()
+	manifest.KeyID = s.signer.KeyID()
+
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export manifest: %w", err)
+	}
+
+	return &models.SignedExport{
+		Manifest:  manifest,
+		Document:  base64.StdEncoding.EncodeToString(data),
+		Signature: s.signer.Sign(encoded),
+	}, nil
+}
+
+// VerifyExport checks that a signed export was signed with this catalog's key
+// and that its document matches the manifest
+func (s *SigningService) VerifyExport(ctx context.Context, export *models.SignedExport) (*models.ExportVerification, error) {
+	if s.signer == nil {
+		return nil, models.ErrSigningDisabled
+	}
+
+	manifest := export.Manifest
+	invalid := func(reason string) (*models.ExportVerification, error) {
+		return &models.ExportVerification{Reason: reason, Manifest: &manifest}, nil
+	}
+
+	if manifest.Algorithm != s.signer.Algorithm() || manifest.KeyID != s.signer.KeyID() {
+		return invalid("export was signed with a different key")
+	}
+
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export manifest: %w", err)
+	}
+	if !s.signer.Verify(encoded, export.Signature) {
+		return invalid("signature does not match the manifest")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(export.Document)
+	if err != nil {
+		return invalid("document is not valid base64")
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != manifest.SHA256 || int64(len(data)) != manifest.Size {
+		return invalid("document does not match the manifest")
+	}
+
+	return &models.ExportVerification{Valid: true, Manifest: &manifest}, nil
+}
+
+// SigningKey describes the key exports are signed with
+func (s *SigningService) SigningKey(ctx context.Context) (*models.SigningKey, error) {
+	if s.signer == nil {
+		return nil, models.ErrSigningDisabled
+	}
+
+	return &models.SigningKey{
+		Algorithm: s.signer.Algorithm(),
+		KeyID:     s.signer.KeyID(),
+		PublicKey: s.signer.PublicKey(),
+	}, nil
+}
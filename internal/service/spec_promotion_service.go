@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"postman-api/internal/codegen"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/storage"
+	"postman-api/internal/variables"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecPromotionService promotes OpenAPI specs through environment-of-record
+// labels such as dev, staging and prod. An approved spec enters the first
+// label, and each promotion copies the content of record of a label to the
+// next one, queueing the downstream exports configured for it.
+type SpecPromotionService struct {
+	openAPIRepo   interfaces.OpenAPIRepository
+	reviewRepo    interfaces.SpecReviewRepository
+	promotionRepo interfaces.SpecPromotionRepository
+	reviewService interfaces.SpecReviewService
+	jobService    interfaces.JobService
+	transactor    interfaces.Transactor
+	labels        []string
+	exports       map[string][]string
+	dir           string
+}
+
+// NewSpecPromotionService creates a new spec promotion service promoting
+// specs through labels in order; promotions to a label queue its exports,
+// which are written below dir
+func NewSpecPromotionService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	reviewRepo interfaces.SpecReviewRepository,
+	promotionRepo interfaces.SpecPromotionRepository,
+	reviewService interfaces.SpecReviewService,
+	jobService interfaces.JobService,
+	transactor interfaces.Transactor,
+	labels []string,
+	exports map[string][]string,
+	dir string,
+) interfaces.SpecPromotionService {
+	return &SpecPromotionService{
+		openAPIRepo:   openAPIRepo,
+		reviewRepo:    reviewRepo,
+		promotionRepo: promotionRepo,
+		reviewService: reviewService,
+		jobService:    jobService,
+		transactor:    transactor,
+		labels:        labels,
+		exports:       exports,
+		dir:           dir,
+	}
+}
+
+// ListSpecLabels returns the content of record of a spec at every label it
+// was promoted to, in pipeline order
+func (s *SpecPromotionService) ListSpecLabels(ctx context.Context, specID int64) ([]*models.SpecPromotion, error) {
+	if _, err := s.openAPIRepo.GetByID(ctx, specID); err != nil {
+		return nil, fmt.Errorf("OpenAPI spec not found: %w", err)
+	}
+
+	promotions, err := s.promotionRepo.ListCurrent(ctx, specID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(promotions, func(a, b *models.SpecPromotion) int {
+		return slices.Index(s.labels, a.Label) - slices.Index(s.labels, b.Label)
+	})
+
+	return promotions, nil
+}
+
+// GetSpecLabel returns the content of record of a spec at label
+func (s *SpecPromotionService) GetSpecLabel(ctx context.Context, specID int64, label string) (*models.SpecPromotion, error) {
+	if err := s.validLabel(label); err != nil {
+		return nil, err
+	}
+
+	return s.promotionRepo.Current(ctx, specID, label)
+}
+
+// ListSpecPromotions returns the promotions of a spec with pagination, newest
+// first, without the content they copied
+func (s *SpecPromotionService) ListSpecPromotions(ctx context.Context, specID int64, page, pageSize int) ([]*models.SpecPromotion, int, error) {
+	if _, err := s.openAPIRepo.GetByID(ctx, specID); err != nil {
+		return nil, 0, fmt.Errorf("OpenAPI spec not found: %w", err)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	promotions, err := s.promotionRepo.ListBySpecID(ctx, specID, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.promotionRepo.CountBySpecID(ctx, specID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return promotions, total, nil
+}
+
+// PromoteSpec copies a spec to label: the spec itself, which must be
+// approved, for the first label, and the content of record of the previous
+// label otherwise. The exports configured for label are queued as jobs.
+func (s *SpecPromotionService) PromoteSpec(ctx context.Context, specID int64, label, promotedBy string) (*models.SpecPromotion, error) {
+	if err := s.validLabel(label); err != nil {
+		return nil, err
+	}
+
+	promotedBy = strings.TrimSpace(promotedBy)
+	if promotedBy == "" {
+		return nil, models.NewValidationError("promoted_by is required")
+	}
+
+	promotion := &models.SpecPromotion{
+		SpecID:     specID,
+		Label:      label,
+		PromotedBy: promotedBy,
+	}
+
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		// Holding the spec lock keeps updates and review decisions out
+		// until the content is copied
+		if err := s.reviewRepo.WithTx(tx).LockSpec(ctx, specID); err != nil {
+			return err
+		}
+
+		promotions := s.promotionRepo.WithTx(tx)
+		if i := slices.Index(s.labels, label); i > 0 {
+			promotion.FromLabel = s.labels[i-1]
+			source, err := promotions.Current(ctx, specID, promotion.FromLabel)
+			if models.ErrorCodeOf(err) == models.ErrCodeNotFound {
+				return models.NewConflictError(fmt.Sprintf("OpenAPI spec must be promoted to %s first", promotion.FromLabel), nil)
+			}
+			if err != nil {
+				return err
+			}
+			promotion.Title, promotion.Version, promotion.Content = source.Title, source.Version, source.Content
+		} else {
+			if err := s.reviewService.RequireApproved(ctx, specID); err != nil {
+				return err
+			}
+			spec, err := s.openAPIRepo.GetByID(ctx, specID)
+			if err != nil {
+				return err
+			}
+			promotion.Title, promotion.Version, promotion.Content = spec.Title, spec.Version, spec.Content
+		}
+
+		return promotions.Create(ctx, promotion)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, export := range s.exports[label] {
+		job, err := s.jobService.Enqueue(ctx, models.JobPromotionExport, models.JSONMap{
+			"promotion_id": promotion.ID,
+			"export":       export,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue %s export: %w", export, err)
+		}
+		promotion.Jobs = append(promotion.Jobs, job)
+	}
+
+	return promotion, nil
+}
+
+// RunPromotionExport renders export of the content a promotion copied and
+// writes it to <dir>/<label>/<spec id>/, returning the path of the file
+func (s *SpecPromotionService) RunPromotionExport(ctx context.Context, promotionID int64, export string) (string, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, promotionID)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		data []byte
+		name string
+	)
+	kind, arg, _ := strings.Cut(export, ":")
+	switch kind {
+	case models.PromotionExportGateway:
+		data, err = codegen.Gateway(promotion.Content, arg)
+		name = "gateway-" + arg + ".json"
+	case models.PromotionExportDocs:
+		data, err = codegen.Documentation(specDocs(promotion), arg)
+		name = "docs.md"
+		if arg == codegen.DocsHTML {
+			name = "docs.html"
+		}
+	default:
+		return "", models.NewValidationError("unsupported promotion export %q", export)
+	}
+	if err != nil {
+		return "", models.NewValidationError("cannot export %s: %s", export, err.Error())
+	}
+
+	path := filepath.Join(s.dir, promotion.Label, strconv.FormatInt(promotion.SpecID, 10), name)
+	if err := storage.WriteFile(path, data); err != nil {
+		return "", fmt.Errorf("failed to write %s export: %w", export, err)
+	}
+
+	return path, nil
+}
+
+func (s *SpecPromotionService) validLabel(label string) error {
+	if !slices.Contains(s.labels, label) {
+		return models.NewValidationError("unknown label %q: use %s", label, strings.Join(s.labels, ", "))
+	}
+	return nil
+}
+
+// specDocs documents the operations of promoted content the way collection
+// docs document requests, each operation as the request converting the spec
+// to a collection would create
+func specDocs(promotion *models.SpecPromotion) codegen.Docs {
+	info, _ := promotion.Content["info"].(map[string]any)
+	description, _ := info["description"].(string)
+
+	baseURL, values := codegen.ServerTemplate(promotion.Content)
+	docs := codegen.Docs{
+		Name:        promotion.Title,
+		Description: description,
+		Variables:   []codegen.DocsVariable{{Name: "baseUrl", Value: baseURL}},
+	}
+	items := codegen.CollectionItems(promotion.Content)
+	for _, item := range items {
+		for name, value := range item.PathParams {
+			if _, ok := values[name]; !ok {
+				values[name] = value
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		docs.Variables = append(docs.Variables, codegen.DocsVariable{Name: name, Value: values[name]})
+	}
+
+	for _, item := range items {
+		flat := flattenRequest(convertedRequest(item, 0), nil, variables.New())
+		// {{baseUrl}} carries its own scheme
+		if strings.HasPrefix(flat.URL, "http://{{") {
+			flat.URL = strings.TrimPrefix(flat.URL, "http://")
+		}
+		docs.Requests = append(docs.Requests, codegen.DocsRequest{
+			Name:        item.Name,
+			Folder:      item.Folder,
+			Description: item.Description,
+			Request: codegen.SnippetRequest{
+				Method:  flat.Method,
+				URL:     flat.URL,
+				Headers: flat.Headers,
+				Body:    flat.Body,
+			},
+		})
+	}
+
+	return docs
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// SpecReviewService moves OpenAPI specs through review: a draft is submitted,
+// approved by enough reviewers, then published. Requesting changes sends it
+// back to draft, as does an update changing its content.
+type SpecReviewService struct {
+	openAPIRepo       interfaces.OpenAPIRepository
+	reviewRepo        interfaces.SpecReviewRepository
+	transactor        interfaces.Transactor
+	requiredApprovals int
+}
+
+// NewSpecReviewService creates a new spec review service; a submitted spec
+// is approved once requiredApprovals reviewers approve it, and while
+// requiredApprovals is zero one approval does and exports don't wait for it
+func NewSpecReviewService(
+	openAPIRepo interfaces.OpenAPIRepository,
+	reviewRepo interfaces.SpecReviewRepository,
+	transactor interfaces.Transactor,
+	requiredApprovals int,
+) interfaces.SpecReviewService {
+	return &SpecReviewService{
+		openAPIRepo:       openAPIRepo,
+		reviewRepo:        reviewRepo,
+		transactor:        transactor,
+		requiredApprovals: requiredApprovals,
+	}
+}
+
+// reviewTransitions lists the statuses each decision can be taken from
+var reviewTransitions = map[string][]string{
+	models.DecisionSubmit:         {models.ReviewDraft},
+	models.DecisionApprove:        {models.ReviewInReview},
+	models.DecisionRequestChanges: {models.ReviewInReview, models.ReviewApproved},
+	models.DecisionPublish:        {models.ReviewApproved},
+}
+
+// GetSpecReview returns the review status of a spec with its decisions
+func (s *SpecReviewService) GetSpecReview(ctx context.Context, specID int64) (*models.SpecReview, error) {
+	decisions, err := s.reviewRepo.ListBySpecID(ctx, specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review decisions: %w", err)
+	}
+
+	status := models.ReviewDraft
+	if len(decisions) == 0 {
+		if _, err := s.openAPIRepo.GetByID(ctx, specID); err != nil {
+			return nil, fmt.Errorf("OpenAPI spec not found: %w", err)
+		}
+	} else {
+		status = decisions[len(decisions)-1].ToStatus
+	}
+
+	approvers, err := s.reviewRepo.Approvers(ctx, specID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvers: %w", err)
+	}
+
+	return &models.SpecReview{
+		SpecID:            specID,
+		Status:            status,
+		RequiredApprovals: s.requiredApprovals,
+		Approvers:         approvers,
+		Decisions:         decisions,
+	}, nil
+}
+
+// DecideSpecReview records a reviewer's decision on a spec and moves it to
+// the status the decision leads to, returning the review
+func (s *SpecReviewService) DecideSpecReview(ctx context.Context, decision *models.SpecReviewDecision) (*models.SpecReview, error) {
+	from, ok := reviewTransitions[decision.Decision]
+	if !ok {
+		return nil, models.NewValidationError("unsupported decision %q: use submit, approve, request_changes or publish", decision.Decision)
+	}
+
+	decision.Reviewer = strings.TrimSpace(decision.Reviewer)
+	if decision.Reviewer == "" {
+		return nil, models.NewValidationError("reviewer is required")
+	}
+
+	err := s.transactor.RunInTx(ctx, func(ctx context.Context, tx bun.Tx) error {
+		reviews := s.reviewRepo.WithTx(tx)
+		if err := reviews.LockSpec(ctx, decision.SpecID); err != nil {
+			return err
+		}
+
+		status, err := reviews.Status(ctx, decision.SpecID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(from, status) {
+			return models.NewConflictError(fmt.Sprintf("cannot %s a spec that is %s", strings.ReplaceAll(decision.Decision, "_", " "), status), nil)
+		}
+
+		decision.FromStatus = status
+		decision.ToStatus, err = s.nextStatus(ctx, reviews, decision)
+		if err != nil {
+			return err
+		}
+
+		return reviews.Create(ctx, decision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSpecReview(ctx, decision.SpecID)
+}
+
+// nextStatus returns the status a valid decision leads to; an approval only
+// approves the spec once enough distinct reviewers approved it
+func (s *SpecReviewService) nextStatus(ctx context.Context, reviews interfaces.SpecReviewRepository, decision *models.SpecReviewDecision) (string, error) {
+	switch decision.Decision {
+	case models.DecisionSubmit:
+		return models.ReviewInReview, nil
+	case models.DecisionRequestChanges:
+		return models.ReviewDraft, nil
+	case models.DecisionPublish:
+		return models.ReviewPublished, nil
+	}
+
+	approvers, err := reviews.Approvers(ctx, decision.SpecID)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(approvers, decision.Reviewer) {
+		return "", models.NewConflictError(fmt.Sprintf("%s already approved this spec", decision.Reviewer), nil)
+	}
+
+	if len(approvers)+1 >= max(s.requiredApprovals, 1) {
+		return models.ReviewApproved, nil
+	}
+	return models.ReviewInReview, nil
+}
+
+// RequireApproved fails unless a spec is approved or published, when
+// approvals are required
+func (s *SpecReviewService) RequireApproved(ctx context.Context, specID int64) error {
+	if s.requiredApprovals == 0 {
+		return nil
+	}
+
+	status, err := s.reviewRepo.Status(ctx, specID)
+	if err != nil {
+		return fmt.Errorf("failed to get review status: %w", err)
+	}
+
+	if status != models.ReviewApproved && status != models.ReviewPublished {
+		return models.NewConflictError(fmt.Sprintf("OpenAPI spec must be approved first, it is %s", status), nil)
+	}
+
+	return nil
+}
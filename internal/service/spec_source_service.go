@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"postman-api/internal/events"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/secrets"
+	"time"
+)
+
+const minSpecSourcePollInterval = 60
+
+// SpecSourceService handles business logic for polled spec sources
+type SpecSourceService struct {
+	sourceRepo     interfaces.SpecSourceRepository
+	openAPIService interfaces.OpenAPIService
+	publisher      events.Publisher
+	cipher         *secrets.Cipher
+	httpClient     *http.Client
+}
+
+// NewSpecSourceService creates a new spec source service; sources are fetched
+// through proxy. Without a cipher, sources with credentials are rejected.
+func NewSpecSourceService(
+	sourceRepo interfaces.SpecSourceRepository,
+	openAPIService interfaces.OpenAPIService,
+	publisher events.Publisher,
+	cipher *secrets.Cipher,
+	proxy models.OutboundProxy,
+) interfaces.SpecSourceService {
+	return &SpecSourceService{
+		sourceRepo:     sourceRepo,
+		openAPIService: openAPIService,
+		publisher:      publisher,
+		cipher:         cipher,
+		httpClient:     newFetchClient(proxy),
+	}
+}
+
+// CreateSpecSource registers a new spec source, encrypting its credentials
+func (s *SpecSourceService) CreateSpecSource(ctx context.Context, source *models.SpecSource) error {
+	if err := validateSpecSource(source); err != nil {
+		return err
+	}
+
+	if err := s.sealAuth(source); err != nil {
+		return err
+	}
+
+	if err := s.sourceRepo.Create(ctx, source); err != nil {
+		return err
+	}
+
+	redactAuth(source)
+	return nil
+}
+
+// GetSpecSource retrieves a spec source by ID
+func (s *SpecSourceService) GetSpecSource(ctx context.Context, id int64) (*models.SpecSource, error) {
+	source, err := s.sourceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	redactAuth(source)
+	return source, nil
+}
+
+// ListSpecSources returns all spec sources with pagination
+func (s *SpecSourceService) ListSpecSources(ctx context.Context, page, pageSize int) ([]*models.SpecSource, int, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	sources, err := s.sourceRepo.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.sourceRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, source := range sources {
+		redactAuth(source)
+	}
+
+	return sources, total, nil
+}
+
+// UpdateSpecSource updates the configuration of a spec source, keeping its
+// poll state; a nil Auth keeps the stored credentials and an empty one removes them
+func (s *SpecSourceService) UpdateSpecSource(ctx context.Context, source *models.SpecSource) error {
+	existing, err := s.sourceRepo.GetByID(ctx, source.ID)
+	if err != nil {
+		return fmt.Errorf("spec source not found: %w", err)
+	}
+
+	if err := validateSpecSource(source); err != nil {
+		return err
+	}
+
+	source.LastHash = existing.LastHash
+	source.LastSpecID = existing.LastSpecID
+	source.LastCheckedAt = existing.LastCheckedAt
+	source.LastError = existing.LastError
+	source.CreatedAt = existing.CreatedAt
+
+	switch {
+	case source.Auth == nil:
+		source.Auth = existing.Auth
+	case len(source.Auth) == 0:
+		source.Auth = nil
+	default:
+		if err := s.sealAuth(source); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sourceRepo.Update(ctx, source); err != nil {
+		return err
+	}
+
+	redactAuth(source)
+	return nil
+}
+
+// DeleteSpecSource removes a spec source; specs it imported are kept
+func (s *SpecSourceService) DeleteSpecSource(ctx context.Context, id int64) error {
+	return s.sourceRepo.Delete(ctx, id)
+}
+
+// RefreshSpecSource fetches the remote document and imports it as a new spec
+// when its hash differs from the last one seen
+func (s *SpecSourceService) RefreshSpecSource(ctx context.Context, id int64) (*models.SpecSourceRefresh, error) {
+	source, err := s.sourceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("spec source not found: %w", err)
+	}
+
+	return s.refresh(ctx, source)
+}
+
+// PollDueSources refreshes every enabled source whose poll interval has elapsed
+func (s *SpecSourceService) PollDueSources(ctx context.Context) error {
+	sources, err := s.sourceRepo.ListDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if _, err := s.refresh(ctx, source); err != nil {
+			log.Printf("Failed to refresh spec source %d: %v", source.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// RunPoller polls due sources every interval until ctx is cancelled
+func (s *SpecSourceService) RunPoller(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.PollDueSources(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to poll spec sources: %v", err)
+			}
+		}
+	}
+}
+
+func (s *SpecSourceService) refresh(ctx context.Context, source *models.SpecSource) (*models.SpecSourceRefresh, error) {
+	now := time.Now()
+	source.LastCheckedAt = &now
+
+	auth, err := openFetchAuth(s.cipher, source.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetchDocument(ctx, s.httpClient, source.URL, auth)
+	if err != nil {
+		source.LastError = err.Error()
+		if updateErr := s.sourceRepo.Update(ctx, source); updateErr != nil {
+			return nil, updateErr
+		}
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	result := &models.SpecSourceRefresh{SourceID: source.ID, Hash: hash}
+
+	if hash != source.LastHash {
+		imported, err := s.openAPIService.ImportOpenAPISpec(ctx, data, models.ImportOptions{
+			Metadata: models.JSONMap{
+				"source_id":  source.ID,
+				"source_url": source.URL,
+				"hash":       hash,
+			},
+			Provenance: &models.Provenance{
+				Source:       models.ProvenanceSpecSource,
+				URL:          source.URL,
+				OriginalHash: hash,
+			},
+		})
+		if err != nil {
+			source.LastError = err.Error()
+			if updateErr := s.sourceRepo.Update(ctx, source); updateErr != nil {
+				return nil, updateErr
+			}
+			if appErr, ok := models.AsError(err); ok && appErr.Code == models.ErrCodeValidation {
+				return nil, models.NewError(models.ErrCodeUpstream, "spec source served an invalid document: "+appErr.Message)
+			}
+			return nil, err
+		}
+
+		source.LastHash = hash
+		source.LastSpecID = imported.ID
+		result.Changed = true
+		result.SpecID = imported.ID
+	}
+
+	source.LastError = ""
+	if err := s.sourceRepo.Update(ctx, source); err != nil {
+		return nil, err
+	}
+
+	if result.Changed {
+		event := events.Event{
+			Type:       events.SpecSourceChanged,
+			EntityType: "spec_source",
+			EntityID:   source.ID,
+			Payload: map[string]any{
+				"spec_id": result.SpecID,
+				"hash":    hash,
+				"url":     source.URL,
+			},
+			OccurredAt: now,
+		}
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish %s event: %v", event.Type, err)
+		}
+	}
+
+	return result, nil
+}
+
+// sealAuth encrypts the credential of source.Auth
+func (s *SpecSourceService) sealAuth(source *models.SpecSource) error {
+	sealed, err := sealFetchAuth(s.cipher, source.Auth)
+	if err != nil {
+		return err
+	}
+
+	source.Auth = sealed
+	return nil
+}
+
+// redactAuth replaces the credentials of source with a description of them
+// before it leaves the service
+func redactAuth(source *models.SpecSource) {
+	source.AuthStatus = describeFetchAuth(source.Auth)
+}
+
+func validateSpecSource(source *models.SpecSource) error {
+	var errs models.FieldErrors
+	validateName(&errs, "name", source.Name)
+
+	u, err := url.Parse(source.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.Add("url", "must be an absolute http(s) URL")
+	}
+
+	if source.PollIntervalSeconds < minSpecSourcePollInterval {
+		errs.Add("poll_interval_seconds", "must be at least %d", minSpecSourcePollInterval)
+	}
+
+	if len(source.Auth) > 0 {
+		switch source.Auth["type"] {
+		case "bearer", "basic", "header":
+		default:
+			errs.Add("auth.type", "must be one of bearer, basic or header")
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+)
+
+// maxStorageReportCollections bounds how many collections a storage report lists
+const maxStorageReportCollections = 500
+
+// StorageService reports where the database's space goes
+type StorageService struct {
+	storageRepo interfaces.StorageRepository
+}
+
+// NewStorageService creates a new storage service
+func NewStorageService(storageRepo interfaces.StorageRepository) interfaces.StorageService {
+	return &StorageService{storageRepo: storageRepo}
+}
+
+// StorageReport returns the size of every table, the usage of every team and
+// the limit largest collections
+func (s *StorageService) StorageReport(ctx context.Context, limit int) (*models.StorageReport, error) {
+	if limit < 1 || limit > maxStorageReportCollections {
+		return nil, models.NewValidationError("limit must be between 1 and %d", maxStorageReportCollections)
+	}
+
+	tables, err := s.storageRepo.TableUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table usage: %w", err)
+	}
+
+	teams, err := s.storageRepo.TeamUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team usage: %w", err)
+	}
+
+	collections, err := s.storageRepo.CollectionUsage(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection usage: %w", err)
+	}
+
+	report := &models.StorageReport{
+		Tables:      tables,
+		Teams:       teams,
+		Collections: collections,
+	}
+	for _, table := range tables {
+		report.TotalBytes += table.TotalBytes
+	}
+	if report.Tables == nil {
+		report.Tables = []models.TableStorage{}
+	}
+	if report.Teams == nil {
+		report.Teams = []models.TeamStorage{}
+	}
+	if report.Collections == nil {
+		report.Collections = []models.CollectionStorage{}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"postman-api/internal/models"
+	"postman-api/internal/variables"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxNameLength bounds the names of collections, requests, specs and environments
+const maxNameLength = 255
+
+// httpMethods are the request methods Postman can send
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "TRACE": true, "CONNECT": true,
+	"COPY": true, "LINK": true, "UNLINK": true, "PURGE": true, "LOCK": true,
+	"UNLOCK": true, "PROPFIND": true, "VIEW": true,
+}
+
+// headerKeyPattern matches an RFC 9110 field name token
+var headerKeyPattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// field joins a field name onto a path prefix
+func field(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// validateName checks that a name is present and not too long
+func validateName(errs *models.FieldErrors, path, name string) {
+	switch {
+	case strings.TrimSpace(name) == "":
+		errs.Add(path, "is required")
+	case utf8.RuneCountInString(name) > maxNameLength:
+		errs.Add(path, "must be at most %d characters", maxNameLength)
+	}
+}
+
+// validateRequest checks the name, method, URL and headers of a request
+func validateRequest(errs *models.FieldErrors, prefix string, request *models.Request) {
+	validateName(errs, field(prefix, "name"), request.Name)
+
+	if method := strings.ToUpper(request.Method); method == "" {
+		errs.Add(field(prefix, "method"), "is required")
+	} else if !httpMethods[method] {
+		errs.Add(field(prefix, "method"), "%q is not a supported HTTP method", request.Method)
+	}
+
+	if raw, _ := request.URL["raw"].(string); raw != "" {
+		if err := validateRequestURL(raw); err != nil {
+			errs.Add(field(prefix, "url"), "%v", err)
+		}
+	}
+
+	validateHeaders(errs, field(prefix, "headers"), request.Headers)
+	validateRunSettings(errs, field(prefix, "run_settings"), request.RunSettings)
+}
+
+// validateRunSettings checks the overrides of how the runner sends a request
+func validateRunSettings(errs *models.FieldErrors, prefix string, settings *models.RunSettings) {
+	if settings == nil {
+		return
+	}
+
+	if settings.TimeoutMs < 0 || time.Duration(settings.TimeoutMs)*time.Millisecond > maxRequestTimeout {
+		errs.Add(field(prefix, "timeout_ms"), "must be between 0 and %d", maxRequestTimeout.Milliseconds())
+	}
+	validateRetryPolicy(errs, field(prefix, "retry"), settings.Retry)
+}
+
+// validateHostOverrides checks that host overrides map host names to IP addresses
+func validateHostOverrides(errs *models.FieldErrors, path string, overrides map[string]string) {
+	for _, host := range slices.Sorted(maps.Keys(overrides)) {
+		if host == "" || strings.ContainsAny(host, ":/ ") {
+			errs.Add(path, "%q is not a host name", host)
+		}
+		if net.ParseIP(overrides[host]) == nil {
+			errs.Add(path, "address of %q must be an IP address", host)
+		}
+	}
+}
+
+// validateRetryPolicy checks the attempts, backoff and statuses of a retry policy
+func validateRetryPolicy(errs *models.FieldErrors, prefix string, policy *models.RetryPolicy) {
+	if policy == nil {
+		return
+	}
+
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > maxRetryAttempts {
+		errs.Add(field(prefix, "max_attempts"), "must be between 1 and %d", maxRetryAttempts)
+	}
+	if policy.BackoffMs < 0 || time.Duration(policy.BackoffMs)*time.Millisecond > maxRunDelay {
+		errs.Add(field(prefix, "backoff_ms"), "must be between 0 and %d", maxRunDelay.Milliseconds())
+	}
+	for _, status := range policy.RetryOn {
+		if status < 100 || status > 599 {
+			errs.Add(field(prefix, "retry_on"), "%d is not an HTTP status code", status)
+		}
+	}
+}
+
+// validateRequestURL parses a raw request URL, treating {{variables}} as
+// placeholders and allowing URLs without a scheme as Postman does
+func validateRequestURL(raw string) error {
+	if strings.TrimSpace(raw) != raw {
+		return fmt.Errorf("must not start or end with whitespace")
+	}
+
+	// Variables may stand for a host, a port or a path segment; 1 is valid as each
+	resolved := variables.Pattern.ReplaceAllString(raw, "1")
+	if !strings.Contains(resolved, "://") && !strings.HasPrefix(resolved, "/") {
+		resolved = "http://" + resolved
+	}
+
+	u, err := url.Parse(resolved)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+	if u.Scheme != "" && u.Host == "" {
+		return fmt.Errorf("is not a valid URL: missing host")
+	}
+
+	return nil
+}
+
+// validateHeaders checks that header keys are valid field names, allowing {{variables}}
+func validateHeaders(errs *models.FieldErrors, path string, headers map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		resolved := variables.Pattern.ReplaceAllString(key, "var")
+		if !headerKeyPattern.MatchString(resolved) {
+			errs.Add(path, "%q is not a valid header name", key)
+		}
+		if strings.ContainsAny(headers[key], "\r\n") {
+			errs.Add(path, "value of %q must not contain line breaks", key)
+		}
+	}
+}
+
+// validateExample checks the name and status code of a saved example
+func validateExample(example *models.Example) error {
+	var errs models.FieldErrors
+	validateName(&errs, "name", example.Name)
+	if example.Code != 0 && (example.Code < 100 || example.Code > 599) {
+		errs.Add("code", "must be an HTTP status code")
+	}
+	return errs.Err()
+}
+
+// validateCollection checks the fields of a collection
+func validateCollection(collection *models.Collection) error {
+	var errs models.FieldErrors
+	validateName(&errs, "name", collection.Name)
+	return errs.Err()
+}
+
+// validateSpec checks the fields of an OpenAPI specification
+func validateSpec(spec *models.OpenAPISpec) error {
+	var errs models.FieldErrors
+	validateName(&errs, "title", spec.Title)
+	if strings.TrimSpace(spec.Version) == "" {
+		errs.Add("version", "is required")
+	}
+	return errs.Err()
+}
+
+// validateItem checks the request of an item, or the name and children of a folder
+func validateItem(errs *models.FieldErrors, path string, item models.PostmanItem) {
+	if item.Request != nil {
+		validateRequest(errs, path, newRequestFromPostmanItem(item, 0, ""))
+		return
+	}
+
+	validateName(errs, field(path, "name"), item.Name)
+	for i, child := range item.Item {
+		validateItem(errs, fmt.Sprintf("%s.item[%d]", path, i), child)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"postman-api/internal/models"
+	"slices"
+	"strings"
+)
+
+// workspaceManifest is the file of a Postman data dump listing its contents
+const workspaceManifest = "archive.json"
+
+// workspaceDump is the single JSON file Postman's older data dumps come as
+type workspaceDump struct {
+	Collections  []json.RawMessage                `json:"collections"`
+	Environments []json.RawMessage                `json:"environments"`
+	Globals      []models.PostmanEnvironmentValue `json:"globals"`
+}
+
+// workspaceEntry is the part of a dumped document that tells what it is
+type workspaceEntry struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Scope string `json:"_postman_variable_scope"`
+	Info  struct {
+		PostmanID string `json:"_postman_id"`
+		Name      string `json:"name"`
+	} `json:"info"`
+}
+
+// ImportWorkspace imports a Postman workspace dump: a zip archive of the
+// collection, environment and globals files of a data export, or the single
+// JSON file of older exports. Every collection and environment is imported
+// on its own and every global set, so that one that fails does not stop the
+// others; the report maps each to what was created from it.
+func (s *BulkImportService) ImportWorkspace(ctx context.Context, file models.ImportFile, opts models.ImportOptions) (*models.WorkspaceImportReport, error) {
+	var entries []models.ImportFile
+	if bytes.HasPrefix(file.Data, zipMagic) {
+		archived, err := archiveEntries(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = archived
+	} else {
+		entries = []models.ImportFile{file}
+	}
+
+	report := &models.WorkspaceImportReport{
+		Collections:  []*models.BulkImportResult{},
+		Environments: []*models.BulkImportResult{},
+		Globals:      []*models.BulkImportResult{},
+	}
+
+	for _, entry := range entries {
+		if path.Base(entry.Filename) == workspaceManifest {
+			continue
+		}
+
+		var dump workspaceDump
+		if json.Unmarshal(entry.Data, &dump) == nil && (dump.Collections != nil || dump.Environments != nil) {
+			s.importDump(ctx, report, entry.Filename, dump, opts)
+			continue
+		}
+
+		var doc workspaceEntry
+		_ = json.Unmarshal(entry.Data, &doc)
+		switch {
+		case doc.Scope == postmanScopeGlobals || inWorkspaceDir(entry.Filename, "globals"):
+			var globals models.PostmanEnvironment
+			if err := json.Unmarshal(entry.Data, &globals); err != nil {
+				report.Globals = append(report.Globals, &models.BulkImportResult{
+					Filename: entry.Filename,
+					Err:      models.NewValidationError("invalid Postman globals format: %v", err),
+				})
+				report.Failed++
+				continue
+			}
+			s.importGlobals(ctx, report, entry.Filename, globals.Values)
+		case doc.Scope == postmanScopeEnvironment || inWorkspaceDir(entry.Filename, "environment", "environments"):
+			s.importEnvironment(ctx, report, entry.Filename, entry.Data, opts)
+		default:
+			s.importCollection(ctx, report, entry.Filename, entry.Data, opts)
+		}
+	}
+
+	if len(report.Collections)+len(report.Environments)+len(report.Globals) == 0 {
+		return nil, models.NewValidationError("no collections, environments or globals to import")
+	}
+
+	return report, nil
+}
+
+// importDump imports the entries of the single JSON file of older data
+// dumps, naming each after its position in the file
+func (s *BulkImportService) importDump(ctx context.Context, report *models.WorkspaceImportReport, filename string, dump workspaceDump, opts models.ImportOptions) {
+	for i, data := range dump.Environments {
+		s.importEnvironment(ctx, report, fmt.Sprintf("%s#environments/%d", filename, i), data, opts)
+	}
+
+	s.importGlobals(ctx, report, filename+"#globals", dump.Globals)
+
+	for i, data := range dump.Collections {
+		s.importCollection(ctx, report, fmt.Sprintf("%s#collections/%d", filename, i), data, opts)
+	}
+}
+
+func (s *BulkImportService) importCollection(ctx context.Context, report *models.WorkspaceImportReport, filename string, data []byte, opts models.ImportOptions) {
+	var doc workspaceEntry
+	_ = json.Unmarshal(data, &doc)
+	result := &models.BulkImportResult{
+		Filename:  filename,
+		Name:      cmp.Or(doc.Info.Name, doc.Name),
+		PostmanID: cmp.Or(doc.Info.PostmanID, doc.ID),
+	}
+
+	if opts.Provenance != nil {
+		provenance := *opts.Provenance
+		provenance.Filename = filename
+		opts.Provenance = &provenance
+	}
+
+	converter, err := s.converters.Detect(data)
+	if err == nil {
+		var imported *models.ImportResult
+		if imported, err = converter.Import(ctx, data, opts); err == nil {
+			result.ID, result.Existing = imported.ID, imported.Existing
+		}
+	}
+
+	report.Collections = append(report.Collections, countImport(report, result, err))
+}
+
+func (s *BulkImportService) importEnvironment(ctx context.Context, report *models.WorkspaceImportReport, filename string, data []byte, opts models.ImportOptions) {
+	var doc workspaceEntry
+	_ = json.Unmarshal(data, &doc)
+	result := &models.BulkImportResult{Filename: filename, Name: doc.Name, PostmanID: doc.ID}
+
+	id, err := s.environmentService.ImportPostmanEnvironment(ctx, data, models.ImportOptions{StripSecrets: opts.StripSecrets})
+	result.ID = id
+
+	report.Environments = append(report.Environments, countImport(report, result, err))
+}
+
+// importGlobals sets the enabled globals of a dump. Globals are stored in
+// plain text, so secret ones are skipped rather than exposed.
+func (s *BulkImportService) importGlobals(ctx context.Context, report *models.WorkspaceImportReport, filename string, values []models.PostmanEnvironmentValue) {
+	for _, value := range values {
+		if value.Key == "" {
+			continue
+		}
+
+		result := &models.BulkImportResult{Filename: filename, Name: value.Key}
+		switch {
+		case value.Enabled != nil && !*value.Enabled:
+			result.Skipped = "disabled"
+		case value.Type == models.VariableTypeSecret:
+			result.Skipped = "secret globals are not imported, set them as secret environment variables instead"
+		}
+		if result.Skipped != "" {
+			report.Skipped++
+			report.Globals = append(report.Globals, result)
+			continue
+		}
+
+		err := s.globalService.SetGlobal(ctx, &models.GlobalVariable{
+			Key:   value.Key,
+			Value: postmanValueString(value.Value),
+		})
+
+		report.Globals = append(report.Globals, countImport(report, result, err))
+	}
+}
+
+// countImport records the outcome of an entry in the report totals
+func countImport(report *models.WorkspaceImportReport, result *models.BulkImportResult, err error) *models.BulkImportResult {
+	if err != nil {
+		result.ID, result.Err = 0, err
+		report.Failed++
+	} else {
+		report.Imported++
+	}
+	return result
+}
+
+// inWorkspaceDir reports whether an archive entry lies in a directory with
+// one of names, the way data dumps group files by type
+func inWorkspaceDir(filename string, names ...string) bool {
+	return slices.ContainsFunc(strings.Split(path.Dir(filename), "/"), func(dir string) bool {
+		return slices.Contains(names, strings.ToLower(dir))
+	})
+}
@@ -0,0 +1,337 @@
+package specdiff
+
+import (
+	"fmt"
+	"postman-api/internal/models"
+	"slices"
+	"strings"
+)
+
+// maxSchemaDepth bounds how deep Compare descends into nested schemas
+const maxSchemaDepth = 10
+
+// Compare classifies the changes going from base to revised as breaking or
+// not for the clients of base: removed paths and operations, new required
+// parameters, body fields and bodies, narrowed enums and changed types of
+// what clients send, and removed or retyped fields of what they receive.
+// Breaking changes come first.
+func Compare(base, revised map[string]any) []models.SpecChange {
+	c := &comparer{base: base, revised: revised}
+
+	basePaths, revisedPaths := objectAt(base, "paths"), objectAt(revised, "paths")
+	for _, path := range sortedKeys(basePaths) {
+		if _, ok := revisedPaths[path]; !ok {
+			c.add(models.ChangePathRemoved, true, "", path, "path %s was removed", path)
+		}
+	}
+	for _, path := range sortedKeys(revisedPaths) {
+		if _, ok := basePaths[path]; !ok {
+			c.add(models.ChangePathAdded, false, "", path, "path %s was added", path)
+		}
+	}
+
+	baseOps, revisedOps := Operations(base), Operations(revised)
+	for _, name := range sortedKeys(baseOps) {
+		path := name[strings.Index(name, " ")+1:]
+		if _, ok := revisedPaths[path]; !ok {
+			continue
+		}
+		revisedOp, ok := revisedOps[name]
+		if !ok {
+			c.add(models.ChangeOperationRemoved, true, name, "", "operation %s was removed", name)
+			continue
+		}
+		c.compareOperation(name, baseOps[name], revisedOp)
+	}
+	for _, name := range sortedKeys(revisedOps) {
+		path := name[strings.Index(name, " ")+1:]
+		if _, ok := baseOps[name]; !ok && basePaths[path] != nil {
+			c.add(models.ChangeOperationAdded, false, name, "", "operation %s was added", name)
+		}
+	}
+
+	slices.SortStableFunc(c.changes, func(a, b models.SpecChange) int {
+		if a.Breaking != b.Breaking {
+			if a.Breaking {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	return c.changes
+}
+
+// comparer collects the changes between two documents
+type comparer struct {
+	base    map[string]any
+	revised map[string]any
+	changes []models.SpecChange
+}
+
+func (c *comparer) add(changeType string, breaking bool, operation, location, format string, args ...any) {
+	c.changes = append(c.changes, models.SpecChange{
+		Type:      changeType,
+		Breaking:  breaking,
+		Operation: operation,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// compareOperation compares the parameters, request body and success
+// response of an operation present in both documents
+func (c *comparer) compareOperation(name string, baseOp, revisedOp any) {
+	path := name[strings.Index(name, " ")+1:]
+	baseItem := objectAt(objectAt(c.base, "paths"), path)
+	revisedItem := objectAt(objectAt(c.revised, "paths"), path)
+	baseObj, _ := baseOp.(map[string]any)
+	revisedObj, _ := revisedOp.(map[string]any)
+
+	baseParams := parameters(c.base, baseItem, baseObj)
+	revisedParams := parameters(c.revised, revisedItem, revisedObj)
+	for _, key := range sortedKeys(revisedParams) {
+		param := revisedParams[key]
+		required, _ := param["required"].(bool)
+		old, ok := baseParams[key]
+		switch {
+		case !ok && required:
+			c.add(models.ChangeRequiredParameterAdded, true, name, key, "required %s was added", key)
+		case !ok:
+			c.add(models.ChangeParameterAdded, false, name, key, "optional %s was added", key)
+		default:
+			if wasRequired, _ := old["required"].(bool); required && !wasRequired {
+				c.add(models.ChangeParameterRequired, true, name, key, "%s became required", key)
+			}
+			c.compareSchema(name, key, parameterSchema(c.base, old), parameterSchema(c.revised, param), true, 0)
+		}
+	}
+	for _, key := range sortedKeys(baseParams) {
+		if _, ok := revisedParams[key]; !ok {
+			c.add(models.ChangeParameterRemoved, false, name, key, "%s was removed", key)
+		}
+	}
+
+	baseBody, baseRequired := requestBody(c.base, baseItem, baseObj)
+	revisedBody, revisedRequired := requestBody(c.revised, revisedItem, revisedObj)
+	switch {
+	case baseBody == nil && revisedBody != nil && revisedRequired:
+		c.add(models.ChangeRequestBodyRequired, true, name, "request body", "a required request body was added")
+	case baseBody != nil && revisedBody != nil:
+		if revisedRequired && !baseRequired {
+			c.add(models.ChangeRequestBodyRequired, true, name, "request body", "the request body became required")
+		}
+		c.compareSchema(name, "request body", baseBody, revisedBody, true, 0)
+	}
+
+	if status, baseResp, revisedResp := successResponses(c.base, baseObj, c.revised, revisedObj); status != "" {
+		c.compareSchema(name, "response "+status, baseResp, revisedResp, false, 0)
+	}
+}
+
+// compareSchema compares the schemas of something clients send, when
+// request is set, or receive
+func (c *comparer) compareSchema(operation, location string, base, revised map[string]any, request bool, depth int) {
+	base, revised = resolve(c.base, base), resolve(c.revised, revised)
+	if base == nil || revised == nil || depth > maxSchemaDepth {
+		return
+	}
+
+	if baseType, revisedType := schemaType(base), schemaType(revised); baseType != "" && revisedType != "" && baseType != revisedType {
+		c.add(models.ChangeTypeChanged, true, operation, location, "type of %s changed from %s to %s", location, baseType, revisedType)
+		return
+	}
+
+	if request {
+		baseEnum, hadEnum := base["enum"].([]any)
+		revisedEnum, hasEnum := revised["enum"].([]any)
+		if hasEnum {
+			var removed []string
+			for _, value := range baseEnum {
+				if !slices.ContainsFunc(revisedEnum, func(v any) bool { return fmt.Sprint(v) == fmt.Sprint(value) }) {
+					removed = append(removed, fmt.Sprint(value))
+				}
+			}
+			switch {
+			case !hadEnum:
+				c.add(models.ChangeEnumNarrowed, true, operation, location, "%s is now limited to an enum", location)
+			case len(removed) > 0:
+				c.add(models.ChangeEnumNarrowed, true, operation, location, "%s no longer accepts %s", location, strings.Join(removed, ", "))
+			case len(revisedEnum) > len(baseEnum):
+				c.add(models.ChangeEnumWidened, false, operation, location, "%s accepts more values", location)
+			}
+		}
+	}
+
+	baseProps, revisedProps := objectAt(base, "properties"), objectAt(revised, "properties")
+	baseRequired, revisedRequired := stringSet(base["required"]), stringSet(revised["required"])
+	for _, prop := range sortedKeys(revisedProps) {
+		field := location + " property " + prop
+		_, existed := baseProps[prop]
+		switch {
+		case request && revisedRequired[prop] && !baseRequired[prop]:
+			c.add(models.ChangeRequiredFieldAdded, true, operation, field, "%s is now required", field)
+		case !existed:
+			c.add(models.ChangePropertyAdded, false, operation, field, "%s was added", field)
+		}
+		if existed {
+			baseProp, _ := baseProps[prop].(map[string]any)
+			revisedProp, _ := revisedProps[prop].(map[string]any)
+			c.compareSchema(operation, field, baseProp, revisedProp, request, depth+1)
+		}
+	}
+	for _, prop := range sortedKeys(baseProps) {
+		if _, ok := revisedProps[prop]; !ok {
+			field := location + " property " + prop
+			c.add(models.ChangePropertyRemoved, !request, operation, field, "%s was removed", field)
+		}
+	}
+
+	baseItems, _ := base["items"].(map[string]any)
+	revisedItems, _ := revised["items"].(map[string]any)
+	c.compareSchema(operation, location+" items", baseItems, revisedItems, request, depth+1)
+}
+
+// parameters returns the path-level and operation parameters of an
+// operation keyed "<in> parameter <name>", leaving out Swagger 2 body parameters
+func parameters(doc, item, op map[string]any) map[string]map[string]any {
+	params := map[string]map[string]any{}
+	shared, _ := item["parameters"].([]any)
+	own, _ := op["parameters"].([]any)
+	for _, raw := range append(append([]any{}, shared...), own...) {
+		param, _ := raw.(map[string]any)
+		param = resolve(doc, param)
+		in, _ := param["in"].(string)
+		name, _ := param["name"].(string)
+		if param == nil || in == "body" || name == "" {
+			continue
+		}
+		params[in+" parameter "+name] = param
+	}
+	return params
+}
+
+// parameterSchema returns the schema of an OpenAPI 3 parameter, or the
+// Swagger 2 parameter itself, which carries its type inline
+func parameterSchema(doc, param map[string]any) map[string]any {
+	if schema, ok := param["schema"].(map[string]any); ok {
+		return resolve(doc, schema)
+	}
+	return param
+}
+
+// requestBody returns the JSON schema of an operation's request body and
+// whether the body is required
+func requestBody(doc, item, op map[string]any) (map[string]any, bool) {
+	if body := resolve(doc, objectAt(op, "requestBody")); body != nil {
+		required, _ := body["required"].(bool)
+		return mediaSchema(doc, objectAt(body, "content")), required
+	}
+
+	shared, _ := item["parameters"].([]any)
+	own, _ := op["parameters"].([]any)
+	for _, raw := range append(append([]any{}, shared...), own...) {
+		param, _ := raw.(map[string]any)
+		param = resolve(doc, param)
+		if in, _ := param["in"].(string); in == "body" {
+			required, _ := param["required"].(bool)
+			schema, _ := param["schema"].(map[string]any)
+			return resolve(doc, schema), required
+		}
+	}
+
+	return nil, false
+}
+
+// successResponses returns the lowest 2xx status declared by both operations
+// with the JSON schemas of their responses to it
+func successResponses(baseDoc, baseOp, revisedDoc, revisedOp map[string]any) (string, map[string]any, map[string]any) {
+	baseResponses, revisedResponses := objectAt(baseOp, "responses"), objectAt(revisedOp, "responses")
+	for _, status := range sortedKeys(baseResponses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		if _, ok := revisedResponses[status]; !ok {
+			continue
+		}
+		baseResp, _ := baseResponses[status].(map[string]any)
+		revisedResp, _ := revisedResponses[status].(map[string]any)
+		return status, responseSchema(baseDoc, baseResp), responseSchema(revisedDoc, revisedResp)
+	}
+	return "", nil, nil
+}
+
+// responseSchema returns the JSON schema of an OpenAPI 3 or Swagger 2 response
+func responseSchema(doc, resp map[string]any) map[string]any {
+	resp = resolve(doc, resp)
+	if content := objectAt(resp, "content"); content != nil {
+		return mediaSchema(doc, content)
+	}
+	schema, _ := resp["schema"].(map[string]any)
+	return resolve(doc, schema)
+}
+
+// mediaSchema returns the schema of the JSON media type of content, or of
+// its first media type when it has no JSON one
+func mediaSchema(doc, content map[string]any) map[string]any {
+	types := sortedKeys(content)
+	idx := slices.IndexFunc(types, func(t string) bool { return strings.Contains(t, "json") })
+	if idx < 0 {
+		if len(types) == 0 {
+			return nil
+		}
+		idx = 0
+	}
+	media, _ := content[types[idx]].(map[string]any)
+	schema, _ := media["schema"].(map[string]any)
+	return resolve(doc, schema)
+}
+
+// resolve follows the local $ref of v, if any, through at most
+// maxSchemaDepth references
+func resolve(doc, v map[string]any) map[string]any {
+	for range maxSchemaDepth {
+		ref, ok := v["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return v
+		}
+
+		var target any = doc
+		for _, part := range strings.Split(ref[2:], "/") {
+			obj, _ := target.(map[string]any)
+			target = obj[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		v, _ = target.(map[string]any)
+	}
+	return v
+}
+
+// schemaType returns the type of a schema as written, empty when it has none
+func schemaType(schema map[string]any) string {
+	t, ok := schema["type"]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(t)
+}
+
+func stringSet(v any) map[string]bool {
+	set := map[string]bool{}
+	values, _ := v.([]any)
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,221 @@
+package specdiff
+
+import (
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+// change is the part of a models.SpecChange the tests check
+type change struct {
+	Type      string
+	Breaking  bool
+	Operation string
+	Location  string
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name          string
+		base, revised string
+		want          []change
+	}{
+		{
+			name:    "identical documents",
+			base:    `{"paths":{"/pets":{"get":{"responses":{"200":{"description":"ok"}}}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"responses":{"200":{"description":"ok"}}}}}}`,
+		},
+		{
+			name:    "path removed and added",
+			base:    `{"paths":{"/pets":{"get":{}}}}`,
+			revised: `{"paths":{"/animals":{"get":{}}}}`,
+			want: []change{
+				{models.ChangePathRemoved, true, "", "/pets"},
+				{models.ChangePathAdded, false, "", "/animals"},
+			},
+		},
+		{
+			name:    "operation removed and added",
+			base:    `{"paths":{"/pets":{"get":{},"delete":{}}}}`,
+			revised: `{"paths":{"/pets":{"get":{},"post":{}}}}`,
+			want: []change{
+				{models.ChangeOperationRemoved, true, "DELETE /pets", ""},
+				{models.ChangeOperationAdded, false, "POST /pets", ""},
+			},
+		},
+		{
+			name:    "operations of a removed path are not reported again",
+			base:    `{"paths":{"/pets":{"get":{},"post":{}}}}`,
+			revised: `{"paths":{}}`,
+			want: []change{
+				{models.ChangePathRemoved, true, "", "/pets"},
+			},
+		},
+		{
+			name:    "required parameter added",
+			base:    `{"paths":{"/pets":{"get":{}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"owner","in":"query","required":true,"schema":{"type":"string"}}]}}}}`,
+			want: []change{
+				{models.ChangeRequiredParameterAdded, true, "GET /pets", "query parameter owner"},
+			},
+		},
+		{
+			name:    "optional parameter added and another removed",
+			base:    `{"paths":{"/pets":{"get":{"parameters":[{"name":"page","in":"query"}]}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"limit","in":"query"}]}}}}`,
+			want: []change{
+				{models.ChangeParameterAdded, false, "GET /pets", "query parameter limit"},
+				{models.ChangeParameterRemoved, false, "GET /pets", "query parameter page"},
+			},
+		},
+		{
+			name:    "parameter became required",
+			base:    `{"paths":{"/pets":{"get":{"parameters":[{"name":"X-Tenant","in":"header"}]}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"X-Tenant","in":"header","required":true}]}}}}`,
+			want: []change{
+				{models.ChangeParameterRequired, true, "GET /pets", "header parameter X-Tenant"},
+			},
+		},
+		{
+			name:    "path-level parameter type changed",
+			base:    `{"paths":{"/pets/{id}":{"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}],"get":{}}}}`,
+			revised: `{"paths":{"/pets/{id}":{"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],"get":{}}}}`,
+			want: []change{
+				{models.ChangeTypeChanged, true, "GET /pets/{id}", "path parameter id"},
+			},
+		},
+		{
+			name:    "required request body added",
+			base:    `{"paths":{"/pets":{"post":{}}}}`,
+			revised: `{"paths":{"/pets":{"post":{"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`,
+			want: []change{
+				{models.ChangeRequestBodyRequired, true, "POST /pets", "request body"},
+			},
+		},
+		{
+			name:    "optional request body added",
+			base:    `{"paths":{"/pets":{"post":{}}}}`,
+			revised: `{"paths":{"/pets":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`,
+		},
+		{
+			name:    "request body became required",
+			base:    `{"paths":{"/pets":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`,
+			revised: `{"paths":{"/pets":{"post":{"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object"}}}}}}}}`,
+			want: []change{
+				{models.ChangeRequestBodyRequired, true, "POST /pets", "request body"},
+			},
+		},
+		{
+			name:    "request fields required, added and removed",
+			base:    `{"paths":{"/pets":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object","properties":{"name":{"type":"string"},"tag":{"type":"string"}}}}}}}}}}`,
+			revised: `{"paths":{"/pets":{"post":{"requestBody":{"content":{"application/json":{"schema":{"type":"object","required":["name","age"],"properties":{"name":{"type":"string"},"age":{"type":"integer"},"color":{"type":"string"}}}}}}}}}}`,
+			want: []change{
+				{models.ChangeRequiredFieldAdded, true, "POST /pets", "request body property age"},
+				{models.ChangeRequiredFieldAdded, true, "POST /pets", "request body property name"},
+				{models.ChangePropertyAdded, false, "POST /pets", "request body property color"},
+				{models.ChangePropertyRemoved, false, "POST /pets", "request body property tag"},
+			},
+		},
+		{
+			name:    "response fields removed and added",
+			base:    `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}}}}}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"object","properties":{"id":{"type":"integer"},"nickname":{"type":"string"}}}}}}}}}}}`,
+			want: []change{
+				{models.ChangePropertyRemoved, true, "GET /pets", "response 200 property name"},
+				{models.ChangePropertyAdded, false, "GET /pets", "response 200 property nickname"},
+			},
+		},
+		{
+			name:    "response field retyped through a component reference",
+			base:    `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/Pet"}}}}}}}}},"components":{"schemas":{"Pet":{"type":"object","properties":{"id":{"type":"integer"}}}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/Pet"}}}}}}}}},"components":{"schemas":{"Pet":{"type":"object","properties":{"id":{"type":"string"}}}}}}`,
+			want: []change{
+				{models.ChangeTypeChanged, true, "GET /pets", "response 200 items property id"},
+			},
+		},
+		{
+			name:    "responses are compared at the lowest success status both declare",
+			base:    `{"paths":{"/pets":{"post":{"responses":{"201":{"content":{"application/json":{"schema":{"type":"object"}}}},"400":{"content":{"application/json":{"schema":{"type":"object"}}}}}}}}}`,
+			revised: `{"paths":{"/pets":{"post":{"responses":{"201":{"content":{"application/json":{"schema":{"type":"array"}}}},"400":{"content":{"application/json":{"schema":{"type":"string"}}}}}}}}}`,
+			want: []change{
+				{models.ChangeTypeChanged, true, "POST /pets", "response 201"},
+			},
+		},
+		{
+			name:    "enum narrowed",
+			base:    `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string","enum":["available","sold"]}}]}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string","enum":["available"]}}]}}}}`,
+			want: []change{
+				{models.ChangeEnumNarrowed, true, "GET /pets", "query parameter status"},
+			},
+		},
+		{
+			name:    "enum introduced",
+			base:    `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string"}}]}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string","enum":["available"]}}]}}}}`,
+			want: []change{
+				{models.ChangeEnumNarrowed, true, "GET /pets", "query parameter status"},
+			},
+		},
+		{
+			name:    "enum widened",
+			base:    `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string","enum":["available"]}}]}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"parameters":[{"name":"status","in":"query","schema":{"type":"string","enum":["available","sold"]}}]}}}}`,
+			want: []change{
+				{models.ChangeEnumWidened, false, "GET /pets", "query parameter status"},
+			},
+		},
+		{
+			name:    "response enums are not checked",
+			base:    `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"string","enum":["a","b"]}}}}}}}}}`,
+			revised: `{"paths":{"/pets":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"type":"string","enum":["a"]}}}}}}}}}`,
+		},
+		{
+			name:    "Swagger 2 body parameter and inline parameter types",
+			base:    `{"swagger":"2.0","paths":{"/pets":{"post":{"parameters":[{"name":"body","in":"body","schema":{"$ref":"#/definitions/Pet"}},{"name":"limit","in":"query","type":"integer"}]}}},"definitions":{"Pet":{"type":"object","properties":{"name":{"type":"string"}}}}}`,
+			revised: `{"swagger":"2.0","paths":{"/pets":{"post":{"parameters":[{"name":"body","in":"body","required":true,"schema":{"$ref":"#/definitions/Pet"}},{"name":"limit","in":"query","type":"string"}]}}},"definitions":{"Pet":{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}}}`,
+			want: []change{
+				{models.ChangeTypeChanged, true, "POST /pets", "query parameter limit"},
+				{models.ChangeRequestBodyRequired, true, "POST /pets", "request body"},
+				{models.ChangeRequiredFieldAdded, true, "POST /pets", "request body property name"},
+			},
+		},
+		{
+			name:    "breaking changes come first",
+			base:    `{"paths":{"/a":{"get":{}},"/z":{"get":{}}}}`,
+			revised: `{"paths":{"/a":{"get":{}},"/b":{"get":{}}}}`,
+			want: []change{
+				{models.ChangePathRemoved, true, "", "/z"},
+				{models.ChangePathAdded, false, "", "/b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(parseDoc(t, tt.base), parseDoc(t, tt.revised))
+
+			var got []change
+			for _, c := range changes {
+				if c.Message == "" {
+					t.Errorf("change %+v has no message", c)
+				}
+				got = append(got, change{c.Type, c.Breaking, c.Operation, c.Location})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareCyclicReference(t *testing.T) {
+	doc := `{"paths":{"/nodes":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Node"}}}}}}}},
+		"components":{"schemas":{"Node":{"type":"object","properties":{"child":{"$ref":"#/components/schemas/Node"}}}}}}`
+
+	if changes := Compare(parseDoc(t, doc), parseDoc(t, doc)); len(changes) != 0 {
+		t.Errorf("Compare() = %+v, want no changes", changes)
+	}
+}
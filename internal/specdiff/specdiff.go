@@ -0,0 +1,77 @@
+// Package specdiff compares two contents of an OpenAPI document by the paths,
+// operations and schemas they declare.
+package specdiff
+
+import (
+	"postman-api/internal/models"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// methods are the keys of a path item that hold operations
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Diff lists the paths, operations and schemas added, removed and changed
+// going from one document to the other. Schemas are the component schemas
+// of OpenAPI 3 documents and the definitions of Swagger 2 documents.
+func Diff(from, to map[string]any) (paths, operations, schemas models.DiffEntries) {
+	return compare(objectAt(from, "paths"), objectAt(to, "paths")),
+		compare(Operations(from), Operations(to)),
+		compare(Schemas(from), Schemas(to))
+}
+
+// Operations returns the operations of a document keyed "METHOD /path"
+func Operations(doc map[string]any) map[string]any {
+	ops := map[string]any{}
+	for path, raw := range objectAt(doc, "paths") {
+		item, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, method := range methods {
+			if op, ok := item[method]; ok {
+				ops[strings.ToUpper(method)+" "+path] = op
+			}
+		}
+	}
+	return ops
+}
+
+// Schemas returns the named schemas of a document
+func Schemas(doc map[string]any) map[string]any {
+	if schemas := objectAt(objectAt(doc, "components"), "schemas"); len(schemas) > 0 {
+		return schemas
+	}
+	return objectAt(doc, "definitions")
+}
+
+// compare lists the keys only in to as added, those only in from as removed
+// and those whose values differ as changed, each sorted
+func compare(from, to map[string]any) models.DiffEntries {
+	entries := models.DiffEntries{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for key, value := range to {
+		old, ok := from[key]
+		switch {
+		case !ok:
+			entries.Added = append(entries.Added, key)
+		case !reflect.DeepEqual(old, value):
+			entries.Changed = append(entries.Changed, key)
+		}
+	}
+	for key := range from {
+		if _, ok := to[key]; !ok {
+			entries.Removed = append(entries.Removed, key)
+		}
+	}
+
+	slices.Sort(entries.Added)
+	slices.Sort(entries.Removed)
+	slices.Sort(entries.Changed)
+	return entries
+}
+
+func objectAt(doc map[string]any, key string) map[string]any {
+	obj, _ := doc[key].(map[string]any)
+	return obj
+}
@@ -0,0 +1,103 @@
+package specdiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+// parseDoc decodes a JSON document the way stored spec content reads back
+func parseDoc(t *testing.T, raw string) map[string]any {
+	t.Helper()
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatalf("invalid test document: %v", err)
+	}
+	return doc
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   string
+		paths      models.DiffEntries
+		operations models.DiffEntries
+		schemas    models.DiffEntries
+	}{
+		{
+			name: "identical documents",
+			from: `{"paths":{"/pets":{"get":{"summary":"List"}}},"components":{"schemas":{"Pet":{"type":"object"}}}}`,
+			to:   `{"paths":{"/pets":{"get":{"summary":"List"}}},"components":{"schemas":{"Pet":{"type":"object"}}}}`,
+		},
+		{
+			name:       "operations added, removed and changed",
+			from:       `{"paths":{"/pets":{"get":{"summary":"List"},"delete":{}},"/owners":{"get":{}}}}`,
+			to:         `{"paths":{"/pets":{"get":{"summary":"List pets"},"post":{}},"/stores":{"get":{}}}}`,
+			paths:      models.DiffEntries{Added: []string{"/stores"}, Removed: []string{"/owners"}, Changed: []string{"/pets"}},
+			operations: models.DiffEntries{Added: []string{"GET /stores", "POST /pets"}, Removed: []string{"DELETE /pets", "GET /owners"}, Changed: []string{"GET /pets"}},
+		},
+		{
+			name:    "OpenAPI 3 component schemas",
+			from:    `{"components":{"schemas":{"Pet":{"type":"object"},"Owner":{"type":"object"}}}}`,
+			to:      `{"components":{"schemas":{"Pet":{"type":"array"},"Store":{"type":"object"}}}}`,
+			schemas: models.DiffEntries{Added: []string{"Store"}, Removed: []string{"Owner"}, Changed: []string{"Pet"}},
+		},
+		{
+			name:    "Swagger 2 definitions",
+			from:    `{"swagger":"2.0","definitions":{"Pet":{"type":"object"}}}`,
+			to:      `{"swagger":"2.0","definitions":{"Pet":{"type":"object"},"Error":{"type":"object"}}}`,
+			schemas: models.DiffEntries{Added: []string{"Error"}},
+		},
+		{
+			name:  "path item keys other than methods are not operations",
+			from:  `{"paths":{"/pets":{"parameters":[],"summary":"Pets"}}}`,
+			to:    `{"paths":{"/pets":{"parameters":[{"name":"q","in":"query"}],"summary":"Pets"}}}`,
+			paths: models.DiffEntries{Changed: []string{"/pets"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, operations, schemas := Diff(parseDoc(t, tt.from), parseDoc(t, tt.to))
+
+			assertEntries(t, "paths", paths, tt.paths)
+			assertEntries(t, "operations", operations, tt.operations)
+			assertEntries(t, "schemas", schemas, tt.schemas)
+		})
+	}
+}
+
+func TestOperations(t *testing.T) {
+	doc := parseDoc(t, `{"paths":{
+		"/pets":{"get":{"operationId":"list"},"post":{"operationId":"create"},"parameters":[]},
+		"/pets/{id}":{"delete":{"operationId":"remove"}},
+		"/broken":"not a path item"
+	}}`)
+
+	got := map[string]string{}
+	for name, op := range Operations(doc) {
+		got[name], _ = op.(map[string]any)["operationId"].(string)
+	}
+
+	want := map[string]string{"GET /pets": "list", "POST /pets": "create", "DELETE /pets/{id}": "remove"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Operations() = %v, want %v", got, want)
+	}
+}
+
+func assertEntries(t *testing.T, name string, got, want models.DiffEntries) {
+	t.Helper()
+
+	for _, list := range []*[]string{&want.Added, &want.Removed, &want.Changed} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %+v, want %+v", name, got, want)
+	}
+}
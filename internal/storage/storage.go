@@ -0,0 +1,119 @@
+// Package storage keeps binary content outside the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque binary content by key
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FileStore keeps blobs as files below a root directory
+type FileStore struct {
+	root string
+}
+
+// NewFileStore creates a blob store rooted at dir
+func NewFileStore(dir string) BlobStore {
+	return &FileStore{root: dir}
+}
+
+// Put writes data under key, atomically replacing any previous content
+func (s *FileStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return WriteFile(path, data)
+}
+
+// WriteFile replaces the file at path with data, creating its directory, so
+// that readers never see it half written
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return nil
+}
+
+// Open streams the blob stored under key
+func (s *FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete removes the blob stored under key; missing blobs are not an error
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// path maps a key such as "attachments/ab12..." to a file, fanning blobs out
+// into subdirectories by the first two characters of the last segment
+func (s *FileStore) path(key string) (string, error) {
+	clean := filepath.ToSlash(filepath.Clean(key))
+	if key == "" || strings.HasPrefix(clean, "../") || clean == ".." || filepath.IsAbs(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	dir, name := filepath.Split(clean)
+	if len(name) > 2 {
+		dir = filepath.Join(dir, name[:2])
+	}
+
+	return filepath.Join(s.root, dir, name), nil
+}
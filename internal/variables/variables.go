@@ -0,0 +1,123 @@
+package variables
+
+import (
+	"postman-api/internal/models"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxDepth bounds how many times variables referring to other variables are expanded
+const MaxDepth = 5
+
+// Pattern matches a {{variable}} reference
+var Pattern = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// Scope names, from lowest to highest precedence
+const (
+	ScopeGlobal      = "global"
+	ScopeCollection  = "collection"
+	ScopeEnvironment = "environment"
+)
+
+// Scope is a named set of variable values
+type Scope struct {
+	Name   string
+	Values map[string]string
+}
+
+// Resolver substitutes {{name}} references and records the names it used
+// and those it could not resolve. It is not safe for concurrent use.
+type Resolver struct {
+	values     map[string]models.VariableReference
+	used       map[string]bool
+	unresolved map[string]bool
+}
+
+// New creates a resolver over scopes, where later scopes take precedence
+// over earlier ones
+func New(scopes ...Scope) *Resolver {
+	r := &Resolver{
+		values:     map[string]models.VariableReference{},
+		used:       map[string]bool{},
+		unresolved: map[string]bool{},
+	}
+	for _, scope := range scopes {
+		for name, value := range scope.Values {
+			r.values[name] = models.VariableReference{Name: name, Value: value, Scope: scope.Name}
+		}
+	}
+	return r
+}
+
+// Name returns the variable name of a {{name}} reference
+func Name(ref string) string {
+	return strings.TrimSpace(ref[2 : len(ref)-2])
+}
+
+// String substitutes the references in s, expanding references within
+// values up to MaxDepth times; references without a value are left in place
+func (r *Resolver) String(s string) string {
+	for range MaxDepth {
+		changed := false
+		s = Pattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := Name(ref)
+			if value, ok := r.values[name]; ok {
+				changed = true
+				r.used[name] = true
+				return value.Value
+			}
+			r.unresolved[name] = true
+			return ref
+		})
+		if !changed {
+			break
+		}
+	}
+	return s
+}
+
+// Value substitutes the references in every string within a decoded JSON
+// value, including object keys, and returns the result
+func (r *Resolver) Value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.String(v)
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for key, value := range v {
+			resolved[r.String(key)] = r.Value(value)
+		}
+		return resolved
+	case []any:
+		resolved := make([]any, len(v))
+		for i, value := range v {
+			resolved[i] = r.Value(value)
+		}
+		return resolved
+	default:
+		return v
+	}
+}
+
+// Used returns the variables substituted so far with the scope of each, sorted by name
+func (r *Resolver) Used() []models.VariableReference {
+	refs := make([]models.VariableReference, 0, len(r.used))
+	for name := range r.used {
+		refs = append(refs, r.values[name])
+	}
+	slices.SortFunc(refs, func(a, b models.VariableReference) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return refs
+}
+
+// Unresolved returns the names referenced so far without a value, sorted
+func (r *Resolver) Unresolved() []string {
+	var names []string
+	for name := range r.unresolved {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
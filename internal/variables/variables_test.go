@@ -0,0 +1,187 @@
+package variables
+
+import (
+	"reflect"
+	"testing"
+
+	"postman-api/internal/models"
+)
+
+func testResolver() *Resolver {
+	return New(
+		Scope{Name: ScopeGlobal, Values: map[string]string{
+			"host":    "global.example.com",
+			"version": "v1",
+			"token":   "global-token",
+		}},
+		Scope{Name: ScopeCollection, Values: map[string]string{
+			"host":    "collection.example.com",
+			"baseUrl": "https://{{host}}/{{version}}",
+			"self":    "{{self}}",
+			"chain1":  "{{chain2}}",
+			"chain2":  "{{chain3}}",
+			"chain3":  "{{chain4}}",
+			"chain4":  "{{chain5}}",
+			"chain5":  "{{chain6}}",
+			"chain6":  "end",
+			"broken":  "{{nowhere}}",
+		}},
+		Scope{Name: ScopeEnvironment, Values: map[string]string{
+			"host":  "env.example.com",
+			"empty": "",
+		}},
+	)
+}
+
+func TestResolverString(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       string
+		used       []string
+		unresolved []string
+	}{
+		{
+			name:  "no references",
+			input: "https://example.com",
+			want:  "https://example.com",
+		},
+		{
+			name:  "later scopes take precedence",
+			input: "{{host}}",
+			want:  "env.example.com",
+			used:  []string{"host"},
+		},
+		{
+			name:  "references within values are expanded",
+			input: "{{baseUrl}}/pets",
+			want:  "https://env.example.com/v1/pets",
+			used:  []string{"baseUrl", "host", "version"},
+		},
+		{
+			name:  "whitespace inside braces is ignored",
+			input: "Bearer {{ token }}",
+			want:  "Bearer global-token",
+			used:  []string{"token"},
+		},
+		{
+			name:  "empty values resolve",
+			input: "[{{empty}}]",
+			want:  "[]",
+			used:  []string{"empty"},
+		},
+		{
+			name:       "unknown references are left in place",
+			input:      "{{host}}/{{missing}}",
+			want:       "env.example.com/{{missing}}",
+			used:       []string{"host"},
+			unresolved: []string{"missing"},
+		},
+		{
+			name:       "unknown references found within values",
+			input:      "{{broken}}",
+			want:       "{{nowhere}}",
+			used:       []string{"broken"},
+			unresolved: []string{"nowhere"},
+		},
+		{
+			name:  "self references stop at the depth limit",
+			input: "{{self}}",
+			want:  "{{self}}",
+			used:  []string{"self"},
+		},
+		{
+			name:  "expansion stops at the depth limit",
+			input: "{{chain1}}",
+			want:  "{{chain6}}",
+			used:  []string{"chain1", "chain2", "chain3", "chain4", "chain5"},
+		},
+		{
+			name:  "nested braces are not references",
+			input: "{{{host}}}",
+			want:  "{env.example.com}",
+			used:  []string{"host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testResolver()
+
+			if got := r.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+
+			var used []string
+			for _, ref := range r.Used() {
+				used = append(used, ref.Name)
+			}
+			if !reflect.DeepEqual(used, tt.used) {
+				t.Errorf("Used() = %v, want %v", used, tt.used)
+			}
+			if unresolved := r.Unresolved(); !reflect.DeepEqual(unresolved, tt.unresolved) {
+				t.Errorf("Unresolved() = %v, want %v", unresolved, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestResolverUsedScopes(t *testing.T) {
+	r := testResolver()
+	r.String("{{baseUrl}} {{token}}")
+
+	want := []models.VariableReference{
+		{Name: "baseUrl", Value: "https://{{host}}/{{version}}", Scope: ScopeCollection},
+		{Name: "host", Value: "env.example.com", Scope: ScopeEnvironment},
+		{Name: "token", Value: "global-token", Scope: ScopeGlobal},
+		{Name: "version", Value: "v1", Scope: ScopeGlobal},
+	}
+	if used := r.Used(); !reflect.DeepEqual(used, want) {
+		t.Errorf("Used() = %+v, want %+v", used, want)
+	}
+}
+
+func TestResolverValue(t *testing.T) {
+	r := testResolver()
+
+	input := map[string]any{
+		"url": "{{baseUrl}}/pets",
+		"{{version}}": map[string]any{
+			"tags":  []any{"{{host}}", 42.0, true, nil},
+			"limit": 10.0,
+		},
+	}
+	want := map[string]any{
+		"url": "https://env.example.com/v1/pets",
+		"v1": map[string]any{
+			"tags":  []any{"env.example.com", 42.0, true, nil},
+			"limit": 10.0,
+		},
+	}
+
+	if got := r.Value(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Value() = %v, want %v", got, want)
+	}
+
+	// The input is left as it was
+	if _, ok := input["{{version}}"]; !ok {
+		t.Error("Value() modified its input")
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"{{host}}", "host"},
+		{"{{ host }}", "host"},
+		{"{{}}", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Name(tt.ref); got != tt.want {
+			t.Errorf("Name(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package version
+
+import "runtime"
+
+// Build metadata, overridden at build time via -ldflags "-X ..."
+var (
+	Version   = "0.0.0-dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	BuildDate string   `json:"build_date"`
+	GoVersion string   `json:"go_version"`
+	Features  []string `json:"features"`
+}
+
+// Get returns the build info along with the enabled feature flags
+func Get(features []string) Info {
+	if features == nil {
+		features = []string{}
+	}
+
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Features:  features,
+	}
+}
+
+// ServerHeader returns the value used for the Server response header
+func ServerHeader() string {
+	return "postman-api/" + Version
+}
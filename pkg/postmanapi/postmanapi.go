@@ -0,0 +1,380 @@
+// Package postmanapi builds the collection and OpenAPI catalog API as an
+// http.Handler, so other Go programs can mount it instead of running the
+// standalone server.
+package postmanapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"postman-api/internal/api"
+	"postman-api/internal/config"
+	"postman-api/internal/database"
+	"postman-api/internal/events"
+	"postman-api/internal/interfaces"
+	"postman-api/internal/models"
+	"postman-api/internal/repository"
+	"postman-api/internal/resilience"
+	"postman-api/internal/secrets"
+	"postman-api/internal/service"
+	"postman-api/internal/signing"
+	"postman-api/internal/storage"
+	"sync"
+	"time"
+)
+
+// Configuration types, re-exported so embedders can build them
+type (
+	Config              = config.Config
+	ServerConfig        = config.ServerConfig
+	DatabaseConfig      = config.DatabaseConfig
+	HooksConfig         = config.HooksConfig
+	StorageConfig       = config.StorageConfig
+	SecretsConfig       = config.SecretsConfig
+	ImportConfig        = config.ImportConfig
+	RetentionConfig     = config.RetentionConfig
+	OutboundConfig      = config.OutboundConfig
+	EventsConfig        = config.EventsConfig
+	JobsConfig          = config.JobsConfig
+	ExportsConfig       = config.ExportsConfig
+	VariablesConfig     = config.VariablesConfig
+	CatalogMirrorConfig = config.CatalogMirrorConfig
+	ReviewConfig        = config.ReviewConfig
+	DigestsConfig       = config.DigestsConfig
+)
+
+// SeedReport summarizes a fixture directory load
+type SeedReport = models.SeedReport
+
+// specSourcePollInterval is how often RunWorkers checks for due spec sources
+const specSourcePollInterval = 30 * time.Second
+
+// digestPollInterval is how often RunWorkers checks for due digests
+const digestPollInterval = 5 * time.Minute
+
+// leaderRetryInterval is how often a replica that is not running a scheduler
+// checks whether it can take it over
+const leaderRetryInterval = 15 * time.Second
+
+// DefaultConfig returns a configuration with sensible database defaults
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// LoadConfig reads the configuration from the environment, an optional .env
+// file and the optional config file named by CONFIG_FILE
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// App is a fully wired instance of the API backed by a caller-owned database
+type App struct {
+	db      *database.Database
+	replica *database.Database
+	handler http.Handler
+
+	specSourceService interfaces.SpecSourceService
+	digestService     interfaces.DigestService
+	seedService       interfaces.SeedService
+	retentionService  interfaces.RetentionService
+	retentionInterval time.Duration
+	eventRelayService interfaces.EventRelayService
+	relayInterval     time.Duration
+	jobService        interfaces.JobService
+	jobs              JobsConfig
+	catalogMirror     *repository.CatalogMirror
+	mirrorInterval    time.Duration
+
+	// jobCtx is the context queued jobs run under, cancelled by AbortJobs
+	jobCtx    context.Context
+	abortJobs context.CancelFunc
+}
+
+// New builds the API handler on top of an open PostgreSQL pool
+func New(cfg *Config, db *sql.DB) (http.Handler, error) {
+	app, err := NewApp(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.Handler(), nil
+}
+
+// NewApp wires repositories, services and the router on top of an open
+// PostgreSQL pool. The caller keeps ownership of db; a replica configured
+// through cfg.Database.ReplicaDSN is opened here and released by Close.
+func NewApp(cfg *Config, db *sql.DB) (*App, error) {
+	if cfg == nil {
+		return nil, errors.New("config is required")
+	}
+	if db == nil {
+		return nil, errors.New("database is required")
+	}
+
+	app := &App{db: database.New(db, &cfg.Database)}
+	app.jobCtx, app.abortJobs = context.WithCancel(context.Background())
+
+	// Connect to the optional read replica, served while the primary is unavailable
+	var replicaCollectionRepo interfaces.CollectionRepository
+	var replicaRequestRepo interfaces.RequestRepository
+	var replicaOpenAPIRepo interfaces.OpenAPIRepository
+	if cfg.Database.ReplicaDSN != "" {
+		replica, err := database.NewReplicaConnection(&cfg.Database)
+		if err != nil {
+			log.Printf("Read replica unavailable, continuing without it: %v", err)
+		} else {
+			app.replica = replica
+			replicaCollectionRepo = repository.NewCollectionRepository(replica.DB)
+			replicaRequestRepo = repository.NewRequestRepository(replica.DB)
+			replicaOpenAPIRepo = repository.NewOpenAPIRepository(replica.DB)
+		}
+	}
+
+	// Initialize repositories, retrying transient failures behind a shared circuit breaker
+	breaker := resilience.NewBreaker(cfg.Database.BreakerThreshold, cfg.Database.BreakerCooldown)
+	retry := resilience.RetryPolicy{MaxAttempts: cfg.Database.RetryAttempts, BaseDelay: cfg.Database.RetryBaseDelay}
+
+	var collectionRepo interfaces.CollectionRepository = repository.NewResilientCollectionRepository(repository.NewCollectionRepository(app.db.DB), replicaCollectionRepo, breaker, retry)
+	var requestRepo interfaces.RequestRepository = repository.NewResilientRequestRepository(repository.NewRequestRepository(app.db.DB), replicaRequestRepo, breaker, retry)
+	var openAPIRepo interfaces.OpenAPIRepository = repository.NewResilientOpenAPIRepository(repository.NewOpenAPIRepository(app.db.DB), replicaOpenAPIRepo, breaker, retry)
+	var folderRepo interfaces.FolderRepository = repository.NewFolderRepository(app.db.DB)
+	var exampleRepo interfaces.ExampleRepository = repository.NewExampleRepository(app.db.DB)
+	var specSourceRepo interfaces.SpecSourceRepository = repository.NewSpecSourceRepository(app.db.DB)
+	var attachmentRepo interfaces.AttachmentRepository = repository.NewAttachmentRepository(app.db.DB)
+	var environmentRepo interfaces.EnvironmentRepository = repository.NewEnvironmentRepository(app.db.DB)
+	var headerPresetRepo interfaces.HeaderPresetRepository = repository.NewHeaderPresetRepository(app.db.DB)
+	var globalVariableRepo interfaces.GlobalVariableRepository = repository.NewGlobalVariableRepository(app.db.DB)
+	var inventoryOwnershipRepo interfaces.InventoryOwnershipRepository = repository.NewInventoryOwnershipRepository(app.db.DB)
+	var securityFindingRepo interfaces.SecurityFindingRepository = repository.NewSecurityFindingRepository(app.db.DB)
+	var runRepo interfaces.RunRepository = repository.NewRunRepository(app.db.DB)
+	var snapshotRepo interfaces.ResponseSnapshotRepository = repository.NewResponseSnapshotRepository(app.db.DB)
+	var loadTestRepo interfaces.LoadTestRepository = repository.NewLoadTestRepository(app.db.DB)
+	var revisionRepo interfaces.CollectionRevisionRepository = repository.NewCollectionRevisionRepository(app.db.DB)
+
+	// Initialize blob storage for attachments and large response bodies
+	blobStore := storage.NewFileStore(cfg.Storage.Dir)
+	responsePolicy := models.ResponseBodyPolicy{
+		OffloadBytes: cfg.Storage.ResponseOffloadBytes,
+		MaxBytes:     cfg.Storage.ResponseMaxBytes,
+		Retain:       cfg.Storage.ResponseRetain,
+	}
+
+	// Archived collections and superseded spec imports are purged by the retention policy
+	retentionPolicy := models.RetentionPolicy{
+		ArchivedCollections: cfg.Retention.ArchivedCollections,
+		SupersededSpecs:     cfg.Retention.SupersededSpecs,
+	}
+
+	// Documents fetched from URLs go through the outbound proxy, if any
+	outboundProxy := models.OutboundProxy{
+		URL:     cfg.Outbound.ProxyURL,
+		NoProxy: cfg.Outbound.NoProxy,
+	}
+
+	// Initialize the cipher for secret variables and spec source credentials;
+	// both are rejected without a key
+	var cipher *secrets.Cipher
+	if len(cfg.Secrets.Key) > 0 {
+		var err error
+		if cipher, err = secrets.NewCipher(cfg.Secrets.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize the signer for signed exports; they are rejected without a key
+	var signer *signing.Signer
+	if len(cfg.Exports.SigningKey) > 0 {
+		var err error
+		if signer, err = signing.NewSigner(cfg.Exports.SigningAlgorithm, cfg.Exports.SigningKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize the event publisher; with a broker configured, changes to
+	// collections, requests and specs go through the outbox to the event bus
+	var publisher events.Publisher = events.NewLogPublisher()
+	if cfg.Events.Broker != "" {
+		broker, err := events.NewBroker(cfg.Events.Broker, cfg.Events.BrokerURL, cfg.Events.NATS)
+		if err != nil {
+			return nil, err
+		}
+
+		outboxRepo := repository.NewOutboxRepository(app.db.DB)
+		app.eventRelayService = service.NewEventRelayService(outboxRepo, broker, cfg.Events.TopicPrefix)
+		app.relayInterval = cfg.Events.RelayInterval
+		publisher = app.eventRelayService
+
+		// Entity changes are recorded in the outbox in the same transaction as the change
+		collectionRepo = repository.NewEventedCollectionRepository(collectionRepo, app.db.DB, outboxRepo)
+		requestRepo = repository.NewEventedRequestRepository(requestRepo, app.db.DB, outboxRepo)
+		openAPIRepo = repository.NewEventedOpenAPIRepository(openAPIRepo, app.db.DB, outboxRepo)
+	}
+
+	// With a catalog mirror every collection is also exported as a JSON
+	// file, written out after the changes to it commit
+	if cfg.CatalogMirror.Dir != "" {
+		app.catalogMirror = repository.NewCatalogMirror(cfg.CatalogMirror.Dir, collectionRepo, requestRepo, folderRepo)
+		app.mirrorInterval = cfg.CatalogMirror.FlushInterval
+		if app.mirrorInterval <= 0 {
+			app.mirrorInterval = config.DefaultCatalogMirrorFlushInterval
+		}
+
+		collectionRepo = repository.NewMirroredCollectionRepository(collectionRepo, app.catalogMirror)
+		requestRepo = repository.NewMirroredRequestRepository(requestRepo, app.catalogMirror)
+		folderRepo = repository.NewMirroredFolderRepository(folderRepo, app.catalogMirror)
+		exampleRepo = repository.NewMirroredExampleRepository(exampleRepo, app.catalogMirror)
+	}
+
+	// Initialize services
+	var collectionService interfaces.CollectionService = service.NewCollectionService(collectionRepo, requestRepo, folderRepo, exampleRepo, revisionRepo, repository.NewTransactor(app.db.DB), blobStore, responsePolicy, cfg.Import.Deduplicate)
+	var requestService interfaces.RequestService = service.NewRequestService(requestRepo, collectionRepo, folderRepo, headerPresetRepo, blobStore, responsePolicy)
+	var specReviewRepo interfaces.SpecReviewRepository = repository.NewSpecReviewRepository(app.db.DB)
+	var specReviewService interfaces.SpecReviewService = service.NewSpecReviewService(openAPIRepo, specReviewRepo, repository.NewTransactor(app.db.DB), cfg.Review.RequiredApprovals)
+	var specRevisionRepo interfaces.SpecRevisionRepository = repository.NewSpecRevisionRepository(app.db.DB)
+	var openAPIService interfaces.OpenAPIService = service.NewOpenAPIService(openAPIRepo, specRevisionRepo, specReviewService, cfg.Import.Deduplicate)
+	var scannerService interfaces.ScannerService = service.NewScannerService(collectionRepo, requestRepo)
+	var specSourceService interfaces.SpecSourceService = service.NewSpecSourceService(specSourceRepo, openAPIService, publisher, cipher, outboundProxy)
+	var digestService interfaces.DigestService = service.NewDigestService(repository.NewDigestRepository(app.db.DB), collectionRepo, revisionRepo, specRevisionRepo, openAPIService, outboundProxy, models.SMTPServer{
+		Addr:     cfg.Digests.SMTPAddr,
+		Username: cfg.Digests.SMTPUsername,
+		Password: cfg.Digests.SMTPPassword,
+		From:     cfg.Digests.From,
+	})
+	var importHookService interfaces.ImportHookService = service.NewImportHookService(collectionService, openAPIService, cipher, outboundProxy)
+	var healthService interfaces.HealthService = service.NewHealthService(app.db.DB, breaker)
+	var attachmentService interfaces.AttachmentService = service.NewAttachmentService(attachmentRepo, collectionService, blobStore, cfg.Storage.MaxAttachmentBytes)
+	var environmentService interfaces.EnvironmentService = service.NewEnvironmentService(environmentRepo, cipher)
+	var globalVariableService interfaces.GlobalVariableService = service.NewGlobalVariableService(globalVariableRepo, cfg.Variables.Globals)
+	var deprecationService interfaces.DeprecationService = service.NewDeprecationService(openAPIRepo, requestRepo)
+	var inventoryService interfaces.InventoryService = service.NewInventoryService(openAPIRepo, requestRepo, inventoryOwnershipRepo)
+	var catalogService interfaces.CatalogService = service.NewCatalogService(collectionRepo, openAPIRepo, inventoryOwnershipRepo)
+	var lintService interfaces.LintService = service.NewLintService(collectionRepo, requestRepo)
+	var retentionService interfaces.RetentionService = service.NewRetentionService(collectionRepo, requestRepo, openAPIRepo, retentionPolicy)
+	var flattenService interfaces.FlattenService = service.NewFlattenService(collectionRepo, requestRepo, headerPresetRepo, environmentService, globalVariableService)
+	var docsService interfaces.DocsService = service.NewDocsService(collectionRepo, requestRepo, folderRepo, headerPresetRepo, environmentService, globalVariableService)
+	var securityService interfaces.SecurityService = service.NewSecurityService(collectionRepo, securityFindingRepo, flattenService)
+	var contractTestService interfaces.ContractTestService = service.NewContractTestService(openAPIRepo, collectionRepo, requestRepo)
+	var conversionService interfaces.ConversionService = service.NewConversionService(openAPIRepo, collectionRepo, requestRepo, folderRepo, repository.NewTransactor(app.db.DB))
+	var jobService interfaces.JobService = service.NewJobService(repository.NewJobRepository(app.db.DB), cfg.Jobs.MaxAttempts)
+	var runnerService interfaces.RunnerService = service.NewRunnerService(requestRepo, collectionRepo, folderRepo, runRepo, snapshotRepo, loadTestRepo, headerPresetRepo, environmentService, globalVariableService, jobService, publisher, outboundProxy, cfg.Runner.RequestTimeout)
+	var specPromotionService interfaces.SpecPromotionService = service.NewSpecPromotionService(openAPIRepo, specReviewRepo, repository.NewSpecPromotionRepository(app.db.DB), specReviewService, jobService, repository.NewTransactor(app.db.DB), cfg.Promotion.Labels, cfg.Promotion.Exports, cfg.Promotion.Dir)
+	var storageService interfaces.StorageService = service.NewStorageService(repository.NewStorageRepository(app.db.DB))
+	var signingService interfaces.SigningService = service.NewSigningService(signer, cfg.Exports.Exporter)
+	var folderService interfaces.FolderService = service.NewFolderService(folderRepo, collectionRepo, requestRepo, repository.NewTransactor(app.db.DB))
+	var exampleService interfaces.ExampleService = service.NewExampleService(exampleRepo, requestRepo, collectionRepo, blobStore, responsePolicy)
+	var converters interfaces.ConverterRegistry = service.NewConverterRegistry()
+	var bulkImportService interfaces.BulkImportService = service.NewBulkImportService(converters, environmentService, globalVariableService)
+	var headerPresetService interfaces.HeaderPresetService = service.NewHeaderPresetService(headerPresetRepo)
+	var seedService interfaces.SeedService = service.NewSeedService(collectionRepo, openAPIRepo, environmentRepo, collectionService, openAPIService, environmentService)
+
+	// Initialize router
+	router := api.NewRouter(cfg, collectionService, requestService, openAPIService, scannerService, specSourceService, importHookService, healthService, attachmentService, environmentService, deprecationService, inventoryService, catalogService, lintService, retentionService, flattenService, securityService, contractTestService, conversionService, jobService, runnerService, storageService, signingService, folderService, bulkImportService, exampleService, headerPresetService, globalVariableService, converters, docsService, specReviewService, digestService, specPromotionService)
+
+	// Collection formats, tried in this order when detecting an upload's format
+	converters.Register(service.BundleConverter(attachmentService))
+	converters.Register(service.PostmanConverter(collectionService))
+
+	// Work queued through the API runs on the job workers
+	jobService.Register(models.JobImportHook, service.ImportHookJob(importHookService))
+	jobService.Register(models.JobRetentionEnforce, service.RetentionJob(retentionService))
+	jobService.Register(models.JobMigrateItems, service.MigrateItemsJob(collectionService))
+	jobService.Register(models.JobPromotionExport, service.PromotionExportJob(specPromotionService))
+	jobService.Register(models.JobRunCollection, service.RunCollectionJob(runnerService))
+	jobService.Register(models.JobLoadTest, service.LoadTestJob(runnerService))
+
+	app.handler = router.Setup()
+	app.specSourceService = specSourceService
+	app.digestService = digestService
+	app.seedService = seedService
+	app.retentionService = retentionService
+	app.retentionInterval = cfg.Retention.Interval
+	app.jobService = jobService
+	app.jobs = cfg.Jobs
+
+	return app, nil
+}
+
+// Handler returns the HTTP handler serving the API
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// Migrate applies any pending schema migrations
+func (a *App) Migrate(ctx context.Context) error {
+	return a.db.Migrate(ctx)
+}
+
+// Seed loads example collections, specs and environments from dir, skipping files loaded before
+func (a *App) Seed(ctx context.Context, dir string) (*SeedReport, error) {
+	return a.seedService.Seed(ctx, dir)
+}
+
+// RunWorkers runs the background jobs, the job queue workers, spec source
+// polling, digests, retention enforcement and, with an event broker configured, the
+// outbox relay, and with a catalog mirror its flushes, until ctx is
+// done. Queued jobs are shared among replicas; each scheduler runs on a
+// single replica at a time, elected through a PostgreSQL advisory lock. Once ctx is done no new jobs are taken, and
+// RunWorkers returns when the jobs in flight finish or AbortJobs is called.
+func (a *App) RunWorkers(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		a.jobService.RunWorkers(ctx, a.jobCtx, a.jobs.Workers, a.jobs.PollInterval)
+	}()
+	go func() {
+		defer wg.Done()
+		a.db.RunAsLeader(ctx, "spec-source-poller", leaderRetryInterval, func(ctx context.Context) {
+			a.specSourceService.RunPoller(ctx, specSourcePollInterval)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		a.db.RunAsLeader(ctx, "digest-scheduler", leaderRetryInterval, func(ctx context.Context) {
+			a.digestService.RunScheduler(ctx, digestPollInterval)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		a.db.RunAsLeader(ctx, "retention-scheduler", leaderRetryInterval, func(ctx context.Context) {
+			a.retentionService.RunScheduler(ctx, a.retentionInterval)
+		})
+	}()
+	if a.catalogMirror != nil {
+		// Every replica writes out the collections changed through it
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.catalogMirror.Run(ctx, a.mirrorInterval)
+		}()
+	}
+	if a.eventRelayService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.db.RunAsLeader(ctx, "event-relay", leaderRetryInterval, func(ctx context.Context) {
+				a.eventRelayService.RunRelay(ctx, a.relayInterval)
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+// AbortJobs interrupts the jobs still running after RunWorkers was told to
+// stop; they go back to the queue to be picked up again
+func (a *App) AbortJobs() {
+	a.abortJobs()
+}
+
+// Close releases resources opened by the app; the caller's database is left open
+func (a *App) Close() error {
+	a.abortJobs()
+
+	if a.replica != nil {
+		return a.replica.Close()
+	}
+
+	return nil
+}